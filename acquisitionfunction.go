@@ -1,6 +1,23 @@
 package ho
 
-import "math"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+const (
+	// defaultGPUCBDelta is the AcquisitionParams.Delta used by GPUCB if
+	// unset.
+	defaultGPUCBDelta = 0.1
+
+	// defaultBeta is the AcquisitionParams.Beta confidence bounds use if
+	// unset.
+	defaultBeta = 2.0
+)
 
 //////
 // Available acquisition functions for Bayesian optimization.
@@ -8,7 +25,9 @@ import "math"
 // exploration (trying new areas) and exploitation (focusing on known good areas).
 //////
 
-// UCB implements the Upper Confidence Bound acquisition function.
+// LowerConfidenceBound (LCB) implements the confidence bound acquisition
+// function for the minimization setting this package works in. It's what the
+// literature calls UCB once the objective is negated.
 //
 // How it works:
 // - Combines the predicted mean performance with the uncertainty (variance)
@@ -30,11 +49,97 @@ import "math"
 //	params := AcquisitionParams{
 //	    Beta: 2.0,  // Balance between exploration and exploitation
 //	}
-//	value := UCB(0.5, 0.2, params)  // Evaluate a point with mean=0.5, variance=0.2
-func UCB(mean, variance float64, params AcquisitionParams) float64 {
+//	value := LowerConfidenceBound(0.5, 0.2, params)  // Evaluate a point with mean=0.5, variance=0.2
+//
+// Direction: MinimizeAcquisition.
+func LowerConfidenceBound(mean, variance float64, params AcquisitionParams) float64 {
 	return mean - params.Beta*math.Sqrt(variance)
 }
 
+// UpperConfidenceBound implements the Upper Confidence Bound acquisition
+// function exactly as found in the literature, i.e. for maximization: it's the
+// upper confidence bound of the negated objective. Selecting the highest value
+// is equivalent to selecting the lowest LowerConfidenceBound.
+//
+// Parameters:
+// - mean: Predicted performance at this point
+// - variance: Uncertainty in the prediction
+// - params.Beta: Exploration weight (higher = more exploration)
+//
+// Important notes:
+// - Higher values are better, set OptimizationConfig.AcquisitionDirection to
+// MaximizeAcquisition (or use SetAcquisition) when using it
+//
+// Direction: MaximizeAcquisition.
+func UpperConfidenceBound(mean, variance float64, params AcquisitionParams) float64 {
+	return -mean + params.Beta*math.Sqrt(variance)
+}
+
+// GPUCB implements the GP-UCB acquisition function from Srinivas et al.
+// ("Gaussian Process Optimization in the Bandit Setting: No Regret and
+// Experimental Design"), in the minimization setting. Instead of a hand-tuned
+// constant Beta, it uses the theoretically motivated schedule computed by
+// GPUCBBeta, growing with the iteration.
+//
+// Parameters:
+// - mean: Predicted performance at this point
+// - variance: Uncertainty in the prediction
+// - params.Iteration, params.Dimensions: Set automatically by the optimizer
+// - params.Delta: Confidence parameter (defaults to 0.1)
+//
+// When to use:
+// - When you don't want to hand-tune Beta
+// - For long runs, where exploration should keep up with the growing number
+// of observations and come with no-regret guarantees
+// - Prefer LowerConfidenceBound with a small Beta for short budgets, where
+// GP-UCB tends to over-explore
+//
+// Example:
+//
+//	config := DefaultConfig()
+//	config.AcquisitionFunc = GPUCB
+//	config.AcqParams.Delta = 0.1
+//
+// Direction: MinimizeAcquisition.
+func GPUCB(mean, variance float64, params AcquisitionParams) float64 {
+	beta := GPUCBBeta(params.Iteration, params.Dimensions, params.Delta)
+
+	return mean - math.Sqrt(beta*variance)
+}
+
+// GPUCBBeta computes the GP-UCB beta schedule from Srinivas et al.:
+//
+//	beta(t) = 2 * log(d * t^2 * pi^2 / (6 * delta))
+//
+// Parameters:
+// - t: Iteration (values below 1 are treated as 1)
+// - d: Number of dimensions (values below 1 are treated as 1)
+// - delta: Confidence parameter in (0, 1), defaults to 0.1 if not positive
+//
+// Returns:
+// - float64: Beta for iteration t. GPUCB weights the standard deviation by
+// its square root.
+func GPUCBBeta(t, d int, delta float64) float64 {
+	if delta <= 0 {
+		delta = defaultGPUCBDelta
+	}
+
+	t = max(t, 1)
+
+	d = max(d, 1)
+
+	return 2 * math.Log(float64(d)*float64(t)*float64(t)*math.Pi*math.Pi/(6*delta))
+}
+
+// UCB implements the Upper Confidence Bound acquisition function in the
+// minimization setting, i.e. it's a lower confidence bound.
+//
+// Deprecated: Use LowerConfidenceBound, which has the same behavior but a name
+// matching the minimization setting.
+func UCB(mean, variance float64, params AcquisitionParams) float64 {
+	return LowerConfidenceBound(mean, variance, params)
+}
+
 // ProbabilityOfImprovement (PI) calculates the probability that a point will
 // improve upon the current best observed value.
 //
@@ -61,8 +166,33 @@ func UCB(mean, variance float64, params AcquisitionParams) float64 {
 //	    Xi: 0.01,        // Look for at least 1% improvement
 //	}
 //	prob := ProbabilityOfImprovement(0.9, 0.2, params)
+//
+// Important notes:
+// - Returns the negated probability, so lower values are better, consistently
+// with the other built-ins. See ProbabilityOfImprovementMin
+//
+// Direction: MinimizeAcquisition.
 func ProbabilityOfImprovement(mean, variance float64, params AcquisitionParams) float64 {
-	z := (mean - params.BestSoFar - params.Xi) / math.Sqrt(variance)
+	return -ProbabilityOfImprovementMin(mean, variance, params)
+}
+
+// ProbabilityOfImprovementMin is the probability that a point improves upon
+// the current best observed value by at least Xi, in the minimization setting:
+// P(f(x) < BestSoFar - Xi).
+//
+// Parameters:
+// - mean: Predicted performance at this point
+// - variance: Uncertainty in the prediction
+// - params.BestSoFar: Best value observed so far
+// - params.Xi: Minimum improvement desired
+//
+// Important notes:
+// - Higher values are better, it's a probability. Use ProbabilityOfImprovement
+// to follow the package's "lower is better" convention
+//
+// Direction: MaximizeAcquisition.
+func ProbabilityOfImprovementMin(mean, variance float64, params AcquisitionParams) float64 {
+	z := (params.BestSoFar - params.Xi - mean) / math.Sqrt(variance)
 
 	return normalCDF(z)
 }
@@ -94,12 +224,72 @@ func ProbabilityOfImprovement(mean, variance float64, params AcquisitionParams)
 //	    Xi: 0.01,        // Look for at least 1% improvement
 //	}
 //	expected := ExpectedImprovement(0.9, 0.2, params)
+//
+// Important notes:
+// - Returns the negated expected improvement, so lower values are better,
+// consistently with the other built-ins. See ExpectedImprovementMin
+//
+// Direction: MinimizeAcquisition.
 func ExpectedImprovement(mean, variance float64, params AcquisitionParams) float64 {
+	return -ExpectedImprovementMin(mean, variance, params)
+}
+
+// ExpectedImprovementMin is the expected improvement of a point over the
+// current best observed value minus Xi, in the minimization setting:
+// E[max(BestSoFar - Xi - f(x), 0)].
+//
+// Parameters:
+// - mean: Predicted performance at this point
+// - variance: Uncertainty in the prediction
+// - params.BestSoFar: Best value observed so far
+// - params.Xi: Minimum improvement desired
+//
+// Important notes:
+// - Higher values are better, it's an expected improvement. Use
+// ExpectedImprovement to follow the package's "lower is better" convention
+//
+// Direction: MaximizeAcquisition.
+func ExpectedImprovementMin(mean, variance float64, params AcquisitionParams) float64 {
 	sigma := math.Sqrt(variance)
 
-	z := (mean - params.BestSoFar - params.Xi) / sigma
+	improvement := params.BestSoFar - params.Xi - mean
+
+	z := improvement / sigma
+
+	return improvement*normalCDF(z) + sigma*normalPDF(z)
+}
+
+// NoisyExpectedImprovement (NoisyEI) is ExpectedImprovement for noisy
+// measurements. Standard EI treats BestSoFar as the true optimum value, but
+// with noisy measurements the best observed value is optimistically biased
+// (it's likely a lucky measurement), and EI under-explores. NoisyEI uses the
+// model's predicted mean at the best observed point (IncumbentMean) instead.
+//
+// Parameters:
+// - mean: Predicted performance at this point
+// - variance: Uncertainty in the prediction
+// - params.IncumbentMean: Set automatically by the optimizer
+// - params.Xi: Minimum improvement desired
+//
+// When to use:
+// - Recommended when measurements are noisy, and each trial is measured only
+// once
+// - Prefer ExpectedImprovement for deterministic objectives
+//
+// Example:
+//
+//	config := DefaultConfig()
+//	config.AcquisitionFunc = NoisyExpectedImprovement
+//	config.AcqParams.Xi = 0.01
+//
+// Important notes:
+// - Returns the negated expected improvement, so lower values are better
+//
+// Direction: MinimizeAcquisition.
+func NoisyExpectedImprovement(mean, variance float64, params AcquisitionParams) float64 {
+	params.BestSoFar = params.IncumbentMean
 
-	return (mean-params.BestSoFar-params.Xi)*normalCDF(z) + sigma*normalPDF(z)
+	return ExpectedImprovement(mean, variance, params)
 }
 
 // ThompsonSampling implements Thompson Sampling acquisition by drawing random
@@ -130,7 +320,237 @@ func ExpectedImprovement(mean, variance float64, params AcquisitionParams) float
 //
 // Warning:
 // - Always initialize RandomState before using this function
-// - Don't share RandomState between different optimization runs.
+// - Don't share RandomState between different optimization runs
+//
+// Direction: MinimizeAcquisition.
 func ThompsonSampling(mean, variance float64, params AcquisitionParams) float64 {
 	return mean + math.Sqrt(variance)*params.RandomState.NormFloat64()
 }
+
+//////
+// Registry.
+//////
+
+// acquisitionsMu protects access to acquisitions.
+var acquisitionsMu sync.RWMutex
+
+// acquisitions is the registry of acquisition functions, built-in ones first.
+var acquisitions = []Acquisition{
+	{Name: "LowerConfidenceBound", Func: LowerConfidenceBound, Direction: MinimizeAcquisition, Validate: validateConfidenceBound},
+	{Name: "UpperConfidenceBound", Func: UpperConfidenceBound, Direction: MaximizeAcquisition, Validate: validateConfidenceBound},
+	{Name: "UCB", Func: UCB, Direction: MinimizeAcquisition, Validate: validateConfidenceBound},
+	{Name: "GPUCB", Func: GPUCB, Direction: MinimizeAcquisition, Validate: validateGPUCB},
+	{Name: "ProbabilityOfImprovement", Func: ProbabilityOfImprovement, Direction: MinimizeAcquisition, Validate: validateXi},
+	{Name: "ProbabilityOfImprovementMin", Func: ProbabilityOfImprovementMin, Direction: MaximizeAcquisition, Validate: validateXi},
+	{Name: "ExpectedImprovement", Func: ExpectedImprovement, Direction: MinimizeAcquisition, Validate: validateXi},
+	{Name: "ExpectedImprovementMin", Func: ExpectedImprovementMin, Direction: MaximizeAcquisition, Validate: validateXi},
+	{Name: "NoisyExpectedImprovement", Func: NoisyExpectedImprovement, Direction: MinimizeAcquisition, Validate: validateXi},
+	{Name: "ThompsonSampling", Func: ThompsonSampling, Direction: MinimizeAcquisition, Validate: validateThompson},
+}
+
+// builtinAcquisitions is the number of built-in acquisition functions, first
+// in acquisitions.
+var builtinAcquisitions = len(acquisitions)
+
+// Acquisitions returns the registered acquisition functions, built-in ones
+// first, along with their direction.
+//
+// Returns:
+// - []Acquisition: Registered acquisition functions.
+func Acquisitions() []Acquisition {
+	acquisitionsMu.RLock()
+	defer acquisitionsMu.RUnlock()
+
+	return append([]Acquisition(nil), acquisitions...)
+}
+
+// RegisterAcquisition registers a custom acquisition function, so it can be
+// looked up by name, e.g. from a configuration file (see LoadConfig). Its
+// Validate function, if any, checks the parameters of runs using it, see
+// OptimizationConfig.Validate.
+//
+// Parameters:
+// - acquisition: The acquisition function, with a unique name
+//
+// Returns:
+// - error: If the name is empty or already registered, or Func is nil
+//
+// Usage example:
+//
+//	err := RegisterAcquisition(Acquisition{
+//	    Name:      "Greedy",
+//	    Func:      func(mean, variance float64, params AcquisitionParams) float64 { return mean - params.Xi },
+//	    Direction: MinimizeAcquisition,
+//	    Validate: func(params *AcquisitionParams) error {
+//	        if params.Xi < 0 {
+//	            return fmt.Errorf("AcqParams.Xi %v is negative", params.Xi)
+//	        }
+//
+//	        return nil
+//	    },
+//	})
+func RegisterAcquisition(acquisition Acquisition) error {
+	if acquisition.Name == "" || acquisition.Func == nil {
+		return errors.New("acquisition must have a name and a function")
+	}
+
+	acquisitionsMu.Lock()
+	defer acquisitionsMu.Unlock()
+
+	for _, registered := range acquisitions {
+		if registered.Name == acquisition.Name {
+			return fmt.Errorf("acquisition %q already registered", acquisition.Name)
+		}
+	}
+
+	acquisitions = append(acquisitions, acquisition)
+
+	return nil
+}
+
+// UnregisterAcquisition removes a custom acquisition function registered with
+// RegisterAcquisition, e.g. once a test or a plugin is done with it. Built-in
+// ones can't be removed.
+//
+// Parameters:
+// - name: Name of the acquisition function
+//
+// Returns:
+// - bool: Whether it was registered, and removed
+//
+// Usage example:
+//
+//	if err := RegisterAcquisition(greedy); err != nil {
+//	    t.Fatal(err)
+//	}
+//
+//	t.Cleanup(func() { UnregisterAcquisition(greedy.Name) })
+func UnregisterAcquisition(name string) bool {
+	acquisitionsMu.Lock()
+	defer acquisitionsMu.Unlock()
+
+	for i := builtinAcquisitions; i < len(acquisitions); i++ {
+		if acquisitions[i].Name == name {
+			acquisitions = slices.Delete(acquisitions, i, i+1)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// LookupAcquisition returns the registered acquisition function with the given
+// name.
+//
+// Parameters:
+// - name: Name of the acquisition function, e.g. "ExpectedImprovementMin"
+//
+// Returns:
+// - Acquisition: The acquisition function
+// - bool: Whether it was found
+//
+// Usage example:
+//
+//	acquisition, ok := LookupAcquisition("ExpectedImprovementMin")
+//	if !ok {
+//	    // Handle unknown name
+//	}
+//
+//	config.SetAcquisition(acquisition)
+func LookupAcquisition(name string) (Acquisition, bool) {
+	acquisitionsMu.RLock()
+	defer acquisitionsMu.RUnlock()
+
+	for _, acquisition := range acquisitions {
+		if acquisition.Name == name {
+			return acquisition, true
+		}
+	}
+
+	return Acquisition{}, false
+}
+
+// acquisitionOf returns the registered acquisition function set in the
+// config, comparing function pointers. The first match wins, e.g. UCB is
+// reported as LowerConfidenceBound.
+func acquisitionOf(config OptimizationConfig) (Acquisition, bool) {
+	if config.AcquisitionFunc == nil || config.AcquisitionFuncEx != nil {
+		return Acquisition{}, false
+	}
+
+	direction := config.AcquisitionDirection
+	if direction == "" {
+		direction = MinimizeAcquisition
+	}
+
+	ptr := reflect.ValueOf(config.AcquisitionFunc).Pointer()
+
+	acquisitionsMu.RLock()
+	defer acquisitionsMu.RUnlock()
+
+	for _, acquisition := range acquisitions {
+		if reflect.ValueOf(acquisition.Func).Pointer() == ptr && acquisition.Direction == direction {
+			return acquisition, true
+		}
+	}
+
+	return Acquisition{}, false
+}
+
+//////
+// Validation.
+//////
+
+// finite returns whether v is neither NaN nor infinite.
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// validateBeta checks Beta, set to defaultBeta if unset.
+func validateBeta(params *AcquisitionParams) error {
+	switch {
+	case params.Beta == 0:
+		params.Beta = defaultBeta
+	case params.Beta < 0 || !finite(params.Beta):
+		return fmt.Errorf("AcqParams.Beta %v must be positive and finite", params.Beta)
+	}
+
+	return nil
+}
+
+// validateXi checks the parameters of ProbabilityOfImprovement,
+// ExpectedImprovement and their variants: Xi only, the optimizer manages
+// BestSoFar and IncumbentMean.
+func validateXi(params *AcquisitionParams) error {
+	if params.Xi < 0 || !finite(params.Xi) {
+		return fmt.Errorf("AcqParams.Xi %v must be non-negative and finite", params.Xi)
+	}
+
+	return nil
+}
+
+// validateConfidenceBound checks the parameters of LowerConfidenceBound,
+// UpperConfidenceBound and UCB.
+func validateConfidenceBound(params *AcquisitionParams) error {
+	return validateBeta(params)
+}
+
+// validateGPUCB checks the parameters of GPUCB, whose beta follows a
+// schedule.
+func validateGPUCB(params *AcquisitionParams) error {
+	if params.Delta < 0 || params.Delta >= 1 || math.IsNaN(params.Delta) {
+		return fmt.Errorf("AcqParams.Delta %v must be in (0, 1), or 0 for %v", params.Delta, defaultGPUCBDelta)
+	}
+
+	return nil
+}
+
+// validateThompson checks the parameters of ThompsonSampling.
+func validateThompson(params *AcquisitionParams) error {
+	if params.RandomState == nil {
+		return errors.New("AcqParams.RandomState is nil, set it, e.g. to rand.New(rand.NewSource(seed))")
+	}
+
+	return nil
+}
@@ -0,0 +1,342 @@
+package ho
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho/benchfuncs"
+)
+
+func TestAcquisitionsConvention(t *testing.T) {
+	params := AcquisitionParams{
+		Beta:        2.0,
+		Xi:          0.01,
+		BestSoFar:   1.0,
+		RandomState: rand.New(rand.NewSource(1)),
+	}
+
+	// The point known to be best has a lower predicted mean, with the same
+	// (small) uncertainty.
+	const (
+		bestMean  = 0.5
+		worseMean = 1.5
+		variance  = 0.01
+	)
+
+	for _, acquisition := range Acquisitions() {
+		t.Run(acquisition.Name, func(t *testing.T) {
+			best := acquisition.Func(bestMean, variance, params)
+			worse := acquisition.Func(worseMean, variance, params)
+
+			// Unified convention: scores are lower-is-better once the
+			// direction is applied.
+			if acquisition.Direction == MaximizeAcquisition {
+				best, worse = -best, -worse
+			}
+
+			assert.Less(t, best, worse)
+		})
+	}
+}
+
+func TestLookupAcquisition(t *testing.T) {
+	acquisition, ok := LookupAcquisition("ExpectedImprovementMin")
+	assert.True(t, ok)
+	assert.Equal(t, MaximizeAcquisition, acquisition.Direction)
+
+	config := DefaultConfig()
+	config.SetAcquisition(acquisition)
+	assert.Equal(t, MaximizeAcquisition, config.AcquisitionDirection)
+
+	_, ok = LookupAcquisition("Unknown")
+	assert.False(t, ok)
+}
+
+func TestGPUCBBeta(t *testing.T) {
+	tests := []struct {
+		t, d  int
+		delta float64
+		want  float64
+	}{
+		{t: 1, d: 1, delta: 0.1, want: 2 * math.Log(math.Pi*math.Pi/0.6)},
+		{t: 10, d: 2, delta: 0.1, want: 2 * math.Log(2*100*math.Pi*math.Pi/0.6)},
+		{t: 50, d: 5, delta: 0.05, want: 2 * math.Log(5*2500*math.Pi*math.Pi/0.3)},
+
+		// Delta defaults to 0.1.
+		{t: 10, d: 2, delta: 0, want: 2 * math.Log(2*100*math.Pi*math.Pi/0.6)},
+	}
+
+	for _, tt := range tests {
+		assert.InDelta(t, tt.want, GPUCBBeta(tt.t, tt.d, tt.delta), 1e-9)
+	}
+
+	// Beta grows with the iteration.
+	assert.Less(t, GPUCBBeta(10, 2, 0.1), GPUCBBeta(11, 2, 0.1))
+
+	params := AcquisitionParams{Iteration: 10, Dimensions: 2, Delta: 0.1}
+	assert.InDelta(t, 1-math.Sqrt(GPUCBBeta(10, 2, 0.1)*4), GPUCB(1, 4, params), 1e-9)
+}
+
+func TestGPUCBOptimization(t *testing.T) {
+	config := fastConfig()
+	config.Iterations = 20
+	config.AcquisitionFunc = GPUCB
+
+	var iterations []int
+
+	// Records the iteration seen by the acquisition function.
+	config.AcquisitionFuncEx = func(candidate []float64, mean, variance float64, params AcquisitionParams) float64 {
+		if len(iterations) == 0 || iterations[len(iterations)-1] != params.Iteration {
+			iterations = append(iterations, params.Iteration)
+		}
+
+		assert.Equal(t, 2, params.Dimensions)
+
+		return GPUCB(mean, variance, params)
+	}
+
+	benchmarkFunc := func(params ...int) error {
+		return nil
+	}
+
+	result := Optimize(
+		config,
+		benchmarkFunc,
+		ParameterRange[int]{Min: 1, Max: 100},
+		ParameterRange[int]{Min: 1, Max: 100},
+	)
+
+	assert.Equal(t, TerminationCompleted, result.TerminationReason)
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+	assert.Len(t, iterations, config.Iterations)
+	assert.Equal(t, config.Iterations, iterations[len(iterations)-1])
+}
+
+func TestNoisyExpectedImprovement(t *testing.T) {
+	params := AcquisitionParams{Xi: 0.01, BestSoFar: 1.0, IncumbentMean: 3.0}
+
+	// NoisyEI is EI against the model's prediction at the incumbent, not the
+	// (optimistically biased) best observation.
+	expected := ExpectedImprovement(2.5, 0.5, AcquisitionParams{Xi: 0.01, BestSoFar: 3.0})
+	assert.InDelta(t, expected, NoisyExpectedImprovement(2.5, 0.5, params), 1e-12)
+
+	// A point predicted better than the incumbent's mean, but worse than the
+	// lucky best observation, still looks like an improvement.
+	assert.Less(t, NoisyExpectedImprovement(2.5, 0.01, params), ExpectedImprovement(2.5, 0.01, params))
+}
+
+func TestIncumbentMean(t *testing.T) {
+	config := fastConfig()
+	config.AcquisitionFunc = NoisyExpectedImprovement
+
+	var o *optimizer[float64]
+
+	var checked int
+
+	config.AcquisitionFuncEx = func(candidate []float64, mean, variance float64, params AcquisitionParams) float64 {
+		// The optimizer sets IncumbentMean to the model's prediction at the
+		// best observed point.
+		want, _ := o.model.Predict(o.modelPoint(o.incumbent()))
+		assert.InDelta(t, want, params.IncumbentMean, 1e-12)
+
+		checked++
+
+		return NoisyExpectedImprovement(mean, variance, params)
+	}
+
+	f := benchfuncs.Branin()
+
+	o = newSyntheticOptimizer(config, f, benchfuncs.WithNoise(f.Objective, 1, rand.New(rand.NewSource(1))), 1)
+
+	o.run()
+
+	assert.Equal(t, config.Iterations*config.NumCandidates, checked)
+}
+
+func TestValidateAcquisitionParams(t *testing.T) {
+	tests := []struct {
+		acquisition string
+		params      AcquisitionParams
+		want        string
+	}{
+		{"LowerConfidenceBound", AcquisitionParams{Beta: -1}, `acquisition "LowerConfidenceBound": AcqParams.Beta -1 must be positive and finite`},
+		{"UpperConfidenceBound", AcquisitionParams{Beta: math.Inf(1)}, `acquisition "UpperConfidenceBound": AcqParams.Beta +Inf must be positive and finite`},
+		{"UCB", AcquisitionParams{Beta: math.NaN()}, `acquisition "UCB": AcqParams.Beta NaN must be positive and finite`},
+		{"GPUCB", AcquisitionParams{Delta: 1}, `acquisition "GPUCB": AcqParams.Delta 1 must be in (0, 1), or 0 for 0.1`},
+		{"GPUCB", AcquisitionParams{Delta: -0.1}, `acquisition "GPUCB": AcqParams.Delta -0.1 must be in (0, 1), or 0 for 0.1`},
+		{"ProbabilityOfImprovement", AcquisitionParams{Xi: -0.01}, `acquisition "ProbabilityOfImprovement": AcqParams.Xi -0.01 must be non-negative and finite`},
+		{"ProbabilityOfImprovementMin", AcquisitionParams{Xi: -0.5}, `acquisition "ProbabilityOfImprovementMin": AcqParams.Xi -0.5 must be non-negative and finite`},
+		{"ExpectedImprovement", AcquisitionParams{Xi: math.NaN()}, `acquisition "ExpectedImprovement": AcqParams.Xi NaN must be non-negative and finite`},
+		{"ExpectedImprovementMin", AcquisitionParams{Xi: -1}, `acquisition "ExpectedImprovementMin": AcqParams.Xi -1 must be non-negative and finite`},
+		{"NoisyExpectedImprovement", AcquisitionParams{Xi: math.Inf(1)}, `acquisition "NoisyExpectedImprovement": AcqParams.Xi +Inf must be non-negative and finite`},
+		{"ThompsonSampling", AcquisitionParams{}, `acquisition "ThompsonSampling": AcqParams.RandomState is nil, set it, e.g. to rand.New(rand.NewSource(seed))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acquisition, func(t *testing.T) {
+			acquisition, ok := LookupAcquisition(tt.acquisition)
+			if !assert.True(t, ok) {
+				return
+			}
+
+			config := DefaultConfig()
+			config.SetAcquisition(acquisition)
+			config.AcqParams = tt.params
+
+			err := config.Validate()
+
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			assert.EqualError(t, err, "invalid configuration: "+tt.want)
+
+			// Runs fail before the first trial, instead of panicking.
+			result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+				t.Fatal("the benchmark must not run")
+
+				return 0, nil
+			}, ParameterRange[float64]{Min: 0, Max: 1})
+
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+		})
+	}
+}
+
+func TestValidateAcquisitionDefaults(t *testing.T) {
+	// Every built-in accepts the defaults.
+	for _, acquisition := range Acquisitions() {
+		config := DefaultConfig()
+		config.SetAcquisition(acquisition)
+
+		assert.NoError(t, config.Validate(), acquisition.Name)
+	}
+
+	// Unset parameters are filled in where it's safe.
+	config := DefaultConfig()
+	config.AcqParams = AcquisitionParams{}
+
+	assert.NoError(t, config.Validate())
+	assert.Equal(t, defaultBeta, config.AcqParams.Beta)
+
+	config.AcquisitionFunc = ExpectedImprovement
+
+	assert.NoError(t, config.Validate())
+	assert.Zero(t, config.AcqParams.BestSoFar)
+	assert.Zero(t, config.AcqParams.Xi)
+
+	// GPUCB defaults Delta itself.
+	config.AcquisitionFunc = GPUCB
+
+	assert.NoError(t, config.Validate())
+	assert.Zero(t, config.AcqParams.Delta)
+
+	config.AcquisitionFunc = nil
+
+	assert.EqualError(t, config.Validate(), "invalid configuration: no acquisition function, see SetAcquisition")
+
+	config.AcquisitionFunc = LowerConfidenceBound
+	config.AcquisitionDirection = "Sideways"
+
+	assert.ErrorIs(t, config.Validate(), ErrInvalidConfig)
+}
+
+func TestValidateRegisteredAcquisition(t *testing.T) {
+	greedy := Acquisition{
+		Name:      "ValidatedGreedy",
+		Func:      func(mean, _ float64, params AcquisitionParams) float64 { return mean - params.Xi },
+		Direction: MinimizeAcquisition,
+		Validate: func(params *AcquisitionParams) error {
+			if params.Xi > 1 {
+				return errors.New("AcqParams.Xi must be at most 1")
+			}
+
+			return nil
+		},
+	}
+
+	if !assert.NoError(t, RegisterAcquisition(greedy)) {
+		return
+	}
+
+	t.Cleanup(func() { UnregisterAcquisition(greedy.Name) })
+
+	config := DefaultConfig()
+	config.SetAcquisition(greedy)
+
+	assert.NoError(t, config.Validate())
+
+	config.AcqParams.Xi = 2
+
+	assert.EqualError(t, config.Validate(), `invalid configuration: acquisition "ValidatedGreedy": AcqParams.Xi must be at most 1`)
+}
+
+func TestBestSoFarManaged(t *testing.T) {
+	objective := func(params ...float64) (float64, error) {
+		return math.Pow(params[0]-7, 2), nil
+	}
+
+	for _, name := range []string{"ProbabilityOfImprovement", "ProbabilityOfImprovementMin", "ExpectedImprovement", "ExpectedImprovementMin"} {
+		t.Run(name, func(t *testing.T) {
+			acquisition, _ := LookupAcquisition(name)
+
+			config := fastConfig()
+			config.InitialSamples = 5
+			config.Iterations = 20
+			config.NumCandidates = 200
+			config.Seed = 1
+			config.SetAcquisition(acquisition)
+			config.AcqParams = AcquisitionParams{}
+
+			result := OptimizeObjective(config, objective, ParameterRange[float64]{Name: "x", Min: 0, Max: 10})
+
+			assert.NoError(t, result.Err)
+			assert.InDelta(t, 7, result.BestParams[0], 1)
+
+			// A configured BestSoFar is ignored.
+			config.AcqParams.BestSoFar = -1e300
+
+			again := OptimizeObjective(config, objective, ParameterRange[float64]{Name: "x", Min: 0, Max: 10})
+
+			assert.NoError(t, again.Err)
+			assert.Equal(t, result.BestParams, again.BestParams)
+		})
+	}
+}
+
+func TestBestSoFarFallback(t *testing.T) {
+	config := fastConfig()
+	config.Seed = 1
+	config.AcqParams = AcquisitionParams{}
+
+	var calls int
+
+	config.AcquisitionFunc = func(mean, variance float64, params AcquisitionParams) float64 {
+		calls++
+
+		// Never called without a value to improve on.
+		assert.NotEqual(t, math.MaxFloat64, params.BestSoFar)
+
+		return ExpectedImprovement(mean, variance, params)
+	}
+
+	var trials int
+
+	// Initial samples, and the first iteration, all fail.
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		trials++
+
+		if trials <= config.InitialSamples+1 {
+			return 0, errors.New("unavailable")
+		}
+
+		return params[0], nil
+	}, ParameterRange[float64]{Name: "x", Min: 0, Max: 10})
+
+	assert.NoError(t, result.Err)
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+	// The first two iterations were scored by LowerConfidenceBound.
+	assert.Equal(t, (config.Iterations-2)*config.NumCandidates, calls)
+	assert.Less(t, result.BestTime, math.MaxFloat64/2)
+}
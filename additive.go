@@ -0,0 +1,329 @@
+package ho
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultGroupSize is the default number of dimensions of automatic
+	// groups, see AdditiveGaussianProcessOptions.
+	defaultGroupSize = 2
+
+	// regroupFrom is the number of observations from which automatic groups
+	// are fitted, then again each time the number of observations doubles.
+	regroupFrom = 10
+
+	// regroupDraws is the number of random groupings compared to the current
+	// one when automatic groups are fitted.
+	regroupDraws = 10
+)
+
+// AdditiveGaussianProcessOptions configures an AdditiveGaussianProcess.
+type AdditiveGaussianProcessOptions struct {
+	// Groups partitions the dimensions, by index in the parameter ranges:
+	// every dimension must be in exactly one group, e.g. {{0, 1}, {2}, {3,
+	// 4}} for 5 parameters where 0 and 1, then 3 and 4, interact. Ignored if
+	// AutoGroup is set.
+	Groups [][]int
+
+	// AutoGroup groups the dimensions automatically, in groups of GroupSize:
+	// among random groupings, the one whose model explains the observations
+	// best, i.e. of maximum marginal likelihood, is kept. Groups are fitted
+	// from 10 observations, then each time their number doubles, in O(n^3)
+	// for each of the 10 groupings compared.
+	AutoGroup bool
+
+	// GroupSize is the number of dimensions of automatic groups, the last
+	// one holding the remainder. If 0, 2.
+	GroupSize int
+
+	// Seed seeds the random groupings: models with the same seed and
+	// observations have the same groups.
+	Seed int64
+}
+
+// AdditiveGaussianProcess is a SurrogateModel for spaces of many dimensions
+// interacting weakly, e.g. 10 to 30 parameters: the objective is modeled as a
+// sum of independent Gaussian processes, each over a group of dimensions.
+// Each term only has to be learned over its few dimensions, so far fewer
+// observations are needed than for a Gaussian process over all of them.
+//
+// Important notes:
+// - Groups are declared or fitted automatically, see
+// AdditiveGaussianProcessOptions. Declared groups that overlap or miss
+// dimensions fail the run with ErrInvalidConfig
+// - It implements AdditiveModel: candidates are also combined group by
+// group, each taking the values of the candidate whose term is the most
+// promising
+// - It shares the kernel width, the prior and the posterior of the
+// Gaussian process, the kernel being the mean of the RBF kernels of the
+// groups.
+//
+// Thread safety:
+// - All methods are safe for concurrent use.
+type AdditiveGaussianProcess struct {
+	// options configures the groups.
+	options AdditiveGaussianProcessOptions
+
+	// gp holds the observations, the additive kernel, and the Cholesky
+	// factor of the kernel matrix.
+	gp *gaussianProcess
+}
+
+//////
+// Methods.
+//////
+
+// Update implements SurrogateModel. It returns an error wrapping
+// ErrInvalidObservation if x doesn't have the dimensions of the declared
+// groups.
+func (a *AdditiveGaussianProcess) Update(x []float64, y float64) error {
+	return a.UpdateWeighted(x, y, 1)
+}
+
+// UpdateWeighted implements WeightedUpdater, see Update.
+func (a *AdditiveGaussianProcess) UpdateWeighted(x []float64, y, weight float64) error {
+	a.gp.mu.Lock()
+	defer a.gp.mu.Unlock()
+
+	// Groups are known once the dimensions are.
+	if a.gp.groups == nil {
+		groups := a.options.Groups
+
+		if a.options.AutoGroup {
+			groups = randomGroups(rand.New(rand.NewSource(a.options.Seed)), len(x), a.options.GroupSize)
+		}
+
+		if err := checkGroups(groups, len(x)); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidObservation, err)
+		}
+
+		a.gp.groups = groups
+	}
+
+	points := len(a.gp.X)
+
+	if err := a.gp.update(x, y, weight); err != nil {
+		return err
+	}
+
+	// Merged observations don't add points.
+	if n := len(a.gp.X); a.options.AutoGroup && n > points && n%regroupFrom == 0 && (n/regroupFrom)&(n/regroupFrom-1) == 0 {
+		a.regroup()
+	}
+
+	return nil
+}
+
+// Predict implements SurrogateModel.
+func (a *AdditiveGaussianProcess) Predict(x []float64) (mean, variance float64) {
+	return a.gp.Predict(x)
+}
+
+// PredictBatch implements BatchPredictor.
+func (a *AdditiveGaussianProcess) PredictBatch(points [][]float64) (means, variances []float64) {
+	return a.gp.PredictBatch(points)
+}
+
+// Points implements SurrogateModel.
+func (a *AdditiveGaussianProcess) Points() [][]float64 {
+	return a.gp.Points()
+}
+
+// Len implements SurrogateModel.
+func (a *AdditiveGaussianProcess) Len() int {
+	return a.gp.Len()
+}
+
+// Clone implements SurrogateModel.
+func (a *AdditiveGaussianProcess) Clone() SurrogateModel {
+	return &AdditiveGaussianProcess{
+		options: a.options,
+		gp:      a.gp.snapshot(),
+	}
+}
+
+// Groups implements AdditiveModel.
+func (a *AdditiveGaussianProcess) Groups() [][]int {
+	a.gp.mu.RLock()
+	defer a.gp.mu.RUnlock()
+
+	return cloneGroups(a.gp.groups)
+}
+
+// PredictTerms implements AdditiveModel.
+func (a *AdditiveGaussianProcess) PredictTerms(points [][]float64) (means, variances [][]float64) {
+	a.gp.mu.RLock()
+	defer a.gp.mu.RUnlock()
+
+	return a.gp.termPosterior(points)
+}
+
+// regroup fits automatic groups: the current ones are replaced by the
+// random grouping of maximum marginal likelihood, if any is more likely.
+// The caller must hold the write lock.
+func (a *AdditiveGaussianProcess) regroup() {
+	// Groupings only depend on the seed and the number of observations.
+	rng := rand.New(rand.NewSource(a.options.Seed + int64(len(a.gp.X))))
+
+	best, likelihood, fitted := a.gp.groups, a.gp.logLikelihood(), a.gp.factorization()
+
+	for i := 0; i < regroupDraws; i++ {
+		a.gp.groups = randomGroups(rng, len(a.gp.X[0]), a.options.GroupSize)
+
+		// Groupings the kernel matrix doesn't factorize with are skipped.
+		if err := a.gp.factorize(); err != nil {
+			continue
+		}
+
+		if l := a.gp.logLikelihood(); l > likelihood {
+			best, likelihood, fitted = a.gp.groups, l, a.gp.factorization()
+		}
+	}
+
+	a.gp.groups = best
+
+	a.gp.restoreFactorization(fitted)
+}
+
+// validate checks the declared groups against the dimensions of the search
+// space.
+//
+// Returns:
+// - error: Describing the first issue if the groups don't partition the
+// dimensions and aren't automatic, nil otherwise.
+func (o AdditiveGaussianProcessOptions) validate(dimensions int) error {
+	if o.AutoGroup {
+		if o.GroupSize < 0 {
+			return fmt.Errorf("GroupSize %d is negative", o.GroupSize)
+		}
+
+		return nil
+	}
+
+	return checkGroups(o.Groups, dimensions)
+}
+
+//////
+// Helpers.
+//////
+
+// checkGroups checks that the groups partition the dimensions.
+//
+// Returns:
+// - error: Describing the first dimension out of range, in two groups or in
+// none, or the first empty group, nil otherwise.
+func checkGroups(groups [][]int, dimensions int) error {
+	if len(groups) == 0 {
+		return fmt.Errorf("no groups of dimensions")
+	}
+
+	owner := make([]int, dimensions)
+
+	for g, group := range groups {
+		if len(group) == 0 {
+			return fmt.Errorf("group %d is empty", g)
+		}
+
+		for _, d := range group {
+			switch {
+			case d < 0 || d >= dimensions:
+				return fmt.Errorf("group %d: dimension %d out of [0, %d)", g, d, dimensions)
+			case owner[d] > 0:
+				return fmt.Errorf("dimension %d is in groups %d and %d", d, owner[d]-1, g)
+			}
+
+			owner[d] = g + 1
+		}
+	}
+
+	for d, g := range owner {
+		if g == 0 {
+			return fmt.Errorf("dimension %d is in no group", d)
+		}
+	}
+
+	return nil
+}
+
+// cloneGroups returns a deep copy of the groups, nil if they're nil.
+func cloneGroups(groups [][]int) [][]int {
+	if groups == nil {
+		return nil
+	}
+
+	clone := make([][]int, len(groups))
+
+	for g, group := range groups {
+		clone[g] = slices.Clone(group)
+	}
+
+	return clone
+}
+
+// randomGroups partitions the dimensions at random, in groups of size, the
+// last one holding the remainder.
+func randomGroups(rng *rand.Rand, dimensions, size int) [][]int {
+	if size <= 0 {
+		size = defaultGroupSize
+	}
+
+	var groups [][]int
+
+	for start, perm := 0, rng.Perm(dimensions); start < dimensions; start += size {
+		groups = append(groups, perm[start:min(start+size, dimensions)])
+	}
+
+	return groups
+}
+
+// additiveOf returns the AdditiveModel the model is, or wraps, if any.
+func additiveOf(model SurrogateModel) (AdditiveModel, bool) {
+	switch m := model.(type) {
+	case *transformedModel:
+		return additiveOf(m.inner())
+	case *latticeModel:
+		// Points are on the lattice, as candidates are.
+		return additiveOf(m.model)
+	case AdditiveModel:
+		return m, m.Groups() != nil
+	default:
+		return nil, false
+	}
+}
+
+//////
+// Factory.
+//////
+
+// NewAdditiveGaussianProcess creates an AdditiveGaussianProcess.
+//
+// Parameters:
+// - options: Configures the groups, declared or automatic
+//
+// Returns:
+// - *AdditiveGaussianProcess: The model, without observations.
+//
+// Usage example:
+//
+//	// Parameters 0 and 1 interact, 2 and 3 too, 4 doesn't.
+//	config := DefaultConfig()
+//	config.Surrogate = func() SurrogateModel {
+//	    return NewAdditiveGaussianProcess(AdditiveGaussianProcessOptions{
+//	        Groups: [][]int{{0, 1}, {2, 3}, {4}},
+//	    })
+//	}
+func NewAdditiveGaussianProcess(options AdditiveGaussianProcessOptions) *AdditiveGaussianProcess {
+	options.Groups = cloneGroups(options.Groups)
+
+	return &AdditiveGaussianProcess{
+		options: options,
+		gp:      newGaussianProcess(),
+	}
+}
@@ -0,0 +1,181 @@
+package ho
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// additiveFunction is a sum of terms over pairs of dimensions.
+func additiveFunction(x ...float64) (float64, error) {
+	var sum float64
+
+	for g := 0; g < len(x); g += 2 {
+		sum += math.Sin(x[g]) + math.Pow(x[g+1]-2, 2)/2 + 0.3*math.Cos(x[g]*x[g+1])
+	}
+
+	return sum, nil
+}
+
+// pairs groups 10 dimensions by pairs, as additiveFunction does.
+var pairs = [][]int{{0, 1}, {2, 3}, {4, 5}, {6, 7}, {8, 9}}
+
+func TestAdditiveGaussianProcess(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	draw := func() []float64 {
+		x := make([]float64, 10)
+
+		for i := range x {
+			x[i] = rng.Float64() * 4
+		}
+
+		return x
+	}
+
+	full := newGaussianProcess()
+	additive := NewAdditiveGaussianProcess(AdditiveGaussianProcessOptions{Groups: pairs})
+	auto := NewAdditiveGaussianProcess(AdditiveGaussianProcessOptions{AutoGroup: true, Seed: 1})
+
+	assert.Nil(t, additive.Groups())
+
+	for i := 0; i < 60; i++ {
+		x := draw()
+		y, _ := additiveFunction(x...)
+
+		assert.NoError(t, full.Update(x, y))
+		assert.NoError(t, additive.Update(x, y))
+		assert.NoError(t, auto.Update(x, y))
+	}
+
+	assert.Equal(t, pairs, additive.Groups())
+	assert.NoError(t, checkGroups(auto.Groups(), 10))
+
+	// Additive models learn the function from far fewer observations.
+	errors := map[SurrogateModel]float64{}
+
+	points := make([][]float64, 300)
+
+	for i := range points {
+		points[i] = draw()
+	}
+
+	for _, model := range []SurrogateModel{full, additive, auto} {
+		means, _ := predictBatch(model, points)
+
+		for i, x := range points {
+			y, _ := additiveFunction(x...)
+
+			errors[model] += math.Pow(means[i]-y, 2) / float64(len(points))
+		}
+	}
+
+	assert.Less(t, errors[additive], errors[full]/2)
+	assert.Less(t, errors[auto], errors[full]/2)
+
+	// Terms sum to the prediction.
+	means, variances := additive.PredictTerms(points[:3])
+
+	assert.Len(t, means, len(pairs))
+
+	for c, x := range points[:3] {
+		mean, variance := additive.Predict(x)
+
+		var sum float64
+
+		for g := range pairs {
+			sum += means[g][c]
+
+			assert.GreaterOrEqual(t, variances[g][c], 0.0)
+			assert.LessOrEqual(t, variances[g][c], variance+1e-9)
+		}
+
+		assert.InDelta(t, mean, sum, 1e-9)
+	}
+
+	// Clones are independent.
+	clone := additive.Clone()
+
+	assert.NoError(t, clone.Update(draw(), 1))
+	assert.Equal(t, 61, clone.Len())
+	assert.Equal(t, 60, additive.Len())
+
+	// Points must have the dimensions of the groups.
+	assert.ErrorIs(t, NewAdditiveGaussianProcess(AdditiveGaussianProcessOptions{Groups: pairs}).Update([]float64{1, 2}, 1), ErrInvalidObservation)
+}
+
+func TestOptimizeAdditive(t *testing.T) {
+	hypers := make([]ParameterRange[float64], 10)
+
+	for i := range hypers {
+		hypers[i] = ParameterRange[float64]{Min: 0, Max: 4}
+	}
+
+	var full, additive float64
+
+	for seed := int64(1); seed <= 3; seed++ {
+		config := DefaultConfig()
+		config.Seed = seed
+		config.InitialSamples = 10
+		config.Iterations = 40
+
+		full += OptimizeObjective(config, additiveFunction, hypers...).BestTime
+
+		config.Surrogate = func() SurrogateModel {
+			return NewAdditiveGaussianProcess(AdditiveGaussianProcessOptions{Groups: pairs})
+		}
+
+		result := OptimizeObjective(config, additiveFunction, hypers...)
+
+		assert.NoError(t, result.Err)
+
+		additive += result.BestTime
+	}
+
+	assert.Less(t, additive, full-3)
+}
+
+func TestAdditiveGaussianProcessInvalid(t *testing.T) {
+	hypers := []ParameterRange[float64]{{Min: 0, Max: 1}, {Min: 0, Max: 1}, {Min: 0, Max: 1}}
+
+	tests := []struct {
+		name    string
+		options AdditiveGaussianProcessOptions
+		want    string
+	}{
+		{name: "no groups", options: AdditiveGaussianProcessOptions{}, want: "no groups"},
+		{name: "overlapping", options: AdditiveGaussianProcessOptions{Groups: [][]int{{0, 1}, {1, 2}}}, want: "dimension 1 is in groups 0 and 1"},
+		{name: "incomplete", options: AdditiveGaussianProcessOptions{Groups: [][]int{{0, 1}}}, want: "dimension 2 is in no group"},
+		{name: "out of range", options: AdditiveGaussianProcessOptions{Groups: [][]int{{0, 1}, {2, 3}}}, want: "group 1: dimension 3 out of [0, 3)"},
+		{name: "empty group", options: AdditiveGaussianProcessOptions{Groups: [][]int{{0, 1, 2}, {}}}, want: "group 1 is empty"},
+		{name: "negative group size", options: AdditiveGaussianProcessOptions{AutoGroup: true, GroupSize: -1}, want: "GroupSize -1 is negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := fastConfig()
+			config.Surrogate = func() SurrogateModel { return NewAdditiveGaussianProcess(tt.options) }
+
+			result := Optimize(config, func(params ...float64) error {
+				t.Fatal("the benchmark must not run")
+
+				return nil
+			}, hypers...)
+
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+			assert.ErrorContains(t, result.Err, tt.want)
+		})
+	}
+
+	// Automatic groups ignore declared ones.
+	config := fastConfig()
+	config.Surrogate = func() SurrogateModel {
+		return NewAdditiveGaussianProcess(AdditiveGaussianProcessOptions{Groups: [][]int{{0, 0}}, AutoGroup: true, GroupSize: 5})
+	}
+
+	result := Optimize(config, func(params ...float64) error { return nil }, hypers...)
+
+	assert.NoError(t, result.Err)
+}
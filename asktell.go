@@ -0,0 +1,1001 @@
+package ho
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Suggestion is a set of parameters to evaluate, handed out by Optimizer.Ask.
+type Suggestion[T constraints.Integer | constraints.Float] struct {
+	// TrialInfo holds the trial metadata. TrialID must be passed back to
+	// Optimizer.Tell along with the result.
+	TrialInfo
+
+	// Params holds the parameter values to evaluate.
+	Params []T
+
+	// BenchmarkParams holds the values to pass the benchmark, if a range is
+	// transformed, see ParameterRange.Transform. Nil otherwise.
+	BenchmarkParams []T
+
+	// ExpiresAt is when the suggestion is abandoned if its result wasn't told,
+	// see OptimizationConfig.LeaseTimeout. Zero if suggestions never expire.
+	ExpiresAt time.Time
+}
+
+// pendingSuggestion is a suggestion whose result wasn't told yet.
+type pendingSuggestion[T constraints.Integer | constraints.Float] struct {
+	// suggestion is the suggestion handed out.
+	suggestion Suggestion[T]
+
+	// askedAt is when the suggestion was handed out.
+	askedAt time.Time
+
+	// scored is the number of candidates scored to propose the suggestion.
+	scored int
+}
+
+// Optimizer is an ask/tell handle over an optimization run, for evaluations
+// driven from outside: call Ask to get parameters to evaluate, evaluate them
+// however and wherever you like, then Tell the result. Unlike Optimize, the
+// run has no fixed length, stop asking whenever you want, e.g. once Done.
+//
+// Usage example:
+//
+//	opt, err := NewOptimizer(DefaultConfig(), ranges...)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	for !opt.Done() {
+//	    suggestion, err := opt.Ask()
+//	    if err != nil {
+//	        return err
+//	    }
+//
+//	    value, err := evaluateRemotely(suggestion.Params)
+//
+//	    if _, err := opt.Tell(suggestion.TrialID, value, err); err != nil {
+//	        return err
+//	    }
+//	}
+//
+//	result := opt.Result()
+//
+// Important notes:
+// - Several suggestions can be pending at once, e.g. to feed parallel
+// workers. Suggestions are diversified with the constant liar strategy: each
+// pending suggestion is assumed to yield the best value seen so far, so the
+// next one goes elsewhere. Lies never reach the model itself
+// - The first InitialSamples suggestions (pending or told, skipped ones
+// excepted) are random samples
+// - With OptimizationConfig.LeaseTimeout, suggestions whose result isn't told
+// in time are abandoned, e.g. when evaluated by batch jobs that may never
+// report. See Pending and Abandoned to persist outstanding suggestions
+// - The handle can run several studies one after another, see Reset
+// - Each study of the handle is tracked and stored on its own, see
+// OptimizationConfig.Trackers and Storage, from its creation or Reset to
+// the next Reset or Close
+// - The handle checkpoints, outstanding suggestions included, see
+// ResumeOptimizerFromCheckpoint. Alternatively, persist Result, Pending and
+// Abandoned, and Restore them. It isn't notified about
+// - A coordinator can share the handle with many workers, e.g. behind a
+// server: Ask and Tell are serialized, and so are model updates. Each
+// suggestion carries the StudyVersion, incremented by Reset, so workers
+// detect resets, and TellVersion rejects results of a previous study,
+// whose trial IDs the new one reuses
+//
+// Thread safety:
+// - All methods are safe for concurrent use.
+type Optimizer[T constraints.Integer | constraints.Float] struct {
+	// mu serializes Ask and Tell.
+	mu sync.Mutex
+
+	// o holds the state of the run.
+	o *optimizer[T]
+
+	// current is o, for the methods that don't wait for Ask and Tell, e.g.
+	// Predict.
+	current atomic.Pointer[optimizer[T]]
+
+	// pending holds the suggestions whose result wasn't told yet, by trial ID.
+	pending map[int]pendingSuggestion[T]
+
+	// abandoned holds the suggestions whose lease expired, by trial ID.
+	abandoned map[int]pendingSuggestion[T]
+
+	// initialTold is the number of initial samples told, skipped excepted.
+	initialTold int
+
+	// told is the number of trials told, skipped excepted.
+	told int
+
+	// iterations is the number of optimization suggestions handed out.
+	iterations int
+
+	// version is the study version, see StudyVersion.
+	version uint64
+
+	// closed is true once the study was closed, until Reset, see Close.
+	closed bool
+}
+
+//////
+// Methods.
+//////
+
+// Ask hands out the next parameters to evaluate.
+//
+// Returns:
+// - Suggestion[T]: Parameters to evaluate, with the trial ID to tell the
+// result with
+// - error: If a Tell requested a stop (see ErrStopOptimization).
+func (opt *Optimizer[T]) Ask() (Suggestion[T], error) {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	switch {
+	case opt.closed:
+		return Suggestion[T]{}, fmt.Errorf("optimization is over: %w", ErrOptimizerClosed)
+	case opt.o.done():
+		return Suggestion[T]{}, fmt.Errorf("optimization is over: %w", opt.o.result().Err)
+	}
+
+	now := time.Now()
+
+	opt.expire(now)
+
+	var (
+		suggestion Suggestion[T]
+		scored     int
+	)
+
+	if initial := opt.initialHandedOut(); initial < opt.o.config.InitialSamples {
+		suggestion.TrialInfo = opt.o.newTrialInfo(PhaseInitialSampling, initial+1, false)
+
+		suggestion.Params = opt.o.initialParams()
+	} else {
+		opt.iterations++
+
+		suggestion.TrialInfo = opt.o.newTrialInfo(PhaseOptimization, opt.iterations, false)
+
+		suggestion.Params, scored = opt.o.nextCandidate(opt.model(), opt.iterations)
+	}
+
+	suggestion.StudyVersion = opt.version
+
+	if params, ok := opt.o.benchmarkParams(suggestion.Params); ok {
+		suggestion.BenchmarkParams = params
+	}
+
+	if opt.o.config.LeaseTimeout > 0 {
+		suggestion.ExpiresAt = now.Add(opt.o.config.LeaseTimeout)
+	}
+
+	opt.pending[suggestion.TrialID] = pendingSuggestion[T]{suggestion: suggestion, askedAt: now, scored: scored}
+
+	// Suggestions are checkpointed as they're handed out, so a resumed
+	// handle still accepts their results.
+	opt.o.mu.Lock()
+	opt.o.checkpoint(true)
+	opt.o.mu.Unlock()
+
+	return suggestion, nil
+}
+
+// SuggestNext previews the parameters the next n suggestions would have,
+// without handing them out: nothing is pending, and the model is left
+// untouched.
+//
+// Parameters:
+// - n: Number of suggestions to preview
+//
+// Returns:
+// - [][]T: The parameters, in the order they would be handed out, or nil if
+// n isn't positive or a Tell requested a stop.
+//
+// Usage example:
+//
+//	// Provision environments for the next batch of trials.
+//	for _, params := range opt.SuggestNext(4) {
+//	    provision(params)
+//	}
+//
+// Important notes:
+// - Previews are diversified as suggestions are: each previewed point is
+// assumed to yield the best value seen so far, as pending ones are, so the
+// next one goes elsewhere
+// - Initial samples are random, as they are when asked
+// - Ask generally doesn't return the previewed parameters: candidates are
+// drawn at random, previews included, and results told in between change
+// the model. Previews are plans, not reservations.
+func (opt *Optimizer[T]) SuggestNext(n int) [][]T {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	if n <= 0 || opt.closed || opt.o.done() {
+		return nil
+	}
+
+	opt.expire(time.Now())
+
+	model := opt.model()
+	lie := opt.o.incumbentTime()
+
+	initial, iteration := opt.initialHandedOut(), opt.iterations
+
+	previews := make([][]T, n)
+
+	for i := range previews {
+		if initial < opt.o.config.InitialSamples {
+			initial++
+
+			previews[i] = opt.o.initialParams()
+		} else {
+			iteration++
+
+			previews[i], _ = opt.o.nextCandidate(model, iteration)
+		}
+
+		if lie != math.MaxFloat64 {
+			model = withLies(model, [][]float64{opt.o.modelPoint(previews[i])}, lie)
+		}
+	}
+
+	return previews
+}
+
+// initialHandedOut returns the number of initial samples handed out, told or
+// pending, skipped ones excepted. Callers must hold mu.
+func (opt *Optimizer[T]) initialHandedOut() int {
+	initial := opt.initialTold
+
+	for _, p := range opt.pending {
+		if p.suggestion.Phase == PhaseInitialSampling {
+			initial++
+		}
+	}
+
+	return initial
+}
+
+// expire abandons the pending suggestions whose lease expired, which
+// retracts their lie. Callers must hold mu.
+func (opt *Optimizer[T]) expire(now time.Time) {
+	for id, p := range opt.pending {
+		if !p.suggestion.ExpiresAt.IsZero() && now.After(p.suggestion.ExpiresAt) {
+			delete(opt.pending, id)
+
+			opt.abandoned[id] = p
+		}
+	}
+}
+
+// model returns the model to score candidates with: the run model, plus a lie
+// at each pending suggestion, see Optimizer.
+func (opt *Optimizer[T]) model() SurrogateModel {
+	lie := opt.o.incumbentTime()
+
+	if len(opt.pending) == 0 || lie == math.MaxFloat64 {
+		return opt.o.model
+	}
+
+	points := make([][]float64, 0, len(opt.pending))
+
+	for _, p := range opt.pendingSorted() {
+		points = append(points, opt.o.modelPoint(p.Params))
+	}
+
+	return withLies(opt.o.model, points, lie)
+}
+
+// pendingSorted returns the pending suggestions, by trial ID.
+func (opt *Optimizer[T]) pendingSorted() []Suggestion[T] {
+	return sortedSuggestions(opt.pending)
+}
+
+// Tell reports the result of a suggestion.
+//
+// Parameters:
+// - trialID: ID of the suggestion, see Suggestion.TrialID
+// - value: The measured value to minimize, e.g. an execution time. Ignored
+// if err isn't nil
+// - err: The evaluation error, if any. ErrSkipTrial and ErrStopOptimization
+// have the same meaning as for benchmark functions
+//
+// Returns:
+// - Trial[T]: The recorded trial
+// - error: Wrapping ErrUnknownTrial if the trial ID doesn't match a pending
+// suggestion, e.g. the result was already told, ErrLeaseExpired if the
+// suggestion was abandoned and late tells aren't accepted, or
+// ErrOptimizerClosed if the study was closed
+//
+// Important notes:
+// - Failed evaluations are penalized so the model learns to avoid them
+// - Non-finite values are penalized or skipped, see
+// OptimizationConfig.NonFiniteValues
+// - Values are transformed, see OptimizationConfig.ObjectiveTransform
+// - Skipped evaluations don't count as samples, the next Ask makes up for
+// them.
+func (opt *Optimizer[T]) Tell(trialID int, value float64, err error) (Trial[T], error) {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	return opt.tell(trialID, value, err)
+}
+
+// TellVersion works like Tell, provided the result belongs to the current
+// study, e.g. for workers that may report after a Reset.
+//
+// Parameters:
+// - version: The study version of the suggestion, see
+// Suggestion.StudyVersion
+// - trialID: ID of the suggestion, see Suggestion.TrialID
+// - value: The measured value to minimize, see Tell
+// - err: The evaluation error, if any, see Tell
+//
+// Returns:
+// - Trial[T]: The recorded trial
+// - error: Wrapping ErrStaleStudy if version isn't the current one,
+// otherwise as returned by Tell.
+func (opt *Optimizer[T]) TellVersion(version uint64, trialID int, value float64, err error) (Trial[T], error) {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	if version != opt.version {
+		return Trial[T]{}, fmt.Errorf("%w: trial %d of study version %d, the study is at version %d", ErrStaleStudy, trialID, version, opt.version)
+	}
+
+	return opt.tell(trialID, value, err)
+}
+
+// tell records the result of a suggestion, see Tell. Callers must hold mu.
+func (opt *Optimizer[T]) tell(trialID int, value float64, err error) (Trial[T], error) {
+	if opt.closed {
+		return Trial[T]{}, fmt.Errorf("%w: trial %d", ErrOptimizerClosed, trialID)
+	}
+
+	opt.expire(time.Now())
+
+	p, ok := opt.pending[trialID]
+	if !ok {
+		p, ok = opt.abandoned[trialID]
+
+		switch {
+		case !ok:
+			return Trial[T]{}, fmt.Errorf("%w: %d", ErrUnknownTrial, trialID)
+		case !opt.o.config.AcceptLateTells:
+			return Trial[T]{}, fmt.Errorf("%w: trial %d expired at %s", ErrLeaseExpired, trialID, p.suggestion.ExpiresAt.Format(time.RFC3339Nano))
+		}
+
+		delete(opt.abandoned, trialID)
+	}
+
+	delete(opt.pending, trialID)
+
+	trial := Trial[T]{
+		TrialInfo:       p.suggestion.TrialInfo,
+		Params:          p.suggestion.Params,
+		BenchmarkParams: p.suggestion.BenchmarkParams,
+		ExecutionTime:   value,
+		Duration:        time.Since(p.askedAt),
+		StartedAt:       p.askedAt,
+		Status:          TrialCompleted,
+		Err:             err,
+	}
+
+	switch {
+	case errors.Is(err, ErrSkipTrial):
+		trial.Status = TrialSkipped
+	case err != nil:
+		trial.Status = TrialFailed
+
+		trial.ExecutionTime = math.MaxFloat64 / 2
+	case !handleNonFinite(&trial, opt.o.config.NonFiniteValues):
+		opt.o.transformObjective(&trial)
+	}
+
+	opt.count(trial)
+
+	trial = opt.o.record(trial)
+
+	total := opt.o.config.Iterations
+	if trial.Phase == PhaseInitialSampling {
+		total = opt.o.config.InitialSamples
+	}
+
+	opt.o.sendProgress(trial, total, p.scored)
+
+	return trial, nil
+}
+
+// count accounts for a told trial in the budget. Initial samples told beyond
+// InitialSamples, e.g. the late result of an abandoned one that was replaced,
+// aren't counted as such. Callers must hold mu.
+func (opt *Optimizer[T]) count(trial Trial[T]) {
+	if trial.Status == TrialSkipped {
+		return
+	}
+
+	opt.told++
+
+	if trial.Phase == PhaseInitialSampling && opt.initialTold < opt.o.config.InitialSamples {
+		opt.initialTold++
+	}
+}
+
+// Pending returns the suggestions whose result wasn't told yet, by trial ID.
+func (opt *Optimizer[T]) Pending() []Suggestion[T] {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	opt.expire(time.Now())
+
+	return opt.pendingSorted()
+}
+
+// Abandoned returns the suggestions whose lease expired before their result
+// was told, by trial ID. Told ones, with AcceptLateTells, are excluded.
+func (opt *Optimizer[T]) Abandoned() []Suggestion[T] {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	opt.expire(time.Now())
+
+	return sortedSuggestions(opt.abandoned)
+}
+
+// Done returns true once InitialSamples plus Iterations results were told
+// (skipped ones excepted), or a Tell requested a stop. Asking for more is
+// still possible, unless a stop was requested.
+func (opt *Optimizer[T]) Done() bool {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	return opt.told >= opt.o.config.InitialSamples+opt.o.config.Iterations || opt.o.done()
+}
+
+// StudyVersion returns the version of the current study: 1 for the first
+// study of the handle, incremented by each Reset.
+func (opt *Optimizer[T]) StudyVersion() uint64 {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	return opt.version
+}
+
+// Observations returns the number of observations in the model, those of
+// previous studies included if it was kept, see Reset.
+func (opt *Optimizer[T]) Observations() int {
+	return opt.current.Load().model.Len()
+}
+
+// Result returns a snapshot of the run results, pending suggestions aside.
+func (opt *Optimizer[T]) Result() *Result[T] {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	return opt.o.result()
+}
+
+// Reset ends the study of the handle and starts a new one, e.g. to tune
+// tenants one after another: trials, the best result and trial IDs start
+// over, while the configuration, search space and seeding policy persist.
+//
+// Parameters:
+// - keepModel: Whether the model keeps the observations of the previous
+// studies, as a prior, instead of starting over from the WarmStart ones
+//
+// Returns:
+// - error: Wrapping ErrStudyInProgress if suggestions are pending, or
+// abandoned ones may still be told, unless the study was closed, nil
+// otherwise.
+//
+// Usage example:
+//
+//	for _, tenant := range tenants {
+//	    if err := opt.Reset(false); err != nil {
+//	        return err
+//	    }
+//
+//	    results[tenant] = tune(opt, tenant)
+//	}
+//
+// Important notes:
+// - Get the Result of a study before resetting, it's discarded. The study is
+// closed first, see Close, and the new one is tracked and stored as a new
+// study
+// - With a fixed OptimizationConfig.Seed, each study starts from it, so
+// studies are reproducible whatever came before: the parameter draws, and
+// AcqParams.RandomState, reseeded from it. Otherwise, each draws a new seed,
+// and RandomState carries on
+// - Initial samples are drawn in each study, even if the model is kept
+// - The study version is incremented, see StudyVersion.
+func (opt *Optimizer[T]) Reset(keepModel bool) error {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	opt.expire(time.Now())
+
+	switch {
+	case opt.closed:
+	case len(opt.pending) > 0:
+		return fmt.Errorf("%w: %d suggestions pending", ErrStudyInProgress, len(opt.pending))
+	case opt.o.config.AcceptLateTells && len(opt.abandoned) > 0:
+		return fmt.Errorf("%w: %d abandoned suggestions may still be told", ErrStudyInProgress, len(opt.abandoned))
+	}
+
+	opt.end()
+
+	previous := opt.o
+
+	config := previous.config
+
+	// The RandomState of the first study is the caller's, left untouched.
+	if config.Seed != 0 {
+		config.AcqParams.RandomState = rand.New(rand.NewSource(config.Seed))
+	}
+
+	o := newOptimizer[T](context.Background(), config, nil, previous.hypers...)
+
+	if keepModel {
+		o.model, o.observations, o.failures = previous.model, previous.observations, previous.failures
+	} else {
+		o.warmStart()
+	}
+
+	opt.pending = make(map[int]pendingSuggestion[T])
+	opt.abandoned = make(map[int]pendingSuggestion[T])
+	opt.initialTold, opt.told, opt.iterations = 0, 0, 0
+	opt.version++
+	opt.closed = false
+
+	opt.start(o)
+
+	return nil
+}
+
+// Close ends the study of the handle, e.g. on shutdown: its outcome is
+// settled, the last checkpoint written, see OptimizationConfig.Checkpoint,
+// and the trackers notified. Asks and tells fail afterwards, until Reset
+// starts a new study.
+//
+// Returns:
+// - *Result[T]: The outcome of the study, pending suggestions aside. If its
+// budget wasn't told, its termination reason is TerminationStopped.
+//
+// Usage example:
+//
+//	defer opt.Close()
+//
+// Important notes:
+// - Pending and abandoned suggestions are checkpointed, so a handle resumed
+// from the checkpoint still accepts their results, see
+// ResumeOptimizerFromCheckpoint
+// - Closing a closed handle only returns the result.
+func (opt *Optimizer[T]) Close() *Result[T] {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	opt.end()
+
+	return opt.o.result()
+}
+
+// start makes o the study of the handle. Callers must hold mu, unless the
+// handle isn't shared yet.
+func (opt *Optimizer[T]) start(o *optimizer[T]) {
+	// Stats report the time since the study started.
+	o.control.start()
+
+	o.checkpointLeases = opt.checkpointLeases
+
+	if o.config.Checkpoint != nil {
+		o.checkpoints = newCheckpointer(*o.config.Checkpoint, o.warnf)
+	}
+
+	o.createStudy()
+
+	o.startTracking()
+
+	opt.o = o
+	opt.current.Store(o)
+}
+
+// end closes the study of the handle, if it isn't, see Close. Callers must
+// hold mu.
+func (opt *Optimizer[T]) end() {
+	if opt.closed {
+		return
+	}
+
+	opt.closed = true
+
+	o := opt.o
+
+	o.mu.Lock()
+
+	if opt.told < o.config.InitialSamples+o.config.Iterations {
+		o.stopped = true
+	}
+
+	ended := o.termination()
+
+	o.ended = &ended
+
+	o.checkpoint(true)
+
+	o.mu.Unlock()
+
+	if o.checkpoints != nil {
+		// Write failures are recorded as warnings.
+		o.checkpoints.close()
+	}
+
+	o.endTracking()
+}
+
+// checkpointLeases adds the pending and abandoned suggestions, and the study
+// version, to a checkpoint. Callers must hold mu.
+func (opt *Optimizer[T]) checkpointLeases(state *checkpointFile) {
+	state.Pending = sortedLeases(opt.pending)
+	state.Abandoned = sortedLeases(opt.abandoned)
+	state.StudyVersion = opt.version
+}
+
+// restoreLease converts a checkpoint lease back to a suggestion of the
+// current study, see restoreLease.
+func (opt *Optimizer[T]) restoreLease(lease checkpointLease) pendingSuggestion[T] {
+	p := restoreLease(opt.o.hypers, lease)
+
+	p.suggestion.StudyVersion = opt.version
+
+	if p.suggestion.Phase == PhaseOptimization {
+		opt.iterations = max(opt.iterations, p.suggestion.Iteration)
+	}
+
+	return p
+}
+
+// Restore brings a new handle back to where a previous one was, e.g. after a
+// coordinator restart: the told trials are recorded, feeding the model and
+// the best result, and the pending suggestions can still be told.
+//
+// Parameters:
+// - trials: The told trials, in tell order, e.g. from Result.Trials
+// - pending: The suggestions whose result wasn't told yet, e.g. from Pending
+// and Abandoned. Those whose lease expired are abandoned
+//
+// Returns:
+// - error: Wrapping ErrStudyInProgress if the handle was already asked,
+// ErrInvalidObservation if a trial or suggestion doesn't have one value per
+// parameter range, nil otherwise. The handle is left untouched on error.
+//
+// Usage example:
+//
+//	opt, err := NewOptimizer(config, ranges...)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	if err := opt.Restore(saved.Trials, saved.Pending); err != nil {
+//	    return err
+//	}
+//
+// Important notes:
+// - Trials are recorded as told: values aren't transformed again, and
+// trials wrapping ErrStopOptimization stop the study. They're reported to
+// the trackers, and stored, as told ones are
+// - The random number generator isn't restored, so suggestions differ from
+// those the previous handle would have made. Use checkpoints with Optimize
+// to resume exactly.
+func (opt *Optimizer[T]) Restore(trials []Trial[T], pending []Suggestion[T]) error {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+
+	if len(opt.pending) > 0 || len(opt.abandoned) > 0 || len(opt.o.trials) > 0 {
+		return fmt.Errorf("%w: the handle was already asked", ErrStudyInProgress)
+	}
+
+	for _, trial := range trials {
+		if len(trial.Params) != len(opt.o.hypers) {
+			return fmt.Errorf("%w: trial %d has %d parameters, expected %d", ErrInvalidObservation, trial.TrialID, len(trial.Params), len(opt.o.hypers))
+		}
+	}
+
+	for _, suggestion := range pending {
+		if len(suggestion.Params) != len(opt.o.hypers) {
+			return fmt.Errorf("%w: suggestion %d has %d parameters, expected %d", ErrInvalidObservation, suggestion.TrialID, len(suggestion.Params), len(opt.o.hypers))
+		}
+	}
+
+	lastTrialID := 0
+
+	for _, trial := range trials {
+		opt.count(trial)
+
+		if trial.Phase == PhaseOptimization {
+			opt.iterations = max(opt.iterations, trial.Iteration)
+		}
+
+		lastTrialID = max(lastTrialID, trial.TrialID)
+
+		if params, ok := opt.o.benchmarkParams(trial.Params); ok && trial.BenchmarkParams == nil {
+			trial.BenchmarkParams = params
+		}
+
+		opt.o.record(trial)
+	}
+
+	now := time.Now()
+
+	for _, suggestion := range pending {
+		if suggestion.Phase == PhaseOptimization {
+			opt.iterations = max(opt.iterations, suggestion.Iteration)
+		}
+
+		lastTrialID = max(lastTrialID, suggestion.TrialID)
+
+		suggestion.StudyVersion = opt.version
+
+		if params, ok := opt.o.benchmarkParams(suggestion.Params); ok && suggestion.BenchmarkParams == nil {
+			suggestion.BenchmarkParams = params
+		}
+
+		opt.pending[suggestion.TrialID] = pendingSuggestion[T]{suggestion: suggestion, askedAt: now}
+	}
+
+	opt.expire(now)
+
+	opt.o.mu.Lock()
+	opt.o.lastTrialID = lastTrialID
+	opt.o.checkpoint(true)
+	opt.o.mu.Unlock()
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// NewOptimizer creates an ask/tell handle, see Optimizer.
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process.
+// TrialTimeout, MaxSkipRetries and MaxConcurrentEvaluations don't apply, the
+// caller drives evaluations. Neither do Notifications
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Optimizer[T]: The handle
+// - error: Wrapping ErrInvalidConfig if the configuration is invalid, or sets
+// an option the handle doesn't support.
+func NewOptimizer[T constraints.Integer | constraints.Float](
+	config OptimizationConfig,
+	hypers ...ParameterRange[T],
+) (*Optimizer[T], error) {
+	return newAskTell(config, nil, "", hypers...)
+}
+
+// ResumeOptimizerFromCheckpoint resumes an ask/tell handle from its last
+// checkpoint, see OptimizationConfig.Checkpoint, e.g. after a coordinator
+// restart: the told trials are restored, along with the model, the best
+// result and the random number generator, and the suggestions that were
+// pending or abandoned can still be told.
+//
+// Parameters:
+// - path: The checkpoint file
+// - config: OptimizationConfig of the handle, see NewOptimizer
+// - hypers: The ParameterRange values of the handle
+//
+// Returns:
+// - *Optimizer[T]: The handle, in the study the checkpoint was written in
+// - error: Wrapping ErrInvalidConfig if the checkpoint can't be read, comes
+// from a different search space, or the configuration is invalid.
+//
+// Usage example:
+//
+//	config.Checkpoint = &Checkpoint{Path: "study.json"}
+//
+//	opt, err := ResumeOptimizerFromCheckpoint("study.json", config, ranges...)
+//	if errors.Is(err, fs.ErrNotExist) {
+//	    opt, err = NewOptimizer(config, ranges...)
+//	}
+//
+// Important notes:
+// - Suggestions whose lease expired in the meantime are abandoned
+// - Checkpoints are written in the background: after a crash, suggestions
+// handed out after the last one written are lost, and their trial IDs
+// handed out again.
+func ResumeOptimizerFromCheckpoint[T constraints.Integer | constraints.Float](
+	path string,
+	config OptimizationConfig,
+	hypers ...ParameterRange[T],
+) (*Optimizer[T], error) {
+	state, err := loadCheckpoint(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	return newAskTell(config, state, "", hypers...)
+}
+
+// ResumeOptimizerFromStorage resumes an ask/tell handle from its stored
+// study, see OptimizationConfig.Storage, e.g. after a coordinator restart:
+// the told trials are restored, along with the model and the best result,
+// and new trials are appended to the study.
+//
+// Parameters:
+// - studyID: The study, see Result.StudyID
+// - config: OptimizationConfig of the handle, Storage included
+// - hypers: The ParameterRange values of the handle
+//
+// Returns:
+// - *Optimizer[T]: The handle
+// - error: Wrapping ErrInvalidConfig if Storage is unset, the study can't be
+// loaded, has a different search space, or the configuration is invalid.
+//
+// Usage example:
+//
+//	config.Storage = storage
+//
+//	opt, err := ResumeOptimizerFromStorage(studyID, config, ranges...)
+//
+// Important notes:
+// - Stored studies only hold told trials: suggestions pending at the
+// restart are lost, and their trial IDs handed out again. Use
+// ResumeOptimizerFromCheckpoint to keep them
+// - The random number generator isn't restored either.
+func ResumeOptimizerFromStorage[T constraints.Integer | constraints.Float](
+	studyID string,
+	config OptimizationConfig,
+	hypers ...ParameterRange[T],
+) (*Optimizer[T], error) {
+	if config.Storage == nil {
+		return nil, fmt.Errorf("%w: resuming a study requires Storage", ErrInvalidConfig)
+	}
+
+	study, err := config.Storage.LoadStudy(context.Background(), studyID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	state, err := newOptimizer[T](context.Background(), config, nil, hypers...).studyCheckpoint(study)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAskTell(config, state, studyID, hypers...)
+}
+
+//////
+// Helpers.
+//////
+
+// newAskTell creates an ask/tell handle, see NewOptimizer.
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process
+// - resumed: The checkpoint to resume from, nil to start afresh
+// - studyID: The stored study the checkpoint comes from, if any, see
+// OptimizationConfig.Storage
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Optimizer[T]: The handle
+// - error: Wrapping ErrInvalidConfig if the configuration is invalid, or sets
+// an option the handle doesn't support.
+func newAskTell[T constraints.Integer | constraints.Float](
+	config OptimizationConfig,
+	resumed *checkpointFile,
+	studyID string,
+	hypers ...ParameterRange[T],
+) (*Optimizer[T], error) {
+	if config.LeaseTimeout < 0 {
+		return nil, fmt.Errorf("%w: LeaseTimeout %v is negative", ErrInvalidConfig, config.LeaseTimeout)
+	}
+
+	if config.Safety != nil && config.Safety.Metric != nil {
+		return nil, fmt.Errorf("%w: Safety: Metric isn't supported, Tell only reports the objective", ErrInvalidConfig)
+	}
+
+	if config.SeedsPerTrial > 1 {
+		return nil, fmt.Errorf("%w: SeedsPerTrial isn't supported, the caller evaluates, and tells the aggregate", ErrInvalidConfig)
+	}
+
+	o := newOptimizer[T](context.Background(), config, nil, hypers...)
+
+	o.resumed = resumed
+	o.studyID = studyID
+
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	opt := &Optimizer[T]{
+		pending:   make(map[int]pendingSuggestion[T]),
+		abandoned: make(map[int]pendingSuggestion[T]),
+		version:   1,
+	}
+
+	if resumed == nil {
+		o.warmStart()
+
+		opt.start(o)
+
+		return opt, nil
+	}
+
+	// The warm start observations are among those of the checkpoint.
+	o.restore()
+
+	opt.start(o)
+
+	opt.version = max(resumed.StudyVersion, 1)
+
+	for _, trial := range o.trials {
+		opt.count(trial)
+
+		if trial.Phase == PhaseOptimization {
+			opt.iterations = max(opt.iterations, trial.Iteration)
+		}
+
+		// Stop requests stop the resumed study, as Restore does.
+		if errors.Is(trial.Err, ErrStopOptimization) {
+			o.stopErr = trial.Err
+		}
+	}
+
+	for _, lease := range resumed.Pending {
+		opt.pending[lease.ID] = opt.restoreLease(lease)
+	}
+
+	for _, lease := range resumed.Abandoned {
+		opt.abandoned[lease.ID] = opt.restoreLease(lease)
+	}
+
+	opt.expire(time.Now())
+
+	return opt, nil
+}
+
+// sortedLeases returns the checkpoint representation of the suggestions, by
+// trial ID.
+func sortedLeases[T constraints.Integer | constraints.Float](m map[int]pendingSuggestion[T]) []checkpointLease {
+	leases := make([]checkpointLease, 0, len(m))
+
+	for _, p := range m {
+		leases = append(leases, newCheckpointLease(p))
+	}
+
+	sort.Slice(leases, func(i, j int) bool { return leases[i].ID < leases[j].ID })
+
+	return leases
+}
+
+// sortedSuggestions returns the suggestions, by trial ID.
+func sortedSuggestions[T constraints.Integer | constraints.Float](m map[int]pendingSuggestion[T]) []Suggestion[T] {
+	suggestions := make([]Suggestion[T], 0, len(m))
+
+	for _, p := range m {
+		suggestions = append(suggestions, p.suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool { return suggestions[i].TrialID < suggestions[j].TrialID })
+
+	return suggestions
+}
@@ -0,0 +1,914 @@
+package ho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho/benchfuncs"
+	"golang.org/x/exp/constraints"
+)
+
+func TestAskTell(t *testing.T) {
+	f := benchfuncs.Branin()
+
+	config := fastConfig()
+
+	opt, err := NewOptimizer(config, rangesOf(f)...)
+	assert.NoError(t, err)
+
+	for i := 0; !opt.Done(); i++ {
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+
+		if i < config.InitialSamples {
+			assert.Equal(t, PhaseInitialSampling, suggestion.Phase)
+		} else {
+			assert.Equal(t, PhaseOptimization, suggestion.Phase)
+			assert.Equal(t, i-config.InitialSamples+1, suggestion.Iteration)
+		}
+
+		value, err := f.Objective(suggestion.Params...)
+
+		trial, err := opt.Tell(suggestion.TrialID, value, err)
+		assert.NoError(t, err)
+		assert.Equal(t, TrialCompleted, trial.Status)
+	}
+
+	result := opt.Result()
+
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+	assert.Equal(t, len(result.Trials), opt.Observations())
+	assert.GreaterOrEqual(t, result.BestTime, f.Optimum)
+}
+
+func TestAskTellPending(t *testing.T) {
+	config := fastConfig()
+
+	opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10}, ParameterRange[float64]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	// Pending initial samples count towards InitialSamples.
+	for i := 0; i < config.InitialSamples+3; i++ {
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+
+		if i < config.InitialSamples {
+			assert.Equal(t, PhaseInitialSampling, suggestion.Phase)
+		} else {
+			assert.Equal(t, PhaseOptimization, suggestion.Phase)
+		}
+	}
+
+	pending := opt.Pending()
+	assert.Len(t, pending, config.InitialSamples+3)
+
+	// Tell the initial samples, then ask again while optimization
+	// suggestions are pending: lies diversify them but never reach the model.
+	for _, suggestion := range pending[:config.InitialSamples] {
+		_, err := opt.Tell(suggestion.TrialID, suggestion.Params[0]+suggestion.Params[1], nil)
+		assert.NoError(t, err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := opt.Ask()
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, opt.Pending(), 6)
+	assert.Equal(t, config.InitialSamples, opt.Observations())
+
+	seen := map[[2]float64]bool{}
+
+	for _, suggestion := range opt.Pending() {
+		seen[[2]float64{suggestion.Params[0], suggestion.Params[1]}] = true
+	}
+
+	assert.Len(t, seen, 6)
+}
+
+func TestSuggestNext(t *testing.T) {
+	config := fastConfig()
+
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}, {Min: 0, Max: 10}}
+
+	opt, err := NewOptimizer(config, ranges...)
+	assert.NoError(t, err)
+
+	assert.Nil(t, opt.SuggestNext(0))
+
+	// Previews span the initial samples and the optimization suggestions.
+	previews := opt.SuggestNext(config.InitialSamples + 2)
+
+	assert.Len(t, previews, config.InitialSamples+2)
+	assert.Empty(t, opt.Pending())
+
+	for i := 0; i < config.InitialSamples; i++ {
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+
+		_, err = opt.Tell(suggestion.TrialID, suggestion.Params[0]+suggestion.Params[1], nil)
+		assert.NoError(t, err)
+	}
+
+	// A pending suggestion is diversified against, as previews are against
+	// each other.
+	suggestion, err := opt.Ask()
+	assert.NoError(t, err)
+
+	previews = opt.SuggestNext(5)
+
+	assert.Len(t, previews, 5)
+	assert.Equal(t, config.InitialSamples, opt.Observations())
+	assert.Len(t, opt.Pending(), 1)
+
+	seen := map[[2]float64]bool{{suggestion.Params[0], suggestion.Params[1]}: true}
+
+	for _, params := range previews {
+		assert.Len(t, params, 2)
+
+		for i, v := range params {
+			assert.GreaterOrEqual(t, v, ranges[i].Min)
+			assert.LessOrEqual(t, v, ranges[i].Max)
+		}
+
+		seen[[2]float64{params[0], params[1]}] = true
+	}
+
+	assert.Len(t, seen, 6)
+}
+
+func TestAskTellErrors(t *testing.T) {
+	config := fastConfig()
+
+	opt, err := NewOptimizer(config, ParameterRange[int]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	// Unknown trial.
+	_, err = opt.Tell(42, 1, nil)
+	assert.ErrorIs(t, err, ErrUnknownTrial)
+
+	suggestion, _ := opt.Ask()
+
+	_, err = opt.Tell(suggestion.TrialID, 1, nil)
+	assert.NoError(t, err)
+
+	// Double tell.
+	_, err = opt.Tell(suggestion.TrialID, 1, nil)
+	assert.ErrorIs(t, err, ErrUnknownTrial)
+
+	// Failures and non-finite values are penalized, and kept out of the
+	// model.
+	for _, tell := range []struct {
+		value float64
+		err   error
+	}{{value: 1, err: errors.New("boom")}, {value: math.NaN()}, {value: math.Inf(-1)}} {
+		suggestion, _ = opt.Ask()
+
+		trial, err := opt.Tell(suggestion.TrialID, tell.value, tell.err)
+		assert.NoError(t, err)
+		assert.Equal(t, TrialFailed, trial.Status)
+		assert.Equal(t, math.MaxFloat64/2, trial.ExecutionTime)
+	}
+
+	// Skipped trials don't count as samples.
+	suggestion, _ = opt.Ask()
+	assert.Equal(t, PhaseOptimization, suggestion.Phase)
+
+	trial, err := opt.Tell(suggestion.TrialID, 1, ErrSkipTrial)
+	assert.NoError(t, err)
+	assert.Equal(t, TrialSkipped, trial.Status)
+	assert.Equal(t, 1, opt.Observations())
+
+	// Stop requests end the run.
+	suggestion, _ = opt.Ask()
+
+	_, err = opt.Tell(suggestion.TrialID, 1, ErrStopOptimization)
+	assert.NoError(t, err)
+	assert.True(t, opt.Done())
+
+	_, err = opt.Ask()
+	assert.ErrorIs(t, err, ErrStopOptimization)
+
+	// Invalid configuration.
+	config.ExclusionZones = []Box{{Min: []float64{0}, Max: []float64{10}}}
+
+	_, err = NewOptimizer(config, ParameterRange[int]{Min: 0, Max: 10})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestAskTellLeases(t *testing.T) {
+	config := fastConfig()
+	config.LeaseTimeout = 20 * time.Millisecond
+
+	opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	lost, err := opt.Ask()
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(config.LeaseTimeout), lost.ExpiresAt, config.LeaseTimeout)
+
+	kept, err := opt.Ask()
+	assert.NoError(t, err)
+
+	time.Sleep(2 * config.LeaseTimeout)
+
+	// Expired suggestions are abandoned, and no longer count as pending
+	// initial samples.
+	assert.Empty(t, opt.Pending())
+	assert.Equal(t, []int{lost.TrialID, kept.TrialID}, trialIDs(opt.Abandoned()))
+
+	for i := 0; i < config.InitialSamples; i++ {
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+		assert.Equal(t, PhaseInitialSampling, suggestion.Phase)
+		assert.Equal(t, i+1, suggestion.Iteration)
+	}
+
+	// Late tells are rejected.
+	_, err = opt.Tell(lost.TrialID, 1, nil)
+	assert.ErrorIs(t, err, ErrLeaseExpired)
+
+	_, err = opt.Tell(lost.TrialID, 1, nil)
+	assert.ErrorIs(t, err, ErrLeaseExpired)
+
+	assert.Equal(t, 0, opt.Observations())
+
+	// Unless accepted, once.
+	config.AcceptLateTells = true
+
+	opt, err = NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	lost, _ = opt.Ask()
+
+	time.Sleep(2 * config.LeaseTimeout)
+
+	trial, err := opt.Tell(lost.TrialID, 1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, TrialCompleted, trial.Status)
+	assert.Equal(t, 1, opt.Observations())
+	assert.Empty(t, opt.Abandoned())
+
+	_, err = opt.Tell(lost.TrialID, 1, nil)
+	assert.ErrorIs(t, err, ErrUnknownTrial)
+
+	// Suggestions told in time aren't abandoned.
+	suggestion, _ := opt.Ask()
+
+	_, err = opt.Tell(suggestion.TrialID, 2, nil)
+	assert.NoError(t, err)
+
+	time.Sleep(2 * config.LeaseTimeout)
+
+	assert.Empty(t, opt.Abandoned())
+
+	config.LeaseTimeout = -time.Second
+
+	_, err = NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestAskTellLateInitialSample(t *testing.T) {
+	config := fastConfig()
+	config.InitialSamples = 2
+	config.LeaseTimeout = 20 * time.Millisecond
+	config.AcceptLateTells = true
+
+	opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	lost, err := opt.Ask()
+	assert.NoError(t, err)
+
+	time.Sleep(2 * config.LeaseTimeout)
+
+	// The abandoned initial sample is replaced.
+	for range config.InitialSamples {
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+		assert.Equal(t, PhaseInitialSampling, suggestion.Phase)
+
+		_, err = opt.Tell(suggestion.TrialID, suggestion.Params[0], nil)
+		assert.NoError(t, err)
+	}
+
+	// Its late result feeds the model, without counting as an extra initial
+	// sample.
+	_, err = opt.Tell(lost.TrialID, lost.Params[0], nil)
+	assert.NoError(t, err)
+	assert.Equal(t, config.InitialSamples+1, opt.Observations())
+
+	opt.mu.Lock()
+	assert.Equal(t, config.InitialSamples, opt.initialTold)
+	assert.Equal(t, config.InitialSamples+1, opt.told)
+	opt.mu.Unlock()
+
+	suggestion, err := opt.Ask()
+	assert.NoError(t, err)
+	assert.Equal(t, PhaseOptimization, suggestion.Phase)
+}
+
+func TestAskTellLeaseRetractsLie(t *testing.T) {
+	config := fastConfig()
+	config.InitialSamples = 2
+	config.LeaseTimeout = 20 * time.Millisecond
+
+	opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	for i := 0; i < config.InitialSamples; i++ {
+		suggestion, _ := opt.Ask()
+
+		_, err := opt.Tell(suggestion.TrialID, suggestion.Params[0], nil)
+		assert.NoError(t, err)
+	}
+
+	_, err = opt.Ask()
+	assert.NoError(t, err)
+
+	// The pending suggestion is lied about, until abandoned.
+	opt.mu.Lock()
+	assert.Equal(t, config.InitialSamples+1, opt.model().Len())
+	opt.mu.Unlock()
+
+	time.Sleep(2 * config.LeaseTimeout)
+
+	assert.Len(t, opt.Abandoned(), 1)
+
+	opt.mu.Lock()
+	assert.Equal(t, config.InitialSamples, opt.model().Len())
+	opt.mu.Unlock()
+}
+
+func TestAskTellReset(t *testing.T) {
+	// study runs a study to completion, of a tenant whose optimum is at
+	// offset.
+	study := func(t *testing.T, opt *Optimizer[float64], offset float64) *Result[float64] {
+		for !opt.Done() {
+			suggestion, err := opt.Ask()
+			assert.NoError(t, err)
+
+			_, err = opt.Tell(suggestion.TrialID, math.Pow(suggestion.Params[0]-offset, 2)+offset, nil)
+			assert.NoError(t, err)
+		}
+
+		return opt.Result()
+	}
+
+	for _, keepModel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("keepModel=%v", keepModel), func(t *testing.T) {
+			var events bytes.Buffer
+
+			tracker := NewMemoryTracker()
+			storage := NewMemoryStorage()
+
+			config := fastConfig()
+			config.Seed = 7
+			config.Trackers = []Tracker{tracker, NewJSONLinesTracker(&events)}
+			config.Storage = storage
+
+			opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+			assert.NoError(t, err)
+
+			n := config.InitialSamples + config.Iterations
+
+			var results []*Result[float64]
+
+			for i, offset := range []float64{2, 5, 8} {
+				if i > 0 {
+					assert.NoError(t, opt.Reset(keepModel))
+				}
+
+				result := study(t, opt, offset)
+
+				// Each study is tracked and stored on its own.
+				assert.Nil(t, tracker.Summary())
+				assert.Len(t, tracker.Trials(), n)
+				assert.Equal(t, 1, tracker.Trials()[0].ID)
+				assert.Equal(t, result.Trials[n-1].ExecutionTime, *tracker.Trials()[n-1].Value)
+
+				assert.Equal(t, strconv.Itoa(i+1), result.StudyID)
+
+				stored, err := storage.LoadStudy(context.Background(), result.StudyID)
+				assert.NoError(t, err)
+				assert.Len(t, stored.Trials, n)
+				assert.Equal(t, result.BestTime, *stored.Best.Value)
+
+				// Each study only holds its own trials and best, the
+				// values of the previous tenants being lower.
+				assert.Len(t, result.Trials, n)
+				assert.Equal(t, 1, result.Trials[0].TrialID)
+				assert.GreaterOrEqual(t, result.BestTime, offset)
+
+				observations := n
+				if keepModel {
+					observations = (i + 1) * n
+				}
+
+				assert.Equal(t, observations, opt.Observations())
+				assert.Equal(t, n, opt.Stats().Trials)
+
+				results = append(results, result)
+			}
+
+			assert.Equal(t, results[2].Trials, opt.Close().Trials)
+			assert.Equal(t, TerminationCompleted, tracker.Summary().TerminationReason)
+
+			studies, err := storage.ListStudies(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, studies, 3)
+
+			// Each study ends before the next one starts.
+			var (
+				sequence []string
+				trials   int
+			)
+
+			for decoder := json.NewDecoder(&events); decoder.More(); {
+				var event TrackerEvent
+
+				assert.NoError(t, decoder.Decode(&event))
+
+				switch event.Event {
+				case "OnTrialEnd":
+					trials++
+				case "OnStudyEnd":
+					assert.Equal(t, n, event.Summary.Trials)
+
+					sequence = append(sequence, fmt.Sprintf("%d trials", trials), event.Event)
+					trials = 0
+				case "OnStudyStart":
+					sequence = append(sequence, event.Event)
+				}
+			}
+
+			lifecycle := []string{"OnStudyStart", fmt.Sprintf("%d trials", n), "OnStudyEnd"}
+
+			assert.Equal(t, slices.Concat(lifecycle, lifecycle, lifecycle), sequence)
+
+			// The seed persists, so initial samples are the same in each
+			// study.
+			for _, result := range results[1:] {
+				for i := range config.InitialSamples {
+					assert.Equal(t, results[0].Trials[i].Params, result.Trials[i].Params)
+				}
+			}
+		})
+	}
+
+	t.Run("in progress", func(t *testing.T) {
+		config := fastConfig()
+		config.LeaseTimeout = 20 * time.Millisecond
+		config.AcceptLateTells = true
+
+		opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+		assert.NoError(t, err)
+
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+
+		// Pending suggestions belong to the study.
+		assert.ErrorIs(t, opt.Reset(false), ErrStudyInProgress)
+
+		// So do abandoned ones that may still be told.
+		time.Sleep(30 * time.Millisecond)
+
+		assert.ErrorIs(t, opt.Reset(false), ErrStudyInProgress)
+
+		_, err = opt.Tell(suggestion.TrialID, 1, nil)
+		assert.NoError(t, err)
+
+		assert.NoError(t, opt.Reset(false))
+
+		// Results of the previous study aren't told to the new one.
+		_, err = opt.Tell(suggestion.TrialID, 1, nil)
+		assert.ErrorIs(t, err, ErrUnknownTrial)
+
+		assert.Empty(t, opt.Result().Trials)
+		assert.Zero(t, opt.Observations())
+	})
+
+	t.Run("random state", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 7
+		config.AcqParams.RandomState = rand.New(rand.NewSource(1))
+
+		opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+		assert.NoError(t, err)
+
+		// Each study after the first starts from the seed, as Thompson
+		// Sampling draws would otherwise differ.
+		want := rand.New(rand.NewSource(config.Seed)).Float64()
+
+		for range 2 {
+			assert.NoError(t, opt.Reset(false))
+
+			state := opt.o.config.AcqParams.RandomState
+
+			assert.NotSame(t, config.AcqParams.RandomState, state)
+			assert.Equal(t, want, state.Float64())
+		}
+	})
+}
+
+func TestAskTellRestore(t *testing.T) {
+	config := fastConfig()
+
+	hyper := ParameterRange[float64]{Min: 0, Max: 10}
+
+	opt, err := NewOptimizer(config, hyper)
+	assert.NoError(t, err)
+
+	n := config.InitialSamples + 2
+
+	suggestions := make([]Suggestion[float64], n)
+
+	for i := range suggestions {
+		suggestions[i], err = opt.Ask()
+		assert.NoError(t, err)
+	}
+
+	for _, s := range suggestions[:n-1] {
+		_, err := opt.Tell(s.TrialID, s.Params[0], nil)
+		assert.NoError(t, err)
+	}
+
+	saved := opt.Result()
+
+	restored, err := NewOptimizer(config, hyper)
+	assert.NoError(t, err)
+	assert.NoError(t, restored.Restore(saved.Trials, opt.Pending()))
+
+	result := restored.Result()
+
+	assert.Equal(t, saved.BestTime, result.BestTime)
+	assert.Equal(t, saved.BestParams, result.BestParams)
+	assert.Len(t, result.Trials, n-1)
+	assert.Equal(t, opt.Observations(), restored.Observations())
+	assert.Equal(t, opt.Pending(), restored.Pending())
+
+	// The pending suggestion can be told, and the next one follows it.
+	_, err = restored.Tell(suggestions[n-1].TrialID, 1, nil)
+	assert.NoError(t, err)
+
+	next, err := restored.Ask()
+	assert.NoError(t, err)
+	assert.Equal(t, suggestions[n-1].TrialID+1, next.TrialID)
+	assert.Equal(t, suggestions[n-1].Iteration+1, next.Iteration)
+
+	// Only new handles are restored.
+	assert.ErrorIs(t, restored.Restore(saved.Trials, nil), ErrStudyInProgress)
+
+	fresh, err := NewOptimizer(config, hyper)
+	assert.NoError(t, err)
+
+	invalid := []Trial[float64]{{TrialInfo: TrialInfo{TrialID: 1}, Params: []float64{1, 2}}}
+
+	assert.ErrorIs(t, fresh.Restore(invalid, nil), ErrInvalidObservation)
+	assert.Empty(t, fresh.Result().Trials)
+
+	// Stop requests stop the restored study.
+	stopped := []Trial[float64]{{
+		TrialInfo: TrialInfo{TrialID: 1, Phase: PhaseInitialSampling, Iteration: 1},
+		Params:    []float64{1},
+		Status:    TrialFailed,
+		Err:       ErrStopOptimization,
+	}}
+
+	assert.NoError(t, fresh.Restore(stopped, nil))
+
+	_, err = fresh.Ask()
+	assert.ErrorIs(t, err, ErrStopOptimization)
+}
+
+func TestAskTellCheckpoint(t *testing.T) {
+	config := fastConfig()
+	config.Checkpoint = &Checkpoint{Path: filepath.Join(t.TempDir(), "study.json")}
+	config.LeaseTimeout = time.Minute
+	config.AcceptLateTells = true
+
+	hyper := ParameterRange[float64]{Min: 0, Max: 10}
+
+	opt, err := NewOptimizer(config, hyper)
+	assert.NoError(t, err)
+
+	assert.NoError(t, opt.Reset(false))
+
+	for range config.InitialSamples {
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+
+		_, err = opt.Tell(suggestion.TrialID, suggestion.Params[0], nil)
+		assert.NoError(t, err)
+	}
+
+	lost, err := opt.Ask()
+	assert.NoError(t, err)
+
+	pending, err := opt.Ask()
+	assert.NoError(t, err)
+
+	// The lease of lost expires.
+	opt.mu.Lock()
+	p := opt.pending[lost.TrialID]
+	p.suggestion.ExpiresAt = time.Now()
+	opt.pending[lost.TrialID] = p
+	opt.mu.Unlock()
+
+	assert.Len(t, opt.Abandoned(), 1)
+
+	// Closing writes the last checkpoint, e.g. on shutdown.
+	saved := opt.Close()
+	assert.Equal(t, TerminationStopped, saved.TerminationReason)
+
+	_, err = opt.Ask()
+	assert.ErrorIs(t, err, ErrOptimizerClosed)
+
+	_, err = opt.Tell(pending.TrialID, 1, nil)
+	assert.ErrorIs(t, err, ErrOptimizerClosed)
+
+	resumed, err := ResumeOptimizerFromCheckpoint(config.Checkpoint.Path, config, hyper)
+	assert.NoError(t, err)
+
+	result := resumed.Result()
+
+	assert.Equal(t, saved.BestTime, result.BestTime)
+	assert.Equal(t, saved.BestParams, result.BestParams)
+	assert.Len(t, result.Trials, config.InitialSamples)
+	assert.Equal(t, opt.Observations(), resumed.Observations())
+	assert.Equal(t, uint64(2), resumed.StudyVersion())
+
+	// The leases survive the restart.
+	restored := resumed.Pending()
+
+	assert.Equal(t, []int{pending.TrialID}, trialIDs(restored))
+	assert.Equal(t, pending.Params, restored[0].Params)
+	assert.Equal(t, pending.StudyVersion, restored[0].StudyVersion)
+	assert.True(t, pending.ExpiresAt.Equal(restored[0].ExpiresAt))
+	assert.Equal(t, []int{lost.TrialID}, trialIDs(resumed.Abandoned()))
+
+	_, err = resumed.TellVersion(pending.StudyVersion, pending.TrialID, 1, nil)
+	assert.NoError(t, err)
+
+	_, err = resumed.Tell(lost.TrialID, 2, nil)
+	assert.NoError(t, err)
+
+	next, err := resumed.Ask()
+	assert.NoError(t, err)
+	assert.Equal(t, pending.TrialID+1, next.TrialID)
+	assert.Equal(t, pending.Iteration+1, next.Iteration)
+
+	resumed.Close()
+
+	_, err = ResumeOptimizerFromCheckpoint(filepath.Join(t.TempDir(), "missing.json"), config, hyper)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = ResumeOptimizerFromCheckpoint(config.Checkpoint.Path, config, hyper, hyper)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestAskTellResumeFromStorage(t *testing.T) {
+	config := fastConfig()
+	config.Storage = NewMemoryStorage()
+
+	hyper := ParameterRange[float64]{Min: 0, Max: 10}
+
+	opt, err := NewOptimizer(config, hyper)
+	assert.NoError(t, err)
+
+	for range config.InitialSamples + 1 {
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+
+		_, err = opt.Tell(suggestion.TrialID, suggestion.Params[0], nil)
+		assert.NoError(t, err)
+	}
+
+	saved := opt.Close()
+
+	resumed, err := ResumeOptimizerFromStorage(saved.StudyID, config, hyper)
+	assert.NoError(t, err)
+
+	result := resumed.Result()
+
+	assert.Equal(t, saved.StudyID, result.StudyID)
+	assert.Equal(t, saved.BestTime, result.BestTime)
+	assert.Equal(t, saved.BestParams, result.BestParams)
+	assert.Len(t, result.Trials, len(saved.Trials))
+	assert.Equal(t, opt.Observations(), resumed.Observations())
+
+	// New trials are appended to the study.
+	next, err := resumed.Ask()
+	assert.NoError(t, err)
+	assert.Equal(t, len(saved.Trials)+1, next.TrialID)
+	assert.Equal(t, 2, next.Iteration)
+
+	_, err = resumed.Tell(next.TrialID, 1, nil)
+	assert.NoError(t, err)
+
+	stored, err := config.Storage.LoadStudy(context.Background(), saved.StudyID)
+	assert.NoError(t, err)
+	assert.Len(t, stored.Trials, len(saved.Trials)+1)
+
+	studies, err := config.Storage.ListStudies(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, studies, 1)
+
+	// A stop request survives the round trip through storage.
+	next, err = resumed.Ask()
+	assert.NoError(t, err)
+
+	_, err = resumed.Tell(next.TrialID, 0, fmt.Errorf("quota: %w", ErrStopOptimization))
+	assert.NoError(t, err)
+
+	stopped := resumed.Close()
+
+	resumed, err = ResumeOptimizerFromStorage(saved.StudyID, config, hyper)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, resumed.Result().Trials[len(stopped.Trials)-1].Err, ErrStopOptimization)
+
+	_, err = resumed.Ask()
+	assert.ErrorIs(t, err, ErrStopOptimization)
+
+	_, err = ResumeOptimizerFromStorage("unknown", config, hyper)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	config.Storage = nil
+
+	_, err = ResumeOptimizerFromStorage(saved.StudyID, config, hyper)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestAskTellCoordinator(t *testing.T) {
+	const workers, asksPerWorker = 10, 10
+
+	config := fastConfig()
+	config.Seed = 1
+
+	opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10}, ParameterRange[float64]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	var (
+		mu        sync.Mutex
+		asked     = map[int]int{}
+		completed = map[int]bool{}
+		failed    = map[int]bool{}
+		dropped   = map[int]bool{}
+		wg        sync.WaitGroup
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func(seed int64) {
+			defer wg.Done()
+
+			rng := rand.New(rand.NewSource(seed))
+
+			for i := 0; i < asksPerWorker; i++ {
+				suggestion, err := opt.Ask()
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				assert.Equal(t, uint64(1), suggestion.StudyVersion)
+
+				mu.Lock()
+				asked[suggestion.TrialID]++
+				mu.Unlock()
+
+				time.Sleep(time.Duration(rng.Intn(3000)) * time.Microsecond)
+
+				var evalErr error
+
+				switch r := rng.Float64(); {
+				case r < 0.2:
+					// The worker crashed: the result is never told.
+					mu.Lock()
+					dropped[suggestion.TrialID] = true
+					mu.Unlock()
+
+					continue
+				case r < 0.3:
+					evalErr = errors.New("worker failed")
+				}
+
+				value := math.Pow(suggestion.Params[0]-3, 2) + math.Pow(suggestion.Params[1]-7, 2)
+
+				trial, err := opt.TellVersion(suggestion.StudyVersion, suggestion.TrialID, value, evalErr)
+				if !assert.NoError(t, err) {
+					continue
+				}
+
+				assert.Equal(t, suggestion.TrialInfo, trial.TrialInfo)
+
+				mu.Lock()
+				if evalErr != nil {
+					failed[trial.TrialID] = true
+				} else {
+					completed[trial.TrialID] = true
+				}
+				mu.Unlock()
+
+				// Retried tells, e.g. on a lost response, aren't counted
+				// twice.
+				if rng.Float64() < 0.2 {
+					_, err := opt.TellVersion(suggestion.StudyVersion, suggestion.TrialID, value, evalErr)
+					assert.ErrorIs(t, err, ErrUnknownTrial)
+				}
+			}
+		}(int64(w))
+	}
+
+	wg.Wait()
+
+	// Every suggestion was handed out once, with consecutive trial IDs.
+	assert.Len(t, asked, workers*asksPerWorker)
+
+	for id := 1; id <= workers*asksPerWorker; id++ {
+		assert.Equal(t, 1, asked[id], id)
+	}
+
+	// Told suggestions are trials, the others still pending.
+	result := opt.Result()
+
+	assert.Len(t, result.Trials, len(completed)+len(failed))
+
+	for _, trial := range result.Trials {
+		assert.True(t, completed[trial.TrialID] || failed[trial.TrialID], trial.TrialID)
+		assert.Equal(t, failed[trial.TrialID], trial.Status == TrialFailed, trial.TrialID)
+	}
+
+	pending := opt.Pending()
+
+	assert.Len(t, pending, len(dropped))
+
+	for _, suggestion := range pending {
+		assert.True(t, dropped[suggestion.TrialID], suggestion.TrialID)
+	}
+
+	// Only completed tells reach the model, lies never do.
+	assert.Equal(t, len(completed), opt.Observations())
+	assert.Equal(t, len(completed), opt.o.model.Len())
+}
+
+func TestAskTellStudyVersion(t *testing.T) {
+	opt, err := NewOptimizer(fastConfig(), ParameterRange[float64]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(1), opt.StudyVersion())
+
+	old, err := opt.Ask()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), old.StudyVersion)
+
+	trial, err := opt.TellVersion(old.StudyVersion, old.TrialID, 1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), trial.StudyVersion)
+
+	assert.NoError(t, opt.Reset(false))
+	assert.Equal(t, uint64(2), opt.StudyVersion())
+
+	// The new study reuses the trial ID, results of the previous one are
+	// rejected.
+	suggestion, err := opt.Ask()
+	assert.NoError(t, err)
+	assert.Equal(t, old.TrialID, suggestion.TrialID)
+	assert.Equal(t, uint64(2), suggestion.StudyVersion)
+
+	_, err = opt.TellVersion(old.StudyVersion, old.TrialID, 1, nil)
+	assert.ErrorIs(t, err, ErrStaleStudy)
+
+	assert.Len(t, opt.Pending(), 1)
+
+	_, err = opt.TellVersion(suggestion.StudyVersion, suggestion.TrialID, 2, nil)
+	assert.NoError(t, err)
+
+	assert.Empty(t, opt.Pending())
+	assert.Equal(t, 1, opt.Observations())
+}
+
+// trialIDs returns the trial IDs of suggestions.
+func trialIDs[T constraints.Integer | constraints.Float](suggestions []Suggestion[T]) []int {
+	ids := make([]int, len(suggestions))
+
+	for i, suggestion := range suggestions {
+		ids[i] = suggestion.TrialID
+	}
+
+	return ids
+}
@@ -0,0 +1,197 @@
+package benchfuncs
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Objective is a function to minimize. It's assignable to
+// ho.ObjectiveFunc[float64].
+type Objective func(params ...float64) (float64, error)
+
+// Bound is the search range of a dimension.
+type Bound struct {
+	// Min is the minimum (inclusive) value.
+	Min float64
+
+	// Max is the maximum (inclusive) value.
+	Max float64
+}
+
+// Function is a synthetic test function along with its metadata.
+type Function struct {
+	// Name of the function.
+	Name string
+
+	// Bounds holds the documented search range of each dimension.
+	Bounds []Bound
+
+	// Optimum is the known global minimum value.
+	Optimum float64
+
+	// Minimizers holds the known locations of the global minimum.
+	Minimizers [][]float64
+
+	// Objective evaluates the function. It returns an error if the number of
+	// parameters doesn't match the number of dimensions.
+	Objective Objective
+}
+
+//////
+// Functions.
+//////
+
+// Branin returns the 2-dimensional Branin-Hoo function, with three global
+// minima.
+//
+// Bounds: x1 in [-5, 10], x2 in [0, 15]
+//
+// Optimum: 0.397887 at (-pi, 12.275), (pi, 2.275) and (9.42478, 2.475).
+func Branin() Function {
+	const (
+		a = 1.0
+		r = 6.0
+		s = 10.0
+	)
+
+	b := 5.1 / (4 * math.Pi * math.Pi)
+	c := 5 / math.Pi
+	t := 1 / (8 * math.Pi)
+
+	return Function{
+		Name:    "Branin",
+		Bounds:  []Bound{{Min: -5, Max: 10}, {Min: 0, Max: 15}},
+		Optimum: 0.397887,
+		Minimizers: [][]float64{
+			{-math.Pi, 12.275},
+			{math.Pi, 2.275},
+			{9.42478, 2.475},
+		},
+		Objective: withDimensions(2, func(x ...float64) float64 {
+			return a*math.Pow(x[1]-b*x[0]*x[0]+c*x[0]-r, 2) + s*(1-t)*math.Cos(x[0]) + s
+		}),
+	}
+}
+
+// Rosenbrock returns the d-dimensional Rosenbrock function, whose global
+// minimum lies in a long, narrow, parabolic valley.
+//
+// Bounds: xi in [-5, 10]
+//
+// Optimum: 0 at (1, ..., 1).
+func Rosenbrock(d int) Function {
+	return Function{
+		Name:       "Rosenbrock",
+		Bounds:     repeat(Bound{Min: -5, Max: 10}, d),
+		Optimum:    0,
+		Minimizers: [][]float64{fill(1, d)},
+		Objective: withDimensions(d, func(x ...float64) float64 {
+			var sum float64
+
+			for i := 0; i < len(x)-1; i++ {
+				sum += 100*math.Pow(x[i+1]-x[i]*x[i], 2) + math.Pow(1-x[i], 2)
+			}
+
+			return sum
+		}),
+	}
+}
+
+// Rastrigin returns the d-dimensional Rastrigin function, highly multimodal
+// with regularly distributed local minima.
+//
+// Bounds: xi in [-5.12, 5.12]
+//
+// Optimum: 0 at (0, ..., 0).
+func Rastrigin(d int) Function {
+	return Function{
+		Name:       "Rastrigin",
+		Bounds:     repeat(Bound{Min: -5.12, Max: 5.12}, d),
+		Optimum:    0,
+		Minimizers: [][]float64{fill(0, d)},
+		Objective: withDimensions(d, func(x ...float64) float64 {
+			sum := 10 * float64(len(x))
+
+			for _, v := range x {
+				sum += v*v - 10*math.Cos(2*math.Pi*v)
+			}
+
+			return sum
+		}),
+	}
+}
+
+// Ackley returns the d-dimensional Ackley function, nearly flat in the outer
+// region with a deep hole at the center.
+//
+// Bounds: xi in [-32.768, 32.768]
+//
+// Optimum: 0 at (0, ..., 0).
+func Ackley(d int) Function {
+	const (
+		a = 20.0
+		b = 0.2
+		c = 2 * math.Pi
+	)
+
+	return Function{
+		Name:       "Ackley",
+		Bounds:     repeat(Bound{Min: -32.768, Max: 32.768}, d),
+		Optimum:    0,
+		Minimizers: [][]float64{fill(0, d)},
+		Objective: withDimensions(d, func(x ...float64) float64 {
+			var sumSquares, sumCos float64
+
+			for _, v := range x {
+				sumSquares += v * v
+				sumCos += math.Cos(c * v)
+			}
+
+			n := float64(len(x))
+
+			return -a*math.Exp(-b*math.Sqrt(sumSquares/n)) - math.Exp(sumCos/n) + a + math.E
+		}),
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// withDimensions turns f into an Objective that checks the number of
+// parameters.
+func withDimensions(d int, f func(x ...float64) float64) Objective {
+	return func(params ...float64) (float64, error) {
+		if len(params) != d {
+			return 0, fmt.Errorf("expected %d parameters, got %d", d, len(params))
+		}
+
+		return f(params...), nil
+	}
+}
+
+// repeat returns a slice with n copies of b.
+func repeat(b Bound, n int) []Bound {
+	bounds := make([]Bound, n)
+
+	for i := range bounds {
+		bounds[i] = b
+	}
+
+	return bounds
+}
+
+// fill returns a slice with n copies of v.
+func fill(v float64, n int) []float64 {
+	values := make([]float64, n)
+
+	for i := range values {
+		values[i] = v
+	}
+
+	return values
+}
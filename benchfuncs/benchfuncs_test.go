@@ -0,0 +1,75 @@
+package benchfuncs
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctions(t *testing.T) {
+	for _, f := range []Function{Branin(), Rosenbrock(3), Rastrigin(4), Ackley(5)} {
+		t.Run(f.Name, func(t *testing.T) {
+			// The optimum is reached at every minimizer.
+			for _, minimizer := range f.Minimizers {
+				assert.Len(t, minimizer, len(f.Bounds))
+
+				value, err := f.Objective(minimizer...)
+				assert.NoError(t, err)
+				assert.InDelta(t, f.Optimum, value, 1e-5)
+			}
+
+			// Random points within bounds are never better than the optimum.
+			rng := rand.New(rand.NewSource(1))
+
+			for i := 0; i < 1000; i++ {
+				params := make([]float64, len(f.Bounds))
+
+				for j, b := range f.Bounds {
+					params[j] = b.Min + rng.Float64()*(b.Max-b.Min)
+				}
+
+				value, err := f.Objective(params...)
+				assert.NoError(t, err)
+				assert.GreaterOrEqual(t, value, f.Optimum-1e-5)
+			}
+
+			// Wrong number of parameters.
+			_, err := f.Objective(make([]float64, len(f.Bounds)+1)...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCounter(t *testing.T) {
+	counter := NewCounter(Rastrigin(2).Objective)
+
+	for i := 0; i < 10; i++ {
+		_, err := counter.Objective(0, 0)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 10, counter.Count())
+}
+
+func TestWithNoise(t *testing.T) {
+	noisy := WithNoise(Rastrigin(2).Objective, 2, rand.New(rand.NewSource(1)))
+
+	var sum, sumSquares float64
+
+	const n = 10000
+
+	for i := 0; i < n; i++ {
+		value, err := noisy(0, 0)
+		assert.NoError(t, err)
+
+		sum += value
+		sumSquares += value * value
+	}
+
+	mean := sum / n
+
+	assert.InDelta(t, 0, mean, 0.1)
+	assert.InDelta(t, 2, math.Sqrt(sumSquares/n-mean*mean), 0.1)
+}
@@ -0,0 +1,22 @@
+// Package benchfuncs provides standard synthetic test functions for
+// evaluating optimizer settings (acquisition functions, candidate counts,
+// schedules) without burning real benchmark time.
+//
+// Every function is exposed as a Function, carrying its documented bounds and
+// known optimum, so regret can be computed. Objectives are assignable to
+// ho.ObjectiveFunc[float64]:
+//
+//	branin := benchfuncs.Branin()
+//
+//	ranges := make([]ho.ParameterRange[float64], len(branin.Bounds))
+//	for i, b := range branin.Bounds {
+//	    ranges[i] = ho.ParameterRange[float64]{Min: b.Min, Max: b.Max}
+//	}
+//
+//	result := ho.OptimizeObjective(ho.DefaultConfig(), branin.Objective, ranges...)
+//
+//	regret := result.BestTime - branin.Optimum
+//
+// Helpers wrap objectives to add measurement noise (WithNoise) or to count
+// invocations (NewCounter).
+package benchfuncs
@@ -0,0 +1,84 @@
+package benchfuncs
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Counter wraps an Objective and records its invocations. It's safe for
+// concurrent use.
+type Counter struct {
+	objective Objective
+
+	count atomic.Int64
+}
+
+//////
+// Methods.
+//////
+
+// Objective evaluates the wrapped objective, counting the invocation.
+func (c *Counter) Objective(params ...float64) (float64, error) {
+	c.count.Add(1)
+
+	return c.objective(params...)
+}
+
+// Count returns the number of invocations so far.
+func (c *Counter) Count() int {
+	return int(c.count.Load())
+}
+
+//////
+// Factory.
+//////
+
+// NewCounter returns a Counter wrapping objective.
+//
+// Usage example:
+//
+//	counter := benchfuncs.NewCounter(benchfuncs.Branin().Objective)
+//
+//	ho.OptimizeObjective(config, counter.Objective, ranges...)
+//
+//	fmt.Println("Evaluations:", counter.Count())
+func NewCounter(objective Objective) *Counter {
+	return &Counter{objective: objective}
+}
+
+//////
+// Wrappers.
+//////
+
+// WithNoise wraps objective, adding Gaussian noise with the given standard
+// deviation to every value, to simulate noisy measurements. It's safe for
+// concurrent use.
+//
+// Parameters:
+// - objective: Objective to wrap
+// - stddev: Standard deviation of the noise
+// - rng: Random number generator used to draw the noise
+//
+// Returns:
+// - Objective: The noisy objective.
+func WithNoise(objective Objective, stddev float64, rng *rand.Rand) Objective {
+	var mu sync.Mutex
+
+	return func(params ...float64) (float64, error) {
+		value, err := objective(params...)
+		if err != nil {
+			return value, err
+		}
+
+		mu.Lock()
+		noise := rng.NormFloat64() * stddev
+		mu.Unlock()
+
+		return value + noise, nil
+	}
+}
@@ -0,0 +1,439 @@
+package ho
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultBenchmarkUnit is the BenchmarkImportOptions.Unit used if unset.
+	defaultBenchmarkUnit = "ns/op"
+
+	// benchmarkPrefix starts the names of benchmarks in go test output.
+	benchmarkPrefix = "Benchmark"
+)
+
+// BenchmarkImportOptions configures ImportBenchmarks.
+type BenchmarkImportOptions struct {
+	// Benchmark is the benchmark to import, e.g. "BenchmarkWrite" (the
+	// "Benchmark" prefix is optional), its sub-benchmarks included.
+	// If empty, all benchmarks are imported, as one objective.
+	Benchmark string
+
+	// Dimensions maps the keys of sub-benchmark name segments to the
+	// parameter ranges they're values of, e.g. {"buf": 0, "workers": 1}
+	// for BenchmarkWrite/buf=4096/workers=8. Indices must be 0 to
+	// len(Dimensions)-1, in the order of the ranges. Segments of other keys,
+	// or without "=", are ignored.
+	Dimensions map[string]int
+
+	// Unit is the metric imported as the value, e.g. "B/op".
+	// If empty, "ns/op" is used.
+	Unit string
+}
+
+// BenchmarkImport is the outcome of ImportBenchmarks.
+type BenchmarkImport struct {
+	// Observations holds one observation per parameter combination, sorted
+	// by parameters.
+	Observations []Observation
+
+	// Results is the number of benchmark results imported, repeated runs
+	// (e.g. -count=10) included.
+	Results int
+
+	// Skipped is the number of malformed benchmark lines, e.g. truncated, or
+	// lacking a dimension or the unit, which were skipped.
+	Skipped int
+}
+
+// benchmarkResult is a benchmark line of go test output, or a row of
+// benchstat CSV output.
+type benchmarkResult struct {
+	// name is the name of the benchmark, without the "Benchmark" prefix and
+	// the GOMAXPROCS suffix.
+	name string
+
+	// value is the value of the metric.
+	value float64
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the options, and sets the defaults.
+func (o *BenchmarkImportOptions) validate() error {
+	if len(o.Dimensions) == 0 {
+		return fmt.Errorf("%w: Dimensions: no dimension", ErrInvalidConfig)
+	}
+
+	seen := make([]bool, len(o.Dimensions))
+
+	for key, dim := range o.Dimensions {
+		if dim < 0 || dim >= len(o.Dimensions) || seen[dim] {
+			return fmt.Errorf("%w: Dimensions: %q maps to %d, expected a distinct index in [0, %d)", ErrInvalidConfig, key, dim, len(o.Dimensions))
+		}
+
+		seen[dim] = true
+	}
+
+	if o.Unit == "" {
+		o.Unit = defaultBenchmarkUnit
+	}
+
+	o.Benchmark = strings.TrimPrefix(o.Benchmark, benchmarkPrefix)
+
+	return nil
+}
+
+// params extracts the parameters of a benchmark from its name segments.
+func (o *BenchmarkImportOptions) params(name string) ([]float64, bool, error) {
+	base, segments, _ := strings.Cut(name, "/")
+
+	if o.Benchmark != "" && base != o.Benchmark {
+		return nil, false, nil
+	}
+
+	params := make([]float64, len(o.Dimensions))
+	found := make([]bool, len(o.Dimensions))
+
+	for _, segment := range strings.Split(segments, "/") {
+		key, raw, ok := strings.Cut(segment, "=")
+		if !ok {
+			continue
+		}
+
+		dim, ok := o.Dimensions[key]
+		if !ok {
+			continue
+		}
+
+		v, err := parseBenchmarkParam(raw)
+		if err != nil {
+			return nil, true, fmt.Errorf("segment %q: %w", segment, err)
+		}
+
+		params[dim], found[dim] = v, true
+	}
+
+	for key, dim := range o.Dimensions {
+		if !found[dim] {
+			return nil, true, fmt.Errorf("no %q segment", key)
+		}
+	}
+
+	return params, true, nil
+}
+
+//////
+// Helpers.
+//////
+
+// parseBenchmarkParam parses the value of a name segment: a number, a
+// duration, in nanoseconds as DurationRange values, or a bool, 0 or 1 as
+// BoolParam values.
+func parseBenchmarkParam(raw string) (float64, error) {
+	if v, err := strconv.ParseFloat(raw, 64); err == nil && !math.IsNaN(v) && !math.IsInf(v, 0) {
+		return v, nil
+	}
+
+	if d, err := time.ParseDuration(raw); err == nil {
+		return float64(d), nil
+	}
+
+	if b, err := strconv.ParseBool(raw); err == nil {
+		if b {
+			return 1, nil
+		}
+
+		return 0, nil
+	}
+
+	return 0, fmt.Errorf("%q isn't a number, a duration or a bool", raw)
+}
+
+// trimProcs removes the GOMAXPROCS suffix of a benchmark name, e.g. "-8".
+func trimProcs(name string) string {
+	i := strings.LastIndexByte(name, '-')
+
+	if i < 0 || i < strings.LastIndexByte(name, '/') {
+		return name
+	}
+
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+
+	return name[:i]
+}
+
+// parseGoTestLine parses a benchmark line of go test output, e.g.
+// "BenchmarkWrite/buf=4096-8  1000  1234 ns/op  4096 B/op".
+func parseGoTestLine(line, unit string) (benchmarkResult, error) {
+	fields := strings.Fields(line)
+
+	// The name, the iterations, then value and unit pairs.
+	if len(fields) < 4 || len(fields)%2 != 0 {
+		return benchmarkResult{}, fmt.Errorf("%d fields, expected a name, iterations, then values and units", len(fields))
+	}
+
+	if _, err := strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return benchmarkResult{}, fmt.Errorf("iterations %q isn't an integer", fields[1])
+	}
+
+	for i := 2; i < len(fields); i += 2 {
+		if fields[i+1] != unit {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return benchmarkResult{}, fmt.Errorf("%s %q isn't a finite number", unit, fields[i])
+		}
+
+		return benchmarkResult{name: trimProcs(strings.TrimPrefix(fields[0], benchmarkPrefix)), value: v}, nil
+	}
+
+	return benchmarkResult{}, fmt.Errorf("no %s value", unit)
+}
+
+// parseGoTest parses go test output. Lines other than benchmark results,
+// e.g. "goos: linux" or "PASS", are ignored.
+func parseGoTest(lines []string, unit string) ([]benchmarkResult, int) {
+	var (
+		results []benchmarkResult
+		skipped int
+	)
+
+	for _, line := range lines {
+		// "BenchmarkX" alone is the name go test -v prints before running it.
+		if !strings.HasPrefix(line, benchmarkPrefix) || len(strings.Fields(line)) < 2 {
+			continue
+		}
+
+		result, err := parseGoTestLine(line, unit)
+		if err != nil {
+			skipped++
+
+			continue
+		}
+
+		results = append(results, result)
+	}
+
+	return results, skipped
+}
+
+// parseBenchstatCSV parses benchstat CSV output (benchstat -format csv):
+// tables headed by a row of units, whose first column holds benchmark
+// names, and whose first column of the unit holds the values of the first
+// file. benchstat reports times in sec/op, converted for "ns/op".
+func parseBenchstatCSV(lines []string, unit string) ([]benchmarkResult, int) {
+	var (
+		results []benchmarkResult
+		skipped int
+
+		// column is the column of the unit in the current table, -1 if the
+		// table is of another unit.
+		column = -1
+		scale  = 1.0
+	)
+
+	for _, line := range lines {
+		if !strings.Contains(line, ",") {
+			// Blank lines separate tables, others are "key: value" headers.
+			if strings.TrimSpace(line) == "" {
+				column = -1
+			}
+
+			continue
+		}
+
+		record, err := csv.NewReader(strings.NewReader(line)).Read()
+		if err != nil {
+			skipped++
+
+			continue
+		}
+
+		// Headers start with an empty cell: file names, then units.
+		if record[0] == "" {
+			column = -1
+
+			for i, cell := range record {
+				if cell == unit || unit == defaultBenchmarkUnit && cell == "sec/op" {
+					column, scale = i, 1
+
+					if cell == "sec/op" {
+						scale = 1e9
+					}
+
+					break
+				}
+			}
+
+			continue
+		}
+
+		if column < 0 || record[0] == "geomean" {
+			continue
+		}
+
+		if column >= len(record) {
+			skipped++
+
+			continue
+		}
+
+		v, err := strconv.ParseFloat(record[column], 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			skipped++
+
+			continue
+		}
+
+		results = append(results, benchmarkResult{name: trimProcs(strings.TrimPrefix(record[0], benchmarkPrefix)), value: v * scale})
+	}
+
+	return results, skipped
+}
+
+//////
+// Exported functionalities.
+//////
+
+// ImportBenchmarks reads Go benchmark output, either go test -bench output
+// or benchstat CSV output (benchstat -format csv), and converts the results
+// to prior observations, for warm starting (see
+// OptimizationConfig.WarmStart). Parameter values are read from the
+// sub-benchmark name segments, e.g. BenchmarkWrite/buf=4096/workers=8, as
+// mapped by BenchmarkImportOptions.Dimensions.
+//
+// Parameters:
+// - r: The output, e.g. a CI artifact
+// - options: Configures the import
+//
+// Returns:
+// - *BenchmarkImport: The observations, and the number of lines skipped
+// - error: Wrapping ErrInvalidConfig if the options are invalid, or the
+// error of the reader.
+//
+// Usage example:
+//
+//	imported, err := ImportBenchmarks(f, BenchmarkImportOptions{
+//	    Benchmark:  "BenchmarkWrite",
+//	    Dimensions: map[string]int{"buf": 0, "workers": 1},
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	config := DefaultConfig()
+//	config.WarmStart = imported.Observations
+//
+//	result := Optimize(config, benchmark, bufRange, workersRange)
+//
+// Important notes:
+// - The format is detected: benchstat CSV if a line starts with a comma, as
+// its headers do
+// - Repeated results of a combination, e.g. from -count=10 or several
+// files, are merged into one observation: their median, weighted by their
+// number (see Observation.Weight)
+// - Values are read as numbers, durations (in nanoseconds, as DurationRange
+// values) or bools (0 or 1, as BoolParam values). Enum values aren't
+// supported
+// - Malformed lines, e.g. truncated, lacking a mapped segment or the unit,
+// are skipped and counted. Results of other benchmarks are ignored.
+func ImportBenchmarks(r io.Reader, options BenchmarkImportOptions) (*BenchmarkImport, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		lines []string
+		isCSV bool
+	)
+
+	scanner := bufio.NewScanner(r)
+
+	// Benchmark names can be long.
+	scanner.Buffer(nil, 1<<20)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		isCSV = isCSV || strings.HasPrefix(line, ",")
+
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	parse := parseGoTest
+	if isCSV {
+		parse = parseBenchstatCSV
+	}
+
+	results, skipped := parse(lines, options.Unit)
+
+	imported := &BenchmarkImport{Skipped: skipped}
+
+	values := map[string][]float64{}
+	params := map[string][]float64{}
+
+	for _, result := range results {
+		p, ok, err := options.params(result.name)
+
+		switch {
+		case !ok:
+			continue
+		case err != nil:
+			imported.Skipped++
+
+			continue
+		}
+
+		key := fmt.Sprint(p)
+
+		params[key] = p
+		values[key] = append(values[key], result.value)
+
+		imported.Results++
+	}
+
+	for key, p := range params {
+		imported.Observations = append(imported.Observations, Observation{
+			Params: p,
+			Value:  median(values[key]),
+			Weight: float64(len(values[key])),
+		})
+	}
+
+	sort.Slice(imported.Observations, func(i, j int) bool {
+		a, b := imported.Observations[i].Params, imported.Observations[j].Params
+
+		for d := range a {
+			if a[d] != b[d] {
+				return a[d] < b[d]
+			}
+		}
+
+		return false
+	})
+
+	return imported, nil
+}
@@ -0,0 +1,120 @@
+package ho
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// importFixture imports a benchmark output fixture.
+func importFixture(t *testing.T, name string, options BenchmarkImportOptions) *BenchmarkImport {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", "benchmarks", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	imported, err := ImportBenchmarks(f, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return imported
+}
+
+func TestImportBenchmarks(t *testing.T) {
+	dims := map[string]int{"buf": 0, "workers": 1}
+
+	t.Run("go test", func(t *testing.T) {
+		imported := importFixture(t, "gotest.txt", BenchmarkImportOptions{Benchmark: "BenchmarkWrite", Dimensions: dims})
+
+		// Repeated results are merged, lacking segments, non-numeric values
+		// and truncated lines are skipped, BenchmarkRead is ignored.
+		assert.Equal(t, []Observation{
+			{Params: []float64{512, 4}, Value: 7250.5, Weight: 1},
+			{Params: []float64{1024, 2}, Value: 9500, Weight: 1},
+			{Params: []float64{2048, 16}, Value: 11000, Weight: 1},
+			{Params: []float64{4096, 8}, Value: 13000, Weight: 3},
+		}, imported.Observations)
+		assert.Equal(t, 6, imported.Results)
+		assert.Equal(t, 4, imported.Skipped)
+	})
+
+	t.Run("unit", func(t *testing.T) {
+		imported := importFixture(t, "gotest.txt", BenchmarkImportOptions{Benchmark: "Write", Dimensions: dims, Unit: "B/op"})
+
+		if assert.Len(t, imported.Observations, 4) {
+			assert.Equal(t, Observation{Params: []float64{4096, 8}, Value: 4096, Weight: 3}, imported.Observations[3])
+		}
+	})
+
+	t.Run("benchstat", func(t *testing.T) {
+		imported := importFixture(t, "benchstat.csv", BenchmarkImportOptions{Benchmark: "BenchmarkWrite", Dimensions: dims})
+
+		if assert.Len(t, imported.Observations, 2) {
+			// sec/op, in nanoseconds, of the first file.
+			assert.Equal(t, []float64{1024, 2}, imported.Observations[0].Params)
+			assert.InDelta(t, 9500, imported.Observations[0].Value, 1e-6)
+			assert.Equal(t, []float64{4096, 8}, imported.Observations[1].Params)
+			assert.InDelta(t, 13000, imported.Observations[1].Value, 1e-6)
+		}
+
+		assert.Equal(t, 2, imported.Results)
+		assert.Equal(t, 1, imported.Skipped)
+
+		imported = importFixture(t, "benchstat.csv", BenchmarkImportOptions{Benchmark: "Write", Dimensions: dims, Unit: "B/op"})
+
+		assert.Equal(t, []Observation{
+			{Params: []float64{1024, 2}, Value: 1024, Weight: 1},
+			{Params: []float64{4096, 8}, Value: 4096, Weight: 1},
+		}, imported.Observations)
+	})
+
+	t.Run("values", func(t *testing.T) {
+		imported, err := ImportBenchmarks(strings.NewReader(
+			"BenchmarkPool/timeout=250ms/warm=true-8 10 100 ns/op\n"+
+				"BenchmarkPool/timeout=1.5e3/warm=0 10 200 ns/op\n"+
+				"BenchmarkPool/timeout=soon/warm=0 10 300 ns/op\n",
+		), BenchmarkImportOptions{Dimensions: map[string]int{"timeout": 0, "warm": 1}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Observation{
+			{Params: []float64{1500, 0}, Value: 200, Weight: 1},
+			{Params: []float64{250e6, 1}, Value: 100, Weight: 1},
+		}, imported.Observations)
+		assert.Equal(t, 1, imported.Skipped)
+	})
+
+	t.Run("warm start", func(t *testing.T) {
+		config := fastConfig()
+		config.WarmStart = importFixture(t, "gotest.txt", BenchmarkImportOptions{Benchmark: "Write", Dimensions: dims}).Observations
+
+		result := OptimizeObjective(config, func(params ...int) (float64, error) {
+			return float64(params[0] + params[1]), nil
+		},
+			ParameterRange[int]{Name: "buf", Min: 256, Max: 8192},
+			ParameterRange[int]{Name: "workers", Min: 1, Max: 16},
+		)
+
+		assert.NoError(t, result.Err)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, dimensions := range []map[string]int{
+			nil,
+			{"buf": 1},
+			{"buf": 0, "workers": 0},
+			{"buf": -1, "workers": 1},
+		} {
+			_, err := ImportBenchmarks(strings.NewReader(""), BenchmarkImportOptions{Dimensions: dimensions})
+			assert.ErrorIs(t, err, ErrInvalidConfig, dimensions)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Methods.
+//////
+
+// Scan binds the best parameters, as the benchmark received them (see
+// ParameterRange.Transform), to the fields of the struct dst points to, by
+// parameter name, see BindParams.
+//
+// Parameters:
+// - dst: Pointer to the destination struct
+//
+// Returns:
+// - error: Wrapping ErrBindParams if a parameter can't be bound
+//
+// Usage example:
+//
+//	result := Optimize(config, benchmark,
+//	    ParameterRange[int]{Name: "Workers", Min: 1, Max: 32},
+//	    ParameterRange[int]{Name: "BufferSize", Min: 1024, Max: 1048576},
+//	)
+//
+//	cfg := loadConfig()
+//	if err := result.Scan(&cfg); err != nil {
+//	    return err
+//	}
+func (r *Result[T]) Scan(dst any) error {
+	return BindParams(r.ParamNames, r.benchmarkBest(), dst)
+}
+
+//////
+// Exported functionalities.
+//////
+
+// BindParams sets the fields of the struct dst points to from parameter
+// values, by name. Fields without a matching parameter are left untouched.
+//
+// Parameters:
+// - names: Parameter names, matched against field names, exactly first, then
+// case-insensitively
+// - values: Parameter values, in the same order as names
+// - dst: Pointer to the destination struct
+//
+// Returns:
+// - error: Wrapping ErrBindParams if dst isn't a non-nil pointer to a struct,
+// names and values lengths differ, a parameter is unnamed or has no matching
+// exported field, or a value can't be converted to the field type
+//
+// Usage example:
+//
+//	var cfg struct {
+//	    Workers int
+//	    Timeout time.Duration
+//	}
+//
+//	err := BindParams([]string{"Workers", "Timeout"}, []float64{8, 5e7}, &cfg)
+//
+// Important notes:
+// - Supported field types are signed and unsigned integers, floats, bool and
+// time.Duration (nanoseconds), and pointers to them, allocated if nil
+// - Values bound to integer fields are rounded to the nearest integer, values
+// bound to bool fields are true if at least 0.5
+// - dst is left untouched if any parameter can't be bound.
+func BindParams[T constraints.Integer | constraints.Float](names []string, values []T, dst any) error {
+	if len(names) != len(values) {
+		return fmt.Errorf("%w: %d names for %d values", ErrBindParams, len(names), len(values))
+	}
+
+	v := reflect.ValueOf(dst)
+
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: destination must be a non-nil pointer to a struct, got %T", ErrBindParams, dst)
+	}
+
+	// Bind to a copy, so dst is left untouched on error.
+	target := reflect.New(v.Elem().Type()).Elem()
+
+	target.Set(v.Elem())
+
+	for i, name := range names {
+		if name == "" {
+			return fmt.Errorf("%w: parameter %d has no name", ErrBindParams, i)
+		}
+
+		field, ok := fieldByName(target, name)
+		if !ok {
+			return fmt.Errorf("%w: no exported field matches parameter %q", ErrBindParams, name)
+		}
+
+		if err := setField(field, float64(values[i])); err != nil {
+			return fmt.Errorf("%w: parameter %q: %w", ErrBindParams, name, err)
+		}
+	}
+
+	v.Elem().Set(target)
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// fieldByName returns the exported field of the struct named name, matched
+// exactly first, then case-insensitively.
+func fieldByName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+
+	if sf, ok := t.FieldByName(name); ok && sf.IsExported() && len(sf.Index) == 1 {
+		return v.Field(sf.Index[0]), true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if sf := t.Field(i); sf.IsExported() && strings.EqualFold(sf.Name, name) {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// setField sets the field from a parameter value, converting it to the field
+// type. Pointer fields are allocated if nil.
+func setField(f reflect.Value, value float64) error {
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+
+		f = f.Elem()
+	}
+
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		rounded := math.Round(value)
+
+		if math.IsNaN(rounded) || rounded < math.MinInt64 || rounded >= math.MaxInt64 || f.OverflowInt(int64(rounded)) {
+			return fmt.Errorf("value %v overflows %v", value, f.Type())
+		}
+
+		f.SetInt(int64(rounded))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		rounded := math.Round(value)
+
+		if math.IsNaN(rounded) || rounded < 0 || rounded >= math.MaxUint64 || f.OverflowUint(uint64(rounded)) {
+			return fmt.Errorf("value %v overflows %v", value, f.Type())
+		}
+
+		f.SetUint(uint64(rounded))
+	case reflect.Float32, reflect.Float64:
+		if f.OverflowFloat(value) {
+			return fmt.Errorf("value %v overflows %v", value, f.Type())
+		}
+
+		f.SetFloat(value)
+	case reflect.Bool:
+		f.SetBool(value >= 0.5)
+	default:
+		return fmt.Errorf("unsupported field type %v", f.Type())
+	}
+
+	return nil
+}
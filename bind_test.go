@@ -0,0 +1,116 @@
+package ho
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bindTarget struct {
+	Workers     int
+	Batch       uint8
+	Rate        float32
+	Timeout     time.Duration
+	Compression bool
+	Limit       *int
+	Addr        string
+	secret      int
+}
+
+func TestBindParams(t *testing.T) {
+	t.Run("partial binding and conversion", func(t *testing.T) {
+		dst := bindTarget{Addr: ":8080", Workers: 1, secret: 7}
+
+		err := BindParams(
+			[]string{"Batch", "Rate", "timeout", "Compression", "Limit"},
+			[]float64{7.6, 0.25, 5e7, 0.9, 3},
+			&dst,
+		)
+		assert.NoError(t, err)
+
+		limit := 3
+
+		assert.Equal(t, bindTarget{
+			Workers:     1,
+			Batch:       8,
+			Rate:        0.25,
+			Timeout:     50 * time.Millisecond,
+			Compression: true,
+			Limit:       &limit,
+			Addr:        ":8080",
+			secret:      7,
+		}, dst)
+	})
+
+	t.Run("integer values", func(t *testing.T) {
+		var dst bindTarget
+
+		assert.NoError(t, BindParams([]string{"Workers", "Rate"}, []int{4, 2}, &dst))
+		assert.Equal(t, 4, dst.Workers)
+		assert.Equal(t, float32(2), dst.Rate)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var nilPtr *bindTarget
+
+		tests := []struct {
+			name   string
+			names  []string
+			values []float64
+			dst    any
+		}{
+			{name: "nil", names: []string{"Workers"}, values: []float64{1}, dst: nil},
+			{name: "nil pointer", names: []string{"Workers"}, values: []float64{1}, dst: nilPtr},
+			{name: "not a pointer", names: []string{"Workers"}, values: []float64{1}, dst: bindTarget{}},
+			{name: "pointer to non-struct", names: []string{"Workers"}, values: []float64{1}, dst: new(int)},
+			{name: "length mismatch", names: []string{"Workers"}, values: []float64{1, 2}, dst: &bindTarget{}},
+			{name: "unnamed", names: []string{""}, values: []float64{1}, dst: &bindTarget{}},
+			{name: "missing field", names: []string{"Threads"}, values: []float64{1}, dst: &bindTarget{}},
+			{name: "unexported field", names: []string{"secret"}, values: []float64{1}, dst: &bindTarget{}},
+			{name: "mismatched type", names: []string{"Addr"}, values: []float64{1}, dst: &bindTarget{}},
+			{name: "overflow", names: []string{"Batch"}, values: []float64{300}, dst: &bindTarget{}},
+			{name: "negative unsigned", names: []string{"Batch"}, values: []float64{-1}, dst: &bindTarget{}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.ErrorIs(t, BindParams(tt.names, tt.values, tt.dst), ErrBindParams)
+			})
+		}
+	})
+
+	t.Run("destination untouched on error", func(t *testing.T) {
+		dst := bindTarget{Workers: 1}
+
+		err := BindParams([]string{"Workers", "Addr"}, []float64{8, 1}, &dst)
+
+		assert.ErrorIs(t, err, ErrBindParams)
+		assert.Equal(t, bindTarget{Workers: 1}, dst)
+	})
+}
+
+func TestResultScan(t *testing.T) {
+	result := OptimizeObjective(fastConfig(), func(params ...int) (float64, error) {
+		return float64(params[0] + params[1]), nil
+	},
+		ParameterRange[int]{Name: "Workers", Min: 1, Max: 32},
+		ParameterRange[int]{Name: "Batch", Min: 1, Max: 64},
+	)
+
+	assert.Equal(t, []string{"Workers", "Batch"}, result.ParamNames)
+
+	dst := bindTarget{Addr: ":8080"}
+
+	assert.NoError(t, result.Scan(&dst))
+	assert.Equal(t, result.BestParams[0], dst.Workers)
+	assert.Equal(t, uint8(result.BestParams[1]), dst.Batch)
+	assert.Equal(t, ":8080", dst.Addr)
+
+	// Unnamed ranges can't be bound.
+	unnamed := OptimizeObjective(fastConfig(), func(params ...int) (float64, error) {
+		return float64(params[0]), nil
+	}, ParameterRange[int]{Min: 1, Max: 32})
+
+	assert.ErrorIs(t, unnamed.Scan(&dst), ErrBindParams)
+}
@@ -0,0 +1,44 @@
+package ho
+
+//////
+// Const, vars, types.
+//////
+
+// BoolParam is an on/off parameter, e.g. a feature flag, see Range.
+//
+// Important notes:
+// - Values are exactly 0 (false) or 1 (true), both when sampled and when
+// proposed by the surrogate, which sees the flag as a two-level category:
+// both settings are always one unit apart
+// - Results render them as "true" or "false", see ParameterSpec.Format and
+// TrialRecord.Formatted
+// - Benchmarks receive 0 or 1, bound to bool fields by BindParams and
+// OptimizeStruct.
+type BoolParam struct {
+	// Name optionally names the parameter, see ParameterRange.Name.
+	Name string
+}
+
+//////
+// Methods.
+//////
+
+// Range returns the parameter range of the flag to pass to Optimize along
+// with other ranges.
+//
+// Returns:
+// - ParameterRange[float64]: The range, typed BoolParameter.
+//
+// Usage example:
+//
+//	result := Optimize(config, func(params ...float64) error {
+//	    return runWorkload(params[0] == 1, int(params[1]))
+//	},
+//	    BoolParam{Name: "Compression"}.Range(),
+//	    ParameterRange[float64]{Name: "Workers", Min: 1, Max: 32, Step: 1},
+//	)
+//
+//	fmt.Println(result.FormatParams(result.BestParams)) // Compression=true Workers=8
+func (p BoolParam) Range() ParameterRange[float64] {
+	return ParameterRange[float64]{Name: p.Name, Type: BoolParameter, Min: 0, Max: 1, Step: 1}
+}
@@ -0,0 +1,81 @@
+package ho
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoolParam(t *testing.T) {
+	type settings struct {
+		Compression bool
+		Workers     int
+	}
+
+	var seen []float64
+
+	config := fastConfig()
+	config.Seed = 3
+
+	// Compression halves the cost, wherever the workers are.
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		var s settings
+
+		assert.NoError(t, BindParams([]string{"Compression", "Workers"}, params, &s))
+
+		seen = append(seen, params[0])
+
+		cost := math.Pow(float64(s.Workers)-6, 2) + 10
+
+		if s.Compression {
+			cost /= 2
+		}
+
+		return cost, nil
+	},
+		BoolParam{Name: "Compression"}.Range(),
+		ParameterRange[float64]{Name: "Workers", Min: 1, Max: 16, Step: 1},
+	)
+
+	assert.NoError(t, result.Err)
+
+	// Flags are never in between.
+	for _, v := range seen {
+		assert.True(t, v == 0 || v == 1, "flag %v", v)
+	}
+
+	var best settings
+
+	assert.NoError(t, BindParams(result.ParamNames, result.BestParams, &best))
+	assert.True(t, best.Compression)
+
+	assert.True(t, strings.HasPrefix(result.FormatParams(result.BestParams), "Compression=true "))
+
+	for _, record := range result.Records() {
+		want := "false"
+
+		if record.Params["Compression"] == 1 {
+			want = "true"
+		}
+
+		assert.Equal(t, map[string]string{"Compression": want}, record.Formatted)
+	}
+
+	assert.Equal(t, "false", ParameterSpec{Type: BoolParameter}.Format(0))
+
+	// Configuration files declare them without bounds.
+	_, space, err := LoadConfig(strings.NewReader("parameters: [{name: Compression, type: bool}]"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ParameterRange[float64]{BoolParam{Name: "Compression"}.Range()}, Ranges[float64](space))
+
+	// Bool ranges can't be widened.
+	hyper := BoolParam{}.Range()
+	hyper.Max = 2
+
+	result = Optimize(fastConfig(), func(params ...float64) error { return nil }, hyper)
+
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+}
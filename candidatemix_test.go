@@ -0,0 +1,61 @@
+package ho
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho/benchfuncs"
+)
+
+// meanBestValue runs the optimization runs times on f and returns the mean
+// best value found.
+func meanBestValue(t *testing.T, config OptimizationConfig, f benchfuncs.Function, runs int) float64 {
+	t.Helper()
+
+	var sum float64
+
+	for seed := 0; seed < runs; seed++ {
+		sum += newSyntheticOptimizer(config, f, f.Objective, int64(seed)).run().BestTime
+	}
+
+	return sum / float64(runs)
+}
+
+func TestCandidateMixConvergence(t *testing.T) {
+	for _, f := range []benchfuncs.Function{benchfuncs.Branin(), benchfuncs.Rosenbrock(2)} {
+		config := fastConfig()
+		config.InitialSamples = 5
+		config.Iterations = 20
+		config.NumCandidates = 20
+
+		uniform := meanBestValue(t, config, f, 20)
+
+		config.CandidateMix = CandidateMix{Incumbent: 0.3, TopK: 0.2}
+
+		mixed := meanBestValue(t, config, f, 20)
+
+		// Candidates around good points converge faster at equal NumCandidates.
+		assert.Less(t, mixed-f.Optimum, uniform-f.Optimum, f.Name)
+	}
+}
+
+func TestPerturbParams(t *testing.T) {
+	o := newOptimizer(
+		context.Background(),
+		fastConfig(),
+		nil,
+		ParameterRange[int]{Min: 1, Max: 10},
+		ParameterRange[int]{Min: -5, Max: 5},
+	)
+
+	// Perturbed integer dims are rounded and clipped.
+	for i := 0; i < 1000; i++ {
+		params := o.perturbParams([]int{10, -5}, 0.5)
+
+		assert.GreaterOrEqual(t, params[0], 1)
+		assert.LessOrEqual(t, params[0], 10)
+		assert.GreaterOrEqual(t, params[1], -5)
+		assert.LessOrEqual(t, params[1], 5)
+	}
+}
@@ -0,0 +1,105 @@
+package ho
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowModel is a Gaussian process whose predictions take a millisecond
+// each, like a big model. It isn't a BatchPredictor.
+type slowModel struct {
+	SurrogateModel
+}
+
+func (m slowModel) Predict(x []float64) (float64, float64) {
+	time.Sleep(time.Millisecond)
+
+	return m.SurrogateModel.Predict(x)
+}
+
+func (m slowModel) Clone() SurrogateModel {
+	return slowModel{m.SurrogateModel.Clone()}
+}
+
+// selectionRun runs a short optimization with a slow model, and returns the
+// progress updates of the optimization phase.
+func selectionRun(t *testing.T, limit time.Duration) ([]ProgressUpdate, *Result[float64]) {
+	t.Helper()
+
+	progress := make(chan ProgressUpdate, 100)
+
+	config := fastConfig()
+	config.Seed = 1
+	config.Iterations = 3
+	config.NumCandidates = 500
+	config.MaxCandidateSelectionTime = limit
+	config.ProgressChan = progress
+	config.Surrogate = func() SurrogateModel {
+		return slowModel{NewGaussianProcess(GaussianProcessOptions{})}
+	}
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return (params[0] - 0.3) * (params[0] - 0.3), nil
+	}, ParameterRange[float64]{Min: 0, Max: 1})
+
+	close(progress)
+
+	var updates []ProgressUpdate
+
+	for update := range progress {
+		if update.Phase == PhaseOptimization {
+			updates = append(updates, update)
+		} else {
+			assert.Zero(t, update.CandidatesScored, update.Phase)
+		}
+	}
+
+	return updates, result
+}
+
+func TestMaxCandidateSelectionTime(t *testing.T) {
+	t.Run("capped", func(t *testing.T) {
+		start := time.Now()
+
+		updates, result := selectionRun(t, 20*time.Millisecond)
+
+		if !assert.NoError(t, result.Err) || !assert.Len(t, updates, 3) {
+			return
+		}
+
+		// Scoring every candidate would take at least 1.5s.
+		assert.Less(t, time.Since(start), time.Second)
+
+		for _, update := range updates {
+			assert.GreaterOrEqual(t, update.CandidatesScored, selectionChunk)
+			assert.Less(t, update.CandidatesScored, 100)
+		}
+
+		// A candidate is still chosen.
+		assert.Len(t, result.Trials, 6)
+
+		for _, trial := range result.Trials {
+			assert.Equal(t, TrialCompleted, trial.Status)
+		}
+	})
+
+	t.Run("uncapped", func(t *testing.T) {
+		updates, result := selectionRun(t, 0)
+
+		if assert.NoError(t, result.Err) && assert.Len(t, updates, 3) {
+			for _, update := range updates {
+				assert.Equal(t, 500, update.CandidatesScored)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		config := fastConfig()
+		config.MaxCandidateSelectionTime = -time.Second
+
+		result := Optimize(config, func(...int) error { return nil }, ParameterRange[int]{Min: 0, Max: 10})
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+}
@@ -0,0 +1,916 @@
+package ho
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// checkpointVersion is the version of the checkpoint file format, bumped
+// whenever a change breaks reading older files. Version 1 files are still
+// read: their observations and failures hold parameter values, instead of
+// the points the model sees, see modelPoint.
+const checkpointVersion = 2
+
+// Checkpoint configures periodic checkpoints: the full state of the run is
+// written as JSON after every few ended trials, so an interrupted run, e.g.
+// on a reclaimed spot instance, doesn't lose its evaluations.
+//
+// Important notes:
+// - Checkpoints are written on a separate goroutine. Evaluations only wait
+// for the state to be copied, and if a write is still in progress when the
+// next checkpoint is due, only the latest state is written
+// - A last checkpoint is written when the run terminates, before the result
+// is returned
+// - Write failures are recorded in Result.Warnings, and don't stop the run
+// - The ask/tell Optimizer checkpoints too, its outstanding suggestions
+// included, see ResumeOptimizerFromCheckpoint.
+type Checkpoint struct {
+	// Path is the checkpoint file. Each checkpoint is written to a temporary
+	// file in the same directory, then renamed over Path, so Path always
+	// holds a complete checkpoint.
+	Path string
+
+	// Writer, if set, is called for each checkpoint instead of writing to
+	// Path, and the checkpoint is written at offset 0 of the returned
+	// destination, which is closed afterwards if it's an io.Closer. Writes
+	// are only atomic if the destination makes them so, e.g. an object
+	// storage upload only committed on Close.
+	Writer func() (io.WriterAt, error)
+
+	// Every is the number of ended trials between checkpoints, skipped and
+	// failed ones included.
+	// If not positive, a checkpoint is written after every trial.
+	Every int
+}
+
+// checkpointFile is the content of a checkpoint file.
+type checkpointFile struct {
+	// Version is the version of the file format, see checkpointVersion.
+	Version int `json:"version"`
+
+	// SavedAt is when the state was copied.
+	SavedAt time.Time `json:"savedAt"`
+
+	// Fingerprint identifies the search space and the settings shaping the
+	// suggestions, see fingerprint.
+	Fingerprint string `json:"fingerprint"`
+
+	// Parameters describes the search space.
+	Parameters []ParameterSpec `json:"parameters"`
+
+	// Seed is the seed of the random number generator.
+	Seed int64 `json:"seed"`
+
+	// Draws is the number of values drawn from the random number generator.
+	Draws uint64 `json:"draws"`
+
+	// LastTrialID is the ID of the last trial started.
+	LastTrialID int `json:"lastTrialId"`
+
+	// InitialSamples is the number of initial sampling slots ended, i.e.
+	// with a trial that wasn't canceled.
+	InitialSamples int `json:"initialSamples"`
+
+	// Iterations is the number of optimization slots ended.
+	Iterations int `json:"iterations"`
+
+	// BestParams holds the best parameters found so far, see
+	// checkpointValues.
+	BestParams []json.Number `json:"bestParams"`
+
+	// BestValue is the best value found so far, math.MaxFloat64 if no
+	// trial completed.
+	BestValue checkpointFloat `json:"bestValue"`
+
+	// Trials holds every ended trial, in completion order.
+	Trials []checkpointTrial `json:"trials"`
+
+	// Observations holds the observations fed to the model, warm start
+	// included, in the order they were fed, at the points the model sees,
+	// see modelPoint.
+	Observations []checkpointObservation `json:"observations"`
+
+	// Failures holds the points of the failed trials kept out of the model,
+	// see FailureSubstitute.
+	Failures [][]float64 `json:"failures,omitempty"`
+
+	// Pending holds the suggestions of an ask/tell Optimizer whose result
+	// wasn't told yet, by trial ID.
+	Pending []checkpointLease `json:"pending,omitempty"`
+
+	// Abandoned holds the suggestions of an ask/tell Optimizer whose lease
+	// expired before their result was told, by trial ID.
+	Abandoned []checkpointLease `json:"abandoned,omitempty"`
+
+	// StudyVersion is the study version of an ask/tell Optimizer, see
+	// Optimizer.StudyVersion, zero otherwise.
+	StudyVersion uint64 `json:"studyVersion,omitempty"`
+
+	// Stats is the state of the run, for monitoring: it isn't restored.
+	Stats *RunStats `json:"stats,omitempty"`
+}
+
+// checkpointTrial is a trial, as stored in a checkpoint file.
+type checkpointTrial struct {
+	ID              int             `json:"id"`
+	Phase           string          `json:"phase"`
+	Iteration       int             `json:"iteration"`
+	Retry           bool            `json:"retry,omitempty"`
+	Seed            int64           `json:"seed,omitempty"`
+	RNGPosition     uint64          `json:"rngPosition,omitempty"`
+	Status          TrialStatus     `json:"status"`
+	Cached          bool            `json:"cached,omitempty"`
+	UnderLoad       bool            `json:"underLoad,omitempty"`
+	Surprising      bool            `json:"surprising,omitempty"`
+	Measurements    []float64       `json:"measurements,omitempty"`
+	SeedValues      []float64       `json:"seedValues,omitempty"`
+	Weight          float64         `json:"weight,omitempty"`
+	Params          []json.Number   `json:"params"`
+	BenchmarkParams []json.Number   `json:"benchmarkParams,omitempty"`
+	Value           checkpointFloat `json:"value"`
+	RawValue        checkpointFloat `json:"rawValue,omitempty"`
+	MeasuredValue   *float64        `json:"measuredValue,omitempty"`
+	Regret          checkpointFloat `json:"regret,omitempty"`
+	StartedAt       time.Time       `json:"startedAt"`
+	Duration        time.Duration   `json:"durationNs"`
+	RateLimitWait   time.Duration   `json:"rateLimitWaitNs,omitempty"`
+	DriftCorrection float64         `json:"driftCorrection,omitempty"`
+	SafetyMetric    *float64        `json:"safetyMetric,omitempty"`
+	SafetyViolation bool            `json:"safetyViolation,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	Stop            bool            `json:"stop,omitempty"`
+}
+
+// checkpointLease is a suggestion handed out by an ask/tell Optimizer, as
+// stored in a checkpoint file.
+type checkpointLease struct {
+	ID          int           `json:"id"`
+	Phase       string        `json:"phase"`
+	Iteration   int           `json:"iteration"`
+	Seed        int64         `json:"seed,omitempty"`
+	RNGPosition uint64        `json:"rngPosition,omitempty"`
+	Params      []json.Number `json:"params"`
+	AskedAt     time.Time     `json:"askedAt"`
+	ExpiresAt   *time.Time    `json:"expiresAt,omitempty"`
+}
+
+// checkpointObservation is an observation fed to the model, as stored in a
+// checkpoint file.
+type checkpointObservation struct {
+	Params []float64       `json:"params"`
+	Value  checkpointFloat `json:"value"`
+	Weight float64         `json:"weight,omitempty"`
+}
+
+// checkpointFloat is a float64 encoding NaN and infinities as JSON strings,
+// which encoding/json rejects as numbers, e.g. a NaN returned by an
+// objective.
+type checkpointFloat float64
+
+// restoredError is the error of a restored trial: its message, wrapping
+// ErrStopOptimization if it requested a stop.
+type restoredError struct {
+	message string
+	target  error
+}
+
+// checkpointer writes checkpoints in the background.
+type checkpointer struct {
+	// config configures the checkpoints.
+	config Checkpoint
+
+	// pending holds the latest state waiting to be written, if any.
+	pending chan *checkpointFile
+
+	// wg tracks the writing goroutine.
+	wg sync.WaitGroup
+
+	// warnf records write failures.
+	warnf func(format string, args ...any)
+}
+
+// countingSource is a rand.Source64 counting its draws, so its state can be
+// saved as its seed and number of draws.
+type countingSource struct {
+	// source is the actual source.
+	source rand.Source64
+
+	// seed is the seed of the source.
+	seed int64
+
+	// draws is the number of values drawn since seeding.
+	draws uint64
+}
+
+//////
+// Methods.
+//////
+
+// Error implements error.
+func (e *restoredError) Error() string {
+	return e.message
+}
+
+// Unwrap returns ErrStopOptimization if the trial requested a stop, nil
+// otherwise.
+func (e *restoredError) Unwrap() error {
+	return e.target
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f checkpointFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return json.Marshal(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *checkpointFloat) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+
+		*f = checkpointFloat(v)
+
+		return nil
+	}
+
+	var v float64
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*f = checkpointFloat(v)
+
+	return nil
+}
+
+// Int63 implements rand.Source.
+func (s *countingSource) Int63() int64 {
+	s.draws++
+
+	return s.source.Int63()
+}
+
+// Uint64 implements rand.Source64.
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+
+	return s.source.Uint64()
+}
+
+// Seed implements rand.Source.
+func (s *countingSource) Seed(seed int64) {
+	s.source.Seed(seed)
+
+	s.seed = seed
+	s.draws = 0
+}
+
+// save queues a state for writing, replacing the one waiting, if any. Calls
+// must be serialized.
+func (c *checkpointer) save(state *checkpointFile) {
+	select {
+	case <-c.pending:
+	default:
+	}
+
+	c.pending <- state
+}
+
+// close writes the pending state, if any, and stops the writing goroutine.
+func (c *checkpointer) close() {
+	close(c.pending)
+
+	c.wg.Wait()
+}
+
+// loop writes queued states until the queue is closed.
+func (c *checkpointer) loop() {
+	defer c.wg.Done()
+
+	for state := range c.pending {
+		if err := c.write(state); err != nil {
+			c.warnf("checkpoint of %d trials not written: %v", len(state.Trials), err)
+		}
+	}
+}
+
+// write encodes and writes a state to the configured destination.
+func (c *checkpointer) write(state *checkpointFile) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if c.config.Writer == nil {
+		return writeFileAtomic(c.config.Path, data)
+	}
+
+	w, err := c.config.Writer()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.WriteAt(data, 0)
+
+	if closer, ok := w.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// validate checks the checkpoint configuration.
+func (c *Checkpoint) validate() error {
+	switch {
+	case c.Path == "" && c.Writer == nil:
+		return fmt.Errorf("%w: Checkpoint.Path or Checkpoint.Writer is required", ErrInvalidConfig)
+	case c.Path != "" && c.Writer != nil:
+		return fmt.Errorf("%w: Checkpoint.Path and Checkpoint.Writer are mutually exclusive", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// checkpoint queues a checkpoint of the run state if Checkpoint.Every trials
+// ended since the last one, or if force is set. The caller must hold mu.
+func (o *optimizer[T]) checkpoint(force bool) {
+	if o.checkpoints == nil {
+		return
+	}
+
+	every := max(o.checkpoints.config.Every, 1)
+
+	if !force && len(o.trials)-o.checkpointed < every {
+		return
+	}
+
+	o.checkpointed = len(o.trials)
+
+	o.checkpoints.save(o.checkpointState())
+}
+
+// checkpointState copies the run state. The caller must hold mu.
+func (o *optimizer[T]) checkpointState() *checkpointFile {
+	o.rngMu.Lock()
+	seed, draws := o.source.seed, o.source.draws
+	o.rngMu.Unlock()
+
+	state := &checkpointFile{
+		Version:      checkpointVersion,
+		SavedAt:      time.Now(),
+		Fingerprint:  fingerprint(o.studyMeta()),
+		Parameters:   o.parameterSpecs(),
+		Seed:         seed,
+		Draws:        draws,
+		LastTrialID:  o.lastTrialID,
+		BestParams:   checkpointValues(o.bestParams),
+		BestValue:    checkpointFloat(o.bestTime),
+		Trials:       make([]checkpointTrial, len(o.trials)),
+		Observations: make([]checkpointObservation, len(o.observations)),
+		Failures:     o.failures,
+	}
+
+	stats := o.stats()
+
+	state.Stats = &stats
+
+	for i, trial := range o.trials {
+		state.Trials[i] = newCheckpointTrial(trial)
+	}
+
+	for slot := range endedSlots(o.trials) {
+		switch slot.Phase {
+		case PhaseInitialSampling:
+			state.InitialSamples++
+		case PhaseOptimization, PhaseIncumbentCheck:
+			state.Iterations++
+		}
+	}
+
+	for i, observation := range o.observations {
+		state.Observations[i] = checkpointObservation{
+			Params: observation.Params,
+			Value:  checkpointFloat(observation.Value),
+			Weight: observation.Weight,
+		}
+	}
+
+	if o.checkpointLeases != nil {
+		o.checkpointLeases(state)
+	}
+
+	return state
+}
+
+// resume runs the optimization from the checkpoint at path.
+//
+// Parameters:
+// - path: The checkpoint file
+//
+// Returns:
+// - *Result[T]: The outcome of the run, trials before the checkpoint
+// included.
+func (o *optimizer[T]) resume(path string) *Result[T] {
+	state, err := loadCheckpoint(path)
+	if err != nil {
+		o.invalidErr = fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+
+		return o.result()
+	}
+
+	o.resumed = state
+
+	return o.run()
+}
+
+// checkResumable checks that the checkpoint to resume from comes from a run
+// of the same search space.
+//
+// Returns:
+// - error: Wraps ErrInvalidConfig if the search spaces differ, nil
+// otherwise.
+func (o *optimizer[T]) checkResumable() error {
+	specs := o.parameterSpecs()
+
+	if len(o.resumed.Parameters) != len(specs) {
+		return fmt.Errorf("%w: the checkpoint has %d parameters, expected %d", ErrInvalidConfig, len(o.resumed.Parameters), len(specs))
+	}
+
+	for i, spec := range specs {
+		if !o.resumed.Parameters[i].equal(spec) {
+			return fmt.Errorf("%w: checkpoint parameter %d is %+v, expected %+v", ErrInvalidConfig, i, o.resumed.Parameters[i], spec)
+		}
+	}
+
+	return nil
+}
+
+// restore restores the state of the checkpoint to resume from: observations
+// are fed to the model, trials recorded, and the random number generator
+// and the best result restored. Observations the model rejects are skipped,
+// with a warning.
+func (o *optimizer[T]) restore() {
+	state := o.resumed
+
+	if state.Fingerprint != fingerprint(o.studyMeta()) {
+		o.warnf("the checkpoint was written with different settings, the run resumes with the current ones")
+	}
+
+	// Replaying the draws brings the generator back to where it was.
+	o.rngMu.Lock()
+
+	o.source.Seed(state.Seed)
+
+	for i := uint64(0); i < state.Draws; i++ {
+		o.source.Uint64()
+	}
+
+	o.rngMu.Unlock()
+
+	// Points of version 1 files are parameter values.
+	point := func(params []float64) []float64 {
+		if state.Version < 2 {
+			return modelCoordinates(o.hypers, params)
+		}
+
+		return params
+	}
+
+	for _, params := range state.Failures {
+		params = point(params)
+
+		if o.substitutesFailures() {
+			o.failures = append(o.failures, params)
+
+			continue
+		}
+
+		// The checkpoint was written keeping failures out of the model.
+		if err := o.model.Update(params, math.MaxFloat64/2); err == nil {
+			o.observations = append(o.observations, Observation{Params: params, Value: math.MaxFloat64 / 2})
+		}
+	}
+
+	for i, observation := range state.Observations {
+		params, value := point(observation.Params), float64(observation.Value)
+
+		// Penalties, e.g. of stored studies or of runs feeding them to the
+		// model, are failures.
+		if value >= math.MaxFloat64/2 && o.substitutesFailures() {
+			o.failures = append(o.failures, params)
+
+			continue
+		}
+
+		if err := updateWeighted(o.model, params, value, observation.Weight); err != nil {
+			o.warnf("checkpoint observation %d not fed to the model: %v", i, err)
+
+			continue
+		}
+
+		o.observations = append(o.observations, Observation{Params: params, Value: value, Weight: observation.Weight})
+	}
+
+	for _, record := range state.Trials {
+		trial := restoreTrial(o.hypers, record)
+
+		o.trials = append(o.trials, trial)
+
+		if o.cache != nil && (trial.Status == TrialCompleted || trial.Status == TrialFailed) {
+			o.cache[cacheKey(o.hypers, trial.Params)] = trial
+		}
+
+		o.restoreSafety(trial)
+	}
+
+	copy(o.bestParams, restoreValues(o.hypers, state.BestParams))
+
+	o.bestTime = float64(state.BestValue)
+	o.lastTrialID = state.LastTrialID
+	o.checkpointed = len(o.trials)
+	o.resumedSlots = endedSlots(o.trials)
+}
+
+//////
+// Helpers.
+//////
+
+// endedSlots returns the trial slots that ended, i.e. with a trial that
+// wasn't canceled, keyed by phase and iteration.
+func endedSlots[T constraints.Integer | constraints.Float](trials []Trial[T]) map[TrialInfo]bool {
+	slots := make(map[TrialInfo]bool)
+
+	for _, trial := range trials {
+		if trial.Status != TrialCanceled {
+			slots[TrialInfo{Phase: trial.Phase, Iteration: trial.Iteration}] = true
+		}
+	}
+
+	return slots
+}
+
+// loadCheckpoint reads the checkpoint file at path, see readCheckpoint.
+func loadCheckpoint(path string) (*checkpointFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return readCheckpoint(f)
+}
+
+// fingerprint identifies the search space and the settings shaping the
+// suggestions of a run, so a checkpoint can be matched with the run it comes
+// from.
+func fingerprint(meta StudyMeta) string {
+	meta.StartedAt = time.Time{}
+	meta.Study = ""
+	meta.Tags = nil
+
+	// Only plain values, encoding can't fail.
+	data, _ := json.Marshal(meta)
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// newCheckpointTrial converts a trial to its checkpoint representation.
+func newCheckpointTrial[T constraints.Integer | constraints.Float](trial Trial[T]) checkpointTrial {
+	record := checkpointTrial{
+		ID:              trial.TrialID,
+		Phase:           trial.Phase,
+		Iteration:       trial.Iteration,
+		Retry:           trial.Retry,
+		Seed:            trial.Seed,
+		RNGPosition:     trial.RNGPosition,
+		Status:          trial.Status,
+		Cached:          trial.Cached,
+		UnderLoad:       trial.UnderLoad,
+		Surprising:      trial.Surprising,
+		Measurements:    trial.Measurements,
+		SeedValues:      trial.SeedValues,
+		Weight:          trial.Weight,
+		Params:          checkpointValues(trial.Params),
+		Value:           checkpointFloat(trial.ExecutionTime),
+		RawValue:        checkpointFloat(trial.RawValue),
+		MeasuredValue:   trial.MeasuredValue,
+		Regret:          checkpointFloat(trial.Regret),
+		StartedAt:       trial.StartedAt,
+		Duration:        trial.Duration,
+		RateLimitWait:   trial.RateLimitWait,
+		DriftCorrection: trial.DriftCorrection,
+		SafetyMetric:    trial.SafetyMetric,
+		SafetyViolation: trial.SafetyViolation,
+	}
+
+	if trial.BenchmarkParams != nil {
+		record.BenchmarkParams = checkpointValues(trial.BenchmarkParams)
+	}
+
+	if trial.Err != nil {
+		record.Error = trial.Err.Error()
+		record.Stop = errors.Is(trial.Err, ErrStopOptimization)
+	}
+
+	return record
+}
+
+// restoreTrial converts a checkpoint trial back to a trial. Parameters are
+// restored exactly, see restoreValues, and the values the benchmark received
+// are derived from them again, see ParameterRange.Transform. Errors only keep
+// their message, and whether they requested a stop.
+func restoreTrial[T constraints.Integer | constraints.Float](hypers []ParameterRange[T], record checkpointTrial) Trial[T] {
+	trial := Trial[T]{
+		TrialInfo: TrialInfo{
+			TrialID:     record.ID,
+			Phase:       record.Phase,
+			Iteration:   record.Iteration,
+			Retry:       record.Retry,
+			Seed:        record.Seed,
+			RNGPosition: record.RNGPosition,
+		},
+		Params:          restoreValues(hypers, record.Params),
+		ExecutionTime:   float64(record.Value),
+		RawValue:        float64(record.RawValue),
+		MeasuredValue:   record.MeasuredValue,
+		Duration:        record.Duration,
+		RateLimitWait:   record.RateLimitWait,
+		DriftCorrection: record.DriftCorrection,
+		StartedAt:       record.StartedAt,
+		Regret:          float64(record.Regret),
+		Status:          record.Status,
+		Cached:          record.Cached,
+		UnderLoad:       record.UnderLoad,
+		Surprising:      record.Surprising,
+		Measurements:    record.Measurements,
+		SeedValues:      record.SeedValues,
+		Weight:          record.Weight,
+		SafetyMetric:    record.SafetyMetric,
+		SafetyViolation: record.SafetyViolation,
+	}
+
+	if record.BenchmarkParams != nil {
+		if params, ok := benchmarkParams(hypers, trial.Params); ok {
+			trial.BenchmarkParams = params
+		}
+	}
+
+	switch {
+	case record.Stop:
+		trial.Err = &restoredError{message: record.Error, target: ErrStopOptimization}
+	case record.Error != "":
+		trial.Err = &restoredError{message: record.Error}
+	}
+
+	return trial
+}
+
+// newCheckpointLease converts a suggestion to its checkpoint representation.
+func newCheckpointLease[T constraints.Integer | constraints.Float](p pendingSuggestion[T]) checkpointLease {
+	lease := checkpointLease{
+		ID:          p.suggestion.TrialID,
+		Phase:       p.suggestion.Phase,
+		Iteration:   p.suggestion.Iteration,
+		Seed:        p.suggestion.Seed,
+		RNGPosition: p.suggestion.RNGPosition,
+		Params:      checkpointValues(p.suggestion.Params),
+		AskedAt:     p.askedAt,
+	}
+
+	if !p.suggestion.ExpiresAt.IsZero() {
+		lease.ExpiresAt = &p.suggestion.ExpiresAt
+	}
+
+	return lease
+}
+
+// restoreLease converts a checkpoint lease back to a suggestion, the values
+// the benchmark receives derived from its parameters again, as restoreTrial
+// does.
+func restoreLease[T constraints.Integer | constraints.Float](hypers []ParameterRange[T], lease checkpointLease) pendingSuggestion[T] {
+	p := pendingSuggestion[T]{
+		suggestion: Suggestion[T]{
+			TrialInfo: TrialInfo{
+				TrialID:     lease.ID,
+				Phase:       lease.Phase,
+				Iteration:   lease.Iteration,
+				Seed:        lease.Seed,
+				RNGPosition: lease.RNGPosition,
+			},
+			Params: restoreValues(hypers, lease.Params),
+		},
+		askedAt: lease.AskedAt,
+	}
+
+	if params, ok := benchmarkParams(hypers, p.suggestion.Params); ok {
+		p.suggestion.BenchmarkParams = params
+	}
+
+	if lease.ExpiresAt != nil {
+		p.suggestion.ExpiresAt = *lease.ExpiresAt
+	}
+
+	return p
+}
+
+// checkpointValues converts parameters to their checkpoint representation:
+// JSON numbers, integers written in full, so values beyond 2^53, e.g. near
+// math.MaxUint64, are restored exactly, which they aren't as float64.
+func checkpointValues[T constraints.Integer | constraints.Float](params []T) []json.Number {
+	values := make([]json.Number, len(params))
+
+	for i, v := range params {
+		switch half := 0.5; {
+		case T(half) != 0:
+			values[i] = json.Number(strconv.FormatFloat(float64(v), 'g', -1, 64))
+		case v < 0:
+			values[i] = json.Number(strconv.FormatInt(int64(v), 10))
+		default:
+			values[i] = json.Number(strconv.FormatUint(uint64(v), 10))
+		}
+	}
+
+	return values
+}
+
+// restoreValues converts checkpoint values back to values of the ranges.
+// Integers are parsed exactly, then clamped to the range and snapped to its
+// Step. Other values, e.g. integers of version 1 files written as float64,
+// go through rangeValue.
+//
+// Parameters:
+// - hypers: The ranges
+// - values: The checkpoint values, in range order
+//
+// Returns:
+// - []T: The values, one per checkpoint value. Values beyond the ranges are
+// left zero.
+func restoreValues[T constraints.Integer | constraints.Float](hypers []ParameterRange[T], values []json.Number) []T {
+	params := make([]T, len(values))
+
+	for i, value := range values {
+		if i >= len(hypers) {
+			continue
+		}
+
+		hyper := hypers[i]
+
+		if v, ok := parseInteger[T](value.String()); ok {
+			params[i] = snapToStep(hyper, min(max(v, hyper.Min), hyper.Max))
+
+			continue
+		}
+
+		f, _ := value.Float64()
+
+		params[i] = rangeValue(hyper, f)
+	}
+
+	return params
+}
+
+// parseInteger parses s as a value of the integer type T.
+//
+// Returns:
+// - T: The value
+// - bool: Whether s is an integer T holds exactly, never for float types.
+func parseInteger[T constraints.Integer | constraints.Float](s string) (T, bool) {
+	if half := 0.5; T(half) != 0 {
+		return 0, false
+	}
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		v := T(i)
+
+		return v, int64(v) == i && (v < 0) == (i < 0)
+	}
+
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		v := T(u)
+
+		return v, uint64(v) == u && v >= 0
+	}
+
+	return 0, false
+}
+
+// readCheckpoint decodes a checkpoint file.
+//
+// Parameters:
+// - r: The checkpoint file content
+//
+// Returns:
+// - *checkpointFile: The checkpoint
+// - error: If the content isn't a checkpoint, or has an unsupported version.
+func readCheckpoint(r io.Reader) (*checkpointFile, error) {
+	var state checkpointFile
+
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return nil, fmt.Errorf("decoding checkpoint: %w", err)
+	}
+
+	if state.Version < 1 || state.Version > checkpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d, expected at most %d", state.Version, checkpointVersion)
+	}
+
+	return &state, nil
+}
+
+// writeFileAtomic writes data to a temporary file in the directory of path,
+// then renames it over path, so path is never left partially written.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	// Nothing to remove once renamed.
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	// The content must reach the disk before the rename does.
+	if err := f.Sync(); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+//////
+// Factory.
+//////
+
+// newCheckpointer creates a checkpointer, and starts its writing goroutine.
+//
+// Parameters:
+// - config: Configures the checkpoints
+// - warnf: Records write failures
+//
+// Returns:
+// - *checkpointer: The checkpointer, to close once the run terminates.
+func newCheckpointer(config Checkpoint, warnf func(format string, args ...any)) *checkpointer {
+	c := &checkpointer{
+		config:  config,
+		pending: make(chan *checkpointFile, 1),
+		warnf:   warnf,
+	}
+
+	c.wg.Add(1)
+
+	go c.loop()
+
+	return c
+}
+
+// newCountingSource creates a countingSource.
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{
+		// The standard source implements rand.Source64.
+		source: rand.NewSource(seed).(rand.Source64),
+		seed:   seed,
+	}
+}
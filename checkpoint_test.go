@@ -0,0 +1,402 @@
+package ho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryFile is an in-memory io.WriterAt counting its closes.
+type memoryFile struct {
+	data   []byte
+	closed bool
+}
+
+func (f *memoryFile) WriteAt(p []byte, off int64) (int, error) {
+	f.data = append(f.data[:off], p...)
+
+	return len(p), nil
+}
+
+func (f *memoryFile) Close() error {
+	f.closed = true
+
+	return nil
+}
+
+// readCheckpointFile reads the checkpoint at path, nil if there's none yet.
+func readCheckpointFile(t *testing.T, path string) *checkpointFile {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	defer f.Close()
+
+	state, err := readCheckpoint(f)
+
+	assert.NoError(t, err)
+
+	return state
+}
+
+func TestCheckpoint(t *testing.T) {
+	t.Run("interrupted run", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "run.json")
+
+		config := fastConfig()
+		config.Seed = 7
+		config.Checkpoint = &Checkpoint{Path: path}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var (
+			mu        sync.Mutex
+			completed [][]float64
+		)
+
+		// The 6th evaluation hangs until the run is canceled, as if the host
+		// died in the middle of it.
+		objective := func(ctx context.Context, params ...float64) (float64, error) {
+			mu.Lock()
+
+			if len(completed) == 5 {
+				mu.Unlock()
+
+				<-ctx.Done()
+
+				return 0, ctx.Err()
+			}
+
+			completed = append(completed, params)
+
+			mu.Unlock()
+
+			return math.Pow(params[0]-3, 2), nil
+		}
+
+		done := make(chan *Result[float64])
+
+		go func() {
+			done <- OptimizeObjectiveWithContext(ctx, config, objective, ParameterRange[float64]{Min: 0, Max: 10})
+		}()
+
+		var state *checkpointFile
+
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			if state = readCheckpointFile(t, path); state != nil && len(state.Trials) == 5 {
+				break
+			}
+		}
+
+		if !assert.NotNil(t, state) || !assert.Len(t, state.Trials, 5) {
+			return
+		}
+
+		mu.Lock()
+
+		for i, trial := range state.Trials {
+			assert.Equal(t, i+1, trial.ID)
+			assert.Equal(t, TrialCompleted, trial.Status)
+			assert.Equal(t, checkpointValues(completed[i]), trial.Params)
+			assert.Equal(t, math.Pow(completed[i][0]-3, 2), float64(trial.Value))
+		}
+
+		mu.Unlock()
+
+		assert.Equal(t, 3, state.InitialSamples)
+		assert.Equal(t, 2, state.Iterations)
+		assert.Equal(t, 5, state.LastTrialID)
+		assert.Equal(t, int64(7), state.Seed)
+		assert.Positive(t, state.Draws)
+		assert.Len(t, state.Observations, 5)
+		assert.Len(t, state.Parameters, 1)
+		assert.NotEmpty(t, state.Fingerprint)
+
+		if assert.NotNil(t, state.Stats) {
+			assert.Equal(t, 5, state.Stats.Trials)
+			assert.Equal(t, 5, state.Stats.Completed)
+			assert.Equal(t, 5, state.Stats.Observations)
+			assert.Equal(t, float64(state.BestValue), state.Stats.BestValue)
+			assert.Equal(t, state.BestParams, checkpointValues(state.Stats.BestParams))
+		}
+
+		cancel()
+
+		result := <-done
+
+		// The last checkpoint holds the canceled trial too.
+		state = readCheckpointFile(t, path)
+
+		assert.Len(t, state.Trials, len(result.Trials))
+		assert.Equal(t, TrialCanceled, state.Trials[len(state.Trials)-1].Status)
+		assert.Equal(t, result.BestTime, float64(state.BestValue))
+		assert.Equal(t, checkpointValues(result.BestParams), state.BestParams)
+		assert.Empty(t, result.Warnings)
+
+		// No temporary file is left behind.
+		entries, err := os.ReadDir(filepath.Dir(path))
+
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("writer", func(t *testing.T) {
+		var (
+			mu    sync.Mutex
+			files []*memoryFile
+		)
+
+		config := fastConfig()
+		config.Checkpoint = &Checkpoint{
+			Writer: func() (io.WriterAt, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				files = append(files, &memoryFile{})
+
+				return files[len(files)-1], nil
+			},
+			Every: 100,
+		}
+
+		objective := func(params ...int) (float64, error) {
+			if params[0] == 3 {
+				return math.NaN(), nil
+			}
+
+			return float64(params[0]), nil
+		}
+
+		result := OptimizeObjective(config, objective, ParameterRange[int]{Min: 0, Max: 5})
+
+		// Only the last checkpoint is due.
+		if !assert.Len(t, files, 1) {
+			return
+		}
+
+		assert.True(t, files[0].closed)
+
+		state, err := readCheckpoint(bytes.NewReader(files[0].data))
+
+		if assert.NoError(t, err) && assert.Len(t, state.Trials, len(result.Trials)) {
+			for i, trial := range result.Trials {
+				assert.Equal(t, trial.ExecutionTime, float64(state.Trials[i].Value))
+
+				raw := float64(state.Trials[i].RawValue)
+
+				assert.True(t, raw == trial.RawValue || math.IsNaN(raw) && math.IsNaN(trial.RawValue))
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		config := fastConfig()
+		config.Checkpoint = &Checkpoint{}
+
+		result := Optimize(config, func(params ...int) error { return nil }, ParameterRange[int]{Min: 0, Max: 5})
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+
+	t.Run("write failure", func(t *testing.T) {
+		config := fastConfig()
+		config.Checkpoint = &Checkpoint{Path: filepath.Join(t.TempDir(), "missing", "run.json")}
+
+		result := Optimize(config, func(params ...int) error { return nil }, ParameterRange[int]{Min: 0, Max: 5})
+
+		assert.Equal(t, TerminationCompleted, result.TerminationReason)
+		assert.NotEmpty(t, result.Warnings)
+	})
+
+	t.Run("version", func(t *testing.T) {
+		_, err := readCheckpoint(strings.NewReader(`{"version": 99}`))
+
+		assert.ErrorContains(t, err, "unsupported checkpoint version 99")
+	})
+}
+
+func TestResumeFromCheckpoint(t *testing.T) {
+	config := fastConfig()
+	config.Seed = 11
+	config.Iterations = 10
+	config.CandidateMix = CandidateMix{Incumbent: 0.3, TopK: 0.3}
+
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}, {Min: -5, Max: 5}}
+
+	objective := func(params ...float64) (float64, error) {
+		return math.Pow(params[0]-3, 2) + math.Pow(params[1]-1, 2), nil
+	}
+
+	uninterrupted := OptimizeObjective(config, objective, ranges...)
+
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "run.json")
+
+	crashed := filepath.Join(dir, "crashed.json")
+
+	// The run dies during its 8th evaluation: the checkpoint file is copied
+	// as it is at that point, before the run terminates.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+
+	interrupted := config
+	interrupted.Checkpoint = &Checkpoint{Path: path}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		OptimizeObjectiveWithContext(ctx, interrupted, func(ctx context.Context, params ...float64) (float64, error) {
+			if calls.Add(1) == 8 {
+				<-ctx.Done()
+
+				return 0, ctx.Err()
+			}
+
+			return objective(params...)
+		}, ranges...)
+	}()
+
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if state := readCheckpointFile(t, path); state != nil && len(state.Trials) == 7 {
+			data, err := os.ReadFile(path)
+
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(crashed, data, 0o600))
+
+			break
+		}
+	}
+
+	cancel()
+
+	<-done
+
+	t.Run("same trials", func(t *testing.T) {
+		var evaluations int
+
+		resumed := ResumeObjectiveFromCheckpoint(crashed, config, func(params ...float64) (float64, error) {
+			evaluations++
+
+			return objective(params...)
+		}, ranges...)
+
+		assert.NoError(t, resumed.Err)
+		assert.Equal(t, len(uninterrupted.Trials)-7, evaluations)
+
+		if !assert.Len(t, resumed.Trials, len(uninterrupted.Trials)) {
+			return
+		}
+
+		for i, trial := range uninterrupted.Trials {
+			assert.Equal(t, trial.TrialInfo, resumed.Trials[i].TrialInfo)
+			assert.Equal(t, trial.Params, resumed.Trials[i].Params)
+			assert.Equal(t, trial.ExecutionTime, resumed.Trials[i].ExecutionTime)
+		}
+
+		assert.Equal(t, uninterrupted.BestParams, resumed.BestParams)
+		assert.Equal(t, uninterrupted.BestTime, resumed.BestTime)
+	})
+
+	t.Run("version 1", func(t *testing.T) {
+		// Version 1 files hold observations as parameter values.
+		state := readCheckpointFile(t, crashed)
+		if !assert.NotNil(t, state) {
+			return
+		}
+
+		state.Version = 1
+
+		for _, observation := range state.Observations {
+			for d, hyper := range ranges {
+				observation.Params[d] += hyper.Min
+			}
+		}
+
+		data, err := json.Marshal(state)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		v1 := filepath.Join(dir, "v1.json")
+
+		assert.NoError(t, os.WriteFile(v1, data, 0o600))
+
+		resumed := ResumeObjectiveFromCheckpoint(v1, config, objective, ranges...)
+
+		assert.NoError(t, resumed.Err)
+
+		if assert.Len(t, resumed.Trials, len(uninterrupted.Trials)) {
+			for i, trial := range uninterrupted.Trials {
+				assert.Equal(t, trial.Params, resumed.Trials[i].Params)
+			}
+		}
+	})
+
+	t.Run("search space mismatch", func(t *testing.T) {
+		resumed := ResumeObjectiveFromCheckpoint(crashed, config, func(params ...float64) (float64, error) {
+			t.Error("the objective must not be called")
+
+			return 0, nil
+		}, ParameterRange[float64]{Min: 0, Max: 10}, ParameterRange[float64]{Min: -5, Max: 6})
+
+		assert.ErrorIs(t, resumed.Err, ErrInvalidConfig)
+		assert.ErrorContains(t, resumed.Err, "checkpoint parameter 1")
+		assert.Equal(t, TerminationInvalidConfig, resumed.TerminationReason)
+		assert.Empty(t, resumed.Trials)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		resumed := ResumeFromCheckpoint(filepath.Join(dir, "missing.json"), config, func(params ...float64) error {
+			return nil
+		}, ranges...)
+
+		assert.ErrorIs(t, resumed.Err, ErrInvalidConfig)
+		assert.ErrorIs(t, resumed.Err, os.ErrNotExist)
+	})
+
+	t.Run("different settings", func(t *testing.T) {
+		extended := config
+		extended.Iterations = 12
+
+		resumed := ResumeObjectiveFromCheckpoint(crashed, extended, objective, ranges...)
+
+		assert.NoError(t, resumed.Err)
+		assert.Len(t, resumed.Trials, len(uninterrupted.Trials)+2)
+		assert.Contains(t, resumed.Warnings, "the checkpoint was written with different settings, the run resumes with the current ones")
+	})
+}
+
+func TestRestoreTrialParams(t *testing.T) {
+	hypers := []ParameterRange[int]{{Min: 4, Max: 20, Step: 4}, log2Range}
+
+	// Values are rounded, clamped and snapped as the run's.
+	trial := restoreTrial(hypers, checkpointTrial{Params: []json.Number{"13.2", "25"}, BenchmarkParams: []json.Number{"0", "0"}})
+
+	assert.Equal(t, []int{12, 20}, trial.Params)
+
+	// Values the benchmark received are derived from the parameters.
+	assert.Equal(t, []int{12, 1 << 20}, trial.BenchmarkParams)
+}
@@ -0,0 +1,28 @@
+// Package main implements the ho command, which optimizes the parameters of
+// an external command, e.g. flags of a CLI benchmark or a script that prints
+// a number.
+//
+// Usage:
+//
+//	ho -config space.yaml [flags] -- command [args...]
+//
+// The search space and optimization settings are read from the config file,
+// see ho.LoadConfig, then overridden by HO_* environment variables, e.g.
+// HO_ITERATIONS=200 or HO_SEED=7, see ho.ApplyEnvOverrides, and by the flags
+// explicitly set. Placeholders like {workers} in the command and its
+// arguments are replaced by the values of the parameters of the same name.
+//
+// The objective is either the execution time of the command ("-objective
+// time", the default), a float parsed from its stdout with a regular
+// expression ("-objective regex -regex 'p99: ([0-9.]+)'"), or a float found
+// at a path of its JSON stdout ("-objective json -json-path latency.p99").
+// A non-zero exit status fails the trial, unless it's the -skip-exit-code.
+//
+// Trials and the best result are written to -out, as JSON or CSV. With
+// -importance, the parameter importance is computed, and added to the JSON
+// output, see ho.Result.ParameterImportance. With -interactions, so are the
+// pairwise interactions, see ho.Result.Interactions.
+//
+// "ho serve [-addr :8080]" runs the HTTP ask/tell service instead, see the
+// httpserver package.
+package main
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/thalesfsp/ho"
+	"github.com/thalesfsp/ho/internal/shared"
+)
+
+//////
+// Helpers.
+//////
+
+// run runs the command with the given arguments, and returns the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "serve" {
+		return runServe(ctx, args[1:], stderr, nil)
+	}
+
+	fs := flag.NewFlagSet(shared.Name, flag.ContinueOnError)
+
+	fs.SetOutput(stderr)
+
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s -config space.yaml [flags] -- command [args...]\n", shared.Name)
+		fmt.Fprintf(stderr, "       %s serve [-addr :8080]\n\n", shared.Name)
+		fmt.Fprintln(stderr, "Placeholders like {name} in the command are replaced by parameter values.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+
+	var (
+		configPath   = fs.String("config", "", "Path of the JSON/YAML config file (required), see ho.LoadConfig")
+		objective    = fs.String("objective", objectiveTime, "Objective to minimize: time, regex or json")
+		regex        = fs.String("regex", "", "Regular expression extracting the objective from stdout, first group if any (-objective regex)")
+		jsonPath     = fs.String("json-path", "", "Dot-separated path of the objective in the JSON stdout, e.g. latency.p99 (-objective json)")
+		timeout      = fs.Duration("timeout", 0, "Per-trial timeout, overrides the config file")
+		retries      = fs.Int("retries", 0, "Replacement trials drawn per skipped trial, overrides the config file")
+		skipExitCode = fs.Int("skip-exit-code", -1, "Exit code meaning the trial must be skipped, -1 for none")
+		outPath      = fs.String("out", "", "Path of the trials and best result output, stdout if empty")
+		format       = fs.String("format", "", "Output format: json or csv, inferred from -out if empty, json otherwise")
+		importance   = fs.Bool("importance", false, "Compute the parameter importance, part of the JSON output and summary")
+		interactions = fs.Bool("interactions", false, "Compute the pairwise interactions of the parameters, part of the JSON output")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	usageErr := func(format string, args ...any) int {
+		fmt.Fprintf(stderr, "%s: %s\n", shared.Name, fmt.Sprintf(format, args...))
+
+		fs.Usage()
+
+		return 2
+	}
+
+	if *configPath == "" {
+		return usageErr("-config is required")
+	}
+
+	if fs.NArg() == 0 {
+		return usageErr("missing command")
+	}
+
+	if *format == "" {
+		*format = formatJSON
+
+		if strings.EqualFold(filepath.Ext(*outPath), ".csv") {
+			*format = formatCSV
+		}
+	}
+
+	if *format != formatJSON && *format != formatCSV {
+		return usageErr("unknown -format %q", *format)
+	}
+
+	r := &runner{
+		template:     fs.Args(),
+		skipExitCode: *skipExitCode,
+		jsonPath:     *jsonPath,
+	}
+
+	switch *objective {
+	case objectiveTime, objectiveJSON:
+	case objectiveRegex:
+		re, err := regexp.Compile(*regex)
+		if err != nil || *regex == "" {
+			return usageErr("-objective regex requires a valid -regex: %v", err)
+		}
+
+		r.regex = re
+	default:
+		return usageErr("unknown -objective %q", *objective)
+	}
+
+	f, err := os.Open(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", shared.Name, err)
+
+		return 1
+	}
+
+	config, space, err := ho.LoadConfig(f)
+
+	f.Close()
+
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s: %v\n", shared.Name, *configPath, err)
+
+		return 1
+	}
+
+	r.space = space
+
+	// HO_* variables override the config file, see ho.ApplyEnvOverrides.
+	if err := ho.ApplyEnvOverrides(ho.DefaultEnvPrefix, &config); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", shared.Name, err)
+
+		return 1
+	}
+
+	// Flags explicitly set override the config file and the environment.
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "timeout":
+			config.TrialTimeout = *timeout
+		case "retries":
+			config.MaxSkipRetries = *retries
+		}
+	})
+
+	ranges := ho.Ranges[float64](space)
+
+	var result *ho.Result[float64]
+
+	if *objective == objectiveTime {
+		result = ho.OptimizeWithContext(ctx, config, r.benchmark, ranges...)
+	} else {
+		result = ho.OptimizeObjectiveWithContext(ctx, config, r.objective, ranges...)
+	}
+
+	out := stdout
+
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", shared.Name, err)
+
+			return 1
+		}
+
+		defer file.Close()
+
+		out = file
+	}
+
+	var scores *ho.ParameterImportance
+
+	if *importance {
+		scores, err = result.ParameterImportance()
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: skipping parameter importance: %v\n", shared.Name, err)
+		}
+	}
+
+	var matrix *ho.InteractionMatrix
+
+	if *interactions {
+		matrix, err = result.Interactions(ho.InteractionOptions{})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: skipping interactions: %v\n", shared.Name, err)
+		}
+	}
+
+	if err := writeReport(out, *format, space, result, scores, matrix); err != nil {
+		fmt.Fprintf(stderr, "%s: writing output: %v\n", shared.Name, err)
+
+		return 1
+	}
+
+	printSummary(stderr, result, scores)
+
+	if result.Err != nil && !errors.Is(result.Err, ho.ErrStopOptimization) {
+		return 1
+	}
+
+	return 0
+}
+
+// printSummary prints the best result, and the importance if computed, in a
+// human-readable form.
+func printSummary(w io.Writer, result *ho.Result[float64], importance *ho.ParameterImportance) {
+	if result.Err != nil {
+		fmt.Fprintf(w, "%s: run ended early (%s): %v\n", shared.Name, result.TerminationReason, result.Err)
+	}
+
+	best := newReport(result).Best
+	if best == nil {
+		fmt.Fprintf(w, "%s: no trial completed\n", shared.Name)
+
+		return
+	}
+
+	names := make([]string, 0, len(best.Params))
+
+	for name := range best.Params {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+
+	for i, name := range names {
+		if formatted, ok := best.Formatted[name]; ok {
+			pairs[i] = name + "=" + formatted
+
+			continue
+		}
+
+		pairs[i] = fmt.Sprintf("%s=%v", name, best.Params[name])
+	}
+
+	fmt.Fprintf(w, "%s: best %s value=%v (%d trials)\n", shared.Name, strings.Join(pairs, " "), best.Value, len(result.Trials))
+
+	if importance == nil {
+		return
+	}
+
+	scores := make([]string, len(importance.Scores))
+
+	for i, score := range importance.Scores {
+		scores[i] = fmt.Sprintf("%s=%.0f%%", score.Name, score.Score*100)
+	}
+
+	fmt.Fprintf(w, "%s: importance %s\n", shared.Name, strings.Join(scores, " "))
+}
+
+//////
+// Main.
+//////
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+
+	cancel()
+
+	os.Exit(code)
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho"
+)
+
+const (
+	bench = "testdata/bench.sh"
+	space = "testdata/space.yaml"
+)
+
+// runCLI runs the command, and returns the exit code, stdout and stderr.
+func runCLI(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run(context.Background(), args, &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+// decodeReport decodes the JSON output.
+func decodeReport(t *testing.T, output string) report {
+	t.Helper()
+
+	var r report
+
+	assert.NoError(t, json.Unmarshal([]byte(output), &r))
+
+	return r
+}
+
+func TestRunObjectives(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "regex", args: []string{"-objective", "regex", "-regex", `score: (\d+)`, "--", bench, "text", "{x}", "{y}"}},
+		{name: "json", args: []string{"-objective", "json", "-json-path", "result.runs.0.score", "--", bench, "json", "{x}", "{y}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, append([]string{"-config", space}, tt.args...)...)
+
+			assert.Equal(t, 0, code, stderr)
+			assert.Contains(t, stderr, "best")
+
+			r := decodeReport(t, stdout)
+
+			assert.Equal(t, ho.TerminationCompleted, r.TerminationReason)
+			assert.Len(t, r.Trials, 8)
+
+			for _, trial := range r.Trials {
+				assert.Equal(t, ho.TrialCompleted, trial.Status)
+
+				x, y := trial.Params["x"], trial.Params["y"]
+
+				// Integer parameters are integers, and the objective was parsed.
+				assert.Equal(t, float64(int(x)), x)
+				assert.Equal(t, (x-7)*(x-7)+y, *trial.Value)
+				assert.LessOrEqual(t, r.Best.Value, *trial.Value)
+			}
+		})
+	}
+}
+
+func TestRunEnvOverrides(t *testing.T) {
+	t.Setenv("HO_ITERATIONS", "2")
+
+	code, stdout, stderr := runCLI(t, "-config", space, "-objective", "regex", "-regex", `score: (\d+)`, "--", bench, "text", "{x}", "{y}")
+
+	assert.Equal(t, 0, code, stderr)
+	assert.Len(t, decodeReport(t, stdout).Trials, 5)
+
+	// Typos aren't ignored.
+	t.Setenv("HO_ITERATION", "2")
+
+	code, _, stderr = runCLI(t, "-config", space, "-objective", "regex", "-regex", `score: (\d+)`, "--", bench, "text", "{x}", "{y}")
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "HO_ITERATION")
+}
+
+func TestRunImportance(t *testing.T) {
+	code, stdout, stderr := runCLI(t, "-config", space, "-importance", "-objective", "regex", "-regex", `score: (\d+)`, "--", bench, "text", "{x}", "{y}")
+
+	assert.Equal(t, 0, code, stderr)
+	assert.Contains(t, stderr, "importance x=")
+
+	r := decodeReport(t, stdout)
+
+	if assert.NotNil(t, r.Importance) && assert.Len(t, r.Importance.Scores, 2) {
+		assert.Equal(t, "x", r.Importance.Scores[0].Name)
+		assert.Equal(t, "y", r.Importance.Scores[1].Name)
+		assert.InDelta(t, 1, r.Importance.Scores[0].Score+r.Importance.Scores[1].Score, 1e-9)
+	}
+
+	// It's only computed on request.
+	_, stdout, _ = runCLI(t, "-config", space, "-objective", "regex", "-regex", `score: (\d+)`, "--", bench, "text", "{x}", "{y}")
+
+	assert.NotContains(t, stdout, "importance")
+}
+
+func TestRunInteractions(t *testing.T) {
+	code, stdout, stderr := runCLI(t, "-config", space, "-interactions", "-objective", "regex", "-regex", `score: (\d+)`, "--", bench, "text", "{x}", "{y}")
+
+	assert.Equal(t, 0, code, stderr)
+
+	r := decodeReport(t, stdout)
+
+	if assert.NotNil(t, r.Interactions) && assert.Len(t, r.Interactions.Strengths, 2) {
+		assert.Equal(t, []string{"x", "y"}, r.Interactions.Names)
+		assert.Equal(t, r.Interactions.Strengths[0][1], r.Interactions.Strengths[1][0])
+	}
+
+	assert.Nil(t, r.Importance)
+}
+
+func TestRunTimeObjective(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "trials.csv")
+
+	code, stdout, stderr := runCLI(t, "-config", space, "-out", out, "--", bench, "text", "{x}", "{y}")
+
+	assert.Equal(t, 0, code, stderr)
+	assert.Empty(t, stdout)
+
+	f, err := os.Open(out)
+	assert.NoError(t, err)
+
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"id", "phase", "iteration", "retry", "status", "cached", "value", "duration_ns", "x", "y", "error"}, rows[0])
+	assert.Len(t, rows, 9)
+
+	for _, row := range rows[1:] {
+		assert.Equal(t, string(ho.TrialCompleted), row[4])
+		assert.NotEmpty(t, row[6])
+	}
+}
+
+func TestRunEnum(t *testing.T) {
+	dir := t.TempDir()
+
+	config := filepath.Join(dir, "space.yaml")
+
+	assert.NoError(t, os.WriteFile(config, []byte(`iterations: 3
+initialSamples: 3
+seed: 1
+parameters:
+  - {name: x, type: int, min: 0, max: 10}
+  - {name: codec, type: enum, values: [zstd, lz4, none]}
+`), 0o600))
+
+	codecs := []string{"zstd", "lz4", "none"}
+
+	out := filepath.Join(dir, "trials.csv")
+
+	// The codec is passed as an extra argument, ignored by the benchmark.
+	code, _, stderr := runCLI(t, "-config", config, "-out", out, "--", bench, "text", "{x}", "0", "{codec}")
+
+	assert.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `best codec=(zstd|lz4|none) x=`, stderr)
+
+	f, err := os.Open(out)
+	assert.NoError(t, err)
+
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "codec", rows[0][9])
+	assert.Len(t, rows, 7)
+
+	for _, row := range rows[1:] {
+		assert.Contains(t, codecs, row[9])
+	}
+
+	code, stdout, stderr := runCLI(t, "-config", config, "--", bench, "text", "{x}", "0", "{codec}")
+
+	assert.Equal(t, 0, code, stderr)
+
+	r := decodeReport(t, stdout)
+
+	assert.Contains(t, codecs, r.Best.Formatted["codec"])
+
+	for _, trial := range r.Trials {
+		assert.Contains(t, codecs, trial.Formatted["codec"])
+		assert.Contains(t, stdout, `"codec": "`+trial.Formatted["codec"]+`"`)
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		code, stdout, stderr := runCLI(t, "-config", space, "-timeout", "50ms", "--", bench, "sleep", "{x}", "{y}")
+
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stderr, "no trial completed")
+
+		for _, trial := range decodeReport(t, stdout).Trials {
+			assert.Equal(t, ho.TrialCanceled, trial.Status)
+		}
+	})
+
+	t.Run("skip with retries", func(t *testing.T) {
+		code, stdout, stderr := runCLI(t,
+			"-config", space, "-skip-exit-code", "3", "-retries", "2",
+			"-objective", "regex", "-regex", `score: (\d+)`,
+			"--", bench, "skip", "{x}", "{y}",
+		)
+
+		assert.Equal(t, 0, code, stderr)
+
+		r := decodeReport(t, stdout)
+
+		for _, trial := range r.Trials {
+			if trial.Params["x"] < 5 {
+				assert.Equal(t, ho.TrialSkipped, trial.Status)
+			} else {
+				assert.Equal(t, ho.TrialCompleted, trial.Status)
+			}
+		}
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		code, stdout, stderr := runCLI(t, "-config", space, "--", bench, "fail", "{x}", "{y}")
+
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stderr, "no trial completed")
+
+		for _, trial := range decodeReport(t, stdout).Trials {
+			assert.Equal(t, ho.TrialFailed, trial.Status)
+			assert.Contains(t, trial.Error, "boom")
+		}
+	})
+
+	t.Run("unparsable output", func(t *testing.T) {
+		code, stdout, _ := runCLI(t, "-config", space, "-objective", "json", "--", bench, "text", "{x}", "{y}")
+
+		assert.Equal(t, 0, code)
+
+		for _, trial := range decodeReport(t, stdout).Trials {
+			assert.Equal(t, ho.TrialFailed, trial.Status)
+			assert.Contains(t, trial.Error, "isn't JSON")
+		}
+	})
+}
+
+func TestRunUsage(t *testing.T) {
+	invalidConfig := filepath.Join(t.TempDir(), "invalid.yaml")
+	assert.NoError(t, os.WriteFile(invalidConfig, []byte("parameters: [{name: x, type: int, min: 2, max: 1}]"), 0o600))
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+		want string
+	}{
+		{name: "missing config", args: []string{"--", bench}, code: 2, want: "-config is required"},
+		{name: "missing command", args: []string{"-config", space}, code: 2, want: "missing command"},
+		{name: "unknown objective", args: []string{"-config", space, "-objective", "cpu", "--", bench}, code: 2, want: "unknown -objective"},
+		{name: "missing regex", args: []string{"-config", space, "-objective", "regex", "--", bench}, code: 2, want: "-regex"},
+		{name: "unknown format", args: []string{"-config", space, "-format", "xml", "--", bench}, code: 2, want: "unknown -format"},
+		{name: "unknown flag", args: []string{"-config", space, "-verbose", "--", bench}, code: 2, want: "-verbose"},
+		{name: "missing config file", args: []string{"-config", "missing.yaml", "--", bench}, code: 1, want: "missing.yaml"},
+		{name: "invalid config file", args: []string{"-config", invalidConfig, "--", bench}, code: 1, want: "parameters[0].min"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, tt.args...)
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, stderr, tt.want)
+		})
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	output := []byte(`{"a": {"b": [1.5, {"c": "2.5"}]}, "d": true}`)
+
+	v, err := parseJSONPath(output, "a.b.0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, v)
+
+	v, err = parseJSONPath(output, "a.b.1.c")
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, v)
+
+	v, err = parseJSONPath([]byte("3"), "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, v)
+
+	for _, path := range []string{"x", "a.b.2", "a.b.x", "a.b.0.c", "d", "a"} {
+		_, err := parseJSONPath(output, path)
+
+		assert.Error(t, err, path)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	r := &runner{
+		template: []string{"bench", "--workers={workers}", "--rate={rate}", "{workers}x", "--codec={codec}"},
+		space: ho.SearchSpace{Parameters: []ho.ParameterSpec{
+			{Name: "workers", Type: ho.IntParameter},
+			{Name: "rate", Type: ho.FloatParameter},
+			{Name: "codec", Type: ho.EnumParameter, Values: []string{"zstd", "lz4"}},
+		}},
+	}
+
+	assert.Equal(t, []string{"bench", "--workers=8", "--rate=0.25", "8x", "--codec=lz4"}, r.command([]float64{8, 0.25, 1}))
+}
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	addrs := make(chan string, 1)
+	codes := make(chan int, 1)
+
+	go func() {
+		codes <- runServe(ctx, []string{"-addr", "127.0.0.1:0"}, io.Discard, func(addr string) { addrs <- addr })
+	}()
+
+	addr := <-addrs
+
+	resp, err := http.Post("http://"+addr+"/studies", "application/json", strings.NewReader(
+		`{"parameters": [{"name": "x", "type": "int", "min": 0, "max": 10}]}`,
+	))
+	assert.NoError(t, err)
+
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	cancel()
+
+	assert.Equal(t, 0, <-codes)
+
+	// Invalid flags.
+	assert.Equal(t, 2, run(context.Background(), []string{"serve", "-port", "1"}, io.Discard, io.Discard))
+}
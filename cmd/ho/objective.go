@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thalesfsp/ho"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// waitDelay bounds how long a canceled command's output is waited for, e.g.
+// when a child process it spawned keeps its stdout open.
+const waitDelay = time.Second
+
+// Objective kinds.
+const (
+	objectiveTime  = "time"
+	objectiveRegex = "regex"
+	objectiveJSON  = "json"
+)
+
+// runner runs the command for a set of parameters.
+type runner struct {
+	// template holds the command and its arguments, with placeholders.
+	template []string
+
+	// space is the search space, used to resolve placeholders.
+	space ho.SearchSpace
+
+	// skipExitCode is the exit code meaning the trial must be skipped, or -1.
+	skipExitCode int
+
+	// regex extracts the objective from stdout, for objectiveRegex.
+	regex *regexp.Regexp
+
+	// jsonPath locates the objective in stdout, for objectiveJSON.
+	jsonPath string
+}
+
+//////
+// Methods.
+//////
+
+// command returns the command and its arguments with the placeholders
+// replaced by the parameter values, see ParameterSpec.Format. Integers are
+// rounded, durations are rendered like "250ms", bools as "true" or "false",
+// enums as the chosen value.
+func (r *runner) command(params []float64) []string {
+	pairs := make([]string, 0, 2*len(params))
+
+	for i, p := range r.space.Parameters {
+		pairs = append(pairs, "{"+p.Name+"}", p.Format(params[i]))
+	}
+
+	replacer := strings.NewReplacer(pairs...)
+
+	args := make([]string, len(r.template))
+
+	for i, arg := range r.template {
+		args[i] = replacer.Replace(arg)
+	}
+
+	return args
+}
+
+// run runs the command and returns its stdout. A non-zero exit status is an
+// error, wrapping ho.ErrSkipTrial if it's the skip exit code.
+func (r *runner) run(ctx context.Context, params []float64) ([]byte, error) {
+	args := r.command(params)
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+
+	cmd.WaitDelay = waitDelay
+
+	var stdout, stderr bytes.Buffer
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) && r.skipExitCode >= 0 && exitErr.ExitCode() == r.skipExitCode {
+		return nil, fmt.Errorf("%w: exit status %d", ho.ErrSkipTrial, r.skipExitCode)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// benchmark runs the command, its execution time being the objective.
+func (r *runner) benchmark(ctx context.Context, params ...float64) error {
+	_, err := r.run(ctx, params)
+
+	return err
+}
+
+// objective runs the command and extracts the objective from its stdout.
+func (r *runner) objective(ctx context.Context, params ...float64) (float64, error) {
+	stdout, err := r.run(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.regex != nil {
+		return parseRegex(r.regex, stdout)
+	}
+
+	return parseJSONPath(stdout, r.jsonPath)
+}
+
+//////
+// Helpers.
+//////
+
+// parseRegex parses the first submatch of the regular expression in the
+// output, or the whole match if it has no group.
+func parseRegex(re *regexp.Regexp, output []byte) (float64, error) {
+	match := re.FindSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("objective regex %q doesn't match the output", re)
+	}
+
+	value := match[0]
+	if len(match) > 1 {
+		value = match[1]
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(string(value)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("objective regex %q: %w", re, err)
+	}
+
+	return v, nil
+}
+
+// parseJSONPath parses the number at the dot-separated path of the JSON
+// output, e.g. "latency.p99" or "runs.0.time". An empty path designates the
+// whole output.
+func parseJSONPath(output []byte, path string) (float64, error) {
+	var node any
+
+	if err := json.Unmarshal(output, &node); err != nil {
+		return 0, fmt.Errorf("objective output isn't JSON: %w", err)
+	}
+
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			switch n := node.(type) {
+			case map[string]any:
+				v, ok := n[key]
+				if !ok {
+					return 0, fmt.Errorf("objective path %q: key %q not found", path, key)
+				}
+
+				node = v
+			case []any:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(n) {
+					return 0, fmt.Errorf("objective path %q: invalid index %q", path, key)
+				}
+
+				node = n[i]
+			default:
+				return 0, fmt.Errorf("objective path %q: can't index %T with %q", path, node, key)
+			}
+		}
+	}
+
+	switch v := node.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("objective path %q: %T isn't a number", path, node)
+	}
+}
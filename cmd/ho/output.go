@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/thalesfsp/ho"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Output formats.
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+// report is the JSON output.
+type report struct {
+	// Best is the best result, nil if no trial completed.
+	Best *bestReport `json:"best"`
+
+	// TerminationReason describes why the run ended.
+	TerminationReason ho.TerminationReason `json:"terminationReason"`
+
+	// TerminationDetail details the reason, see ho.Result.TerminationDetail.
+	TerminationDetail string `json:"terminationDetail,omitempty"`
+
+	// Error is the error that terminated the run early, if any.
+	Error string `json:"error,omitempty"`
+
+	// Trials holds every trial, in completion order.
+	Trials []ho.TrialRecord `json:"trials"`
+
+	// Importance holds the parameter importance, if computed (-importance).
+	Importance *ho.ParameterImportance `json:"importance,omitempty"`
+
+	// Interactions holds the pairwise interactions, if computed
+	// (-interactions).
+	Interactions *ho.InteractionMatrix `json:"interactions,omitempty"`
+}
+
+// bestReport is the best result of the JSON output.
+type bestReport struct {
+	Params    map[string]float64 `json:"params"`
+	Formatted map[string]string  `json:"formatted,omitempty"`
+	Value     float64            `json:"value"`
+
+	// Confidence is the 95% confidence interval of the value, if the best
+	// parameters were measured more than once.
+	Confidence *ho.ConfidenceInterval `json:"confidence,omitempty"`
+}
+
+//////
+// Helpers.
+//////
+
+// newReport builds the JSON output of the result.
+func newReport(result *ho.Result[float64]) report {
+	r := report{
+		TerminationReason: result.TerminationReason,
+		TerminationDetail: result.TerminationDetail,
+		Trials:            result.Records(),
+	}
+
+	if result.Err != nil {
+		r.Error = result.Err.Error()
+	}
+
+	for _, trial := range r.Trials {
+		if trial.Value != nil && (r.Best == nil || *trial.Value < r.Best.Value) {
+			r.Best = &bestReport{Params: trial.Params, Formatted: trial.Formatted, Value: *trial.Value}
+		}
+	}
+
+	if ci, err := result.BestConfidence(0.95); err == nil && r.Best != nil {
+		r.Best.Confidence = &ci
+	}
+
+	return r
+}
+
+// writeReport writes the result in the given format. The importance and
+// interactions, if computed, are only part of the JSON output.
+func writeReport(
+	w io.Writer,
+	format string,
+	space ho.SearchSpace,
+	result *ho.Result[float64],
+	importance *ho.ParameterImportance,
+	interactions *ho.InteractionMatrix,
+) error {
+	r := newReport(result)
+
+	r.Importance = importance
+	r.Interactions = interactions
+
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(r)
+	case formatCSV:
+		return writeCSV(w, space, r)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// writeCSV writes the trials as CSV, one row per trial. Parameter values are
+// rendered by their type, e.g. durations like "250ms".
+func writeCSV(w io.Writer, space ho.SearchSpace, r report) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"id", "phase", "iteration", "retry", "status", "cached", "value", "duration_ns"}
+
+	for _, p := range space.Parameters {
+		header = append(header, p.Name)
+	}
+
+	if err := writer.Write(append(header, "error")); err != nil {
+		return err
+	}
+
+	for _, trial := range r.Trials {
+		value := ""
+		if trial.Value != nil {
+			value = strconv.FormatFloat(*trial.Value, 'g', -1, 64)
+		}
+
+		row := []string{
+			strconv.Itoa(trial.ID),
+			trial.Phase,
+			strconv.Itoa(trial.Iteration),
+			strconv.FormatBool(trial.Retry),
+			string(trial.Status),
+			strconv.FormatBool(trial.Cached),
+			value,
+			strconv.FormatInt(trial.DurationNS, 10),
+		}
+
+		for _, p := range space.Parameters {
+			row = append(row, p.Format(trial.Params[p.Name]))
+		}
+
+		if err := writer.Write(append(row, trial.Error)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/thalesfsp/ho/httpserver"
+	"github.com/thalesfsp/ho/internal/shared"
+)
+
+// shutdownTimeout bounds how long in-flight requests are waited for on
+// shutdown.
+const shutdownTimeout = 5 * time.Second
+
+//////
+// Helpers.
+//////
+
+// runServe runs the HTTP ask/tell service until ctx is canceled, and returns
+// the exit code.
+//
+// Parameters:
+// - ctx: Canceling it shuts the service down
+// - args: Arguments after "serve"
+// - stderr: Where errors and the listening address are written
+// - ready: Called with the listening address once the service accepts
+// requests, may be nil.
+func runServe(ctx context.Context, args []string, stderr io.Writer, ready func(addr string)) int {
+	fs := flag.NewFlagSet(shared.Name+" serve", flag.ContinueOnError)
+
+	fs.SetOutput(stderr)
+
+	addr := fs.String("addr", ":8080", "Address to listen on")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", shared.Name, err)
+
+		return 1
+	}
+
+	server := &http.Server{
+		Handler:           httpserver.New(httpserver.Options{}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(stderr, "%s: serving ask/tell API on %s\n", shared.Name, listener.Addr())
+
+	if ready != nil {
+		ready(listener.Addr().String())
+	}
+
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "%s: %v\n", shared.Name, err)
+
+		return 1
+	}
+
+	return 0
+}
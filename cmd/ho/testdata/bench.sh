@@ -0,0 +1,17 @@
+#!/bin/sh
+# Helper benchmark for integration tests: prints (x-7)^2 + y, in the format
+# given as first argument.
+#
+# Usage: bench.sh text|json|sleep|skip|fail x y
+format=$1
+x=$2
+y=$3
+value=$(( (x - 7) * (x - 7) + y ))
+
+case "$format" in
+text) echo "warming up..."; echo "score: $value" ;;
+json) echo "{\"result\": {\"runs\": [{\"score\": $value}]}}" ;;
+sleep) exec sleep 5 ;;
+skip) [ "$x" -lt 5 ] && exit 3; echo "score: $value" ;;
+fail) echo "boom" >&2; exit 1 ;;
+esac
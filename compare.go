@@ -0,0 +1,315 @@
+package ho
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// PhaseComparison is the TrialInfo.Phase of the measurements of
+	// CompareConfigs.
+	PhaseComparison = "Comparison"
+
+	// defaultComparePercentile is the CompareOptions.Percentile used if
+	// unset.
+	defaultComparePercentile = 0.95
+
+	// defaultResamples is the CompareOptions.Resamples used if unset.
+	defaultResamples = 10000
+)
+
+// ComparisonVerdict summarizes a Comparison.
+type ComparisonVerdict string
+
+const (
+	// VerdictAFaster means A is significantly faster than B.
+	VerdictAFaster ComparisonVerdict = "AFaster"
+
+	// VerdictBFaster means B is significantly faster than A.
+	VerdictBFaster ComparisonVerdict = "BFaster"
+
+	// VerdictIndistinguishable means the measurements can't tell A and B
+	// apart.
+	VerdictIndistinguishable ComparisonVerdict = "Indistinguishable"
+)
+
+// CompareOptions configures CompareConfigs.
+type CompareOptions struct {
+	// Measurement holds the measurement settings, applied as during a run:
+	// TrialTimeout, CooldownBetweenTrials, RateLimiter, the Setup and
+	// Teardown hooks, and Seed, which seeds TrialInfo.Seed and the
+	// bootstrap. Other settings are ignored.
+	Measurement OptimizationConfig
+
+	// WarmUp is the number of measurements of each configuration, in the
+	// same interleaved order, made and discarded first, e.g. to fill caches.
+	WarmUp int
+
+	// Percentile is the percentile reported in each SampleSummary, e.g.
+	// 0.99 for p99.
+	// If 0, 0.95 is used.
+	Percentile float64
+
+	// Resamples is the number of bootstrap resamples of the difference.
+	// If 0, 10000 is used.
+	Resamples int
+}
+
+// SampleSummary summarizes the measurements of a configuration.
+type SampleSummary struct {
+	// Values holds the measurements, in order, e.g. execution times in
+	// nanoseconds.
+	Values []float64
+
+	// Mean is the mean of Values.
+	Mean float64
+
+	// Median is the median of Values.
+	Median float64
+
+	// StdDev is the sample standard deviation of Values.
+	StdDev float64
+
+	// Percentile is the CompareOptions.Percentile percentile of Values.
+	Percentile float64
+}
+
+// Comparison is the outcome of CompareConfigs.
+type Comparison struct {
+	// A and B summarize the measurements of each configuration.
+	A, B SampleSummary
+
+	// Difference is the mean of A minus the mean of B: negative if A is
+	// faster.
+	Difference float64
+
+	// DifferenceCI is the 95% bootstrap confidence interval of Difference.
+	DifferenceCI [2]float64
+
+	// PValue is the two-sided p-value of Welch's t-test, as a cross-check
+	// of DifferenceCI.
+	PValue float64
+
+	// Verdict is VerdictAFaster or VerdictBFaster if DifferenceCI excludes
+	// 0, VerdictIndistinguishable otherwise.
+	Verdict ComparisonVerdict
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the options.
+//
+// Parameters:
+// - runs: Number of measurements of each configuration
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if an option is invalid, nil otherwise.
+func (c CompareOptions) validate(runs int) error {
+	switch {
+	case runs < 2:
+		return fmt.Errorf("%w: runs must be at least 2, got %d", ErrInvalidConfig, runs)
+	case c.WarmUp < 0:
+		return fmt.Errorf("%w: WarmUp %d is negative", ErrInvalidConfig, c.WarmUp)
+	case c.Percentile < 0 || c.Percentile > 1:
+		return fmt.Errorf("%w: Percentile %v must be within [0, 1]", ErrInvalidConfig, c.Percentile)
+	case c.Resamples < 0:
+		return fmt.Errorf("%w: Resamples %d is negative", ErrInvalidConfig, c.Resamples)
+	case c.Measurement.TrialTimeout < 0:
+		return fmt.Errorf("%w: TrialTimeout %v is negative", ErrInvalidConfig, c.Measurement.TrialTimeout)
+	case c.Measurement.CooldownBetweenTrials < 0:
+		return fmt.Errorf("%w: CooldownBetweenTrials %v is negative", ErrInvalidConfig, c.Measurement.CooldownBetweenTrials)
+	}
+
+	return c.Measurement.HookScope.validate()
+}
+
+//////
+// Helpers.
+//////
+
+// compareConfigs runs the interleaved measurements, warm-up included, and
+// compares them, see CompareConfigs.
+//
+// Parameters:
+// - measure: Measures parameters
+// - paramsA, paramsB: The configurations
+// - runs: Number of measurements of each configuration
+// - options: Configures the comparison
+// - rng: Draws the bootstrap resamples
+func compareConfigs[T constraints.Integer | constraints.Float](
+	measure func(params []T) (float64, error),
+	paramsA, paramsB []T,
+	runs int,
+	options CompareOptions,
+	rng *rand.Rand,
+) (*Comparison, error) {
+	if options.WarmUp > 0 {
+		if _, _, err := interleave(measure, paramsA, paramsB, options.WarmUp); err != nil {
+			return nil, fmt.Errorf("warm-up %w", err)
+		}
+	}
+
+	a, b, err := interleave(measure, paramsA, paramsB, runs)
+	if err != nil {
+		return nil, fmt.Errorf("comparison %w", err)
+	}
+
+	percentile := options.Percentile
+	if percentile == 0 {
+		percentile = defaultComparePercentile
+	}
+
+	resamples := options.Resamples
+	if resamples == 0 {
+		resamples = defaultResamples
+	}
+
+	comparison := &Comparison{
+		A:          summarize(a, percentile),
+		B:          summarize(b, percentile),
+		Difference: mean(a) - mean(b),
+		Verdict:    VerdictIndistinguishable,
+	}
+
+	comparison.DifferenceCI = bootstrapDifference(a, b, resamples, rng)
+
+	_, _, comparison.PValue = welchTTest(a, b)
+
+	switch {
+	case comparison.DifferenceCI[1] < 0:
+		comparison.Verdict = VerdictAFaster
+	case comparison.DifferenceCI[0] > 0:
+		comparison.Verdict = VerdictBFaster
+	}
+
+	return comparison, nil
+}
+
+// summarize summarizes measurements.
+func summarize(values []float64, percentile float64) SampleSummary {
+	return SampleSummary{
+		Values:     values,
+		Mean:       mean(values),
+		Median:     median(values),
+		StdDev:     math.Sqrt(sampleVariance(values)),
+		Percentile: quantile(values, percentile),
+	}
+}
+
+// bootstrapDifference returns the percentile bootstrap confidence interval,
+// at the 5% significance level, of the difference of the means of a and b,
+// resampled independently.
+func bootstrapDifference(a, b []float64, resamples int, rng *rand.Rand) [2]float64 {
+	differences := make([]float64, resamples)
+
+	for i := range differences {
+		differences[i] = resampledMean(a, rng) - resampledMean(b, rng)
+	}
+
+	return [2]float64{
+		quantile(differences, significanceLevel/2),
+		quantile(differences, 1-significanceLevel/2),
+	}
+}
+
+// resampledMean returns the mean of a resample of the values, drawn with
+// replacement, for the bootstrap.
+func resampledMean(values []float64, rng *rand.Rand) float64 {
+	var sum float64
+
+	for range values {
+		sum += values[rng.Intn(len(values))]
+	}
+
+	return sum / float64(len(values))
+}
+
+//////
+// Exported functionalities.
+//////
+
+// CompareConfigs compares two configurations head-to-head, outside of an
+// optimization run, with the measurement plumbing of trials: trial context
+// and TrialTimeout, cooldowns, rate limiting and hooks, see
+// CompareOptions.Measurement.
+//
+// Parameters:
+// - options: Configures the measurements and the comparison
+// - benchmarkFunc: The function to benchmark, its execution time measured
+// - paramsA, paramsB: The configurations to compare
+// - runs: Number of measurements of each configuration, at least 2
+//
+// Returns:
+// - *Comparison: Summaries, the difference with its confidence interval,
+// and the verdict
+// - error: Wrapping ErrInvalidConfig if an option is invalid, or the first
+// measurement error, e.g. a timeout.
+//
+// Usage example:
+//
+//	comparison, err := CompareConfigs(CompareOptions{WarmUp: 2}, benchmark, current, result.BestParams, 30)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	if comparison.Verdict == VerdictBFaster {
+//	    deploy(result.BestParams)
+//	}
+//
+// Important notes:
+// - Measurements are interleaved (ABBA order), so slow drifts of the system
+// under test affect both configurations equally
+// - The benchmark is invoked with TrialInfo.Phase PhaseComparison, and
+// TrialIDs counting invocations, warm-up included
+// - The verdict relies on a bootstrap of the difference of the means, which
+// doesn't assume normality. ValidateAgainst tests the same question with
+// Welch's t-test.
+func CompareConfigs[T constraints.Integer | constraints.Float](
+	options CompareOptions,
+	benchmarkFunc BenchmarkFuncCtx[T],
+	paramsA, paramsB []T,
+	runs int,
+) (*Comparison, error) {
+	if err := options.validate(runs); err != nil {
+		return nil, err
+	}
+
+	o := newOptimizer[T](context.Background(), options.Measurement, fromBenchmarkFuncCtx(benchmarkFunc))
+
+	var invocations int
+
+	measure := func(params []T) (float64, error) {
+		if err := o.cooldown(false); err != nil {
+			return 0, err
+		}
+
+		invocations++
+
+		m := o.measureHooked(TrialInfo{
+			TrialID: invocations,
+			Phase:   PhaseComparison,
+			Seed:    trialSeed(o.source.seed, invocations),
+		}, params)
+
+		switch {
+		case m.err != nil:
+			return 0, m.err
+		case m.canceled != nil:
+			return 0, m.canceled
+		}
+
+		return m.value, nil
+	}
+
+	return compareConfigs(measure, paramsA, paramsB, runs, options, rand.New(rand.NewSource(o.source.seed)))
+}
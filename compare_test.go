@@ -0,0 +1,139 @@
+package ho
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVerdicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		meanA float64
+		want  ComparisonVerdict
+	}{
+		{name: "A faster", meanA: 90, want: VerdictAFaster},
+		{name: "B faster", meanA: 110, want: VerdictBFaster},
+		{name: "indistinguishable", meanA: 100, want: VerdictIndistinguishable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+
+			// Parameters are the means of normal distributions.
+			measure := func(params []float64) (float64, error) {
+				return params[0] + 5*rng.NormFloat64(), nil
+			}
+
+			comparison, err := compareConfigs(measure, []float64{tt.meanA}, []float64{100}, 30, CompareOptions{}, rand.New(rand.NewSource(2)))
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want, comparison.Verdict)
+
+			// The interval holds the true difference.
+			assert.Less(t, comparison.DifferenceCI[0], tt.meanA-100)
+			assert.Greater(t, comparison.DifferenceCI[1], tt.meanA-100)
+			assert.InDelta(t, tt.meanA-100, comparison.Difference, 4)
+
+			if tt.want == VerdictIndistinguishable {
+				assert.Greater(t, comparison.PValue, 0.05)
+			} else {
+				assert.Less(t, comparison.PValue, 1e-6)
+			}
+
+			for _, summary := range []SampleSummary{comparison.A, comparison.B} {
+				assert.Len(t, summary.Values, 30)
+				assert.InDelta(t, 5, summary.StdDev, 2)
+				assert.Greater(t, summary.Percentile, summary.Median)
+			}
+		})
+	}
+}
+
+func TestCompareConfigs(t *testing.T) {
+	t.Run("interleaved after warm-up", func(t *testing.T) {
+		var order []int
+
+		_, err := compareConfigs(func(params []int) (float64, error) {
+			order = append(order, params[0])
+
+			return float64(params[0]), nil
+		}, []int{1}, []int{2}, 2, CompareOptions{WarmUp: 1}, rand.New(rand.NewSource(1)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 1, 2, 2, 1}, order)
+	})
+
+	t.Run("measurement plumbing", func(t *testing.T) {
+		var infos []TrialInfo
+
+		options := CompareOptions{WarmUp: 1}
+		options.Measurement.Seed = 1
+
+		// Setup isn't timed.
+		options.Measurement.Setup = func(info TrialInfo, _ ...float64) error {
+			infos = append(infos, info)
+
+			time.Sleep(5 * time.Millisecond)
+
+			return nil
+		}
+
+		comparison, err := CompareConfigs(options, func(_ context.Context, params ...int) error {
+			time.Sleep(time.Duration(params[0]) * time.Millisecond)
+
+			return nil
+		}, []int{1}, []int{4}, 5)
+		assert.NoError(t, err)
+
+		assert.Equal(t, VerdictAFaster, comparison.Verdict)
+		assert.Less(t, comparison.A.Median, float64(4*time.Millisecond))
+
+		assert.Len(t, infos, 12)
+
+		for i, info := range infos {
+			assert.Equal(t, TrialInfo{TrialID: i + 1, Phase: PhaseComparison, Seed: trialSeed(1, i+1)}, info)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		options := CompareOptions{}
+		options.Measurement.TrialTimeout = time.Millisecond
+
+		_, err := CompareConfigs(options, func(ctx context.Context, _ ...int) error {
+			<-ctx.Done()
+
+			return nil
+		}, []int{1}, []int{2}, 3)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		benchmark := func(context.Context, ...int) error { return nil }
+
+		for _, options := range []CompareOptions{{WarmUp: -1}, {Percentile: 1.5}, {Resamples: -1}} {
+			_, err := CompareConfigs(options, benchmark, []int{1}, []int{2}, 3)
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+		}
+
+		_, err := CompareConfigs(CompareOptions{}, benchmark, []int{1}, []int{2}, 1)
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+
+	t.Run("quantile", func(t *testing.T) {
+		values := make([]float64, 100)
+
+		for i := range values {
+			values[i] = float64(100 - i)
+		}
+
+		assert.InDelta(t, 95.05, quantile(values, 0.95), 1e-9)
+		assert.Equal(t, 100.0, quantile(values, 1))
+		assert.Equal(t, 1.0, quantile(values, 0))
+	})
+}
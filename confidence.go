@@ -0,0 +1,257 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultConfidenceLevel is the level of ProgressUpdate.BestConfidence.
+	defaultConfidenceLevel = 0.95
+
+	// confidenceSeed seeds the bootstrap resamples, so the same result
+	// always gives the same interval.
+	confidenceSeed = 1
+)
+
+// ConfidenceInterval is a bootstrap confidence interval for the expected
+// value of a configuration, or for its improvement over another, see
+// Result.BestConfidence and Result.ImprovementConfidence.
+type ConfidenceInterval struct {
+	// Level is the confidence level, e.g. 0.95.
+	Level float64 `json:"level"`
+
+	// Estimate is the mean of the measurements, or the difference of the
+	// means for an improvement.
+	Estimate float64 `json:"estimate"`
+
+	// Lower and Upper bound the interval.
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+
+	// Samples is the number of measurements of the configuration the
+	// interval is based on.
+	Samples int `json:"samples"`
+
+	// BaselineSamples is the number of measurements of the baseline, for an
+	// improvement, 0 otherwise.
+	BaselineSamples int `json:"baselineSamples,omitempty"`
+}
+
+//////
+// Methods.
+//////
+
+// BestConfidence returns a bootstrap confidence interval for the expected
+// value of the best parameters, e.g. to report "1.52s ± 0.04s" rather than
+// the single lucky measurement that made them the best.
+//
+// Parameters:
+// - level: The confidence level, within (0, 1), e.g. 0.95
+//
+// Returns:
+// - ConfidenceInterval: The interval of the mean of the measurements
+// - error: Wrapping ErrInvalidConfig if the level is out of range, or
+// ErrTooFewMeasurements if the best parameters were measured less than
+// twice.
+//
+// Usage example:
+//
+//	ci, err := result.BestConfidence(0.95)
+//	if err == nil {
+//	    fmt.Printf("%.3g [%.3g, %.3g] over %d runs\n", ci.Estimate, ci.Lower, ci.Upper, ci.Samples)
+//	}
+//
+// Important notes:
+// - The measurements are those of every completed trial of the best
+// parameters: repeats, see OptimizationConfig.SurpriseRemeasure, and
+// re-evaluations, see OptimizationConfig.IncumbentReevaluation. Cached
+// trials reuse a measurement, so don't count, and with budgets only those at
+// the highest budget do, see Trial.Budget
+// - The interval is a percentile bootstrap of the mean, which doesn't assume
+// normality, but is too narrow with few measurements: 10 or more give
+// intervals close to their nominal level
+// - Resamples are drawn from a fixed seed, so the interval is reproducible.
+func (r *Result[T]) BestConfidence(level float64) (ConfidenceInterval, error) {
+	if err := checkConfidenceLevel(level); err != nil {
+		return ConfidenceInterval{}, err
+	}
+
+	return bestConfidence(r.Trials, r.BestParams, level)
+}
+
+// ImprovementConfidence returns a bootstrap confidence interval for the
+// improvement of the best parameters over those of a baseline trial, e.g.
+// the current production configuration, measured first.
+//
+// Parameters:
+// - baselineTrialID: The trial whose parameters are the baseline, see
+// TrialInfo.TrialID
+// - level: The confidence level, within (0, 1), e.g. 0.95
+//
+// Returns:
+// - ConfidenceInterval: The interval of the mean of the baseline minus that
+// of the best parameters, positive if they improve on it
+// - error: Wrapping ErrInvalidConfig if the level is out of range,
+// ErrUnknownTrial if the result has no such trial, or ErrTooFewMeasurements
+// if either parameters were measured less than twice.
+//
+// Usage example:
+//
+//	ci, err := result.ImprovementConfidence(1, 0.95)
+//	if err == nil && ci.Lower > 0 {
+//	    deploy(result.BestParams)
+//	}
+//
+// Important notes:
+// - Measurements are gathered as for BestConfidence, for both parameters,
+// and resampled independently.
+func (r *Result[T]) ImprovementConfidence(baselineTrialID int, level float64) (ConfidenceInterval, error) {
+	if err := checkConfidenceLevel(level); err != nil {
+		return ConfidenceInterval{}, err
+	}
+
+	var baseline []T
+
+	for _, trial := range r.Trials {
+		if trial.TrialID == baselineTrialID {
+			baseline = trial.Params
+
+			break
+		}
+	}
+
+	if baseline == nil {
+		return ConfidenceInterval{}, fmt.Errorf("%w: %d", ErrUnknownTrial, baselineTrialID)
+	}
+
+	best := pointMeasurements(r.Trials, r.BestParams)
+	if len(best) < 2 {
+		return ConfidenceInterval{}, fmt.Errorf("%w: the best parameters have %d", ErrTooFewMeasurements, len(best))
+	}
+
+	base := pointMeasurements(r.Trials, baseline)
+	if len(base) < 2 {
+		return ConfidenceInterval{}, fmt.Errorf("%w: the baseline has %d", ErrTooFewMeasurements, len(base))
+	}
+
+	rng := rand.New(rand.NewSource(confidenceSeed))
+
+	differences := make([]float64, defaultResamples)
+
+	for i := range differences {
+		differences[i] = resampledMean(base, rng) - resampledMean(best, rng)
+	}
+
+	return ConfidenceInterval{
+		Level:           level,
+		Estimate:        mean(base) - mean(best),
+		Lower:           quantile(differences, (1-level)/2),
+		Upper:           quantile(differences, (1+level)/2),
+		Samples:         len(best),
+		BaselineSamples: len(base),
+	}, nil
+}
+
+//////
+// Helpers.
+//////
+
+// checkConfidenceLevel returns an error wrapping ErrInvalidConfig if the
+// level isn't within (0, 1).
+func checkConfidenceLevel(level float64) error {
+	if !(level > 0 && level < 1) {
+		return fmt.Errorf("%w: confidence level %v must be within (0, 1)", ErrInvalidConfig, level)
+	}
+
+	return nil
+}
+
+// pointMeasurements returns the measurements of the parameters: those of
+// each completed, non-cached trial with the exact same parameters, see
+// Trial.Measurements. With budgets, only those at the highest budget the
+// parameters were measured with count, see Trial.Budget.
+func pointMeasurements[T constraints.Integer | constraints.Float](trials []Trial[T], params []T) []float64 {
+	if params == nil {
+		return nil
+	}
+
+	key := paramsKey(params)
+
+	var (
+		matching []Trial[T]
+		budget   float64
+	)
+
+	for _, trial := range trials {
+		if trial.Status != TrialCompleted || trial.Cached || paramsKey(trial.Params) != key {
+			continue
+		}
+
+		matching = append(matching, trial)
+
+		budget = math.Max(budget, trial.Budget)
+	}
+
+	var values []float64
+
+	for _, trial := range matching {
+		if trial.Budget != budget {
+			continue
+		}
+
+		if len(trial.Measurements) > 0 {
+			values = append(values, trial.Measurements...)
+		} else {
+			values = append(values, trial.ExecutionTime)
+		}
+	}
+
+	return values
+}
+
+// bestConfidence returns the bootstrap confidence interval of the mean of
+// the measurements of the best parameters, see Result.BestConfidence.
+//
+// Parameters:
+// - trials: The trials of the run
+// - params: The best parameters, nil if none
+// - level: The confidence level, valid
+//
+// Returns:
+// - ConfidenceInterval: The interval
+// - error: Wrapping ErrTooFewMeasurements if there are less than two
+// measurements.
+func bestConfidence[T constraints.Integer | constraints.Float](
+	trials []Trial[T],
+	params []T,
+	level float64,
+) (ConfidenceInterval, error) {
+	values := pointMeasurements(trials, params)
+	if len(values) < 2 {
+		return ConfidenceInterval{}, fmt.Errorf("%w: the best parameters have %d", ErrTooFewMeasurements, len(values))
+	}
+
+	rng := rand.New(rand.NewSource(confidenceSeed))
+
+	means := make([]float64, defaultResamples)
+
+	for i := range means {
+		means[i] = resampledMean(values, rng)
+	}
+
+	return ConfidenceInterval{
+		Level:    level,
+		Estimate: mean(values),
+		Lower:    quantile(means, (1-level)/2),
+		Upper:    quantile(means, (1+level)/2),
+		Samples:  len(values),
+	}, nil
+}
@@ -0,0 +1,200 @@
+package ho
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// measuredResult returns a result whose best parameters, 1, were measured
+// with the values, alongside a baseline, 2, measured with the baseline
+// values, and a failed and a cached trial of the best parameters, which
+// don't count.
+func measuredResult(values, baseline []float64) *Result[float64] {
+	result := &Result[float64]{BestParams: []float64{1}}
+
+	add := func(params float64, value float64, status TrialStatus, cached bool) {
+		result.Trials = append(result.Trials, Trial[float64]{
+			TrialInfo:     TrialInfo{TrialID: len(result.Trials) + 1},
+			Params:        []float64{params},
+			ExecutionTime: value,
+			Status:        status,
+			Cached:        cached,
+		})
+	}
+
+	for _, v := range baseline {
+		add(2, v, TrialCompleted, false)
+	}
+
+	for _, v := range values {
+		add(1, v, TrialCompleted, false)
+	}
+
+	add(1, 1000, TrialFailed, false)
+	add(1, 1000, TrialCompleted, true)
+
+	return result
+}
+
+func TestBestConfidence(t *testing.T) {
+	t.Run("coverage", func(t *testing.T) {
+		const (
+			replications = 200
+			samples      = 20
+			level        = 0.9
+		)
+
+		for name, draw := range map[string]func(rng *rand.Rand) float64{
+			// Mean 10.
+			"normal": func(rng *rand.Rand) float64 { return 10 + 2*rng.NormFloat64() },
+			// Mean 10, skewed, as timings often are.
+			"exponential": func(rng *rand.Rand) float64 { return 5 + 5*rng.ExpFloat64() },
+		} {
+			rng := rand.New(rand.NewSource(1))
+
+			var covered int
+
+			for i := 0; i < replications; i++ {
+				values := make([]float64, samples)
+
+				for j := range values {
+					values[j] = draw(rng)
+				}
+
+				ci, err := measuredResult(values, nil).BestConfidence(level)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				assert.Equal(t, samples, ci.Samples, name)
+				assert.InDelta(t, mean(values), ci.Estimate, 1e-9, name)
+				assert.LessOrEqual(t, ci.Lower, ci.Upper, name)
+
+				if ci.Lower <= 10 && 10 <= ci.Upper {
+					covered++
+				}
+			}
+
+			// The percentile bootstrap is a little too narrow with 20
+			// samples, more so for skewed ones.
+			coverage := float64(covered) / replications
+
+			assert.Greater(t, coverage, 0.8, name)
+			assert.Less(t, coverage, 0.97, name)
+		}
+	})
+
+	t.Run("improvement", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+
+		best, baseline := make([]float64, 15), make([]float64, 10)
+
+		for i := range best {
+			best[i] = 8 + rng.NormFloat64()
+		}
+
+		for i := range baseline {
+			baseline[i] = 10 + rng.NormFloat64()
+		}
+
+		result := measuredResult(best, baseline)
+
+		ci, err := result.ImprovementConfidence(1, 0.95)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, 15, ci.Samples)
+		assert.Equal(t, 10, ci.BaselineSamples)
+		assert.InDelta(t, mean(baseline)-mean(best), ci.Estimate, 1e-9)
+		assert.Greater(t, ci.Lower, 0.0)
+		assert.Less(t, ci.Lower, 2.0)
+		assert.Greater(t, ci.Upper, 2.0)
+
+		// A trial of the best parameters as the baseline improves on
+		// nothing.
+		ci, err = result.ImprovementConfidence(11, 0.95)
+		if assert.NoError(t, err) {
+			assert.Zero(t, ci.Estimate)
+			assert.Less(t, ci.Lower, 0.0)
+			assert.Greater(t, ci.Upper, 0.0)
+		}
+
+		_, err = result.ImprovementConfidence(100, 0.95)
+		assert.ErrorIs(t, err, ErrUnknownTrial)
+
+		_, err = measuredResult(best, baseline[:1]).ImprovementConfidence(1, 0.95)
+		assert.ErrorIs(t, err, ErrTooFewMeasurements)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := measuredResult([]float64{1}, nil).BestConfidence(0.95)
+		assert.ErrorIs(t, err, ErrTooFewMeasurements)
+		assert.Contains(t, err.Error(), "have 1")
+
+		_, err = (&Result[float64]{}).BestConfidence(0.95)
+		assert.ErrorIs(t, err, ErrTooFewMeasurements)
+
+		for _, level := range []float64{0, 1, -0.5, math.NaN()} {
+			_, err = measuredResult([]float64{1, 2}, nil).BestConfidence(level)
+			assert.ErrorIs(t, err, ErrInvalidConfig, level)
+		}
+	})
+
+	t.Run("final progress update", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 1
+		config.InitialSamples = 5
+		config.Iterations = 12
+		config.IncumbentReevaluation = &IncumbentReevaluation{Every: 3}
+
+		progress := make(chan ProgressUpdate, 100)
+		config.ProgressChan = progress
+
+		rng := rand.New(rand.NewSource(1))
+
+		result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+			return (params[0]-3)*(params[0]-3) + 0.1*rng.NormFloat64(), nil
+		}, ParameterRange[float64]{Min: 0, Max: 10})
+
+		if !assert.NoError(t, result.Err) {
+			return
+		}
+
+		close(progress)
+
+		var final ProgressUpdate
+
+		for update := range progress {
+			final = update
+		}
+
+		want, err := result.BestConfidence(0.95)
+		if !assert.NoError(t, err) || !assert.NotNil(t, final.BestConfidence) {
+			return
+		}
+
+		assert.Equal(t, PhaseDone, final.Phase)
+		assert.Equal(t, want, *final.BestConfidence)
+		assert.Greater(t, want.Samples, 1)
+		assert.InDelta(t, result.BestTime, want.Estimate, 1e-9)
+		assert.Contains(t, final.String(), "95% CI")
+
+		data, err := json.Marshal(final)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		var decoded struct {
+			BestConfidence *ConfidenceInterval `json:"bestConfidence"`
+		}
+
+		if assert.NoError(t, json.Unmarshal(data, &decoded)) && assert.NotNil(t, decoded.BestConfidence) {
+			assert.Equal(t, want, *decoded.BestConfidence)
+		}
+	})
+}
@@ -0,0 +1,581 @@
+package ho
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"time"
+
+	"golang.org/x/exp/constraints"
+	"gopkg.in/yaml.v3"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// ParameterType is the type of a parameter of a SearchSpace.
+type ParameterType string
+
+const (
+	// IntParameter is an integer parameter.
+	IntParameter ParameterType = "int"
+
+	// FloatParameter is a floating point parameter.
+	FloatParameter ParameterType = "float"
+
+	// DurationParameter is a duration parameter, in nanoseconds, see
+	// DurationRange.
+	DurationParameter ParameterType = "duration"
+
+	// BoolParameter is an on/off parameter, 0 for false and 1 for true, see
+	// BoolParam. It takes no bounds.
+	BoolParameter ParameterType = "bool"
+
+	// EnumParameter chooses among named values, values being indexes into
+	// them, see EnumParam. It takes no bounds.
+	EnumParameter ParameterType = "enum"
+)
+
+// ParameterSpec is the declarative definition of a parameter, as found in
+// configuration files, see LoadConfig.
+type ParameterSpec struct {
+	// Name of the parameter, required and unique within the search space.
+	Name string `json:"name" yaml:"name"`
+
+	// Type of the parameter, "int", "float", "duration", "bool" or "enum".
+	// Bounds and steps of durations are in nanoseconds, bools and enums take
+	// none.
+	Type ParameterType `json:"type" yaml:"type"`
+
+	// Min is the minimum (inclusive) value.
+	Min float64 `json:"min" yaml:"min"`
+
+	// Max is the maximum (inclusive) value.
+	Max float64 `json:"max" yaml:"max"`
+
+	// Scale is "linear" (default) or "log". Log scale samples every order of
+	// magnitude equally, and requires a positive Min.
+	Scale string `json:"scale,omitempty" yaml:"scale,omitempty"`
+
+	// Step optionally restricts values to Min plus a multiple of Step, see
+	// ParameterRange.Step. Integer parameters always have a step of at least
+	// 1.
+	Step float64 `json:"step,omitempty" yaml:"step,omitempty"`
+
+	// Values are the choices of an enum parameter, e.g. ["zstd", "lz4"].
+	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+// SearchSpace is the declarative definition of a search space, as found in
+// configuration files, see LoadConfig. Use Ranges to get the ParameterRange
+// values to optimize.
+type SearchSpace struct {
+	// Parameters holds the parameters definitions, in order.
+	Parameters []ParameterSpec `json:"parameters" yaml:"parameters"`
+}
+
+// configDocument is the layout of configuration files.
+type configDocument struct {
+	Iterations               int                    `json:"iterations" yaml:"iterations"`
+	InitialSamples           int                    `json:"initialSamples" yaml:"initialSamples"`
+	NumCandidates            int                    `json:"numCandidates" yaml:"numCandidates"`
+	SelectionTime            duration               `json:"maxCandidateSelectionTime,omitempty" yaml:"maxCandidateSelectionTime,omitempty"`
+	Acquisition              acquisitionDocument    `json:"acquisition" yaml:"acquisition"`
+	Seed                     int64                  `json:"seed,omitempty" yaml:"seed,omitempty"`
+	MaxSkipRetries           int                    `json:"maxSkipRetries,omitempty" yaml:"maxSkipRetries,omitempty"`
+	NonFiniteValues          NonFinitePolicy        `json:"nonFiniteValues,omitempty" yaml:"nonFiniteValues,omitempty"`
+	SeedsPerTrial            int                    `json:"seedsPerTrial,omitempty" yaml:"seedsPerTrial,omitempty"`
+	SeedAggregation          SeedAggregation        `json:"seedAggregation,omitempty" yaml:"seedAggregation,omitempty"`
+	OutputTransform          OutputTransform        `json:"outputTransform,omitempty" yaml:"outputTransform,omitempty"`
+	FailedTrials             FailurePolicy          `json:"failedTrials,omitempty" yaml:"failedTrials,omitempty"`
+	TrialTimeout             duration               `json:"trialTimeout,omitempty" yaml:"trialTimeout,omitempty"`
+	TimeBudget               duration               `json:"timeBudget,omitempty" yaml:"timeBudget,omitempty"`
+	CooldownBetweenTrials    duration               `json:"cooldownBetweenTrials,omitempty" yaml:"cooldownBetweenTrials,omitempty"`
+	CooldownWithinTrials     duration               `json:"cooldownWithinTrials,omitempty" yaml:"cooldownWithinTrials,omitempty"`
+	HookScope                HookScope              `json:"hookScope,omitempty" yaml:"hookScope,omitempty"`
+	CountPausedTime          bool                   `json:"countPausedTime,omitempty" yaml:"countPausedTime,omitempty"`
+	MaxConcurrentEvaluations int                    `json:"maxConcurrentEvaluations,omitempty" yaml:"maxConcurrentEvaluations,omitempty"`
+	CacheEvaluations         bool                   `json:"cacheEvaluations,omitempty" yaml:"cacheEvaluations,omitempty"`
+	LeaseTimeout             duration               `json:"leaseTimeout,omitempty" yaml:"leaseTimeout,omitempty"`
+	AcceptLateTells          bool                   `json:"acceptLateTells,omitempty" yaml:"acceptLateTells,omitempty"`
+	ExclusionZones           []Box                  `json:"exclusionZones,omitempty" yaml:"exclusionZones,omitempty"`
+	Constraints              []Constraint           `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	Notifications            *notificationsDocument `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+	Parameters               []ParameterSpec        `json:"parameters" yaml:"parameters"`
+}
+
+// acquisitionDocument is the layout of the acquisition section of
+// configuration files.
+type acquisitionDocument struct {
+	Name  string  `json:"name" yaml:"name"`
+	Beta  float64 `json:"beta" yaml:"beta"`
+	Xi    float64 `json:"xi" yaml:"xi"`
+	Delta float64 `json:"delta,omitempty" yaml:"delta,omitempty"`
+}
+
+// notificationsDocument is the layout of the notifications section of
+// configuration files.
+type notificationsDocument struct {
+	URL          string            `json:"url" yaml:"url"`
+	Headers      map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Timeout      duration          `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	MaxRetries   int               `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+	RetryBackoff duration          `json:"retryBackoff,omitempty" yaml:"retryBackoff,omitempty"`
+	DrainTimeout duration          `json:"drainTimeout,omitempty" yaml:"drainTimeout,omitempty"`
+}
+
+// duration is a time.Duration written like "30s" in configuration files.
+type duration time.Duration
+
+//////
+// Methods.
+//////
+
+// Format renders a value of the parameter: durations like "250ms", bools as
+// "true" or "false", enums as the chosen value, integers rounded, floats in
+// the shortest representation.
+//
+// Parameters:
+// - v: The value, e.g. from TrialRecord.Params
+//
+// Returns:
+// - string: The rendered value.
+func (p ParameterSpec) Format(v float64) string {
+	switch p.Type {
+	case DurationParameter:
+		return time.Duration(math.Round(v)).String()
+	case BoolParameter:
+		return strconv.FormatBool(v >= 0.5)
+	case EnumParameter:
+		return enumValue(p.Values, v)
+	case IntParameter:
+		return strconv.FormatFloat(math.Round(v), 'f', -1, 64)
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// equal returns true if both parameters are defined the same.
+func (p ParameterSpec) equal(q ParameterSpec) bool {
+	return p.Name == q.Name &&
+		p.Type == q.Type &&
+		p.Min == q.Min &&
+		p.Max == q.Max &&
+		p.Scale == q.Scale &&
+		p.Step == q.Step &&
+		slices.Equal(p.Values, q.Values)
+}
+
+// integral returns true if values of the type are whole numbers.
+func (t ParameterType) integral() bool {
+	return t == IntParameter || t == DurationParameter || t == BoolParameter || t == EnumParameter
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *duration) UnmarshalYAML(node *yaml.Node) error {
+	var s string
+
+	if err := node.Decode(&s); err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+
+	*d = duration(v)
+
+	return nil
+}
+
+// Validate checks the search space.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig and pointing at the offending field,
+// e.g. "parameters[1].max", nil if valid.
+func (s SearchSpace) Validate() error {
+	if len(s.Parameters) == 0 {
+		return fmt.Errorf("%w: parameters: at least one parameter is required", ErrInvalidConfig)
+	}
+
+	names := make(map[string]bool, len(s.Parameters))
+
+	for i, p := range s.Parameters {
+		invalid := func(field, format string, args ...any) error {
+			return fmt.Errorf("%w: parameters[%d].%s: %s", ErrInvalidConfig, i, field, fmt.Sprintf(format, args...))
+		}
+
+		switch {
+		case p.Name == "":
+			return invalid("name", "required")
+		case names[p.Name]:
+			return invalid("name", "duplicate name %q", p.Name)
+		case p.Type != IntParameter && p.Type != FloatParameter && p.Type != DurationParameter && p.Type != BoolParameter && p.Type != EnumParameter:
+			return invalid("type", "expected %q, %q, %q, %q or %q, got %q", IntParameter, FloatParameter, DurationParameter, BoolParameter, EnumParameter, p.Type)
+		case p.Type == BoolParameter && (p.Min != 0 || (p.Max != 0 && p.Max != 1) || p.Step > 1 || p.Scale != ""):
+			return invalid("type", "bool parameters take no bounds, step nor scale")
+		case p.Type != EnumParameter && p.Values != nil:
+			return invalid("values", "only enum parameters take values")
+		case p.Type == EnumParameter && validateEnum(p.Values) != nil:
+			return invalid("values", "%v", validateEnum(p.Values))
+		case p.Type == EnumParameter && (p.Min != 0 || (p.Max != 0 && p.Max != float64(len(p.Values)-1)) || p.Step > 1 || p.Scale != ""):
+			return invalid("type", "enum parameters take no bounds, step nor scale")
+		case p.Min > p.Max:
+			return invalid("min", "%v is greater than max %v", p.Min, p.Max)
+		case p.Type.integral() && p.Min != math.Trunc(p.Min):
+			return invalid("min", "%v is not an integer", p.Min)
+		case p.Type.integral() && p.Max != math.Trunc(p.Max):
+			return invalid("max", "%v is not an integer", p.Max)
+		case p.Step < 0:
+			return invalid("step", "%v is negative", p.Step)
+		case p.Type.integral() && p.Step != math.Trunc(p.Step):
+			return invalid("step", "%v is not an integer", p.Step)
+		case p.Scale != "" && p.Scale != "linear" && p.Scale != "log":
+			return invalid("scale", "expected \"linear\" or \"log\", got %q", p.Scale)
+		case p.Scale == "log" && p.Min <= 0:
+			return invalid("scale", "log scale requires a positive min, got %v", p.Min)
+		}
+
+		names[p.Name] = true
+	}
+
+	return nil
+}
+
+// config validates the document and builds the OptimizationConfig.
+func (d *configDocument) config() (OptimizationConfig, error) {
+	config := DefaultConfig()
+
+	switch {
+	case d.Iterations < 0:
+		return config, fmt.Errorf("%w: iterations: %d is negative", ErrInvalidConfig, d.Iterations)
+	case d.InitialSamples < 1:
+		return config, fmt.Errorf("%w: initialSamples: must be at least 1, got %d", ErrInvalidConfig, d.InitialSamples)
+	case d.NumCandidates < 0:
+		return config, fmt.Errorf("%w: numCandidates: %d is negative", ErrInvalidConfig, d.NumCandidates)
+	case d.SelectionTime < 0:
+		return config, fmt.Errorf("%w: maxCandidateSelectionTime: %v is negative", ErrInvalidConfig, time.Duration(d.SelectionTime))
+	case d.MaxSkipRetries < 0:
+		return config, fmt.Errorf("%w: maxSkipRetries: %d is negative", ErrInvalidConfig, d.MaxSkipRetries)
+	case d.NonFiniteValues != "" && d.NonFiniteValues != NonFinitePenalize && d.NonFiniteValues != NonFiniteSkip:
+		return config, fmt.Errorf("%w: nonFiniteValues: expected %q or %q, got %q", ErrInvalidConfig, NonFinitePenalize, NonFiniteSkip, d.NonFiniteValues)
+	case d.SeedsPerTrial < 0:
+		return config, fmt.Errorf("%w: seedsPerTrial: %d is negative", ErrInvalidConfig, d.SeedsPerTrial)
+	case d.SeedAggregation.validate() != nil:
+		return config, fmt.Errorf("%w: seedAggregation: expected %q, %q or %q, got %q", ErrInvalidConfig, SeedMean, SeedMedian, SeedWorst, d.SeedAggregation)
+	case d.OutputTransform.validate() != nil:
+		return config, fmt.Errorf("%w: outputTransform: expected %q, %q, %q or %q, got %q", ErrInvalidConfig, OutputRaw, OutputLog, OutputStandardize, OutputRankGauss, d.OutputTransform)
+	case d.FailedTrials.validate() != nil:
+		return config, fmt.Errorf("%w: failedTrials: expected %q or %q, got %q", ErrInvalidConfig, FailureSubstitute, FailurePenalize, d.FailedTrials)
+	case d.HookScope.validate() != nil:
+		return config, fmt.Errorf("%w: hookScope: expected %q or %q, got %q", ErrInvalidConfig, HooksPerTrial, HooksPerMeasurement, d.HookScope)
+	case d.TrialTimeout < 0:
+		return config, fmt.Errorf("%w: trialTimeout: %v is negative", ErrInvalidConfig, time.Duration(d.TrialTimeout))
+	case d.TimeBudget < 0:
+		return config, fmt.Errorf("%w: timeBudget: %v is negative", ErrInvalidConfig, time.Duration(d.TimeBudget))
+	case d.CooldownBetweenTrials < 0:
+		return config, fmt.Errorf("%w: cooldownBetweenTrials: %v is negative", ErrInvalidConfig, time.Duration(d.CooldownBetweenTrials))
+	case d.CooldownWithinTrials < 0:
+		return config, fmt.Errorf("%w: cooldownWithinTrials: %v is negative", ErrInvalidConfig, time.Duration(d.CooldownWithinTrials))
+	case d.MaxConcurrentEvaluations < 0:
+		return config, fmt.Errorf("%w: maxConcurrentEvaluations: %d is negative", ErrInvalidConfig, d.MaxConcurrentEvaluations)
+	case d.LeaseTimeout < 0:
+		return config, fmt.Errorf("%w: leaseTimeout: %v is negative", ErrInvalidConfig, time.Duration(d.LeaseTimeout))
+	}
+
+	for i, zone := range d.ExclusionZones {
+		if err := zone.validate(len(d.Parameters)); err != nil {
+			return config, fmt.Errorf("exclusionZones[%d]: %w", i, err)
+		}
+	}
+
+	for i, c := range d.Constraints {
+		if err := c.validate(len(d.Parameters)); err != nil {
+			return config, fmt.Errorf("constraints[%d]: %w", i, err)
+		}
+	}
+
+	acquisition, ok := LookupAcquisition(d.Acquisition.Name)
+	if !ok {
+		return config, fmt.Errorf("%w: acquisition.name: unknown acquisition %q", ErrInvalidConfig, d.Acquisition.Name)
+	}
+
+	config.Iterations = d.Iterations
+	config.InitialSamples = d.InitialSamples
+	config.NumCandidates = d.NumCandidates
+	config.MaxCandidateSelectionTime = time.Duration(d.SelectionTime)
+	config.SetAcquisition(acquisition)
+	config.AcqParams.Beta = d.Acquisition.Beta
+	config.AcqParams.Xi = d.Acquisition.Xi
+	config.AcqParams.Delta = d.Acquisition.Delta
+	config.Seed = d.Seed
+	config.MaxSkipRetries = d.MaxSkipRetries
+	config.NonFiniteValues = d.NonFiniteValues
+	config.SeedsPerTrial = d.SeedsPerTrial
+	config.SeedAggregation = d.SeedAggregation
+	config.OutputTransform = d.OutputTransform
+	config.FailedTrials = d.FailedTrials
+	config.TrialTimeout = time.Duration(d.TrialTimeout)
+	config.TimeBudget = time.Duration(d.TimeBudget)
+	config.CooldownBetweenTrials = time.Duration(d.CooldownBetweenTrials)
+	config.CooldownWithinTrials = time.Duration(d.CooldownWithinTrials)
+	config.HookScope = d.HookScope
+	config.CountPausedTime = d.CountPausedTime
+	config.MaxConcurrentEvaluations = d.MaxConcurrentEvaluations
+	config.CacheEvaluations = d.CacheEvaluations
+	config.LeaseTimeout = time.Duration(d.LeaseTimeout)
+	config.AcceptLateTells = d.AcceptLateTells
+	config.ExclusionZones = d.ExclusionZones
+	config.Constraints = d.Constraints
+
+	if n := d.Notifications; n != nil {
+		config.Notifications = &Notifications{
+			URL:          n.URL,
+			Headers:      n.Headers,
+			Timeout:      time.Duration(n.Timeout),
+			MaxRetries:   n.MaxRetries,
+			RetryBackoff: time.Duration(n.RetryBackoff),
+			DrainTimeout: time.Duration(n.DrainTimeout),
+		}
+
+		if err := config.Notifications.validate(); err != nil {
+			return config, fmt.Errorf("notifications: %w", err)
+		}
+	}
+
+	if d.Seed != 0 {
+		config.AcqParams.RandomState = rand.New(rand.NewSource(d.Seed))
+	}
+
+	if err := config.Validate(); err != nil {
+		return config, fmt.Errorf("acquisition: %w", err)
+	}
+
+	return config, nil
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Ranges converts the search space to ParameterRange values, in order, to be
+// passed to Optimize and friends. The search space must be valid, see
+// SearchSpace.Validate.
+//
+// Type Parameter:
+//   - T: The numeric type for parameters, use float64 for spaces mixing int
+//     and float parameters
+//
+// Parameters:
+// - space: The search space
+//
+// Returns:
+// - []ParameterRange[T]: The parameter ranges, named after the parameters.
+func Ranges[T constraints.Integer | constraints.Float](space SearchSpace) []ParameterRange[T] {
+	ranges := make([]ParameterRange[T], len(space.Parameters))
+
+	for i, p := range space.Parameters {
+		step := p.Step
+
+		// Integer parameters stay integers, even with a float T.
+		if p.Type.integral() {
+			step = math.Max(step, 1)
+		}
+
+		ranges[i] = ParameterRange[T]{
+			Name: p.Name,
+			Type: p.Type,
+			Min:  fromFloat64[T](p.Min),
+			Max:  fromFloat64[T](p.Max),
+			Step: fromFloat64[T](step),
+		}
+
+		// Bools and enums take no bounds, they're 0 or 1, and indexes.
+		switch p.Type {
+		case BoolParameter:
+			ranges[i].Max = 1
+		case EnumParameter:
+			ranges[i].Max = fromFloat64[T](float64(len(p.Values) - 1))
+			ranges[i].Values = append([]string(nil), p.Values...)
+		}
+
+		if p.Scale == "log" {
+			ranges[i].Prior = LogUniform()
+		}
+	}
+
+	return ranges
+}
+
+// LoadConfig parses a declarative JSON or YAML document into an
+// OptimizationConfig plus a SearchSpace, so runs can be adjusted without code
+// changes. Settings missing from the document take their DefaultConfig value.
+//
+// Parameters:
+// - r: Reader of the document
+//
+// Returns:
+// - OptimizationConfig: The configuration
+// - SearchSpace: The search space, see Ranges
+// - error: Wrapping ErrInvalidConfig and pointing at the offending field if
+// the document is malformed or invalid
+//
+// Usage example:
+//
+//	// iterations: 50
+//	// initialSamples: 10
+//	// numCandidates: 100
+//	// acquisition:
+//	//   name: ExpectedImprovement
+//	//   xi: 0.01
+//	// seed: 42
+//	// trialTimeout: 30s
+//	// parameters:
+//	//   - {name: workers, type: int, min: 1, max: 32}
+//	//   - {name: bufferSize, type: int, min: 1024, max: 1048576, scale: log, step: 1024}
+//	f, err := os.Open("tuning.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//
+//	config, space, err := LoadConfig(f)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	result := Optimize(config, benchmark, Ranges[float64](space)...)
+//
+// Important notes:
+// - Acquisition functions are resolved by name, see Acquisitions and
+// RegisterAcquisition
+// - Unknown fields are rejected, so typos don't go unnoticed.
+func LoadConfig(r io.Reader) (OptimizationConfig, SearchSpace, error) {
+	defaults := DefaultConfig()
+
+	doc := configDocument{
+		Iterations:     defaults.Iterations,
+		InitialSamples: defaults.InitialSamples,
+		NumCandidates:  defaults.NumCandidates,
+		Acquisition: acquisitionDocument{
+			Name: "LowerConfidenceBound",
+			Beta: defaults.AcqParams.Beta,
+			Xi:   defaults.AcqParams.Xi,
+		},
+	}
+
+	decoder := yaml.NewDecoder(r)
+
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return defaults, SearchSpace{}, fmt.Errorf("%w: empty document", ErrInvalidConfig)
+		}
+
+		return defaults, SearchSpace{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	space := SearchSpace{Parameters: doc.Parameters}
+
+	if err := space.Validate(); err != nil {
+		return defaults, SearchSpace{}, err
+	}
+
+	config, err := doc.config()
+	if err != nil {
+		return defaults, SearchSpace{}, err
+	}
+
+	return config, space, nil
+}
+
+// SaveConfig writes the configuration and search space as a JSON document
+// that LoadConfig reads back.
+//
+// Parameters:
+// - w: Writer of the document
+// - config: The configuration
+// - space: The search space
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if the search space is invalid or the
+// acquisition function isn't registered, or the write error
+//
+// Important notes:
+// - Settings that can't be written declaratively (ProgressChan,
+// CandidateFilter, Setup, Teardown, CandidateMix, KnownOptimum,
+// AcquisitionFuncEx, priors other than log scale, Notifications.ErrorLog,
+// Trackers, Safety) are not saved.
+func SaveConfig(w io.Writer, config OptimizationConfig, space SearchSpace) error {
+	if err := space.Validate(); err != nil {
+		return err
+	}
+
+	acquisition, ok := acquisitionOf(config)
+	if !ok {
+		return fmt.Errorf("%w: acquisition: the acquisition function isn't registered", ErrInvalidConfig)
+	}
+
+	doc := configDocument{
+		Iterations:     config.Iterations,
+		InitialSamples: config.InitialSamples,
+		NumCandidates:  config.NumCandidates,
+		Acquisition: acquisitionDocument{
+			Name:  acquisition.Name,
+			Beta:  config.AcqParams.Beta,
+			Xi:    config.AcqParams.Xi,
+			Delta: config.AcqParams.Delta,
+		},
+		SelectionTime:            duration(config.MaxCandidateSelectionTime),
+		Seed:                     config.Seed,
+		MaxSkipRetries:           config.MaxSkipRetries,
+		NonFiniteValues:          config.NonFiniteValues,
+		SeedsPerTrial:            config.SeedsPerTrial,
+		SeedAggregation:          config.SeedAggregation,
+		OutputTransform:          config.OutputTransform,
+		FailedTrials:             config.FailedTrials,
+		TrialTimeout:             duration(config.TrialTimeout),
+		TimeBudget:               duration(config.TimeBudget),
+		CooldownBetweenTrials:    duration(config.CooldownBetweenTrials),
+		CooldownWithinTrials:     duration(config.CooldownWithinTrials),
+		HookScope:                config.HookScope,
+		CountPausedTime:          config.CountPausedTime,
+		MaxConcurrentEvaluations: config.MaxConcurrentEvaluations,
+		CacheEvaluations:         config.CacheEvaluations,
+		LeaseTimeout:             duration(config.LeaseTimeout),
+		AcceptLateTells:          config.AcceptLateTells,
+		ExclusionZones:           config.ExclusionZones,
+		Constraints:              config.Constraints,
+		Parameters:               space.Parameters,
+	}
+
+	if n := config.Notifications; n != nil {
+		doc.Notifications = &notificationsDocument{
+			URL:          n.URL,
+			Headers:      n.Headers,
+			Timeout:      duration(n.Timeout),
+			MaxRetries:   n.MaxRetries,
+			RetryBackoff: duration(n.RetryBackoff),
+			DrainTimeout: duration(n.DrainTimeout),
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
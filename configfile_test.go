@@ -0,0 +1,258 @@
+package ho
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlConfig = `
+iterations: 20
+initialSamples: 5
+numCandidates: 30
+maxCandidateSelectionTime: 50ms
+acquisition:
+  name: ExpectedImprovementMin
+  xi: 0.05
+seed: 42
+trialTimeout: 30s
+nonFiniteValues: Skip
+seedsPerTrial: 5
+seedAggregation: Worst
+outputTransform: RankGauss
+failedTrials: Penalize
+hookScope: PerMeasurement
+maxSkipRetries: 2
+cacheEvaluations: true
+leaseTimeout: 2h
+acceptLateTells: true
+notifications:
+  url: https://chat.example.com/hooks/ho
+  headers: {Authorization: Bearer token}
+  timeout: 5s
+  maxRetries: 3
+  drainTimeout: 1m
+exclusionZones:
+  - {min: [16, 0], max: [32, 1024]}
+constraints:
+  - {kind: SumAtMost, dims: [0, 1], limit: 500000}
+parameters:
+  - name: workers
+    type: int
+    min: 1
+    max: 32
+  - name: bufferSize
+    type: int
+    min: 1024
+    max: 1048576
+    scale: log
+    step: 1024
+`
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		config, space, err := LoadConfig(strings.NewReader(yamlConfig))
+		assert.NoError(t, err)
+
+		assert.Equal(t, 20, config.Iterations)
+		assert.Equal(t, 5, config.InitialSamples)
+		assert.Equal(t, 30, config.NumCandidates)
+		assert.Equal(t, 50*time.Millisecond, config.MaxCandidateSelectionTime)
+		assert.Equal(t, MaximizeAcquisition, config.AcquisitionDirection)
+		assert.Equal(t, 0.05, config.AcqParams.Xi)
+		assert.Equal(t, 2.0, config.AcqParams.Beta) // Default.
+		assert.Equal(t, int64(42), config.Seed)
+		assert.Equal(t, 30*time.Second, config.TrialTimeout)
+		assert.Equal(t, NonFiniteSkip, config.NonFiniteValues)
+		assert.Equal(t, 5, config.SeedsPerTrial)
+		assert.Equal(t, SeedWorst, config.SeedAggregation)
+		assert.Equal(t, OutputRankGauss, config.OutputTransform)
+		assert.Equal(t, FailurePenalize, config.FailedTrials)
+		assert.Equal(t, HooksPerMeasurement, config.HookScope)
+		assert.Equal(t, 2, config.MaxSkipRetries)
+		assert.True(t, config.CacheEvaluations)
+		assert.Equal(t, 2*time.Hour, config.LeaseTimeout)
+		assert.True(t, config.AcceptLateTells)
+		assert.Equal(t, &Notifications{
+			URL:          "https://chat.example.com/hooks/ho",
+			Headers:      map[string]string{"Authorization": "Bearer token"},
+			Timeout:      5 * time.Second,
+			MaxRetries:   3,
+			DrainTimeout: time.Minute,
+		}, config.Notifications)
+		assert.Equal(t, []Box{{Min: []float64{16, 0}, Max: []float64{32, 1024}}}, config.ExclusionZones)
+		assert.Equal(t, []Constraint{SumAtMost([]int{0, 1}, 500000)}, config.Constraints)
+
+		assert.Equal(t, []ParameterSpec{
+			{Name: "workers", Type: IntParameter, Min: 1, Max: 32},
+			{Name: "bufferSize", Type: IntParameter, Min: 1024, Max: 1048576, Scale: "log", Step: 1024},
+		}, space.Parameters)
+
+		ranges := Ranges[float64](space)
+
+		assert.Equal(t, ParameterRange[float64]{Name: "workers", Type: IntParameter, Min: 1, Max: 32, Step: 1}, ranges[0])
+		assert.Equal(t, 1024.0, ranges[1].Step)
+		assert.Equal(t, LogUniform(), ranges[1].Prior)
+	})
+
+	t.Run("json with defaults", func(t *testing.T) {
+		config, space, err := LoadConfig(strings.NewReader(
+			`{"parameters": [{"name": "rate", "type": "float", "min": 0.1, "max": 1}]}`,
+		))
+		assert.NoError(t, err)
+
+		defaults := DefaultConfig()
+
+		assert.Equal(t, defaults.Iterations, config.Iterations)
+		assert.Equal(t, defaults.InitialSamples, config.InitialSamples)
+		assert.Equal(t, defaults.NumCandidates, config.NumCandidates)
+		assert.Equal(t, MinimizeAcquisition, config.AcquisitionDirection)
+		assert.Len(t, space.Parameters, 1)
+	})
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	param := "parameters: [{name: a, type: int, min: 1, max: 2}]\n"
+
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{name: "empty", doc: "", want: "empty document"},
+		{name: "syntax", doc: "iterations: [", want: "yaml"},
+		{name: "unknown field", doc: "iteration: 5\n" + param, want: "iteration"},
+		{name: "wrong type", doc: "iterations: many\n" + param, want: "line 1"},
+		{name: "invalid duration", doc: "trialTimeout: soon\n" + param, want: "soon"},
+		{name: "negative iterations", doc: "iterations: -1\n" + param, want: "iterations:"},
+		{name: "unknown non-finite policy", doc: "nonFiniteValues: Ignore\n" + param, want: "nonFiniteValues:"},
+		{name: "invalid acquisition parameter", doc: "acquisition: {name: ExpectedImprovement, xi: -1}\n" + param, want: "AcqParams.Xi -1"},
+		{name: "negative seeds per trial", doc: "seedsPerTrial: -1\n" + param, want: "seedsPerTrial:"},
+		{name: "unknown seed aggregation", doc: "seedAggregation: Best\n" + param, want: "seedAggregation:"},
+		{name: "unknown output transform", doc: "outputTransform: Sqrt\n" + param, want: "outputTransform:"},
+		{name: "unknown failure policy", doc: "failedTrials: Ignore\n" + param, want: "failedTrials:"},
+		{name: "unknown hook scope", doc: "hookScope: PerRun\n" + param, want: "hookScope:"},
+		{name: "no initial samples", doc: "initialSamples: 0\n" + param, want: "initialSamples:"},
+		{name: "negative candidates", doc: "numCandidates: -1\n" + param, want: "numCandidates:"},
+		{name: "unknown acquisition", doc: "acquisition: {name: Magic}\n" + param, want: "acquisition.name:"},
+		{name: "invalid zone", doc: "exclusionZones: [{min: [1], max: [2, 3]}]\n" + param, want: "exclusionZones[0]:"},
+		{name: "invalid constraint", doc: "constraints: [{kind: LessOrEqual, dims: [0, 1]}]\n" + param, want: "constraints[0]:"},
+		{name: "notifications without url", doc: "notifications: {maxRetries: 2}\n" + param, want: "notifications:"},
+		{name: "no parameters", doc: "iterations: 5", want: "parameters:"},
+		{name: "missing name", doc: "parameters: [{type: int, min: 1, max: 2}]", want: "parameters[0].name:"},
+		{
+			name: "duplicate name",
+			doc:  "parameters: [{name: a, type: int, min: 1, max: 2}, {name: a, type: int, min: 1, max: 2}]",
+			want: "parameters[1].name:",
+		},
+		{name: "unknown type", doc: "parameters: [{name: a, type: string, min: 1, max: 2}]", want: "parameters[0].type:"},
+		{name: "min greater than max", doc: "parameters: [{name: a, type: int, min: 3, max: 2}]", want: "parameters[0].min:"},
+		{name: "fractional int", doc: "parameters: [{name: a, type: int, min: 1, max: 2.5}]", want: "parameters[0].max:"},
+		{name: "fractional duration", doc: "parameters: [{name: a, type: duration, min: 1, max: 2e6, step: 0.5}]", want: "parameters[0].step:"},
+		{name: "bool with bounds", doc: "parameters: [{name: a, type: bool, min: 1, max: 2}]", want: "parameters[0].type:"},
+		{name: "negative step", doc: "parameters: [{name: a, type: float, min: 1, max: 2, step: -1}]", want: "parameters[0].step:"},
+		{name: "unknown scale", doc: "parameters: [{name: a, type: float, min: 1, max: 2, scale: exp}]", want: "parameters[0].scale:"},
+		{name: "log scale at zero", doc: "parameters: [{name: a, type: float, min: 0, max: 2, scale: log}]", want: "parameters[0].scale:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := LoadConfig(strings.NewReader(tt.doc))
+
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestSaveConfig(t *testing.T) {
+	config, space, err := LoadConfig(strings.NewReader(yamlConfig))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, SaveConfig(&buf, config, space))
+
+	reloaded, reloadedSpace, err := LoadConfig(&buf)
+	assert.NoError(t, err)
+
+	assert.Equal(t, space, reloadedSpace)
+	assert.Equal(t, config.Iterations, reloaded.Iterations)
+	assert.Equal(t, config.AcqParams.Xi, reloaded.AcqParams.Xi)
+	assert.Equal(t, config.AcquisitionDirection, reloaded.AcquisitionDirection)
+	assert.Equal(t, config.Seed, reloaded.Seed)
+	assert.Equal(t, config.TrialTimeout, reloaded.TrialTimeout)
+	assert.Equal(t, config.MaxCandidateSelectionTime, reloaded.MaxCandidateSelectionTime)
+	assert.Equal(t, config.LeaseTimeout, reloaded.LeaseTimeout)
+	assert.Equal(t, config.AcceptLateTells, reloaded.AcceptLateTells)
+	assert.Equal(t, config.Notifications, reloaded.Notifications)
+	assert.Equal(t, config.ExclusionZones, reloaded.ExclusionZones)
+	assert.Equal(t, config.Constraints, reloaded.Constraints)
+
+	// Unregistered acquisition functions can't be saved.
+	config.AcquisitionFunc = func(mean, variance float64, params AcquisitionParams) float64 { return mean }
+
+	assert.ErrorIs(t, SaveConfig(&buf, config, space), ErrInvalidConfig)
+}
+
+func TestRegisterAcquisition(t *testing.T) {
+	greedy := Acquisition{
+		Name:      "TestGreedy",
+		Func:      func(mean, variance float64, params AcquisitionParams) float64 { return mean },
+		Direction: MinimizeAcquisition,
+	}
+
+	if !assert.NoError(t, RegisterAcquisition(greedy)) {
+		return
+	}
+
+	t.Cleanup(func() { UnregisterAcquisition(greedy.Name) })
+
+	assert.Error(t, RegisterAcquisition(greedy))
+	assert.Error(t, RegisterAcquisition(Acquisition{Name: "TestNil"}))
+
+	config, _, err := LoadConfig(strings.NewReader(
+		"acquisition: {name: TestGreedy}\nparameters: [{name: a, type: int, min: 1, max: 2}]",
+	))
+	assert.NoError(t, err)
+
+	acquisition, ok := acquisitionOf(config)
+	assert.True(t, ok)
+	assert.Equal(t, "TestGreedy", acquisition.Name)
+
+	// Built-ins stay.
+	assert.False(t, UnregisterAcquisition("ExpectedImprovement"))
+	assert.False(t, UnregisterAcquisition("TestNil"))
+}
+
+func TestStepAndSeed(t *testing.T) {
+	assert.Equal(t, 1.5, snapToStep(ParameterRange[float64]{Min: 0.5, Max: 2, Step: 0.5}, 1.6))
+	assert.Equal(t, 1.5, snapToStep(ParameterRange[float64]{Min: 0.5, Max: 1.8, Step: 0.5}, 1.8))
+	assert.Equal(t, 12, snapToStep(ParameterRange[int]{Min: 4, Max: 20, Step: 4}, 13))
+
+	config := fastConfig()
+	config.Seed = 7
+
+	run := func() *Result[float64] {
+		return OptimizeObjective(config, func(params ...float64) (float64, error) {
+			return params[0], nil
+		}, ParameterRange[float64]{Min: 0, Max: 10, Step: 0.25})
+	}
+
+	first := run()
+
+	for _, trial := range first.Trials {
+		assert.Equal(t, 0.0, math.Mod(trial.Params[0], 0.25))
+	}
+
+	// Same seed, same trials.
+	second := run()
+
+	for i := range first.Trials {
+		assert.Equal(t, first.Trials[i].Params, second.Trials[i].Params)
+	}
+}
@@ -0,0 +1,163 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// ConstraintKind is the relation a Constraint enforces.
+type ConstraintKind string
+
+const (
+	// ConstraintLessOrEqual requires the first dimension to be less than or
+	// equal to the second, see LessOrEqual.
+	ConstraintLessOrEqual ConstraintKind = "LessOrEqual"
+
+	// ConstraintSumAtMost requires the sum of the dimensions to be at most
+	// the limit, see SumAtMost.
+	ConstraintSumAtMost ConstraintKind = "SumAtMost"
+)
+
+// Constraint is a relation between parameters that every evaluated point
+// satisfies, e.g. a minimum pool size that can't exceed the maximum one,
+// which per-dimension ranges can't express. See
+// OptimizationConfig.Constraints.
+//
+// Usage example:
+//
+//	// minConns <= maxConns, and both pools fit in 64 connections.
+//	config.Constraints = []Constraint{
+//	    LessOrEqual(0, 1),
+//	    SumAtMost([]int{1, 2}, 64),
+//	}
+//
+// Validation:
+// - Dims must hold valid, distinct parameter indices: two of them for
+// ConstraintLessOrEqual, at least one for ConstraintSumAtMost
+// - Limit must be finite.
+type Constraint struct {
+	// Kind is the relation.
+	Kind ConstraintKind `json:"kind" yaml:"kind"`
+
+	// Dims holds the 0-based indices of the constrained parameters, in the
+	// order of the relation.
+	Dims []int `json:"dims" yaml:"dims"`
+
+	// Limit is the bound of ConstraintSumAtMost, unused otherwise.
+	Limit float64 `json:"limit,omitempty" yaml:"limit,omitempty"`
+}
+
+//////
+// Methods.
+//////
+
+// Satisfied returns true if the point satisfies the constraint.
+//
+// Parameters:
+// - point: Coordinates of the point, one value per dimension
+//
+// Returns:
+// - bool: Whether the point satisfies the relation.
+func (c Constraint) Satisfied(point []float64) bool {
+	switch c.Kind {
+	case ConstraintLessOrEqual:
+		return point[c.Dims[0]] <= point[c.Dims[1]]
+	case ConstraintSumAtMost:
+		var sum float64
+
+		for _, d := range c.Dims {
+			sum += point[d]
+		}
+
+		return sum <= c.Limit
+	default:
+		return false
+	}
+}
+
+// validate checks the constraint against the number of dimensions of the
+// search space.
+func (c Constraint) validate(dimensions int) error {
+	switch c.Kind {
+	case ConstraintLessOrEqual:
+		if len(c.Dims) != 2 {
+			return fmt.Errorf("%w: constraint %s must have 2 dimensions, got %d", ErrInvalidConfig, c.Kind, len(c.Dims))
+		}
+	case ConstraintSumAtMost:
+		if len(c.Dims) == 0 {
+			return fmt.Errorf("%w: constraint %s has no dimensions", ErrInvalidConfig, c.Kind)
+		}
+
+		if math.IsNaN(c.Limit) || math.IsInf(c.Limit, 0) {
+			return fmt.Errorf("%w: constraint %s limit %v isn't finite", ErrInvalidConfig, c.Kind, c.Limit)
+		}
+	default:
+		return fmt.Errorf("%w: unknown constraint kind %q", ErrInvalidConfig, c.Kind)
+	}
+
+	seen := make(map[int]bool, len(c.Dims))
+
+	for _, d := range c.Dims {
+		switch {
+		case d < 0 || d >= dimensions:
+			return fmt.Errorf("%w: constraint %s dimension %d out of [0, %d)", ErrInvalidConfig, c.Kind, d, dimensions)
+		case seen[d]:
+			return fmt.Errorf("%w: constraint %s repeats dimension %d", ErrInvalidConfig, c.Kind, d)
+		}
+
+		seen[d] = true
+	}
+
+	return nil
+}
+
+// feasible returns true if the point lies outside of the exclusion zones, and
+// satisfies the constraints, see OptimizationConfig.Constraints.
+func (o *optimizer[T]) feasible(params []T) bool {
+	point := paramsToFloat64s(params)
+
+	if inExclusionZone(o.config.ExclusionZones, point) {
+		return false
+	}
+
+	for _, c := range o.config.Constraints {
+		if !c.Satisfied(point) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//////
+// Factory.
+//////
+
+// LessOrEqual creates a constraint requiring the parameter a to be less than
+// or equal to the parameter b, e.g. a minimum and a maximum pool size.
+//
+// Parameters:
+// - a, b: The 0-based indices of the parameters
+//
+// Returns:
+// - Constraint: The constraint.
+func LessOrEqual(a, b int) Constraint {
+	return Constraint{Kind: ConstraintLessOrEqual, Dims: []int{a, b}}
+}
+
+// SumAtMost creates a constraint requiring the sum of the parameters to be at
+// most limit, e.g. pools sharing a connection budget.
+//
+// Parameters:
+// - dims: The 0-based indices of the parameters
+// - limit: The largest sum allowed
+//
+// Returns:
+// - Constraint: The constraint.
+func SumAtMost(dims []int, limit float64) Constraint {
+	return Constraint{Kind: ConstraintSumAtMost, Dims: append([]int(nil), dims...), Limit: limit}
+}
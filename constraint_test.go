@@ -0,0 +1,165 @@
+package ho
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// poolRanges are minimum and maximum pool sizes, and a worker count.
+var poolRanges = []ParameterRange[int]{
+	{Name: "minConns", Min: 1, Max: 100},
+	{Name: "maxConns", Min: 1, Max: 100},
+	{Name: "workers", Min: 1, Max: 100},
+}
+
+// poolConstraints order the pool sizes, and cap the connections and workers.
+var poolConstraints = []Constraint{
+	LessOrEqual(0, 1),
+	SumAtMost([]int{1, 2}, 120),
+}
+
+// violations returns the constraints the point violates.
+func violations(constraints []Constraint, point []float64) []Constraint {
+	var violated []Constraint
+
+	for _, c := range constraints {
+		if !c.Satisfied(point) {
+			violated = append(violated, c)
+		}
+	}
+
+	return violated
+}
+
+func TestConstraints(t *testing.T) {
+	t.Run("sampling", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 1
+		config.Constraints = poolConstraints
+
+		o := newOptimizer[int](context.Background(), config, nil, poolRanges...)
+
+		if !assert.NoError(t, o.validate()) {
+			return
+		}
+
+		for i := 0; i < 5000; i++ {
+			point := paramsToFloat64s(o.randomParams())
+
+			assert.Empty(t, violations(poolConstraints, point), point)
+		}
+
+		// Both constraints rule out much of the box, so they compose rather
+		// than one of them being vacuous.
+		violated := map[ConstraintKind]int{}
+
+		for i := 0; i < 5000; i++ {
+			for _, c := range violations(poolConstraints, paramsToFloat64s(o.drawParams())) {
+				violated[c.Kind]++
+			}
+		}
+
+		assert.Greater(t, violated[ConstraintLessOrEqual], 1000)
+		assert.Greater(t, violated[ConstraintSumAtMost], 1000)
+	})
+
+	t.Run("rejection keeps samples uniform", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 1
+		config.Constraints = []Constraint{LessOrEqual(0, 1)}
+
+		o := newOptimizer[float64](context.Background(), config, nil, ParameterRange[float64]{Min: 0, Max: 1}, ParameterRange[float64]{Min: 0, Max: 1})
+
+		var a, b float64
+
+		const draws = 5000
+
+		for i := 0; i < draws; i++ {
+			params := o.randomParams()
+
+			a += params[0] / draws
+			b += params[1] / draws
+		}
+
+		// Uniform over the triangle below the diagonal.
+		assert.InDelta(t, 1.0/3, a, 0.02)
+		assert.InDelta(t, 2.0/3, b, 0.02)
+	})
+
+	t.Run("run", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 1
+		config.InitialSamples = 10
+		config.Iterations = 40
+		config.NumCandidates = 100
+		config.CandidateMix = CandidateMix{Incumbent: 0.5, TopK: 0.2}
+		config.Constraints = poolConstraints
+
+		// Every candidate reaches the filter, perturbations included.
+		var candidates int
+
+		config.CandidateFilter = func(candidate []float64) bool {
+			candidates++
+
+			assert.Empty(t, violations(poolConstraints, candidate), candidate)
+
+			return true
+		}
+
+		var benchmarked int
+
+		result := OptimizeObjective(config, func(params ...int) (float64, error) {
+			benchmarked++
+
+			assert.Empty(t, violations(poolConstraints, paramsToFloat64s(params)), params)
+
+			// Best with large, equal pools and few workers: at the
+			// constraint boundaries.
+			return float64((params[1]-params[0])*(params[1]-params[0]) + (100 - params[1]) + params[2]), nil
+		}, poolRanges...)
+
+		if !assert.NoError(t, result.Err) {
+			return
+		}
+
+		assert.Equal(t, config.InitialSamples+config.Iterations, benchmarked)
+		assert.Greater(t, candidates, 3000)
+
+		for _, trial := range result.Trials {
+			assert.Empty(t, violations(poolConstraints, paramsToFloat64s(trial.Params)), trial.Params)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, constraints := range map[string][]Constraint{
+			"unknown kind":       {{Kind: "GreaterThan", Dims: []int{0, 1}}},
+			"one dimension":      {{Kind: ConstraintLessOrEqual, Dims: []int{0}}},
+			"no dimensions":      {SumAtMost(nil, 10)},
+			"out of range":       {LessOrEqual(0, 3)},
+			"negative dimension": {SumAtMost([]int{-1}, 10)},
+			"repeated dimension": {SumAtMost([]int{1, 1}, 10)},
+			"infinite limit":     {{Kind: ConstraintSumAtMost, Dims: []int{0}, Limit: math.Inf(1)}},
+			"infeasible":         {SumAtMost([]int{0, 1, 2}, 2)},
+		} {
+			config := fastConfig()
+			config.Constraints = constraints
+
+			result := Optimize(config, func(...int) error { return nil }, poolRanges...)
+
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig, name)
+			assert.Empty(t, result.Trials, name)
+		}
+
+		// The drift reference, the middle of the space by default, is
+		// benchmarked too.
+		config := fastConfig()
+		config.Constraints = []Constraint{SumAtMost([]int{0, 1, 2}, 100)}
+		config.DriftSentinel = &DriftSentinel{}
+
+		result := Optimize(config, func(...int) error { return nil }, poolRanges...)
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+}
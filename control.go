@@ -0,0 +1,319 @@
+package ho
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// RunState is the state of a run, see RunHandle.State.
+type RunState string
+
+const (
+	// RunRunning means the run starts trials as usual.
+	RunRunning RunState = "Running"
+
+	// RunPaused means the run doesn't start any trial until resumed.
+	RunPaused RunState = "Paused"
+
+	// RunStopping means the run was stopped, and waits for running trials.
+	RunStopping RunState = "Stopping"
+
+	// RunDone means the run ended.
+	RunDone RunState = "Done"
+)
+
+// runControl is the state machine controlling a run: Running and Paused
+// alternate until Stopping, and the run ends in Done. Stopping and Done are
+// final. It's safe for concurrent use.
+type runControl struct {
+	// mu protects the fields below.
+	mu sync.Mutex
+
+	// state is the current state.
+	state RunState
+
+	// stopped is true if a stop was requested before the run ended.
+	stopped bool
+
+	// resumed is closed when the run leaves the Paused state.
+	resumed chan struct{}
+
+	// startedAt is when the run started.
+	startedAt time.Time
+
+	// pausedAt is when the run was last paused.
+	pausedAt time.Time
+
+	// paused is the time spent paused, the current pause excluded.
+	paused time.Duration
+}
+
+// RunHandle controls a run started with Start or StartObjective, from any
+// goroutine.
+//
+// Important notes:
+// - Pause, Resume and Stop are idempotent, and have no effect once the run
+// ended
+// - Running trials are never interrupted: pausing or stopping only prevents
+// further trials from starting. Use the run context to interrupt them.
+//
+// Thread safety:
+// - All methods are safe for concurrent use.
+type RunHandle[T constraints.Integer | constraints.Float] struct {
+	// o is the run.
+	o *optimizer[T]
+
+	// done is closed when the run ended.
+	done chan struct{}
+
+	// result is the outcome of the run, set before done is closed.
+	result *Result[T]
+}
+
+//////
+// Methods.
+//////
+
+// start marks the start of the run, for TimeBudget. Pauses before the start
+// only count from there.
+func (c *runControl) start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.startedAt = time.Now()
+	c.pausedAt = c.startedAt
+	c.paused = 0
+}
+
+// pause moves from Running to Paused.
+func (c *runControl) pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != RunRunning {
+		return
+	}
+
+	c.state = RunPaused
+	c.pausedAt = time.Now()
+	c.resumed = make(chan struct{})
+}
+
+// resume moves from Paused to Running.
+func (c *runControl) resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != RunPaused {
+		return
+	}
+
+	c.leavePause()
+
+	c.state = RunRunning
+}
+
+// stop moves from Running or Paused to Stopping.
+func (c *runControl) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case RunPaused:
+		c.leavePause()
+	case RunStopping, RunDone:
+		return
+	}
+
+	c.state = RunStopping
+	c.stopped = true
+}
+
+// finish moves to Done, once the run ended.
+func (c *runControl) finish() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == RunPaused {
+		c.leavePause()
+	}
+
+	c.state = RunDone
+}
+
+// leavePause accounts for the current pause, and wakes up the goroutines
+// waiting for it to end. The caller must hold mu.
+func (c *runControl) leavePause() {
+	c.paused += time.Since(c.pausedAt)
+
+	close(c.resumed)
+}
+
+// current returns the current state.
+func (c *runControl) current() RunState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+// stopRequested returns true if a stop was requested before the run ended.
+func (c *runControl) stopRequested() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stopped
+}
+
+// await blocks while the run is paused, or until ctx is done.
+func (c *runControl) await(ctx context.Context) {
+	c.mu.Lock()
+
+	if c.state != RunPaused {
+		c.mu.Unlock()
+
+		return
+	}
+
+	resumed := c.resumed
+
+	c.mu.Unlock()
+
+	select {
+	case <-resumed:
+	case <-ctx.Done():
+	}
+}
+
+// elapsed returns the wall time since the start of the run.
+//
+// Parameters:
+// - countPaused: Whether time spent paused counts
+//
+// Returns:
+// - time.Duration: The elapsed time.
+func (c *runControl) elapsed(countPaused bool) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elapsed := time.Since(c.startedAt)
+
+	if countPaused {
+		return elapsed
+	}
+
+	paused := c.paused
+
+	if c.state == RunPaused {
+		paused += time.Since(c.pausedAt)
+	}
+
+	return elapsed - paused
+}
+
+// Pause stops starting trials until Resume, e.g. to free the system under
+// test for a while. Running trials complete as usual.
+func (h *RunHandle[T]) Pause() {
+	h.o.control.pause()
+}
+
+// Resume resumes a paused run.
+func (h *RunHandle[T]) Resume() {
+	h.o.control.resume()
+}
+
+// Stop stops the run, paused or not, and waits for it to end: running trials
+// complete, but no further trial starts.
+//
+// Returns:
+// - *Result[T]: The outcome of the run, with termination reason
+// TerminationStopped unless it ended before.
+func (h *RunHandle[T]) Stop() *Result[T] {
+	h.o.control.stop()
+
+	return h.Wait()
+}
+
+// Wait waits for the run to end.
+//
+// Returns:
+// - *Result[T]: The outcome of the run.
+func (h *RunHandle[T]) Wait() *Result[T] {
+	<-h.done
+
+	return h.result
+}
+
+// Done returns a channel closed when the run ended, e.g. to wait for it in a
+// select statement.
+func (h *RunHandle[T]) Done() <-chan struct{} {
+	return h.done
+}
+
+// State returns the state of the run.
+func (h *RunHandle[T]) State() RunState {
+	return h.o.control.current()
+}
+
+// proceed waits while the run is paused, measures the drift sentinel if
+// due, waits for the cooldown between trials, and returns true if it may
+// start a further trial.
+func (o *optimizer[T]) proceed() bool {
+	o.control.await(o.ctx)
+
+	o.watchDrift()
+
+	// Canceling the run context cuts the cooldown short, and ends the run.
+	_ = o.cooldown(false)
+
+	return !o.done()
+}
+
+// interrupted returns true if the run was stopped or TimeBudget elapsed, and
+// records it so the run ends with the matching termination reason. The
+// caller must hold mu.
+func (o *optimizer[T]) interrupted() bool {
+	if o.control.stopRequested() {
+		o.stopped = true
+	}
+
+	if o.config.TimeBudget > 0 && o.control.elapsed(o.config.CountPausedTime) >= o.config.TimeBudget {
+		o.outOfTime = true
+	}
+
+	return o.stopped || o.outOfTime
+}
+
+//////
+// Factory.
+//////
+
+// newRunControl creates the control of a run, Running.
+func newRunControl() *runControl {
+	return &runControl{state: RunRunning}
+}
+
+// startRun runs the optimization in the background.
+func startRun[T constraints.Integer | constraints.Float](o *optimizer[T]) *RunHandle[T] {
+	h := &RunHandle[T]{
+		o:    o,
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(h.done)
+
+		h.result = o.run()
+
+		o.control.finish()
+	}()
+
+	return h
+}
@@ -0,0 +1,163 @@
+package ho
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingObjective returns an objective blocking its 3rd call until release
+// is closed, signaling reached when it gets there, and counting its calls.
+func blockingObjective(calls *atomic.Int32, reached, release chan struct{}) ObjectiveFuncCtx[float64] {
+	return func(ctx context.Context, params ...float64) (float64, error) {
+		if calls.Add(1) == 3 {
+			close(reached)
+
+			<-release
+		}
+
+		return math.Pow(params[0]-3, 2), nil
+	}
+}
+
+func TestRunHandle(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	t.Run("pause and resume", func(t *testing.T) {
+		var calls atomic.Int32
+
+		reached, release := make(chan struct{}), make(chan struct{})
+
+		run := StartObjective(context.Background(), fastConfig(), blockingObjective(&calls, reached, release), ranges...)
+
+		<-reached
+
+		run.Pause()
+		run.Pause()
+
+		assert.Equal(t, RunPaused, run.State())
+
+		// The running trial completes, but no other starts.
+		close(release)
+
+		time.Sleep(50 * time.Millisecond)
+
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Equal(t, RunPaused, run.State())
+
+		run.Resume()
+		run.Resume()
+
+		result := run.Wait()
+
+		assert.Equal(t, TerminationCompleted, result.TerminationReason)
+		assert.Len(t, result.Trials, 8)
+		assert.Equal(t, int32(8), calls.Load())
+		assert.Equal(t, RunDone, run.State())
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		var calls atomic.Int32
+
+		reached, release := make(chan struct{}), make(chan struct{})
+
+		run := StartObjective(context.Background(), fastConfig(), blockingObjective(&calls, reached, release), ranges...)
+
+		<-reached
+
+		run.Pause()
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+
+			close(release)
+		}()
+
+		// The running trial completes before the run ends.
+		result := run.Stop()
+
+		assert.Equal(t, TerminationStopped, result.TerminationReason)
+		assert.NoError(t, result.Err)
+		assert.Len(t, result.Trials, 3)
+		assert.Equal(t, TrialCompleted, result.Trials[2].Status)
+		assert.Equal(t, RunDone, run.State())
+		assert.Same(t, result, run.Stop())
+
+		run.Resume()
+
+		assert.Equal(t, RunDone, run.State())
+	})
+
+	t.Run("time budget", func(t *testing.T) {
+		for _, countPaused := range []bool{false, true} {
+			var calls atomic.Int32
+
+			reached, release := make(chan struct{}), make(chan struct{})
+
+			config := fastConfig()
+			config.TimeBudget = 200 * time.Millisecond
+			config.CountPausedTime = countPaused
+
+			run := StartObjective(context.Background(), config, blockingObjective(&calls, reached, release), ranges...)
+
+			<-reached
+
+			run.Pause()
+
+			close(release)
+
+			time.Sleep(300 * time.Millisecond)
+
+			run.Resume()
+
+			result := run.Wait()
+
+			if countPaused {
+				assert.Equal(t, TerminationTimeBudget, result.TerminationReason)
+				assert.Len(t, result.Trials, 3)
+			} else {
+				assert.Equal(t, TerminationCompleted, result.TerminationReason)
+				assert.Len(t, result.Trials, 8)
+			}
+		}
+	})
+
+	t.Run("canceled while paused", func(t *testing.T) {
+		var calls atomic.Int32
+
+		reached, release := make(chan struct{}), make(chan struct{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		run := StartObjective(ctx, fastConfig(), blockingObjective(&calls, reached, release), ranges...)
+
+		<-reached
+
+		run.Pause()
+
+		close(release)
+
+		cancel()
+
+		result := run.Wait()
+
+		assert.Equal(t, TerminationContextCanceled, result.TerminationReason)
+		assert.Len(t, result.Trials, 3)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		config := fastConfig()
+		config.TimeBudget = -time.Second
+
+		result := Start(context.Background(), config, func(ctx context.Context, params ...int) error {
+			return nil
+		}, ParameterRange[int]{Min: 0, Max: 5}).Wait()
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+}
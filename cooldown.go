@@ -0,0 +1,54 @@
+package ho
+
+import (
+	"context"
+	"time"
+)
+
+//////
+// Methods.
+//////
+
+// cooldown waits before a benchmark invocation, unless it's the first of the
+// run, see OptimizationConfig.CooldownBetweenTrials.
+//
+// Parameters:
+// - withinTrial: Whether the invocation measures a trial again, see
+// OptimizationConfig.CooldownWithinTrials
+//
+// Returns:
+// - error: The error of the run context if it was done while waiting, nil
+// otherwise.
+func (o *optimizer[T]) cooldown(withinTrial bool) error {
+	d := o.config.CooldownBetweenTrials
+
+	if withinTrial && o.config.CooldownWithinTrials > 0 {
+		d = o.config.CooldownWithinTrials
+	}
+
+	if d <= 0 || !o.invoked.Load() {
+		return nil
+	}
+
+	return o.sleep(o.ctx, d)
+}
+
+//////
+// Helpers.
+//////
+
+// sleep waits for d, or until ctx is done.
+//
+// Returns:
+// - error: The error of ctx if it was done first, nil otherwise.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
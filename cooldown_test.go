@@ -0,0 +1,125 @@
+package ho
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cooldownRun returns an optimizer minimizing objective, whose waits are
+// recorded in events instead of happening, along with benchmark invocations.
+func cooldownRun(config OptimizationConfig, objective ObjectiveFunc[float64], events *[]string) *optimizer[float64] {
+	o := newOptimizer[float64](context.Background(), config, nil, ParameterRange[float64]{Min: 0, Max: 10})
+
+	o.objectiveFunc = func(_ context.Context, info TrialInfo, params ...float64) (float64, error) {
+		*events = append(*events, fmt.Sprintf("trial %d", info.TrialID))
+
+		return objective(params...)
+	}
+
+	o.sleep = func(ctx context.Context, d time.Duration) error {
+		*events = append(*events, fmt.Sprintf("sleep %v", d))
+
+		return nil
+	}
+
+	return o
+}
+
+func TestCooldown(t *testing.T) {
+	objective := func(params ...float64) (float64, error) {
+		return math.Pow(params[0]-3, 2), nil
+	}
+
+	t.Run("between trials", func(t *testing.T) {
+		var events []string
+
+		config := fastConfig()
+		config.CooldownBetweenTrials = time.Second
+
+		result := cooldownRun(config, objective, &events).run()
+
+		// Initial samples included, no wait before the first trial.
+		var expected []string
+
+		for i := range result.Trials {
+			if i > 0 {
+				expected = append(expected, "sleep 1s")
+			}
+
+			expected = append(expected, fmt.Sprintf("trial %d", i+1))
+		}
+
+		assert.Equal(t, expected, events)
+	})
+
+	t.Run("within trials", func(t *testing.T) {
+		var events []string
+
+		config := fastConfig()
+		config.Seed = 1
+		config.CooldownBetweenTrials = time.Second
+		config.CooldownWithinTrials = time.Millisecond
+		config.SurpriseRemeasure = &SurpriseRemeasure{Remeasurements: 2}
+
+		result := cooldownRun(config, glitchingObjective(nil, 0), &events).run()
+
+		assert.True(t, result.Trials[3].Surprising)
+		assert.Equal(t, []string{"trial 4", "sleep 1ms", "trial 4", "sleep 1ms", "trial 4", "sleep 1s", "trial 5"}, events[6:13])
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		config := fastConfig()
+		config.CooldownBetweenTrials = time.Hour
+
+		calls := 0
+
+		// The run is canceled during the first cooldown.
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		start := time.Now()
+
+		result := OptimizeObjectiveWithContext(ctx, config, func(ctx context.Context, params ...float64) (float64, error) {
+			calls++
+
+			return objective(params...)
+		}, ParameterRange[float64]{Min: 0, Max: 10})
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, 1, calls)
+		assert.Len(t, result.Trials, 1)
+		assert.Equal(t, TerminationContextCanceled, result.TerminationReason)
+	})
+
+	t.Run("time budget", func(t *testing.T) {
+		config := fastConfig()
+		config.CooldownBetweenTrials = 60 * time.Millisecond
+		config.TimeBudget = 100 * time.Millisecond
+
+		result := OptimizeObjective(config, objective, ParameterRange[float64]{Min: 0, Max: 10})
+
+		// Cooldowns count against the budget, not against trial durations.
+		assert.Equal(t, TerminationTimeBudget, result.TerminationReason)
+		assert.Len(t, result.Trials, 2)
+
+		for _, trial := range result.Trials {
+			assert.Less(t, trial.Duration, 20*time.Millisecond)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		config := fastConfig()
+		config.CooldownWithinTrials = -time.Second
+
+		result := OptimizeObjective(config, objective, ParameterRange[float64]{Min: 0, Max: 10})
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+}
@@ -0,0 +1,339 @@
+package ho
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sync"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// defaultRunnersUp is the DebugCapture.RunnersUp used if unset.
+const defaultRunnersUp = 5
+
+// DebugCapture configures the capture of what the model believed at each
+// iteration, to replay the run when convergence goes wrong: after each
+// candidate selection, a DebugSnapshot is appended to the debug trace.
+//
+// Important notes:
+// - Snapshots are kept in Result.DebugTrace, or written to Path for long
+// runs, one JSON object per line, see ReadDebugTrace
+// - Replacements of skipped trials are selected, and captured, again
+// - Only optimization runs capture snapshots, not the ask/tell Optimizer.
+type DebugCapture struct {
+	// Path, if set, is the file snapshots are written to, truncated when
+	// the first one is, instead of Result.DebugTrace. Write failures are
+	// recorded in Result.Warnings, and don't stop the run.
+	Path string
+
+	// RunnersUp is the number of runner-up candidates captured, by
+	// acquisition score. If 0, 5 is used.
+	RunnersUp int
+}
+
+// DebugSnapshot is what the model believed when a candidate was selected,
+// see DebugCapture.
+type DebugSnapshot struct {
+	// Iteration is the optimization iteration the candidate was selected
+	// for.
+	Iteration int `json:"iteration"`
+
+	// Chosen is the selected candidate.
+	Chosen DebugCandidate `json:"chosen"`
+
+	// RunnersUp holds the most promising candidates after Chosen, by
+	// increasing acquisition score.
+	RunnersUp []DebugCandidate `json:"runnersUp"`
+
+	// Kernel holds the effective kernel hyperparameters of the model, nil
+	// if it isn't kernel-based.
+	Kernel *KernelStats `json:"kernel,omitempty"`
+}
+
+// DebugCandidate is a scored candidate, see DebugSnapshot.
+type DebugCandidate struct {
+	// Params holds the parameters of the candidate, as float64.
+	Params []float64 `json:"params"`
+
+	// Mean is the predicted mean, of transformed values, see
+	// OptimizationConfig.OutputTransform.
+	Mean float64 `json:"mean"`
+
+	// Variance is the predicted variance.
+	Variance float64 `json:"variance"`
+
+	// Acquisition is the acquisition score, lower is better: values of
+	// acquisition functions with MaximizeAcquisition direction are negated.
+	Acquisition float64 `json:"acquisition"`
+}
+
+// debugCandidateJSON is the layout of a DebugCandidate in a trace file,
+// non-finite values included.
+type debugCandidateJSON struct {
+	Params      []float64       `json:"params"`
+	Mean        checkpointFloat `json:"mean"`
+	Variance    checkpointFloat `json:"variance"`
+	Acquisition checkpointFloat `json:"acquisition"`
+}
+
+// debugTrace collects the snapshots of a run. It's safe for concurrent use.
+type debugTrace struct {
+	// config configures the capture.
+	config DebugCapture
+
+	// mu protects the fields below.
+	mu sync.Mutex
+
+	// snapshots holds the snapshots, unless written to config.Path.
+	snapshots []DebugSnapshot
+
+	// written is true once the file was truncated.
+	written bool
+
+	// failed is true once a write failed, so it's only reported once.
+	failed bool
+
+	// warnf records write failures.
+	warnf func(format string, args ...any)
+}
+
+//////
+// Methods.
+//////
+
+// MarshalJSON implements json.Marshaler. Non-finite values, e.g. a NaN
+// score, are written as strings.
+func (c DebugCandidate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(debugCandidateJSON{
+		Params:      c.Params,
+		Mean:        checkpointFloat(c.Mean),
+		Variance:    checkpointFloat(c.Variance),
+		Acquisition: checkpointFloat(c.Acquisition),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *DebugCandidate) UnmarshalJSON(data []byte) error {
+	var v debugCandidateJSON
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*c = DebugCandidate{
+		Params:      v.Params,
+		Mean:        float64(v.Mean),
+		Variance:    float64(v.Variance),
+		Acquisition: float64(v.Acquisition),
+	}
+
+	return nil
+}
+
+// validate checks the settings.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a setting is invalid, nil otherwise.
+func (d *DebugCapture) validate() error {
+	if d.RunnersUp < 0 {
+		return fmt.Errorf("%w: DebugCapture.RunnersUp %d is negative", ErrInvalidConfig, d.RunnersUp)
+	}
+
+	return nil
+}
+
+// record appends a snapshot to the trace.
+func (t *debugTrace) record(snapshot DebugSnapshot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.config.Path == "" {
+		t.snapshots = append(t.snapshots, snapshot)
+
+		return
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+
+	if !t.written {
+		flags |= os.O_TRUNC
+	}
+
+	if err := appendJSONLine(t.config.Path, flags, snapshot); err != nil {
+		if !t.failed {
+			t.warnf("debug snapshot of iteration %d not written: %v", snapshot.Iteration, err)
+		}
+
+		t.failed = true
+
+		return
+	}
+
+	t.written = true
+}
+
+// trace returns a copy of the snapshots kept in memory, nil if none, e.g.
+// on a nil trace.
+func (t *debugTrace) trace() []DebugSnapshot {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.snapshots)
+}
+
+// captureDebug records the selection of a candidate in the debug trace, if
+// DebugCapture is set.
+//
+// Parameters:
+// - model: Model that scored the candidates
+// - iteration: Current optimization iteration
+// - candidates: The candidates
+// - means, variances, scores: Their predictions and acquisition scores
+// - chosen: Index of the selected candidate.
+func (o *optimizer[T]) captureDebug(model SurrogateModel, iteration int, candidates [][]T, means, variances, scores []float64, chosen int) {
+	candidate := func(i int) DebugCandidate {
+		return DebugCandidate{
+			Params:      paramsToFloat64s(candidates[i]),
+			Mean:        means[i],
+			Variance:    variances[i],
+			Acquisition: scores[i],
+		}
+	}
+
+	others := make([]int, 0, len(candidates)-1)
+
+	for i := range candidates {
+		if i != chosen {
+			others = append(others, i)
+		}
+	}
+
+	// NaN scores come last.
+	slices.SortStableFunc(others, func(a, b int) int {
+		switch x, y := scores[a], scores[b]; {
+		case math.IsNaN(x) && math.IsNaN(y):
+			return 0
+		case math.IsNaN(x):
+			return 1
+		case math.IsNaN(y):
+			return -1
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	runnersUp := o.config.DebugCapture.RunnersUp
+	if runnersUp == 0 {
+		runnersUp = defaultRunnersUp
+	}
+
+	snapshot := DebugSnapshot{
+		Iteration: iteration,
+		Chosen:    candidate(chosen),
+		RunnersUp: make([]DebugCandidate, 0, min(runnersUp, len(others))),
+		Kernel:    kernelStatsOf(model),
+	}
+
+	for _, i := range others[:min(runnersUp, len(others))] {
+		snapshot.RunnersUp = append(snapshot.RunnersUp, candidate(i))
+	}
+
+	o.debug.record(snapshot)
+}
+
+//////
+// Helpers.
+//////
+
+// appendJSONLine writes v as a line of JSON to the file at path, opened with
+// flags.
+func appendJSONLine(path string, flags int, v any) error {
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(f).Encode(v); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+//////
+// Factory.
+//////
+
+// newDebugTrace creates the debug trace of a run, nil unless DebugCapture
+// is set.
+func newDebugTrace(config OptimizationConfig, warnf func(format string, args ...any)) *debugTrace {
+	if config.DebugCapture == nil {
+		return nil
+	}
+
+	return &debugTrace{config: *config.DebugCapture, warnf: warnf}
+}
+
+//////
+// Exported functionalities.
+//////
+
+// ReadDebugTrace reads the snapshots written to DebugCapture.Path.
+//
+// Parameters:
+// - r: The trace, e.g. the opened file
+//
+// Returns:
+// - []DebugSnapshot: The snapshots, in iteration order
+// - error: If a line isn't a snapshot, nil otherwise.
+//
+// Usage example:
+//
+//	f, err := os.Open("run.debug.jsonl")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//
+//	snapshots, err := ReadDebugTrace(f)
+func ReadDebugTrace(r io.Reader) ([]DebugSnapshot, error) {
+	var snapshots []DebugSnapshot
+
+	scanner := bufio.NewScanner(r)
+
+	// Snapshots of many dimensions make long lines.
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		var snapshot DebugSnapshot
+
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
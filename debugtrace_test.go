@@ -0,0 +1,126 @@
+package ho
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugCapture(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}, {Min: 0, Max: 10}}
+
+	objective := func(params ...float64) (float64, error) {
+		return math.Pow(params[0]-3, 2) + math.Pow(params[1]-7, 2), nil
+	}
+
+	t.Run("in memory", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 1
+		config.DebugCapture = &DebugCapture{}
+
+		result := OptimizeObjective(config, objective, ranges...)
+
+		assert.NoError(t, result.Err)
+
+		if !assert.Len(t, result.DebugTrace, config.Iterations) {
+			return
+		}
+
+		for i, snapshot := range result.DebugTrace {
+			trial := result.Trials[config.InitialSamples+i]
+
+			assert.Equal(t, i+1, snapshot.Iteration)
+			assert.Equal(t, trial.Params, snapshot.Chosen.Params)
+			assert.Len(t, snapshot.RunnersUp, 5)
+			assert.NotNil(t, snapshot.Kernel)
+
+			// Runners-up follow the chosen candidate, by acquisition.
+			previous := snapshot.Chosen.Acquisition
+
+			for _, candidate := range snapshot.RunnersUp {
+				assert.GreaterOrEqual(t, candidate.Acquisition, previous)
+				assert.Len(t, candidate.Params, 2)
+
+				previous = candidate.Acquisition
+			}
+		}
+
+		// Nothing is captured unless asked.
+		config.DebugCapture = nil
+
+		assert.Nil(t, OptimizeObjective(config, objective, ranges...).DebugTrace)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "run.debug.jsonl")
+
+		// Snapshots of an earlier run are replaced.
+		assert.NoError(t, os.WriteFile(path, []byte("stale\n"), 0o644))
+
+		config := fastConfig()
+		config.Seed = 1
+		config.DebugCapture = &DebugCapture{Path: path, RunnersUp: 2}
+
+		result := OptimizeObjective(config, objective, ranges...)
+
+		assert.NoError(t, result.Err)
+		assert.Nil(t, result.DebugTrace)
+		assert.Empty(t, result.Warnings)
+
+		f, err := os.Open(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		defer f.Close()
+
+		snapshots, err := ReadDebugTrace(f)
+
+		assert.NoError(t, err)
+
+		if assert.Len(t, snapshots, config.Iterations) {
+			for i, snapshot := range snapshots {
+				assert.Equal(t, result.Trials[config.InitialSamples+i].Params, snapshot.Chosen.Params)
+				assert.Len(t, snapshot.RunnersUp, 2)
+			}
+		}
+	})
+
+	t.Run("unwritable file", func(t *testing.T) {
+		config := fastConfig()
+		config.DebugCapture = &DebugCapture{Path: filepath.Join(t.TempDir(), "missing", "run.debug.jsonl")}
+
+		result := OptimizeObjective(config, objective, ranges...)
+
+		assert.NoError(t, result.Err)
+
+		if assert.Len(t, result.Warnings, 1) {
+			assert.True(t, strings.HasPrefix(result.Warnings[0], "debug snapshot of iteration 1 not written"))
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		config := fastConfig()
+		config.DebugCapture = &DebugCapture{RunnersUp: -1}
+
+		assert.ErrorIs(t, OptimizeObjective(config, objective, ranges...).Err, ErrInvalidConfig)
+	})
+}
+
+func TestReadDebugTrace(t *testing.T) {
+	snapshots, err := ReadDebugTrace(strings.NewReader(`{"iteration":1,"chosen":{"params":[1],"mean":0,"variance":1,"acquisition":"NaN"},"runnersUp":[]}` + "\n"))
+
+	assert.NoError(t, err)
+
+	if assert.Len(t, snapshots, 1) {
+		assert.True(t, math.IsNaN(snapshots[0].Chosen.Acquisition))
+	}
+
+	_, err = ReadDebugTrace(strings.NewReader("{\n"))
+
+	assert.ErrorContains(t, err, "line 1")
+}
@@ -11,10 +11,11 @@
 //   - Thread-safe Implementation: All components are designed for concurrent
 //     optimization runs
 //   - Multiple Acquisition Functions: Various strategies for parameter space
-//     exploration including Upper Confidence Bound (UCB), Probability of
+//     exploration including Lower/Upper Confidence Bound (LCB/UCB), Probability of
 //     Improvement (PI), Expected Improvement (EI), and Thompson Sampling
 //   - Generic Implementation: Works with both integer and floating-point parameters
-//   - Progress Monitoring: Real-time updates on optimization progress via channels
+//   - Progress Monitoring: Real-time updates on optimization progress via channels,
+//     with the overall progress across phases and budgets
 //   - Flexible Configuration: Highly customizable optimization process
 //   - Automatic Parameter Tuning: Learns from previous evaluations to suggest
 //     better parameters
@@ -30,7 +31,7 @@
 //
 // The library provides four acquisition functions for different optimization strategies:
 //
-// 1. Upper Confidence Bound (UCB):
+// 1. Lower Confidence Bound (LCB):
 //
 //   - Balances exploration and exploitation
 //
@@ -38,7 +39,10 @@
 //
 //   - Default choice, works well in most cases
 //
-//     config := DefaultConfig()  // Uses UCB by default
+//   - What the literature calls UCB, in the minimization setting (UCB is kept
+//     as a deprecated alias)
+//
+//     config := DefaultConfig()  // Uses LowerConfidenceBound by default
 //     config.AcqParams.Beta = 2.0  // Adjust exploration-exploitation trade-off
 //
 // 2. Probability of Improvement (PI):
@@ -77,6 +81,16 @@
 //     config.AcquisitionFunc = ThompsonSampling
 //     config.AcqParams.RandomState = rand.New(rand.NewSource(time.Now().UnixNano()))
 //
+// All built-ins follow the package's "lower is better" convention, except the
+// ones named as in the literature for maximization (UpperConfidenceBound,
+// ExpectedImprovementMin, ProbabilityOfImprovementMin). Use SetAcquisition so
+// the optimizer knows the direction:
+//
+//	acquisition, _ := LookupAcquisition("ExpectedImprovementMin")
+//
+//	config := DefaultConfig()
+//	config.SetAcquisition(acquisition)
+//
 // # Configuration
 //
 // The OptimizationConfig struct allows customization of the optimization process:
@@ -93,7 +107,40 @@
 // Recommended settings:
 //   - Iterations: 20-200 (more = better results but longer runtime)
 //   - InitialSamples: 5-20 (more = better initial model)
-//   - NumCandidates: 50-500 (more = better search but slower iterations)
+//   - NumCandidates: 0 derives it from the dimensions, 100 per parameter up
+//     to 2000, or 50-500 (more = better search but slower iterations)
+//
+// # Skipping Trials
+//
+// If a measurement was invalidated by something unrelated to the parameters,
+// return ErrSkipTrial from the benchmark. The trial is recorded as skipped, but
+// it neither updates the model nor the best result:
+//
+//	config := DefaultConfig()
+//	config.MaxSkipRetries = 3  // Draw up to 3 replacements per skipped trial
+//
+//	result := Optimize(config, func(params ...int) error {
+//	    if deployInProgress() {
+//	        return ErrSkipTrial
+//	    }
+//
+//	    return runWorkload(params[0])
+//	}, ranges...)
+//
+// # Cancellation and Timeouts
+//
+// Use OptimizeWithContext with a BenchmarkFuncCtx to make per-trial timeouts and
+// run cancellation actually cancel work:
+//
+//	config := DefaultConfig()
+//	config.TrialTimeout = 30 * time.Second
+//
+//	result := OptimizeWithContext(ctx, config, func(ctx context.Context, params ...int) error {
+//	    return runWorkload(ctx, params[0])
+//	}, ranges...)
+//
+// Timed out trials are recorded as TrialCanceled and penalized. Canceling ctx
+// stops the run immediately with termination reason TerminationContextCanceled.
 //
 // # Thread Safety
 //
@@ -0,0 +1,289 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// PhaseSentinel is the TrialInfo.Phase of reference measurements, see
+	// DriftSentinel.
+	PhaseSentinel = "Sentinel"
+
+	// defaultSentinelEvery is the DriftSentinel.Every used if unset.
+	defaultSentinelEvery = 10
+
+	// defaultDriftThreshold is the DriftSentinel.Threshold used if unset.
+	defaultDriftThreshold = 0.1
+)
+
+// DriftAction is what happens when drift is detected, see DriftSentinel.
+type DriftAction string
+
+const (
+	// DriftWarn records a warning in Result.Warnings.
+	DriftWarn DriftAction = "Warn"
+
+	// DriftCorrect records a warning, and divides the values of the next
+	// completed trials by the drift ratio before they reach the model.
+	DriftCorrect DriftAction = "Correct"
+)
+
+// DriftSentinel configures the interleaved measurement of a reference
+// configuration, to detect environmental drift: if the system slows down
+// halfway through a run, e.g. on thermal throttling or a cron job, every
+// later value is biased, and comparisons across time are invalid.
+//
+// Important notes:
+// - The reference is measured before the first trial, its baseline, then
+// every Every ended trials. Measurements are invoked with TrialInfo.Phase
+// PhaseSentinel and TrialID 0, and are listed in Result.Drift, not
+// Result.Trials: they never reach the model nor the best result
+// - Drift is detected when the ratio of a measurement to the baseline is
+// off 1 by more than Threshold. Progress updates carry the last ratio, see
+// ProgressUpdate.DriftRatio
+// - With DriftCorrect, values of trials completing after the detection are
+// divided by the ratio, until a measurement within Threshold, see
+// Trial.DriftCorrection. The correction suits values proportional to the
+// speed of the system, e.g. execution times
+// - With MaxConcurrentEvaluations greater than 1, initial samples may run
+// along reference measurements.
+type DriftSentinel struct {
+	// Every is the number of ended trials between reference measurements.
+	// If 0, 10 is used.
+	Every int
+
+	// Reference is the reference configuration, one value per parameter
+	// range.
+	// If nil, the midpoint of each range is used.
+	Reference []float64
+
+	// Threshold is the relative deviation from the baseline beyond which
+	// drift is detected, e.g. 0.1 for 10%.
+	// If 0, 0.1 is used.
+	Threshold float64
+
+	// Action is what happens when drift is detected.
+	// If empty, DriftWarn is used.
+	Action DriftAction
+}
+
+// DriftMeasurement is a measurement of the reference configuration, see
+// DriftSentinel.
+type DriftMeasurement struct {
+	// AfterTrials is the number of trials ended before the measurement.
+	AfterTrials int
+
+	// Time is when the measurement started.
+	Time time.Time
+
+	// Value is the measured value, e.g. an execution time in nanoseconds.
+	Value float64
+
+	// Ratio is Value over the baseline, the first measured value.
+	Ratio float64
+
+	// Drifted is true if Ratio is off 1 by more than DriftSentinel.Threshold.
+	Drifted bool
+}
+
+// driftState tracks the reference measurements of a run.
+type driftState struct {
+	// history holds the measurements, baseline first.
+	history []DriftMeasurement
+
+	// measured is the number of ended trials at the last measurement.
+	measured int
+
+	// attempts is the number of measurements, failed ones included.
+	attempts int
+
+	// correction is the ratio values are divided by, 0 if none.
+	correction float64
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the settings.
+//
+// Parameters:
+// - dims: Number of parameter ranges
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a setting is invalid, nil otherwise.
+func (s *DriftSentinel) validate(dims int) error {
+	switch {
+	case s.Every < 0:
+		return fmt.Errorf("%w: DriftSentinel.Every %d is negative", ErrInvalidConfig, s.Every)
+	case s.Reference != nil && len(s.Reference) != dims:
+		return fmt.Errorf("%w: DriftSentinel.Reference has %d values, expected %d", ErrInvalidConfig, len(s.Reference), dims)
+	case s.Threshold < 0 || math.IsNaN(s.Threshold):
+		return fmt.Errorf("%w: DriftSentinel.Threshold %v must be positive", ErrInvalidConfig, s.Threshold)
+	}
+
+	switch s.Action {
+	case "", DriftWarn, DriftCorrect:
+		return nil
+	default:
+		return fmt.Errorf("%w: DriftSentinel.Action: unknown action %q", ErrInvalidConfig, s.Action)
+	}
+}
+
+// watchDrift measures the reference configuration if it's due, see
+// DriftSentinel.
+func (o *optimizer[T]) watchDrift() {
+	settings := o.config.DriftSentinel
+
+	if settings == nil {
+		return
+	}
+
+	every := settings.Every
+	if every == 0 {
+		every = defaultSentinelEvery
+	}
+
+	o.mu.Lock()
+
+	ended := len(o.trials)
+
+	due := len(o.drift.history) == 0 || ended-o.drift.measured >= every
+
+	o.mu.Unlock()
+
+	if !due || o.cooldown(false) != nil {
+		return
+	}
+
+	info, params := TrialInfo{Phase: PhaseSentinel, Seed: trialSeed(o.source.seed, 0)}, o.referenceParams()
+
+	m := o.measureHooked(info, params)
+
+	err := m.err
+
+	switch {
+	case err == nil && m.canceled != nil:
+		err = m.canceled
+	case err == nil && (math.IsNaN(m.value) || math.IsInf(m.value, 0)):
+		err = fmt.Errorf("%w %v", ErrNonFiniteValue, m.value)
+	case err == nil && m.value <= 0:
+		err = fmt.Errorf("value %v isn't positive", m.value)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.drift.measured = ended
+
+	o.drift.attempts++
+
+	if err != nil {
+		o.warnings = append(o.warnings, fmt.Sprintf("reference measurement after %d trials failed: %v", ended, err))
+
+		return
+	}
+
+	measurement := DriftMeasurement{
+		AfterTrials: ended,
+		Time:        m.startedAt,
+		Value:       m.value,
+		Ratio:       1,
+	}
+
+	if len(o.drift.history) > 0 {
+		measurement.Ratio = m.value / o.drift.history[0].Value
+	}
+
+	threshold := settings.Threshold
+	if threshold == 0 {
+		threshold = defaultDriftThreshold
+	}
+
+	measurement.Drifted = math.Abs(measurement.Ratio-1) > threshold
+
+	o.drift.history = append(o.drift.history, measurement)
+
+	o.drift.correction = 0
+
+	if !measurement.Drifted {
+		return
+	}
+
+	o.warnings = append(o.warnings, fmt.Sprintf("drift detected after %d trials: reference measured %g, %+.1f%% off its baseline %g",
+		ended, m.value, (measurement.Ratio-1)*100, o.drift.history[0].Value))
+
+	if settings.Action == DriftCorrect {
+		o.drift.correction = measurement.Ratio
+	}
+}
+
+// correctDrift divides the value of a completed trial by the drift ratio, if
+// drift was detected and DriftCorrect is set.
+//
+// Parameters:
+// - trial: The completed trial, updated in place.
+func (o *optimizer[T]) correctDrift(trial *Trial[T]) {
+	if o.drift == nil {
+		return
+	}
+
+	o.mu.Lock()
+	correction := o.drift.correction
+	o.mu.Unlock()
+
+	if correction == 0 {
+		return
+	}
+
+	trial.ExecutionTime /= correction
+	trial.DriftCorrection = correction
+}
+
+// driftRatio returns the ratio of the last reference measurement, 0 if none.
+// The caller must hold mu.
+func (o *optimizer[T]) driftRatio() float64 {
+	if o.drift == nil || len(o.drift.history) == 0 {
+		return 0
+	}
+
+	return o.drift.history[len(o.drift.history)-1].Ratio
+}
+
+// referenceParams returns the reference configuration, see
+// DriftSentinel.Reference.
+func (o *optimizer[T]) referenceParams() []T {
+	params := make([]T, len(o.hypers))
+
+	for i, hyper := range o.hypers {
+		v := (float64(hyper.Min) + float64(hyper.Max)) / 2
+
+		if reference := o.config.DriftSentinel.Reference; reference != nil {
+			v = reference[i]
+		}
+
+		params[i] = rangeValue(hyper, v)
+	}
+
+	return params
+}
+
+//////
+// Factory.
+//////
+
+// newDriftState returns the drift state of a run, nil unless a drift
+// sentinel is configured.
+func newDriftState(config OptimizationConfig) *driftState {
+	if config.DriftSentinel == nil {
+		return nil
+	}
+
+	return &driftState{}
+}
@@ -0,0 +1,129 @@
+package ho
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowingObjective returns an objective whose values double after its 5th
+// call, as if the system slowed down, along with the function the values are
+// proportional to.
+func slowingObjective() (ObjectiveFunc[float64], func(x float64) float64) {
+	var calls int
+
+	f := func(x float64) float64 {
+		return math.Pow(x-3, 2) + 1
+	}
+
+	return func(params ...float64) (float64, error) {
+		calls++
+
+		if calls > 5 {
+			return 2 * f(params[0]), nil
+		}
+
+		return f(params[0]), nil
+	}, f
+}
+
+func TestDriftSentinel(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	t.Run("warn", func(t *testing.T) {
+		progress := make(chan ProgressUpdate, 20)
+
+		// The reference is measured before the 1st, 4th and 7th trials, the
+		// 1st, 5th and 9th calls.
+		config := fastConfig()
+		config.ProgressChan = progress
+		config.DriftSentinel = &DriftSentinel{Every: 3}
+
+		objective, _ := slowingObjective()
+
+		result := OptimizeObjective(config, objective, ranges...)
+
+		close(progress)
+
+		if !assert.Len(t, result.Drift, 3) {
+			return
+		}
+
+		for i, expected := range []DriftMeasurement{
+			{AfterTrials: 0, Value: 5, Ratio: 1},
+			{AfterTrials: 3, Value: 5, Ratio: 1},
+			{AfterTrials: 6, Value: 10, Ratio: 2, Drifted: true},
+		} {
+			expected.Time = result.Drift[i].Time
+
+			assert.Equal(t, expected, result.Drift[i])
+		}
+
+		assert.Equal(t, []string{"drift detected after 6 trials: reference measured 10, +100.0% off its baseline 5"}, result.Warnings)
+
+		// Reference measurements aren't trials.
+		assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+		assert.Equal(t, len(result.Trials), result.model.Len())
+
+		var last ProgressUpdate
+
+		for update := range progress {
+			if update.Phase != PhaseDone {
+				last = update
+			}
+		}
+
+		assert.Equal(t, 2.0, last.DriftRatio)
+
+		for _, trial := range result.Trials {
+			assert.Zero(t, trial.DriftCorrection)
+		}
+	})
+
+	t.Run("correct", func(t *testing.T) {
+		config := fastConfig()
+		config.DriftSentinel = &DriftSentinel{Every: 3, Reference: []float64{3}, Action: DriftCorrect}
+
+		objective, f := slowingObjective()
+
+		result := OptimizeObjective(config, objective, ranges...)
+
+		if !assert.Len(t, result.Drift, 3) {
+			return
+		}
+
+		assert.Equal(t, 1.0, result.Drift[0].Value)
+
+		// Trials measured after the detection are corrected, the others
+		// aren't.
+		for i, trial := range result.Trials {
+			switch {
+			case i >= 6:
+				assert.Equal(t, 2.0, trial.DriftCorrection)
+				assert.InDelta(t, f(trial.Params[0]), trial.ExecutionTime, 1e-9)
+			case i >= 3:
+				assert.Zero(t, trial.DriftCorrection)
+				assert.InDelta(t, 2*f(trial.Params[0]), trial.ExecutionTime, 1e-9)
+			default:
+				assert.Zero(t, trial.DriftCorrection)
+				assert.InDelta(t, f(trial.Params[0]), trial.ExecutionTime, 1e-9)
+			}
+		}
+
+		assert.Equal(t, 2.0, NewTrialRecord(result.Trials[7], nil).DriftCorrection)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		objective, _ := slowingObjective()
+
+		for _, sentinel := range []DriftSentinel{{Every: -1}, {Reference: []float64{1, 2}}, {Threshold: -1}, {Action: "Ignore"}} {
+			config := fastConfig()
+			config.DriftSentinel = &sentinel
+
+			result := OptimizeObjective(config, objective, ranges...)
+
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+		}
+	})
+}
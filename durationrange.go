@@ -0,0 +1,75 @@
+package ho
+
+import (
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// DurationRange is a parameter range of durations, e.g. timeouts and
+// intervals, see Range.
+//
+// Important notes:
+// - Values are sampled and modeled in nanoseconds, and always a whole number
+// of nanoseconds
+// - Results render them as durations, e.g. "250ms", see ParameterSpec.Format
+// and TrialRecord.Formatted
+// - Benchmarks receive nanoseconds, bound to time.Duration fields by
+// BindParams and OptimizeStruct.
+type DurationRange struct {
+	// Name optionally names the parameter, see ParameterRange.Name.
+	Name string
+
+	// Min is the minimum (inclusive) duration.
+	Min time.Duration
+
+	// Max is the maximum (inclusive) duration.
+	Max time.Duration
+
+	// Step optionally restricts durations to Min plus a multiple of Step,
+	// e.g. 50ms increments.
+	// If zero, durations are any whole number of nanoseconds.
+	Step time.Duration
+
+	// Log samples every order of magnitude equally, see LogUniform. It
+	// requires a positive Min.
+	Log bool
+}
+
+//////
+// Methods.
+//////
+
+// Range returns the parameter range of the durations, in nanoseconds, to
+// pass to Optimize along with other ranges.
+//
+// Returns:
+// - ParameterRange[float64]: The range, typed DurationParameter.
+//
+// Usage example:
+//
+//	result := Optimize(config, func(params ...float64) error {
+//	    return runWorkload(time.Duration(params[0]), int(params[1]))
+//	},
+//	    DurationRange{Name: "Timeout", Min: 100 * time.Microsecond, Max: time.Second, Log: true}.Range(),
+//	    ParameterRange[float64]{Name: "Workers", Min: 1, Max: 32, Step: 1},
+//	)
+//
+//	fmt.Println(result.FormatParams(result.BestParams)) // Timeout=2.5ms Workers=8
+func (r DurationRange) Range() ParameterRange[float64] {
+	hyper := ParameterRange[float64]{
+		Name: r.Name,
+		Type: DurationParameter,
+		Min:  float64(r.Min),
+		Max:  float64(r.Max),
+		Step: float64(max(r.Step, time.Nanosecond)),
+	}
+
+	if r.Log {
+		hyper.Prior = LogUniform()
+	}
+
+	return hyper
+}
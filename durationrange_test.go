@@ -0,0 +1,101 @@
+package ho
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationRange(t *testing.T) {
+	hyper := DurationRange{Name: "Timeout", Min: time.Millisecond, Max: time.Second, Log: true}.Range()
+
+	assert.Equal(t, ParameterRange[float64]{
+		Name:  "Timeout",
+		Type:  DurationParameter,
+		Min:   1e6,
+		Max:   1e9,
+		Step:  1,
+		Prior: LogUniform(),
+	}, hyper)
+
+	assert.Equal(t, 50e3, DurationRange{Step: 50 * time.Microsecond}.Range().Step)
+
+	spec := ParameterSpec{Type: DurationParameter}
+
+	assert.Equal(t, "250ms", spec.Format(250e6))
+	assert.Equal(t, "150µs", spec.Format(150e3+0.4))
+	assert.Equal(t, "8", ParameterSpec{Type: IntParameter}.Format(7.6))
+	assert.Equal(t, "0.25", ParameterSpec{Type: FloatParameter}.Format(0.25))
+}
+
+func TestOptimizeDuration(t *testing.T) {
+	type settings struct {
+		Timeout time.Duration
+		Workers int
+	}
+
+	var seen []time.Duration
+
+	config := fastConfig()
+
+	// Sub-millisecond, snapped to 50µs steps.
+	result := Optimize(config, func(params ...float64) error {
+		var s settings
+
+		assert.NoError(t, BindParams([]string{"Timeout", "Workers"}, params, &s))
+
+		seen = append(seen, s.Timeout)
+
+		time.Sleep(s.Timeout)
+
+		return nil
+	},
+		DurationRange{Name: "Timeout", Min: 100 * time.Microsecond, Max: 900 * time.Microsecond, Step: 50 * time.Microsecond}.Range(),
+		ParameterRange[float64]{Name: "Workers", Min: 1, Max: 8, Step: 1},
+	)
+
+	assert.NoError(t, result.Err)
+	assert.Len(t, seen, len(result.Trials))
+
+	for _, d := range seen {
+		assert.GreaterOrEqual(t, d, 100*time.Microsecond)
+		assert.LessOrEqual(t, d, 900*time.Microsecond)
+		assert.Zero(t, d%(50*time.Microsecond), "%v is off the step", d)
+	}
+
+	// Results render durations as such, other parameters as numbers.
+	timeout := time.Duration(math.Round(result.BestParams[0]))
+
+	assert.Equal(t, "Timeout="+timeout.String()+" Workers="+ParameterSpec{Type: IntParameter}.Format(result.BestParams[1]), result.FormatParams(result.BestParams))
+
+	for _, record := range result.Records() {
+		assert.Equal(t, map[string]string{"Timeout": time.Duration(record.Params["Timeout"]).String()}, record.Formatted)
+	}
+
+	// The type is checked.
+	config = fastConfig()
+
+	result = Optimize(config, func(params ...float64) error { return nil },
+		ParameterRange[float64]{Min: 1, Max: 2, Type: "bytes"},
+	)
+
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+}
+
+func TestOptimizeStructDuration(t *testing.T) {
+	type settings struct {
+		Interval time.Duration `ho:"min=200us,max=800us,step=100us"`
+	}
+
+	best, result := OptimizeStruct(fastConfig(), func(s settings) error {
+		assert.Zero(t, s.Interval%(100*time.Microsecond), "%v is off the step", s.Interval)
+
+		return nil
+	}, settings{})
+
+	assert.NoError(t, result.Err)
+	assert.Equal(t, time.Duration(result.BestParams[0]), best.Interval)
+	assert.Equal(t, "Interval="+best.Interval.String(), result.FormatParams(result.BestParams))
+}
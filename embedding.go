@@ -0,0 +1,350 @@
+package ho
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultEmbeddingDimensions caps the default number of latent
+	// dimensions, see RandomEmbedding.
+	defaultEmbeddingDimensions = 10
+
+	// embeddingSeedMix is mixed into the run seed to seed the projection, so
+	// it doesn't share its random numbers with the samples of the run.
+	embeddingSeedMix = 0x5eed_e3bd_d1e6
+)
+
+// RandomEmbedding configures the search of a high-dimensional space in a
+// low-dimensional random embedding, see OptimizeEmbedded.
+type RandomEmbedding struct {
+	// Dimensions is the number of latent dimensions, at most the number of
+	// parameter ranges. It should be at least the number of parameters that
+	// matter. If 0, min(10, number of parameter ranges).
+	Dimensions int
+}
+
+// Embedding is the random projection of a run in a random embedding, see
+// OptimizeEmbedded. Latent points z, in [-1, 1] along each latent dimension,
+// map to the parameters Matrix * z, clipped to [-1, 1] and scaled to each
+// range.
+type Embedding struct {
+	// Matrix holds the projection, one row per parameter range, one column
+	// per latent dimension. Rows are random unit vectors, drawn by a
+	// generator seeded with the run seed, see Result.Seed: unlike rows of
+	// standard normal values, they keep most parameters within their range
+	// instead of clipped to its bounds.
+	Matrix [][]float64
+
+	// ranges holds the parameter ranges, as float64 ones.
+	ranges []ParameterRange[float64]
+}
+
+//////
+// Methods.
+//////
+
+// Params maps a latent point to the parameters it stands for, e.g. the best
+// parameters of the latent run to those the benchmark received.
+//
+// Parameters:
+// - latent: The latent point, one value per latent dimension
+//
+// Returns:
+// - []float64: The parameters, one value per parameter range, rounded and
+// snapped to the range, see ParameterRange.Step.
+//
+// Important notes:
+// - Ranges with a LogUniform prior are scaled in log space, the prior of
+// other ranges doesn't apply.
+func (e *Embedding) Params(latent []float64) []float64 {
+	params := make([]float64, len(e.Matrix))
+
+	for i, row := range e.Matrix {
+		var y float64
+
+		for j, a := range row {
+			y += a * latent[j]
+		}
+
+		hyper := e.ranges[i]
+
+		// The projection, in [-1, 1], is a position in the range.
+		u := (clamp(y, -1, 1) + 1) / 2
+
+		v := hyper.Min + u*(hyper.Max-hyper.Min)
+
+		if _, ok := hyper.Prior.(logUniform); ok && hyper.Min > 0 {
+			v = math.Exp(math.Log(hyper.Min) + u*(math.Log(hyper.Max)-math.Log(hyper.Min)))
+		}
+
+		params[i] = rangeValue(hyper, v)
+	}
+
+	return params
+}
+
+// latentRanges returns the ranges of the latent box.
+func (e *Embedding) latentRanges() []ParameterRange[float64] {
+	ranges := make([]ParameterRange[float64], len(e.Matrix[0]))
+
+	for j := range ranges {
+		ranges[j] = ParameterRange[float64]{Min: -1, Max: 1}
+	}
+
+	return ranges
+}
+
+// validate checks the embedding against the run.
+//
+// Parameters:
+// - config: The configuration of the run
+// - ranges: Number of parameter ranges
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if the embedding doesn't fit the
+// ranges, or the configuration refers to points of the full space, nil
+// otherwise.
+func (r RandomEmbedding) validate(config OptimizationConfig, ranges int) error {
+	switch {
+	case ranges == 0:
+		return fmt.Errorf("%w: RandomEmbedding: no parameter range to embed", ErrInvalidConfig)
+	case r.Dimensions < 0 || r.Dimensions > ranges:
+		return fmt.Errorf("%w: RandomEmbedding: Dimensions %d out of [1, %d]", ErrInvalidConfig, r.Dimensions, ranges)
+	case len(config.WarmStart) > 0:
+		return fmt.Errorf("%w: WarmStart: not supported in a random embedding", ErrInvalidConfig)
+	case len(config.ExclusionZones) > 0:
+		return fmt.Errorf("%w: ExclusionZones: not supported in a random embedding", ErrInvalidConfig)
+	case len(config.Constraints) > 0:
+		return fmt.Errorf("%w: Constraints: not supported in a random embedding", ErrInvalidConfig)
+	case config.KnownOptimum != nil && config.KnownOptimum.Location != nil:
+		return fmt.Errorf("%w: KnownOptimum: Location not supported in a random embedding", ErrInvalidConfig)
+	case config.Safety != nil:
+		return fmt.Errorf("%w: Safety: not supported in a random embedding", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// optimizeEmbedded runs the optimization in the random embedding of the
+// ranges, see OptimizeEmbedded.
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process
+// - embedding: Configures the embedding
+// - hypers: The parameter ranges
+// - run: Runs the latent optimization, its function receiving the
+// parameters through project
+//
+// Returns:
+// - []T: The best parameters, nil if no trial completed
+// - *Result[float64]: The outcome of the latent run.
+func optimizeEmbedded[T constraints.Integer | constraints.Float](
+	config OptimizationConfig,
+	embedding RandomEmbedding,
+	hypers []ParameterRange[T],
+	run func(config OptimizationConfig, project func(latent []float64) []T, ranges []ParameterRange[float64]) *Result[float64],
+) ([]T, *Result[float64]) {
+	err := embedding.validate(config, len(hypers))
+	if err == nil {
+		err = newOptimizer(context.Background(), DefaultConfig(), nil, hypers...).validate()
+	}
+
+	if err != nil {
+		o := newOptimizer[float64](context.Background(), config, nil)
+
+		o.invalidErr = err
+
+		return nil, o.result()
+	}
+
+	// The seed of the run seeds the projection too, so it's reproducible.
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+
+	e := newEmbedding(config.Seed, embedding.Dimensions, hypers)
+
+	project := func(latent []float64) []T {
+		params := make([]T, len(hypers))
+
+		for i, v := range e.Params(latent) {
+			params[i] = fromFloat64[T](v)
+		}
+
+		params, _ = benchmarkParams(hypers, params)
+
+		return params
+	}
+
+	if filter := config.CandidateFilter; filter != nil {
+		config.CandidateFilter = func(candidate []float64) bool {
+			return filter(e.Params(candidate))
+		}
+	}
+
+	if setup := config.Setup; setup != nil {
+		config.Setup = func(info TrialInfo, latent ...float64) error {
+			return setup(info, paramsToFloat64s(project(latent))...)
+		}
+	}
+
+	result := run(config, project, e.latentRanges())
+
+	result.Embedding = e
+
+	if result.BestTime == math.MaxFloat64 {
+		return nil, result
+	}
+
+	return project(result.BestParams), result
+}
+
+//////
+// Factory.
+//////
+
+// newEmbedding draws the random projection of the ranges.
+//
+// Parameters:
+// - seed: The run seed
+// - dimensions: Number of latent dimensions, 0 for the default
+// - hypers: The parameter ranges
+//
+// Returns:
+// - *Embedding: The projection.
+func newEmbedding[T constraints.Integer | constraints.Float](seed int64, dimensions int, hypers []ParameterRange[T]) *Embedding {
+	if dimensions == 0 {
+		dimensions = min(defaultEmbeddingDimensions, len(hypers))
+	}
+
+	rng := rand.New(rand.NewSource(seed ^ embeddingSeedMix))
+
+	e := &Embedding{
+		Matrix: make([][]float64, len(hypers)),
+		ranges: make([]ParameterRange[float64], len(hypers)),
+	}
+
+	for i, hyper := range hypers {
+		e.Matrix[i] = make([]float64, dimensions)
+
+		// Normal values, normalized, are uniform on the unit sphere.
+		var norm float64
+
+		for j := range e.Matrix[i] {
+			e.Matrix[i][j] = rng.NormFloat64()
+
+			norm += e.Matrix[i][j] * e.Matrix[i][j]
+		}
+
+		for j := range e.Matrix[i] {
+			e.Matrix[i][j] /= math.Sqrt(norm)
+		}
+
+		// Values are rounded as the range's, see latticeOf.
+		l := latticeOf(hyper)
+
+		e.ranges[i] = ParameterRange[float64]{
+			Name:   hyper.Name,
+			Type:   hyper.Type,
+			Min:    float64(hyper.Min),
+			Max:    float64(hyper.Max),
+			Step:   l.spacing,
+			Values: hyper.Values,
+			Prior:  hyper.Prior,
+		}
+	}
+
+	return e
+}
+
+//////
+// Exported functionalities.
+//////
+
+// OptimizeEmbedded works like Optimize, for spaces with many parameters of
+// which only a few matter, e.g. 40 tunables: the search runs in a random
+// embedding of few latent dimensions (REMBO), where the model and the
+// acquisition function work, while the benchmark receives every parameter.
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process
+// - embedding: Configures the embedding, e.g. its number of dimensions
+// - benchmarkFunc: The function whose parameters you want to optimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - []T: The best parameters found, nil if no trial completed
+// - *Result[float64]: The outcome of the latent run, its Embedding mapping
+// latent points to parameters.
+//
+// Usage example:
+//
+//	best, result := OptimizeEmbedded(config, RandomEmbedding{Dimensions: 4}, func(params ...float64) error {
+//	    return runWorkload(params...)
+//	}, ranges...) // e.g. 40 ranges
+//
+//	for _, trial := range result.Trials {
+//	    fmt.Println(result.Embedding.Params(trial.Params), trial.ExecutionTime)
+//	}
+//
+// Important notes:
+// - Latent points map to the parameters through a random projection drawn
+// from the run seed, kept in Result.Embedding, see Embedding. Set
+// OptimizationConfig.Seed to reproduce it
+// - Trials, progress updates, trackers, storage and checkpoints hold latent
+// points, see Embedding.Params. CandidateFilter and Setup receive parameters
+// - WarmStart, ExclusionZones, Constraints, KnownOptimum.Location and Safety
+// refer to parameters, they fail the run with ErrInvalidConfig.
+func OptimizeEmbedded[T constraints.Integer | constraints.Float](
+	config OptimizationConfig,
+	embedding RandomEmbedding,
+	benchmarkFunc BenchmarkFunc[T],
+	hypers ...ParameterRange[T],
+) ([]T, *Result[float64]) {
+	return optimizeEmbedded(config, embedding, hypers, func(config OptimizationConfig, project func([]float64) []T, ranges []ParameterRange[float64]) *Result[float64] {
+		return Optimize(config, func(latent ...float64) error {
+			return benchmarkFunc(project(latent)...)
+		}, ranges...)
+	})
+}
+
+// OptimizeObjectiveEmbedded works exactly like OptimizeEmbedded but
+// minimizes the value returned by the objective function, see
+// OptimizeObjective.
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process
+// - embedding: Configures the embedding, e.g. its number of dimensions
+// - objectiveFunc: The function whose value you want to minimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - []T: The best parameters found, nil if no trial completed
+// - *Result[float64]: The outcome of the latent run, its Embedding mapping
+// latent points to parameters.
+func OptimizeObjectiveEmbedded[T constraints.Integer | constraints.Float](
+	config OptimizationConfig,
+	embedding RandomEmbedding,
+	objectiveFunc ObjectiveFunc[T],
+	hypers ...ParameterRange[T],
+) ([]T, *Result[float64]) {
+	return optimizeEmbedded(config, embedding, hypers, func(config OptimizationConfig, project func([]float64) []T, ranges []ParameterRange[float64]) *Result[float64] {
+		return OptimizeObjective(config, func(latent ...float64) (float64, error) {
+			return objectiveFunc(project(latent)...)
+		}, ranges...)
+	})
+}
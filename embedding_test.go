@@ -0,0 +1,143 @@
+package ho
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// activeSubspace is a 30-dimensional function of which only 2 dimensions
+// matter, minimal at x[3] = 0.3 and x[17] = -0.5.
+func activeSubspace(x ...float64) (float64, error) {
+	return math.Pow(x[3]-0.3, 2) + math.Pow(x[17]+0.5, 2), nil
+}
+
+func TestOptimizeEmbedded(t *testing.T) {
+	hypers := make([]ParameterRange[float64], 30)
+
+	for i := range hypers {
+		hypers[i] = ParameterRange[float64]{Min: -1, Max: 1}
+	}
+
+	config := DefaultConfig()
+	config.Seed = 7
+	config.InitialSamples = 10
+	config.Iterations = 30
+	config.NumCandidates = 50
+
+	best, result := OptimizeObjectiveEmbedded(config, RandomEmbedding{Dimensions: 4}, activeSubspace, hypers...)
+
+	assert.NoError(t, result.Err)
+	assert.Len(t, best, 30)
+	assert.Len(t, result.BestParams, 4)
+	assert.Equal(t, best, result.Embedding.Params(result.BestParams))
+
+	value, _ := activeSubspace(best...)
+
+	assert.Equal(t, result.BestTime, value)
+
+	for _, v := range best {
+		assert.GreaterOrEqual(t, v, -1.0)
+		assert.LessOrEqual(t, v, 1.0)
+	}
+
+	assert.Less(t, result.BestTime, 0.01)
+
+	// The same budget in the full space does worse.
+	var embedded, full float64
+
+	for seed := int64(1); seed <= 5; seed++ {
+		config.Seed = seed
+
+		_, result := OptimizeObjectiveEmbedded(config, RandomEmbedding{Dimensions: 4}, activeSubspace, hypers...)
+
+		embedded += result.BestTime
+		full += OptimizeObjective(config, activeSubspace, hypers...).BestTime
+	}
+
+	assert.Less(t, embedded, full/2)
+
+	config.Seed = 7
+
+	// The projection is derived from the seed.
+	_, again := OptimizeObjectiveEmbedded(config, RandomEmbedding{Dimensions: 4}, activeSubspace, hypers...)
+
+	assert.Equal(t, int64(7), again.Seed)
+	assert.Equal(t, result.Embedding.Matrix, again.Embedding.Matrix)
+	assert.Equal(t, result.BestParams, again.BestParams)
+	assert.Len(t, result.Embedding.Matrix, 30)
+	assert.Len(t, result.Embedding.Matrix[0], 4)
+
+	config.Seed = 8
+
+	_, other := OptimizeObjectiveEmbedded(config, RandomEmbedding{}, activeSubspace, hypers...)
+
+	assert.NotEqual(t, result.Embedding.Matrix[0], other.Embedding.Matrix[0])
+	assert.Len(t, other.Embedding.Matrix[0], 10)
+}
+
+func TestOptimizeEmbeddedTypes(t *testing.T) {
+	ranges := []ParameterRange[int]{
+		{Min: -5, Max: 5},
+		{Min: 0, Max: 100, Step: 10},
+		{Min: 1, Max: 1000, Prior: LogUniform()},
+	}
+
+	best, result := OptimizeEmbedded(fastConfig(), RandomEmbedding{Dimensions: 2}, func(params ...int) error {
+		assert.GreaterOrEqual(t, params[0], -5)
+		assert.LessOrEqual(t, params[0], 5)
+		assert.Zero(t, params[1]%10)
+		assert.GreaterOrEqual(t, params[2], 1)
+		assert.LessOrEqual(t, params[2], 1000)
+
+		return nil
+	}, ranges...)
+
+	assert.NoError(t, result.Err)
+	assert.Len(t, best, 3)
+
+	for _, trial := range result.Trials {
+		for _, v := range result.Embedding.Params(trial.Params) {
+			assert.Equal(t, math.Round(v), v)
+		}
+	}
+}
+
+func TestOptimizeEmbeddedInvalid(t *testing.T) {
+	hypers := []ParameterRange[float64]{{Min: 0, Max: 1}, {Min: 0, Max: 1}}
+
+	warm := fastConfig()
+	warm.WarmStart = []Observation{{Params: []float64{0.5, 0.5}, Value: 1}}
+
+	zones := fastConfig()
+	zones.ExclusionZones = []Box{{Min: []float64{0, 0}, Max: []float64{0.1, 0.1}}}
+
+	tests := []struct {
+		name      string
+		config    OptimizationConfig
+		embedding RandomEmbedding
+		hypers    []ParameterRange[float64]
+	}{
+		{name: "too many dimensions", config: fastConfig(), embedding: RandomEmbedding{Dimensions: 3}, hypers: hypers},
+		{name: "negative dimensions", config: fastConfig(), embedding: RandomEmbedding{Dimensions: -1}, hypers: hypers},
+		{name: "no range", config: fastConfig()},
+		{name: "invalid range", config: fastConfig(), hypers: []ParameterRange[float64]{{Type: BoolParameter, Max: 2, Step: 1}}},
+		{name: "warm start", config: warm, hypers: hypers},
+		{name: "exclusion zones", config: zones, hypers: hypers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best, result := OptimizeObjectiveEmbedded(tt.config, tt.embedding, func(params ...float64) (float64, error) {
+				t.Fatal("the objective must not run")
+
+				return 0, nil
+			}, tt.hypers...)
+
+			assert.Nil(t, best)
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+			assert.Equal(t, TerminationInvalidConfig, result.TerminationReason)
+		})
+	}
+}
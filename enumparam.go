@@ -0,0 +1,108 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// EnumParam is a parameter choosing among named values, e.g. compression
+// codecs, see Range.
+//
+// Important notes:
+// - Values are indexes into Values, always whole numbers, both when sampled
+// and when proposed by the surrogate
+// - Results render them as the chosen value, e.g. "zstd", see
+// ParameterSpec.Format and TrialRecord.Formatted
+// - Benchmarks receive indexes, decoded by Value, or the value itself in
+// string fields with OptimizeStruct.
+type EnumParam struct {
+	// Name optionally names the parameter, see ParameterRange.Name.
+	Name string
+
+	// Values are the choices, at least one, without duplicates.
+	Values []string
+}
+
+//////
+// Methods.
+//////
+
+// Range returns the parameter range of the choices to pass to Optimize along
+// with other ranges.
+//
+// Returns:
+// - ParameterRange[float64]: The range of indexes, typed EnumParameter.
+//
+// Usage example:
+//
+//	codec := EnumParam{Name: "Codec", Values: []string{"zstd", "lz4", "none"}}
+//
+//	result := Optimize(config, func(params ...float64) error {
+//	    return runWorkload(codec.Value(params[0]))
+//	}, codec.Range())
+//
+//	fmt.Println(result.FormatParams(result.BestParams)) // Codec=lz4
+func (p EnumParam) Range() ParameterRange[float64] {
+	return ParameterRange[float64]{
+		Name:   p.Name,
+		Type:   EnumParameter,
+		Min:    0,
+		Max:    float64(len(p.Values) - 1),
+		Step:   1,
+		Values: append([]string(nil), p.Values...),
+	}
+}
+
+// Value returns the choice at index v, as passed to benchmarks.
+//
+// Parameters:
+// - v: The index, rounded and clamped to the choices
+//
+// Returns:
+// - string: The choice, empty if there's none.
+func (p EnumParam) Value(v float64) string {
+	return enumValue(p.Values, v)
+}
+
+//////
+// Helpers.
+//////
+
+// enumValue returns the choice at index v, rounded and clamped to the
+// choices, empty if there's none.
+func enumValue(values []string, v float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	i := int(clamp(math.Round(v), 0, float64(len(values)-1)))
+
+	return values[i]
+}
+
+// validateEnum checks the choices of an enum parameter.
+//
+// Returns:
+// - error: Describing the first issue if there's no choice or a duplicate
+// one, nil otherwise.
+func validateEnum(values []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("enum parameters need at least one value")
+	}
+
+	seen := make(map[string]bool, len(values))
+
+	for _, value := range values {
+		if seen[value] {
+			return fmt.Errorf("duplicate value %q", value)
+		}
+
+		seen[value] = true
+	}
+
+	return nil
+}
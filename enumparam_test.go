@@ -0,0 +1,128 @@
+package ho
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// codecCost is the cost of a compression codec, lz4 being the cheapest.
+var codecCost = map[string]float64{"zstd": 3, "lz4": 1, "none": 5}
+
+func TestEnumParam(t *testing.T) {
+	codec := EnumParam{Name: "Codec", Values: []string{"zstd", "lz4", "none"}}
+
+	assert.Equal(t, ParameterRange[float64]{
+		Name:   "Codec",
+		Type:   EnumParameter,
+		Max:    2,
+		Step:   1,
+		Values: []string{"zstd", "lz4", "none"},
+	}, codec.Range())
+
+	assert.Equal(t, "lz4", codec.Value(1.2))
+	assert.Equal(t, "none", codec.Value(7))
+	assert.Equal(t, "", EnumParam{}.Value(0))
+
+	progress := make(chan ProgressUpdate, 20)
+
+	config := fastConfig()
+	config.ProgressChan = progress
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		value := codec.Value(params[0])
+
+		assert.Contains(t, codecCost, value)
+
+		return codecCost[value], nil
+	}, codec.Range())
+
+	close(progress)
+
+	assert.NoError(t, result.Err)
+	assert.Equal(t, "Codec=lz4", result.FormatParams(result.BestParams))
+
+	for update := range progress {
+		assert.Contains(t, codecCost, update.BestFormatted["Codec"])
+
+		if update.Phase != PhaseDone {
+			assert.Contains(t, codecCost, update.CurrentFormatted["Codec"])
+		}
+	}
+
+	// Records carry the chosen values, not the indexes only.
+	records := result.Records()
+
+	data, err := json.Marshal(records)
+	assert.NoError(t, err)
+
+	for _, record := range records {
+		value := record.Formatted["Codec"]
+
+		assert.Equal(t, codec.Value(record.Params["Codec"]), value)
+		assert.Contains(t, string(data), `"formatted":{"Codec":"`+value+`"}`)
+	}
+
+	// Exported as categorical distributions, imported back as enums.
+	var buf bytes.Buffer
+
+	assert.NoError(t, ExportOptunaJSON(&buf, "codec", result, codec.Range()))
+	assert.Contains(t, buf.String(), `"Codec": "lz4"`)
+
+	space, observations, err := ImportOptunaJSON(&buf)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []ParameterRange[float64]{codec.Range()}, Ranges[float64](space))
+	assert.Len(t, observations, len(result.Trials))
+}
+
+func TestOptimizeStructEnum(t *testing.T) {
+	type settings struct {
+		Codec string `ho:"values=zstd|lz4|none"`
+		Level int    `ho:"min=1,max=9"`
+	}
+
+	best, result := OptimizeStruct(fastConfig(), func(s settings) error {
+		assert.Contains(t, codecCost, s.Codec)
+
+		return nil
+	}, settings{})
+
+	assert.NoError(t, result.Err)
+	assert.Contains(t, codecCost, best.Codec)
+	assert.Equal(t, EnumParam{Values: []string{"zstd", "lz4", "none"}}.Value(result.BestParams[0]), best.Codec)
+}
+
+func TestEnumParamInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		hyper ParameterRange[float64]
+	}{
+		{name: "no values", hyper: EnumParam{}.Range()},
+		{name: "duplicate values", hyper: EnumParam{Values: []string{"zstd", "lz4", "zstd"}}.Range()},
+		{name: "widened", hyper: ParameterRange[float64]{Type: EnumParameter, Max: 5, Step: 1, Values: []string{"zstd"}}},
+		{name: "values of a number", hyper: ParameterRange[float64]{Max: 1, Values: []string{"zstd", "lz4"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Optimize(fastConfig(), func(params ...float64) error { return nil }, tt.hyper)
+
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+		})
+	}
+
+	for doc, want := range map[string]string{
+		"parameters: [{name: a, type: enum}]":                     "parameters[0].values: enum parameters need at least one value",
+		"parameters: [{name: a, type: enum, values: [a, b, a]}]":  `parameters[0].values: duplicate value "a"`,
+		"parameters: [{name: a, type: int, max: 1, values: [a]}]": "parameters[0].values: only enum parameters",
+	} {
+		_, _, err := LoadConfig(strings.NewReader(doc))
+
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, want)
+	}
+}
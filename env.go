@@ -0,0 +1,272 @@
+package ho
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// DefaultEnvPrefix is the prefix of the variables read by ApplyEnvOverrides
+// if none is given.
+const DefaultEnvPrefix = "HO"
+
+// EnvVariable is an environment variable honored by ApplyEnvOverrides.
+type EnvVariable struct {
+	// Name is the name of the variable, e.g. "HO_ITERATIONS".
+	Name string
+
+	// Field is the OptimizationConfig field it overrides, e.g.
+	// "Iterations".
+	Field string
+
+	// Description tells what values are accepted.
+	Description string
+
+	// Value is the value of the variable in the environment, empty if
+	// unset.
+	Value string
+
+	// Set is true if the variable is set, and so overrides Field.
+	Set bool
+}
+
+// envOverride is a configuration field that can be overridden from the
+// environment.
+type envOverride struct {
+	// suffix is the name of the variable, without the prefix.
+	suffix string
+
+	// field is the overridden field.
+	field string
+
+	// description tells what values are accepted.
+	description string
+
+	// apply parses the value, and sets the field.
+	apply func(config *OptimizationConfig, value string) error
+}
+
+// envOverrides are the variables honored by ApplyEnvOverrides, in the order
+// they're applied.
+var envOverrides = []envOverride{
+	{"ITERATIONS", "Iterations", "non-negative integer", envInt(0, func(c *OptimizationConfig) *int { return &c.Iterations })},
+	{"INITIAL_SAMPLES", "InitialSamples", "positive integer", envInt(1, func(c *OptimizationConfig) *int { return &c.InitialSamples })},
+	{"NUM_CANDIDATES", "NumCandidates", "non-negative integer, 0 to derive it", envInt(0, func(c *OptimizationConfig) *int { return &c.NumCandidates })},
+	{"SEED", "Seed", "integer, 0 to seed from the clock", envSeed},
+	{"MAX_DURATION", "TimeBudget", `non-negative duration, e.g. "30m", 0 for none`, envDuration(func(c *OptimizationConfig) *time.Duration { return &c.TimeBudget })},
+	{"TRIAL_TIMEOUT", "TrialTimeout", `non-negative duration, e.g. "30s", 0 for none`, envDuration(func(c *OptimizationConfig) *time.Duration { return &c.TrialTimeout })},
+	{"MAX_CANDIDATE_SELECTION_TIME", "MaxCandidateSelectionTime", `non-negative duration, e.g. "50ms", 0 for none`, envDuration(func(c *OptimizationConfig) *time.Duration { return &c.MaxCandidateSelectionTime })},
+	{"MAX_SKIP_RETRIES", "MaxSkipRetries", "non-negative integer", envInt(0, func(c *OptimizationConfig) *int { return &c.MaxSkipRetries })},
+	{"SEEDS_PER_TRIAL", "SeedsPerTrial", "non-negative integer, 0 or 1 for a single seed", envInt(0, func(c *OptimizationConfig) *int { return &c.SeedsPerTrial })},
+	{"MAX_CONCURRENT_EVALUATIONS", "MaxConcurrentEvaluations", "non-negative integer", envInt(0, func(c *OptimizationConfig) *int { return &c.MaxConcurrentEvaluations })},
+	{"ACQUISITION", "AcquisitionFunc", "name of a registered acquisition function, see LookupAcquisition", envAcquisition},
+}
+
+//////
+// Helpers.
+//////
+
+// envPrefix returns the prefix of the variables, with its separator.
+func envPrefix(prefix string) string {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	return prefix + "_"
+}
+
+// envInt returns an apply function parsing an integer of at least minimum.
+func envInt(minimum int, field func(c *OptimizationConfig) *int) func(*OptimizationConfig, string) error {
+	return func(config *OptimizationConfig, value string) error {
+		v, err := strconv.Atoi(value)
+
+		switch {
+		case err != nil:
+			return fmt.Errorf("%q isn't an integer", value)
+		case v < minimum:
+			return fmt.Errorf("%d is less than %d", v, minimum)
+		}
+
+		*field(config) = v
+
+		return nil
+	}
+}
+
+// envDuration returns an apply function parsing a non-negative duration, see
+// time.ParseDuration.
+func envDuration(field func(c *OptimizationConfig) *time.Duration) func(*OptimizationConfig, string) error {
+	return func(config *OptimizationConfig, value string) error {
+		d, err := time.ParseDuration(value)
+
+		switch {
+		case err != nil:
+			return err
+		case d < 0:
+			return fmt.Errorf("%v is negative", d)
+		}
+
+		*field(config) = d
+
+		return nil
+	}
+}
+
+// envSeed parses the seed. A fixed seed seeds AcqParams.RandomState too, as
+// LoadConfig does, so Thompson Sampling runs are reproducible.
+func envSeed(config *OptimizationConfig, value string) error {
+	seed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%q isn't an integer", value)
+	}
+
+	config.Seed = seed
+
+	if seed != 0 {
+		config.AcqParams.RandomState = rand.New(rand.NewSource(seed))
+	}
+
+	return nil
+}
+
+// envAcquisition looks the acquisition function up by name.
+func envAcquisition(config *OptimizationConfig, value string) error {
+	acquisition, ok := LookupAcquisition(value)
+	if !ok {
+		return fmt.Errorf("unknown acquisition function %q", value)
+	}
+
+	config.SetAcquisition(acquisition)
+
+	return nil
+}
+
+// applyEnv applies the variables of the environment, as returned by
+// os.Environ, see ApplyEnvOverrides.
+func applyEnv(prefix string, config *OptimizationConfig, environ []string) error {
+	prefix = envPrefix(prefix)
+
+	values := make(map[string]string)
+
+	var names []string
+
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+
+		if strings.HasPrefix(name, prefix) {
+			values[name] = value
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	known := make(map[string]bool, len(envOverrides))
+
+	for _, o := range envOverrides {
+		known[prefix+o.suffix] = true
+	}
+
+	for _, name := range names {
+		if !known[name] {
+			return fmt.Errorf("%w: unknown environment variable %s, see EnvVariables", ErrInvalidConfig, name)
+		}
+	}
+
+	// Apply to a copy, so config is left untouched on error.
+	overridden := *config
+
+	for _, o := range envOverrides {
+		value, ok := values[prefix+o.suffix]
+		if !ok {
+			continue
+		}
+
+		if err := o.apply(&overridden, strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("%w: %s%s: %w, expected a %s", ErrInvalidConfig, prefix, o.suffix, err, o.description)
+		}
+	}
+
+	*config = overridden
+
+	return nil
+}
+
+//////
+// Exported functionalities.
+//////
+
+// ApplyEnvOverrides overrides fields of the configuration with environment
+// variables, e.g. to tweak a run launched from a fixed binary in CI, see
+// EnvVariables for the honored ones:
+//
+//	HO_ITERATIONS=200 HO_MAX_DURATION=45m HO_SEED=7 ./tune
+//
+// Parameters:
+// - prefix: Prefix of the variables, separated from their name by "_". If
+// empty, DefaultEnvPrefix is used
+// - config: The configuration to override
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a variable with the prefix isn't
+// honored, e.g. a typo, or its value is malformed or out of range. config is
+// then left untouched.
+//
+// Usage example:
+//
+//	config, space, err := LoadConfig(f)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	if err := ApplyEnvOverrides("HO", &config); err != nil {
+//	    return err
+//	}
+//
+// Important notes:
+// - Variables take precedence over the values config holds, from code or a
+// configuration file: call it last, or before the assignments that should
+// win, e.g. from command line flags
+// - Variables are applied in the order of EnvVariables, ACQUISITION last.
+func ApplyEnvOverrides(prefix string, config *OptimizationConfig) error {
+	return applyEnv(prefix, config, os.Environ())
+}
+
+// EnvVariables returns the environment variables honored by
+// ApplyEnvOverrides, in the order they're applied, with their value in the
+// environment, e.g. to log which ones override the configuration.
+//
+// Parameters:
+// - prefix: Prefix of the variables, see ApplyEnvOverrides
+//
+// Returns:
+// - []EnvVariable: The variables.
+func EnvVariables(prefix string) []EnvVariable {
+	prefix = envPrefix(prefix)
+
+	variables := make([]EnvVariable, len(envOverrides))
+
+	for i, o := range envOverrides {
+		name := prefix + o.suffix
+
+		value, set := os.LookupEnv(name)
+
+		variables[i] = EnvVariable{
+			Name:        name,
+			Field:       o.field,
+			Description: o.description,
+			Value:       value,
+			Set:         set,
+		}
+	}
+
+	return variables
+}
@@ -0,0 +1,110 @@
+package ho
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Run("variables", func(t *testing.T) {
+		tests := []struct {
+			env   string
+			check func(t *testing.T, config OptimizationConfig)
+		}{
+			{"HO_ITERATIONS=200", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 200, c.Iterations) }},
+			{"HO_ITERATIONS=0", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 0, c.Iterations) }},
+			{"HO_INITIAL_SAMPLES=12", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 12, c.InitialSamples) }},
+			{"HO_NUM_CANDIDATES=500", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 500, c.NumCandidates) }},
+			{"HO_SEED=-7", func(t *testing.T, c OptimizationConfig) {
+				assert.Equal(t, int64(-7), c.Seed)
+				assert.Equal(t, rand.New(rand.NewSource(-7)).Float64(), c.AcqParams.RandomState.Float64())
+			}},
+			{"HO_MAX_DURATION=45m", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 45*time.Minute, c.TimeBudget) }},
+			{"HO_TRIAL_TIMEOUT=1m30s", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 90*time.Second, c.TrialTimeout) }},
+			{"HO_MAX_CANDIDATE_SELECTION_TIME=50ms", func(t *testing.T, c OptimizationConfig) {
+				assert.Equal(t, 50*time.Millisecond, c.MaxCandidateSelectionTime)
+			}},
+			{"HO_MAX_SKIP_RETRIES=3", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 3, c.MaxSkipRetries) }},
+			{"HO_SEEDS_PER_TRIAL=5", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 5, c.SeedsPerTrial) }},
+			{"HO_MAX_CONCURRENT_EVALUATIONS=4", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 4, c.MaxConcurrentEvaluations) }},
+			{"HO_ACQUISITION=LowerConfidenceBound", func(t *testing.T, c OptimizationConfig) {
+				assert.Equal(t, MinimizeAcquisition, c.AcquisitionDirection)
+				assert.NotNil(t, c.AcquisitionFunc)
+			}},
+			{"HO_ITERATIONS= 7 ", func(t *testing.T, c OptimizationConfig) { assert.Equal(t, 7, c.Iterations) }},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.env, func(t *testing.T) {
+				config := DefaultConfig()
+
+				if assert.NoError(t, applyEnv("", &config, []string{"PATH=/bin", tt.env})) {
+					tt.check(t, config)
+				}
+			})
+		}
+
+		// Every honored variable is covered.
+		assert.Len(t, EnvVariables(""), len(tests)-2)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name string
+			env  string
+			want string
+		}{
+			{"unknown", "HO_ITERATION=200", "HO_ITERATION"},
+			{"not an integer", "HO_ITERATIONS=many", "HO_ITERATIONS"},
+			{"negative", "HO_NUM_CANDIDATES=-1", "HO_NUM_CANDIDATES"},
+			{"below minimum", "HO_INITIAL_SAMPLES=0", "HO_INITIAL_SAMPLES"},
+			{"fractional", "HO_MAX_SKIP_RETRIES=1.5", "HO_MAX_SKIP_RETRIES"},
+			{"seed overflow", "HO_SEED=99999999999999999999", "HO_SEED"},
+			{"duration without unit", "HO_MAX_DURATION=30", "HO_MAX_DURATION"},
+			{"negative duration", "HO_TRIAL_TIMEOUT=-1s", "HO_TRIAL_TIMEOUT"},
+			{"empty", "HO_MAX_CANDIDATE_SELECTION_TIME=", "HO_MAX_CANDIDATE_SELECTION_TIME"},
+			{"unknown acquisition", "HO_ACQUISITION=Greedy", "Greedy"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				config := DefaultConfig()
+				config.Iterations = 42
+
+				err := applyEnv("", &config, []string{"HO_ITERATIONS=100", tt.env})
+
+				assert.ErrorIs(t, err, ErrInvalidConfig)
+				assert.ErrorContains(t, err, tt.want)
+
+				// Left untouched.
+				assert.Equal(t, 42, config.Iterations)
+			})
+		}
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		t.Setenv("TUNE_ITERATIONS", "77")
+		t.Setenv("TUNE_SEED", "9")
+		t.Setenv("HO_ITERATIONS", "not read")
+
+		config := DefaultConfig()
+
+		if assert.NoError(t, ApplyEnvOverrides("TUNE", &config)) {
+			assert.Equal(t, 77, config.Iterations)
+			assert.Equal(t, int64(9), config.Seed)
+		}
+
+		variables := EnvVariables("TUNE")
+
+		assert.Equal(t, EnvVariable{Name: "TUNE_ITERATIONS", Field: "Iterations", Description: "non-negative integer", Value: "77", Set: true}, variables[0])
+
+		for _, v := range variables[1:] {
+			assert.Equal(t, v.Name == "TUNE_SEED", v.Set, v.Name)
+		}
+
+		assert.Equal(t, "HO_ITERATIONS", EnvVariables("")[0].Name)
+	})
+}
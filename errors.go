@@ -0,0 +1,115 @@
+package ho
+
+import "errors"
+
+//////
+// Sentinel errors.
+//////
+
+// ErrInvalidConfig is returned (wrapped) in Result.Err when the optimization
+// can't start because of an invalid configuration or search space. The run
+// then has termination reason TerminationInvalidConfig, and no benchmark
+// invocation happens.
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ErrSkipTrial can be returned (or wrapped) by a benchmark function to signal
+// that the current measurement was invalidated by something unrelated to the
+// parameters being tested, e.g. a deploy happened mid-measurement or the load
+// generator hiccuped.
+//
+// When the optimizer detects it (via errors.Is):
+// - The trial is recorded with status TrialSkipped
+// - The Gaussian Process model is NOT updated
+// - The best parameters and best time are NOT updated
+// - The trial is NOT treated as a failure
+// - A replacement evaluation is drawn if OptimizationConfig.MaxSkipRetries
+// allows it, so the budget isn't silently shortened
+//
+// Usage example:
+//
+//	benchmark := func(params ...int) error {
+//	    if deployInProgress() {
+//	        return ho.ErrSkipTrial
+//	    }
+//
+//	    return runWorkload(params[0])
+//	}
+var ErrSkipTrial = errors.New("trial skipped")
+
+// ErrStopOptimization can be returned (or wrapped) by a benchmark function to
+// signal that continuing the optimization is pointless or dangerous, e.g. the
+// error budget of the system under test is exhausted.
+//
+// When the optimizer detects it (via errors.Is):
+// - The trial is recorded as failed
+// - No further benchmark invocations happen, in any phase
+// - The best found so far is returned, with termination reason
+// TerminationBenchmarkRequestedStop
+// - The returned error (including what it wraps) is preserved in Result.Err
+//
+// Usage example:
+//
+//	benchmark := func(params ...int) error {
+//	    if errorBudgetExhausted() {
+//	        return fmt.Errorf("error budget exhausted: %w", ho.ErrStopOptimization)
+//	    }
+//
+//	    return runWorkload(params[0])
+//	}
+var ErrStopOptimization = errors.New("optimization stopped by benchmark")
+
+// ErrSafetyViolation is wrapped in Result.Err when a trial violated the
+// safety limit and the run was aborted, see Safety.AbortOnViolation.
+var ErrSafetyViolation = errors.New("safety limit violated")
+
+// ErrNonFiniteValue is wrapped in Trial.Err when the objective returned NaN
+// or an infinite value, which is then handled according to
+// OptimizationConfig.NonFiniteValues.
+var ErrNonFiniteValue = errors.New("non-finite value")
+
+// ErrObjectiveTransform is wrapped in Trial.Err when
+// OptimizationConfig.ObjectiveTransform failed, or returned a non-finite
+// value, failing the trial.
+var ErrObjectiveTransform = errors.New("objective transform failed")
+
+// ErrBindParams is returned (wrapped) by BindParams and Result.Scan when the
+// parameters can't be bound to the destination struct, e.g. a parameter has
+// no matching field, or the field type isn't numeric.
+var ErrBindParams = errors.New("can't bind parameters")
+
+// ErrUnknownTrial is returned (wrapped) by Optimizer.Tell when the trial ID
+// doesn't match a pending suggestion, e.g. it was never handed out, or its
+// result was already told, and by Result.ReplayTrial when the result has no
+// such trial.
+var ErrUnknownTrial = errors.New("unknown trial")
+
+// ErrLeaseExpired is returned (wrapped) by Optimizer.Tell when the suggestion
+// was abandoned because its lease expired, unless late tells are accepted,
+// see OptimizationConfig.LeaseTimeout.
+var ErrLeaseExpired = errors.New("suggestion lease expired")
+
+// ErrInvalidObservation is returned (wrapped) by SurrogateModel.Update when
+// the observation would corrupt the model, e.g. it has a non-finite value.
+// The model is left untouched.
+var ErrInvalidObservation = errors.New("invalid observation")
+
+// ErrStudyInProgress is returned (wrapped) by Optimizer.Reset when the study
+// of the handle isn't over: suggestions are pending, or abandoned ones may
+// still be told, see OptimizationConfig.AcceptLateTells. Optimizer.Restore
+// returns it when the handle was already asked.
+var ErrStudyInProgress = errors.New("study in progress")
+
+// ErrStaleStudy is returned (wrapped) by Optimizer.TellVersion when the
+// result belongs to a study the handle was reset from, see
+// Optimizer.StudyVersion.
+var ErrStaleStudy = errors.New("stale study version")
+
+// ErrOptimizerClosed is returned (wrapped) by Optimizer.Ask and Tell once the
+// study of the handle was closed, until Reset starts a new one, see
+// Optimizer.Close.
+var ErrOptimizerClosed = errors.New("optimizer closed")
+
+// ErrTooFewMeasurements is returned (wrapped) by Result.BestConfidence and
+// Result.ImprovementConfidence when a configuration was measured only once,
+// as a single measurement tells nothing about its spread.
+var ErrTooFewMeasurements = errors.New("too few measurements")
@@ -0,0 +1,23 @@
+// Package eval compares optimizer configurations on a problem, e.g. to pick
+// the acquisition function that works best for a family of workloads,
+// without writing the experiment loop:
+//
+//	report, err := eval.Run(ctx, eval.FromFunction(benchfuncs.Branin()), []eval.Config{
+//	    {Name: "EI", Config: ei},
+//	    {Name: "LCB", Config: lcb},
+//	}, eval.Options{Repetitions: 10, Budget: 40})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	for _, c := range report.Configs {
+//	    fmt.Printf("%s: median best %.4g\n", c.Name, c.Median)
+//	}
+//
+// Every configuration runs Repetitions times with Budget evaluations each.
+// Repetition r of every configuration uses the same seed, so the comparison
+// is paired: configurations see the same target noise, and the report counts
+// how often each one beats each other one. Runs are sequential, so reports
+// are reproducible as long as the configurations evaluate one trial at a
+// time.
+package eval
@@ -0,0 +1,424 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/thalesfsp/ho"
+	"github.com/thalesfsp/ho/benchfuncs"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultRepetitions is the Options.Repetitions used if unset.
+	defaultRepetitions = 5
+
+	// defaultSeed is the Options.Seed used if unset.
+	defaultSeed = 1
+)
+
+// Target is the problem the configurations are compared on.
+type Target struct {
+	// Name identifies the target in the report.
+	Name string
+
+	// Ranges is the search space.
+	Ranges []ho.ParameterRange[float64]
+
+	// Objective returns the objective of a run, called once per run with
+	// its seed, e.g. to seed measurement noise, so repetitions are paired
+	// across configurations.
+	Objective func(seed int64) ho.ObjectiveFuncCtx[float64]
+}
+
+// Config is a configuration to compare.
+type Config struct {
+	// Name identifies the configuration in the report, e.g. "EI".
+	Name string
+
+	// Config configures the runs. Its Seed and Iterations are set by Run,
+	// see Options.
+	Config ho.OptimizationConfig
+}
+
+// Options configures Run.
+type Options struct {
+	// Repetitions is the number of runs of each configuration.
+	// If 0, 5 is used.
+	Repetitions int
+
+	// Budget is the number of evaluations of each run, initial samples
+	// included: runs make Budget minus InitialSamples iterations.
+	Budget int
+
+	// Seed is the seed of the first repetition, the next ones using the
+	// following seeds.
+	// If 0, 1 is used.
+	Seed int64
+}
+
+// Report is the outcome of Run.
+type Report struct {
+	// Target is the name of the target.
+	Target string `json:"target"`
+
+	// Budget is the number of evaluations of each run.
+	Budget int `json:"budget"`
+
+	// Seeds holds the seed of each repetition.
+	Seeds []int64 `json:"seeds"`
+
+	// Configs holds the report of each configuration, in order.
+	Configs []ConfigReport `json:"configs"`
+
+	// WinRates holds, for each pair of configurations, the share of
+	// repetitions in which the first found a lower best value than the
+	// second, ties counting half: WinRates[i][j] + WinRates[j][i] is 1, and
+	// WinRates[i][i] is 0.5.
+	WinRates [][]float64 `json:"winRates"`
+}
+
+// ConfigReport summarizes the runs of a configuration.
+type ConfigReport struct {
+	// Name is the name of the configuration.
+	Name string `json:"name"`
+
+	// Mean, Median and StdDev summarize the best values of the runs.
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stdDev"`
+
+	// Convergence holds the mean, across runs, of the best value after
+	// each evaluation. Runs without a completed trial yet are left out,
+	// and entries are math.MaxFloat64 if no run has one.
+	Convergence []float64 `json:"convergence"`
+
+	// Runs holds each run, one per repetition.
+	Runs []RunReport `json:"runs"`
+}
+
+// RunReport is a run of a configuration.
+type RunReport struct {
+	// Seed is the seed of the run.
+	Seed int64 `json:"seed"`
+
+	// BestValue is the best value found, math.MaxFloat64 if no trial
+	// completed.
+	BestValue float64 `json:"bestValue"`
+
+	// BestParams holds the parameters of BestValue.
+	BestParams []float64 `json:"bestParams"`
+
+	// Trials holds the trials of the run, in completion order.
+	Trials []ho.TrialRecord `json:"trials"`
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the options against the target and configurations.
+//
+// Returns:
+// - error: Wrapping ho.ErrInvalidConfig if an option is invalid, nil
+// otherwise.
+func (o Options) validate(target Target, configs []Config) error {
+	switch {
+	case o.Repetitions < 0:
+		return fmt.Errorf("%w: Repetitions %d is negative", ho.ErrInvalidConfig, o.Repetitions)
+	case target.Objective == nil:
+		return fmt.Errorf("%w: target %q has no Objective", ho.ErrInvalidConfig, target.Name)
+	case len(target.Ranges) == 0:
+		return fmt.Errorf("%w: target %q has no Ranges", ho.ErrInvalidConfig, target.Name)
+	case len(configs) == 0:
+		return fmt.Errorf("%w: no configuration to compare", ho.ErrInvalidConfig)
+	}
+
+	names := make(map[string]bool, len(configs))
+
+	for i, c := range configs {
+		switch {
+		case c.Name == "":
+			return fmt.Errorf("%w: configuration %d has no Name", ho.ErrInvalidConfig, i)
+		case names[c.Name]:
+			return fmt.Errorf("%w: duplicate configuration %q", ho.ErrInvalidConfig, c.Name)
+		case o.Budget <= c.Config.InitialSamples:
+			return fmt.Errorf("%w: Budget %d leaves no iteration to %q after %d initial samples",
+				ho.ErrInvalidConfig, o.Budget, c.Name, c.Config.InitialSamples)
+		}
+
+		names[c.Name] = true
+	}
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// runConfig runs a configuration once per seed.
+func runConfig(ctx context.Context, target Target, c Config, budget int, seeds []int64) (ConfigReport, error) {
+	report := ConfigReport{Name: c.Name}
+
+	curves := make([][]float64, len(seeds))
+
+	for r, seed := range seeds {
+		config := c.Config
+		config.Seed = seed
+		config.Iterations = budget - config.InitialSamples
+
+		result := ho.OptimizeObjectiveWithContext(ctx, config, target.Objective(seed), target.Ranges...)
+		if result.Err != nil {
+			return report, fmt.Errorf("%s, seed %d: %w", c.Name, seed, result.Err)
+		}
+
+		report.Runs = append(report.Runs, RunReport{
+			Seed:       seed,
+			BestValue:  result.BestTime,
+			BestParams: result.BestParams,
+			Trials:     result.Records(),
+		})
+
+		curves[r] = bestSoFar(result.Trials, budget)
+	}
+
+	best := make([]float64, len(report.Runs))
+
+	for r, run := range report.Runs {
+		best[r] = run.BestValue
+	}
+
+	report.Mean, report.StdDev = meanStdDev(best)
+	report.Median = median(best)
+	report.Convergence = meanCurve(curves, budget)
+
+	return report, nil
+}
+
+// bestSoFar returns the best value of the completed trials after each
+// evaluation, NaN before the first one. Runs ended early keep their last
+// value.
+func bestSoFar(trials []ho.Trial[float64], budget int) []float64 {
+	curve := make([]float64, budget)
+
+	best := math.NaN()
+
+	for i := range curve {
+		if i < len(trials) && trials[i].Status == ho.TrialCompleted && !(trials[i].ExecutionTime >= best) {
+			best = trials[i].ExecutionTime
+		}
+
+		curve[i] = best
+	}
+
+	return curve
+}
+
+// meanCurve returns the mean of the curves at each evaluation, ignoring NaN
+// values, math.MaxFloat64 if they're all NaN.
+func meanCurve(curves [][]float64, budget int) []float64 {
+	mean := make([]float64, budget)
+
+	for i := range mean {
+		var (
+			sum   float64
+			count int
+		)
+
+		for _, curve := range curves {
+			if !math.IsNaN(curve[i]) {
+				sum += curve[i]
+				count++
+			}
+		}
+
+		mean[i] = math.MaxFloat64
+
+		if count > 0 {
+			mean[i] = sum / float64(count)
+		}
+	}
+
+	return mean
+}
+
+// winRates returns the pairwise win rates of the configurations, see
+// Report.WinRates.
+func winRates(configs []ConfigReport) [][]float64 {
+	rates := make([][]float64, len(configs))
+
+	for i, a := range configs {
+		rates[i] = make([]float64, len(configs))
+
+		for j, b := range configs {
+			var wins float64
+
+			for r := range a.Runs {
+				switch x, y := a.Runs[r].BestValue, b.Runs[r].BestValue; {
+				case x < y:
+					wins++
+				case x == y:
+					wins += 0.5
+				}
+			}
+
+			rates[i][j] = wins / float64(len(a.Runs))
+		}
+	}
+
+	return rates
+}
+
+// meanStdDev returns the mean and sample standard deviation of the values,
+// the deviation being 0 for a single value.
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+
+	for _, v := range values {
+		sum += v
+	}
+
+	mean := sum / float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	var squares float64
+
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(squares / float64(len(values)-1))
+}
+
+// median returns the median of the values.
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+//////
+// Factory.
+//////
+
+// FromFunction creates a target from a synthetic function, searched within
+// its documented bounds.
+//
+// Parameters:
+// - f: The function
+//
+// Returns:
+// - Target: The target, its objective the same for every seed.
+func FromFunction(f benchfuncs.Function) Target {
+	ranges := make([]ho.ParameterRange[float64], len(f.Bounds))
+
+	for i, b := range f.Bounds {
+		ranges[i] = ho.ParameterRange[float64]{Min: b.Min, Max: b.Max}
+	}
+
+	return Target{
+		Name:   f.Name,
+		Ranges: ranges,
+		Objective: func(int64) ho.ObjectiveFuncCtx[float64] {
+			return func(_ context.Context, params ...float64) (float64, error) {
+				return f.Objective(params...)
+			}
+		},
+	}
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Run runs every configuration on the target, Repetitions times each, and
+// compares them, see the package documentation.
+//
+// Parameters:
+// - ctx: Context of the runs, see ho.OptimizeObjectiveWithContext
+// - target: The problem
+// - configs: The configurations to compare, e.g. differing in acquisition
+// function, schedule or CandidateMix
+// - options: Configures the comparison
+//
+// Returns:
+// - *Report: Summaries, convergence curves and win rates
+// - error: Wrapping ho.ErrInvalidConfig if an option or a configuration is
+// invalid, or the error of the first failed run, e.g. the context's.
+//
+// Usage example:
+//
+//	noisy := eval.Target{
+//	    Name:   "noisy Branin",
+//	    Ranges: ranges,
+//	    Objective: func(seed int64) ho.ObjectiveFuncCtx[float64] {
+//	        objective := benchfuncs.WithNoise(branin.Objective, 0.1, rand.New(rand.NewSource(seed)))
+//
+//	        return func(_ context.Context, params ...float64) (float64, error) {
+//	            return objective(params...)
+//	        }
+//	    },
+//	}
+//
+//	report, err := eval.Run(ctx, noisy, configs, eval.Options{Budget: 30})
+//
+// Important notes:
+// - Best values are the runs' Result.BestTime, as measured, so with noise
+// they include lucky measurements
+// - Run errors abort the comparison, failed trials don't: they count
+// against the budget.
+func Run(ctx context.Context, target Target, configs []Config, options Options) (*Report, error) {
+	if err := options.validate(target, configs); err != nil {
+		return nil, err
+	}
+
+	repetitions := options.Repetitions
+	if repetitions == 0 {
+		repetitions = defaultRepetitions
+	}
+
+	seed := options.Seed
+	if seed == 0 {
+		seed = defaultSeed
+	}
+
+	report := &Report{
+		Target: target.Name,
+		Budget: options.Budget,
+		Seeds:  make([]int64, repetitions),
+	}
+
+	for r := range report.Seeds {
+		report.Seeds[r] = seed + int64(r)
+	}
+
+	for _, c := range configs {
+		configReport, err := runConfig(ctx, target, c, options.Budget, report.Seeds)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Configs = append(report.Configs, configReport)
+	}
+
+	report.WinRates = winRates(report.Configs)
+
+	return report, nil
+}
@@ -0,0 +1,204 @@
+package eval
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho"
+	"github.com/thalesfsp/ho/benchfuncs"
+)
+
+// matrix is a tiny comparison matrix: two acquisition functions, and
+// perturbations of the incumbent.
+func matrix() []Config {
+	ei := ho.DefaultConfig()
+	ei.InitialSamples = 4
+	ei.NumCandidates = 50
+	ei.SetAcquisition(mustLookup("ExpectedImprovement"))
+
+	lcb := ei
+	lcb.SetAcquisition(mustLookup("LowerConfidenceBound"))
+
+	local := lcb
+	local.CandidateMix = ho.CandidateMix{Incumbent: 0.5}
+
+	return []Config{{Name: "EI", Config: ei}, {Name: "LCB", Config: lcb}, {Name: "LCB local", Config: local}}
+}
+
+// mustLookup returns a registered acquisition function.
+func mustLookup(name string) ho.Acquisition {
+	acquisition, ok := ho.LookupAcquisition(name)
+	if !ok {
+		panic(name)
+	}
+
+	return acquisition
+}
+
+// noisyBranin is Branin with measurement noise seeded by the run.
+func noisyBranin() Target {
+	branin := benchfuncs.Branin()
+
+	target := FromFunction(branin)
+	target.Name = "noisy Branin"
+	target.Objective = func(seed int64) ho.ObjectiveFuncCtx[float64] {
+		objective := benchfuncs.WithNoise(branin.Objective, 0.5, rand.New(rand.NewSource(seed)))
+
+		return func(_ context.Context, params ...float64) (float64, error) {
+			return objective(params...)
+		}
+	}
+
+	return target
+}
+
+func TestRun(t *testing.T) {
+	options := Options{Repetitions: 3, Budget: 12}
+
+	t.Run("report", func(t *testing.T) {
+		report, err := Run(context.Background(), FromFunction(benchfuncs.Branin()), matrix(), options)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "Branin", report.Target)
+		assert.Equal(t, 12, report.Budget)
+		assert.Equal(t, []int64{1, 2, 3}, report.Seeds)
+
+		if !assert.Len(t, report.Configs, 3) {
+			return
+		}
+
+		for i, c := range report.Configs {
+			assert.Equal(t, matrix()[i].Name, c.Name)
+
+			if !assert.Len(t, c.Runs, 3) || !assert.Len(t, c.Convergence, 12) {
+				continue
+			}
+
+			best := make([]float64, len(c.Runs))
+
+			for r, run := range c.Runs {
+				assert.Equal(t, report.Seeds[r], run.Seed)
+				assert.Len(t, run.Trials, 12)
+				assert.GreaterOrEqual(t, run.BestValue, 0.397887-1e-6)
+				assert.Len(t, run.BestParams, 2)
+
+				best[r] = run.BestValue
+			}
+
+			var sum float64
+
+			for _, v := range best {
+				sum += v
+			}
+
+			assert.InDelta(t, sum/3, c.Mean, 1e-9)
+			assert.InDelta(t, sum/3, c.Convergence[11], 1e-9)
+			assert.Contains(t, best, c.Median)
+			assert.GreaterOrEqual(t, c.StdDev, 0.0)
+
+			// Curves never go up.
+			for k := 1; k < len(c.Convergence); k++ {
+				assert.LessOrEqual(t, c.Convergence[k], c.Convergence[k-1])
+			}
+		}
+
+		if !assert.Len(t, report.WinRates, 3) {
+			return
+		}
+
+		for i := range report.WinRates {
+			assert.Equal(t, 0.5, report.WinRates[i][i])
+
+			for j := range report.WinRates {
+				assert.InDelta(t, 1, report.WinRates[i][j]+report.WinRates[j][i], 1e-9)
+			}
+		}
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		first, err := Run(context.Background(), noisyBranin(), matrix(), options)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		second, err := Run(context.Background(), noisyBranin(), matrix(), options)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, first.WinRates, second.WinRates)
+
+		for i := range first.Configs {
+			a, b := first.Configs[i], second.Configs[i]
+
+			assert.Equal(t, a.Convergence, b.Convergence)
+
+			for r := range a.Runs {
+				assert.Equal(t, a.Runs[r].BestValue, b.Runs[r].BestValue)
+				assert.Equal(t, a.Runs[r].BestParams, b.Runs[r].BestParams)
+			}
+		}
+
+		// Another seed gives other runs.
+		options := options
+		options.Seed = 100
+
+		third, err := Run(context.Background(), noisyBranin(), matrix(), options)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []int64{100, 101, 102}, third.Seeds)
+			assert.NotEqual(t, first.Configs[0].Convergence, third.Configs[0].Convergence)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		target := FromFunction(benchfuncs.Branin())
+
+		duplicate := matrix()
+		duplicate[1].Name = "EI"
+
+		for name, tt := range map[string]struct {
+			target  Target
+			configs []Config
+			options Options
+		}{
+			"no configuration":      {target: target, options: options},
+			"no name":               {target: target, configs: []Config{{Config: ho.DefaultConfig()}}, options: options},
+			"duplicate name":        {target: target, configs: duplicate, options: options},
+			"budget below samples":  {target: target, configs: matrix(), options: Options{Budget: 4}},
+			"negative repetitions":  {target: target, configs: matrix(), options: Options{Repetitions: -1, Budget: 12}},
+			"no objective":          {target: Target{Ranges: target.Ranges}, configs: matrix(), options: options},
+			"no ranges":             {target: Target{Objective: target.Objective}, configs: matrix(), options: options},
+			"invalid configuration": {target: target, configs: []Config{{Name: "bad", Config: ho.OptimizationConfig{InitialSamples: 1, NumCandidates: -1}}}, options: options},
+		} {
+			_, err := Run(context.Background(), tt.target, tt.configs, tt.options)
+			assert.ErrorIs(t, err, ho.ErrInvalidConfig, name)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Run(ctx, target, matrix(), options)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestBestSoFar(t *testing.T) {
+	trials := []ho.Trial[float64]{
+		{Status: ho.TrialFailed, ExecutionTime: math.MaxFloat64 / 2},
+		{Status: ho.TrialCompleted, ExecutionTime: 3},
+		{Status: ho.TrialCompleted, ExecutionTime: 5},
+		{Status: ho.TrialCompleted, ExecutionTime: 1},
+	}
+
+	curve := bestSoFar(trials, 5)
+
+	assert.True(t, math.IsNaN(curve[0]))
+	assert.Equal(t, []float64{3, 3, 1, 1}, curve[1:])
+
+	assert.Equal(t, []float64{math.MaxFloat64, 2, 2, 0.5, 0.5}, meanCurve([][]float64{curve, {math.NaN(), 1, 1, 0, 0}}, 5))
+}
@@ -0,0 +1,264 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// coverageCheckDraws is the number of random draws used to check that the
+	// constraints don't rule out the whole search space.
+	coverageCheckDraws = 10000
+
+	// rejectionDraws is the number of infeasible draws after which
+	// randomParams searches the cells outside of the exclusion zones instead.
+	rejectionDraws = 1000
+
+	// zoneSearchBudget bounds the number of cells outsideZones visits, so
+	// pathological zone layouts can't stall it.
+	zoneSearchBudget = 100000
+)
+
+// zoneSearch searches for a point outside of the exclusion zones, see
+// outsideZones.
+type zoneSearch[T constraints.Integer | constraints.Float] struct {
+	// hypers is the search space.
+	hypers []ParameterRange[T]
+
+	// rng shuffles the cells, and draws the values of dimensions no zone
+	// constrains anymore. If nil, cells are visited in order, and those
+	// values are the range Min.
+	rng *rand.Rand
+
+	// accept checks the points found, may be nil.
+	accept func([]T) bool
+
+	// point is the point being built, one dimension at a time.
+	point []T
+
+	// budget is the number of cells left to visit.
+	budget int
+}
+
+// Box is a rectangular sub-region of the search space, defined by per-dimension
+// inclusive bounds. It's used to declare exclusion zones, see
+// OptimizationConfig.ExclusionZones.
+//
+// Usage example:
+//
+//	// Buffer sizes above 512MB OOM the host, whatever the worker count.
+//	zone := Box{
+//	    Min: []float64{512 << 20, 1},
+//	    Max: []float64{1 << 30, 32},
+//	}
+//
+// Validation:
+// - Min and Max must have one value per parameter range
+// - Min must be less than or equal to Max in every dimension.
+type Box struct {
+	// Min holds the minimum (inclusive) value of each dimension.
+	Min []float64 `json:"min"`
+
+	// Max holds the maximum (inclusive) value of each dimension.
+	Max []float64 `json:"max"`
+}
+
+//////
+// Methods.
+//////
+
+// Contains returns true if the point lies inside the box, bounds included.
+//
+// Parameters:
+// - point: Coordinates of the point, one value per dimension
+//
+// Returns:
+// - bool: Whether the point is inside the box.
+func (b Box) Contains(point []float64) bool {
+	for i, v := range point {
+		if v < b.Min[i] || v > b.Max[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validate checks the box against the number of dimensions of the search
+// space.
+func (b Box) validate(dimensions int) error {
+	if len(b.Min) != dimensions || len(b.Max) != dimensions {
+		return fmt.Errorf(
+			"%w: exclusion zone must have %d dimensions, got min=%d max=%d",
+			ErrInvalidConfig, dimensions, len(b.Min), len(b.Max),
+		)
+	}
+
+	for i := range b.Min {
+		if b.Min[i] > b.Max[i] {
+			return fmt.Errorf(
+				"%w: exclusion zone min (%v) greater than max (%v) in dimension %d",
+				ErrInvalidConfig, b.Min[i], b.Max[i], i,
+			)
+		}
+	}
+
+	return nil
+}
+
+// search fills the dimensions of point from dim on so it lies outside of the
+// zones, which contain the values of the previous dimensions, and returns
+// true if it found such a point that's accepted.
+//
+// Each dimension is split by the bounds of the zones into intervals whose
+// values lie in the same zones, so a single value per interval is tried: the
+// search is exhaustive, and exact, as zones are closed boxes.
+func (s *zoneSearch[T]) search(zones []Box, dim int) bool {
+	if s.budget <= 0 {
+		return false
+	}
+
+	s.budget--
+
+	if len(zones) == 0 {
+		for i := dim; i < len(s.hypers); i++ {
+			if s.rng != nil {
+				s.point[i] = drawValue(s.rng, s.hypers[i])
+			} else {
+				s.point[i] = s.hypers[i].Min
+			}
+		}
+
+		return s.accept == nil || s.accept(s.point)
+	}
+
+	if dim == len(s.hypers) {
+		return false
+	}
+
+	values := intervalValues(s.hypers[dim], zones, dim)
+
+	if s.rng != nil {
+		s.rng.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+	}
+
+	for _, v := range values {
+		x := float64(v)
+
+		var inside []Box
+
+		for _, zone := range zones {
+			if x >= zone.Min[dim] && x <= zone.Max[dim] {
+				inside = append(inside, zone)
+			}
+		}
+
+		s.point[dim] = v
+
+		if s.search(inside, dim+1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//////
+// Helpers.
+//////
+
+// outsideZones searches for a point of the search space outside of every
+// zone, and accepted, e.g. satisfying the constraints.
+//
+// Parameters:
+// - hypers: The search space
+// - zones: The exclusion zones
+// - rng: Picks a random point if not nil, under the caller's lock.
+// Otherwise, the search is deterministic, and draws nothing
+// - accept: Checks the points found, may be nil
+//
+// Returns:
+// - []T: The point, nil if none was found
+// - bool: Whether the search was exhaustive: then, with a nil accept, no
+// point is found only if the zones cover the whole space.
+func outsideZones[T constraints.Integer | constraints.Float](
+	hypers []ParameterRange[T],
+	zones []Box,
+	rng *rand.Rand,
+	accept func([]T) bool,
+) ([]T, bool) {
+	s := &zoneSearch[T]{
+		hypers: hypers,
+		rng:    rng,
+		accept: accept,
+		point:  make([]T, len(hypers)),
+		budget: zoneSearchBudget,
+	}
+
+	if s.search(zones, 0) {
+		return s.point, true
+	}
+
+	return nil, s.budget > 0
+}
+
+// intervalValues returns values of the range, at least one in each interval
+// the bounds of the zones split dimension dim into, see zoneSearch.search.
+// Values are those draws can take, e.g. on the lattice of integer ranges.
+func intervalValues[T constraints.Integer | constraints.Float](hyper ParameterRange[T], zones []Box, dim int) []T {
+	min, max := float64(hyper.Min), float64(hyper.Max)
+
+	bounds := []float64{min, max}
+
+	for _, zone := range zones {
+		bounds = append(bounds, zone.Min[dim], zone.Max[dim])
+	}
+
+	var values []T
+
+	if spacing := latticeOf(hyper).spacing; spacing > 0 {
+		// The lattice values around each bound, wide enough to absorb the
+		// rounding of large ranges.
+		for _, bound := range bounds {
+			k := math.Floor((bound - min) / spacing)
+
+			for _, d := range []float64{-1, 0, 1, 2} {
+				values = append(values, modelValue(hyper, (k+d)*spacing))
+			}
+		}
+	} else {
+		// The bounds, and the middle of the intervals between them.
+		slices.Sort(bounds)
+
+		for i, bound := range bounds {
+			values = append(values, rangeValue(hyper, bound))
+
+			if i > 0 {
+				values = append(values, rangeValue(hyper, bounds[i-1]+(bound-bounds[i-1])/2))
+			}
+		}
+	}
+
+	slices.Sort(values)
+
+	return slices.CompactFunc(values, func(a, b T) bool { return float64(a) == float64(b) })
+}
+
+// inExclusionZone returns true if the point lies inside any of the zones.
+func inExclusionZone(zones []Box, point []float64) bool {
+	for _, zone := range zones {
+		if zone.Contains(point) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,165 @@
+package ho
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExclusionZones(t *testing.T) {
+	config := fastConfig()
+	config.InitialSamples = 20
+	config.Iterations = 20
+
+	config.ExclusionZones = []Box{
+		{Min: []float64{1, 1}, Max: []float64{50, 10}},
+		{Min: []float64{80, 1}, Max: []float64{100, 5}},
+	}
+
+	benchmarkFunc := func(params ...int) error {
+		return nil
+	}
+
+	result := Optimize(
+		config,
+		benchmarkFunc,
+		ParameterRange[int]{Min: 1, Max: 100},
+		ParameterRange[int]{Min: 1, Max: 10},
+	)
+
+	assert.Equal(t, TerminationCompleted, result.TerminationReason)
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+	// Zero evaluated points fall inside the declared zones.
+	for _, trial := range result.Trials {
+		assert.False(t, inExclusionZone(config.ExclusionZones, paramsToFloat64s(trial.Params)), trial.Params)
+	}
+}
+
+func TestExclusionZonesSmallFreeRegion(t *testing.T) {
+	t.Run("integer", func(t *testing.T) {
+		config := fastConfig()
+		config.ExclusionZones = []Box{
+			{Min: []float64{1}, Max: []float64{49}},
+			{Min: []float64{51}, Max: []float64{100}},
+		}
+
+		result := Optimize(config, func(params ...int) error {
+			return nil
+		}, ParameterRange[int]{Min: 1, Max: 100})
+
+		assert.Equal(t, TerminationCompleted, result.TerminationReason)
+
+		for _, trial := range result.Trials {
+			assert.Equal(t, []int{50}, trial.Params)
+		}
+	})
+
+	t.Run("float", func(t *testing.T) {
+		config := fastConfig()
+
+		// Only a corner of about 1e-10 of the space is free.
+		config.ExclusionZones = []Box{
+			{Min: []float64{0, 0}, Max: []float64{99.999, 100}},
+			{Min: []float64{99.999, 0}, Max: []float64{100, 99.999}},
+		}
+
+		result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+			return params[0] + params[1], nil
+		}, ParameterRange[float64]{Min: 0, Max: 100}, ParameterRange[float64]{Min: 0, Max: 100})
+
+		assert.Equal(t, TerminationCompleted, result.TerminationReason)
+		assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+		for _, trial := range result.Trials {
+			assert.False(t, inExclusionZone(config.ExclusionZones, trial.Params), trial.Params)
+		}
+	})
+}
+
+func TestOutsideZones(t *testing.T) {
+	hypers := []ParameterRange[float64]{{Min: 0, Max: 100}}
+
+	// Closed zones meeting at 50 cover the range, a gap doesn't.
+	point, decided := outsideZones(hypers, []Box{{Min: []float64{0}, Max: []float64{50}}, {Min: []float64{50}, Max: []float64{100}}}, nil, nil)
+	assert.Nil(t, point)
+	assert.True(t, decided)
+
+	point, decided = outsideZones(hypers, []Box{{Min: []float64{0}, Max: []float64{49.9}}, {Min: []float64{50}, Max: []float64{100}}}, nil, nil)
+	assert.True(t, decided)
+
+	if assert.Len(t, point, 1) {
+		assert.Greater(t, point[0], 49.9)
+		assert.Less(t, point[0], 50.0)
+	}
+
+	// Stepped ranges only take values on their lattice.
+	stepped := []ParameterRange[float64]{{Min: 0, Max: 100, Step: 10}}
+
+	point, _ = outsideZones(stepped, []Box{{Min: []float64{0}, Max: []float64{39}}, {Min: []float64{41}, Max: []float64{100}}}, nil, nil)
+	assert.Equal(t, []float64{40}, point)
+
+	point, _ = outsideZones(stepped, []Box{{Min: []float64{0}, Max: []float64{35}}, {Min: []float64{36}, Max: []float64{100}}}, nil, nil)
+	assert.Nil(t, point)
+
+	// Points are checked.
+	point, decided = outsideZones(hypers, nil, nil, func([]float64) bool { return false })
+	assert.Nil(t, point)
+	assert.True(t, decided)
+}
+
+func TestExclusionZonesValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		zones []Box
+	}{
+		{
+			name:  "whole space",
+			zones: []Box{{Min: []float64{0}, Max: []float64{100}}},
+		},
+		{
+			name: "whole space with multiple zones",
+			zones: []Box{
+				{Min: []float64{1}, Max: []float64{50}},
+				{Min: []float64{51}, Max: []float64{100}},
+			},
+		},
+		{
+			name: "whole space with adjacent zones",
+			zones: []Box{
+				{Min: []float64{0}, Max: []float64{49.5}},
+				{Min: []float64{49.5}, Max: []float64{100}},
+			},
+		},
+		{
+			name:  "dimensions mismatch",
+			zones: []Box{{Min: []float64{1, 1}, Max: []float64{50, 50}}},
+		},
+		{
+			name:  "min greater than max",
+			zones: []Box{{Min: []float64{50}, Max: []float64{1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := fastConfig()
+			config.ExclusionZones = tt.zones
+
+			var calls int
+
+			benchmarkFunc := func(params ...int) error {
+				calls++
+
+				return nil
+			}
+
+			result := Optimize(config, benchmarkFunc, ParameterRange[int]{Min: 1, Max: 100})
+
+			assert.Equal(t, TerminationInvalidConfig, result.TerminationReason)
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+			assert.Zero(t, calls)
+			assert.Empty(t, result.Trials)
+		})
+	}
+}
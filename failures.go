@@ -0,0 +1,91 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// failureMargin is the margin above the worst observed value failed trials
+// are assumed to yield, relative to the spread of the observed values, see
+// FailureSubstitute.
+const failureMargin = 0.1
+
+// FailurePolicy determines how failed trials are modeled, see
+// OptimizationConfig.FailedTrials.
+type FailurePolicy string
+
+const (
+	// FailureSubstitute keeps failed trials out of the model. Candidates are
+	// scored as if they yielded the worst observed value plus a margin, so
+	// the acquisition is steered away from them without their penalty
+	// flattening every real observation.
+	FailureSubstitute FailurePolicy = "Substitute"
+
+	// FailurePenalize feeds failed trials to the model with their penalty.
+	FailurePenalize FailurePolicy = "Penalize"
+)
+
+//////
+// Methods.
+//////
+
+// validate checks the policy.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if the policy is unknown, nil
+// otherwise.
+func (p FailurePolicy) validate() error {
+	switch p {
+	case "", FailureSubstitute, FailurePenalize:
+		return nil
+	default:
+		return fmt.Errorf("%w: FailedTrials: unknown policy %q", ErrInvalidConfig, p)
+	}
+}
+
+// substitutesFailures returns true if failed trials are kept out of the
+// model, see FailureSubstitute.
+func (o *optimizer[T]) substitutesFailures() bool {
+	return o.config.FailedTrials != FailurePenalize
+}
+
+// withFailures returns a copy of the model with the failed trials, at the
+// worst observed value plus a margin, to score candidates with. The model
+// itself is returned if there's no failure, or no observation to substitute
+// a value from.
+//
+// Parameters:
+// - model: The model, left untouched
+//
+// Returns:
+// - SurrogateModel: The model to score candidates with.
+func (o *optimizer[T]) withFailures(model SurrogateModel) SurrogateModel {
+	o.mu.Lock()
+
+	failures := o.failures
+
+	best, worst := math.Inf(1), math.Inf(-1)
+
+	for _, observation := range o.observations {
+		best = math.Min(best, observation.Value)
+		worst = math.Max(worst, observation.Value)
+	}
+
+	o.mu.Unlock()
+
+	if len(failures) == 0 || math.IsInf(worst, -1) {
+		return model
+	}
+
+	margin := failureMargin * (worst - best)
+
+	if margin == 0 {
+		margin = failureMargin * math.Max(math.Abs(worst), 1)
+	}
+
+	return withLies(model, failures, worst+margin)
+}
@@ -0,0 +1,141 @@
+package ho
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingParabola is a parabola over [0, 10], its minimum at 3, failing
+// beyond 6.
+func failingParabola(params ...float64) (float64, error) {
+	if params[0] > 6 {
+		return 0, errors.New("out of memory")
+	}
+
+	return math.Pow(params[0]-3, 2) + 1, nil
+}
+
+func TestFailedTrials(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	newConfig := func() OptimizationConfig {
+		config := fastConfig()
+		config.Seed = 7
+		config.InitialSamples = 10
+
+		return config
+	}
+
+	failed := func(result *Result[float64]) int {
+		var n int
+
+		for _, trial := range result.Trials {
+			if trial.Status == TrialFailed {
+				assert.Equal(t, math.MaxFloat64/2, trial.ExecutionTime)
+
+				n++
+			}
+		}
+
+		return n
+	}
+
+	t.Run("substitute", func(t *testing.T) {
+		progress := make(chan ProgressUpdate, 20)
+
+		config := newConfig()
+		config.ProgressChan = progress
+		config.Checkpoint = &Checkpoint{Path: filepath.Join(t.TempDir(), "run.json")}
+
+		result := OptimizeObjective(config, failingParabola, ranges...)
+
+		close(progress)
+
+		failures := failed(result)
+
+		assert.GreaterOrEqual(t, failures, 3)
+		assert.Equal(t, len(result.Trials)-failures, result.model.Len())
+
+		// Predictions still tell fast points from slow ones.
+		fast, _, err := result.Predict([]float64{3})
+		assert.NoError(t, err)
+
+		slow, _, err := result.Predict([]float64{5.5})
+		assert.NoError(t, err)
+
+		assert.InDelta(t, 1, fast, 1)
+		assert.Greater(t, slow-fast, 2.0)
+
+		var last ProgressUpdate
+
+		for update := range progress {
+			if update.Phase != PhaseDone {
+				last = update
+			}
+		}
+
+		assert.Equal(t, failures, last.FailedTrials)
+
+		// Failures are checkpointed apart from the observations.
+		state := readCheckpointFile(t, config.Checkpoint.Path)
+
+		if assert.NotNil(t, state) {
+			assert.Len(t, state.Failures, failures)
+			assert.Len(t, state.Observations, result.model.Len())
+		}
+	})
+
+	t.Run("penalize", func(t *testing.T) {
+		config := newConfig()
+		config.FailedTrials = FailurePenalize
+
+		result := OptimizeObjective(config, failingParabola, ranges...)
+
+		assert.Equal(t, len(result.Trials), result.model.Len())
+
+		// Penalties overwhelm the real observations.
+		fast, _, _ := result.Predict([]float64{3})
+
+		assert.False(t, math.Abs(fast-1) < 1)
+	})
+
+	t.Run("substitute value", func(t *testing.T) {
+		o := newOptimizer(nil, newConfig(), nil, ranges...)
+
+		model := newGaussianProcess()
+
+		// Without observations, there's nothing to substitute from.
+		o.failures = [][]float64{{9}}
+
+		assert.Same(t, model, o.withFailures(model))
+
+		for x, y := range []float64{5, 2, 1, 2, 5} {
+			o.observations = append(o.observations, Observation{Params: []float64{float64(x)}, Value: y})
+
+			assert.NoError(t, model.Update([]float64{float64(x)}, y))
+		}
+
+		scored := o.withFailures(model)
+
+		assert.Equal(t, 5, model.Len())
+		assert.Equal(t, 6, scored.Len())
+
+		// The worst value plus 10% of the spread.
+		mean, _ := scored.Predict([]float64{9})
+
+		assert.InDelta(t, 5.4, mean, 0.01)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		config := newConfig()
+		config.FailedTrials = "Ignore"
+
+		result := OptimizeObjective(config, failingParabola, ranges...)
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+}
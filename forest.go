@@ -0,0 +1,270 @@
+package ho
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// forestTrees is the default number of trees of a forest.
+	forestTrees = 32
+
+	// forestMinLeaf is the minimum number of samples of a leaf.
+	forestMinLeaf = 2
+
+	// forestMaxDepth bounds the depth of trees.
+	forestMaxDepth = 16
+)
+
+// treeNode is a node of a regression tree. Leaves have no children.
+type treeNode struct {
+	// feature is the index of the split feature.
+	feature int
+
+	// threshold splits samples: lower or equal ones go left.
+	threshold float64
+
+	// left and right are the indices of the children, 0 for leaves (the root
+	// is never a child).
+	left, right int
+
+	// value is the mean of the samples of a leaf.
+	value float64
+}
+
+// regressionTree is a CART regression tree, nodes being stored flat, the root
+// first.
+type regressionTree struct {
+	nodes []treeNode
+}
+
+// regressionForest is a random forest regressor: bootstrap-sampled
+// regression trees, each split considering a random subset of the features.
+type regressionForest struct {
+	trees []regressionTree
+}
+
+// treeBuilder holds the state of a tree being fitted.
+type treeBuilder struct {
+	x    [][]float64
+	y    []float64
+	rng  *rand.Rand
+	tree *regressionTree
+
+	// features is the number of features considered per split.
+	features int
+}
+
+//////
+// Methods.
+//////
+
+// predict returns the mean of the tree predictions.
+func (f *regressionForest) predict(x []float64) float64 {
+	mean, _ := f.predictWithVariance(x)
+
+	return mean
+}
+
+// predictWithVariance returns the mean and the variance of the tree
+// predictions.
+func (f *regressionForest) predictWithVariance(x []float64) (mean, variance float64) {
+	var sum, squares float64
+
+	for i := range f.trees {
+		v := f.trees[i].predict(x)
+
+		sum += v
+		squares += v * v
+	}
+
+	n := float64(len(f.trees))
+
+	mean = sum / n
+
+	return mean, math.Max(squares/n-mean*mean, 0)
+}
+
+// predict returns the value of the leaf x falls in.
+func (t *regressionTree) predict(x []float64) float64 {
+	node := &t.nodes[0]
+
+	for node.left != 0 {
+		if x[node.feature] <= node.threshold {
+			node = &t.nodes[node.left]
+		} else {
+			node = &t.nodes[node.right]
+		}
+	}
+
+	return node.value
+}
+
+// build fits the node for the given samples, and returns its index.
+func (b *treeBuilder) build(samples []int, depth int) int {
+	index := len(b.tree.nodes)
+
+	b.tree.nodes = append(b.tree.nodes, treeNode{value: subsetMean(b.y, samples)})
+
+	if len(samples) < 2*forestMinLeaf || depth >= forestMaxDepth {
+		return index
+	}
+
+	feature, threshold, ok := b.split(samples)
+	if !ok {
+		return index
+	}
+
+	var left, right []int
+
+	for _, s := range samples {
+		if b.x[s][feature] <= threshold {
+			left = append(left, s)
+		} else {
+			right = append(right, s)
+		}
+	}
+
+	l := b.build(left, depth+1)
+	r := b.build(right, depth+1)
+
+	b.tree.nodes[index].feature = feature
+	b.tree.nodes[index].threshold = threshold
+	b.tree.nodes[index].left = l
+	b.tree.nodes[index].right = r
+
+	return index
+}
+
+// split finds the split of a random subset of the features minimizing the
+// sum of squared errors of the children.
+//
+// Returns:
+// - int: The split feature
+// - float64: The split threshold
+// - bool: False if no split improves on the parent.
+func (b *treeBuilder) split(samples []int) (int, float64, bool) {
+	var (
+		bestFeature   int
+		bestThreshold float64
+		found         bool
+	)
+
+	// Splits must strictly improve on the parent.
+	bestSSE := subsetSSE(b.y, samples) * (1 - 1e-12)
+
+	sorted := append([]int(nil), samples...)
+
+	for _, feature := range b.rng.Perm(len(b.x[0]))[:b.features] {
+		sort.Slice(sorted, func(i, j int) bool { return b.x[sorted[i]][feature] < b.x[sorted[j]][feature] })
+
+		// Running sums of the left side, the right side being the rest.
+		var leftSum, leftSquares, totalSum, totalSquares float64
+
+		for _, s := range sorted {
+			totalSum += b.y[s]
+			totalSquares += b.y[s] * b.y[s]
+		}
+
+		for i := 0; i < len(sorted)-1; i++ {
+			v := b.y[sorted[i]]
+
+			leftSum += v
+			leftSquares += v * v
+
+			n := float64(i + 1)
+			m := float64(len(sorted)) - n
+
+			lower, upper := b.x[sorted[i]][feature], b.x[sorted[i+1]][feature]
+
+			if lower == upper || i+1 < forestMinLeaf || len(sorted)-i-1 < forestMinLeaf {
+				continue
+			}
+
+			rightSum := totalSum - leftSum
+
+			split := leftSquares - leftSum*leftSum/n + (totalSquares - leftSquares) - rightSum*rightSum/m
+
+			if split < bestSSE {
+				bestSSE = split
+				bestFeature = feature
+				bestThreshold = (lower + upper) / 2
+				found = true
+			}
+		}
+	}
+
+	return bestFeature, bestThreshold, found
+}
+
+//////
+// Helpers.
+//////
+
+// subsetMean returns the mean of the values at the given indices.
+func subsetMean(values []float64, indices []int) float64 {
+	var sum float64
+
+	for _, i := range indices {
+		sum += values[i]
+	}
+
+	return sum / float64(len(indices))
+}
+
+// subsetSSE returns the sum of squared errors around the mean of the values
+// at the given indices.
+func subsetSSE(values []float64, indices []int) float64 {
+	m := subsetMean(values, indices)
+
+	var sum float64
+
+	for _, i := range indices {
+		sum += (values[i] - m) * (values[i] - m)
+	}
+
+	return sum
+}
+
+//////
+// Factory.
+//////
+
+// fitForest fits a random forest.
+//
+// Parameters:
+// - x: Samples, all with the same number of features
+// - y: Sample values
+// - rng: Source of randomness for bootstrapping and feature subsets
+// - trees: Number of trees
+//
+// Returns:
+// - *regressionForest: The forest.
+//
+// Important notes:
+// - As in SMAC, splits consider 5/6 of the features, so a forest still
+// favors informative features when there are few of them.
+func fitForest(x [][]float64, y []float64, rng *rand.Rand, trees int) *regressionForest {
+	features := int(math.Ceil(float64(len(x[0])) * 5 / 6))
+
+	forest := &regressionForest{trees: make([]regressionTree, trees)}
+
+	for t := range forest.trees {
+		samples := make([]int, len(x))
+
+		for i := range samples {
+			samples[i] = rng.Intn(len(x))
+		}
+
+		b := &treeBuilder{x: x, y: y, rng: rng, tree: &forest.trees[t], features: features}
+
+		b.build(samples, 0)
+	}
+
+	return forest
+}
@@ -0,0 +1,294 @@
+package ho
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// progressUpdateJSON is the JSON representation of a ProgressUpdate.
+// Parameters are keyed by name, see ProgressUpdate.ParamNames.
+type progressUpdateJSON struct {
+	TrialID              int                 `json:"trialId,omitempty"`
+	Time                 string              `json:"time,omitempty"`
+	Phase                string              `json:"phase"`
+	Iteration            int                 `json:"iteration,omitempty"`
+	TotalIterations      int                 `json:"totalIterations,omitempty"`
+	Params               map[string]float64  `json:"params,omitempty"`
+	Formatted            map[string]string   `json:"formatted,omitempty"`
+	Value                *checkpointFloat    `json:"value,omitempty"`
+	DurationNS           int64               `json:"durationNs,omitempty"`
+	NewBest              bool                `json:"newBest,omitempty"`
+	BestParams           map[string]float64  `json:"bestParams,omitempty"`
+	BestFormatted        map[string]string   `json:"bestFormatted,omitempty"`
+	BestValue            *checkpointFloat    `json:"bestValue"`
+	Regret               checkpointFloat     `json:"regret,omitempty"`
+	DriftRatio           checkpointFloat     `json:"driftRatio,omitempty"`
+	FailedTrials         int                 `json:"failedTrials"`
+	CandidatesScored     int                 `json:"candidatesScored,omitempty"`
+	OverallProgress      float64             `json:"overallProgress"`
+	EvaluationsCompleted int                 `json:"evaluationsCompleted"`
+	EvaluationsPlanned   int                 `json:"evaluationsPlanned"`
+	TerminationReason    TerminationReason   `json:"terminationReason,omitempty"`
+	TerminationDetail    string              `json:"terminationDetail,omitempty"`
+	BestConfidence       *ConfidenceInterval `json:"bestConfidence,omitempty"`
+}
+
+// trialJSON is the JSON representation of a Trial: its TrialRecord, and
+// when it started.
+type trialJSON struct {
+	TrialRecord
+
+	StartedAt string `json:"startedAt,omitempty"`
+}
+
+//////
+// Methods.
+//////
+
+// String renders the update for logs, e.g. "[Optimization 3/50] trial 13:
+// lr=0.01 workers=8 value=1.25 duration=1.2s best=1.25 progress=26% NEW
+// BEST". Parameters are named, typed ones rendered as ParameterSpec.Format
+// does, and a best value of math.MaxFloat64, i.e. none yet, as "n/a".
+func (u ProgressUpdate) String() string {
+	best := u.bestParams()
+
+	if u.Phase == PhaseDone {
+		s := fmt.Sprintf("[%s] best=%s", u.Phase, formatValue(u.CurrentBestTime))
+
+		if ci := u.BestConfidence; ci != nil {
+			s += fmt.Sprintf(" (%.0f%% CI %.4g..%.4g, n=%d)", ci.Level*100, ci.Lower, ci.Upper, ci.Samples)
+		}
+
+		if params := u.formatParams(best, u.BestFormatted); params != "" {
+			s += " at " + params
+		}
+
+		if u.TerminationReason != "" {
+			s += fmt.Sprintf(": %s (%s)", u.TerminationReason, u.TerminationDetail)
+		}
+
+		return s
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "[%s %d/%d]", u.Phase, u.CurrentIteration, u.TotalIterations)
+
+	if u.TrialID > 0 {
+		fmt.Fprintf(&sb, " trial %d", u.TrialID)
+	}
+
+	sb.WriteByte(':')
+
+	if params := u.formatParams(u.currentParams(), u.CurrentFormatted); params != "" {
+		sb.WriteString(" " + params)
+	}
+
+	fmt.Fprintf(&sb, " value=%s", formatValue(u.LastExecutionTime))
+
+	if u.LastDuration > 0 {
+		fmt.Fprintf(&sb, " duration=%v", u.LastDuration)
+	}
+
+	fmt.Fprintf(&sb, " best=%s", formatValue(u.CurrentBestTime))
+
+	if u.EvaluationsPlanned > 0 {
+		fmt.Fprintf(&sb, " progress=%.0f%%", u.OverallProgress*100)
+	}
+
+	if u.NewBest {
+		sb.WriteString(" NEW BEST")
+	}
+
+	return sb.String()
+}
+
+// MarshalJSON implements json.Marshaler. Field names are stable, parameters
+// keyed by name, the time is RFC 3339, and a best value of math.MaxFloat64,
+// i.e. none yet, is null.
+func (u ProgressUpdate) MarshalJSON() ([]byte, error) {
+	v := progressUpdateJSON{
+		TrialID:              u.TrialID,
+		Phase:                u.Phase,
+		Iteration:            u.CurrentIteration,
+		TotalIterations:      u.TotalIterations,
+		Formatted:            u.CurrentFormatted,
+		DurationNS:           u.LastDuration.Nanoseconds(),
+		NewBest:              u.NewBest,
+		BestParams:           u.namedParams(u.bestParams()),
+		BestFormatted:        u.BestFormatted,
+		Regret:               checkpointFloat(u.InstantaneousRegret),
+		DriftRatio:           checkpointFloat(u.DriftRatio),
+		FailedTrials:         u.FailedTrials,
+		CandidatesScored:     u.CandidatesScored,
+		OverallProgress:      u.OverallProgress,
+		EvaluationsCompleted: u.EvaluationsCompleted,
+		EvaluationsPlanned:   u.EvaluationsPlanned,
+		TerminationReason:    u.TerminationReason,
+		TerminationDetail:    u.TerminationDetail,
+		BestConfidence:       u.BestConfidence,
+	}
+
+	if !u.Time.IsZero() {
+		v.Time = u.Time.Format(time.RFC3339Nano)
+	}
+
+	// The final update isn't about a trial.
+	if u.Phase != PhaseDone {
+		value := checkpointFloat(u.LastExecutionTime)
+
+		v.Params, v.Value = u.namedParams(u.currentParams()), &value
+	}
+
+	if u.CurrentBestTime != math.MaxFloat64 {
+		best := checkpointFloat(u.CurrentBestTime)
+
+		v.BestValue = &best
+	}
+
+	return json.Marshal(v)
+}
+
+// formatParams renders parameters as name=value pairs, typed ones as
+// rendered in formatted.
+func (u ProgressUpdate) formatParams(values []float64, formatted map[string]string) string {
+	pairs := make([]string, len(values))
+
+	for i, v := range values {
+		name := paramName(u.ParamNames, i)
+
+		s, ok := formatted[name]
+		if !ok {
+			s = strconv.FormatFloat(v, 'g', -1, 64)
+		}
+
+		pairs[i] = name + "=" + s
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// currentParams returns the parameters being tested, the rounded ones if the
+// update doesn't hold the others, e.g. if built by hand.
+func (u ProgressUpdate) currentParams() []float64 {
+	if u.CurrentValues != nil {
+		return u.CurrentValues
+	}
+
+	return intsToFloats(u.CurrentParams)
+}
+
+// bestParams returns the best parameters, as currentParams does, nil if
+// there's none.
+func (u ProgressUpdate) bestParams() []float64 {
+	switch {
+	case u.CurrentBestTime == math.MaxFloat64:
+		return nil
+	case u.BestValues != nil:
+		return u.BestValues
+	default:
+		return intsToFloats(u.CurrentBestParams)
+	}
+}
+
+// namedParams keys parameters by name, nil if there's none.
+func (u ProgressUpdate) namedParams(values []float64) map[string]float64 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	params := make(map[string]float64, len(values))
+
+	for i, v := range values {
+		params[paramName(u.ParamNames, i)] = v
+	}
+
+	return params
+}
+
+// String renders the trial for logs, e.g. "trial 13 [Optimization 3]
+// Completed: param0=0.01 param1=8 value=1.25 duration=1.2s". The value is
+// only rendered for completed trials, the error for others. Trials don't
+// know the names of their parameters, see NamedString.
+func (t Trial[T]) String() string {
+	return t.NamedString(nil)
+}
+
+// NamedString renders the trial as String does, with parameters named.
+//
+// Parameters:
+// - names: Parameter names, e.g. Result.ParamNames. Parameters without one
+// are named "param<i>"
+//
+// Returns:
+// - string: e.g. "trial 13 [Optimization 3] Completed: lr=0.01 workers=8
+// value=1.25 duration=1.2s".
+//
+// Usage example:
+//
+//	for _, trial := range result.Trials {
+//	    log.Println(trial.NamedString(result.ParamNames))
+//	}
+func (t Trial[T]) NamedString(names []string) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "trial %d [%s %d] %s:", t.TrialID, t.Phase, t.Iteration, t.Status)
+
+	for i, v := range t.Params {
+		fmt.Fprintf(&sb, " %s=%s", paramName(names, i), strconv.FormatFloat(float64(v), 'g', -1, 64))
+	}
+
+	if t.Status == TrialCompleted {
+		fmt.Fprintf(&sb, " value=%s", formatValue(t.ExecutionTime))
+	}
+
+	fmt.Fprintf(&sb, " duration=%v", t.Duration)
+
+	if t.Retry {
+		sb.WriteString(" retry")
+	}
+
+	if t.Cached {
+		sb.WriteString(" cached")
+	}
+
+	if t.Err != nil {
+		fmt.Fprintf(&sb, " error=%q", t.Err.Error())
+	}
+
+	return sb.String()
+}
+
+// MarshalJSON implements json.Marshaler. The trial is rendered as its
+// TrialRecord, see NewTrialRecord, with an RFC 3339 "startedAt" time.
+func (t Trial[T]) MarshalJSON() ([]byte, error) {
+	v := trialJSON{TrialRecord: NewTrialRecord(t, nil)}
+
+	if !t.StartedAt.IsZero() {
+		v.StartedAt = t.StartedAt.Format(time.RFC3339Nano)
+	}
+
+	return json.Marshal(v)
+}
+
+//////
+// Helpers.
+//////
+
+// formatValue renders a value in the shortest representation,
+// math.MaxFloat64, i.e. no value, as "n/a".
+func formatValue(v float64) string {
+	if v == math.MaxFloat64 {
+		return "n/a"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,198 @@
+package ho
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// update rewrites the golden files, e.g. go test -run TestFormat -update.
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// assertGolden compares got to the golden file testdata/format/name.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "format", name)
+
+	if *update {
+		assert.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, string(want), got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	at := time.Date(2026, 3, 14, 15, 9, 26, 535000000, time.UTC)
+
+	updates := []ProgressUpdate{
+		{
+			TrialID:              1,
+			Time:                 at,
+			Phase:                PhaseInitialSampling,
+			CurrentIteration:     1,
+			TotalIterations:      3,
+			CurrentParams:        []int{0, 8},
+			CurrentBestParams:    []int{0, 0},
+			ParamNames:           []string{"lr", "codec"},
+			CurrentValues:        []float64{0.01, 1},
+			CurrentFormatted:     map[string]string{"codec": "lz4"},
+			CurrentBestTime:      math.MaxFloat64,
+			LastExecutionTime:    math.MaxFloat64 / 2,
+			LastDuration:         1500 * time.Millisecond,
+			FailedTrials:         1,
+			OverallProgress:      0.125,
+			EvaluationsCompleted: 1,
+			EvaluationsPlanned:   8,
+		},
+		{
+			TrialID:              4,
+			Time:                 at.Add(time.Minute),
+			Phase:                PhaseOptimization,
+			CurrentIteration:     1,
+			TotalIterations:      5,
+			CurrentParams:        []int{0, 0},
+			CurrentBestParams:    []int{0, 0},
+			ParamNames:           []string{"lr", "codec"},
+			CurrentValues:        []float64{0.003, 0},
+			BestValues:           []float64{0.003, 0},
+			CurrentFormatted:     map[string]string{"codec": "zstd"},
+			BestFormatted:        map[string]string{"codec": "zstd"},
+			CurrentBestTime:      0.25,
+			LastExecutionTime:    0.25,
+			LastDuration:         820 * time.Microsecond,
+			NewBest:              true,
+			FailedTrials:         1,
+			OverallProgress:      0.5,
+			EvaluationsCompleted: 4,
+			EvaluationsPlanned:   8,
+		},
+		{
+			Time:                 at.Add(2 * time.Minute),
+			Phase:                PhaseDone,
+			CurrentBestParams:    []int{0, 0},
+			ParamNames:           []string{"lr", "codec"},
+			BestValues:           []float64{0.003, 0},
+			BestFormatted:        map[string]string{"codec": "zstd"},
+			CurrentBestTime:      0.25,
+			OverallProgress:      1,
+			EvaluationsCompleted: 8,
+			EvaluationsPlanned:   8,
+			TerminationReason:    TerminationCompleted,
+			TerminationDetail:    "3 initial samples and 5 iterations evaluated",
+		},
+		// Built by hand, without names nor unrounded values.
+		{
+			Phase:             PhaseOptimization,
+			CurrentIteration:  2,
+			TotalIterations:   5,
+			CurrentParams:     []int{3, 7},
+			CurrentBestTime:   math.MaxFloat64,
+			LastExecutionTime: 12,
+		},
+	}
+
+	trials := []Trial[float64]{
+		{
+			TrialInfo:     TrialInfo{TrialID: 4, Phase: PhaseOptimization, Iteration: 1, Seed: 42, RNGPosition: 7},
+			Params:        []float64{0.003, 0},
+			ExecutionTime: 0.25,
+			Duration:      820 * time.Microsecond,
+			StartedAt:     at,
+			Status:        TrialCompleted,
+		},
+		{
+			TrialInfo:     TrialInfo{TrialID: 5, Phase: PhaseOptimization, Iteration: 2, Retry: true, Seed: 43},
+			Params:        []float64{0.5, 2},
+			ExecutionTime: math.MaxFloat64 / 2,
+			Duration:      2 * time.Second,
+			StartedAt:     at.Add(time.Second),
+			Status:        TrialFailed,
+			Err:           errors.New(`connection "db" refused`),
+		},
+	}
+
+	var text, lines []string
+
+	for _, u := range updates {
+		data, err := json.Marshal(u)
+
+		assert.NoError(t, err)
+
+		text = append(text, u.String())
+		lines = append(lines, string(data))
+	}
+
+	assertGolden(t, "progress.txt", strings.Join(text, "\n")+"\n")
+	assertGolden(t, "progress.jsonl", strings.Join(lines, "\n")+"\n")
+
+	text, lines = nil, nil
+
+	for _, trial := range trials {
+		data, err := json.Marshal(trial)
+
+		assert.NoError(t, err)
+
+		text = append(text, trial.String())
+		lines = append(lines, string(data))
+	}
+
+	assertGolden(t, "trial.txt", strings.Join(text, "\n")+"\n")
+	assertGolden(t, "trial.jsonl", strings.Join(lines, "\n")+"\n")
+
+	// Names, e.g. Result.ParamNames, replace the default ones.
+	assert.Equal(t, "trial 4 [Optimization 1] Completed: lr=0.003 codec=0 value=0.25 duration=820µs", trials[0].NamedString([]string{"lr", "codec"}))
+	assert.Equal(t, "trial 4 [Optimization 1] Completed: lr=0.003 param1=0 value=0.25 duration=820µs", trials[0].NamedString([]string{"lr"}))
+
+	// Pointers marshal the same.
+	data, err := json.Marshal([]*ProgressUpdate{&updates[2]})
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"bestValue":0.25`)
+}
+
+func TestProgressUpdateFields(t *testing.T) {
+	progressChan := make(chan ProgressUpdate, 20)
+
+	config := fastConfig()
+	config.ProgressChan = progressChan
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return math.Pow(params[0]-0.3, 2), nil
+	}, ParameterRange[float64]{Name: "lr", Min: 0, Max: 1})
+
+	close(progressChan)
+
+	best := math.MaxFloat64
+
+	for update := range progressChan {
+		assert.False(t, update.Time.IsZero())
+		assert.Equal(t, []string{"lr"}, update.ParamNames)
+
+		if update.Phase == PhaseDone {
+			assert.Equal(t, result.BestParams, update.BestValues)
+
+			continue
+		}
+
+		// Values aren't rounded.
+		trial := result.Trials[update.TrialID-1]
+
+		assert.Equal(t, trial.Params, update.CurrentValues)
+		assert.Equal(t, trial.Duration, update.LastDuration)
+		assert.Equal(t, update.LastExecutionTime < best, update.NewBest)
+
+		best = math.Min(best, update.LastExecutionTime)
+	}
+}
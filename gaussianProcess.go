@@ -1,6 +1,7 @@
 package ho
 
 import (
+	"fmt"
 	"math"
 	"sync"
 )
@@ -9,6 +10,34 @@ import (
 // Const, vars, types.
 //////
 
+// refactorEvery is the number of rows appended to the Cholesky factor after
+// which it's recomputed from scratch, bounding the rounding errors
+// accumulated by incremental updates.
+const refactorEvery = 256
+
+// jitters are the variances added to the diagonal of the kernel matrix,
+// relative to the unit variance of the kernel, tried in order until it
+// factorizes: near-duplicate observations make it ill-conditioned. The last
+// one suffices for finite points and a positive finite sigma, as their
+// kernel matrix is positive semidefinite, with rounding errors far below
+// it. Less than 1e-6 would let nearly identical points with different
+// values bend the mean far outside the observed values.
+var jitters = [...]float64{1e-6, 1e-4}
+
+// GaussianProcessOptions configures a Gaussian process, see
+// NewGaussianProcess.
+type GaussianProcessOptions struct {
+	// AppendDuplicates keeps repeated observations of a point as separate
+	// observations, as an exact Gaussian process does, relying on the jitter
+	// on the diagonal of the kernel matrix to keep it positive definite. If
+	// false, repeated observations are merged into their running mean.
+	AppendDuplicates bool
+
+	// MergeTolerance is the distance, along each coordinate, within which
+	// points are merged as the same point. If 0, only identical points are.
+	MergeTolerance float64
+}
+
 // gaussianProcess implements a thread-safe Gaussian Process model for regression
 // with multidimensional inputs. It is used to predict the performance of untested
 // hyperparameter combinations based on previously observed results.
@@ -18,17 +47,31 @@ import (
 // - X: Slice of observed input points (each point is a slice of float64)
 // - Y: Slice of observed values (execution times) at each input point
 // - sigma: Kernel width parameter controlling the smoothness of interpolation
+// - weights: Total weight of the observations merged into each point, see
+// GaussianProcessOptions and WeightedUpdater
+// - prior, scale: Prior mean and variance, the mean and variance of Y
+// - chol, whitened, jitter: Cholesky factor of the kernel matrix,
+// L^-1 * (Y - prior), and the jitter on its diagonal, maintained by Update
+// for the posterior
 //
 // Thread safety:
 // - All fields are protected by the RWMutex
 // - Safe for concurrent access from multiple goroutines
-// - Uses RLock for read operations (Predict, RBFKernel)
+// - Uses RLock for read operations (Predict, PredictBatch)
 // - Uses Lock for write operations (Update, SetSigma)
+// - Locks are taken once per call, never while held, as they aren't
+// reentrant
+//
+// Caching:
+// - The Cholesky factor and L^-1 * (Y - prior) are computed on Update and
+// SetSigma, never on predictions
+// - Predictions evaluate the kernel n times per point, and solve against the
+// factor, in O(n^2) per point as the posterior variance requires
 //
 // Memory usage:
-// - Grows linearly with number of observations
+// - Grows quadratically with number of observations, for the Cholesky factor
 // - Each observation stores a copy of input parameters
-// - O(n) memory where n is number of observations.
+// - O(n^2) memory where n is number of observations.
 type gaussianProcess struct {
 	// mu protects access to all fields
 	mu sync.RWMutex
@@ -40,73 +83,77 @@ type gaussianProcess struct {
 
 	// Y stores the observed values (execution times) at each point in X
 	// Must have same length as X
+	// Repeated observations of a point are merged into their mean
 	Y []float64
 
+	// weights stores the total weight of the observations merged into each
+	// point of X, the effective sample size of its value in Y, see
+	// WeightedUpdater
+	weights []float64
+
+	// observations is the number of observations, merged ones included
+	observations int
+
+	// noise holds the variance of the observations at each point of X,
+	// relative to the prior variance, if modeled, see
+	// HeteroscedasticGaussianProcess. Points beyond it have none, but the
+	// jitter.
+	noise []float64
+
+	// options configures how repeated observations are handled
+	options GaussianProcessOptions
+
 	// sigma is the kernel width parameter
 	// Larger values = smoother interpolation
 	// Smaller values = more local influence
 	sigma float64
-}
 
-//////
-// Methods.
-//////
+	// groups partitions the dimensions for an additive kernel, see
+	// AdditiveGaussianProcess. If nil, the kernel is over all dimensions.
+	groups [][]int
 
-// RBFKernel implements the Radial Basis Function (also known as Gaussian) kernel.
-// This kernel measures the similarity between two points in the input space,
-// with the similarity decreasing exponentially with distance.
-//
-// Parameters:
-// - x1, x2: Input vectors to compare (must have same length)
-//
-// Returns:
-// - float64: Kernel value (similarity) between the points (0.0 to 1.0)
-//
-// Usage example:
-//
-//	gp := newGaussianProcess()
-//	similarity := gp.RBFKernel(
-//	    []float64{1.0, 2.0},
-//	    []float64{1.1, 2.1},
-//	)
-//
-// Mathematical formula:
-//
-//	k(x1, x2) = exp(-sum((x1 - x2)^2) / (2 * sigma^2))
-//
-// Important notes:
-// - Panics if input vectors have different lengths
-// - Returns 1.0 for identical points
-// - Returns values close to 0.0 for distant points
-// - Uses read lock to access sigma
-//
-// Thread safety:
-// - Protected by read mutex for sigma access
-// - Safe for concurrent access
-// - Multiple kernel calculations can proceed in parallel.
-func (gp *gaussianProcess) RBFKernel(x1, x2 []float64) float64 {
-	if len(x1) != len(x2) {
-		panic("input vectors must have the same length")
-	}
+	// chol holds the lower triangular Cholesky factor L of the kernel
+	// matrix K + noise, never modified once computed, the noise on the
+	// diagonal shrinking with the weight of each point, see diagonal
+	chol cholesky
 
-	// Get sigma value thread-safely
-	gp.mu.RLock()
-	sigma := gp.sigma
-	gp.mu.RUnlock()
+	// prior is the prior mean, the mean of Y, so predictions away from
+	// observations revert to it instead of 0
+	prior float64
 
-	// Calculate squared Euclidean distance
-	var sum float64
+	// scale is the prior variance, the variance of Y, or 1 if Y doesn't
+	// vary, so variances are in the units of Y
+	scale float64
 
-	for i := range x1 {
-		diff := x1[i] - x2[i]
+	// whitened holds L^-1 * (Y - prior), so the posterior mean is a dot
+	// product
+	whitened []float64
 
-		sum += diff * diff
-	}
+	// jitter is the variance added to the diagonal of the factored kernel
+	// matrix, the first of jitters unless more was needed for it to be
+	// positive definite
+	jitter float64
 
-	// Apply RBF kernel formula
-	return math.Exp(-sum / (2 * sigma * sigma))
+	// appended is the number of rows appended since the last full
+	// factorization
+	appended int
 }
 
+// factorization is the state factorize derives from the observations,
+// saved to roll back to when a refit fails.
+type factorization struct {
+	chol     cholesky
+	prior    float64
+	scale    float64
+	whitened []float64
+	jitter   float64
+	appended int
+}
+
+//////
+// Methods.
+//////
+
 // Predict estimates the expected execution time and uncertainty at a given point
 // based on previously observed data points.
 //
@@ -126,14 +173,24 @@ func (gp *gaussianProcess) RBFKernel(x1, x2 []float64) float64 {
 //
 // Mathematical details:
 // - Uses RBF kernel to measure similarity to known points
-// - Mean is weighted average of observed values
-// - Variance indicates prediction uncertainty
+// - Mean and variance are those of the Gaussian process posterior, with the
+// mean m and the variance s of the observed values as the prior mean and
+// variance: mean = m + k^T (K + noise*I)^-1 (Y - m), and
+// variance = s * (1 - k^T (K + noise*I)^-1 k), k being the kernel values
+// between x and the observed points, and K the kernel matrix of the observed
+// points
+// - Both are computed from v = L^-1 k, L being the Cholesky factor
+// maintained by Update: mean = m + v^T L^-1 (Y - m), and
+// variance = s * (1 - v^T v)
+// - Far from the observed points, mean reverts to m, and variance to s
 // - Returns (0, 1) if no observations exist
 //
 // Important notes:
 // - Thread-safe (uses read lock)
+// - Returns (NaN, NaN) if x doesn't have the dimensions of the observed
+// points, see Optimizer.Predict for a checked prediction
 // - O(n) space complexity for temporary storage
-// - O(n^2) time complexity for variance calculation
+// - O(n^2) time complexity for the triangular solve
 // - n is the number of observations
 //
 // Best practices:
@@ -144,41 +201,47 @@ func (gp *gaussianProcess) RBFKernel(x1, x2 []float64) float64 {
 // Performance considerations:
 // - Computation time increases quadratically with observations
 // - Consider limiting total observations in long-running optimizations
-// - Memory usage is linear with number of observations.
+// - Temporary memory usage is linear with number of observations.
 func (gp *gaussianProcess) Predict(x []float64) (mean, variance float64) {
 	gp.mu.RLock()
 	defer gp.mu.RUnlock()
 
-	// Handle case with no observations
-	if len(gp.X) == 0 {
-		return 0, 1
-	}
-
-	// Calculate kernel values between x and all observed points
-	k := make([]float64, len(gp.X))
-	for i := range gp.X {
-		k[i] = gp.RBFKernel(x, gp.X[i])
-	}
-
-	// Calculate mean prediction
-	var sum float64
+	// Mismatched points are predicted as NaN.
+	means, variances, _ := gp.posterior([][]float64{x}, gp.prior, gp.scale, gp.whitened)
 
-	for i := range gp.X {
-		sum += k[i] * gp.Y[i]
-	}
-
-	mean = sum / float64(len(gp.X))
+	return means[0], variances[0]
+}
 
-	// Calculate variance.
-	variance = 1.0
+// PredictBatch implements BatchPredictor: it estimates the expected
+// execution time and uncertainty at many points at once, exactly as Predict
+// does point by point.
+//
+// Parameters:
+// - points: Input points at which to make predictions
+//
+// Returns:
+// - means: Expected execution times, aligned with points
+// - variances: Uncertainties, aligned with points
+//
+// Usage example:
+//
+//	means, variances := gp.PredictBatch(candidates)
+//	for i := range candidates {
+//	    score := acquisitionFunc(means[i], variances[i], params)
+//	}
+//
+// Performance considerations:
+// - Takes the read lock once, instead of once per point
+// - Forward substitutions are solved for all points at once, e.g. by a
+// single triangular solve with multiple right-hand sides.
+func (gp *gaussianProcess) PredictBatch(points [][]float64) (means, variances []float64) {
+	gp.mu.RLock()
+	defer gp.mu.RUnlock()
 
-	for i := range gp.X {
-		for j := range gp.X {
-			variance -= k[i] * k[j] / float64(len(gp.X))
-		}
-	}
+	// Mismatched points are predicted as NaN.
+	means, variances, _ = gp.posterior(points, gp.prior, gp.scale, gp.whitened)
 
-	return mean, variance
+	return means, variances
 }
 
 // Update adds a new observation point to the Gaussian Process model.
@@ -189,18 +252,31 @@ func (gp *gaussianProcess) Predict(x []float64) (mean, variance float64) {
 // - x: Slice of float64 values representing the input point (hyperparameters)
 // - y: Observed value (execution time) at point x
 //
+// Returns:
+// - error: Wrapping ErrInvalidObservation if x or y isn't finite, x
+// doesn't have the dimensions of the observed points, or the kernel matrix
+// isn't positive definite even with the largest jitter, in which case the
+// model is left untouched
+//
 // Usage example:
 //
 //	gp := newGaussianProcess()
 //
 //	// Add observation: parameters [1.0, 2.0] resulted in execution time 100.5
-//	gp.Update([]float64{1.0, 2.0}, 100.5)
+//	if err := gp.Update([]float64{1.0, 2.0}, 100.5); err != nil {
+//	    return err
+//	}
 //
 // Important notes:
 // - Creates a deep copy of input slice x to prevent external modifications
 // - Maintains thread safety using mutex
-// - Appends to internal X and Y slices
-// - Memory usage grows with each update
+// - Appends to internal X and Y slices, unless x repeats an observed point:
+// the observation is then merged into the running mean of the point, see
+// GaussianProcessOptions
+// - Extends the Cholesky factor of the kernel matrix by a row, and
+// recomputes it from scratch every refactorEvery updates, and on merges, as
+// the noise of the merged point shrinks
+// - Memory usage grows with each update, merges excepted
 //
 // Thread safety:
 // - Protected by write mutex (gp.mu)
@@ -209,14 +285,57 @@ func (gp *gaussianProcess) Predict(x []float64) (mean, variance float64) {
 // - Blocks Predict operations while running
 //
 // Performance considerations:
-// - O(1) time complexity for the update itself
-// - Memory grows linearly with number of observations
+// - O(n^2) time complexity for extending the factor, instead of O(n^3) for
+// refactorizing the kernel matrix, amortized full refactorizations
+// included. Merges refactorize, in O(n^3), n being the number of distinct
+// points
+// - Memory grows quadratically with number of observations, as the factor
+// does
 // - Creates new slice and copies data on each call
 // - Consider memory impact with large numbers of updates.
-func (gp *gaussianProcess) Update(x []float64, y float64) {
+func (gp *gaussianProcess) Update(x []float64, y float64) error {
+	return gp.UpdateWeighted(x, y, 1)
+}
+
+// UpdateWeighted implements WeightedUpdater: the observation is added as by
+// Update, with the noise on its diagonal term of the kernel matrix divided
+// by weight, see diagonal. Merged into an observed point, it moves the
+// mean of the point in proportion to its weight.
+func (gp *gaussianProcess) UpdateWeighted(x []float64, y, weight float64) error {
 	gp.mu.Lock()
 	defer gp.mu.Unlock()
 
+	return gp.update(x, y, weight)
+}
+
+// update implements UpdateWeighted. The caller must hold the write lock.
+func (gp *gaussianProcess) update(x []float64, y, weight float64) error {
+	if err := checkObservation(x, y, gp.X); err != nil {
+		return err
+	}
+
+	if err := checkWeight(weight); err != nil {
+		return err
+	}
+
+	if i := gp.duplicateOf(x); i >= 0 {
+		mergedWeight, mergedY := gp.weights[i], gp.Y[i]
+
+		gp.weights[i] += weight
+		gp.Y[i] += (y - gp.Y[i]) * weight / gp.weights[i]
+
+		// The noise of the point shrinks with its weight.
+		if err := gp.factorize(); err != nil {
+			gp.weights[i], gp.Y[i] = mergedWeight, mergedY
+
+			return err
+		}
+
+		gp.observations++
+
+		return nil
+	}
+
 	// Create deep copy of input to prevent external modifications
 	newX := make([]float64, len(x))
 	copy(newX, x)
@@ -224,6 +343,25 @@ func (gp *gaussianProcess) Update(x []float64, y float64) {
 	// Append new observation to our training data
 	gp.X = append(gp.X, newX)
 	gp.Y = append(gp.Y, y)
+	gp.weights = append(gp.weights, weight)
+
+	refit := gp.appendRow
+
+	if gp.appended >= refactorEvery {
+		refit = gp.factorize
+	}
+
+	if err := refit(); err != nil {
+		n := len(gp.X) - 1
+
+		gp.X, gp.Y, gp.weights = gp.X[:n], gp.Y[:n], gp.weights[:n]
+
+		return err
+	}
+
+	gp.observations++
+
+	return nil
 }
 
 // SetSigma updates the kernel width parameter (sigma) of the Gaussian Process.
@@ -233,21 +371,31 @@ func (gp *gaussianProcess) Update(x []float64, y float64) {
 // Parameters:
 // - sigma: New kernel width value (must be positive)
 //
+// Returns:
+// - error: Wrapping ErrInvalidObservation if the kernel matrix isn't
+// positive definite with sigma, e.g. it's NaN, in which case the model is
+// left untouched
+//
 // Usage example:
 //
 //	gp := newGaussianProcess()
 //
 //	// Set wider kernel for smoother interpolation
-//	gp.SetSigma(2.0)
+//	if err := gp.SetSigma(2.0); err != nil {
+//	    return err
+//	}
 //
 //	// Set narrower kernel for more local influence
-//	gp.SetSigma(0.5)
+//	if err := gp.SetSigma(0.5); err != nil {
+//	    return err
+//	}
 //
 // Important notes:
 // - Affects all subsequent predictions
 // - Larger values = smoother interpolation
 // - Smaller values = more local influence
 // - No validation of sigma value (caller's responsibility)
+// - Refactorizes the kernel matrix, in O(n^3)
 //
 // Thread safety:
 // - Protected by write mutex (gp.mu)
@@ -259,10 +407,21 @@ func (gp *gaussianProcess) Update(x []float64, y float64) {
 // - Choose sigma based on expected smoothness of function
 // - Consider validating sigma > 0 before calling
 // - May need tuning for different optimization problems.
-func (gp *gaussianProcess) SetSigma(sigma float64) {
+func (gp *gaussianProcess) SetSigma(sigma float64) error {
 	gp.mu.Lock()
 	defer gp.mu.Unlock()
+
+	previous := gp.sigma
+
 	gp.sigma = sigma
+
+	if err := gp.factorize(); err != nil {
+		gp.sigma = previous
+
+		return err
+	}
+
+	return nil
 }
 
 // GetSigma returns the current kernel width parameter (sigma) of the Gaussian Process.
@@ -307,6 +466,504 @@ func (gp *gaussianProcess) GetSigma() float64 {
 	return gp.sigma
 }
 
+// Points returns a copy of the observed input points.
+//
+// Returns:
+// - [][]float64: Observed input points, in observation order, repeated
+// points once unless GaussianProcessOptions.AppendDuplicates is set
+//
+// Thread safety:
+// - Protected by read mutex (gp.mu)
+// - Returns a deep copy, safe to use after the model is updated.
+func (gp *gaussianProcess) Points() [][]float64 {
+	gp.mu.RLock()
+	defer gp.mu.RUnlock()
+
+	points := make([][]float64, len(gp.X))
+
+	for i, x := range gp.X {
+		points[i] = make([]float64, len(x))
+
+		copy(points[i], x)
+	}
+
+	return points
+}
+
+// Len returns the number of observations, merged ones included.
+func (gp *gaussianProcess) Len() int {
+	gp.mu.RLock()
+	defer gp.mu.RUnlock()
+
+	return gp.observations
+}
+
+// usedJitter returns the jitter on the diagonal of the factored kernel
+// matrix, see jitters.
+func (gp *gaussianProcess) usedJitter() float64 {
+	gp.mu.RLock()
+	defer gp.mu.RUnlock()
+
+	return gp.jitter
+}
+
+// snapshot returns a copy of the model, taken under the read lock, so
+// predictions on it are consistent while the model keeps being updated.
+//
+// Returns:
+// - *gaussianProcess: The copy, sharing the observed points and the Cholesky
+// factor, which are never modified.
+func (gp *gaussianProcess) snapshot() *gaussianProcess {
+	gp.mu.RLock()
+	defer gp.mu.RUnlock()
+
+	return &gaussianProcess{
+		X:            append([][]float64(nil), gp.X...),
+		Y:            append([]float64(nil), gp.Y...),
+		weights:      append([]float64(nil), gp.weights...),
+		observations: gp.observations,
+		noise:        append([]float64(nil), gp.noise...),
+		options:      gp.options,
+		sigma:        gp.sigma,
+		groups:       gp.groups,
+		chol:         gp.chol,
+		prior:        gp.prior,
+		scale:        gp.scale,
+		whitened:     append([]float64(nil), gp.whitened...),
+		jitter:       gp.jitter,
+		appended:     gp.appended,
+	}
+}
+
+// Clone implements SurrogateModel, see snapshot.
+func (gp *gaussianProcess) Clone() SurrogateModel {
+	return gp.snapshot()
+}
+
+// posterior returns the posterior means and variances at the points, for the
+// prior mean and variance, and the centered observations whitened by the
+// Cholesky factor, see Predict. The caller must hold the read lock.
+//
+// Returns:
+// - means, variances: Aligned with points, NaN for points without the
+// dimensions of the observed points
+// - error: Wrapping ErrInvalidObservation, describing the first such point,
+// if any.
+func (gp *gaussianProcess) posterior(points [][]float64, prior, scale float64, whitened []float64) (means, variances []float64, err error) {
+	means = make([]float64, len(points))
+	variances = make([]float64, len(points))
+
+	if len(gp.X) == 0 {
+		for i := range variances {
+			variances[i] = 1
+		}
+
+		return means, variances, nil
+	}
+
+	k := make([][]float64, len(points))
+
+	for c, x := range points {
+		k[c] = make([]float64, len(gp.X))
+
+		// Points are checked once here, not on each kernel evaluation.
+		if len(x) != len(gp.X[0]) {
+			if err == nil {
+				err = fmt.Errorf("%w: point %d has %d dimensions, expected %d", ErrInvalidObservation, c, len(x), len(gp.X[0]))
+			}
+
+			continue
+		}
+
+		for i := range gp.X {
+			k[c][i] = gp.kernel(x, gp.X[i])
+		}
+	}
+
+	for c, v := range gp.chol.solve(k) {
+		if len(points[c]) != len(gp.X[0]) {
+			means[c], variances[c] = math.NaN(), math.NaN()
+
+			continue
+		}
+
+		means[c], variances[c] = prior, 1.0
+
+		for i := range v {
+			means[c] += v[i] * whitened[i]
+			variances[c] -= v[i] * v[i]
+		}
+
+		variances[c] = min(max(variances[c], 0), 1) * scale
+	}
+
+	return means, variances, err
+}
+
+// termPosterior returns the posterior means and variances, at the points, of
+// each term of an additive kernel, see AdditiveModel, NaN for points without
+// the dimensions of the observed points, as for posterior. The caller must
+// hold the read lock.
+//
+// Important notes:
+// - The terms are independent a priori, each with the prior variance
+// divided by the number of groups, and the prior mean split evenly
+// - Means are v^T L^-1 (Y - m), v = L^-1 k_g, k_g being the kernel values
+// of the term, so they sum to the posterior mean.
+func (gp *gaussianProcess) termPosterior(points [][]float64) (means, variances [][]float64) {
+	means = make([][]float64, len(gp.groups))
+	variances = make([][]float64, len(gp.groups))
+
+	share := 1 / float64(len(gp.groups))
+
+	for g, group := range gp.groups {
+		means[g] = make([]float64, len(points))
+		variances[g] = make([]float64, len(points))
+
+		if len(gp.X) == 0 {
+			for c := range points {
+				variances[g][c] = share
+			}
+
+			continue
+		}
+
+		k := make([][]float64, len(points))
+
+		for c, x := range points {
+			k[c] = make([]float64, len(gp.X))
+
+			if len(x) != len(gp.X[0]) {
+				continue
+			}
+
+			for i := range gp.X {
+				k[c][i] = share * groupKernel(x, gp.X[i], group, gp.sigma)
+			}
+		}
+
+		for c, v := range gp.chol.solve(k) {
+			if len(points[c]) != len(gp.X[0]) {
+				means[g][c], variances[g][c] = math.NaN(), math.NaN()
+
+				continue
+			}
+
+			means[g][c], variances[g][c] = gp.prior*share, share
+
+			for i := range v {
+				means[g][c] += v[i] * gp.whitened[i]
+				variances[g][c] -= v[i] * v[i]
+			}
+
+			variances[g][c] = min(max(variances[g][c], 0), share) * gp.scale
+		}
+	}
+
+	return means, variances
+}
+
+// kernel returns the kernel value between the points: the RBF kernel, or
+// the mean of the RBF kernels over each group of dimensions for an additive
+// kernel, which is 1 for identical points either way. The caller must hold
+// the lock.
+func (gp *gaussianProcess) kernel(x1, x2 []float64) float64 {
+	if gp.groups == nil {
+		return rbfKernel(x1, x2, gp.sigma)
+	}
+
+	var sum float64
+
+	for _, group := range gp.groups {
+		sum += groupKernel(x1, x2, group, gp.sigma)
+	}
+
+	return sum / float64(len(gp.groups))
+}
+
+// logLikelihood returns the log marginal likelihood of the observations,
+// up to a constant, to compare kernels on the same observations. The caller
+// must hold the lock.
+func (gp *gaussianProcess) logLikelihood() float64 {
+	var fit, det float64
+
+	for i, w := range gp.whitened {
+		fit += w * w
+
+		det += math.Log(gp.chol.at(i, i))
+	}
+
+	n := float64(len(gp.whitened))
+
+	return -fit/(2*gp.scale) - det - n/2*math.Log(gp.scale)
+}
+
+// appendRow extends the Cholesky factor with the row of the last
+// observation, in O(n^2), or refactorizes if the extended kernel matrix
+// isn't positive definite, see factorize. The caller must hold the write
+// lock.
+func (gp *gaussianProcess) appendRow() error {
+	n := gp.chol.size()
+	x := gp.X[n]
+
+	column := make([]float64, n+1)
+
+	for j := 0; j < n; j++ {
+		column[j] = gp.kernel(x, gp.X[j])
+	}
+
+	column[n] = gp.diagonal(n)
+
+	chol, ok := gp.chol.extend(column)
+	if !ok {
+		return gp.factorize()
+	}
+
+	gp.chol = chol
+	gp.appended++
+
+	gp.whiten()
+
+	return nil
+}
+
+// factorize recomputes the Cholesky factor of the kernel matrix from
+// scratch, in O(n^3). The caller must hold the write lock.
+//
+// Returns:
+// - error: Wrapping ErrInvalidObservation if the kernel matrix isn't
+// positive definite even with the last jitter, e.g. sigma is NaN, in which
+// case the factorization is left untouched
+//
+// Important notes:
+// - The jitter is escalated through jitters until the kernel matrix is
+// positive definite, e.g. despite near-duplicate points.
+func (gp *gaussianProcess) factorize() error {
+	n := len(gp.X)
+
+	previous := gp.jitter
+
+	for _, gp.jitter = range jitters {
+		chol, ok := newCholesky(n, func(i, j int) float64 {
+			if i == j {
+				return gp.diagonal(i)
+			}
+
+			return gp.kernel(gp.X[i], gp.X[j])
+		})
+
+		if ok {
+			gp.chol = chol
+			gp.appended = 0
+
+			gp.whiten()
+
+			return nil
+		}
+	}
+
+	gp.jitter = previous
+
+	return fmt.Errorf("%w: the kernel matrix of %d points isn't positive definite, even with a jitter of %g", ErrInvalidObservation, n, jitters[len(jitters)-1])
+}
+
+// factorization returns the state derived by factorize, to restore it with
+// restoreFactorization. The caller must hold the lock.
+func (gp *gaussianProcess) factorization() factorization {
+	return factorization{
+		chol:     gp.chol,
+		prior:    gp.prior,
+		scale:    gp.scale,
+		whitened: gp.whitened,
+		jitter:   gp.jitter,
+		appended: gp.appended,
+	}
+}
+
+// restoreFactorization restores a state returned by factorization. The
+// caller must hold the write lock.
+func (gp *gaussianProcess) restoreFactorization(f factorization) {
+	gp.chol = f.chol
+	gp.prior, gp.scale = f.prior, f.scale
+	gp.whitened = f.whitened
+	gp.jitter = f.jitter
+	gp.appended = f.appended
+}
+
+// diagonal returns the i-th diagonal entry of the kernel matrix to factor:
+// the unit kernel variance, plus the jitter divided by the weight of the
+// point, as averaging observations shrinks their noise. A weight below 1
+// adds 1/weight - 1 kernel variances of noise on top, as the jitter alone
+// would still interpolate a down-weighted observation. Modeled noise, if
+// any, is divided by the weight too. The caller must hold the lock.
+func (gp *gaussianProcess) diagonal(i int) float64 {
+	weight := gp.weights[i]
+
+	d := 1 + gp.jitter/weight + math.Max(1/weight-1, 0)
+
+	if i < len(gp.noise) {
+		d += gp.noise[i] / weight
+	}
+
+	return d
+}
+
+// looResiduals returns the leave-one-out residuals of the observed points:
+// the value of each minus the posterior mean at it given the others, noise
+// included, in O(n^3). The caller must hold the lock.
+//
+// Mathematical details:
+// - With K the factored kernel matrix, noise included, the residual of
+// point i is [K^-1 (Y - m)]_i / [K^-1]_ii, the columns of L^-1 giving both.
+func (gp *gaussianProcess) looResiduals() []float64 {
+	n := len(gp.X)
+
+	identity := make([][]float64, n)
+
+	for i := range identity {
+		identity[i] = make([]float64, n)
+		identity[i][i] = 1
+	}
+
+	residuals := make([]float64, n)
+
+	for i, column := range gp.chol.solve(identity) {
+		var alpha, precision float64
+
+		for k, v := range column {
+			alpha += v * gp.whitened[k]
+			precision += v * v
+		}
+
+		residuals[i] = alpha / precision
+	}
+
+	return residuals
+}
+
+// duplicateOf returns the index of the point x is merged into, or -1 if it
+// isn't, see GaussianProcessOptions. The caller must hold the lock.
+func (gp *gaussianProcess) duplicateOf(x []float64) int {
+	if gp.options.AppendDuplicates {
+		return -1
+	}
+
+	for i, point := range gp.X {
+		if withinTolerance(x, point, gp.options.MergeTolerance) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// whiten recomputes the prior mean and variance, and the whitened centered
+// observations, in O(n^2), as every observation shifts the prior mean. The
+// caller must hold the write lock.
+func (gp *gaussianProcess) whiten() {
+	gp.prior, gp.scale = priorMean(gp.Y), priorScale(gp.Y)
+
+	centered := make([]float64, len(gp.Y))
+
+	for i, y := range gp.Y {
+		centered[i] = y - gp.prior
+	}
+
+	gp.whitened = gp.chol.solve([][]float64{centered})[0]
+}
+
+//////
+// Helpers.
+//////
+
+// priorMean returns the mean of the values, without overflowing on huge
+// ones, such as failure penalties.
+func priorMean(values []float64) float64 {
+	var m float64
+
+	for _, v := range values {
+		m += v / float64(len(values))
+	}
+
+	return m
+}
+
+// priorScale returns the population variance of the values, or 1 if they
+// don't vary, capped to math.MaxFloat64, as huge values such as failure
+// penalties overflow it.
+func priorScale(values []float64) float64 {
+	scale := populationVariance(values)
+
+	switch {
+	case math.IsInf(scale, 1):
+		return math.MaxFloat64
+	case scale > 0:
+		return scale
+	}
+
+	return 1
+}
+
+// withinTolerance returns true if the points are within tolerance of each
+// other along each coordinate.
+func withinTolerance(x1, x2 []float64, tolerance float64) bool {
+	for i := range x1 {
+		if math.Abs(x1[i]-x2[i]) > tolerance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rbfKernel implements the Radial Basis Function (also known as Gaussian)
+// kernel. It measures the similarity between two points in the input space,
+// decreasing exponentially with distance.
+//
+// Parameters:
+// - x1, x2: Input vectors to compare, of the same length
+// - sigma: Kernel width
+//
+// Returns:
+// - float64: Kernel value (similarity) between the points (0.0 to 1.0)
+//
+// Mathematical formula:
+//
+//	k(x1, x2) = exp(-sum((x1 - x2)^2) / (2 * sigma^2))
+//
+// Important notes:
+// - Lengths aren't checked: points are validated once, on Update and
+// Predict, instead of on each of their n kernel evaluations
+// - Returns 1.0 for identical points
+// - Returns values close to 0.0 for distant points.
+func rbfKernel(x1, x2 []float64, sigma float64) float64 {
+	// Calculate squared Euclidean distance
+	var sum float64
+
+	for i := range x1 {
+		diff := x1[i] - x2[i]
+
+		sum += diff * diff
+	}
+
+	// Apply RBF kernel formula
+	return math.Exp(-sum / (2 * sigma * sigma))
+}
+
+// groupKernel returns the RBF kernel between the points along the
+// dimensions of the group only.
+func groupKernel(x1, x2 []float64, group []int, sigma float64) float64 {
+	var sum float64
+
+	for _, d := range group {
+		diff := x1[d] - x2[d]
+
+		sum += diff * diff
+	}
+
+	return math.Exp(-sum / (2 * sigma * sigma))
+}
+
 //////
 // Factory.
 //////
@@ -333,6 +990,32 @@ func (gp *gaussianProcess) GetSigma() float64 {
 // - Don't share instances between independent optimizations.
 func newGaussianProcess() *gaussianProcess {
 	return &gaussianProcess{
-		sigma: 1.0, // Default kernel width
+		sigma:  1.0, // Default kernel width
+		chol:   emptyCholesky(),
+		jitter: jitters[0],
 	}
 }
+
+// NewGaussianProcess creates a Gaussian process, the default model, with
+// the given options, e.g. to append repeated observations instead of merging
+// them.
+//
+// Parameters:
+// - options: Configures the model, zero values select the defaults
+//
+// Returns:
+// - SurrogateModel: The model, without observations.
+//
+// Usage example:
+//
+//	config := DefaultConfig()
+//	config.Surrogate = func() SurrogateModel {
+//	    return NewGaussianProcess(GaussianProcessOptions{MergeTolerance: 1e-9})
+//	}
+func NewGaussianProcess(options GaussianProcessOptions) SurrogateModel {
+	gp := newGaussianProcess()
+
+	gp.options = options
+
+	return gp
+}
@@ -0,0 +1,522 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// randomGP returns a Gaussian process updated with n observations at random
+// points of [0, 5]^dims.
+func randomGP(rng *rand.Rand, n, dims int) *gaussianProcess {
+	gp := newGaussianProcess()
+
+	for i := 0; i < n; i++ {
+		gp.Update(randomPoint(rng, dims), rng.NormFloat64())
+	}
+
+	return gp
+}
+
+// randomPoint returns a random point of [0, 5]^dims.
+func randomPoint(rng *rand.Rand, dims int) []float64 {
+	x := make([]float64, dims)
+
+	for d := range x {
+		x[d] = 5 * rng.Float64()
+	}
+
+	return x
+}
+
+func TestGaussianProcessPosterior(t *testing.T) {
+	gp := newGaussianProcess()
+
+	mean, variance := gp.Predict([]float64{1})
+
+	assert.Equal(t, 0.0, mean)
+	assert.Equal(t, 1.0, variance)
+
+	gp.Update([]float64{0}, 10)
+	gp.Update([]float64{2}, 20)
+
+	// Observations are interpolated, with little uncertainty left.
+	for _, observed := range [][2]float64{{0, 10}, {2, 20}} {
+		mean, variance := gp.Predict([]float64{observed[0]})
+
+		assert.InDelta(t, observed[1], mean, 1e-3)
+		assert.InDelta(t, 0, variance, 1e-3)
+	}
+
+	// Between observations, the mean lies between their values.
+	for _, x := range []float64{0.5, 1, 1.5} {
+		mean, _ := gp.Predict([]float64{x})
+
+		assert.Greater(t, mean, 10.0)
+		assert.Less(t, mean, 20.0)
+	}
+
+	// Away from observations, the prior is recovered: the mean and variance
+	// of the observed values.
+	mean, variance = gp.Predict([]float64{50})
+
+	assert.InDelta(t, 15, mean, 1e-9)
+	assert.InDelta(t, 25, variance, 1e-9)
+
+	// Duplicate observations are averaged.
+	gp.Update([]float64{0}, 12)
+
+	mean, _ = gp.Predict([]float64{0})
+
+	assert.InDelta(t, 11, mean, 1e-3)
+}
+
+func TestGaussianProcessIncrementalCholesky(t *testing.T) {
+	for _, n := range []int{refactorEvery - 6, 600} {
+		gp := randomGP(rand.New(rand.NewSource(int64(n))), n, 3)
+
+		full := gp.snapshot()
+
+		assert.NoError(t, full.factorize())
+
+		assert.Less(t, gp.appended, refactorEvery)
+
+		assertSameFactor(t, full.chol, gp.chol, 1e-9)
+
+		assert.InEpsilonSlice(t, full.whitened, gp.whitened, 1e-6)
+
+		x := []float64{2.5, 2.5, 2.5}
+
+		mean, variance := gp.Predict(x)
+		fullMean, fullVariance := full.Predict(x)
+
+		assert.InDelta(t, fullMean, mean, 1e-6)
+		assert.InDelta(t, fullVariance, variance, 1e-6)
+	}
+
+	// Changing the kernel width refactorizes.
+	gp := randomGP(rand.New(rand.NewSource(1)), 20, 2)
+
+	assert.NoError(t, gp.SetSigma(2))
+
+	fresh := newGaussianProcess()
+
+	assert.NoError(t, fresh.SetSigma(2))
+
+	for i, x := range gp.X {
+		fresh.Update(x, gp.Y[i])
+	}
+
+	assert.Equal(t, 0, gp.appended)
+	assert.InDeltaSlice(t, fresh.whitened, gp.whitened, 1e-6)
+}
+
+func TestGaussianProcessStability(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// Duplicate and nearly identical points, with different values.
+	for _, width := range []float64{0, 1e-12, 1e-9, 1e-6} {
+		gp := newGaussianProcess()
+
+		for i := 0; i < 50; i++ {
+			assert.NoError(t, gp.Update([]float64{1 + width*rng.Float64(), 2}, 10+rng.Float64()))
+		}
+
+		for _, x := range [][]float64{{1, 2}, {1 + width/2, 2}, {1.5, 2}, {50, 50}} {
+			mean, variance := gp.Predict(x)
+
+			assert.GreaterOrEqual(t, mean, 10.0)
+			assert.LessOrEqual(t, mean, 11.0)
+			assert.GreaterOrEqual(t, variance, 0.0)
+			assert.LessOrEqual(t, variance, gp.scale)
+		}
+	}
+
+	// Failure penalties don't overflow the prior.
+	gp := newGaussianProcess()
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, gp.Update([]float64{float64(i)}, math.MaxFloat64/2+float64(i)))
+	}
+
+	mean, variance := gp.Predict([]float64{2.5})
+
+	assert.False(t, math.IsInf(mean, 0) || math.IsNaN(mean))
+	assert.False(t, math.IsInf(variance, 0) || math.IsNaN(variance))
+
+	// Non-finite observations are rejected, leaving the model untouched.
+	for _, observation := range []struct {
+		x []float64
+		y float64
+	}{
+		{[]float64{1}, math.NaN()},
+		{[]float64{1}, math.Inf(-1)},
+		{[]float64{math.Inf(1)}, 1},
+	} {
+		assert.ErrorIs(t, gp.Update(observation.x, observation.y), ErrInvalidObservation)
+	}
+
+	assert.Equal(t, 5, gp.Len())
+
+	// A jitter beyond the minimal one is reported.
+	assert.Empty(t, jitterWarning(gp))
+
+	gp.jitter = jitters[1]
+
+	assert.Contains(t, jitterWarning(gp), "ill-conditioned")
+}
+
+func TestGaussianProcessDuplicates(t *testing.T) {
+	gp := newGaussianProcess()
+
+	assert.NoError(t, gp.Update([]float64{5, 5}, 0))
+
+	for _, y := range []float64{10, 12, 11, 9, 13} {
+		assert.NoError(t, gp.Update([]float64{1, 2}, y))
+	}
+
+	clone := gp.snapshot()
+
+	assert.NoError(t, clone.Update([]float64{1, 2}, 100))
+
+	// Repeats are merged into a single point, with their mean.
+	assert.Equal(t, [][]float64{{5, 5}, {1, 2}}, gp.Points())
+	assert.Equal(t, []float64{0, 11}, gp.Y)
+	assert.Equal(t, []float64{1, 5}, gp.weights)
+	assert.Equal(t, 6, gp.Len())
+
+	mean, _ := gp.Predict([]float64{1, 2})
+
+	assert.InDelta(t, 11, mean, 1e-3)
+
+	// Merging into a clone leaves the model untouched.
+	assert.InDelta(t, 155.0/6, clone.Y[1], 1e-9)
+	assert.Equal(t, 7, clone.Len())
+
+	// Nearby points are merged within the tolerance only.
+	tolerant := NewGaussianProcess(GaussianProcessOptions{MergeTolerance: 0.1})
+
+	for _, x := range [][]float64{{1, 2}, {1.05, 2}, {1.2, 2}} {
+		assert.NoError(t, tolerant.Update(x, 1))
+	}
+
+	assert.Equal(t, [][]float64{{1, 2}, {1.2, 2}}, tolerant.Points())
+
+	// Exact Gaussian processes keep every observation.
+	exact := NewGaussianProcess(GaussianProcessOptions{AppendDuplicates: true})
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, exact.Update([]float64{1, 2}, float64(i)))
+	}
+
+	assert.Len(t, exact.Points(), 5)
+	assert.Equal(t, 5, exact.Len())
+}
+
+func TestGaussianProcessWeights(t *testing.T) {
+	// An outlier between observations of a line.
+	fit := func(weight float64) *gaussianProcess {
+		gp := newGaussianProcess()
+
+		for x := 0.0; x <= 4; x++ {
+			assert.NoError(t, gp.Update([]float64{x}, x))
+		}
+
+		assert.NoError(t, gp.UpdateWeighted([]float64{2.5}, 50, weight))
+
+		return gp
+	}
+
+	full, _ := fit(1).Predict([]float64{2.5})
+	light, _ := fit(0.01).Predict([]float64{2.5})
+
+	// At full weight the outlier is interpolated, down-weighted it barely
+	// moves the prediction off the line.
+	assert.InDelta(t, 50, full, 1)
+	assert.InDelta(t, 2.5, light, 2)
+
+	// Merged into an observed point, it moves its mean in proportion.
+	gp := newGaussianProcess()
+
+	assert.NoError(t, gp.Update([]float64{1}, 10))
+	assert.NoError(t, gp.UpdateWeighted([]float64{1}, 20, 0.25))
+
+	assert.InDelta(t, 12, gp.Y[0], 1e-9)
+	assert.Equal(t, []float64{1.25}, gp.weights)
+	assert.Equal(t, 2, gp.Len())
+
+	// Weights above 1 shrink the noise like repeats do.
+	repeated := newGaussianProcess()
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, repeated.Update([]float64{1}, 10))
+	}
+
+	weighted := newGaussianProcess()
+
+	assert.NoError(t, weighted.UpdateWeighted([]float64{1}, 10, 4))
+	assert.Equal(t, repeated.diagonal(0), weighted.diagonal(0))
+
+	for _, weight := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		assert.ErrorIs(t, gp.UpdateWeighted([]float64{2}, 1, weight), ErrInvalidObservation, weight)
+	}
+
+	assert.Equal(t, 2, gp.Len())
+}
+
+func TestSurrogateDimensions(t *testing.T) {
+	models := []SurrogateModel{
+		newGaussianProcess(),
+		NewStudentTProcess(StudentTProcessOptions{}),
+		NewRandomForest(RandomForestOptions{}),
+	}
+
+	for _, model := range models {
+		assert.NoError(t, model.Update([]float64{1, 2}, 1))
+
+		err := model.Update([]float64{1, 2, 3}, 1)
+
+		assert.ErrorIs(t, err, ErrInvalidObservation)
+		assert.EqualError(t, err, "invalid observation: point has 3 dimensions, expected 2")
+		assert.Equal(t, 1, model.Len())
+	}
+
+	// Malformed points are predicted as NaN, and reported by posterior.
+	gp := models[0].(*gaussianProcess)
+
+	mean, variance := gp.Predict([]float64{1})
+	assert.True(t, math.IsNaN(mean))
+	assert.True(t, math.IsNaN(variance))
+
+	means, variances := gp.PredictBatch([][]float64{{1, 2}, {1}})
+	assert.InDelta(t, 1, means[0], 1e-3)
+	assert.False(t, math.IsNaN(variances[0]))
+	assert.True(t, math.IsNaN(means[1]))
+	assert.True(t, math.IsNaN(variances[1]))
+
+	_, _, err := gp.posterior([][]float64{{1, 2}, {1}}, gp.prior, gp.scale, gp.whitened)
+	assert.ErrorIs(t, err, ErrInvalidObservation)
+	assert.EqualError(t, err, "invalid observation: point 1 has 1 dimensions, expected 2")
+
+	_, _, err = predictParams[float64](gp, []ParameterRange[float64]{{Min: 0, Max: 1}}, []float64{1})
+	assert.ErrorIs(t, err, ErrInvalidObservation)
+
+	additive := NewAdditiveGaussianProcess(AdditiveGaussianProcessOptions{Groups: [][]int{{0}, {1}}})
+	assert.NoError(t, additive.Update([]float64{1, 2}, 1))
+
+	termMeans, termVariances := additive.PredictTerms([][]float64{{1}})
+	assert.True(t, math.IsNaN(termMeans[0][0]))
+	assert.True(t, math.IsNaN(termVariances[1][0]))
+}
+
+func TestGaussianProcessNotPositiveDefinite(t *testing.T) {
+	gp := randomGP(rand.New(rand.NewSource(1)), 10, 2)
+
+	x := []float64{2.5, 2.5}
+
+	mean, variance := gp.Predict(x)
+
+	// The kernel matrix doesn't factorize with a NaN kernel width, the model
+	// is left untouched.
+	err := gp.SetSigma(math.NaN())
+	assert.ErrorIs(t, err, ErrInvalidObservation)
+	assert.Contains(t, err.Error(), "isn't positive definite")
+	assert.Equal(t, 1.0, gp.GetSigma())
+
+	afterMean, afterVariance := gp.Predict(x)
+	assert.Equal(t, mean, afterMean)
+	assert.Equal(t, variance, afterVariance)
+
+	// Updates failing to refit are rolled back, new and merged points alike.
+	gp.sigma = math.NaN()
+
+	points := gp.Points()
+
+	assert.ErrorIs(t, gp.Update(x, 1), ErrInvalidObservation)
+	assert.ErrorIs(t, gp.Update(points[0], 1), ErrInvalidObservation)
+	assert.Equal(t, 10, gp.Len())
+	assert.Equal(t, points, gp.Points())
+
+	gp.sigma = 1
+
+	afterMean, afterVariance = gp.Predict(x)
+	assert.Equal(t, mean, afterMean)
+	assert.Equal(t, variance, afterVariance)
+}
+
+// TestGaussianProcessConcurrency runs predictions concurrently with updates
+// and kernel width changes, so writers keep waiting on the lock while readers
+// hold it: a read lock taken twice by a reader would deadlock. Run with -race.
+func TestGaussianProcessConcurrency(t *testing.T) {
+	// Readers must run in parallel with the writer, even on a single CPU.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	gp := randomGP(rand.New(rand.NewSource(1)), 20, 2)
+
+	var wg sync.WaitGroup
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+
+		go func(seed int64) {
+			defer wg.Done()
+
+			rng := rand.New(rand.NewSource(seed))
+
+			for i := 0; i < 300; i++ {
+				gp.Predict(randomPoint(rng, 2))
+				gp.PredictBatch([][]float64{randomPoint(rng, 2), randomPoint(rng, 2)})
+			}
+		}(int64(r))
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		rng := rand.New(rand.NewSource(-1))
+
+		for i := 0; i < 1000; i++ {
+			if i%20 == 0 {
+				assert.NoError(t, gp.Update(randomPoint(rng, 2), rng.NormFloat64()))
+			}
+
+			gp.SetSigma(0.5 + rng.Float64())
+		}
+	}()
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("deadlock: concurrent predictions and updates didn't finish")
+	}
+
+	assert.Equal(t, 70, gp.Len())
+}
+
+// assertSameFactor asserts both factors are equal, within delta.
+func assertSameFactor(t *testing.T, expected, actual cholesky, delta float64) {
+	t.Helper()
+
+	if !assert.Equal(t, expected.size(), actual.size()) {
+		return
+	}
+
+	for i := 0; i < expected.size(); i++ {
+		for j := 0; j <= i; j++ {
+			assert.InDelta(t, expected.at(i, j), actual.at(i, j), delta, "L[%d][%d]", i, j)
+		}
+	}
+}
+
+// BenchmarkGaussianProcessUpdate compares adding an observation by extending
+// the Cholesky factor, in O(n^2), to refactorizing, in O(n^3).
+func BenchmarkGaussianProcessUpdate(b *testing.B) {
+	for _, n := range []int{500, 1000} {
+		rng := rand.New(rand.NewSource(1))
+
+		gp := randomGP(rng, n, 3)
+		x := randomPoint(rng, 3)
+
+		b.Run(fmt.Sprintf("incremental/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				clone := gp.snapshot()
+				clone.appended = 0
+
+				clone.Update(x, 1)
+			}
+		})
+
+		b.Run(fmt.Sprintf("refactorize/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				clone := gp.snapshot()
+				clone.X = append(clone.X, x)
+				clone.Y = append(clone.Y, 1)
+
+				_ = clone.factorize()
+			}
+		})
+	}
+}
+
+func TestGaussianProcessPredictBatch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	candidates := make([][]float64, 100)
+
+	for i := range candidates {
+		candidates[i] = randomPoint(rng, 3)
+	}
+
+	// The empty model predicts the prior.
+	means, variances := newGaussianProcess().PredictBatch(candidates[:2])
+
+	assert.Equal(t, []float64{0, 0}, means)
+	assert.Equal(t, []float64{1, 1}, variances)
+
+	models := []SurrogateModel{
+		randomGP(rng, 300, 3),
+		NewStudentTProcess(StudentTProcessOptions{}),
+		NewRandomForest(RandomForestOptions{}),
+	}
+
+	for _, x := range models[0].Points()[:50] {
+		y := rng.NormFloat64()
+
+		models[1].Update(x, y)
+		models[2].Update(x, y)
+	}
+
+	for _, model := range models {
+		means, variances := predictBatch(model, candidates)
+
+		for i, x := range candidates {
+			mean, variance := model.Predict(x)
+
+			assert.Equal(t, mean, means[i])
+			assert.Equal(t, variance, variances[i])
+		}
+	}
+}
+
+// BenchmarkGaussianProcessPredict compares scoring 500 candidates against 300
+// observations point by point, and at once.
+func BenchmarkGaussianProcessPredict(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+
+	gp := randomGP(rng, 300, 3)
+
+	candidates := make([][]float64, 500)
+
+	for i := range candidates {
+		candidates[i] = randomPoint(rng, 3)
+	}
+
+	b.Run("point", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, x := range candidates {
+				gp.Predict(x)
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			gp.PredictBatch(candidates)
+		}
+	})
+}
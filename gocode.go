@@ -0,0 +1,374 @@
+package ho
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"math"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// durationUnits are the units durations are written in by GoCode, largest
+// first.
+var durationUnits = []struct {
+	unit time.Duration
+	name string
+}{
+	{time.Hour, "time.Hour"},
+	{time.Minute, "time.Minute"},
+	{time.Second, "time.Second"},
+	{time.Millisecond, "time.Millisecond"},
+	{time.Microsecond, "time.Microsecond"},
+}
+
+//////
+// Methods.
+//////
+
+// GoCode renders the best parameters as Go source, ready to paste instead
+// of transcribing them by hand: a struct literal of the struct type for
+// OptimizeStruct runs, a block of named constants otherwise, preceded by
+// comments carrying the best value and the run metadata.
+//
+// Parameters:
+// - packagePath: Import path of the package the source goes in, e.g.
+// "example.com/app/tuning", or just its name, e.g. "tuning". The last
+// element is the package clause
+// - varName: Name of the struct variable, or prefix of the constants, e.g.
+// "best" for bestWorkers and bestTimeout
+//
+// Returns:
+// - string: The source, formatted by go/format
+// - error: Wrapping ErrInvalidConfig if varName or the package name isn't a
+// valid identifier,
+// parameter names collide once turned into identifiers, or the run has no
+// best parameters.
+//
+// Usage example:
+//
+//	result := Optimize(config, benchmark,
+//	    ParameterRange[int]{Name: "Workers", Min: 1, Max: 32},
+//	    DurationRange{Name: "Timeout", Min: 10 * time.Millisecond, Max: time.Second}.Range(),
+//	)
+//
+//	code, err := result.GoCode("tuning", "best")
+//
+//	// package tuning
+//	//
+//	// import "time"
+//	//
+//	// // The best constants hold the best configuration found.
+//	// // ...
+//	// const (
+//	//     bestWorkers = 8
+//	//     bestTimeout = 250 * time.Millisecond
+//	// )
+//
+// Important notes:
+// - Integers are rounded, durations are written in the largest unit they're
+// a whole number of, enums as the chosen string. Integer-typed runs render
+// values exactly, e.g. math.MaxUint64
+// - The struct type of an OptimizeStruct run is referred to unqualified only
+// if its import path is packagePath, so pass the full path to generate code
+// for the package declaring it
+// - Values are those the benchmark received, see ParameterRange.Transform
+// - Struct literals only set the tuned fields: set the others as in the
+// template
+// - Unnamed parameters are named "param<i>", i being their 0-based index.
+func (r *Result[T]) GoCode(packagePath, varName string) (string, error) {
+	packageName := path.Base(packagePath)
+
+	for _, name := range []string{packageName, varName} {
+		if !token.IsIdentifier(name) {
+			return "", fmt.Errorf("%w: %q isn't a valid Go identifier", ErrInvalidConfig, name)
+		}
+	}
+
+	if r.BestTime == math.MaxFloat64 || len(r.BestParams) == 0 {
+		return "", fmt.Errorf("%w: the run has no best parameters to render", ErrInvalidConfig)
+	}
+
+	var (
+		decl    string
+		imports []string
+		err     error
+	)
+
+	typeName, typeImport, isStruct := r.structTypeName(packagePath)
+
+	if isStruct {
+		decl, imports = r.goStruct(varName, typeName), []string{typeImport}
+	} else {
+		decl, err = r.goConstants(varName)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if strings.Contains(decl, "time.") {
+		imports = append(imports, "time")
+	}
+
+	imports = slices.DeleteFunc(imports, func(imported string) bool { return imported == "" })
+
+	slices.Sort(imports)
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+
+	switch len(imports) {
+	case 0:
+	case 1:
+		fmt.Fprintf(&b, "import %q\n\n", imports[0])
+	default:
+		b.WriteString("import (\n")
+
+		for _, imported := range imports {
+			fmt.Fprintf(&b, "%q\n", imported)
+		}
+
+		b.WriteString(")\n\n")
+	}
+
+	for _, line := range r.goComment(varName, isStruct) {
+		fmt.Fprintf(&b, "// %s\n", line)
+	}
+
+	b.WriteString(decl)
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("formatting the generated code: %w", err)
+	}
+
+	return string(source), nil
+}
+
+// structTypeName returns how the struct type of an OptimizeStruct run is
+// referred to from the package at packagePath, and the import it needs, if
+// any.
+//
+// Returns:
+// - string: The type name, qualified if imported
+// - string: The import path, empty if none is needed
+// - bool: False if the run isn't an OptimizeStruct one, or its type can't be
+// referred to, i.e. it's anonymous, or unexported or in a main package from
+// another package.
+func (r *Result[T]) structTypeName(packagePath string) (string, string, bool) {
+	t := r.structType
+
+	switch {
+	case t == nil || t.Name() == "":
+		return "", "", false
+	case t.PkgPath() == packagePath:
+		return t.Name(), "", true
+	case t.PkgPath() == "main" || !token.IsExported(t.Name()):
+		return "", "", false
+	}
+
+	return path.Base(t.PkgPath()) + "." + t.Name(), t.PkgPath(), true
+}
+
+// goStruct renders the best parameters as a struct literal of the struct
+// type of an OptimizeStruct run.
+func (r *Result[T]) goStruct(varName, typeName string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "var %s = %s{\n", varName, typeName)
+
+	for i, v := range r.benchmarkBest() {
+		fmt.Fprintf(&b, "%s: %s,\n", paramName(r.ParamNames, i), r.goValue(i, v))
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// goConstants renders the best parameters as a block of constants named
+// after varName and the parameters.
+//
+// Returns:
+// - string: The block
+// - error: Wrapping ErrInvalidConfig if two parameters get the same name.
+func (r *Result[T]) goConstants(varName string) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("const (\n")
+
+	seen := make(map[string]bool, len(r.ParamNames))
+
+	for i, v := range r.benchmarkBest() {
+		name := varName + goIdentifier(paramName(r.ParamNames, i))
+
+		if seen[name] {
+			return "", fmt.Errorf("%w: parameters render as the same constant %s", ErrInvalidConfig, name)
+		}
+
+		seen[name] = true
+
+		fmt.Fprintf(&b, "%s = %s\n", name, r.goValue(i, v))
+	}
+
+	b.WriteString(")\n")
+
+	return b.String(), nil
+}
+
+// goValue renders the value of the i-th parameter as a Go expression, by its
+// type: durations, bools and enums as such, numbers as integers or floats,
+// see integral. Integers of integer-typed runs are formatted from their
+// type, as float64 doesn't hold them all exactly.
+func (r *Result[T]) goValue(i int, v T) string {
+	hyper := r.hyper(i)
+
+	f := float64(v)
+
+	switch paramType(hyper) {
+	case DurationParameter:
+		if half := 0.5; T(half) == 0 {
+			return goDuration(time.Duration(v))
+		}
+
+		return goDuration(time.Duration(math.Round(f)))
+	case BoolParameter:
+		return strconv.FormatBool(f >= 0.5)
+	case EnumParameter:
+		return strconv.Quote(enumValue(hyper.Values, f))
+	}
+
+	if r.integral(i) {
+		if half := 0.5; T(half) != 0 {
+			return strconv.FormatFloat(math.Round(f), 'f', -1, 64)
+		}
+
+		if v < 0 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+
+		return strconv.FormatUint(uint64(v), 10)
+	}
+
+	// Untyped constants written without a dot or exponent would be ints.
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+
+	return s
+}
+
+// integral returns true if the i-th parameter is an integer: an integer
+// field of an OptimizeStruct run, or an IntParameter.
+func (r *Result[T]) integral(i int) bool {
+	if r.structType != nil {
+		if field, ok := r.structType.FieldByName(paramName(r.ParamNames, i)); ok {
+			kind := field.Type.Kind()
+
+			return kind != reflect.Float32 && kind != reflect.Float64
+		}
+	}
+
+	return paramType(r.hyper(i)) == IntParameter
+}
+
+// hyper returns the range of the i-th parameter, a float one if unknown.
+func (r *Result[T]) hyper(i int) ParameterRange[T] {
+	if i < len(r.hypers) {
+		return r.hypers[i]
+	}
+
+	return ParameterRange[T]{Type: FloatParameter}
+}
+
+// goComment returns the lines of the comment of the declaration: what it
+// holds, the best value, and the run metadata.
+func (r *Result[T]) goComment(varName string, isStruct bool) []string {
+	lines := []string{"The " + varName + " constants hold the best configuration found."}
+
+	if isStruct {
+		lines[0] = varName + " is the best configuration found. Untuned fields are left out."
+	}
+
+	best := "Best value: " + strconv.FormatFloat(r.BestTime, 'g', -1, 64)
+
+	for _, trial := range r.Trials {
+		if trial.Status == TrialCompleted && trial.ExecutionTime == r.BestTime && slices.Equal(trial.Params, r.BestParams) {
+			best += fmt.Sprintf(" (trial %d)", trial.TrialID)
+
+			break
+		}
+	}
+
+	run := fmt.Sprintf("Run: %d trials, seed %d", len(r.Trials), r.Seed)
+
+	if r.StudyID != "" {
+		run += ", study " + r.StudyID
+	}
+
+	if r.TerminationReason != "" {
+		run += ", ended: " + string(r.TerminationReason)
+	}
+
+	return append(lines, "", best, run)
+}
+
+//////
+// Helpers.
+//////
+
+// goDuration renders a duration as a Go expression, in the largest unit it
+// is a whole number of, e.g. "250 * time.Millisecond".
+func goDuration(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+
+	for _, u := range durationUnits {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+
+	return fmt.Sprintf("%d * time.Nanosecond", d)
+}
+
+// goIdentifier turns a parameter name into the suffix of an identifier, e.g.
+// "buffer-size" into "BufferSize": letters and digits are kept, each run of
+// them capitalized.
+func goIdentifier(name string) string {
+	var b strings.Builder
+
+	upper := true
+
+	for _, c := range name {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			upper = true
+
+			continue
+		}
+
+		if upper {
+			c = unicode.ToUpper(c)
+		}
+
+		b.WriteRune(c)
+
+		upper = false
+	}
+
+	return b.String()
+}
@@ -0,0 +1,184 @@
+package ho
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tunedServer is a struct whose best configuration is rendered.
+type tunedServer struct {
+	Workers     int           `ho:"min=1,max=32"`
+	Ratio       float64       `ho:"min=0,max=1"`
+	Timeout     time.Duration `ho:"min=10ms,max=1s"`
+	Compression bool          `ho:""`
+	Codec       string        `ho:"values=zstd|lz4|none"`
+	Addr        string
+}
+
+// TunedClient is an exported struct whose best configuration is rendered.
+type TunedClient struct {
+	Retries int `ho:"min=0,max=5"`
+}
+
+// codeResult returns a finished run over the ranges, whose best trial has
+// the parameters.
+func codeResult[T int | int64 | uint64 | float64](params []T, hypers ...ParameterRange[T]) *Result[T] {
+	names := make([]string, len(hypers))
+
+	for i, hyper := range hypers {
+		names[i] = hyper.Name
+	}
+
+	return &Result[T]{
+		BestParams: params,
+		BestTime:   1.25,
+		Trials: []Trial[T]{
+			{TrialInfo: TrialInfo{TrialID: 1}, Params: params, ExecutionTime: 3, Status: TrialCompleted},
+			{TrialInfo: TrialInfo{TrialID: 2}, Params: params, ExecutionTime: 1.25, Status: TrialCompleted},
+		},
+		TerminationReason: TerminationCompleted,
+		ParamNames:        names,
+		Seed:              42,
+		hypers:            hypers,
+	}
+}
+
+// assertCompiles parses and type-checks the source.
+func assertCompiles(t *testing.T, source string) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "best.go", source, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	config := types.Config{Importer: importer.Default()}
+
+	_, err = config.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	assert.NoError(t, err)
+}
+
+func TestGoCode(t *testing.T) {
+	for name, result := range map[string]*Result[float64]{
+		"int":      codeResult([]float64{8.4, 3}, ParameterRange[float64]{Name: "workers", Type: IntParameter, Min: 1, Max: 32}, ParameterRange[float64]{Min: 1, Max: 4, Type: IntParameter}),
+		"float":    codeResult([]float64{0.003, 2}, ParameterRange[float64]{Name: "learning-rate", Min: 0.001, Max: 0.1}, ParameterRange[float64]{Name: "momentum", Min: 0, Max: 4}),
+		"bool":     codeResult([]float64{1, 0.2}, BoolParam{Name: "compression"}.Range(), BoolParam{Name: "fsync"}.Range()),
+		"duration": codeResult([]float64{2.5e8, 90e9, 1500}, DurationRange{Name: "timeout", Min: time.Millisecond, Max: time.Second}.Range(), DurationRange{Name: "idle", Min: time.Second, Max: time.Hour}.Range(), DurationRange{Name: "spin", Min: 0, Max: time.Millisecond}.Range()),
+		"enum":     codeResult([]float64{1.2}, EnumParam{Name: "codec", Values: []string{"zstd", "lz4", "none"}}.Range()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			code, err := result.GoCode("tuning", "best")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assertGolden(t, "gocode_"+name+".go.golden", code)
+			assertCompiles(t, code)
+		})
+	}
+
+	// Integers are exact, even beyond 2^53.
+	t.Run("uint64", func(t *testing.T) {
+		code, err := codeResult([]uint64{math.MaxUint64, 1<<53 + 1}, ParameterRange[uint64]{Name: "seed", Max: math.MaxUint64}, ParameterRange[uint64]{Name: "offset", Max: math.MaxUint64}).GoCode("tuning", "best")
+		if assert.NoError(t, err) {
+			assertGolden(t, "gocode_uint64.go.golden", code)
+			assertCompiles(t, code)
+		}
+	})
+
+	t.Run("int64", func(t *testing.T) {
+		code, err := codeResult([]int64{math.MaxInt64, math.MinInt64}, ParameterRange[int64]{Name: "max", Min: math.MinInt64, Max: math.MaxInt64}, ParameterRange[int64]{Name: "min", Min: math.MinInt64, Max: math.MaxInt64}).GoCode("tuning", "best")
+		if assert.NoError(t, err) {
+			assertGolden(t, "gocode_int64.go.golden", code)
+			assertCompiles(t, code)
+		}
+	})
+
+	t.Run("int parameters", func(t *testing.T) {
+		code, err := codeResult([]int{8}, ParameterRange[int]{Name: "Workers", Min: 1, Max: 32}).GoCode("main", "Best")
+		if assert.NoError(t, err) {
+			assert.Contains(t, code, "BestWorkers = 8\n")
+			assertCompiles(t, code)
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 1
+
+		_, result := OptimizeStruct(config, func(tunedServer) error { return nil }, tunedServer{Addr: ":8080"})
+		if !assert.NoError(t, result.Err) {
+			return
+		}
+
+		// Pin the values, so the golden file doesn't follow the optimizer.
+		result.BestParams = []float64{7.6, 0.5, 2.5e8, 0.9, 2}
+		result.BestTime = 1.25
+		result.Trials = result.Trials[:2]
+
+		code, err := result.GoCode("github.com/thalesfsp/ho", "best")
+		if assert.NoError(t, err) {
+			assertGolden(t, "gocode_struct.go.golden", code)
+
+			_, err = parser.ParseFile(token.NewFileSet(), "best.go", code, 0)
+			assert.NoError(t, err)
+		}
+
+		// Other packages can't refer to the unexported type, even with the
+		// same name.
+		for _, packagePath := range []string{"tuning", "ho", "example.com/fork/ho"} {
+			code, err = result.GoCode(packagePath, "best")
+			if assert.NoError(t, err) {
+				assert.Contains(t, code, "bestWorkers     = 8\n")
+				assertCompiles(t, code)
+			}
+		}
+
+		// Exported types are imported unless the package is theirs.
+		_, client := OptimizeStruct(config, func(TunedClient) error { return nil }, TunedClient{})
+		if !assert.NoError(t, client.Err) {
+			return
+		}
+
+		code, err = client.GoCode("github.com/thalesfsp/ho", "best")
+		if assert.NoError(t, err) {
+			assert.Contains(t, code, "var best = TunedClient{")
+			assert.NotContains(t, code, "import")
+		}
+
+		code, err = client.GoCode("example.com/fork/ho", "best")
+		if assert.NoError(t, err) {
+			assert.Contains(t, code, "var best = ho.TunedClient{")
+			assert.Contains(t, code, `import "github.com/thalesfsp/ho"`)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		result := codeResult([]float64{1, 2}, ParameterRange[float64]{Name: "a-b"}, ParameterRange[float64]{Name: "aB"})
+
+		for name, names := range map[string][2]string{
+			"package":  {"my-package", "best"},
+			"variable": {"tuning", "1best"},
+			"empty":    {"tuning", ""},
+		} {
+			_, err := codeResult([]float64{1}, ParameterRange[float64]{Name: "a"}).GoCode(names[0], names[1])
+			assert.ErrorIs(t, err, ErrInvalidConfig, name)
+		}
+
+		_, err := result.GoCode("tuning", "best")
+		assert.ErrorIs(t, err, ErrInvalidConfig, "collision")
+
+		_, err = (&Result[float64]{BestTime: 1.7976931348623157e308}).GoCode("tuning", "best")
+		assert.ErrorIs(t, err, ErrInvalidConfig, "no best")
+	})
+}
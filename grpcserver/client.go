@@ -0,0 +1,154 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/thalesfsp/ho"
+	"github.com/thalesfsp/ho/grpcserver/hopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// EvaluateFunc evaluates a suggestion, and returns the value to minimize.
+// Returning (or wrapping) ho.ErrSkipTrial reports an invalidated measurement,
+// and ho.ErrStopOptimization stops the study.
+type EvaluateFunc func(ctx context.Context, params map[string]float64) (float64, error)
+
+// Client is a Go client of the StudyService.
+type Client struct {
+	// client is the generated client.
+	client hopb.StudyServiceClient
+}
+
+//////
+// Methods.
+//////
+
+// CreateStudy creates a study.
+//
+// Parameters:
+// - ctx: Request context
+// - config: Configuration document, JSON or YAML, see ho.LoadConfig
+// - leaseTimeout: Lease duration of suggestions, the server default if zero
+//
+// Returns:
+// - string: The study ID
+// - error: If the configuration is invalid (codes.InvalidArgument), or the
+// request fails.
+func (c *Client) CreateStudy(ctx context.Context, config string, leaseTimeout time.Duration) (string, error) {
+	resp, err := c.client.CreateStudy(ctx, &hopb.CreateStudyRequest{
+		Config:         config,
+		LeaseTimeoutMs: leaseTimeout.Milliseconds(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetStudyId(), nil
+}
+
+// Best returns the best result of a study so far, along with its progress.
+func (c *Client) Best(ctx context.Context, studyID string) (*hopb.GetBestResponse, error) {
+	return c.client.GetBest(ctx, &hopb.GetBestRequest{StudyId: studyID})
+}
+
+// Work evaluates suggestions of a study until it's done.
+//
+// Parameters:
+// - ctx: Canceling it stops the worker, its current suggestion is re-issued
+// once its lease expires
+// - studyID: The study to work for
+// - workerID: Identifies the worker, for bookkeeping
+// - evaluate: Evaluates suggestions
+//
+// Returns:
+// - error: If a request fails.
+//
+// Important notes:
+// - Results rejected because another worker reported the suggestion first,
+// e.g. after the lease expired, are ignored.
+func (c *Client) Work(ctx context.Context, studyID, workerID string, evaluate EvaluateFunc) error {
+	stream, err := c.client.StreamSuggestions(ctx, &hopb.StreamSuggestionsRequest{
+		StudyId:  studyID,
+		WorkerId: workerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		suggestion, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		value, evalErr := evaluate(ctx, suggestion.GetParams())
+
+		// The worker is stopping, the lease expires.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		_, err = c.client.ReportResult(ctx, report(suggestion, value, evalErr))
+		if err != nil && status.Code(err) != codes.FailedPrecondition {
+			return fmt.Errorf("reporting trial %d: %w", suggestion.GetTrialId(), err)
+		}
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// report builds the report of an evaluation.
+func report(suggestion *hopb.Suggestion, value float64, err error) *hopb.ReportResultRequest {
+	req := &hopb.ReportResultRequest{
+		StudyId: suggestion.GetStudyId(),
+		TrialId: suggestion.GetTrialId(),
+	}
+
+	switch {
+	case errors.Is(err, ho.ErrStopOptimization):
+		req.Stop = true
+
+		// The server wraps the error with ho.ErrStopOptimization again.
+		req.Error = strings.TrimSuffix(strings.TrimSuffix(err.Error(), ho.ErrStopOptimization.Error()), ": ")
+	case errors.Is(err, ho.ErrSkipTrial):
+		req.Skipped = true
+	case err != nil:
+		req.Error = err.Error()
+	default:
+		req.Value = &value
+	}
+
+	return req
+}
+
+//////
+// Factory.
+//////
+
+// NewClient creates a Client.
+//
+// Parameters:
+// - conn: Connection to the server, e.g. from grpc.NewClient
+//
+// Returns:
+// - *Client: The client.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: hopb.NewStudyServiceClient(conn)}
+}
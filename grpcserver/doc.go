@@ -0,0 +1,40 @@
+// Package grpcserver exposes ask/tell optimization (see ho.Optimizer) over
+// gRPC, for distributed evaluation workers written in any language. The
+// protocol is defined in proto/ho.proto, generated Go code lives in hopb:
+//
+//	CreateStudy        Create a study from a configuration document (see
+//	                   ho.LoadConfig)
+//	StreamSuggestions  Stream suggestions to a worker, one at a time
+//	ReportResult       Report the result of a suggestion
+//	GetBest            Get the best result so far
+//
+// Suggestions are leased: a suggestion not reported within its lease (e.g.
+// its worker crashed) is re-issued to the next worker asking. The first
+// result reported for a suggestion wins, later ones are rejected with
+// codes.FailedPrecondition.
+//
+// A stream sends the next suggestion once the previous one is reported, or
+// its lease expires, and ends once the study is done. Results of outstanding
+// suggestions are still accepted afterwards.
+//
+// Usage example:
+//
+//	listener, err := net.Listen("tcp", ":9090")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	server := grpc.NewServer()
+//
+//	hopb.RegisterStudyServiceServer(server, grpcserver.New(grpcserver.Options{}))
+//
+//	log.Fatal(server.Serve(listener))
+//
+// Go workers can use Client, see examples/worker for a complete worker.
+//
+// Thread safety:
+// - Studies are locked independently, so workers of different studies don't
+// contend.
+package grpcserver
+
+//go:generate protoc -I proto --go_out=hopb --go_opt=paths=source_relative --go-grpc_out=hopb --go-grpc_opt=paths=source_relative ho.proto
@@ -0,0 +1,90 @@
+// Command worker is an example distributed evaluation worker: it evaluates
+// suggestions of a study served by a grpcserver.Server until the study is
+// done, then prints the best result.
+//
+// Usage:
+//
+//	worker -addr localhost:9090 -study 3f9a...
+//
+// Without -study, a study is created first, with a toy search space. Start
+// more workers on the printed study ID to evaluate in parallel.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/thalesfsp/ho/grpcserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// studyDocument is the configuration of the study created without -study.
+const studyDocument = `{
+	"iterations": 20,
+	"initialSamples": 5,
+	"parameters": [
+		{"name": "x", "type": "float", "min": -5, "max": 5},
+		{"name": "y", "type": "float", "min": -5, "max": 5}
+	]
+}`
+
+// evaluate is the function to minimize, a stand-in for a real benchmark.
+func evaluate(ctx context.Context, params map[string]float64) (float64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	return math.Pow(params["x"]-1, 2) + math.Pow(params["y"]+2, 2), nil
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:9090", "Server address")
+	studyID := flag.String("study", "", "Study to work for, a new one is created if empty")
+	workerID := flag.String("worker", "", "Worker ID, the host name if empty")
+
+	flag.Parse()
+
+	if *workerID == "" {
+		*workerID, _ = os.Hostname()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	client := grpcserver.NewClient(conn)
+
+	if *studyID == "" {
+		if *studyID, err = client.CreateStudy(ctx, studyDocument, 0); err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println("study:", *studyID)
+	}
+
+	if err := client.Work(ctx, *studyID, *workerID, evaluate); err != nil {
+		log.Fatal(err)
+	}
+
+	best, err := client.Best(ctx, *studyID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("best: %v (value %g)\n", best.GetBest().GetParams(), best.GetBest().GetValue())
+}
@@ -0,0 +1,820 @@
+// Protocol for distributed evaluation workers: workers in any language pull
+// parameter suggestions and push results back, while the server owns the
+// model. See the grpcserver Go package for the semantics.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: ho.proto
+
+package hopb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Parameter is a dimension of the search space.
+type Parameter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// "int" or "float".
+	Type string  `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Min  float64 `protobuf:"fixed64,3,opt,name=min,proto3" json:"min,omitempty"`
+	Max  float64 `protobuf:"fixed64,4,opt,name=max,proto3" json:"max,omitempty"`
+	// "linear" or "log".
+	Scale         string  `protobuf:"bytes,5,opt,name=scale,proto3" json:"scale,omitempty"`
+	Step          float64 `protobuf:"fixed64,6,opt,name=step,proto3" json:"step,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Parameter) Reset() {
+	*x = Parameter{}
+	mi := &file_ho_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Parameter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Parameter) ProtoMessage() {}
+
+func (x *Parameter) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Parameter.ProtoReflect.Descriptor instead.
+func (*Parameter) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Parameter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Parameter) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Parameter) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Parameter) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Parameter) GetScale() string {
+	if x != nil {
+		return x.Scale
+	}
+	return ""
+}
+
+func (x *Parameter) GetStep() float64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+type CreateStudyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Configuration document, JSON or YAML, with the search space (see
+	// ho.LoadConfig).
+	Config string `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	// Lease duration of suggestions, in milliseconds. A suggestion not reported
+	// within its lease is re-issued to another worker. Zero means the server
+	// default.
+	LeaseTimeoutMs int64 `protobuf:"varint,2,opt,name=lease_timeout_ms,json=leaseTimeoutMs,proto3" json:"lease_timeout_ms,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateStudyRequest) Reset() {
+	*x = CreateStudyRequest{}
+	mi := &file_ho_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateStudyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateStudyRequest) ProtoMessage() {}
+
+func (x *CreateStudyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateStudyRequest.ProtoReflect.Descriptor instead.
+func (*CreateStudyRequest) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateStudyRequest) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+func (x *CreateStudyRequest) GetLeaseTimeoutMs() int64 {
+	if x != nil {
+		return x.LeaseTimeoutMs
+	}
+	return 0
+}
+
+type CreateStudyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StudyId       string                 `protobuf:"bytes,1,opt,name=study_id,json=studyId,proto3" json:"study_id,omitempty"`
+	Parameters    []*Parameter           `protobuf:"bytes,2,rep,name=parameters,proto3" json:"parameters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateStudyResponse) Reset() {
+	*x = CreateStudyResponse{}
+	mi := &file_ho_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateStudyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateStudyResponse) ProtoMessage() {}
+
+func (x *CreateStudyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateStudyResponse.ProtoReflect.Descriptor instead.
+func (*CreateStudyResponse) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateStudyResponse) GetStudyId() string {
+	if x != nil {
+		return x.StudyId
+	}
+	return ""
+}
+
+func (x *CreateStudyResponse) GetParameters() []*Parameter {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+type StreamSuggestionsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	StudyId string                 `protobuf:"bytes,1,opt,name=study_id,json=studyId,proto3" json:"study_id,omitempty"`
+	// Identifies the worker, for bookkeeping.
+	WorkerId string `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	// Maximum number of suggestions to stream. Zero means until the study is
+	// done.
+	MaxSuggestions int32 `protobuf:"varint,3,opt,name=max_suggestions,json=maxSuggestions,proto3" json:"max_suggestions,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StreamSuggestionsRequest) Reset() {
+	*x = StreamSuggestionsRequest{}
+	mi := &file_ho_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSuggestionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSuggestionsRequest) ProtoMessage() {}
+
+func (x *StreamSuggestionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSuggestionsRequest.ProtoReflect.Descriptor instead.
+func (*StreamSuggestionsRequest) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamSuggestionsRequest) GetStudyId() string {
+	if x != nil {
+		return x.StudyId
+	}
+	return ""
+}
+
+func (x *StreamSuggestionsRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *StreamSuggestionsRequest) GetMaxSuggestions() int32 {
+	if x != nil {
+		return x.MaxSuggestions
+	}
+	return 0
+}
+
+// Suggestion is a set of parameters to evaluate.
+type Suggestion struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	StudyId   string                 `protobuf:"bytes,1,opt,name=study_id,json=studyId,proto3" json:"study_id,omitempty"`
+	TrialId   int64                  `protobuf:"varint,2,opt,name=trial_id,json=trialId,proto3" json:"trial_id,omitempty"`
+	Phase     string                 `protobuf:"bytes,3,opt,name=phase,proto3" json:"phase,omitempty"`
+	Iteration int32                  `protobuf:"varint,4,opt,name=iteration,proto3" json:"iteration,omitempty"`
+	Params    map[string]float64     `protobuf:"bytes,5,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	// When the lease expires, in Unix nanoseconds.
+	LeaseExpiresUnixNano int64 `protobuf:"varint,6,opt,name=lease_expires_unix_nano,json=leaseExpiresUnixNano,proto3" json:"lease_expires_unix_nano,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Suggestion) Reset() {
+	*x = Suggestion{}
+	mi := &file_ho_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Suggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suggestion) ProtoMessage() {}
+
+func (x *Suggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suggestion.ProtoReflect.Descriptor instead.
+func (*Suggestion) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Suggestion) GetStudyId() string {
+	if x != nil {
+		return x.StudyId
+	}
+	return ""
+}
+
+func (x *Suggestion) GetTrialId() int64 {
+	if x != nil {
+		return x.TrialId
+	}
+	return 0
+}
+
+func (x *Suggestion) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *Suggestion) GetIteration() int32 {
+	if x != nil {
+		return x.Iteration
+	}
+	return 0
+}
+
+func (x *Suggestion) GetParams() map[string]float64 {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *Suggestion) GetLeaseExpiresUnixNano() int64 {
+	if x != nil {
+		return x.LeaseExpiresUnixNano
+	}
+	return 0
+}
+
+type ReportResultRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	StudyId string                 `protobuf:"bytes,1,opt,name=study_id,json=studyId,proto3" json:"study_id,omitempty"`
+	TrialId int64                  `protobuf:"varint,2,opt,name=trial_id,json=trialId,proto3" json:"trial_id,omitempty"`
+	// Measured value to minimize, required unless error is set, or skipped or
+	// stop is true.
+	Value *float64 `protobuf:"fixed64,3,opt,name=value,proto3,oneof" json:"value,omitempty"`
+	// Reports a failed evaluation.
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// Reports an invalidated measurement, the trial doesn't count.
+	Skipped bool `protobuf:"varint,5,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	// Requests the study to stop.
+	Stop          bool `protobuf:"varint,6,opt,name=stop,proto3" json:"stop,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportResultRequest) Reset() {
+	*x = ReportResultRequest{}
+	mi := &file_ho_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportResultRequest) ProtoMessage() {}
+
+func (x *ReportResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportResultRequest.ProtoReflect.Descriptor instead.
+func (*ReportResultRequest) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReportResultRequest) GetStudyId() string {
+	if x != nil {
+		return x.StudyId
+	}
+	return ""
+}
+
+func (x *ReportResultRequest) GetTrialId() int64 {
+	if x != nil {
+		return x.TrialId
+	}
+	return 0
+}
+
+func (x *ReportResultRequest) GetValue() float64 {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return 0
+}
+
+func (x *ReportResultRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ReportResultRequest) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+func (x *ReportResultRequest) GetStop() bool {
+	if x != nil {
+		return x.Stop
+	}
+	return false
+}
+
+type ReportResultResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Best result so far, unset until a trial completes.
+	Best          *Best `protobuf:"bytes,1,opt,name=best,proto3" json:"best,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportResultResponse) Reset() {
+	*x = ReportResultResponse{}
+	mi := &file_ho_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportResultResponse) ProtoMessage() {}
+
+func (x *ReportResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportResultResponse.ProtoReflect.Descriptor instead.
+func (*ReportResultResponse) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReportResultResponse) GetBest() *Best {
+	if x != nil {
+		return x.Best
+	}
+	return nil
+}
+
+type GetBestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StudyId       string                 `protobuf:"bytes,1,opt,name=study_id,json=studyId,proto3" json:"study_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBestRequest) Reset() {
+	*x = GetBestRequest{}
+	mi := &file_ho_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBestRequest) ProtoMessage() {}
+
+func (x *GetBestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBestRequest.ProtoReflect.Descriptor instead.
+func (*GetBestRequest) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetBestRequest) GetStudyId() string {
+	if x != nil {
+		return x.StudyId
+	}
+	return ""
+}
+
+type GetBestResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Best result so far, unset until a trial completes.
+	Best *Best `protobuf:"bytes,1,opt,name=best,proto3" json:"best,omitempty"`
+	// Whether the study is done.
+	Done bool `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`
+	// Number of reported trials, skipped ones included.
+	ReportedTrials int32 `protobuf:"varint,3,opt,name=reported_trials,json=reportedTrials,proto3" json:"reported_trials,omitempty"`
+	// Number of suggestions waiting for a result.
+	Pending       int32 `protobuf:"varint,4,opt,name=pending,proto3" json:"pending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBestResponse) Reset() {
+	*x = GetBestResponse{}
+	mi := &file_ho_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBestResponse) ProtoMessage() {}
+
+func (x *GetBestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBestResponse.ProtoReflect.Descriptor instead.
+func (*GetBestResponse) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetBestResponse) GetBest() *Best {
+	if x != nil {
+		return x.Best
+	}
+	return nil
+}
+
+func (x *GetBestResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *GetBestResponse) GetReportedTrials() int32 {
+	if x != nil {
+		return x.ReportedTrials
+	}
+	return 0
+}
+
+func (x *GetBestResponse) GetPending() int32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+// Best is the best result of a study.
+type Best struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrialId       int64                  `protobuf:"varint,1,opt,name=trial_id,json=trialId,proto3" json:"trial_id,omitempty"`
+	Params        map[string]float64     `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Best) Reset() {
+	*x = Best{}
+	mi := &file_ho_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Best) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Best) ProtoMessage() {}
+
+func (x *Best) ProtoReflect() protoreflect.Message {
+	mi := &file_ho_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Best.ProtoReflect.Descriptor instead.
+func (*Best) Descriptor() ([]byte, []int) {
+	return file_ho_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Best) GetTrialId() int64 {
+	if x != nil {
+		return x.TrialId
+	}
+	return 0
+}
+
+func (x *Best) GetParams() map[string]float64 {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *Best) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+var File_ho_proto protoreflect.FileDescriptor
+
+const file_ho_proto_rawDesc = "" +
+	"\n" +
+	"\bho.proto\x12\x05ho.v1\"\x81\x01\n" +
+	"\tParameter\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x10\n" +
+	"\x03min\x18\x03 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x04 \x01(\x01R\x03max\x12\x14\n" +
+	"\x05scale\x18\x05 \x01(\tR\x05scale\x12\x12\n" +
+	"\x04step\x18\x06 \x01(\x01R\x04step\"V\n" +
+	"\x12CreateStudyRequest\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\x12(\n" +
+	"\x10lease_timeout_ms\x18\x02 \x01(\x03R\x0eleaseTimeoutMs\"b\n" +
+	"\x13CreateStudyResponse\x12\x19\n" +
+	"\bstudy_id\x18\x01 \x01(\tR\astudyId\x120\n" +
+	"\n" +
+	"parameters\x18\x02 \x03(\v2\x10.ho.v1.ParameterR\n" +
+	"parameters\"{\n" +
+	"\x18StreamSuggestionsRequest\x12\x19\n" +
+	"\bstudy_id\x18\x01 \x01(\tR\astudyId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12'\n" +
+	"\x0fmax_suggestions\x18\x03 \x01(\x05R\x0emaxSuggestions\"\x9f\x02\n" +
+	"\n" +
+	"Suggestion\x12\x19\n" +
+	"\bstudy_id\x18\x01 \x01(\tR\astudyId\x12\x19\n" +
+	"\btrial_id\x18\x02 \x01(\x03R\atrialId\x12\x14\n" +
+	"\x05phase\x18\x03 \x01(\tR\x05phase\x12\x1c\n" +
+	"\titeration\x18\x04 \x01(\x05R\titeration\x125\n" +
+	"\x06params\x18\x05 \x03(\v2\x1d.ho.v1.Suggestion.ParamsEntryR\x06params\x125\n" +
+	"\x17lease_expires_unix_nano\x18\x06 \x01(\x03R\x14leaseExpiresUnixNano\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xb4\x01\n" +
+	"\x13ReportResultRequest\x12\x19\n" +
+	"\bstudy_id\x18\x01 \x01(\tR\astudyId\x12\x19\n" +
+	"\btrial_id\x18\x02 \x01(\x03R\atrialId\x12\x19\n" +
+	"\x05value\x18\x03 \x01(\x01H\x00R\x05value\x88\x01\x01\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x18\n" +
+	"\askipped\x18\x05 \x01(\bR\askipped\x12\x12\n" +
+	"\x04stop\x18\x06 \x01(\bR\x04stopB\b\n" +
+	"\x06_value\"7\n" +
+	"\x14ReportResultResponse\x12\x1f\n" +
+	"\x04best\x18\x01 \x01(\v2\v.ho.v1.BestR\x04best\"+\n" +
+	"\x0eGetBestRequest\x12\x19\n" +
+	"\bstudy_id\x18\x01 \x01(\tR\astudyId\"\x89\x01\n" +
+	"\x0fGetBestResponse\x12\x1f\n" +
+	"\x04best\x18\x01 \x01(\v2\v.ho.v1.BestR\x04best\x12\x12\n" +
+	"\x04done\x18\x02 \x01(\bR\x04done\x12'\n" +
+	"\x0freported_trials\x18\x03 \x01(\x05R\x0ereportedTrials\x12\x18\n" +
+	"\apending\x18\x04 \x01(\x05R\apending\"\xa3\x01\n" +
+	"\x04Best\x12\x19\n" +
+	"\btrial_id\x18\x01 \x01(\x03R\atrialId\x12/\n" +
+	"\x06params\x18\x02 \x03(\v2\x17.ho.v1.Best.ParamsEntryR\x06params\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x012\xa2\x02\n" +
+	"\fStudyService\x12D\n" +
+	"\vCreateStudy\x12\x19.ho.v1.CreateStudyRequest\x1a\x1a.ho.v1.CreateStudyResponse\x12I\n" +
+	"\x11StreamSuggestions\x12\x1f.ho.v1.StreamSuggestionsRequest\x1a\x11.ho.v1.Suggestion0\x01\x12G\n" +
+	"\fReportResult\x12\x1a.ho.v1.ReportResultRequest\x1a\x1b.ho.v1.ReportResultResponse\x128\n" +
+	"\aGetBest\x12\x15.ho.v1.GetBestRequest\x1a\x16.ho.v1.GetBestResponseB.Z,github.com/thalesfsp/ho/grpcserver/hopb;hopbb\x06proto3"
+
+var (
+	file_ho_proto_rawDescOnce sync.Once
+	file_ho_proto_rawDescData []byte
+)
+
+func file_ho_proto_rawDescGZIP() []byte {
+	file_ho_proto_rawDescOnce.Do(func() {
+		file_ho_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ho_proto_rawDesc), len(file_ho_proto_rawDesc)))
+	})
+	return file_ho_proto_rawDescData
+}
+
+var file_ho_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_ho_proto_goTypes = []any{
+	(*Parameter)(nil),                // 0: ho.v1.Parameter
+	(*CreateStudyRequest)(nil),       // 1: ho.v1.CreateStudyRequest
+	(*CreateStudyResponse)(nil),      // 2: ho.v1.CreateStudyResponse
+	(*StreamSuggestionsRequest)(nil), // 3: ho.v1.StreamSuggestionsRequest
+	(*Suggestion)(nil),               // 4: ho.v1.Suggestion
+	(*ReportResultRequest)(nil),      // 5: ho.v1.ReportResultRequest
+	(*ReportResultResponse)(nil),     // 6: ho.v1.ReportResultResponse
+	(*GetBestRequest)(nil),           // 7: ho.v1.GetBestRequest
+	(*GetBestResponse)(nil),          // 8: ho.v1.GetBestResponse
+	(*Best)(nil),                     // 9: ho.v1.Best
+	nil,                              // 10: ho.v1.Suggestion.ParamsEntry
+	nil,                              // 11: ho.v1.Best.ParamsEntry
+}
+var file_ho_proto_depIdxs = []int32{
+	0,  // 0: ho.v1.CreateStudyResponse.parameters:type_name -> ho.v1.Parameter
+	10, // 1: ho.v1.Suggestion.params:type_name -> ho.v1.Suggestion.ParamsEntry
+	9,  // 2: ho.v1.ReportResultResponse.best:type_name -> ho.v1.Best
+	9,  // 3: ho.v1.GetBestResponse.best:type_name -> ho.v1.Best
+	11, // 4: ho.v1.Best.params:type_name -> ho.v1.Best.ParamsEntry
+	1,  // 5: ho.v1.StudyService.CreateStudy:input_type -> ho.v1.CreateStudyRequest
+	3,  // 6: ho.v1.StudyService.StreamSuggestions:input_type -> ho.v1.StreamSuggestionsRequest
+	5,  // 7: ho.v1.StudyService.ReportResult:input_type -> ho.v1.ReportResultRequest
+	7,  // 8: ho.v1.StudyService.GetBest:input_type -> ho.v1.GetBestRequest
+	2,  // 9: ho.v1.StudyService.CreateStudy:output_type -> ho.v1.CreateStudyResponse
+	4,  // 10: ho.v1.StudyService.StreamSuggestions:output_type -> ho.v1.Suggestion
+	6,  // 11: ho.v1.StudyService.ReportResult:output_type -> ho.v1.ReportResultResponse
+	8,  // 12: ho.v1.StudyService.GetBest:output_type -> ho.v1.GetBestResponse
+	9,  // [9:13] is the sub-list for method output_type
+	5,  // [5:9] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_ho_proto_init() }
+func file_ho_proto_init() {
+	if File_ho_proto != nil {
+		return
+	}
+	file_ho_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ho_proto_rawDesc), len(file_ho_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ho_proto_goTypes,
+		DependencyIndexes: file_ho_proto_depIdxs,
+		MessageInfos:      file_ho_proto_msgTypes,
+	}.Build()
+	File_ho_proto = out.File
+	file_ho_proto_goTypes = nil
+	file_ho_proto_depIdxs = nil
+}
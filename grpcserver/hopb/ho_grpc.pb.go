@@ -0,0 +1,259 @@
+// Protocol for distributed evaluation workers: workers in any language pull
+// parameter suggestions and push results back, while the server owns the
+// model. See the grpcserver Go package for the semantics.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: ho.proto
+
+package hopb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	StudyService_CreateStudy_FullMethodName       = "/ho.v1.StudyService/CreateStudy"
+	StudyService_StreamSuggestions_FullMethodName = "/ho.v1.StudyService/StreamSuggestions"
+	StudyService_ReportResult_FullMethodName      = "/ho.v1.StudyService/ReportResult"
+	StudyService_GetBest_FullMethodName           = "/ho.v1.StudyService/GetBest"
+)
+
+// StudyServiceClient is the client API for StudyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// StudyService drives ask/tell optimization studies.
+type StudyServiceClient interface {
+	// CreateStudy creates a study from a configuration document.
+	CreateStudy(ctx context.Context, in *CreateStudyRequest, opts ...grpc.CallOption) (*CreateStudyResponse, error)
+	// StreamSuggestions streams suggestions to a worker, one at a time: the
+	// next suggestion is sent once the previous one is reported, or its lease
+	// expires. The stream ends when the study is done.
+	StreamSuggestions(ctx context.Context, in *StreamSuggestionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Suggestion], error)
+	// ReportResult reports the result of a suggestion.
+	ReportResult(ctx context.Context, in *ReportResultRequest, opts ...grpc.CallOption) (*ReportResultResponse, error)
+	// GetBest returns the best result of a study so far.
+	GetBest(ctx context.Context, in *GetBestRequest, opts ...grpc.CallOption) (*GetBestResponse, error)
+}
+
+type studyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStudyServiceClient(cc grpc.ClientConnInterface) StudyServiceClient {
+	return &studyServiceClient{cc}
+}
+
+func (c *studyServiceClient) CreateStudy(ctx context.Context, in *CreateStudyRequest, opts ...grpc.CallOption) (*CreateStudyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateStudyResponse)
+	err := c.cc.Invoke(ctx, StudyService_CreateStudy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceClient) StreamSuggestions(ctx context.Context, in *StreamSuggestionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Suggestion], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StudyService_ServiceDesc.Streams[0], StudyService_StreamSuggestions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamSuggestionsRequest, Suggestion]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StudyService_StreamSuggestionsClient = grpc.ServerStreamingClient[Suggestion]
+
+func (c *studyServiceClient) ReportResult(ctx context.Context, in *ReportResultRequest, opts ...grpc.CallOption) (*ReportResultResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportResultResponse)
+	err := c.cc.Invoke(ctx, StudyService_ReportResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceClient) GetBest(ctx context.Context, in *GetBestRequest, opts ...grpc.CallOption) (*GetBestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBestResponse)
+	err := c.cc.Invoke(ctx, StudyService_GetBest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StudyServiceServer is the server API for StudyService service.
+// All implementations must embed UnimplementedStudyServiceServer
+// for forward compatibility.
+//
+// StudyService drives ask/tell optimization studies.
+type StudyServiceServer interface {
+	// CreateStudy creates a study from a configuration document.
+	CreateStudy(context.Context, *CreateStudyRequest) (*CreateStudyResponse, error)
+	// StreamSuggestions streams suggestions to a worker, one at a time: the
+	// next suggestion is sent once the previous one is reported, or its lease
+	// expires. The stream ends when the study is done.
+	StreamSuggestions(*StreamSuggestionsRequest, grpc.ServerStreamingServer[Suggestion]) error
+	// ReportResult reports the result of a suggestion.
+	ReportResult(context.Context, *ReportResultRequest) (*ReportResultResponse, error)
+	// GetBest returns the best result of a study so far.
+	GetBest(context.Context, *GetBestRequest) (*GetBestResponse, error)
+	mustEmbedUnimplementedStudyServiceServer()
+}
+
+// UnimplementedStudyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStudyServiceServer struct{}
+
+func (UnimplementedStudyServiceServer) CreateStudy(context.Context, *CreateStudyRequest) (*CreateStudyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateStudy not implemented")
+}
+func (UnimplementedStudyServiceServer) StreamSuggestions(*StreamSuggestionsRequest, grpc.ServerStreamingServer[Suggestion]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSuggestions not implemented")
+}
+func (UnimplementedStudyServiceServer) ReportResult(context.Context, *ReportResultRequest) (*ReportResultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportResult not implemented")
+}
+func (UnimplementedStudyServiceServer) GetBest(context.Context, *GetBestRequest) (*GetBestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBest not implemented")
+}
+func (UnimplementedStudyServiceServer) mustEmbedUnimplementedStudyServiceServer() {}
+func (UnimplementedStudyServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeStudyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StudyServiceServer will
+// result in compilation errors.
+type UnsafeStudyServiceServer interface {
+	mustEmbedUnimplementedStudyServiceServer()
+}
+
+func RegisterStudyServiceServer(s grpc.ServiceRegistrar, srv StudyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedStudyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&StudyService_ServiceDesc, srv)
+}
+
+func _StudyService_CreateStudy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStudyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).CreateStudy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_CreateStudy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).CreateStudy(ctx, req.(*CreateStudyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyService_StreamSuggestions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamSuggestionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StudyServiceServer).StreamSuggestions(m, &grpc.GenericServerStream[StreamSuggestionsRequest, Suggestion]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StudyService_StreamSuggestionsServer = grpc.ServerStreamingServer[Suggestion]
+
+func _StudyService_ReportResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).ReportResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_ReportResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).ReportResult(ctx, req.(*ReportResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyService_GetBest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).GetBest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_GetBest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).GetBest(ctx, req.(*GetBestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StudyService_ServiceDesc is the grpc.ServiceDesc for StudyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StudyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ho.v1.StudyService",
+	HandlerType: (*StudyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateStudy",
+			Handler:    _StudyService_CreateStudy_Handler,
+		},
+		{
+			MethodName: "ReportResult",
+			Handler:    _StudyService_ReportResult_Handler,
+		},
+		{
+			MethodName: "GetBest",
+			Handler:    _StudyService_GetBest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSuggestions",
+			Handler:       _StudyService_StreamSuggestions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ho.proto",
+}
@@ -0,0 +1,434 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/ho"
+	"github.com/thalesfsp/ho/grpcserver/hopb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// DefaultLeaseTimeout is the lease duration of suggestions, unless set by
+// Options or the study.
+const DefaultLeaseTimeout = 5 * time.Minute
+
+// Options configures a Server.
+type Options struct {
+	// LeaseTimeout is the default lease duration of suggestions,
+	// DefaultLeaseTimeout if zero. Studies can override it on creation.
+	LeaseTimeout time.Duration
+}
+
+// Server implements hopb.StudyServiceServer, see the package documentation.
+type Server struct {
+	hopb.UnimplementedStudyServiceServer
+
+	// leaseTimeout is the default lease duration of suggestions.
+	leaseTimeout time.Duration
+
+	// mu protects studies.
+	mu sync.RWMutex
+
+	// studies holds the studies, by ID.
+	studies map[string]*study
+}
+
+// lease is a suggestion handed out to a worker.
+type lease struct {
+	// suggestion is the suggestion handed out.
+	suggestion ho.Suggestion[float64]
+
+	// worker identifies the worker holding the lease.
+	worker string
+
+	// expires is when the suggestion is re-issued if not reported.
+	expires time.Time
+}
+
+// study is an optimization driven through the API.
+type study struct {
+	// id identifies the study.
+	id string
+
+	// space is the search space.
+	space ho.SearchSpace
+
+	// optimizer is the ask/tell handle.
+	optimizer *ho.Optimizer[float64]
+
+	// leaseTimeout is the lease duration of suggestions.
+	leaseTimeout time.Duration
+
+	// mu serializes the study operations, keeping leases consistent with the
+	// optimizer.
+	mu sync.Mutex
+
+	// leases holds the leased suggestions, by trial ID.
+	leases map[int]*lease
+
+	// expired holds the suggestions whose lease expired, to re-issue, by
+	// trial ID.
+	expired []ho.Suggestion[float64]
+
+	// changed is closed, and replaced, whenever a result is reported.
+	changed chan struct{}
+}
+
+//////
+// Methods.
+//////
+
+// CreateStudy implements hopb.StudyServiceServer.
+func (s *Server) CreateStudy(_ context.Context, req *hopb.CreateStudyRequest) (*hopb.CreateStudyResponse, error) {
+	if req.GetLeaseTimeoutMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "lease_timeout_ms must be non-negative")
+	}
+
+	config, space, err := ho.LoadConfig(strings.NewReader(req.GetConfig()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	optimizer, err := ho.NewOptimizer(config, ho.Ranges[float64](space)...)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	st := &study{
+		id:           newID(),
+		space:        space,
+		optimizer:    optimizer,
+		leaseTimeout: s.leaseTimeout,
+		leases:       make(map[int]*lease),
+		changed:      make(chan struct{}),
+	}
+
+	if req.GetLeaseTimeoutMs() > 0 {
+		st.leaseTimeout = time.Duration(req.GetLeaseTimeoutMs()) * time.Millisecond
+	}
+
+	s.mu.Lock()
+	s.studies[st.id] = st
+	s.mu.Unlock()
+
+	parameters := make([]*hopb.Parameter, len(space.Parameters))
+
+	for i, p := range space.Parameters {
+		parameters[i] = &hopb.Parameter{
+			Name:  p.Name,
+			Type:  string(p.Type),
+			Min:   p.Min,
+			Max:   p.Max,
+			Scale: p.Scale,
+			Step:  p.Step,
+		}
+	}
+
+	return &hopb.CreateStudyResponse{StudyId: st.id, Parameters: parameters}, nil
+}
+
+// StreamSuggestions implements hopb.StudyServiceServer.
+func (s *Server) StreamSuggestions(
+	req *hopb.StreamSuggestionsRequest,
+	stream hopb.StudyService_StreamSuggestionsServer,
+) error {
+	st, err := s.study(req.GetStudyId())
+	if err != nil {
+		return err
+	}
+
+	for sent := int32(0); req.GetMaxSuggestions() == 0 || sent < req.GetMaxSuggestions(); sent++ {
+		l, err := st.lease(req.GetWorkerId())
+		if err != nil {
+			return err
+		}
+
+		// The study is done.
+		if l == nil {
+			return nil
+		}
+
+		if err := stream.Send(st.suggestion(l)); err != nil {
+			return err
+		}
+
+		// The lease is kept if the worker goes away: it may still report,
+		// otherwise the lease expires.
+		if err := st.wait(stream.Context(), l); err != nil {
+			return status.FromContextError(err).Err()
+		}
+	}
+
+	return nil
+}
+
+// ReportResult implements hopb.StudyServiceServer.
+func (s *Server) ReportResult(_ context.Context, req *hopb.ReportResultRequest) (*hopb.ReportResultResponse, error) {
+	st, err := s.study(req.GetStudyId())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		value   float64
+		evalErr error
+	)
+
+	switch {
+	case req.GetStop():
+		evalErr = ho.ErrStopOptimization
+
+		if req.GetError() != "" {
+			evalErr = fmt.Errorf("%s: %w", req.GetError(), ho.ErrStopOptimization)
+		}
+	case req.GetSkipped():
+		evalErr = ho.ErrSkipTrial
+	case req.GetError() != "":
+		evalErr = errors.New(req.GetError())
+	case req.Value == nil:
+		return nil, status.Error(codes.InvalidArgument, "value is required unless error, skipped or stop is set")
+	default:
+		value = req.GetValue()
+	}
+
+	if err := st.report(int(req.GetTrialId()), value, evalErr); err != nil {
+		return nil, err
+	}
+
+	return &hopb.ReportResultResponse{Best: st.best()}, nil
+}
+
+// GetBest implements hopb.StudyServiceServer.
+func (s *Server) GetBest(_ context.Context, req *hopb.GetBestRequest) (*hopb.GetBestResponse, error) {
+	st, err := s.study(req.GetStudyId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &hopb.GetBestResponse{
+		Best:           st.best(),
+		Done:           st.optimizer.Done(),
+		ReportedTrials: int32(len(st.optimizer.Result().Trials)),
+		Pending:        int32(len(st.optimizer.Pending())),
+	}, nil
+}
+
+// study returns the study with the given ID.
+func (s *Server) study(id string) (*study, error) {
+	s.mu.RLock()
+	st, ok := s.studies[id]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown study %q", id)
+	}
+
+	return st, nil
+}
+
+// lease leases the next suggestion to a worker: an expired suggestion if
+// any, a new one otherwise.
+//
+// Returns:
+// - *lease: The lease, nil if the study is done
+// - error: If no suggestion can be made.
+func (st *study) lease(worker string) (*lease, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.optimizer.Done() {
+		return nil, nil
+	}
+
+	st.expire(time.Now())
+
+	var suggestion ho.Suggestion[float64]
+
+	if len(st.expired) > 0 {
+		suggestion = st.expired[0]
+
+		st.expired = st.expired[1:]
+	} else {
+		var err error
+
+		suggestion, err = st.optimizer.Ask()
+		if err != nil {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+
+	l := &lease{
+		suggestion: suggestion,
+		worker:     worker,
+		expires:    time.Now().Add(st.leaseTimeout),
+	}
+
+	st.leases[suggestion.TrialID] = l
+
+	return l, nil
+}
+
+// expire moves the suggestions whose lease expired to the re-issue queue.
+// Callers must hold mu.
+func (st *study) expire(now time.Time) {
+	for id, l := range st.leases {
+		if now.After(l.expires) {
+			delete(st.leases, id)
+
+			st.expired = append(st.expired, l.suggestion)
+		}
+	}
+
+	sort.Slice(st.expired, func(i, j int) bool { return st.expired[i].TrialID < st.expired[j].TrialID })
+}
+
+// wait blocks until the lease is over, i.e. its suggestion was reported, or
+// the lease expired.
+//
+// Returns:
+// - error: The context error, if ctx is done first.
+func (st *study) wait(ctx context.Context, l *lease) error {
+	timer := time.NewTimer(time.Until(l.expires))
+	defer timer.Stop()
+
+	for {
+		st.mu.Lock()
+		// The lease is gone once reported, or re-issued.
+		over := st.leases[l.suggestion.TrialID] != l
+		changed := st.changed
+		st.mu.Unlock()
+
+		if over {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// report tells the result of a suggestion, whether its lease is current,
+// expired or re-issued.
+func (st *study) report(trialID int, value float64, evalErr error) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, err := st.optimizer.Tell(trialID, value, evalErr); err != nil {
+		if errors.Is(err, ho.ErrUnknownTrial) || errors.Is(err, ho.ErrLeaseExpired) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	delete(st.leases, trialID)
+
+	for i, suggestion := range st.expired {
+		if suggestion.TrialID == trialID {
+			st.expired = append(st.expired[:i], st.expired[i+1:]...)
+
+			break
+		}
+	}
+
+	close(st.changed)
+
+	st.changed = make(chan struct{})
+
+	return nil
+}
+
+// params maps parameter names to values.
+func (st *study) params(params []float64) map[string]float64 {
+	named := make(map[string]float64, len(params))
+
+	for i, p := range st.space.Parameters {
+		named[p.Name] = params[i]
+	}
+
+	return named
+}
+
+// suggestion converts a lease to its API form.
+func (st *study) suggestion(l *lease) *hopb.Suggestion {
+	return &hopb.Suggestion{
+		StudyId:              st.id,
+		TrialId:              int64(l.suggestion.TrialID),
+		Phase:                l.suggestion.Phase,
+		Iteration:            int32(l.suggestion.Iteration),
+		Params:               st.params(l.suggestion.Params),
+		LeaseExpiresUnixNano: l.expires.UnixNano(),
+	}
+}
+
+// best returns the best completed trial, nil if none.
+func (st *study) best() *hopb.Best {
+	var best *hopb.Best
+
+	for _, trial := range st.optimizer.Result().Trials {
+		if trial.Status == ho.TrialCompleted && (best == nil || trial.ExecutionTime < best.GetValue()) {
+			best = &hopb.Best{
+				TrialId: int64(trial.TrialID),
+				Params:  st.params(trial.Params),
+				Value:   trial.ExecutionTime,
+			}
+		}
+	}
+
+	return best
+}
+
+//////
+// Helpers.
+//////
+
+// newID returns a random study ID.
+func newID() string {
+	b := make([]byte, 8)
+
+	// crypto/rand.Read never returns an error.
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+//////
+// Factory.
+//////
+
+// New creates a Server.
+//
+// Parameters:
+// - opts: Server options
+//
+// Returns:
+// - *Server: The server, to register with hopb.RegisterStudyServiceServer.
+func New(opts Options) *Server {
+	leaseTimeout := opts.LeaseTimeout
+	if leaseTimeout <= 0 {
+		leaseTimeout = DefaultLeaseTimeout
+	}
+
+	return &Server{
+		leaseTimeout: leaseTimeout,
+		studies:      make(map[string]*study),
+	}
+}
@@ -0,0 +1,212 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho"
+	"github.com/thalesfsp/ho/grpcserver/hopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const studyDocument = `{
+	"iterations": 8,
+	"initialSamples": 4,
+	"numCandidates": 10,
+	"parameters": [
+		{"name": "x", "type": "float", "min": -5, "max": 5},
+		{"name": "workers", "type": "int", "min": 1, "max": 8}
+	]
+}`
+
+// quadratic is the function workers minimize.
+func quadratic(_ context.Context, params map[string]float64) (float64, error) {
+	return math.Pow(params["x"]-1, 2) + params["workers"], nil
+}
+
+// serve starts a server over an in-memory connection, and returns a client.
+func serve(t *testing.T) (*Client, hopb.StudyServiceClient) {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+
+	server := grpc.NewServer()
+
+	hopb.RegisterStudyServiceServer(server, New(Options{}))
+
+	go func() { _ = server.Serve(listener) }()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return NewClient(conn), hopb.NewStudyServiceClient(conn)
+}
+
+func TestWorkers(t *testing.T) {
+	client, _ := serve(t)
+
+	ctx := context.Background()
+
+	studyID, err := client.CreateStudy(ctx, studyDocument, time.Minute)
+	assert.NoError(t, err)
+
+	var (
+		wg        sync.WaitGroup
+		evaluated atomic.Int32
+	)
+
+	for i := range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := client.Work(ctx, studyID, fmt.Sprintf("worker-%d", i), func(ctx context.Context, params map[string]float64) (float64, error) {
+				// Inject failures.
+				if evaluated.Add(1)%5 == 0 {
+					return 0, errors.New("crashed")
+				}
+
+				return quadratic(ctx, params)
+			})
+
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	best, err := client.Best(ctx, studyID)
+	assert.NoError(t, err)
+
+	assert.True(t, best.GetDone())
+	assert.GreaterOrEqual(t, best.GetReportedTrials(), int32(12))
+	assert.Equal(t, int32(0), best.GetPending())
+	assert.NotNil(t, best.GetBest())
+
+	value, _ := quadratic(ctx, best.GetBest().GetParams())
+	assert.InDelta(t, value, best.GetBest().GetValue(), 1e-9)
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	client, raw := serve(t)
+
+	ctx := context.Background()
+
+	studyID, err := client.CreateStudy(ctx, studyDocument, 50*time.Millisecond)
+	assert.NoError(t, err)
+
+	// A worker takes a suggestion, then crashes.
+	crashedCtx, crash := context.WithCancel(ctx)
+
+	stream, err := raw.StreamSuggestions(crashedCtx, &hopb.StreamSuggestionsRequest{StudyId: studyID, WorkerId: "crashed"})
+	assert.NoError(t, err)
+
+	lost, err := stream.Recv()
+	assert.NoError(t, err)
+
+	crash()
+
+	// Another worker gets a fresh suggestion while the lease holds, the lost
+	// one once it expired.
+	stream, err = raw.StreamSuggestions(ctx, &hopb.StreamSuggestionsRequest{StudyId: studyID, WorkerId: "alive", MaxSuggestions: 2})
+	assert.NoError(t, err)
+
+	fresh, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.NotEqual(t, lost.GetTrialId(), fresh.GetTrialId())
+
+	reissued, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, lost.GetTrialId(), reissued.GetTrialId())
+	assert.Equal(t, lost.GetParams(), reissued.GetParams())
+	assert.Greater(t, reissued.GetLeaseExpiresUnixNano(), lost.GetLeaseExpiresUnixNano())
+
+	value := 1.0
+
+	_, err = raw.ReportResult(ctx, &hopb.ReportResultRequest{StudyId: studyID, TrialId: reissued.GetTrialId(), Value: &value})
+	assert.NoError(t, err)
+
+	// A late result of the crashed worker is rejected.
+	_, err = raw.ReportResult(ctx, &hopb.ReportResultRequest{StudyId: studyID, TrialId: lost.GetTrialId(), Value: &value})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// The stream ends after MaxSuggestions, the other lease still holds.
+	_, err = stream.Recv()
+	assert.Error(t, err)
+
+	best, err := client.Best(ctx, studyID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), best.GetReportedTrials())
+	assert.Equal(t, int32(1), best.GetPending())
+	assert.Equal(t, lost.GetTrialId(), best.GetBest().GetTrialId())
+}
+
+func TestStop(t *testing.T) {
+	client, _ := serve(t)
+
+	ctx := context.Background()
+
+	studyID, err := client.CreateStudy(ctx, studyDocument, 0)
+	assert.NoError(t, err)
+
+	err = client.Work(ctx, studyID, "worker", func(context.Context, map[string]float64) (float64, error) {
+		return 0, fmt.Errorf("out of budget: %w", ho.ErrStopOptimization)
+	})
+	assert.NoError(t, err)
+
+	best, err := client.Best(ctx, studyID)
+	assert.NoError(t, err)
+	assert.True(t, best.GetDone())
+	assert.Equal(t, int32(1), best.GetReportedTrials())
+	assert.Nil(t, best.GetBest())
+}
+
+func TestErrors(t *testing.T) {
+	client, raw := serve(t)
+
+	ctx := context.Background()
+
+	_, err := client.CreateStudy(ctx, `{"parameters": []}`, 0)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Best(ctx, "missing")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	err = client.Work(ctx, "missing", "worker", quadratic)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	studyID, err := client.CreateStudy(ctx, studyDocument, 0)
+	assert.NoError(t, err)
+
+	// A value is required.
+	_, err = raw.ReportResult(ctx, &hopb.ReportResultRequest{StudyId: studyID, TrialId: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Unknown trial.
+	_, err = raw.ReportResult(ctx, &hopb.ReportResultRequest{StudyId: studyID, TrialId: 1, Skipped: true})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
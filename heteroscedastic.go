@@ -0,0 +1,291 @@
+package ho
+
+import (
+	"math"
+	"sync"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultNoiseMinPoints is the default
+	// HeteroscedasticOptions.MinPoints.
+	defaultNoiseMinPoints = 10
+
+	// defaultNoiseIterations is the default
+	// HeteroscedasticOptions.Iterations.
+	defaultNoiseIterations = 3
+
+	// logChiSquareBias is minus the mean of the log of a squared standard
+	// normal, γ + log 2: the log of a squared residual underestimates the log
+	// of its variance by as much on average.
+	logChiSquareBias = 1.2704
+
+	// logNoiseWeight is the weight of a log squared residual in the noise
+	// model, see WeightedUpdater: the variance of the log of a squared
+	// normal is about 5, so single residuals are smoothed, not interpolated.
+	logNoiseWeight = 0.25
+)
+
+// HeteroscedasticOptions configures a HeteroscedasticGaussianProcess.
+type HeteroscedasticOptions struct {
+	// MinPoints is the number of distinct observed points from which the
+	// noise is modeled, the model being a plain Gaussian process until then.
+	// If 0, 10 is used.
+	MinPoints int
+
+	// Iterations is the number of times the noise and the model are fitted
+	// in turn on each Update, each fit of the noise using the residuals of
+	// the previous model. If 0, 3 is used.
+	Iterations int
+}
+
+// HeteroscedasticGaussianProcess is a SurrogateModel for objectives whose
+// noise varies across the space, e.g. erratic timings with small batch
+// sizes and stable ones with large batches. A single noise level either
+// over-smooths the stable regions or over-trusts the noisy ones: this model
+// fits a second Gaussian process to the log of the noise variance, and uses
+// its prediction as the noise of each observation in the posterior.
+//
+// Important notes:
+// - The noise is fitted to the log squared leave-one-out residuals of the
+// model, or to the log sample variance of points observed more than once,
+// alternately with the model, see HeteroscedasticOptions.Iterations
+// - Predict returns the variance of an observation: the posterior variance
+// plus the predicted noise, so acquisition functions see wider intervals in
+// noisy regions. Noise returns the predicted noise alone
+// - Update is O(n^3) once the noise is modeled, for each iteration, where
+// the Gaussian process is O(n^2): the model is opt-in for that reason.
+//
+// Thread safety:
+// - All methods are safe for concurrent use.
+type HeteroscedasticGaussianProcess struct {
+	// mu protects the fields below.
+	mu sync.RWMutex
+
+	// options configures the model, defaults applied.
+	options HeteroscedasticOptions
+
+	// gp holds the observations, the kernel, the noise of each point, and
+	// the Cholesky factor of the kernel matrix.
+	gp *gaussianProcess
+
+	// noise models the log of the noise variance, in the units of the
+	// observations squared. Nil until MinPoints points are observed.
+	noise *gaussianProcess
+
+	// counts, means and squares hold the number of observations of each
+	// point of gp, their mean, and the sum of their squared deviations from
+	// it, for sample variances.
+	counts  []int
+	means   []float64
+	squares []float64
+}
+
+//////
+// Methods.
+//////
+
+// Update implements SurrogateModel. It refits the noise once MinPoints
+// points are observed.
+func (h *HeteroscedasticGaussianProcess) Update(x []float64, y float64) error {
+	return h.UpdateWeighted(x, y, 1)
+}
+
+// UpdateWeighted implements WeightedUpdater: the weight divides the modeled
+// noise of the observation too.
+func (h *HeteroscedasticGaussianProcess) UpdateWeighted(x []float64, y, weight float64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Only this model updates gp, so reading it unlocked is safe.
+	i := h.gp.duplicateOf(x)
+
+	if err := h.gp.UpdateWeighted(x, y, weight); err != nil {
+		return err
+	}
+
+	if i < 0 {
+		i = len(h.counts)
+
+		h.counts = append(h.counts, 0)
+		h.means = append(h.means, 0)
+		h.squares = append(h.squares, 0)
+	}
+
+	// Welford's algorithm.
+	h.counts[i]++
+
+	delta := y - h.means[i]
+
+	h.means[i] += delta / float64(h.counts[i])
+	h.squares[i] += delta * (y - h.means[i])
+
+	if len(h.counts) >= h.options.MinPoints {
+		h.fit()
+	}
+
+	return nil
+}
+
+// Predict implements SurrogateModel, see PredictBatch.
+func (h *HeteroscedasticGaussianProcess) Predict(x []float64) (mean, variance float64) {
+	means, variances := h.PredictBatch([][]float64{x})
+
+	return means[0], variances[0]
+}
+
+// PredictBatch implements BatchPredictor. Variances are those of
+// observations, the predicted noise included.
+func (h *HeteroscedasticGaussianProcess) PredictBatch(points [][]float64) (means, variances []float64) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	means, variances = h.gp.PredictBatch(points)
+
+	for i, noise := range h.noiseAt(points) {
+		variances[i] += noise
+	}
+
+	return means, variances
+}
+
+// Noise returns the predicted noise variance at x, in the units of the
+// observations squared, 0 until the noise is modeled.
+func (h *HeteroscedasticGaussianProcess) Noise(x []float64) float64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.noiseAt([][]float64{x})[0]
+}
+
+// Points implements SurrogateModel.
+func (h *HeteroscedasticGaussianProcess) Points() [][]float64 {
+	return h.gp.Points()
+}
+
+// Len implements SurrogateModel.
+func (h *HeteroscedasticGaussianProcess) Len() int {
+	return h.gp.Len()
+}
+
+// Clone implements SurrogateModel.
+func (h *HeteroscedasticGaussianProcess) Clone() SurrogateModel {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	clone := &HeteroscedasticGaussianProcess{
+		options: h.options,
+		gp:      h.gp.snapshot(),
+		counts:  append([]int(nil), h.counts...),
+		means:   append([]float64(nil), h.means...),
+		squares: append([]float64(nil), h.squares...),
+	}
+
+	if h.noise != nil {
+		clone.noise = h.noise.snapshot()
+	}
+
+	return clone
+}
+
+// noiseAt returns the predicted noise variances at the points, 0 until the
+// noise is modeled. The caller must hold the lock.
+func (h *HeteroscedasticGaussianProcess) noiseAt(points [][]float64) []float64 {
+	if h.noise == nil {
+		return make([]float64, len(points))
+	}
+
+	noise, _ := h.noise.PredictBatch(points)
+
+	for i, logNoise := range noise {
+		noise[i] = math.Exp(logNoise)
+	}
+
+	return noise
+}
+
+// fit fits the noise and the model in turn, see Iterations. The caller must
+// hold the write lock.
+func (h *HeteroscedasticGaussianProcess) fit() {
+	h.gp.mu.Lock()
+	defer h.gp.mu.Unlock()
+
+	// Variances below the jitter are indistinguishable from it.
+	floor := jitters[0] * priorScale(h.gp.Y)
+
+	for iteration := 0; iteration < h.options.Iterations; iteration++ {
+		noise := newGaussianProcess()
+		noise.sigma = h.gp.sigma
+
+		for i, residual := range h.gp.looResiduals() {
+			target, weight := math.Log(math.Max(residual*residual, floor))+logChiSquareBias, logNoiseWeight
+
+			// Repeats give the variance directly.
+			if h.counts[i] > 1 {
+				target = math.Log(math.Max(h.squares[i]/float64(h.counts[i]-1), floor))
+				weight *= float64(h.counts[i] - 1)
+			}
+
+			// Targets are finite, and points those of gp.
+			_ = noise.update(h.gp.X[i], target, weight)
+		}
+
+		logNoise, _ := noise.PredictBatch(h.gp.X)
+
+		scale := priorScale(h.gp.Y)
+
+		previous := h.gp.noise
+
+		h.gp.noise = make([]float64, len(logNoise))
+
+		for i, v := range logNoise {
+			h.gp.noise[i] = math.Exp(v) / scale
+		}
+
+		// Noise the kernel matrix doesn't factorize with is dropped, the
+		// factorization being left untouched.
+		if err := h.gp.factorize(); err != nil {
+			h.gp.noise = previous
+
+			return
+		}
+
+		h.noise = noise
+	}
+}
+
+//////
+// Factory.
+//////
+
+// NewHeteroscedasticGaussianProcess creates a HeteroscedasticGaussianProcess.
+//
+// Parameters:
+// - options: Configures the model, zero values select the defaults
+//
+// Returns:
+// - *HeteroscedasticGaussianProcess: The model, without observations.
+//
+// Usage example:
+//
+//	config := DefaultConfig()
+//	config.Surrogate = func() SurrogateModel {
+//	    return NewHeteroscedasticGaussianProcess(HeteroscedasticOptions{})
+//	}
+func NewHeteroscedasticGaussianProcess(options HeteroscedasticOptions) *HeteroscedasticGaussianProcess {
+	if options.MinPoints <= 0 {
+		options.MinPoints = defaultNoiseMinPoints
+	}
+
+	if options.Iterations <= 0 {
+		options.Iterations = defaultNoiseIterations
+	}
+
+	return &HeteroscedasticGaussianProcess{
+		options: options,
+		gp:      newGaussianProcess(),
+	}
+}
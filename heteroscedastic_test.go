@@ -0,0 +1,113 @@
+package ho
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// regionNoise returns a smooth function of x in [0, 10], with noise of
+// standard deviation 0.05 below 5, and 1 above.
+func regionNoise(rng *rand.Rand, x float64) float64 {
+	stdDev := 0.05
+	if x > 5 {
+		stdDev = 1
+	}
+
+	return math.Sin(x) + stdDev*rng.NormFloat64()
+}
+
+func TestHeteroscedasticGaussianProcess(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	model := NewHeteroscedasticGaussianProcess(HeteroscedasticOptions{})
+	plain := newGaussianProcess()
+
+	for i := 0; i < 80; i++ {
+		x := []float64{10 * rng.Float64()}
+		y := regionNoise(rng, x[0])
+
+		assert.NoError(t, model.Update(x, y))
+		assert.NoError(t, plain.Update(x, y))
+	}
+
+	stable, noisy := []float64{2.5}, []float64{7.5}
+
+	// The noise is learned per region.
+	assert.InDelta(t, 1, math.Sqrt(model.Noise(noisy)), 0.5)
+	assert.Less(t, math.Sqrt(model.Noise(stable)), 0.2)
+
+	// Predictive intervals are wider in the noisy region, where a plain
+	// Gaussian process interpolates the noise.
+	_, stableVariance := model.Predict(stable)
+	_, noisyVariance := model.Predict(noisy)
+
+	assert.Greater(t, noisyVariance, 10*stableVariance)
+
+	// The mean follows the function, not the noise.
+	for _, x := range []float64{2.5, 7.5} {
+		mean, _ := model.Predict([]float64{x})
+
+		assert.InDelta(t, math.Sin(x), mean, 0.5, x)
+	}
+
+	means, variances := model.PredictBatch([][]float64{stable, noisy})
+
+	for i, x := range [][]float64{stable, noisy} {
+		mean, variance := model.Predict(x)
+
+		assert.Equal(t, mean, means[i])
+		assert.Equal(t, variance, variances[i])
+	}
+
+	// Clones are independent.
+	clone := model.Clone()
+
+	assert.NoError(t, clone.Update([]float64{2.5}, 100))
+	assert.Equal(t, 81, clone.Len())
+	assert.Equal(t, 80, model.Len())
+	assert.Equal(t, noisyVariance, func() float64 { _, v := model.Predict(noisy); return v }())
+}
+
+func TestHeteroscedasticRepeats(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	model := NewHeteroscedasticGaussianProcess(HeteroscedasticOptions{MinPoints: 4})
+
+	// Points are measured 5 times each, the sample variances give the noise.
+	for x := 0.0; x <= 10; x++ {
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, model.Update([]float64{x}, regionNoise(rng, x)))
+		}
+	}
+
+	assert.Len(t, model.Points(), 11)
+	assert.Greater(t, model.Noise([]float64{8}), 10*model.Noise([]float64{2}))
+
+	// Below MinPoints, the noise isn't modeled.
+	early := NewHeteroscedasticGaussianProcess(HeteroscedasticOptions{})
+
+	assert.NoError(t, early.Update([]float64{1}, 1))
+	assert.Zero(t, early.Noise([]float64{1}))
+}
+
+func TestOptimizeHeteroscedastic(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	config := fastConfig()
+	config.Seed = 3
+	config.InitialSamples = 10
+	config.Iterations = 10
+	config.Surrogate = func() SurrogateModel {
+		return NewHeteroscedasticGaussianProcess(HeteroscedasticOptions{})
+	}
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return regionNoise(rng, params[0]), nil
+	}, ParameterRange[float64]{Min: 0, Max: 10})
+
+	assert.NoError(t, result.Err)
+	assert.Len(t, result.Trials, 20)
+}
@@ -1,9 +1,9 @@
 package ho
 
 import (
-	"math"
+	"context"
+	"fmt"
 	"math/rand"
-	"sync"
 	"time"
 
 	"golang.org/x/exp/constraints"
@@ -18,11 +18,10 @@ func DefaultConfig() OptimizationConfig {
 	return OptimizationConfig{
 		Iterations:      50,
 		InitialSamples:  10,
-		NumCandidates:   50,
-		AcquisitionFunc: UCB,
+		NumCandidates:   0, // Derived from the number of dimensions.
+		AcquisitionFunc: LowerConfidenceBound,
 		AcqParams: AcquisitionParams{
-			BestSoFar:   math.MaxFloat64,
-			Beta:        2.0,
+			Beta:        defaultBeta,
 			RandomState: rand.New(rand.NewSource(time.Now().UnixNano())),
 			Xi:          0.01,
 		},
@@ -30,12 +29,75 @@ func DefaultConfig() OptimizationConfig {
 	}
 }
 
+// SetAcquisition sets the acquisition function along with its direction.
+//
+// Parameters:
+// - acquisition: The acquisition function, see Acquisitions
+//
+// Usage example:
+//
+//	config := DefaultConfig()
+//
+//	acquisition, _ := LookupAcquisition("ExpectedImprovementMin")
+//	config.SetAcquisition(acquisition)
+func (c *OptimizationConfig) SetAcquisition(acquisition Acquisition) {
+	c.AcquisitionFunc = acquisition.Func
+	c.AcquisitionDirection = acquisition.Direction
+}
+
+// Validate checks that an acquisition function is set, and that AcqParams
+// holds what it needs: each registered acquisition function declares its
+// checks (see Acquisition.Validate), which fill in safe defaults for unset
+// parameters. Runs validate their configuration before starting.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig, naming the acquisition function and
+// the parameter, if the configuration is invalid, nil otherwise.
+//
+// Usage example:
+//
+//	config := DefaultConfig()
+//	config.AcquisitionFunc = ThompsonSampling
+//	config.AcqParams.RandomState = nil
+//
+//	err := config.Validate()
+//	// invalid configuration: acquisition "ThompsonSampling": AcqParams.RandomState is nil, ...
+//
+// Important notes:
+// - Built-ins need: Beta > 0 for the confidence bounds (2 if unset), Delta
+// in (0, 1) for GPUCB (0.1 if unset), Xi >= 0 for the improvement-based
+// ones, and RandomState for ThompsonSampling
+// - Parameters of AcquisitionFuncEx, and of unregistered acquisition
+// functions, aren't checked.
+func (c *OptimizationConfig) Validate() error {
+	if c.AcquisitionFunc == nil && c.AcquisitionFuncEx == nil {
+		return fmt.Errorf("%w: no acquisition function, see SetAcquisition", ErrInvalidConfig)
+	}
+
+	switch c.AcquisitionDirection {
+	case "", MinimizeAcquisition, MaximizeAcquisition:
+	default:
+		return fmt.Errorf("%w: AcquisitionDirection: unknown direction %q", ErrInvalidConfig, c.AcquisitionDirection)
+	}
+
+	acquisition, ok := acquisitionOf(*c)
+	if !ok || acquisition.Validate == nil {
+		return nil
+	}
+
+	if err := acquisition.Validate(&c.AcqParams); err != nil {
+		return fmt.Errorf("%w: acquisition %q: %w", ErrInvalidConfig, acquisition.Name, err)
+	}
+
+	return nil
+}
+
 // OptimizeHyperparameters uses Bayesian optimization to find the optimal hyperparameters
 // for your benchmark function. It combines Gaussian Process regression with acquisition
 // functions to efficiently search the parameter space.
 //
 // Type Parameter:
-//   - T: The numeric type for parameters (int64 or float64)
+//   - T: The numeric type for parameters (any integer or floating-point type)
 //
 // Parameters:
 // - config: OptimizationConfig controlling the optimization process
@@ -110,219 +172,407 @@ func DefaultConfig() OptimizationConfig {
 // - Total runtime = InitialSamples + Iterations evaluations
 // - Each iteration evaluates exactly one point
 // - Memory usage scales with number of evaluations
-// - Consider reducing NumCandidates if iterations are too slow.
+// - Consider reducing NumCandidates, derived from the number of dimensions
+// by default, if iterations are too slow.
 func OptimizeHyperparameters[T constraints.Integer | constraints.Float](
 	config OptimizationConfig,
 	benchmarkFunc BenchmarkFunc[T],
 	hypers ...ParameterRange[T],
 ) []T {
-	// Initialize thread-safe random number generator for generating parameter
-	// values. Using current time as seed ensures different random sequences
-	// across runs.
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-
-	var rngMu sync.Mutex
-
-	// safeRandomParams generates a set of random parameters within the specified ranges
-	// in a thread-safe manner. This is used both for initial sampling and generating
-	// candidates during optimization.
-	//
-	// Parameters:
-	// - hypers: Slice of ParameterRange defining valid ranges for each parameter
-	//
-	// Returns:
-	// - []T: Slice of random values, one for each parameter range
-	safeRandomParams := func(hypers []ParameterRange[T]) []T {
-		rngMu.Lock()
-		defer rngMu.Unlock()
-
-		params := make([]T, len(hypers))
-
-		for i, hyper := range hypers {
-			switch any(hyper.Min).(type) {
-			case int, int32, int64:
-				// For integer types, generate random integer in range
-				min := int64(hyper.Min)
-
-				max := int64(hyper.Max)
-
-				params[i] = T(min + rng.Int63n(max-min+1))
-			case float32, float64:
-				// For float types, generate random float in range
-				min := float64(hyper.Min)
-
-				max := float64(hyper.Max)
-
-				params[i] = T(min + rng.Float64()*(max-min))
-			}
-		}
-
-		return params
-	}
-
-	// Helper function to convert parameters to float64 for Gaussian Process
-	paramsToFloat64s := func(params []T) []float64 {
-		floats := make([]float64, len(params))
-		for i, v := range params {
-			floats[i] = float64(v)
-		}
-
-		return floats
-	}
-
-	// Initialize the Gaussian Process model that will be used to predict
-	// performance at untested points.
-	gp := newGaussianProcess()
-
-	// bestParams tracks the parameter combination that produced the best result.
-	bestParams := make([]T, len(hypers))
-
-	// bestTime tracks the best execution time seen so far (lower is better).
-	bestTime := math.MaxFloat64
-
-	// bestMu protects access to bestParams and bestTime.
-	var bestMu sync.Mutex
-
-	// Helper function to send progress updates.
-	sendProgress := func(phase string, iteration, total int, currentParams []T, execTime float64) {
-		if config.ProgressChan != nil {
-			bestMu.Lock()
-
-			// Convert current and best params to []int for backward compatibility
-			currentInts := make([]int, len(currentParams))
-
-			bestInts := make([]int, len(bestParams))
-
-			for i, v := range currentParams {
-				currentInts[i] = int(v)
-			}
-
-			for i, v := range bestParams {
-				bestInts[i] = int(v)
-			}
-
-			update := ProgressUpdate{
-				Phase:             phase,
-				CurrentIteration:  iteration,
-				TotalIterations:   total,
-				CurrentParams:     currentInts,
-				CurrentBestParams: bestInts,
-				CurrentBestTime:   bestTime,
-				LastExecutionTime: execTime,
-			}
+	return Optimize(config, benchmarkFunc, hypers...).BestParams
+}
 
-			bestMu.Unlock()
+// Optimize works exactly like OptimizeHyperparameters but returns the full
+// outcome of the run, including every trial performed.
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process
+// - benchmarkFunc: The function whose parameters you want to optimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Result[T]: The outcome of the run
+//
+// Usage example:
+//
+//	result := Optimize(DefaultConfig(), benchmark, ranges...)
+//
+//	fmt.Println("Best params:", result.BestParams)
+//	fmt.Println("Best time:", time.Duration(result.BestTime))
+//
+//	for _, trial := range result.Trials {
+//	    fmt.Println(trial.Phase, trial.Iteration, trial.Status, trial.Params)
+//	}
+func Optimize[T constraints.Integer | constraints.Float](
+	config OptimizationConfig,
+	benchmarkFunc BenchmarkFunc[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	return newOptimizer(context.Background(), config, fromBenchmarkFunc(benchmarkFunc), hypers...).run()
+}
 
-			select {
-			case config.ProgressChan <- update:
-			default:
-				// Skip update if channel is full.
-			}
-		}
-	}
+// OptimizeObjective works exactly like Optimize but minimizes the value
+// returned by the objective function, instead of the execution time of a
+// benchmark function. Result.BestTime and Trial.ExecutionTime then hold
+// objective values; Trial.Duration still holds the measured wall time.
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process
+// - objectiveFunc: The function whose value you want to minimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Result[T]: The outcome of the run
+//
+// Usage example:
+//
+//	result := OptimizeObjective(
+//	    DefaultConfig(),
+//	    func(params ...float64) (float64, error) {
+//	        return math.Pow(params[0]-3, 2), nil
+//	    },
+//	    ParameterRange[float64]{Min: -10, Max: 10},
+//	)
+func OptimizeObjective[T constraints.Integer | constraints.Float](
+	config OptimizationConfig,
+	objectiveFunc ObjectiveFunc[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	o := newOptimizer[T](context.Background(), config, nil, hypers...)
 
-	// updateBest safely updates the best parameters and time if a new best is
-	// found.
-	//
-	// Parameters:
-	// - params: Parameter combination to potentially update as best
-	// - executionTime: Execution time achieved with these parameters
-	updateBest := func(params []T, executionTime float64) {
-		bestMu.Lock()
-		defer bestMu.Unlock()
-
-		if executionTime < bestTime {
-			bestTime = executionTime
-
-			copy(bestParams, params)
-		}
+	o.objectiveFunc = func(_ context.Context, _ TrialInfo, params ...T) (float64, error) {
+		return objectiveFunc(params...)
 	}
 
-	// Phase 1: Initial random sampling.
-	//
-	// Build initial model by sampling random points in the parameter space.
-	// This helps establish a baseline understanding of the function behavior.
-	for i := 0; i < config.InitialSamples; i++ {
-		// Generate and evaluate random parameters.
-		params := safeRandomParams(hypers)
-
-		// Measure execution time directly with our generic benchmark function
-		startTime := time.Now()
-		err := benchmarkFunc(params...)
-		executionTime := float64(time.Since(startTime).Nanoseconds())
-
-		// Apply penalty if the benchmark failed
-		if err != nil {
-			executionTime = math.MaxFloat64/2 + executionTime
-		}
-
-		// Convert parameters to float64 for the Gaussian Process
-		floatParams := paramsToFloat64s(params)
+	return o.run()
+}
 
-		// Update our model with the new observation
-		gp.Update(floatParams, executionTime)
+// OptimizeWithInfo works exactly like Optimize but accepts a benchmark function
+// that also receives metadata about the trial being evaluated.
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process
+// - benchmarkFunc: The function whose parameters you want to optimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Result[T]: The outcome of the run
+//
+// Usage example:
+//
+//	result := OptimizeWithInfo(
+//	    DefaultConfig(),
+//	    func(info TrialInfo, params ...int) error {
+//	        log.Printf("trial=%d phase=%s", info.TrialID, info.Phase)
+//
+//	        return runWorkload(params[0])
+//	    },
+//	    ranges...,
+//	)
+func OptimizeWithInfo[T constraints.Integer | constraints.Float](
+	config OptimizationConfig,
+	benchmarkFunc BenchmarkFuncWithInfo[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	return newOptimizer(context.Background(), config, fromBenchmarkFuncWithInfo(benchmarkFunc), hypers...).run()
+}
 
-		// Update best parameters if this is better
-		updateBest(params, executionTime)
+// OptimizeObjectiveWithInfo works exactly like OptimizeObjective but accepts
+// an objective function that also receives metadata about the trial being
+// evaluated, e.g. the seed to draw its randomness from.
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process
+// - objectiveFunc: The function whose value you want to minimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Result[T]: The outcome of the run
+//
+// Usage example:
+//
+//	config := DefaultConfig()
+//	config.SeedsPerTrial = 5
+//
+//	result := OptimizeObjectiveWithInfo(
+//	    config,
+//	    func(info TrialInfo, params ...float64) (float64, error) {
+//	        return train(rand.New(rand.NewSource(info.Seed)), params[0])
+//	    },
+//	    ranges...,
+//	)
+func OptimizeObjectiveWithInfo[T constraints.Integer | constraints.Float](
+	config OptimizationConfig,
+	objectiveFunc ObjectiveFuncWithInfo[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	o := newOptimizer[T](context.Background(), config, nil, hypers...)
 
-		sendProgress("InitialSampling", i+1, config.InitialSamples, params, executionTime)
+	o.objectiveFunc = func(_ context.Context, info TrialInfo, params ...T) (float64, error) {
+		return objectiveFunc(info, params...)
 	}
 
-	// Phase 2: Bayesian optimization loop.
-	//
-	// Iteratively select and evaluate new points based on model predictions.
-	for i := 0; i < config.Iterations; i++ {
-		var nextParams []T
-
-		bestAcquisition := math.MaxFloat64
+	return o.run()
+}
 
-		// Update acquisition function with current best time
-		config.AcqParams.BestSoFar = bestTime
+// OptimizeWithContext works exactly like Optimize but accepts a benchmark
+// function that receives a context.
+//
+// Parameters:
+// - ctx: Run context, each trial context is derived from it
+// - config: OptimizationConfig controlling the optimization process
+// - benchmarkFunc: The function whose parameters you want to optimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Result[T]: The outcome of the run
+//
+// Usage example:
+//
+//	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer cancel()
+//
+//	config := DefaultConfig()
+//	config.TrialTimeout = 30 * time.Second
+//
+//	result := OptimizeWithContext(
+//	    ctx,
+//	    config,
+//	    func(ctx context.Context, params ...int) error {
+//	        return runWorkload(ctx, params[0])
+//	    },
+//	    ranges...,
+//	)
+//
+// Important notes:
+// - Canceling ctx stops the run immediately: the running trial's context is
+// canceled, and no further trial is started
+// - The result then has termination reason TerminationContextCanceled and the
+// context cause as Err.
+func OptimizeWithContext[T constraints.Integer | constraints.Float](
+	ctx context.Context,
+	config OptimizationConfig,
+	benchmarkFunc BenchmarkFuncCtx[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	return newOptimizer(ctx, config, fromBenchmarkFuncCtx(benchmarkFunc), hypers...).run()
+}
 
-		// Generate and evaluate random candidates
-		// Choose the most promising one according to the acquisition function
-		for j := 0; j < config.NumCandidates; j++ {
-			// Generate random candidate parameters
-			candidateParams := safeRandomParams(hypers)
+// OptimizeObjectiveWithContext works exactly like OptimizeObjective but
+// accepts a run context and an objective function that receives the trial
+// context, see OptimizeWithContext.
+//
+// Parameters:
+// - ctx: Run context, each trial context is derived from it
+// - config: OptimizationConfig controlling the optimization process
+// - objectiveFunc: The function whose value you want to minimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Result[T]: The outcome of the run.
+func OptimizeObjectiveWithContext[T constraints.Integer | constraints.Float](
+	ctx context.Context,
+	config OptimizationConfig,
+	objectiveFunc ObjectiveFuncCtx[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	o := newOptimizer[T](ctx, config, nil, hypers...)
 
-			floatCandidateParams := paramsToFloat64s(candidateParams)
+	o.objectiveFunc = func(ctx context.Context, _ TrialInfo, params ...T) (float64, error) {
+		return objectiveFunc(ctx, params...)
+	}
 
-			// Get model's prediction for these parameters
-			mean, variance := gp.Predict(floatCandidateParams)
+	return o.run()
+}
 
-			// Evaluate how promising this point is
-			acquisition := config.AcquisitionFunc(mean, variance, config.AcqParams)
+// Start works exactly like OptimizeWithContext but runs the optimization in
+// the background, and returns a handle to pause, resume or stop it while it
+// runs.
+//
+// Parameters:
+// - ctx: Run context, each trial context is derived from it
+// - config: OptimizationConfig controlling the optimization process
+// - benchmarkFunc: The function whose parameters you want to optimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *RunHandle[T]: Controls the run, see RunHandle
+//
+// Usage example:
+//
+//	run := Start(ctx, config, benchmark, ranges...)
+//
+//	// Free the system under test during the nightly backup.
+//	run.Pause()
+//	backup()
+//	run.Resume()
+//
+//	result := run.Wait()
+//
+// Important notes:
+// - Time spent paused doesn't count against OptimizationConfig.TimeBudget,
+// unless OptimizationConfig.CountPausedTime is set.
+func Start[T constraints.Integer | constraints.Float](
+	ctx context.Context,
+	config OptimizationConfig,
+	benchmarkFunc BenchmarkFuncCtx[T],
+	hypers ...ParameterRange[T],
+) *RunHandle[T] {
+	return startRun(newOptimizer(ctx, config, fromBenchmarkFuncCtx(benchmarkFunc), hypers...))
+}
 
-			// Update if this is the most promising candidate so far
-			if acquisition < bestAcquisition {
-				bestAcquisition = acquisition
+// StartObjective works exactly like OptimizeObjectiveWithContext but runs the
+// optimization in the background, see Start.
+//
+// Parameters:
+// - ctx: Run context, each trial context is derived from it
+// - config: OptimizationConfig controlling the optimization process
+// - objectiveFunc: The function whose value you want to minimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *RunHandle[T]: Controls the run, see RunHandle.
+func StartObjective[T constraints.Integer | constraints.Float](
+	ctx context.Context,
+	config OptimizationConfig,
+	objectiveFunc ObjectiveFuncCtx[T],
+	hypers ...ParameterRange[T],
+) *RunHandle[T] {
+	o := newOptimizer[T](ctx, config, nil, hypers...)
 
-				nextParams = candidateParams
-			}
-		}
+	o.objectiveFunc = func(ctx context.Context, _ TrialInfo, params ...T) (float64, error) {
+		return objectiveFunc(ctx, params...)
+	}
 
-		// Evaluate the most promising candidate.
-		startTime := time.Now()
+	return startRun(o)
+}
 
-		err := benchmarkFunc(nextParams...)
+// ResumeFromCheckpoint resumes an interrupted run from its last checkpoint,
+// see OptimizationConfig.Checkpoint, and continues it as if it had never been
+// interrupted: the trials of the checkpoint are restored, along with the
+// model, the best result and the random number generator, and only the
+// remaining budget is evaluated.
+//
+// Parameters:
+// - path: The checkpoint file
+// - config: OptimizationConfig of the interrupted run
+// - benchmarkFunc: The function whose parameters you want to optimize
+// - hypers: The ParameterRange values of the interrupted run
+//
+// Returns:
+// - *Result[T]: The outcome of the run, trials before the interruption
+// included. If the checkpoint can't be read, or has a different search
+// space, Result.Err wraps ErrInvalidConfig
+//
+// Usage example:
+//
+//	config.Checkpoint = &Checkpoint{Path: "run.checkpoint.json"}
+//
+//	result := ResumeFromCheckpoint("run.checkpoint.json", config, benchmark, ranges...)
+//
+// Important notes:
+// - Checkpoints keep writing to config.Checkpoint, if set
+// - With the same seed and a deterministic benchmark, the resumed run
+// evaluates the same parameters as an uninterrupted one, provided
+// evaluations are serial. Trials canceled with the run, and skipped trials
+// whose replacements weren't drawn yet, aren't evaluated again
+// - AcqParams.RandomState isn't part of the checkpoint, so runs using
+// Thompson sampling don't resume exactly.
+func ResumeFromCheckpoint[T constraints.Integer | constraints.Float](
+	path string,
+	config OptimizationConfig,
+	benchmarkFunc BenchmarkFunc[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	return newOptimizer(context.Background(), config, fromBenchmarkFunc(benchmarkFunc), hypers...).resume(path)
+}
 
-		executionTime := float64(time.Since(startTime).Nanoseconds())
+// ResumeObjectiveFromCheckpoint works exactly like ResumeFromCheckpoint but
+// minimizes the value returned by the objective function, see
+// OptimizeObjective.
+//
+// Parameters:
+// - path: The checkpoint file
+// - config: OptimizationConfig of the interrupted run
+// - objectiveFunc: The function whose value you want to minimize
+// - hypers: The ParameterRange values of the interrupted run
+//
+// Returns:
+// - *Result[T]: The outcome of the run, trials before the interruption
+// included.
+func ResumeObjectiveFromCheckpoint[T constraints.Integer | constraints.Float](
+	path string,
+	config OptimizationConfig,
+	objectiveFunc ObjectiveFunc[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	o := newOptimizer[T](context.Background(), config, nil, hypers...)
 
-		// Apply penalty if the benchmark failed.
-		if err != nil {
-			executionTime = math.MaxFloat64/2 + executionTime
-		}
+	o.objectiveFunc = func(_ context.Context, _ TrialInfo, params ...T) (float64, error) {
+		return objectiveFunc(params...)
+	}
 
-		// Update model with the new observation.
-		floatNextParams := paramsToFloat64s(nextParams)
+	return o.resume(path)
+}
 
-		gp.Update(floatNextParams, executionTime)
+// ResumeFromStorage resumes a stored study, see OptimizationConfig.Storage,
+// e.g. after a crash: its trials are restored, along with the model and the
+// best result, only the remaining budget is evaluated, and new trials are
+// appended to the study.
+//
+// Parameters:
+// - studyID: The study, see Result.StudyID
+// - config: OptimizationConfig of the study, Storage included
+// - benchmarkFunc: The function whose parameters you want to optimize
+// - hypers: The ParameterRange values of the study
+//
+// Returns:
+// - *Result[T]: The outcome of the run, trials of the study included. If
+// Storage is unset, the study can't be loaded, or has a different search
+// space, Result.Err wraps ErrInvalidConfig
+//
+// Usage example:
+//
+//	config.Storage = storage
+//
+//	result := ResumeFromStorage(studyID, config, benchmark, ranges...)
+//
+// Important notes:
+// - Stored studies don't hold the state of the random number generator,
+// use ResumeFromCheckpoint to resume exactly where the run left off
+// - WarmStart observations aren't stored, they're fed to the model again.
+func ResumeFromStorage[T constraints.Integer | constraints.Float](
+	studyID string,
+	config OptimizationConfig,
+	benchmarkFunc BenchmarkFunc[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	return newOptimizer(context.Background(), config, fromBenchmarkFunc(benchmarkFunc), hypers...).resumeStudy(studyID)
+}
 
-		// Update best parameters if this is better.
-		updateBest(nextParams, executionTime)
+// ResumeObjectiveFromStorage works exactly like ResumeFromStorage but
+// minimizes the value returned by the objective function, see
+// OptimizeObjective.
+//
+// Parameters:
+// - studyID: The study, see Result.StudyID
+// - config: OptimizationConfig of the study, Storage included
+// - objectiveFunc: The function whose value you want to minimize
+// - hypers: The ParameterRange values of the study
+//
+// Returns:
+// - *Result[T]: The outcome of the run, trials of the study included.
+func ResumeObjectiveFromStorage[T constraints.Integer | constraints.Float](
+	studyID string,
+	config OptimizationConfig,
+	objectiveFunc ObjectiveFunc[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	o := newOptimizer[T](context.Background(), config, nil, hypers...)
 
-		sendProgress("Optimization", i+1, config.Iterations, nextParams, executionTime)
+	o.objectiveFunc = func(_ context.Context, _ TrialInfo, params ...T) (float64, error) {
+		return objectiveFunc(params...)
 	}
 
-	return bestParams
+	return o.resumeStudy(studyID)
 }
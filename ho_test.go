@@ -1,12 +1,14 @@
 package ho
 
 import (
+	"context"
 	"math/rand"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho/benchfuncs"
 )
 
 // Sample function to be benchmarked.
@@ -32,7 +34,7 @@ func testFuncInt(bufferSize int, multipler int) error {
 }
 
 func TestOptimizeBufferSize(t *testing.T) {
-	// Using default configuration (UCB)
+	// Using default configuration (LCB)
 	config := DefaultConfig()
 
 	// Your benchmark function
@@ -131,7 +133,7 @@ func testFuncFloat(bufferSize float32, multiplier float32) error {
 }
 
 func TestOptimizeBufferSizeFloat(t *testing.T) {
-	// Using default configuration (UCB)
+	// Using default configuration (LCB)
 	config := DefaultConfig()
 
 	// Your benchmark function with type conversion
@@ -206,3 +208,51 @@ func TestOptimizeBufferSizeChannelFloat(t *testing.T) {
 	// Ensure optimal parameters are returned.
 	assert.Len(t, bestParams, 2)
 }
+
+func TestOptimizeObjective(t *testing.T) {
+	f := benchfuncs.Branin()
+
+	counter := benchfuncs.NewCounter(f.Objective)
+
+	config := DefaultConfig()
+
+	result := OptimizeObjective(config, counter.Objective, rangesOf(f)...)
+
+	// The optimizer minimizes the returned value, not the execution time.
+	assert.Equal(t, config.InitialSamples+config.Iterations, counter.Count())
+	assert.GreaterOrEqual(t, result.BestTime, f.Optimum)
+	assert.Less(t, result.BestTime, 10.0)
+
+	value, err := f.Objective(result.BestParams...)
+	assert.NoError(t, err)
+	assert.InDelta(t, value, result.BestTime, 1e-9)
+}
+
+func TestOptimizeObjectiveWithContext(t *testing.T) {
+	config := fastConfig()
+	config.TrialTimeout = 20 * time.Millisecond
+
+	result := OptimizeObjectiveWithContext(context.Background(), config, func(ctx context.Context, params ...int) (float64, error) {
+		// Odd values hang until the trial times out.
+		if params[0]%2 == 1 {
+			<-ctx.Done()
+
+			return 0, ctx.Err()
+		}
+
+		return float64(params[0]), nil
+	}, ParameterRange[int]{Min: 0, Max: 9})
+
+	assert.NoError(t, result.Err)
+
+	for _, trial := range result.Trials {
+		if trial.Params[0]%2 == 1 {
+			assert.Equal(t, TrialCanceled, trial.Status)
+		} else {
+			assert.Equal(t, TrialCompleted, trial.Status)
+			assert.Equal(t, float64(trial.Params[0]), trial.ExecutionTime)
+		}
+	}
+
+	assert.Equal(t, 0, result.BestParams[0]%2)
+}
@@ -0,0 +1,102 @@
+package ho
+
+import (
+	"fmt"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// HookScope determines what the Setup and Teardown hooks wrap, see
+// OptimizationConfig.HookScope.
+type HookScope string
+
+const (
+	// HooksPerTrial runs the hooks once per trial, around all of its
+	// measurements, see OptimizationConfig.SurpriseRemeasure.
+	HooksPerTrial HookScope = "PerTrial"
+
+	// HooksPerMeasurement runs the hooks around each benchmark invocation,
+	// re-measurements included.
+	HooksPerMeasurement HookScope = "PerMeasurement"
+)
+
+//////
+// Methods.
+//////
+
+// validate checks the scope.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if the scope is unknown, nil otherwise.
+func (s HookScope) validate() error {
+	switch s {
+	case "", HooksPerTrial, HooksPerMeasurement:
+		return nil
+	default:
+		return fmt.Errorf("%w: HookScope: unknown scope %q", ErrInvalidConfig, s)
+	}
+}
+
+// setUp runs the Setup hook if the hooks wrap scope, see
+// OptimizationConfig.Setup.
+//
+// Parameters:
+// - scope: What is about to run
+// - info: Metadata of the trial
+// - params: Parameters of the trial
+//
+// Returns:
+// - func(): Runs the Teardown hook if the hooks wrap scope, a no-op
+// otherwise. It must be called even if Setup failed
+// - error: Wrapping the error of Setup, nil otherwise.
+func (o *optimizer[T]) setUp(scope HookScope, info TrialInfo, params []T) (func(), error) {
+	current := o.config.HookScope
+	if current == "" {
+		current = HooksPerTrial
+	}
+
+	teardown := func() {}
+
+	if current != scope {
+		return teardown, nil
+	}
+
+	if o.config.Teardown != nil {
+		teardown = func() { o.config.Teardown(info) }
+	}
+
+	if o.config.Setup == nil {
+		return teardown, nil
+	}
+
+	params, _ = o.benchmarkParams(params)
+
+	if err := o.config.Setup(info, paramsToFloat64s(params)...); err != nil {
+		return teardown, fmt.Errorf("setup: %w", err)
+	}
+
+	return teardown, nil
+}
+
+// measureHooked measures parameters outside of trials, e.g. a reference
+// configuration, between the Setup and Teardown hooks as a trial would be.
+//
+// Parameters:
+// - info: Metadata of the measurement
+// - params: Parameters to measure
+//
+// Returns:
+// - measurement: The outcome of the invocation, or the Setup error.
+func (o *optimizer[T]) measureHooked(info TrialInfo, params []T) measurement {
+	teardown, err := o.setUp(HooksPerTrial, info, params)
+	defer teardown()
+
+	if err != nil {
+		return measurement{err: err, startedAt: time.Now()}
+	}
+
+	return o.measure(info, params)
+}
@@ -0,0 +1,252 @@
+package ho
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hookEvents records hook and benchmark invocations, by trial ID.
+type hookEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (e *hookEvents) add(format string, args ...any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.events = append(e.events, fmt.Sprintf(format, args...))
+}
+
+func (e *hookEvents) count(prefix string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var n int
+
+	for _, event := range e.events {
+		if len(event) >= len(prefix) && event[:len(prefix)] == prefix {
+			n++
+		}
+	}
+
+	return n
+}
+
+// hookedConfig returns a sequential fastConfig whose hooks record their
+// invocations in events.
+func hookedConfig(events *hookEvents) OptimizationConfig {
+	config := fastConfig()
+	config.MaxConcurrentEvaluations = 1
+
+	config.Setup = func(info TrialInfo, params ...float64) error {
+		events.add("setup %d %v", info.TrialID, params)
+
+		return nil
+	}
+
+	config.Teardown = func(info TrialInfo) {
+		events.add("teardown %d", info.TrialID)
+	}
+
+	return config
+}
+
+func TestHooks(t *testing.T) {
+	ranges := []ParameterRange[int]{{Min: 0, Max: 100}}
+
+	t.Run("ordering", func(t *testing.T) {
+		events := &hookEvents{}
+
+		result := OptimizeWithInfo(hookedConfig(events), func(info TrialInfo, params ...int) error {
+			events.add("benchmark %d", info.TrialID)
+
+			return nil
+		}, ranges...)
+
+		var expected []string
+
+		for _, trial := range result.Trials {
+			expected = append(expected,
+				fmt.Sprintf("setup %d [%d]", trial.TrialID, trial.Params[0]),
+				fmt.Sprintf("benchmark %d", trial.TrialID),
+				fmt.Sprintf("teardown %d", trial.TrialID),
+			)
+		}
+
+		assert.Equal(t, expected, events.events)
+	})
+
+	t.Run("excluded from durations", func(t *testing.T) {
+		config := fastConfig()
+
+		config.Setup = func(TrialInfo, ...float64) error {
+			time.Sleep(50 * time.Millisecond)
+
+			return nil
+		}
+
+		config.Teardown = func(TrialInfo) {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		result := Optimize(config, func(...int) error { return nil }, ranges...)
+
+		for _, trial := range result.Trials {
+			assert.Less(t, trial.Duration, 25*time.Millisecond)
+			assert.Less(t, trial.ExecutionTime, float64(25*time.Millisecond))
+		}
+	})
+
+	t.Run("teardown on failure", func(t *testing.T) {
+		events := &hookEvents{}
+
+		config := hookedConfig(events)
+		config.TrialTimeout = 5 * time.Millisecond
+
+		result := OptimizeWithContext(context.Background(), config, func(ctx context.Context, params ...int) error {
+			switch {
+			case params[0]%3 == 0:
+				return errors.New("boom")
+			case params[0]%3 == 1:
+				<-ctx.Done()
+
+				return ctx.Err()
+			default:
+				return nil
+			}
+		}, ranges...)
+
+		var failed int
+
+		for _, trial := range result.Trials {
+			if trial.Status != TrialCompleted {
+				failed++
+			}
+		}
+
+		assert.Positive(t, failed)
+		assert.Equal(t, len(result.Trials), events.count("setup"))
+		assert.Equal(t, len(result.Trials), events.count("teardown"))
+	})
+
+	t.Run("teardown on panic", func(t *testing.T) {
+		events := &hookEvents{}
+
+		o := newOptimizer[float64](context.Background(), hookedConfig(events), nil, ParameterRange[float64]{Min: 0, Max: 10})
+
+		o.objectiveFunc = func(context.Context, TrialInfo, ...float64) (float64, error) {
+			panic("boom")
+		}
+
+		assert.Panics(t, func() {
+			o.measureTrial(TrialInfo{TrialID: 1}, []float64{1}, false)
+		})
+
+		assert.Equal(t, []string{"setup 1 [1]", "teardown 1"}, events.events)
+	})
+
+	t.Run("setup failure", func(t *testing.T) {
+		events := &hookEvents{}
+
+		config := hookedConfig(events)
+
+		setupErr := errors.New("no connection")
+
+		config.Setup = func(info TrialInfo, _ ...float64) error {
+			events.add("setup %d", info.TrialID)
+
+			switch info.TrialID {
+			case 2:
+				return setupErr
+			case 3:
+				return ErrSkipTrial
+			default:
+				return nil
+			}
+		}
+
+		result := OptimizeWithInfo(config, func(info TrialInfo, params ...int) error {
+			events.add("benchmark %d", info.TrialID)
+
+			return nil
+		}, ranges...)
+
+		assert.Equal(t, TrialFailed, result.Trials[1].Status)
+		assert.ErrorIs(t, result.Trials[1].Err, setupErr)
+		assert.Equal(t, TrialSkipped, result.Trials[2].Status)
+
+		assert.Equal(t, []string{
+			"setup 1", "benchmark 1", "teardown 1",
+			"setup 2", "teardown 2",
+			"setup 3", "teardown 3",
+		}, events.events[:7])
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		for _, test := range []struct {
+			scope HookScope
+			extra int
+		}{
+			{scope: "", extra: 0},
+			{scope: HooksPerTrial, extra: 0},
+			{scope: HooksPerMeasurement, extra: 2},
+		} {
+			events := &hookEvents{}
+
+			config := hookedConfig(events)
+			config.Seed = 1
+			config.HookScope = test.scope
+			config.SurpriseRemeasure = &SurpriseRemeasure{Remeasurements: 2}
+
+			result := OptimizeObjective(config, glitchingObjective(nil, 0), ParameterRange[float64]{Min: 0, Max: 10})
+
+			assert.True(t, result.Trials[config.InitialSamples].Surprising)
+			assert.Equal(t, len(result.Trials)+test.extra, events.count("setup"), test.scope)
+			assert.Equal(t, len(result.Trials)+test.extra, events.count("teardown"), test.scope)
+		}
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		hypers := make([]ParameterRange[float64], 30)
+
+		for i := range hypers {
+			hypers[i] = ParameterRange[float64]{Min: -1, Max: 1}
+		}
+
+		var seen [][]float64
+
+		config := fastConfig()
+		config.MaxConcurrentEvaluations = 1
+
+		// Setup receives the parameters, not the latent point.
+		config.Setup = func(_ TrialInfo, params ...float64) error {
+			seen = append(seen, params)
+
+			return nil
+		}
+
+		_, result := OptimizeObjectiveEmbedded(config, RandomEmbedding{Dimensions: 4}, activeSubspace, hypers...)
+
+		assert.Len(t, seen, len(result.Trials))
+
+		for i, params := range seen {
+			assert.Equal(t, result.Embedding.Params(result.Trials[i].Params), params)
+		}
+	})
+
+	t.Run("unknown scope", func(t *testing.T) {
+		config := fastConfig()
+		config.HookScope = "PerRun"
+
+		result := Optimize(config, func(...int) error { return nil }, ranges...)
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+}
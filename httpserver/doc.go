@@ -0,0 +1,31 @@
+// Package httpserver exposes ask/tell optimization (see ho.Optimizer) over
+// HTTP, so evaluation farms orchestrated by non-Go systems can drive studies
+// remotely:
+//
+//	POST /studies              Create a study, the body is a configuration
+//	                           document (see ho.LoadConfig), as JSON
+//	POST /studies/{id}/ask     Get a trial ID and parameters to evaluate
+//	POST /studies/{id}/tell    Report the result of a trial
+//	GET  /studies/{id}         Get the study state: best, history, model stats
+//
+// Usage example:
+//
+//	server := httpserver.New(httpserver.Options{})
+//
+//	log.Fatal(http.ListenAndServe(":8080", server))
+//
+// A typical worker loop, in any language:
+//
+//	curl -X POST localhost:8080/studies -d @study.json
+//	# {"id": "3f9a...", "parameters": [...]}
+//
+//	curl -X POST localhost:8080/studies/3f9a.../ask
+//	# {"trialId": 1, "phase": "Initial Sampling", "iteration": 1, "params": {"workers": 12}}
+//
+//	curl -X POST localhost:8080/studies/3f9a.../tell -d '{"trialId": 1, "value": 0.42}'
+//
+// Concurrent asks are diversified with the constant liar strategy, see
+// ho.Optimizer. Studies live in memory; configure a ho.Storage to record
+// them as they're told, and call Server.Load on startup to serve them again
+// after a restart.
+package httpserver
@@ -0,0 +1,625 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/ho"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// maxBodySize caps the size of request bodies.
+const maxBodySize = 1 << 20
+
+// ConfigTag is the tag holding the configuration document of the studies
+// the server stores, see Options.Storage.
+const ConfigTag = "httpserver.config"
+
+// Options configures a Server.
+type Options struct {
+	// Storage, if set, records each study and its trials as they're told,
+	// see ho.OptimizationConfig.Storage, and Server.Load to serve them again
+	// after a restart. Study IDs are those of the storage.
+	Storage ho.Storage
+}
+
+// Server serves the HTTP ask/tell API, see the package documentation.
+type Server struct {
+	// mux routes requests.
+	mux *http.ServeMux
+
+	// storage records studies, may be nil.
+	storage ho.Storage
+
+	// mu protects studies.
+	mu sync.RWMutex
+
+	// studies holds the studies, by ID.
+	studies map[string]*study
+}
+
+// study is an optimization driven through the API.
+type study struct {
+	// id identifies the study.
+	id string
+
+	// space is the search space.
+	space ho.SearchSpace
+
+	// optimizer is the ask/tell handle, it's safe for concurrent use.
+	optimizer *ho.Optimizer[float64]
+
+	// createdAt is when the study was created.
+	createdAt time.Time
+
+	// config is the configuration document the study was created with.
+	config string
+}
+
+// StudyState is the state of a study, as returned by GET /studies/{id}.
+type StudyState struct {
+	// ID identifies the study.
+	ID string `json:"id"`
+
+	// CreatedAt is when the study was created.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Config is the configuration document the study was created with.
+	Config string `json:"config"`
+
+	// Parameters holds the search space.
+	Parameters []ho.ParameterSpec `json:"parameters"`
+
+	// Done is true once the configured budget was told, or a stop requested.
+	Done bool `json:"done"`
+
+	// TerminationReason is set once a stop was requested.
+	TerminationReason ho.TerminationReason `json:"terminationReason,omitempty"`
+
+	// Error holds the error a stop was requested with, if any.
+	Error string `json:"error,omitempty"`
+
+	// Best is the best result, nil until a trial completes.
+	Best *Best `json:"best"`
+
+	// Trials holds the told trials, in tell order.
+	Trials []Trial `json:"trials"`
+
+	// Pending holds the suggestions whose result wasn't told yet.
+	Pending []Suggestion `json:"pending"`
+
+	// Abandoned holds the suggestions whose lease expired before their result
+	// was told, see ho.OptimizationConfig.LeaseTimeout.
+	Abandoned []Suggestion `json:"abandoned"`
+
+	// Model holds statistics about the model.
+	Model ModelStats `json:"model"`
+
+	// Warnings holds the non-fatal issues of the study, e.g. trials the
+	// storage failed to record.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Best is the best result of a study.
+type Best struct {
+	Params    map[string]float64 `json:"params"`
+	Formatted map[string]string  `json:"formatted,omitempty"`
+	Value     float64            `json:"value"`
+}
+
+// Suggestion is a set of parameters to evaluate, as returned by ask.
+type Suggestion struct {
+	TrialID   int                `json:"trialId"`
+	Phase     string             `json:"phase"`
+	Iteration int                `json:"iteration"`
+	Params    map[string]float64 `json:"params"`
+	Formatted map[string]string  `json:"formatted,omitempty"`
+	ExpiresAt *time.Time         `json:"expiresAt,omitempty"`
+}
+
+// Trial is a told trial.
+type Trial struct {
+	Suggestion
+
+	Status     ho.TrialStatus `json:"status"`
+	Value      float64        `json:"value"`
+	DurationNS int64          `json:"durationNs"`
+	Error      string         `json:"error,omitempty"`
+	Stop       bool           `json:"stop,omitempty"`
+}
+
+// ModelStats holds statistics about the model of a study.
+type ModelStats struct {
+	// Observations is the number of observations the model was fed.
+	Observations int `json:"observations"`
+
+	// Pending is the number of suggestions waiting for a result, i.e. lies
+	// used to diversify suggestions.
+	Pending int `json:"pending"`
+}
+
+// createResponse is the response of POST /studies.
+type createResponse struct {
+	ID         string             `json:"id"`
+	Parameters []ho.ParameterSpec `json:"parameters"`
+}
+
+// tellRequest is the body of POST /studies/{id}/tell.
+type tellRequest struct {
+	// TrialID is the trial ID returned by ask.
+	TrialID *int `json:"trialId"`
+
+	// Value is the measured value to minimize, required unless Error is set,
+	// or Skipped or Stop is true.
+	Value *float64 `json:"value"`
+
+	// Error reports a failed evaluation.
+	Error string `json:"error"`
+
+	// Skipped reports an invalidated measurement, see ho.ErrSkipTrial.
+	Skipped bool `json:"skipped"`
+
+	// Stop requests the study to stop, see ho.ErrStopOptimization.
+	Stop bool `json:"stop"`
+}
+
+// tellResponse is the response of POST /studies/{id}/tell.
+type tellResponse struct {
+	Trial Trial `json:"trial"`
+	Best  *Best `json:"best"`
+}
+
+// errorResponse is the body of error responses.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+//////
+// Methods.
+//////
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// create handles POST /studies.
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	document, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+
+		return
+	}
+
+	st, err := newStudy(string(document), s.storage, "", time.Now().UTC())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	switch {
+	case s.storage == nil:
+		st.id = newID()
+	case st.id == "":
+		// Studies that can't be stored aren't registered.
+		st.optimizer.Close()
+
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("storing study: %s", strings.Join(st.optimizer.Result().Warnings, "; ")))
+
+		return
+	}
+
+	s.mu.Lock()
+	s.studies[st.id] = st
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, createResponse{ID: st.id, Parameters: st.space.Parameters})
+}
+
+// ask handles POST /studies/{id}/ask.
+func (s *Server) ask(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.study(w, r)
+	if !ok {
+		return
+	}
+
+	suggestion, err := st.optimizer.Ask()
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, st.suggestion(suggestion))
+}
+
+// tell handles POST /studies/{id}/tell.
+func (s *Server) tell(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.study(w, r)
+	if !ok {
+		return
+	}
+
+	var req tellRequest
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+
+		return
+	}
+
+	if req.TrialID == nil {
+		writeError(w, http.StatusBadRequest, errors.New("trialId is required"))
+
+		return
+	}
+
+	var (
+		value   float64
+		evalErr error
+	)
+
+	switch {
+	case req.Stop:
+		evalErr = ho.ErrStopOptimization
+
+		if req.Error != "" {
+			evalErr = fmt.Errorf("%s: %w", req.Error, ho.ErrStopOptimization)
+		}
+	case req.Skipped:
+		evalErr = ho.ErrSkipTrial
+	case req.Error != "":
+		evalErr = errors.New(req.Error)
+	case req.Value == nil:
+		writeError(w, http.StatusBadRequest, errors.New("value is required unless error, skipped or stop is set"))
+
+		return
+	default:
+		value = *req.Value
+	}
+
+	trial, err := st.optimizer.Tell(*req.TrialID, value, evalErr)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tellResponse{Trial: st.trial(trial), Best: st.state().Best})
+}
+
+// get handles GET /studies/{id}.
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.study(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, st.state())
+}
+
+// study returns the study of the request, or writes a 404.
+func (s *Server) study(w http.ResponseWriter, r *http.Request) (*study, bool) {
+	id := r.PathValue("id")
+
+	s.mu.RLock()
+	st, ok := s.studies[id]
+	s.mu.RUnlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown study %q", id))
+	}
+
+	return st, ok
+}
+
+// Load registers the studies of the storage created by a server, e.g. after
+// a restart, so they can be asked and told again. Studies already
+// registered are kept, and studies the server didn't create, i.e. without
+// ConfigTag, are ignored.
+//
+// Parameters:
+// - ctx: Passed to the storage
+//
+// Returns:
+// - error: If there's no storage, the storage fails, or a study can't be
+// resumed, nil otherwise. No study is registered on error.
+//
+// Usage example:
+//
+//	server := httpserver.New(httpserver.Options{Storage: storage})
+//
+//	if err := server.Load(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Important notes:
+// - Told trials feed the model again, see ho.ResumeOptimizerFromStorage
+// - The storage only holds told trials: suggestions pending at the restart
+// are lost, their results are rejected as unknown trials, and their trial
+// IDs handed out again
+// - The random number generator of a study isn't stored, so suggestions
+// differ from those it would have made without the restart.
+func (s *Server) Load(ctx context.Context) error {
+	if s.storage == nil {
+		return errors.New("no storage configured")
+	}
+
+	stored, err := s.storage.ListStudies(ctx)
+	if err != nil {
+		return fmt.Errorf("listing studies: %w", err)
+	}
+
+	s.mu.RLock()
+
+	stored = slices.DeleteFunc(stored, func(study ho.StoredStudy) bool {
+		_, ok := s.studies[study.ID]
+
+		return ok || study.Meta.Tags[ConfigTag] == ""
+	})
+
+	s.mu.RUnlock()
+
+	studies := make([]*study, 0, len(stored))
+
+	for _, study := range stored {
+		st, err := newStudy(study.Meta.Tags[ConfigTag], s.storage, study.ID, study.Meta.StartedAt.UTC())
+		if err != nil {
+			for _, st := range studies {
+				st.optimizer.Close()
+			}
+
+			return fmt.Errorf("resuming study %q: %w", study.ID, err)
+		}
+
+		studies = append(studies, st)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, st := range studies {
+		if _, ok := s.studies[st.id]; !ok {
+			s.studies[st.id] = st
+		}
+	}
+
+	return nil
+}
+
+// params maps parameter names to values.
+func (st *study) params(params []float64) map[string]float64 {
+	named := make(map[string]float64, len(params))
+
+	for i, p := range st.space.Parameters {
+		named[p.Name] = params[i]
+	}
+
+	return named
+}
+
+// formatted maps the names of typed parameters, e.g. enums, to their values
+// rendered by ho.ParameterSpec.Format, nil if there's none.
+func (st *study) formatted(params []float64) map[string]string {
+	var formatted map[string]string
+
+	for i, p := range st.space.Parameters {
+		switch p.Type {
+		case ho.DurationParameter, ho.BoolParameter, ho.EnumParameter:
+		default:
+			continue
+		}
+
+		if formatted == nil {
+			formatted = make(map[string]string)
+		}
+
+		formatted[p.Name] = p.Format(params[i])
+	}
+
+	return formatted
+}
+
+// suggestion converts a suggestion to its API form.
+func (st *study) suggestion(suggestion ho.Suggestion[float64]) Suggestion {
+	s := Suggestion{
+		TrialID:   suggestion.TrialID,
+		Phase:     suggestion.Phase,
+		Iteration: suggestion.Iteration,
+		Params:    st.params(suggestion.Params),
+		Formatted: st.formatted(suggestion.Params),
+	}
+
+	if !suggestion.ExpiresAt.IsZero() {
+		s.ExpiresAt = &suggestion.ExpiresAt
+	}
+
+	return s
+}
+
+// trial converts a trial to its API form.
+func (st *study) trial(trial ho.Trial[float64]) Trial {
+	t := Trial{
+		Suggestion: Suggestion{
+			TrialID:   trial.TrialID,
+			Phase:     trial.Phase,
+			Iteration: trial.Iteration,
+			Params:    st.params(trial.Params),
+			Formatted: st.formatted(trial.Params),
+		},
+		Status:     trial.Status,
+		Value:      trial.ExecutionTime,
+		DurationNS: trial.Duration.Nanoseconds(),
+	}
+
+	if trial.Err != nil {
+		t.Error = trial.Err.Error()
+		t.Stop = errors.Is(trial.Err, ho.ErrStopOptimization)
+	}
+
+	return t
+}
+
+// state returns the current state of the study.
+func (st *study) state() StudyState {
+	result := st.optimizer.Result()
+
+	pending := st.optimizer.Pending()
+
+	abandoned := st.optimizer.Abandoned()
+
+	state := StudyState{
+		ID:         st.id,
+		CreatedAt:  st.createdAt,
+		Config:     st.config,
+		Parameters: st.space.Parameters,
+		Done:       st.optimizer.Done(),
+		Trials:     make([]Trial, len(result.Trials)),
+		Pending:    make([]Suggestion, len(pending)),
+		Abandoned:  make([]Suggestion, len(abandoned)),
+		Model: ModelStats{
+			Observations: st.optimizer.Observations(),
+			Pending:      len(pending),
+		},
+		Warnings: result.Warnings,
+	}
+
+	if result.Err != nil {
+		state.TerminationReason = result.TerminationReason
+		state.Error = result.Err.Error()
+	}
+
+	for i, trial := range result.Trials {
+		state.Trials[i] = st.trial(trial)
+
+		if trial.Status == ho.TrialCompleted && (state.Best == nil || trial.ExecutionTime < state.Best.Value) {
+			state.Best = &Best{Params: state.Trials[i].Params, Formatted: state.Trials[i].Formatted, Value: trial.ExecutionTime}
+		}
+	}
+
+	for i, suggestion := range pending {
+		state.Pending[i] = st.suggestion(suggestion)
+	}
+
+	for i, suggestion := range abandoned {
+		state.Abandoned[i] = st.suggestion(suggestion)
+	}
+
+	return state
+}
+
+//////
+// Helpers.
+//////
+
+// newID returns a random study ID.
+func newID() string {
+	b := make([]byte, 8)
+
+	// crypto/rand.Read never returns an error.
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	w.WriteHeader(status)
+
+	// The status is already sent, nothing sensible can be done on error.
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+//////
+// Factory.
+//////
+
+// newStudy creates a study from its configuration document, or resumes it
+// from the storage if studyID is set. The study ID is that of the storage,
+// empty if there's none or the study couldn't be stored.
+func newStudy(document string, storage ho.Storage, studyID string, createdAt time.Time) (*study, error) {
+	config, space, err := ho.LoadConfig(strings.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	if storage != nil {
+		config.Storage = storage
+
+		config.Tags = maps.Clone(config.Tags)
+		if config.Tags == nil {
+			config.Tags = make(map[string]string, 1)
+		}
+
+		config.Tags[ConfigTag] = document
+	}
+
+	var optimizer *ho.Optimizer[float64]
+
+	if studyID == "" {
+		optimizer, err = ho.NewOptimizer(config, ho.Ranges[float64](space)...)
+	} else {
+		optimizer, err = ho.ResumeOptimizerFromStorage(studyID, config, ho.Ranges[float64](space)...)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &study{
+		id:        optimizer.Result().StudyID,
+		space:     space,
+		optimizer: optimizer,
+		createdAt: createdAt,
+		config:    document,
+	}, nil
+}
+
+// New creates a Server.
+//
+// Parameters:
+// - opts: Server options
+//
+// Returns:
+// - *Server: The server, an http.Handler.
+func New(opts Options) *Server {
+	s := &Server{
+		mux:     http.NewServeMux(),
+		storage: opts.Storage,
+		studies: make(map[string]*study),
+	}
+
+	s.mux.HandleFunc("POST /studies", s.create)
+	s.mux.HandleFunc("POST /studies/{id}/ask", s.ask)
+	s.mux.HandleFunc("POST /studies/{id}/tell", s.tell)
+	s.mux.HandleFunc("GET /studies/{id}", s.get)
+
+	return s
+}
@@ -0,0 +1,333 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho"
+)
+
+const studyDocument = `{
+	"iterations": 4,
+	"initialSamples": 3,
+	"numCandidates": 10,
+	"parameters": [
+		{"name": "x", "type": "float", "min": -5, "max": 5},
+		{"name": "workers", "type": "int", "min": 1, "max": 8}
+	]
+}`
+
+// failingStorage is a storage failing every call.
+type failingStorage struct {
+	ho.Storage
+}
+
+func (failingStorage) CreateStudy(context.Context, ho.StudyMeta) (string, error) {
+	return "", errors.New("disk full")
+}
+
+func (failingStorage) ListStudies(context.Context) ([]ho.StoredStudy, error) {
+	return nil, errors.New("disk full")
+}
+
+// do sends a request to the server, and decodes the JSON response into out,
+// if not nil.
+func do(t *testing.T, server http.Handler, method, path, body string, out any) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+
+	server.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	if out != nil {
+		assert.NoError(t, json.NewDecoder(bytes.NewReader(rec.Body.Bytes())).Decode(out), rec.Body.String())
+	}
+
+	return rec.Code
+}
+
+// tellBody builds a tell request body.
+func tellBody(trialID int, value float64) string {
+	b, _ := json.Marshal(map[string]any{"trialId": trialID, "value": value})
+
+	return string(b)
+}
+
+func TestLifecycle(t *testing.T) {
+	storage := ho.NewMemoryStorage()
+
+	server := New(Options{Storage: storage})
+
+	var created createResponse
+
+	assert.Equal(t, http.StatusCreated, do(t, server, http.MethodPost, "/studies", studyDocument, &created))
+	assert.Len(t, created.Parameters, 2)
+
+	base := "/studies/" + created.ID
+
+	// Ask a batch, as parallel workers would, then tell them all.
+	for round := 0; round < 2; round++ {
+		suggestions := make([]Suggestion, 4)
+
+		for i := range suggestions {
+			assert.Equal(t, http.StatusOK, do(t, server, http.MethodPost, base+"/ask", "", &suggestions[i]))
+
+			x, workers := suggestions[i].Params["x"], suggestions[i].Params["workers"]
+
+			assert.GreaterOrEqual(t, x, -5.0)
+			assert.LessOrEqual(t, x, 5.0)
+			assert.Equal(t, float64(int(workers)), workers)
+		}
+
+		var state StudyState
+
+		assert.Equal(t, http.StatusOK, do(t, server, http.MethodGet, base, "", &state))
+		assert.Len(t, state.Pending, 4)
+		assert.Equal(t, 4, state.Model.Pending)
+
+		for _, s := range suggestions {
+			var resp tellResponse
+
+			value := s.Params["x"] * s.Params["x"]
+
+			assert.Equal(t, http.StatusOK, do(t, server, http.MethodPost, base+"/tell", tellBody(s.TrialID, value), &resp))
+			assert.Equal(t, "Completed", string(resp.Trial.Status))
+			assert.Equal(t, value, resp.Trial.Value)
+			assert.NotNil(t, resp.Best)
+		}
+	}
+
+	var state StudyState
+
+	assert.Equal(t, http.StatusOK, do(t, server, http.MethodGet, base, "", &state))
+	assert.True(t, state.Done)
+	assert.Len(t, state.Trials, 8)
+	assert.Empty(t, state.Pending)
+	assert.Equal(t, 8, state.Model.Observations)
+
+	for _, trial := range state.Trials {
+		assert.LessOrEqual(t, state.Best.Value, trial.Value)
+	}
+
+	// The storage holds the told trials, and the configuration document.
+	stored, err := storage.LoadStudy(context.Background(), created.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored.Trials, len(state.Trials))
+	assert.Equal(t, studyDocument, stored.Meta.Tags[ConfigTag])
+	assert.Equal(t, state.Best.Value, *stored.Best.Value)
+
+	// Stop requests end the study.
+	var suggestion Suggestion
+
+	do(t, server, http.MethodPost, base+"/ask", "", &suggestion)
+
+	body, _ := json.Marshal(map[string]any{"trialId": suggestion.TrialID, "stop": true, "error": "budget exhausted"})
+
+	assert.Equal(t, http.StatusOK, do(t, server, http.MethodPost, base+"/tell", string(body), nil))
+	assert.Equal(t, http.StatusConflict, do(t, server, http.MethodPost, base+"/ask", "", nil))
+
+	do(t, server, http.MethodGet, base, "", &state)
+	assert.Contains(t, state.Error, "budget exhausted")
+	assert.NotEmpty(t, state.TerminationReason)
+}
+
+func TestTellErrors(t *testing.T) {
+	server := New(Options{})
+
+	var created createResponse
+
+	do(t, server, http.MethodPost, "/studies", studyDocument, &created)
+
+	base := "/studies/" + created.ID
+
+	var suggestion Suggestion
+
+	do(t, server, http.MethodPost, base+"/ask", "", &suggestion)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "malformed", body: "{", status: http.StatusBadRequest},
+		{name: "unknown field", body: `{"trialId": 1, "value": 1, "score": 1}`, status: http.StatusBadRequest},
+		{name: "missing trial ID", body: `{"value": 1}`, status: http.StatusBadRequest},
+		{name: "missing value", body: `{"trialId": 1}`, status: http.StatusBadRequest},
+		{name: "unknown trial", body: tellBody(42, 1), status: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp errorResponse
+
+			assert.Equal(t, tt.status, do(t, server, http.MethodPost, base+"/tell", tt.body, &resp))
+			assert.NotEmpty(t, resp.Error)
+		})
+	}
+
+	// The suggestion is still pending, the first tell succeeds, the second
+	// conflicts.
+	assert.Equal(t, http.StatusOK, do(t, server, http.MethodPost, base+"/tell", tellBody(suggestion.TrialID, 1), nil))
+	assert.Equal(t, http.StatusConflict, do(t, server, http.MethodPost, base+"/tell", tellBody(suggestion.TrialID, 1), nil))
+
+	// Failed and skipped evaluations.
+	for _, body := range []string{`{"error": "boom"}`, `{"skipped": true}`} {
+		do(t, server, http.MethodPost, base+"/ask", "", &suggestion)
+
+		b := strings.Replace(body, "{", `{"trialId": `+jsonInt(suggestion.TrialID)+`, `, 1)
+
+		var resp tellResponse
+
+		assert.Equal(t, http.StatusOK, do(t, server, http.MethodPost, base+"/tell", b, &resp))
+		assert.NotEqual(t, "Completed", string(resp.Trial.Status))
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	server := New(Options{})
+
+	assert.Equal(t, http.StatusBadRequest, do(t, server, http.MethodPost, "/studies", `{"parameters": []}`, nil))
+	assert.Equal(t, http.StatusBadRequest, do(t, server, http.MethodPost, "/studies", `{`, nil))
+	assert.Equal(t, http.StatusNotFound, do(t, server, http.MethodPost, "/studies/nope/ask", "", nil))
+	assert.Equal(t, http.StatusNotFound, do(t, server, http.MethodGet, "/studies/nope", "", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, do(t, server, http.MethodGet, "/studies/nope/ask", "", nil))
+
+	// Storage failures are reported.
+	failing := New(Options{Storage: failingStorage{}})
+
+	var resp errorResponse
+
+	assert.Equal(t, http.StatusInternalServerError, do(t, failing, http.MethodPost, "/studies", studyDocument, &resp))
+	assert.Contains(t, resp.Error, "disk full")
+}
+
+func TestConcurrentTells(t *testing.T) {
+	storage := ho.NewMemoryStorage()
+
+	server := New(Options{Storage: storage})
+
+	var created createResponse
+
+	do(t, server, http.MethodPost, "/studies", studyDocument, &created)
+
+	base := "/studies/" + created.ID
+
+	suggestions := make([]Suggestion, 6)
+
+	for i := range suggestions {
+		do(t, server, http.MethodPost, base+"/ask", "", &suggestions[i])
+	}
+
+	var wg sync.WaitGroup
+
+	for _, s := range suggestions {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.Equal(t, http.StatusOK, do(t, server, http.MethodPost, base+"/tell", tellBody(s.TrialID, s.Params["x"]), nil))
+		}()
+	}
+
+	wg.Wait()
+
+	// The storage holds every trial.
+	stored, err := storage.LoadStudy(context.Background(), created.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored.Trials, len(suggestions))
+}
+
+func TestLoad(t *testing.T) {
+	storage := ho.NewMemoryStorage()
+
+	// Studies not created by a server are ignored.
+	_, err := storage.CreateStudy(context.Background(), ho.StudyMeta{})
+	assert.NoError(t, err)
+
+	server := New(Options{Storage: storage})
+
+	var created createResponse
+
+	do(t, server, http.MethodPost, "/studies", studyDocument, &created)
+
+	base := "/studies/" + created.ID
+
+	suggestions := make([]Suggestion, 5)
+
+	for i := range suggestions {
+		do(t, server, http.MethodPost, base+"/ask", "", &suggestions[i])
+	}
+
+	// A failed trial, then completed ones, one suggestion left pending.
+	body, _ := json.Marshal(map[string]any{"trialId": suggestions[0].TrialID, "error": "boom"})
+
+	do(t, server, http.MethodPost, base+"/tell", string(body), nil)
+
+	for _, s := range suggestions[1:4] {
+		do(t, server, http.MethodPost, base+"/tell", tellBody(s.TrialID, s.Params["x"]), nil)
+	}
+
+	var before StudyState
+
+	do(t, server, http.MethodGet, base, "", &before)
+
+	// A restarted server serves the study from the storage.
+	restarted := New(Options{Storage: storage})
+
+	assert.Equal(t, http.StatusNotFound, do(t, restarted, http.MethodGet, base, "", nil))
+	assert.NoError(t, restarted.Load(context.Background()))
+	assert.Len(t, restarted.studies, 1)
+
+	var after StudyState
+
+	assert.Equal(t, http.StatusOK, do(t, restarted, http.MethodGet, base, "", &after))
+	assert.Equal(t, before.Config, after.Config)
+	assert.Equal(t, before.Best, after.Best)
+	assert.Equal(t, before.Model.Observations, after.Model.Observations)
+	assert.Len(t, after.Trials, len(before.Trials))
+
+	for i, trial := range after.Trials {
+		assert.Equal(t, before.Trials[i].Suggestion, trial.Suggestion)
+		assert.Equal(t, before.Trials[i].Status, trial.Status)
+		assert.Equal(t, before.Trials[i].Error, trial.Error)
+	}
+
+	// The pending suggestion is lost, its trial ID is handed out again.
+	assert.Empty(t, after.Pending)
+	assert.Equal(t, http.StatusConflict, do(t, restarted, http.MethodPost, base+"/tell", tellBody(suggestions[4].TrialID, 1), nil))
+
+	var next Suggestion
+
+	assert.Equal(t, http.StatusOK, do(t, restarted, http.MethodPost, base+"/ask", "", &next))
+	assert.Equal(t, suggestions[4].TrialID, next.TrialID)
+
+	// Stopped studies stay stopped.
+	body, _ = json.Marshal(map[string]any{"trialId": next.TrialID, "stop": true})
+
+	assert.Equal(t, http.StatusOK, do(t, restarted, http.MethodPost, base+"/tell", string(body), nil))
+
+	stopped := New(Options{Storage: storage})
+
+	assert.NoError(t, stopped.Load(context.Background()))
+	assert.Equal(t, http.StatusConflict, do(t, stopped, http.MethodPost, base+"/ask", "", nil))
+
+	// Load needs a working storage.
+	assert.Error(t, New(Options{}).Load(context.Background()))
+	assert.Error(t, New(Options{Storage: failingStorage{}}).Load(context.Background()))
+}
+
+// jsonInt formats an integer as JSON.
+func jsonInt(i int) string {
+	b, _ := json.Marshal(i)
+
+	return string(b)
+}
@@ -0,0 +1,612 @@
+package ho
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// PhaseHyperband is the TrialInfo.Phase of the evaluations of a
+	// Hyperband run, see OptimizeHyperband.
+	PhaseHyperband = "Hyperband"
+
+	// DefaultHyperbandEta is the Hyperband.Eta used if unset.
+	DefaultHyperbandEta = 3
+
+	// DefaultHyperbandRandomFraction is the Hyperband.RandomFraction used if
+	// unset.
+	DefaultHyperbandRandomFraction = 1.0 / 3
+)
+
+// HyperbandProposals is how the configurations of a Hyperband bracket are
+// proposed, see Hyperband.Proposals.
+type HyperbandProposals string
+
+const (
+	// ProposeModel proposes configurations with the surrogate model, as BOHB
+	// does, once enough configurations were evaluated.
+	ProposeModel HyperbandProposals = "Model"
+
+	// ProposeRandom draws configurations uniformly, as plain Hyperband does.
+	ProposeRandom HyperbandProposals = "Random"
+)
+
+// BudgetedObjectiveFunc is an objective evaluated with a resource budget,
+// e.g. a number of epochs, iterations or seconds: the larger the budget, the
+// closer the value to the final one, and the more expensive the evaluation.
+//
+// Parameters:
+// - ctx: The trial context
+// - budget: The resource budget, between Hyperband.MinBudget and
+// Hyperband.MaxBudget
+// - params: The parameters to evaluate
+//
+// Returns:
+// - float64: The value reached with the budget, lower is better
+// - error: Failure, handled as for ObjectiveFunc.
+type BudgetedObjectiveFunc[T constraints.Integer | constraints.Float] func(ctx context.Context, budget float64, params ...T) (float64, error)
+
+// Hyperband configures a Hyperband run, see OptimizeHyperband.
+type Hyperband struct {
+	// MinBudget is the budget of the first rung of the most aggressive
+	// bracket. It must be positive.
+	MinBudget float64
+
+	// MaxBudget is the budget of the last rung of each bracket, the budget
+	// the best value is measured with. It must be at least MinBudget.
+	MaxBudget float64
+
+	// Eta is the halving rate: each rung keeps the best 1/Eta of the
+	// configurations, evaluated with Eta times the budget.
+	// If 0, DefaultHyperbandEta is used.
+	Eta int
+
+	// Iterations is the number of Hyperband iterations, each running every
+	// bracket once.
+	// If 0, 1 is used.
+	Iterations int
+
+	// Proposals is how configurations are proposed.
+	// If empty, ProposeModel is used.
+	Proposals HyperbandProposals
+
+	// RandomFraction is the fraction of configurations still drawn uniformly
+	// with ProposeModel, so the model's blind spots are explored too.
+	// If 0, DefaultHyperbandRandomFraction is used.
+	RandomFraction float64
+}
+
+// BracketStats holds the statistics of a Hyperband bracket, see
+// Result.Brackets.
+type BracketStats struct {
+	// Iteration is the (1-based) Hyperband iteration the bracket belongs to.
+	Iteration int `json:"iteration"`
+
+	// Halvings is the number of halvings of the bracket: the most aggressive
+	// bracket of an iteration comes first, the last one has none.
+	Halvings int `json:"halvings"`
+
+	// Configs is the number of configurations of the first rung.
+	Configs int `json:"configs"`
+
+	// ModelProposals is the number of them proposed by the model, see
+	// ProposeModel.
+	ModelProposals int `json:"modelProposals"`
+
+	// MinBudget is the budget of the first rung.
+	MinBudget float64 `json:"minBudget"`
+
+	// Evaluations is the number of evaluations, across rungs.
+	Evaluations int `json:"evaluations"`
+
+	// Cost is the sum of the budgets evaluated.
+	Cost float64 `json:"cost"`
+
+	// BestValue is the best value of the last rung, evaluated with
+	// MaxBudget, nil if none completed.
+	BestValue *float64 `json:"bestValue,omitempty"`
+
+	// BestTrialID is the ID of the trial of BestValue, 0 if none.
+	BestTrialID int `json:"bestTrialId,omitempty"`
+}
+
+// hyperbandState tracks a Hyperband run.
+type hyperbandState struct {
+	// config is the configuration, defaults applied.
+	config Hyperband
+
+	// halvings is the number of halvings of the most aggressive bracket.
+	halvings int
+
+	// planned is the number of evaluations of the run.
+	planned int
+
+	// newModel creates the models proposals are scored with.
+	newModel func() SurrogateModel
+
+	// configs holds the value of each evaluated configuration at its
+	// highest budget, by point, and order their points, in evaluation
+	// order. Protected by the optimizer's mu.
+	configs map[string]*budgetedValue
+	order   []string
+
+	// brackets holds the statistics of the brackets run so far. Protected by
+	// the optimizer's mu.
+	brackets []BracketStats
+}
+
+// budgetedValue is the value of a configuration at its highest budget.
+type budgetedValue struct {
+	// params is the point of the configuration, see modelPoint.
+	params []float64
+
+	// budget is the highest budget the configuration completed with.
+	budget float64
+
+	// value is its value with budget.
+	value float64
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the configuration against the run.
+//
+// Parameters:
+// - config: The configuration of the run
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a setting is invalid, or the run
+// uses an unsupported option, nil otherwise.
+func (h Hyperband) validate(config OptimizationConfig) error {
+	switch {
+	case !(h.MinBudget > 0) || math.IsInf(h.MinBudget, 0):
+		return fmt.Errorf("%w: Hyperband: MinBudget %v must be finite and positive", ErrInvalidConfig, h.MinBudget)
+	case !(h.MaxBudget >= h.MinBudget) || math.IsInf(h.MaxBudget, 0):
+		return fmt.Errorf("%w: Hyperband: MaxBudget %v must be finite and at least MinBudget", ErrInvalidConfig, h.MaxBudget)
+	case h.Eta < 0 || h.Eta == 1:
+		return fmt.Errorf("%w: Hyperband: Eta %d must be 0 or at least 2", ErrInvalidConfig, h.Eta)
+	case h.Iterations < 0:
+		return fmt.Errorf("%w: Hyperband: Iterations %d is negative", ErrInvalidConfig, h.Iterations)
+	case h.RandomFraction < 0 || h.RandomFraction > 1 || math.IsNaN(h.RandomFraction):
+		return fmt.Errorf("%w: Hyperband: RandomFraction %v out of [0, 1]", ErrInvalidConfig, h.RandomFraction)
+	}
+
+	switch h.Proposals {
+	case "", ProposeModel, ProposeRandom:
+	default:
+		return fmt.Errorf("%w: Hyperband: unknown Proposals %q", ErrInvalidConfig, h.Proposals)
+	}
+
+	return checkFidelityOptions(config, "a Hyperband run")
+}
+
+// rungs returns the number of configurations and the budget of each rung of
+// the bracket with the given number of halvings.
+func (h *hyperbandState) rungs(halvings int) ([]int, []float64) {
+	eta := h.config.Eta
+
+	power := 1
+
+	for i := 0; i < halvings; i++ {
+		power *= eta
+	}
+
+	// The brackets of an iteration spend about the same budget.
+	n := ((h.halvings+1)*power + halvings) / (halvings + 1)
+
+	counts, budgets := make([]int, halvings+1), make([]float64, halvings+1)
+
+	for i := range counts {
+		counts[i] = n
+
+		budgets[i] = h.config.MaxBudget / math.Pow(float64(eta), float64(halvings-i))
+
+		n /= eta
+	}
+
+	return counts, budgets
+}
+
+// recordHyperband feeds a recorded trial of a Hyperband run to the
+// bookkeeping of its configuration. Only trials evaluated with MaxBudget
+// reach the run model, and can be the best.
+func (o *optimizer[T]) recordHyperband(trial Trial[T]) {
+	if trial.Status != TrialCompleted {
+		return
+	}
+
+	x := o.modelPoint(trial.Params)
+
+	key := pointKey(x)
+
+	o.mu.Lock()
+
+	value, ok := o.hyperband.configs[key]
+
+	if !ok {
+		value = &budgetedValue{params: x}
+
+		o.hyperband.configs[key] = value
+		o.hyperband.order = append(o.hyperband.order, key)
+	}
+
+	if !ok || trial.Budget >= value.budget {
+		value.budget, value.value = trial.Budget, trial.ExecutionTime
+	}
+
+	o.mu.Unlock()
+
+	if trial.Budget < o.hyperband.config.MaxBudget {
+		return
+	}
+
+	if err := updateWeighted(o.model, x, trial.ExecutionTime, trial.Weight); err != nil {
+		o.warnf("trial %d not fed to the model: %v", trial.TrialID, err)
+
+		return
+	}
+
+	o.mu.Lock()
+	o.observations = append(o.observations, Observation{Params: x, Value: trial.ExecutionTime, Weight: trial.Weight})
+	o.mu.Unlock()
+
+	previous, improved := o.updateBest(trial.Params, trial.ExecutionTime)
+
+	if improved {
+		o.mu.Lock()
+		o.bestTrialID = trial.TrialID
+		o.mu.Unlock()
+
+		o.newBest(trial, previous)
+	}
+}
+
+// proposalModel fits a model on the value of each evaluated configuration at
+// its highest budget, to propose configurations with.
+//
+// Returns:
+// - SurrogateModel: The model, nil if fewer configurations than dimensions
+// plus one were evaluated
+// - float64: The best value the model observed
+// - []T: The parameters of the best value.
+func (o *optimizer[T]) proposalModel() (SurrogateModel, float64, []T) {
+	o.mu.Lock()
+
+	values := make([]budgetedValue, len(o.hyperband.order))
+
+	for i, key := range o.hyperband.order {
+		values[i] = *o.hyperband.configs[key]
+	}
+
+	o.mu.Unlock()
+
+	model := o.hyperband.newModel()
+
+	if model == nil || len(values) < len(o.hypers)+1 {
+		return nil, math.MaxFloat64, nil
+	}
+
+	best, bestIndex := math.MaxFloat64, -1
+
+	for i, value := range values {
+		if model.Update(value.params, value.value) == nil && value.value < best {
+			best, bestIndex = value.value, i
+		}
+	}
+
+	if bestIndex < 0 {
+		return nil, math.MaxFloat64, nil
+	}
+
+	incumbent := make([]T, len(o.hypers))
+
+	for d, v := range values[bestIndex].params {
+		incumbent[d] = modelValue(o.hypers[d], v)
+	}
+
+	return model, best, incumbent
+}
+
+// proposeConfigs proposes the configurations of a bracket.
+//
+// Parameters:
+// - n: Number of configurations
+// - iteration: The index of the bracket in the run
+//
+// Returns:
+// - [][]T: The configurations
+// - int: Number of them proposed by the model.
+func (o *optimizer[T]) proposeConfigs(n, iteration int) ([][]T, int) {
+	var model SurrogateModel
+
+	var best float64
+
+	var incumbent []T
+
+	if o.hyperband.config.Proposals == ProposeModel {
+		model, best, incumbent = o.proposalModel()
+	}
+
+	configs := make([][]T, 0, n)
+
+	proposed := 0
+
+	for i := 0; i < n; i++ {
+		var params []T
+
+		if model != nil && !o.randomProposal() {
+			params, _ = o.proposeCandidate(model, iteration, best, incumbent)
+		}
+
+		if params == nil {
+			params = o.initialParams()
+		} else {
+			proposed++
+
+			// The next proposals go elsewhere, see withLies.
+			model = withLies(model, [][]float64{o.modelPoint(params)}, best)
+		}
+
+		configs = append(configs, params)
+	}
+
+	return configs, proposed
+}
+
+// randomProposal returns whether the next proposal of the model is drawn
+// uniformly instead, see Hyperband.RandomFraction.
+func (o *optimizer[T]) randomProposal() bool {
+	o.rngMu.Lock()
+	defer o.rngMu.Unlock()
+
+	return o.rng.Float64() < o.hyperband.config.RandomFraction
+}
+
+// runBracket runs successive halving on the configurations of a bracket:
+// each rung evaluates them with its budget, and the best 1/Eta move on to
+// the next rung.
+//
+// Parameters:
+// - iteration: The Hyperband iteration
+// - index: The index of the bracket in the run
+// - halvings: The number of halvings of the bracket.
+func (o *optimizer[T]) runBracket(iteration, index, halvings int) {
+	h := o.hyperband
+
+	counts, budgets := h.rungs(halvings)
+
+	configs, proposed := o.proposeConfigs(counts[0], index)
+
+	o.mu.Lock()
+
+	h.brackets = append(h.brackets, BracketStats{
+		Iteration:      iteration,
+		Halvings:       halvings,
+		Configs:        counts[0],
+		ModelProposals: proposed,
+		MinBudget:      budgets[0],
+	})
+
+	stats := &h.brackets[len(h.brackets)-1]
+
+	o.mu.Unlock()
+
+	total := h.config.Iterations * (h.halvings + 1)
+
+	for rung := range counts {
+		values := make([]float64, len(configs))
+
+		for i, params := range configs {
+			if !o.proceed() {
+				return
+			}
+
+			info := o.newTrialInfo(PhaseHyperband, index, false)
+
+			info.Budget = budgets[rung]
+
+			trial := o.evaluate(info, params)
+
+			o.sendProgress(trial, total, 0)
+
+			// Trials that didn't complete are the worst.
+			values[i] = math.Inf(1)
+
+			if trial.Status == TrialCompleted {
+				values[i] = trial.ExecutionTime
+			}
+
+			o.mu.Lock()
+
+			stats.Evaluations++
+			stats.Cost += budgets[rung]
+
+			if rung == len(counts)-1 && trial.Status == TrialCompleted && (stats.BestValue == nil || trial.ExecutionTime < *stats.BestValue) {
+				value := trial.ExecutionTime
+
+				stats.BestValue, stats.BestTrialID = &value, trial.TrialID
+			}
+
+			o.mu.Unlock()
+		}
+
+		if rung == len(counts)-1 {
+			break
+		}
+
+		order := make([]int, len(configs))
+
+		for i := range order {
+			order[i] = i
+		}
+
+		sort.SliceStable(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+
+		next := make([][]T, counts[rung+1])
+
+		for i := range next {
+			next[i] = configs[order[i]]
+		}
+
+		configs = next
+	}
+}
+
+// runHyperband runs every bracket of each Hyperband iteration, most
+// aggressive first, see OptimizeHyperband.
+func (o *optimizer[T]) runHyperband() {
+	index := 0
+
+	for iteration := 1; iteration <= o.hyperband.config.Iterations; iteration++ {
+		for halvings := o.hyperband.halvings; halvings >= 0; halvings-- {
+			if !o.proceed() {
+				return
+			}
+
+			index++
+
+			o.runBracket(iteration, index, halvings)
+		}
+	}
+}
+
+//////
+// Exported functionalities.
+//////
+
+// OptimizeHyperband minimizes an objective evaluated with a resource budget,
+// e.g. the validation loss after a number of epochs, with Hyperband: many
+// configurations are evaluated with a small budget, and only the best are
+// evaluated with larger ones, successive halving. With ProposeModel, the
+// default, configurations are proposed by the surrogate model instead of
+// drawn uniformly, as BOHB does.
+//
+// Parameters:
+// - ctx: Run context, each trial context is derived from it
+// - config: OptimizationConfig controlling the optimization process
+// - hyperband: The budgets and the schedule, see Hyperband
+// - objective: The objective, evaluated with a budget
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Result[T]: The outcome of the run, see Result.Brackets for the
+// statistics of each bracket
+//
+// Usage example:
+//
+//	hyperband := Hyperband{MinBudget: 1, MaxBudget: 81}
+//
+//	result := OptimizeHyperband(ctx, config, hyperband, func(ctx context.Context, epochs float64, params ...float64) (float64, error) {
+//	    return train(ctx, int(epochs), params...)
+//	}, ranges...)
+//
+// Important notes:
+// - Each iteration runs the brackets from the most aggressive one, starting
+// with the most configurations at MinBudget, to the last one, evaluating a
+// few configurations at MaxBudget only. Budgets are MaxBudget divided by
+// powers of Eta, not rounded
+// - Proposals are scored by a model fitted on the value of each evaluated
+// configuration at its highest budget. Its best value is the lie of the
+// constant liar strategy, so the proposals of a bracket spread out. Until
+// dimensions plus one configurations completed, they're drawn uniformly,
+// as initial samples are
+// - Only values evaluated with MaxBudget reach the run model and can be the
+// best, i.e. Result.BestTime, Result.Predict and Result.PredictGrid are
+// about MaxBudget values
+// - Evaluations are serial, whatever MaxConcurrentEvaluations. Each one is a
+// trial with Phase PhaseHyperband, Iteration the index of its bracket in
+// the run, and Budget its budget, so the Setup and Teardown hooks see it.
+// Trials that don't complete rank last. ProfileBest profiles with MaxBudget
+// - InitialSamples and Iterations are ignored. WarmStart, Checkpoint,
+// CacheEvaluations, DriftSentinel, SurpriseRemeasure, Safety, KnownOptimum
+// and output transforms aren't supported.
+func OptimizeHyperband[T constraints.Integer | constraints.Float](
+	ctx context.Context,
+	config OptimizationConfig,
+	hyperband Hyperband,
+	objective BudgetedObjectiveFunc[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	o := newOptimizer[T](ctx, config, nil, hypers...)
+
+	if err := hyperband.validate(config); err != nil {
+		o.invalidErr = err
+
+		return o.result()
+	}
+
+	o.hyperband = newHyperbandState(hyperband, surrogateFactory(config, hypers))
+
+	maxBudget := o.hyperband.config.MaxBudget
+
+	o.objectiveFunc = func(ctx context.Context, info TrialInfo, params ...T) (float64, error) {
+		// The profiling run of the best parameters has no budget.
+		budget := info.Budget
+		if budget == 0 {
+			budget = maxBudget
+		}
+
+		return objective(ctx, budget, params...)
+	}
+
+	return o.run()
+}
+
+//////
+// Factory.
+//////
+
+// newHyperbandState creates the state of a Hyperband run.
+//
+// Parameters:
+// - config: The configuration, validated
+// - newModel: Creates the models proposals are scored with
+//
+// Returns:
+// - *hyperbandState: The state, with the schedule computed.
+func newHyperbandState(config Hyperband, newModel func() SurrogateModel) *hyperbandState {
+	if config.Eta == 0 {
+		config.Eta = DefaultHyperbandEta
+	}
+
+	if config.Iterations == 0 {
+		config.Iterations = 1
+	}
+
+	if config.Proposals == "" {
+		config.Proposals = ProposeModel
+	}
+
+	if config.RandomFraction == 0 {
+		config.RandomFraction = DefaultHyperbandRandomFraction
+	}
+
+	state := &hyperbandState{config: config, newModel: newModel, configs: make(map[string]*budgetedValue)}
+
+	// Budgets below MinBudget, but for rounding errors, aren't evaluated.
+	eta := float64(config.Eta)
+
+	for config.MaxBudget/math.Pow(eta, float64(state.halvings+1)) >= config.MinBudget*(1-1e-9) {
+		state.halvings++
+	}
+
+	for halvings := state.halvings; halvings >= 0; halvings-- {
+		counts, _ := state.rungs(halvings)
+
+		for _, n := range counts {
+			state.planned += config.Iterations * n
+		}
+	}
+
+	return state
+}
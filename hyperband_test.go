@@ -0,0 +1,141 @@
+package ho
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// learningCurve converges, as the budget grows, to a quadratic minimal at
+// (0.2, 0.7, 0.4). Small budgets favor small y, so they rank configurations
+// differently than the final values do.
+func learningCurve(_ context.Context, budget float64, params ...float64) (float64, error) {
+	x, y, z := params[0], params[1], params[2]
+
+	final := (x-0.2)*(x-0.2) + (y-0.7)*(y-0.7) + (z-0.4)*(z-0.4)
+
+	return final + (0.2+y)/budget, nil
+}
+
+// curveRanges is the search space of learningCurve.
+var curveRanges = []ParameterRange[float64]{
+	{Name: "x", Min: 0, Max: 1},
+	{Name: "y", Min: 0, Max: 1},
+	{Name: "z", Min: 0, Max: 1},
+}
+
+func TestOptimizeHyperband(t *testing.T) {
+	hyperband := Hyperband{MinBudget: 1, MaxBudget: 27, Iterations: 2}
+
+	t.Run("schedule", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Seed = 1
+
+		var budgets []float64
+
+		config.Setup = func(info TrialInfo, _ ...float64) error {
+			budgets = append(budgets, info.Budget)
+
+			return nil
+		}
+
+		result := OptimizeHyperband(context.Background(), config, hyperband, learningCurve, curveRanges...)
+
+		if !assert.NoError(t, result.Err) {
+			return
+		}
+
+		assert.Equal(t, TerminationCompleted, result.TerminationReason)
+
+		// Brackets of 27, 12, 6 and 4 configurations, halved by 3.
+		if !assert.Len(t, result.Brackets, 8) {
+			return
+		}
+
+		for i, want := range []BracketStats{
+			{Iteration: 1, Halvings: 3, Configs: 27, MinBudget: 1, Evaluations: 40, Cost: 27 + 27 + 27 + 27},
+			{Iteration: 1, Halvings: 2, Configs: 12, MinBudget: 3, Evaluations: 17, Cost: 36 + 36 + 27},
+			{Iteration: 1, Halvings: 1, Configs: 6, MinBudget: 9, Evaluations: 8, Cost: 54 + 54},
+			{Iteration: 1, Halvings: 0, Configs: 4, MinBudget: 27, Evaluations: 4, Cost: 108},
+		} {
+			got := result.Brackets[i]
+
+			assert.Equal(t, want.Iteration, got.Iteration)
+			assert.Equal(t, want.Halvings, got.Halvings)
+			assert.Equal(t, want.Configs, got.Configs)
+			assert.Equal(t, want.MinBudget, got.MinBudget)
+			assert.Equal(t, want.Evaluations, got.Evaluations)
+			assert.InDelta(t, want.Cost, got.Cost, 1e-9)
+
+			if assert.NotNil(t, got.BestValue) {
+				assert.Equal(t, *got.BestValue, result.Trials[got.BestTrialID-1].ExecutionTime)
+			}
+		}
+
+		// The first brackets are random, the second iteration's are mostly
+		// proposed by the model.
+		assert.Zero(t, result.Brackets[0].ModelProposals)
+		assert.Greater(t, result.Brackets[4].ModelProposals, result.Brackets[4].Configs/2)
+
+		assert.Len(t, result.Trials, 2*(40+17+8+4))
+		assert.Len(t, budgets, len(result.Trials))
+
+		for i, trial := range result.Trials {
+			assert.Equal(t, PhaseHyperband, trial.Phase)
+			assert.Equal(t, budgets[i], trial.Budget)
+			assert.Equal(t, trial.Budget, NewTrialRecord(trial, result.ParamNames).Budget)
+		}
+
+		// Only values with MaxBudget are eligible.
+		best := math.MaxFloat64
+
+		for _, trial := range result.Trials {
+			if trial.Budget == 27 {
+				best = math.Min(best, trial.ExecutionTime)
+			}
+		}
+
+		assert.Equal(t, best, result.BestTime)
+		assert.Contains(t, result.TerminationDetail, "8 brackets")
+	})
+
+	t.Run("model proposals beat random ones", func(t *testing.T) {
+		var bohb, random float64
+
+		for seed := int64(1); seed <= 5; seed++ {
+			config := DefaultConfig()
+			config.Seed = seed
+
+			bohb += OptimizeHyperband(context.Background(), config, hyperband, learningCurve, curveRanges...).BestTime
+
+			plain := hyperband
+			plain.Proposals = ProposeRandom
+
+			random += OptimizeHyperband(context.Background(), config, plain, learningCurve, curveRanges...).BestTime
+		}
+
+		assert.Less(t, bohb, random)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, hyperband := range []Hyperband{
+			{MaxBudget: 27},
+			{MinBudget: 27, MaxBudget: 1},
+			{MinBudget: 1, MaxBudget: 27, Eta: 1},
+			{MinBudget: 1, MaxBudget: 27, Iterations: -1},
+			{MinBudget: 1, MaxBudget: 27, RandomFraction: 2},
+			{MinBudget: 1, MaxBudget: 27, Proposals: "TPE"},
+		} {
+			result := OptimizeHyperband(context.Background(), DefaultConfig(), hyperband, learningCurve, curveRanges...)
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig, hyperband)
+		}
+
+		config := DefaultConfig()
+		config.CacheEvaluations = true
+
+		result := OptimizeHyperband(context.Background(), config, Hyperband{MinBudget: 1, MaxBudget: 27}, learningCurve, curveRanges...)
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+}
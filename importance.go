@@ -0,0 +1,332 @@
+package ho
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// importanceSeed seeds the forest and the samples, so importance is
+	// deterministic for a given history.
+	importanceSeed = 1
+
+	// importanceSamples is the number of points the surrogate is averaged
+	// over.
+	importanceSamples = 64
+
+	// importanceResolution is the maximum number of values each parameter is
+	// swept over.
+	importanceResolution = 10
+
+	// importancePairDims is the number of most important parameters whose
+	// pairwise interactions are computed.
+	importancePairDims = 4
+
+	// minImportanceTrials is the minimum number of completed trials.
+	minImportanceTrials = 4
+)
+
+// ParameterImportance tells which parameters matter, see
+// Result.ParameterImportance.
+type ParameterImportance struct {
+	// Scores holds the importance of each parameter, in range order.
+	Scores []ImportanceScore `json:"scores"`
+
+	// Interactions holds the interactions between the most important
+	// parameters, strongest first.
+	Interactions []InteractionScore `json:"interactions"`
+}
+
+// ImportanceScore is the importance of a parameter.
+type ImportanceScore struct {
+	// Dim is the index of the parameter.
+	Dim int `json:"dim"`
+
+	// Name is the name of the parameter. Unnamed parameters are named
+	// "param<i>", as in TrialRecord.
+	Name string `json:"name"`
+
+	// Score is the share of the main effects variance due to the parameter.
+	// Scores sum to 1.
+	Score float64 `json:"score"`
+}
+
+// InteractionScore is the interaction strength of a pair of parameters.
+type InteractionScore struct {
+	// Dims holds the indices of the parameters.
+	Dims [2]int `json:"dims"`
+
+	// Names holds the names of the parameters.
+	Names [2]string `json:"names"`
+
+	// Strength is the share of the total variance due to the interaction of
+	// the parameters, beyond their main effects.
+	Strength float64 `json:"strength"`
+}
+
+// importanceAnalysis holds the state of an importance computation.
+type importanceAnalysis struct {
+	// predict predicts the objective at a point, e.g. with a forest.
+	predict func(x []float64) float64
+
+	// samples holds the points the surrogate is averaged over.
+	samples [][]float64
+
+	// axes holds the sweep values of each parameter.
+	axes [][]float64
+}
+
+//////
+// Methods.
+//////
+
+// ParameterImportance estimates how much each parameter matters, and how
+// strongly the most important ones interact, from the completed trials.
+//
+// Returns:
+// - *ParameterImportance: The scores
+// - error: If there are too few completed trials.
+//
+// Usage example:
+//
+//	importance, err := result.ParameterImportance()
+//	if err != nil {
+//	    return err
+//	}
+//
+//	for _, score := range importance.Scores {
+//	    fmt.Printf("%s: %.0f%%\n", score.Name, score.Score*100)
+//	}
+//
+// Important notes:
+// - It's a functional ANOVA estimate: a random forest is fitted on the
+// completed trials, then sampled. The importance of a parameter is the
+// variance of its main effect, i.e. the prediction averaged over the other
+// parameters, relative to the other main effects
+// - Interactions are computed between the 4 most important parameters, as
+// the variance of their joint effect beyond the main effects, relative to
+// the total variance
+// - Samples are uniform, or follow the parameter priors if set. Integer and
+// stepped parameters are sampled and swept on their lattice
+// - Results are deterministic for a given history. If the surrogate is
+// flat, e.g. all values are equal, all scores are 0.
+func (r *Result[T]) ParameterImportance() (*ParameterImportance, error) {
+	var (
+		x [][]float64
+		y []float64
+	)
+
+	for _, trial := range r.Trials {
+		if trial.Status == TrialCompleted {
+			x = append(x, paramsToFloat64s(trial.Params))
+			y = append(y, trial.ExecutionTime)
+		}
+	}
+
+	if len(x) < minImportanceTrials || len(r.hypers) == 0 {
+		return nil, fmt.Errorf("parameter importance needs at least %d completed trials, got %d", minImportanceTrials, len(x))
+	}
+
+	rng := rand.New(rand.NewSource(importanceSeed))
+
+	forest := fitForest(x, y, rng, forestTrees)
+
+	analysis := newImportanceAnalysis(r.hypers, forest.predict, importanceSamples, importanceResolution, rng)
+
+	total := analysis.totalVariance()
+
+	importance := &ParameterImportance{Scores: make([]ImportanceScore, len(r.hypers))}
+
+	mainEffects := make([][]float64, len(r.hypers))
+
+	var sum float64
+
+	for d := range r.hypers {
+		mainEffects[d] = analysis.mainEffect(d)
+
+		importance.Scores[d] = ImportanceScore{
+			Dim:   d,
+			Name:  paramName(r.ParamNames, d),
+			Score: populationVariance(mainEffects[d]),
+		}
+
+		sum += importance.Scores[d].Score
+	}
+
+	for d := range importance.Scores {
+		if sum > 0 {
+			importance.Scores[d].Score /= sum
+		}
+	}
+
+	top := make([]int, len(r.hypers))
+
+	for d := range top {
+		top[d] = d
+	}
+
+	sort.SliceStable(top, func(i, j int) bool { return importance.Scores[top[i]].Score > importance.Scores[top[j]].Score })
+
+	top = top[:min(len(top), importancePairDims)]
+
+	sort.Ints(top)
+
+	for a := 0; a < len(top); a++ {
+		for b := a + 1; b < len(top); b++ {
+			i, j := top[a], top[b]
+
+			interaction := InteractionScore{
+				Dims:  [2]int{i, j},
+				Names: [2]string{paramName(r.ParamNames, i), paramName(r.ParamNames, j)},
+			}
+
+			if total > 0 {
+				interaction.Strength = analysis.interaction(i, j, mainEffects[i], mainEffects[j]) / total
+			}
+
+			importance.Interactions = append(importance.Interactions, interaction)
+		}
+	}
+
+	sort.SliceStable(importance.Interactions, func(i, j int) bool {
+		return importance.Interactions[i].Strength > importance.Interactions[j].Strength
+	})
+
+	return importance, nil
+}
+
+// totalVariance returns the variance of the predictions over the samples.
+func (a *importanceAnalysis) totalVariance() float64 {
+	predictions := make([]float64, len(a.samples))
+
+	for i, sample := range a.samples {
+		predictions[i] = a.predict(sample)
+	}
+
+	return populationVariance(predictions)
+}
+
+// mainEffect returns the prediction averaged over the samples, d being
+// replaced by each of its sweep values.
+func (a *importanceAnalysis) mainEffect(d int) []float64 {
+	effect := make([]float64, len(a.axes[d]))
+
+	point := make([]float64, len(a.axes))
+
+	for k, v := range a.axes[d] {
+		for _, sample := range a.samples {
+			copy(point, sample)
+
+			point[d] = v
+
+			effect[k] += a.predict(point)
+		}
+
+		effect[k] /= float64(len(a.samples))
+	}
+
+	return effect
+}
+
+// interaction returns the variance of the joint effect of i and j, beyond
+// their main effects.
+func (a *importanceAnalysis) interaction(i, j int, effectI, effectJ []float64) float64 {
+	var residuals []float64
+
+	point := make([]float64, len(a.axes))
+
+	for ki, vi := range a.axes[i] {
+		for kj, vj := range a.axes[j] {
+			var joint float64
+
+			for _, sample := range a.samples {
+				copy(point, sample)
+
+				point[i], point[j] = vi, vj
+
+				joint += a.predict(point)
+			}
+
+			joint /= float64(len(a.samples))
+
+			residuals = append(residuals, joint-effectI[ki]-effectJ[kj])
+		}
+	}
+
+	return populationVariance(residuals)
+}
+
+//////
+// Helpers.
+//////
+
+// newImportanceAnalysis samples the points the predictions are averaged
+// over, uniformly or following the parameter priors if set, and the sweep
+// values of each parameter.
+//
+// Parameters:
+// - hypers: The parameter ranges
+// - predict: Predicts the objective at a point
+// - samples: Number of points the predictions are averaged over
+// - resolution: Maximum number of values each parameter is swept over
+// - rng: Draws the points
+func newImportanceAnalysis[T constraints.Integer | constraints.Float](
+	hypers []ParameterRange[T],
+	predict func(x []float64) float64,
+	samples, resolution int,
+	rng *rand.Rand,
+) *importanceAnalysis {
+	analysis := &importanceAnalysis{
+		predict: predict,
+		samples: make([][]float64, samples),
+		axes:    make([][]float64, len(hypers)),
+	}
+
+	for i := range analysis.samples {
+		params := make([]T, len(hypers))
+
+		for d, hyper := range hypers {
+			if hyper.Prior != nil {
+				params[d] = sampleFromPrior(rng, hyper)
+
+				continue
+			}
+
+			min, max := float64(hyper.Min), float64(hyper.Max)
+
+			params[d] = rangeValue(hyper, min+rng.Float64()*(max-min))
+		}
+
+		analysis.samples[i] = paramsToFloat64s(params)
+	}
+
+	for d, hyper := range hypers {
+		analysis.axes[d] = importanceAxis(hyper, resolution)
+	}
+
+	return analysis
+}
+
+// importanceAxis returns the sweep values of a parameter range: the centers
+// of resolution equal cells, so sweeps weigh the range evenly, or the whole
+// lattice if it's coarser.
+func importanceAxis[T constraints.Integer | constraints.Float](hyper ParameterRange[T], resolution int) []float64 {
+	if axis := gridAxis(hyper, resolution); len(axis) < resolution {
+		return axis
+	}
+
+	axis := make([]float64, 0, resolution)
+
+	for k := 0; k < resolution; k++ {
+		axis = appendAxisValue(axis, hyper, (float64(k)+0.5)/float64(resolution))
+	}
+
+	return axis
+}
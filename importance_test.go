@@ -0,0 +1,97 @@
+package ho
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// historyOf returns a result with n completed trials of f, at uniformly
+// random points of the unit cube.
+func historyOf(n, dims int, f func(x ...float64) float64) *Result[float64] {
+	rng := rand.New(rand.NewSource(42))
+
+	result := &Result[float64]{
+		ParamNames: []string{"x", "y", "z"}[:dims],
+		hypers:     make([]ParameterRange[float64], dims),
+	}
+
+	for d := range result.hypers {
+		result.hypers[d] = ParameterRange[float64]{Name: result.ParamNames[d], Min: 0, Max: 1}
+	}
+
+	for i := 0; i < n; i++ {
+		params := make([]float64, dims)
+
+		for d := range params {
+			params[d] = rng.Float64()
+		}
+
+		result.Trials = append(result.Trials, Trial[float64]{Params: params, ExecutionTime: f(params...), Status: TrialCompleted})
+	}
+
+	// Failed trials are ignored.
+	result.Trials = append(result.Trials, Trial[float64]{Params: make([]float64, dims), ExecutionTime: 1e9, Status: TrialFailed})
+
+	return result
+}
+
+func TestParameterImportance(t *testing.T) {
+	// z is irrelevant, x matters most.
+	result := historyOf(80, 3, func(x ...float64) float64 {
+		return 10*x[0] + 3*x[1]*x[1]
+	})
+
+	importance, err := result.ParameterImportance()
+
+	if !assert.NoError(t, err) || !assert.Len(t, importance.Scores, 3) {
+		return
+	}
+
+	x, y, z := importance.Scores[0], importance.Scores[1], importance.Scores[2]
+
+	assert.Equal(t, ImportanceScore{Dim: 2, Name: "z", Score: z.Score}, z)
+	assert.InDelta(t, 1, x.Score+y.Score+z.Score, 1e-9)
+	assert.Greater(t, x.Score, 0.7)
+	assert.Greater(t, y.Score, z.Score)
+	assert.Less(t, z.Score, 0.05)
+
+	// The function is additive.
+	assert.Len(t, importance.Interactions, 3)
+
+	for _, interaction := range importance.Interactions {
+		assert.Less(t, interaction.Strength, 0.1)
+	}
+
+	// Results are deterministic.
+	again, _ := result.ParameterImportance()
+
+	assert.Equal(t, importance, again)
+}
+
+func TestParameterImportanceInteractions(t *testing.T) {
+	// x and y only matter together.
+	result := historyOf(120, 3, func(x ...float64) float64 {
+		return 40 * (x[0] - 0.5) * (x[1] - 0.5)
+	})
+
+	importance, err := result.ParameterImportance()
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	strongest := importance.Interactions[0]
+
+	assert.Equal(t, [2]int{0, 1}, strongest.Dims)
+	assert.Equal(t, [2]string{"x", "y"}, strongest.Names)
+	assert.Greater(t, strongest.Strength, 0.3)
+	assert.Less(t, importance.Interactions[1].Strength, 0.1)
+}
+
+func TestParameterImportanceTooFewTrials(t *testing.T) {
+	_, err := historyOf(3, 2, func(x ...float64) float64 { return x[0] }).ParameterImportance()
+
+	assert.EqualError(t, err, "parameter importance needs at least 4 completed trials, got 3")
+}
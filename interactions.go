@@ -0,0 +1,177 @@
+package ho
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// DefaultInteractionSamples is the InteractionOptions.Samples used if
+	// unset.
+	DefaultInteractionSamples = 64
+
+	// DefaultInteractionResolution is the InteractionOptions.Resolution used
+	// if unset.
+	DefaultInteractionResolution = 10
+)
+
+// InteractionOptions sets the sampling budget of Result.Interactions. Each
+// pair of parameters costs up to Resolution² × Samples model predictions.
+type InteractionOptions struct {
+	// Samples is the number of points the predictions are averaged over.
+	// If 0, DefaultInteractionSamples is used.
+	Samples int
+
+	// Resolution is the maximum number of values each parameter is swept
+	// over.
+	// If 0, DefaultInteractionResolution is used.
+	Resolution int
+}
+
+// InteractionMatrix holds the interaction strength of every pair of
+// parameters, see Result.Interactions.
+type InteractionMatrix struct {
+	// Names holds the names of the parameters, in range order. Unnamed
+	// parameters are named "param<i>", as in TrialRecord.
+	Names []string `json:"names"`
+
+	// Strengths holds the interaction strengths, Strengths[i][j] being the
+	// strength of parameters i and j, as in InteractionScore. It's symmetric,
+	// with a zero diagonal.
+	Strengths [][]float64 `json:"strengths"`
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the options.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if an option is invalid, nil otherwise.
+func (o InteractionOptions) validate() error {
+	switch {
+	case o.Samples < 0:
+		return fmt.Errorf("%w: Samples %d is negative", ErrInvalidConfig, o.Samples)
+	case o.Resolution < 0 || o.Resolution == 1:
+		return fmt.Errorf("%w: Resolution %d must be 0 or at least 2", ErrInvalidConfig, o.Resolution)
+	}
+
+	return nil
+}
+
+// Pairs returns the pairs of parameters, strongest interaction first.
+func (m *InteractionMatrix) Pairs() []InteractionScore {
+	var pairs []InteractionScore
+
+	for i := range m.Strengths {
+		for j := i + 1; j < len(m.Strengths); j++ {
+			pairs = append(pairs, InteractionScore{
+				Dims:     [2]int{i, j},
+				Names:    [2]string{m.Names[i], m.Names[j]},
+				Strength: m.Strengths[i][j],
+			})
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Strength > pairs[j].Strength })
+
+	return pairs
+}
+
+// Interactions estimates how strongly each pair of parameters interacts,
+// from the model at the end of the run.
+//
+// Parameters:
+// - options: The sampling budget, zero values for the defaults
+//
+// Returns:
+// - *InteractionMatrix: The strengths, see InteractionMatrix.Pairs
+// - error: Wrapping ErrInvalidConfig if an option is invalid, or if the run
+// has no model.
+//
+// Usage example:
+//
+//	interactions, err := result.Interactions(InteractionOptions{})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	for _, pair := range interactions.Pairs()[:3] {
+//	    fmt.Printf("%s × %s: %.0f%%\n", pair.Names[0], pair.Names[1], pair.Strength*100)
+//	}
+//
+// Important notes:
+// - The strength of a pair is the variance of its joint partial dependence
+// beyond the sum of the main effects, relative to the total variance of the
+// predictions. Partial dependences are averaged over Samples points, drawn
+// as in ParameterImportance
+// - Unlike ParameterImportance, which fits a random forest on the trials
+// and only pairs the most important parameters, every pair is scored, on
+// the fitted surrogate model
+// - It's post-hoc and may be slow: raise the budget for smoother estimates,
+// lower it for many parameters or trials
+// - Results are deterministic for a given model and budget. If the model is
+// flat, all strengths are 0.
+func (r *Result[T]) Interactions(options InteractionOptions) (*InteractionMatrix, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	if r.model == nil || r.model.Len() == 0 || len(r.hypers) == 0 {
+		return nil, fmt.Errorf("%w: the run has no model to analyze", ErrInvalidConfig)
+	}
+
+	samples := options.Samples
+	if samples == 0 {
+		samples = DefaultInteractionSamples
+	}
+
+	resolution := options.Resolution
+	if resolution == 0 {
+		resolution = DefaultInteractionResolution
+	}
+
+	predict := func(x []float64) float64 {
+		mean, _ := rawPrediction(r.model, modelCoordinates(r.hypers, x))
+
+		return mean
+	}
+
+	analysis := newImportanceAnalysis(r.hypers, predict, samples, resolution, rand.New(rand.NewSource(importanceSeed)))
+
+	matrix := &InteractionMatrix{
+		Names:     make([]string, len(r.hypers)),
+		Strengths: make([][]float64, len(r.hypers)),
+	}
+
+	mainEffects := make([][]float64, len(r.hypers))
+
+	for d := range r.hypers {
+		matrix.Names[d] = paramName(r.ParamNames, d)
+		matrix.Strengths[d] = make([]float64, len(r.hypers))
+
+		mainEffects[d] = analysis.mainEffect(d)
+	}
+
+	total := analysis.totalVariance()
+
+	if total == 0 {
+		return matrix, nil
+	}
+
+	for i := range r.hypers {
+		for j := i + 1; j < len(r.hypers); j++ {
+			strength := analysis.interaction(i, j, mainEffects[i], mainEffects[j]) / total
+
+			matrix.Strengths[i][j], matrix.Strengths[j][i] = strength, strength
+		}
+	}
+
+	return matrix, nil
+}
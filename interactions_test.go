@@ -0,0 +1,101 @@
+package ho
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fittedModelOf returns a result whose model is fitted to f at n uniformly
+// random points of the unit cube.
+func fittedModelOf(n int, names []string, f func(x ...float64) float64) *Result[float64] {
+	rng := rand.New(rand.NewSource(42))
+
+	model := newGaussianProcess()
+
+	result := &Result[float64]{
+		ParamNames: names,
+		hypers:     make([]ParameterRange[float64], len(names)),
+		model:      model,
+	}
+
+	for d := range result.hypers {
+		result.hypers[d] = ParameterRange[float64]{Name: names[d], Min: 0, Max: 1}
+	}
+
+	for i := 0; i < n; i++ {
+		x := make([]float64, len(names))
+
+		for d := range x {
+			x[d] = rng.Float64()
+		}
+
+		model.Update(x, f(x...))
+	}
+
+	return result
+}
+
+func TestInteractions(t *testing.T) {
+	// x and y interact strongly, z and w are independent.
+	result := fittedModelOf(120, []string{"x", "y", "z", "w"}, func(x ...float64) float64 {
+		return 40*(x[0]-0.5)*(x[1]-0.5) + 3*x[2] + 2*x[3]
+	})
+
+	interactions, err := result.Interactions(InteractionOptions{})
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"x", "y", "z", "w"}, interactions.Names)
+
+	for i, row := range interactions.Strengths {
+		assert.Zero(t, row[i])
+
+		for j := range row {
+			assert.Equal(t, row[j], interactions.Strengths[j][i])
+		}
+	}
+
+	pairs := interactions.Pairs()
+
+	if !assert.Len(t, pairs, 6) {
+		return
+	}
+
+	assert.Equal(t, [2]int{0, 1}, pairs[0].Dims)
+	assert.Equal(t, [2]string{"x", "y"}, pairs[0].Names)
+	assert.Greater(t, pairs[0].Strength, 0.5)
+
+	for _, pair := range pairs[1:] {
+		assert.Less(t, pair.Strength, 0.01, pair.Names)
+	}
+
+	// Results are deterministic, and the budget is configurable.
+	again, _ := result.Interactions(InteractionOptions{})
+
+	assert.Equal(t, interactions, again)
+
+	small, err := result.Interactions(InteractionOptions{Samples: 8, Resolution: 3})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, [2]int{0, 1}, small.Pairs()[0].Dims)
+	}
+}
+
+func TestInteractionsInvalid(t *testing.T) {
+	result := fittedModelOf(10, []string{"x", "y"}, func(x ...float64) float64 { return x[0] })
+
+	for _, options := range []InteractionOptions{{Samples: -1}, {Resolution: 1}} {
+		_, err := result.Interactions(options)
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	}
+
+	// Without observations, there's nothing to analyze.
+	result.model = newGaussianProcess()
+
+	_, err := result.Interactions(InteractionOptions{})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
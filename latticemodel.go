@@ -0,0 +1,183 @@
+package ho
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// lattice is the set of coordinates a dimension of the points models see
+// takes: multiples of spacing, as coordinates are offsets from the Min of
+// the range (see modelPoint), or any coordinate if spacing is 0.
+type lattice struct {
+	// spacing is the Step of the range, at least 1 for integer ones, 0 for
+	// continuous ones.
+	spacing float64
+}
+
+// latticeModel is a SurrogateModel rounding the integer and stepped
+// dimensions of points to their lattice before they reach the model, on
+// updates and predictions alike. The model is then piecewise-constant along
+// them: it can't favor 7.4 once 7 and 8 are evaluated, as 7.4 is 7.
+//
+// Thread safety:
+// - All methods are safe for concurrent use, as long as the model's are.
+type latticeModel struct {
+	// lattices holds the lattice of each dimension, never modified.
+	lattices []lattice
+
+	// model is fed with the rounded points.
+	model SurrogateModel
+}
+
+//////
+// Methods.
+//////
+
+// round rounds v to the lattice, halfway cases up, as rangeValue does.
+func (l lattice) round(v float64) float64 {
+	if l.spacing <= 0 {
+		return v
+	}
+
+	return math.Floor(v/l.spacing+0.5) * l.spacing
+}
+
+// Update implements SurrogateModel.
+func (m *latticeModel) Update(x []float64, y float64) error {
+	return m.model.Update(onLattice(m.lattices, x), y)
+}
+
+// UpdateWeighted implements WeightedUpdater, weighting the observation if
+// the wrapped model does.
+func (m *latticeModel) UpdateWeighted(x []float64, y, weight float64) error {
+	if err := checkWeight(weight); err != nil {
+		return err
+	}
+
+	return updateWeighted(m.model, onLattice(m.lattices, x), y, weight)
+}
+
+// Predict implements SurrogateModel.
+func (m *latticeModel) Predict(x []float64) (mean, variance float64) {
+	return m.model.Predict(onLattice(m.lattices, x))
+}
+
+// PredictBatch implements BatchPredictor.
+func (m *latticeModel) PredictBatch(points [][]float64) (means, variances []float64) {
+	rounded := make([][]float64, len(points))
+
+	for i, x := range points {
+		rounded[i] = onLattice(m.lattices, x)
+	}
+
+	return predictBatch(m.model, rounded)
+}
+
+// Points implements SurrogateModel. Points are rounded to the lattices.
+func (m *latticeModel) Points() [][]float64 {
+	return m.model.Points()
+}
+
+// Len implements SurrogateModel.
+func (m *latticeModel) Len() int {
+	return m.model.Len()
+}
+
+// Clone implements SurrogateModel.
+func (m *latticeModel) Clone() SurrogateModel {
+	return &latticeModel{lattices: m.lattices, model: m.model.Clone()}
+}
+
+//////
+// Helpers.
+//////
+
+// latticeOf returns the lattice of the range's coordinates: multiples of
+// Step, or of 1 for integer ranges without one, float ones with an integer
+// Type included.
+func latticeOf[T constraints.Integer | constraints.Float](hyper ParameterRange[T]) lattice {
+	spacing := math.Max(float64(hyper.Step), 0)
+
+	if half := 0.5; T(half) == 0 || hyper.Type.integral() {
+		spacing = math.Max(spacing, 1)
+	}
+
+	return lattice{spacing: spacing}
+}
+
+// latticesOf returns the lattice of each range, see latticeOf.
+func latticesOf[T constraints.Integer | constraints.Float](hypers []ParameterRange[T]) []lattice {
+	lattices := make([]lattice, len(hypers))
+
+	for i, hyper := range hypers {
+		lattices[i] = latticeOf(hyper)
+	}
+
+	return lattices
+}
+
+// onLattice returns a copy of x, its coordinates rounded to their lattice.
+// Coordinates beyond the lattices, e.g. of a point with too many dimensions
+// for the model to reject, are copied as they are.
+func onLattice(lattices []lattice, x []float64) []float64 {
+	rounded := make([]float64, len(x))
+
+	for i, v := range x {
+		if i < len(lattices) {
+			v = lattices[i].round(v)
+		}
+
+		rounded[i] = v
+	}
+
+	return rounded
+}
+
+// pointKey returns a key identifying the point, to find the evaluated ones.
+func pointKey(x []float64) string {
+	var sb strings.Builder
+
+	for i, v := range x {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+
+	return sb.String()
+}
+
+//////
+// Factory.
+//////
+
+// newLatticeModel returns a model rounding points to the lattices of the
+// ranges, see latticeModel, or model itself if every range is continuous.
+//
+// Parameters:
+// - model: The model, nil if the Surrogate factory returned nil
+// - lattices: The lattice of each dimension, see latticeOf
+//
+// Returns:
+// - SurrogateModel: The model to feed with points.
+func newLatticeModel(model SurrogateModel, lattices []lattice) SurrogateModel {
+	if model == nil {
+		return nil
+	}
+
+	for _, l := range lattices {
+		if l.spacing > 0 {
+			return &latticeModel{lattices: lattices, model: model}
+		}
+	}
+
+	return model
+}
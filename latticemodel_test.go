@@ -0,0 +1,92 @@
+package ho
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatticeModel(t *testing.T) {
+	lattices := latticesOf([]ParameterRange[float64]{
+		{Min: 0, Max: 20, Type: IntParameter},
+		{Min: 0, Max: 1},
+		{Min: -1, Max: 1, Step: 0.5},
+	})
+
+	assert.Equal(t, []float64{7, 0.3, -0.5}, onLattice(lattices, []float64{7.4, 0.3, -0.3}))
+	assert.Equal(t, []float64{8, 0.5, 0}, onLattice(lattices, []float64{7.5, 0.5, -0.25}))
+
+	model := newLatticeModel(newGaussianProcess(), lattices)
+
+	assert.NoError(t, model.Update([]float64{7.2, 0.5, 0}, 1))
+	assert.NoError(t, model.Update([]float64{8, 0.5, 0}, 3))
+	assert.Equal(t, [][]float64{{7, 0.5, 0}, {8, 0.5, 0}}, model.Points())
+
+	// Piecewise-constant along the integer dimension.
+	mean, variance := model.Predict([]float64{7.4, 0.5, 0})
+	atSeven, atSevenVariance := model.Predict([]float64{7, 0.5, 0})
+
+	assert.Equal(t, atSeven, mean)
+	assert.Equal(t, atSevenVariance, variance)
+
+	means, _ := predictBatch(model.Clone(), [][]float64{{6.6, 0.5, 0}, {7.6, 0.5, 0.1}})
+
+	assert.Equal(t, atSeven, means[0])
+	assert.InDelta(t, 3, means[1], 0.01)
+
+	// Continuous spaces aren't wrapped.
+	gp := newGaussianProcess()
+
+	assert.Same(t, gp, newLatticeModel(gp, latticesOf([]ParameterRange[float64]{{Min: 0, Max: 1}})))
+	assert.Nil(t, newLatticeModel(nil, lattices))
+}
+
+func TestOptimizeIntegerNoDuplicates(t *testing.T) {
+	tests := []struct {
+		name  string
+		hyper ParameterRange[float64]
+	}{
+		{name: "int type", hyper: ParameterRange[float64]{Min: 0, Max: 60, Type: IntParameter}},
+		{name: "stepped", hyper: ParameterRange[float64]{Min: 0, Max: 30, Step: 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := fastConfig()
+			config.Seed = 1
+			config.Iterations = 40
+
+			benchmarked := map[float64]int{}
+
+			result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+				benchmarked[params[0]]++
+
+				return math.Pow(params[0]-17, 2), nil
+			}, tt.hyper)
+
+			assert.NoError(t, result.Err)
+			assert.Len(t, benchmarked, config.InitialSamples+config.Iterations)
+
+			for v, n := range benchmarked {
+				assert.Equal(t, 1, n, "%v benchmarked %d times", v, n)
+			}
+		})
+	}
+
+	// Integer types too.
+	seen := map[int]int{}
+
+	config := fastConfig()
+	config.Seed = 1
+	config.Iterations = 40
+
+	result := OptimizeObjective(config, func(params ...int) (float64, error) {
+		seen[params[0]]++
+
+		return math.Abs(float64(params[0] - 17)), nil
+	}, ParameterRange[int]{Min: -30, Max: 30})
+
+	assert.NoError(t, result.Err)
+	assert.Len(t, seen, config.InitialSamples+config.Iterations)
+}
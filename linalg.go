@@ -0,0 +1,211 @@
+package ho
+
+import "math"
+
+//////
+// Const, vars, types.
+//////
+
+// substitutionBlock is the number of right-hand sides forward substituted
+// together by forwardSubstituteBatch.
+const substitutionBlock = 32
+
+// cholesky is the lower triangular Cholesky factor L of a symmetric positive
+// definite matrix A = L L^T. Factors are never modified once computed, so
+// they can be shared between snapshots of a model.
+//
+// The Gaussian process uses gonum's, unless built with the nogonum tag, in
+// which case it uses the pure-Go rowCholesky.
+type cholesky interface {
+	// size returns the order n of the matrix.
+	size() int
+
+	// at returns L[i][j].
+	at(i, j int) float64
+
+	// extend returns the factor of the matrix extended by a row and column,
+	// column holding the n off-diagonal entries then the diagonal one, in
+	// O(n^2). It returns false if the extended matrix isn't positive
+	// definite.
+	extend(column []float64) (cholesky, bool)
+
+	// solve solves L v = b for each b, of n entries.
+	solve(bs [][]float64) [][]float64
+}
+
+// rowCholesky is a pure-Go cholesky, storing the rows of L, row i having
+// i+1 entries.
+type rowCholesky [][]float64
+
+//////
+// Methods.
+//////
+
+// size implements cholesky.
+func (l rowCholesky) size() int {
+	return len(l)
+}
+
+// at implements cholesky.
+func (l rowCholesky) at(i, j int) float64 {
+	if j > i {
+		return 0
+	}
+
+	return l[i][j]
+}
+
+// extend implements cholesky.
+//
+// Mathematical details:
+// - The factor of A extended by the column c and the diagonal entry d is L
+// extended by the row l = L^-1 c, and the diagonal entry sqrt(d - l^T l).
+func (l rowCholesky) extend(column []float64) (cholesky, bool) {
+	n := len(l)
+
+	row := append(forwardSubstitute(l, column[:n]), 0)
+
+	diagonal := column[n]
+
+	for j := 0; j < n; j++ {
+		diagonal -= row[j] * row[j]
+	}
+
+	if !(diagonal > 0) {
+		return nil, false
+	}
+
+	row[n] = math.Sqrt(diagonal)
+
+	// The full slice expression copies the rows, so l is left untouched.
+	return append(l[:n:n], row), true
+}
+
+// solve implements cholesky.
+func (l rowCholesky) solve(bs [][]float64) [][]float64 {
+	return forwardSubstituteBatch(l, bs)
+}
+
+//////
+// Helpers.
+//////
+
+// emptyCholesky returns the cholesky of a 0×0 matrix, to extend.
+func emptyCholesky() cholesky {
+	chol, _ := newCholesky(0, nil)
+
+	return chol
+}
+
+// factorizeRows computes the rowCholesky of the n×n symmetric matrix whose
+// lower triangle entries are given by entry, with the Cholesky-Crout
+// algorithm, in O(n^3).
+//
+// Returns:
+// - rowCholesky: The factor
+// - bool: False if the matrix isn't positive definite.
+func factorizeRows(n int, entry func(i, j int) float64) (rowCholesky, bool) {
+	l := make(rowCholesky, n)
+
+	for i := range l {
+		l[i] = make([]float64, i+1)
+
+		for j := range l[i] {
+			l[i][j] = entry(i, j)
+		}
+	}
+
+	// Column by column, in place of the lower triangle of the matrix.
+	for j := 0; j < n; j++ {
+		diagonal := l[j][j]
+
+		for k := 0; k < j; k++ {
+			diagonal -= l[j][k] * l[j][k]
+		}
+
+		if !(diagonal > 0) {
+			return nil, false
+		}
+
+		l[j][j] = math.Sqrt(diagonal)
+
+		for i := j + 1; i < n; i++ {
+			sum := l[i][j]
+
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+
+			l[i][j] = sum / l[j][j]
+		}
+	}
+
+	return l, true
+}
+
+// forwardSubstitute solves L v = b for v, L being lower triangular and
+// stored by rows, as rowCholesky. Only the first len(b) rows of L are used.
+func forwardSubstitute(l [][]float64, b []float64) []float64 {
+	v := make([]float64, len(b))
+
+	for i := range v {
+		sum := b[i]
+
+		for j := 0; j < i; j++ {
+			sum -= l[i][j] * v[j]
+		}
+
+		v[i] = sum / l[i][i]
+	}
+
+	return v
+}
+
+// forwardSubstituteBatch solves L v = b for each b, as forwardSubstitute
+// does, with the same floating-point operations. Right-hand sides are solved
+// in blocks, row by row, so each row of L is read once per block, and four
+// at a time, so their independent sums are computed in parallel.
+func forwardSubstituteBatch(l [][]float64, bs [][]float64) [][]float64 {
+	vs := make([][]float64, len(bs))
+
+	for c, b := range bs {
+		vs[c] = make([]float64, len(b))
+	}
+
+	for start := 0; start < len(bs); start += substitutionBlock {
+		end := min(start+substitutionBlock, len(bs))
+
+		for i := range bs[start] {
+			row := l[i][:i]
+			pivot := l[i][i]
+
+			c := start
+
+			for ; c+4 <= end; c += 4 {
+				v0, v1, v2, v3 := vs[c][:i], vs[c+1][:i], vs[c+2][:i], vs[c+3][:i]
+				s0, s1, s2, s3 := bs[c][i], bs[c+1][i], bs[c+2][i], bs[c+3][i]
+
+				for j, r := range row {
+					s0 -= r * v0[j]
+					s1 -= r * v1[j]
+					s2 -= r * v2[j]
+					s3 -= r * v3[j]
+				}
+
+				vs[c][i], vs[c+1][i], vs[c+2][i], vs[c+3][i] = s0/pivot, s1/pivot, s2/pivot, s3/pivot
+			}
+
+			for ; c < end; c++ {
+				v, sum := vs[c][:i], bs[c][i]
+
+				for j, r := range row {
+					sum -= r * v[j]
+				}
+
+				vs[c][i] = sum / pivot
+			}
+		}
+	}
+
+	return vs
+}
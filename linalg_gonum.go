@@ -0,0 +1,148 @@
+//go:build !nogonum
+
+package ho
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/mat"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// gonumCholesky is a cholesky backed by gonum: factorizations by
+// mat.Cholesky, and solves by BLAS triangular solves.
+type gonumCholesky struct {
+	// lower holds L, row-major.
+	lower blas64.Triangular
+}
+
+//////
+// Methods.
+//////
+
+// size implements cholesky.
+func (g gonumCholesky) size() int {
+	return g.lower.N
+}
+
+// at implements cholesky.
+func (g gonumCholesky) at(i, j int) float64 {
+	if j > i {
+		return 0
+	}
+
+	return g.lower.Data[i*g.lower.Stride+j]
+}
+
+// extend implements cholesky. L is copied, as it's shared between snapshots,
+// and the new row l = L^-1 c solved in place, as for rowCholesky.
+func (g gonumCholesky) extend(column []float64) (cholesky, bool) {
+	n := g.lower.N
+
+	extended := newLower(n + 1)
+
+	for i := 0; i < n; i++ {
+		copy(extended.Data[i*extended.Stride:], g.lower.Data[i*g.lower.Stride:i*g.lower.Stride+i+1])
+	}
+
+	row := extended.Data[n*extended.Stride : n*extended.Stride+n]
+
+	copy(row, column[:n])
+
+	diagonal := column[n]
+
+	if n > 0 {
+		blas64.Trsv(blas.NoTrans, g.lower, blas64.Vector{N: n, Inc: 1, Data: row})
+
+		diagonal -= blas64.Dot(blas64.Vector{N: n, Inc: 1, Data: row}, blas64.Vector{N: n, Inc: 1, Data: row})
+	}
+
+	if !(diagonal > 0) {
+		return nil, false
+	}
+
+	extended.Data[n*extended.Stride+n] = math.Sqrt(diagonal)
+
+	return gonumCholesky{lower: extended}, true
+}
+
+// solve implements cholesky, solving X L^T = B for all right-hand sides at
+// once, B holding them as rows. Each row is solved with dot products against
+// the rows of L, the same way whatever the number of rows.
+func (g gonumCholesky) solve(bs [][]float64) [][]float64 {
+	n, m := g.lower.N, len(bs)
+
+	x := blas64.General{Rows: m, Cols: n, Stride: max(n, 1), Data: make([]float64, m*n)}
+
+	for c, b := range bs {
+		copy(x.Data[c*n:(c+1)*n], b)
+	}
+
+	if n > 0 && m > 0 {
+		blas64.Trsm(blas.Right, blas.Trans, 1, g.lower, x)
+	}
+
+	vs := make([][]float64, m)
+
+	for c := range vs {
+		vs[c] = x.Data[c*n : (c+1)*n : (c+1)*n]
+	}
+
+	return vs
+}
+
+//////
+// Helpers.
+//////
+
+// newLower returns a zero n×n lower triangular matrix.
+func newLower(n int) blas64.Triangular {
+	return blas64.Triangular{
+		Uplo:   blas.Lower,
+		Diag:   blas.NonUnit,
+		N:      n,
+		Stride: max(n, 1),
+		Data:   make([]float64, n*n),
+	}
+}
+
+//////
+// Factory.
+//////
+
+// newCholesky computes the cholesky of the n×n symmetric matrix whose lower
+// triangle entries are given by entry, in O(n^3).
+//
+// Returns:
+// - cholesky: The factor
+// - bool: False if the matrix isn't positive definite.
+func newCholesky(n int, entry func(i, j int) float64) (cholesky, bool) {
+	if n == 0 {
+		return gonumCholesky{lower: newLower(0)}, true
+	}
+
+	sym := mat.NewSymDense(n, nil)
+
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sym.SetSym(i, j, entry(i, j))
+		}
+	}
+
+	var factor mat.Cholesky
+
+	if !factor.Factorize(sym) {
+		return nil, false
+	}
+
+	var lower mat.TriDense
+
+	factor.LTo(&lower)
+
+	return gonumCholesky{lower: lower.RawTriangular()}, true
+}
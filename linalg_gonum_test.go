@@ -0,0 +1,139 @@
+//go:build !nogonum
+
+package ho
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// kernelEntry returns the entries of the kernel matrix of n random points of
+// [0, 5]^3, with the noise on the diagonal.
+func kernelEntry(rng *rand.Rand, n int) func(i, j int) float64 {
+	points := make([][]float64, n)
+
+	for i := range points {
+		points[i] = randomPoint(rng, 3)
+	}
+
+	return func(i, j int) float64 {
+		if i == j {
+			return 1 + jitters[0]
+		}
+
+		return rbfKernel(points[i], points[j], 1)
+	}
+}
+
+func TestGonumCholeskyParity(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 20} {
+		rng := rand.New(rand.NewSource(int64(n)))
+		entry := kernelEntry(rng, n)
+
+		rows, ok := factorizeRows(n, entry)
+
+		assert.True(t, ok)
+
+		factor, ok := newCholesky(n, entry)
+
+		if !assert.True(t, ok) {
+			continue
+		}
+
+		assertSameFactor(t, rows, factor, 1e-12)
+
+		// Extending from the empty factor, row by row.
+		extended := emptyCholesky()
+
+		for i := 0; i < n; i++ {
+			column := make([]float64, i+1)
+
+			for j := range column {
+				column[j] = entry(i, j)
+			}
+
+			extended, ok = extended.extend(column)
+
+			assert.True(t, ok)
+		}
+
+		assertSameFactor(t, rows, extended, 1e-12)
+
+		bs := [][]float64{make([]float64, n), make([]float64, n), make([]float64, n)}
+
+		for _, b := range bs {
+			for i := range b {
+				b[i] = rng.NormFloat64()
+			}
+		}
+
+		expected := rows.solve(bs)
+
+		for c, v := range factor.solve(bs) {
+			assert.InDeltaSlice(t, expected[c], v, 1e-9)
+		}
+	}
+
+	// Indefinite matrices are reported.
+	_, ok := newCholesky(2, func(i, j int) float64 {
+		if i == j {
+			return 1
+		}
+
+		return 2
+	})
+
+	assert.False(t, ok)
+
+	_, ok = emptyCholesky().extend([]float64{-1})
+
+	assert.False(t, ok)
+}
+
+// BenchmarkCholesky compares gonum to the pure-Go fallback, for factorizing
+// a kernel matrix of n observations, and solving for 500 candidates.
+func BenchmarkCholesky(b *testing.B) {
+	for _, n := range []int{300, 1000} {
+		entry := kernelEntry(rand.New(rand.NewSource(1)), n)
+
+		rows, _ := factorizeRows(n, entry)
+		factor, _ := newCholesky(n, entry)
+
+		bs := make([][]float64, 500)
+
+		for c := range bs {
+			bs[c] = make([]float64, n)
+
+			for i := range bs[c] {
+				bs[c][i] = entry(i, (i+c)%n)
+			}
+		}
+
+		b.Run(fmt.Sprintf("factorize/gonum/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				newCholesky(n, entry)
+			}
+		})
+
+		b.Run(fmt.Sprintf("factorize/purego/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				factorizeRows(n, entry)
+			}
+		})
+
+		b.Run(fmt.Sprintf("solve/gonum/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				factor.solve(bs)
+			}
+		})
+
+		b.Run(fmt.Sprintf("solve/purego/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rows.solve(bs)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+//go:build nogonum
+
+package ho
+
+//////
+// Factory.
+//////
+
+// newCholesky computes the cholesky of the n×n symmetric matrix whose lower
+// triangle entries are given by entry, in O(n^3).
+//
+// Returns:
+// - cholesky: The factor
+// - bool: False if the matrix isn't positive definite.
+func newCholesky(n int, entry func(i, j int) float64) (cholesky, bool) {
+	l, ok := factorizeRows(n, entry)
+	if !ok {
+		return nil, false
+	}
+
+	return l, true
+}
@@ -0,0 +1,209 @@
+package ho
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultGateMaxWait is the LoadGate.MaxWait used if unset.
+	defaultGateMaxWait = time.Minute
+
+	// defaultGateBackoff is the LoadGate.Backoff used if unset.
+	defaultGateBackoff = 100 * time.Millisecond
+
+	// procLoadAverage is the file ProcLoadAverage reads by default.
+	procLoadAverage = "/proc/loadavg"
+)
+
+// ReadyFunc reports whether the system is ready for a measurement, see
+// LoadGate.
+//
+// Parameters:
+// - ctx: The run context
+//
+// Returns:
+// - error: Why the system isn't ready, e.g. it's busy, nil if it's ready.
+type ReadyFunc func(ctx context.Context) error
+
+// LoadGate configures a gate before each trial: on shared runners, a timing
+// sensitive benchmark started while the system is busy yields a garbage
+// observation, so the run waits for the system to be ready first.
+//
+// Important notes:
+// - Ready is called before each trial, and again with exponential backoff
+// while it returns an error, up to MaxWait. The trial then starts anyway,
+// flagged with Trial.UnderLoad, and a warning is recorded in
+// Result.Warnings
+// - Time spent waiting isn't part of the trial Duration
+// - Canceling the run context ends the wait, and the trial is recorded as
+// canceled without invoking the benchmark
+// - Cached trials aren't gated, see OptimizationConfig.CacheEvaluations
+// - Ready must be safe for concurrent use if
+// OptimizationConfig.MaxConcurrentEvaluations is greater than 1.
+type LoadGate struct {
+	// Ready reports whether the system is ready, e.g. LoadBelow.
+	Ready ReadyFunc
+
+	// MaxWait is the longest a trial waits for the system to be ready.
+	// If 0, 1 minute is used.
+	MaxWait time.Duration
+
+	// Backoff is the wait before calling Ready again, doubled after every
+	// call.
+	// If 0, 100ms is used.
+	Backoff time.Duration
+}
+
+// LoadReader reads the load of the system, see LoadBelow.
+type LoadReader interface {
+	// Load returns the current load of the system.
+	Load() (float64, error)
+}
+
+// ProcLoadAverage is a LoadReader reading the 1-minute load average of Linux
+// systems.
+type ProcLoadAverage struct {
+	// Path is the file to read, in the format of /proc/loadavg.
+	// If empty, /proc/loadavg is read.
+	Path string
+}
+
+//////
+// Methods.
+//////
+
+// Load implements LoadReader.
+func (p ProcLoadAverage) Load() (float64, error) {
+	path := p.Path
+	if path == "" {
+		path = procLoadAverage
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%s: no load average", path)
+	}
+
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return load, nil
+}
+
+// validate checks the settings.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a setting is invalid, nil otherwise.
+func (g *LoadGate) validate() error {
+	switch {
+	case g.Ready == nil:
+		return fmt.Errorf("%w: LoadGate.Ready is required", ErrInvalidConfig)
+	case g.MaxWait < 0:
+		return fmt.Errorf("%w: LoadGate.MaxWait %v is negative", ErrInvalidConfig, g.MaxWait)
+	case g.Backoff < 0:
+		return fmt.Errorf("%w: LoadGate.Backoff %v is negative", ErrInvalidConfig, g.Backoff)
+	}
+
+	return nil
+}
+
+// awaitReady waits for the system to be ready before a trial, see LoadGate.
+//
+// Parameters:
+// - trialID: The trial about to start
+//
+// Returns:
+// - bool: True if the system still wasn't ready after LoadGate.MaxWait
+// - error: The error of the run context if it was done while waiting, nil
+// otherwise.
+func (o *optimizer[T]) awaitReady(trialID int) (bool, error) {
+	gate := o.config.LoadGate
+
+	if gate == nil {
+		return false, nil
+	}
+
+	maxWait := gate.MaxWait
+	if maxWait == 0 {
+		maxWait = defaultGateMaxWait
+	}
+
+	backoff := gate.Backoff
+	if backoff == 0 {
+		backoff = defaultGateBackoff
+	}
+
+	deadline := time.Now().Add(maxWait)
+
+	for {
+		err := gate.Ready(o.ctx)
+		if err == nil {
+			return false, nil
+		}
+
+		remaining := time.Until(deadline)
+
+		if remaining <= 0 {
+			o.warnf("trial %d started under load after waiting %v: %v", trialID, maxWait, err)
+
+			return true, nil
+		}
+
+		if err := o.sleep(o.ctx, min(backoff, remaining)); err != nil {
+			return false, err
+		}
+
+		backoff *= 2
+	}
+}
+
+//////
+// Factory.
+//////
+
+// LoadBelow returns a ReadyFunc reporting the system ready while its load is
+// below max.
+//
+// Parameters:
+// - reader: Reads the load, e.g. ProcLoadAverage
+// - max: The load from which the system is busy
+//
+// Returns:
+// - ReadyFunc: The ready function. Load read failures are reported as not
+// ready.
+//
+// Usage example:
+//
+//	config.LoadGate = &ho.LoadGate{
+//	    Ready: ho.LoadBelow(ho.ProcLoadAverage{}, float64(runtime.NumCPU())/2),
+//	}
+func LoadBelow(reader LoadReader, max float64) ReadyFunc {
+	return func(ctx context.Context) error {
+		load, err := reader.Load()
+		if err != nil {
+			return err
+		}
+
+		if load >= max {
+			return fmt.Errorf("load %g isn't below %g", load, max)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+package ho
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLoad is a LoadReader returning a fixed load.
+type fakeLoad struct {
+	load float64
+	err  error
+}
+
+// Load implements LoadReader.
+func (f fakeLoad) Load() (float64, error) {
+	return f.load, f.err
+}
+
+func TestLoadGate(t *testing.T) {
+	ranges := []ParameterRange[int]{{Min: 1, Max: 100}}
+
+	busy := errors.New("busy")
+
+	t.Run("wait", func(t *testing.T) {
+		var calls int
+
+		// The system is busy for the first 3 checks.
+		config := fastConfig()
+		config.LoadGate = &LoadGate{
+			Ready: func(ctx context.Context) error {
+				calls++
+
+				if calls <= 3 {
+					return busy
+				}
+
+				return nil
+			},
+			Backoff: 20 * time.Millisecond,
+		}
+
+		result := Optimize(config, func(params ...int) error { return nil }, ranges...)
+
+		assert.NoError(t, result.Err)
+		assert.Empty(t, result.Warnings)
+		assert.Equal(t, 3+len(result.Trials), calls)
+
+		// The wait of the first trial isn't part of its duration.
+		assert.Less(t, result.Trials[0].Duration, 50*time.Millisecond)
+
+		for _, trial := range result.Trials {
+			assert.False(t, trial.UnderLoad)
+		}
+	})
+
+	t.Run("under load", func(t *testing.T) {
+		config := fastConfig()
+		config.LoadGate = &LoadGate{
+			Ready:   func(ctx context.Context) error { return busy },
+			MaxWait: 5 * time.Millisecond,
+			Backoff: time.Millisecond,
+		}
+
+		result := Optimize(config, func(params ...int) error { return nil }, ranges...)
+
+		assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+		assert.Len(t, result.Warnings, len(result.Trials))
+		assert.Equal(t, "trial 1 started under load after waiting 5ms: busy", result.Warnings[0])
+
+		for _, trial := range result.Trials {
+			assert.True(t, trial.UnderLoad)
+			assert.Equal(t, TrialCompleted, trial.Status)
+			assert.True(t, NewTrialRecord(trial, nil).UnderLoad)
+		}
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		config := fastConfig()
+		config.LoadGate = &LoadGate{
+			Ready: func(ctx context.Context) error {
+				cancel()
+
+				return busy
+			},
+			Backoff: time.Hour,
+		}
+
+		var invoked bool
+
+		result := OptimizeWithContext(ctx, config, func(ctx context.Context, params ...int) error {
+			invoked = true
+
+			return nil
+		}, ranges...)
+
+		assert.False(t, invoked)
+		assert.Equal(t, TerminationContextCanceled, result.TerminationReason)
+
+		if assert.Len(t, result.Trials, 1) {
+			assert.Equal(t, TrialCanceled, result.Trials[0].Status)
+			assert.ErrorIs(t, result.Trials[0].Err, context.Canceled)
+		}
+	})
+
+	t.Run("load below", func(t *testing.T) {
+		ctx := context.Background()
+
+		assert.NoError(t, LoadBelow(fakeLoad{load: 1.5}, 2)(ctx))
+		assert.EqualError(t, LoadBelow(fakeLoad{load: 2}, 2)(ctx), "load 2 isn't below 2")
+		assert.ErrorIs(t, LoadBelow(fakeLoad{err: busy}, 2)(ctx), busy)
+
+		path := filepath.Join(t.TempDir(), "loadavg")
+
+		assert.NoError(t, os.WriteFile(path, []byte("0.52 0.58 0.59 1/467 12345\n"), 0o600))
+
+		load, err := ProcLoadAverage{Path: path}.Load()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.52, load)
+
+		assert.NoError(t, os.WriteFile(path, nil, 0o600))
+
+		_, err = ProcLoadAverage{Path: path}.Load()
+
+		assert.ErrorContains(t, err, "no load average")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, gate := range []LoadGate{{}, {Ready: LoadBelow(fakeLoad{}, 1), MaxWait: -1}, {Ready: LoadBelow(fakeLoad{}, 1), Backoff: -1}} {
+			config := fastConfig()
+			config.LoadGate = &gate
+
+			result := Optimize(config, func(params ...int) error { return nil }, ranges...)
+
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+		}
+	})
+}
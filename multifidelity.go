@@ -0,0 +1,518 @@
+package ho
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// PhaseLowFidelity is the TrialInfo.Phase of the cheap evaluations of a
+	// multi-fidelity run, initial samples aside, see OptimizeMultiFidelity.
+	PhaseLowFidelity = "LowFidelity"
+
+	// PhaseHighFidelity is the TrialInfo.Phase of the expensive evaluations
+	// of a multi-fidelity run, see OptimizeMultiFidelity.
+	PhaseHighFidelity = "HighFidelity"
+
+	// DefaultInitialHighSamples is the MultiFidelity.InitialHighSamples used
+	// if unset.
+	DefaultInitialHighSamples = 2
+
+	// DefaultPromotionStdDevs is the MultiFidelity.PromotionStdDevs used if
+	// unset.
+	DefaultPromotionStdDevs = 1.0
+)
+
+// MultiFidelity configures a run evaluating a cheap proxy of the objective,
+// e.g. a shorter benchmark or a smaller dataset, and the objective itself
+// only where the proxy is promising, see OptimizeMultiFidelity.
+type MultiFidelity struct {
+	// CostRatio is the cost of a high-fidelity evaluation in low-fidelity
+	// ones, e.g. 10 if the proxy runs for 6s and the objective for 1m. It
+	// must be greater than 1.
+	CostRatio float64
+
+	// Budget is the cost the run may spend, in low-fidelity evaluations: a
+	// low-fidelity evaluation costs 1, a high-fidelity one CostRatio. It
+	// replaces OptimizationConfig.Iterations.
+	Budget float64
+
+	// InitialHighSamples is the number of high-fidelity evaluations of the
+	// best initial samples, to fit the correction from.
+	// If 0, DefaultInitialHighSamples is used.
+	InitialHighSamples int
+
+	// PromotionStdDevs is how optimistic promotions are: a point is promoted
+	// if its low-fidelity value, corrected by the predicted mean minus
+	// PromotionStdDevs standard deviations of the residual, beats the best
+	// high-fidelity value.
+	// If 0, DefaultPromotionStdDevs is used.
+	PromotionStdDevs float64
+}
+
+// fidelityState tracks a multi-fidelity run.
+type fidelityState struct {
+	// config is the configuration, defaults applied.
+	config MultiFidelity
+
+	// model predicts the high-fidelity objective, see fidelityModel.
+	model *fidelityModel
+
+	// lows holds the completed low-fidelity values, by point. Protected by
+	// the optimizer's mu.
+	lows map[string]float64
+}
+
+// fidelityModel is a SurrogateModel of the high-fidelity objective: the sum
+// of a model of the low-fidelity objective and a model of the residual, the
+// high-fidelity value minus the low-fidelity one at the same point.
+//
+// Important notes:
+// - Predictions sum the means and the variances of the models, as if they
+// were independent. Until the residual is observed, they're the
+// low-fidelity ones
+// - Update isn't supported: each model is updated on its own
+// - Points and Len are the low-fidelity model's, the points candidates are
+// picked among.
+//
+// Thread safety:
+// - All methods are safe for concurrent use, as long as the models' are.
+type fidelityModel struct {
+	// low models the low-fidelity objective.
+	low SurrogateModel
+
+	// residual models the high-fidelity value minus the low-fidelity one.
+	residual SurrogateModel
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the configuration against the run.
+//
+// Parameters:
+// - config: The configuration of the run
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a setting is invalid, or the run
+// uses an unsupported option, nil otherwise.
+func (m MultiFidelity) validate(config OptimizationConfig) error {
+	switch {
+	case !(m.CostRatio > 1) || math.IsInf(m.CostRatio, 0):
+		return fmt.Errorf("%w: MultiFidelity: CostRatio %v must be finite and greater than 1", ErrInvalidConfig, m.CostRatio)
+	case m.InitialHighSamples < 0:
+		return fmt.Errorf("%w: MultiFidelity: InitialHighSamples %d is negative", ErrInvalidConfig, m.InitialHighSamples)
+	case m.PromotionStdDevs < 0 || math.IsNaN(m.PromotionStdDevs):
+		return fmt.Errorf("%w: MultiFidelity: PromotionStdDevs %v is negative", ErrInvalidConfig, m.PromotionStdDevs)
+	case config.InitialSamples < 0:
+		return fmt.Errorf("%w: InitialSamples %d is negative", ErrInvalidConfig, config.InitialSamples)
+	}
+
+	// The initial evaluations must fit in the budget.
+	if initial := float64(config.InitialSamples) + float64(m.initialHighSamples())*m.CostRatio; !(m.Budget >= initial) {
+		return fmt.Errorf("%w: MultiFidelity: Budget %v is less than the %v cost units of the initial evaluations", ErrInvalidConfig, m.Budget, initial)
+	}
+
+	return checkFidelityOptions(config, "a multi-fidelity run")
+}
+
+// initialHighSamples returns InitialHighSamples, or its default if unset.
+func (m MultiFidelity) initialHighSamples() int {
+	if m.InitialHighSamples == 0 {
+		return DefaultInitialHighSamples
+	}
+
+	return m.InitialHighSamples
+}
+
+// promotionStdDevs returns PromotionStdDevs, or its default if unset.
+func (m MultiFidelity) promotionStdDevs() float64 {
+	if m.PromotionStdDevs == 0 {
+		return DefaultPromotionStdDevs
+	}
+
+	return m.PromotionStdDevs
+}
+
+// Update implements SurrogateModel. It's not supported, see fidelityModel.
+func (m *fidelityModel) Update([]float64, float64) error {
+	return fmt.Errorf("%w: multi-fidelity models are updated per fidelity", ErrInvalidObservation)
+}
+
+// Predict implements SurrogateModel.
+func (m *fidelityModel) Predict(x []float64) (mean, variance float64) {
+	mean, variance = m.low.Predict(x)
+
+	if m.residual.Len() > 0 {
+		residualMean, residualVariance := m.residual.Predict(x)
+
+		mean, variance = mean+residualMean, variance+residualVariance
+	}
+
+	return mean, variance
+}
+
+// PredictBatch implements BatchPredictor.
+func (m *fidelityModel) PredictBatch(points [][]float64) (means, variances []float64) {
+	means, variances = predictBatch(m.low, points)
+
+	if m.residual.Len() > 0 {
+		residualMeans, residualVariances := predictBatch(m.residual, points)
+
+		for i := range points {
+			means[i] += residualMeans[i]
+			variances[i] += residualVariances[i]
+		}
+	}
+
+	return means, variances
+}
+
+// Points implements SurrogateModel. Points are the low-fidelity ones.
+func (m *fidelityModel) Points() [][]float64 {
+	return m.low.Points()
+}
+
+// Len implements SurrogateModel.
+func (m *fidelityModel) Len() int {
+	return m.low.Len()
+}
+
+// Clone implements SurrogateModel.
+func (m *fidelityModel) Clone() SurrogateModel {
+	return &fidelityModel{low: m.low.Clone(), residual: m.residual.Clone()}
+}
+
+// checkFidelityOptions checks that the run doesn't use options assuming
+// values of a single fidelity, e.g. from a cache or a checkpoint.
+//
+// Parameters:
+// - config: The configuration of the run
+// - run: The kind of run, for error messages
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if an unsupported option is set, nil
+// otherwise.
+func checkFidelityOptions(config OptimizationConfig, run string) error {
+	unsupported := map[string]bool{
+		"WarmStart":             len(config.WarmStart) > 0,
+		"Checkpoint":            config.Checkpoint != nil,
+		"CacheEvaluations":      config.CacheEvaluations,
+		"DriftSentinel":         config.DriftSentinel != nil,
+		"SurpriseRemeasure":     config.SurpriseRemeasure != nil,
+		"IncumbentReevaluation": config.IncumbentReevaluation != nil,
+		"Safety":                config.Safety != nil,
+		"KnownOptimum":          config.KnownOptimum != nil,
+		"OutputTransform":       config.OutputTransform != "" && config.OutputTransform != OutputRaw,
+	}
+
+	for _, option := range []string{
+		"WarmStart", "Checkpoint", "CacheEvaluations", "DriftSentinel", "SurpriseRemeasure", "IncumbentReevaluation", "Safety", "KnownOptimum", "OutputTransform",
+	} {
+		if unsupported[option] {
+			return fmt.Errorf("%w: %s: not supported in %s", ErrInvalidConfig, option, run)
+		}
+	}
+
+	return nil
+}
+
+// highFidelity returns whether trials of the phase evaluate the
+// high-fidelity objective. Only initial samples and low-fidelity trials
+// don't: the profiling run of the best parameters, see ProfileBest, does.
+func highFidelity(phase string) bool {
+	return phase != PhaseInitialSampling && phase != PhaseLowFidelity
+}
+
+// fidelityCost returns the cost spent so far, in low-fidelity evaluations,
+// and the number of evaluations of each fidelity. The caller must hold mu.
+//
+// Returns:
+// - float64: The cost spent
+// - int: Number of low-fidelity evaluations, initial samples included
+// - int: Number of high-fidelity evaluations.
+func (o *optimizer[T]) fidelityCost() (float64, int, int) {
+	var low, high int
+
+	for _, trial := range o.trials {
+		if highFidelity(trial.Phase) {
+			high++
+		} else {
+			low++
+		}
+	}
+
+	return float64(low) + float64(high)*o.fidelity.config.CostRatio, low, high
+}
+
+// affordable returns whether an evaluation of the given cost fits in the
+// remaining budget.
+func (o *optimizer[T]) affordable(cost float64) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	spent, _, _ := o.fidelityCost()
+
+	return spent+cost <= o.fidelity.config.Budget
+}
+
+// recordFidelity feeds a recorded trial of a multi-fidelity run to the model
+// of its fidelity. Only high-fidelity trials can be the best.
+//
+// Parameters:
+// - trial: The recorded trial.
+//
+// Important notes:
+// - Only completed trials are fed: failures never reach the models,
+// whatever FailedTrials
+// - High-fidelity trials are fed to the residual model, as the difference to
+// the low-fidelity value at their point.
+func (o *optimizer[T]) recordFidelity(trial Trial[T]) {
+	if trial.Status != TrialCompleted {
+		return
+	}
+
+	x := o.modelPoint(trial.Params)
+
+	key := pointKey(x)
+
+	if !highFidelity(trial.Phase) {
+		if err := updateWeighted(o.fidelity.model.low, x, trial.ExecutionTime, trial.Weight); err != nil {
+			o.warnf("trial %d not fed to the model: %v", trial.TrialID, err)
+
+			return
+		}
+
+		o.mu.Lock()
+		o.fidelity.lows[key] = trial.ExecutionTime
+		o.observations = append(o.observations, Observation{Params: x, Value: trial.ExecutionTime, Weight: trial.Weight})
+		o.mu.Unlock()
+
+		return
+	}
+
+	o.mu.Lock()
+	low, ok := o.fidelity.lows[key]
+	o.mu.Unlock()
+
+	if ok {
+		if err := updateWeighted(o.fidelity.model.residual, x, trial.ExecutionTime-low, trial.Weight); err != nil {
+			o.warnf("trial %d not fed to the model: %v", trial.TrialID, err)
+		}
+	}
+
+	previous, improved := o.updateBest(trial.Params, trial.ExecutionTime)
+
+	if improved {
+		o.mu.Lock()
+		o.bestTrialID = trial.TrialID
+		o.mu.Unlock()
+
+		o.newBest(trial, previous)
+	}
+}
+
+// promote returns whether a completed low-fidelity trial deserves a
+// high-fidelity evaluation: it's affordable, and the optimistic prediction
+// of its high-fidelity value beats the best one.
+func (o *optimizer[T]) promote(trial Trial[T]) bool {
+	if trial.Status != TrialCompleted || !o.affordable(o.fidelity.config.CostRatio) {
+		return false
+	}
+
+	o.mu.Lock()
+	best := o.bestTime
+	o.mu.Unlock()
+
+	residual := o.fidelity.model.residual
+
+	if best == math.MaxFloat64 || residual.Len() == 0 {
+		return true
+	}
+
+	mean, variance := residual.Predict(o.modelPoint(trial.Params))
+
+	return trial.ExecutionTime+mean-o.fidelity.config.promotionStdDevs()*math.Sqrt(math.Max(variance, 0)) < best
+}
+
+// bestLowTrials returns up to n completed low-fidelity trials, best first,
+// at distinct points.
+func (o *optimizer[T]) bestLowTrials(n int) []Trial[T] {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var trials []Trial[T]
+
+	seen := make(map[string]bool)
+
+	for _, trial := range o.trials {
+		key := pointKey(o.modelPoint(trial.Params))
+
+		if highFidelity(trial.Phase) || trial.Status != TrialCompleted || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		trials = append(trials, trial)
+	}
+
+	sort.SliceStable(trials, func(i, j int) bool { return trials[i].ExecutionTime < trials[j].ExecutionTime })
+
+	return trials[:min(n, len(trials))]
+}
+
+// runFidelities runs the evaluations of a multi-fidelity run, until the
+// budget is spent or the run is done, see OptimizeMultiFidelity.
+func (o *optimizer[T]) runFidelities() {
+	config := o.fidelity.config
+
+	highs := int(config.Budget / config.CostRatio)
+
+	o.runInitialSampling()
+
+	// The correction is first fitted at the most promising initial samples.
+	for i, trial := range o.bestLowTrials(config.initialHighSamples()) {
+		if !o.proceed() || !o.affordable(config.CostRatio) {
+			return
+		}
+
+		params := trial.Params
+
+		o.runTrial(PhaseHighFidelity, i+1, highs, func() ([]T, int) { return params, 0 })
+	}
+
+	for iteration := 1; o.affordable(1) && o.proceed(); iteration++ {
+		trial := o.runTrial(PhaseLowFidelity, iteration, int(config.Budget), func() ([]T, int) {
+			return o.nextCandidate(o.model, iteration)
+		})
+
+		if !o.promote(trial) || !o.proceed() {
+			continue
+		}
+
+		params := trial.Params
+
+		o.runTrial(PhaseHighFidelity, iteration, highs, func() ([]T, int) { return params, 0 })
+	}
+}
+
+//////
+// Exported functionalities.
+//////
+
+// OptimizeMultiFidelity minimizes an expensive objective with the help of a
+// cheap, possibly noisy and biased, proxy: most evaluations are spent on the
+// proxy, and only the most promising points are evaluated with the
+// objective itself.
+//
+// Parameters:
+// - ctx: Run context, each trial context is derived from it
+// - config: OptimizationConfig controlling the optimization process
+// - fidelity: The cost ratio and the budget, see MultiFidelity
+// - low: The cheap proxy of the objective
+// - high: The objective, whose value you want to minimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *Result[T]: The outcome of the run. Result.Trials holds the evaluations
+// of both fidelities, high-fidelity ones with Phase PhaseHighFidelity
+//
+// Usage example:
+//
+//	fidelity := MultiFidelity{CostRatio: 10, Budget: 200}
+//
+//	result := OptimizeMultiFidelity(ctx, config, fidelity, func(ctx context.Context, params ...int64) (float64, error) {
+//	    return runWorkload(ctx, 6*time.Second, params...)
+//	}, func(ctx context.Context, params ...int64) (float64, error) {
+//	    return runWorkload(ctx, time.Minute, params...)
+//	}, ranges...)
+//
+// Important notes:
+// - The InitialSamples random points are evaluated with low, then the best
+// InitialHighSamples of them with high. Then each iteration evaluates the
+// most promising candidate with low, and promotes it to high if the
+// low-fidelity value, corrected by the predicted residual, may beat the best
+// high-fidelity value, see MultiFidelity.PromotionStdDevs
+// - The residual, the high-fidelity value minus the low-fidelity one, is
+// modeled by its own surrogate, so the correction may vary across the
+// space. Candidates are scored on the sum of both models, which
+// Result.Predict and Result.PredictGrid predict too
+// - Only high-fidelity values can be the best, i.e. Result.BestTime and
+// Result.BestParams come from a high-fidelity trial
+// - The run completes once the next low-fidelity evaluation doesn't fit in
+// MultiFidelity.Budget, Iterations is ignored. TimeBudget, Stop and the run
+// context end it earlier, as usual
+// - Both fidelities are evaluated in the Setup and Teardown hooks, which
+// tell them apart by TrialInfo.Phase: PhaseInitialSampling and
+// PhaseLowFidelity for low, PhaseHighFidelity for high. ProfileBest
+// profiles high
+// - WarmStart, Checkpoint, CacheEvaluations, DriftSentinel,
+// SurpriseRemeasure, Safety, KnownOptimum and output transforms aren't
+// supported.
+func OptimizeMultiFidelity[T constraints.Integer | constraints.Float](
+	ctx context.Context,
+	config OptimizationConfig,
+	fidelity MultiFidelity,
+	low, high ObjectiveFuncCtx[T],
+	hypers ...ParameterRange[T],
+) *Result[T] {
+	o := newOptimizer[T](ctx, config, nil, hypers...)
+
+	if err := fidelity.validate(config); err != nil {
+		o.invalidErr = err
+
+		return o.result()
+	}
+
+	o.objectiveFunc = func(ctx context.Context, info TrialInfo, params ...T) (float64, error) {
+		if highFidelity(info.Phase) {
+			return high(ctx, params...)
+		}
+
+		return low(ctx, params...)
+	}
+
+	o.fidelity = newFidelityState(fidelity, surrogateFactory(config, hypers))
+
+	// A nil model is reported by run, as for other runs.
+	if o.fidelity.model != nil {
+		o.model = o.fidelity.model
+	}
+
+	return o.run()
+}
+
+//////
+// Factory.
+//////
+
+// newFidelityState creates the state of a multi-fidelity run.
+//
+// Parameters:
+// - config: The configuration, validated
+// - newModel: Creates the models of each fidelity
+//
+// Returns:
+// - *fidelityState: The state, its model nil if newModel returns nil.
+func newFidelityState(config MultiFidelity, newModel func() SurrogateModel) *fidelityState {
+	config.InitialHighSamples = config.initialHighSamples()
+	config.PromotionStdDevs = config.promotionStdDevs()
+
+	state := &fidelityState{config: config, lows: make(map[string]float64)}
+
+	if low, residual := newModel(), newModel(); low != nil && residual != nil {
+		state.model = &fidelityModel{low: low, residual: residual}
+	}
+
+	return state
+}
@@ -0,0 +1,150 @@
+package ho
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// trueObjective is minimal at (0.3, 0.7), where it's 0.
+func trueObjective(_ context.Context, params ...float64) (float64, error) {
+	x, y := params[0], params[1]
+
+	return 10 * ((x-0.3)*(x-0.3) + (y-0.7)*(y-0.7)), nil
+}
+
+// cheapProxy returns a noisy, biased version of trueObjective: scaled,
+// offset, tilted along x, and noisy, so its optimum is off the true one.
+func cheapProxy(seed int64) ObjectiveFuncCtx[float64] {
+	var mu sync.Mutex
+
+	rng := rand.New(rand.NewSource(seed))
+
+	return func(ctx context.Context, params ...float64) (float64, error) {
+		value, _ := trueObjective(ctx, params...)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		return 0.8*value + 2 + params[0] + 0.1*rng.NormFloat64(), nil
+	}
+}
+
+func TestOptimizeMultiFidelity(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Name: "x", Min: 0, Max: 1}, {Name: "y", Min: 0, Max: 1}}
+
+	fidelity := MultiFidelity{CostRatio: 10, Budget: 200}
+
+	for seed := int64(1); seed <= 3; seed++ {
+		config := DefaultConfig()
+		config.Seed = seed
+		config.InitialSamples = 10
+
+		result := OptimizeMultiFidelity(context.Background(), config, fidelity, cheapProxy(seed), trueObjective, ranges...)
+
+		if !assert.NoError(t, result.Err) {
+			return
+		}
+
+		assert.Equal(t, TerminationCompleted, result.TerminationReason)
+
+		var low, high int
+
+		var best *Trial[float64]
+
+		for i, trial := range result.Trials {
+			if trial.Phase == PhaseHighFidelity {
+				high++
+
+				if best == nil || trial.ExecutionTime < best.ExecutionTime {
+					best = &result.Trials[i]
+				}
+			} else {
+				low++
+			}
+		}
+
+		// Most evaluations are cheap, and the budget is never exceeded.
+		assert.Greater(t, low, 2*high)
+		assert.GreaterOrEqual(t, high, DefaultInitialHighSamples+1)
+		assert.LessOrEqual(t, float64(low)+fidelity.CostRatio*float64(high), fidelity.Budget)
+		assert.Contains(t, result.TerminationDetail, "high-fidelity evaluations")
+
+		// Only high-fidelity values are eligible, and the best is close to
+		// the true optimum.
+		if assert.NotNil(t, best) {
+			assert.Equal(t, best.ExecutionTime, result.BestTime)
+			assert.Equal(t, best.Params, result.BestParams)
+		}
+
+		assert.Less(t, result.BestTime, 0.05)
+
+		// The model predicts the high-fidelity objective, correction included.
+		mean, _, err := result.Predict([]float64{0.3, 0.7})
+
+		if assert.NoError(t, err) {
+			assert.InDelta(t, 0, mean, 1)
+		}
+	}
+}
+
+func TestOptimizeMultiFidelityPhases(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 1}}
+
+	var mu sync.Mutex
+
+	phases := map[string]int{}
+
+	config := DefaultConfig()
+	config.Seed = 1
+	config.InitialSamples = 4
+	config.Setup = func(info TrialInfo, _ ...float64) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		phases[info.Phase]++
+
+		return nil
+	}
+
+	objective := func(_ context.Context, params ...float64) (float64, error) {
+		return math.Abs(params[0] - 0.5), nil
+	}
+
+	result := OptimizeMultiFidelity(context.Background(), config, MultiFidelity{CostRatio: 5, Budget: 30}, objective, objective, ranges...)
+
+	assert.NoError(t, result.Err)
+
+	assert.Equal(t, 4, phases[PhaseInitialSampling])
+	assert.GreaterOrEqual(t, phases[PhaseHighFidelity], DefaultInitialHighSamples)
+	assert.Positive(t, phases[PhaseLowFidelity])
+	assert.Equal(t, len(result.Trials), phases[PhaseInitialSampling]+phases[PhaseLowFidelity]+phases[PhaseHighFidelity])
+}
+
+func TestOptimizeMultiFidelityInvalid(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 1}}
+
+	config := DefaultConfig()
+	config.InitialSamples = 5
+
+	for _, fidelity := range []MultiFidelity{
+		{CostRatio: 1, Budget: 100},
+		{CostRatio: math.Inf(1), Budget: 100},
+		{CostRatio: 10, Budget: 100, InitialHighSamples: -1},
+		{CostRatio: 10, Budget: 100, PromotionStdDevs: -1},
+		{CostRatio: 10, Budget: 20},
+	} {
+		result := OptimizeMultiFidelity(context.Background(), config, fidelity, trueObjective, trueObjective, ranges...)
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig, fidelity)
+		assert.Empty(t, result.Trials)
+	}
+
+	config.CacheEvaluations = true
+
+	result := OptimizeMultiFidelity(context.Background(), config, MultiFidelity{CostRatio: 10, Budget: 100}, trueObjective, trueObjective, ranges...)
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+}
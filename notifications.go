@@ -0,0 +1,391 @@
+package ho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// DefaultNotificationTimeout bounds each delivery attempt, unless
+	// Notifications.Timeout is set.
+	DefaultNotificationTimeout = 10 * time.Second
+
+	// DefaultNotificationBackoff is the delay before the first retry, unless
+	// Notifications.RetryBackoff is set.
+	DefaultNotificationBackoff = time.Second
+
+	// DefaultNotificationDrainTimeout bounds how long a terminated run waits
+	// for pending notifications, unless Notifications.DrainTimeout is set.
+	DefaultNotificationDrainTimeout = 30 * time.Second
+
+	// notificationQueueSize is the number of notifications that can wait for
+	// delivery. Notifications are dropped, and counted as failures, beyond.
+	notificationQueueSize = 64
+)
+
+// NotificationEvent is the kind of event a notification is sent for.
+type NotificationEvent string
+
+const (
+	// EventNewBest is sent whenever a trial improves on the best result.
+	EventNewBest NotificationEvent = "newBest"
+
+	// EventRunCompleted is sent when the run terminates, whatever the reason.
+	EventRunCompleted NotificationEvent = "runCompleted"
+)
+
+// Notifications configures webhook notifications: a JSON Notification is
+// POSTed to URL on new bests and run completion, e.g. to a chat webhook.
+//
+// Important notes:
+// - Delivery happens on a separate goroutine, so it never blocks the
+// optimization. Once the run terminates, pending notifications are delivered
+// before the result is returned, for up to DrainTimeout
+// - Deliveries are bound to the run context: canceling it abandons pending
+// notifications, the completion one included
+// - Deliveries failing after all retries, or abandoned, are logged, and
+// counted in Result.NotificationFailures
+// - Only optimization runs notify, not the ask/tell Optimizer.
+type Notifications struct {
+	// URL receives the notifications.
+	URL string
+
+	// Headers are added to every request, e.g. an authorization token.
+	Headers map[string]string
+
+	// Timeout bounds each delivery attempt.
+	// If 0, DefaultNotificationTimeout is used.
+	Timeout time.Duration
+
+	// MaxRetries is how many times a failed delivery is retried. Any non-2xx
+	// response is a failure.
+	// If 0, failed deliveries aren't retried.
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubled for each
+	// subsequent one.
+	// If 0, DefaultNotificationBackoff is used.
+	RetryBackoff time.Duration
+
+	// DrainTimeout bounds how long the terminated run waits for pending
+	// notifications, retries included, before abandoning them.
+	// If 0, DefaultNotificationDrainTimeout is used.
+	DrainTimeout time.Duration
+
+	// ErrorLog logs delivery failures.
+	// If nil, the standard logger is used.
+	ErrorLog *log.Logger
+}
+
+// Notification is the JSON payload of webhook notifications.
+type Notification struct {
+	// Event is the kind of event.
+	Event NotificationEvent `json:"event"`
+
+	// Time is when the event happened.
+	Time time.Time `json:"time"`
+
+	// Trial is the new best trial, for EventNewBest.
+	Trial *TrialRecord `json:"trial,omitempty"`
+
+	// ImprovementPercent is the improvement over the previous best, in percent
+	// of it, for EventNewBest. Nil for the first best.
+	ImprovementPercent *float64 `json:"improvementPercent,omitempty"`
+
+	// Summary summarizes the run, for EventRunCompleted.
+	Summary *RunSummary `json:"summary,omitempty"`
+}
+
+// RunSummary summarizes a terminated run.
+type RunSummary struct {
+	// TerminationReason describes why the run ended.
+	TerminationReason TerminationReason `json:"terminationReason"`
+
+	// TerminationDetail details the reason, see Result.TerminationDetail.
+	TerminationDetail string `json:"terminationDetail,omitempty"`
+
+	// Error is the error that terminated the run early, if any.
+	Error string `json:"error,omitempty"`
+
+	// Trials is the number of trials, skipped ones included.
+	Trials int `json:"trials"`
+
+	// Completed is the number of completed trials.
+	Completed int `json:"completed"`
+
+	// Best is the best trial, nil if none completed.
+	Best *TrialRecord `json:"best"`
+}
+
+// notifier delivers notifications in the background.
+type notifier struct {
+	// config configures delivery.
+	config Notifications
+
+	// client sends the requests.
+	client *http.Client
+
+	// ctx bounds deliveries, derived from the run context.
+	ctx context.Context
+
+	// cancel abandons pending deliveries.
+	cancel context.CancelFunc
+
+	// queue holds the notifications waiting for delivery.
+	queue chan Notification
+
+	// wg tracks the delivery goroutine.
+	wg sync.WaitGroup
+
+	// failures counts the notifications that couldn't be delivered.
+	failures atomic.Int64
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the notifications configuration.
+func (n *Notifications) validate() error {
+	switch {
+	case n.URL == "":
+		return fmt.Errorf("%w: Notifications.URL is required", ErrInvalidConfig)
+	case n.Timeout < 0:
+		return fmt.Errorf("%w: Notifications.Timeout %v is negative", ErrInvalidConfig, n.Timeout)
+	case n.MaxRetries < 0:
+		return fmt.Errorf("%w: Notifications.MaxRetries %d is negative", ErrInvalidConfig, n.MaxRetries)
+	case n.RetryBackoff < 0:
+		return fmt.Errorf("%w: Notifications.RetryBackoff %v is negative", ErrInvalidConfig, n.RetryBackoff)
+	case n.DrainTimeout < 0:
+		return fmt.Errorf("%w: Notifications.DrainTimeout %v is negative", ErrInvalidConfig, n.DrainTimeout)
+	}
+
+	return nil
+}
+
+// notify queues a notification for delivery, without blocking.
+func (n *notifier) notify(notification Notification) {
+	select {
+	case n.queue <- notification:
+	default:
+		n.failures.Add(1)
+
+		n.logf("dropping %s notification, too many pending", notification.Event)
+	}
+}
+
+// close delivers the pending notifications, for up to the drain timeout,
+// abandons the others, and stops the delivery goroutine.
+//
+// Returns:
+// - int: The number of notifications that couldn't be delivered.
+func (n *notifier) close() int {
+	close(n.queue)
+
+	done := make(chan struct{})
+
+	go func() {
+		n.wg.Wait()
+
+		close(done)
+	}()
+
+	timer := time.NewTimer(n.config.DrainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		n.logf("abandoning pending notifications after %v", n.config.DrainTimeout)
+
+		// Pending deliveries fail right away.
+		n.cancel()
+
+		<-done
+	}
+
+	n.cancel()
+
+	return int(n.failures.Load())
+}
+
+// loop delivers queued notifications until the queue is closed.
+func (n *notifier) loop() {
+	defer n.wg.Done()
+
+	for notification := range n.queue {
+		if err := n.deliver(notification); err != nil {
+			n.failures.Add(1)
+
+			n.logf("delivering %s notification: %v", notification.Event, err)
+		}
+	}
+}
+
+// deliver POSTs a notification, retrying with backoff.
+func (n *notifier) deliver(notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	backoff := n.config.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err = n.post(body)
+		if err == nil || attempt == n.config.MaxRetries || n.ctx.Err() != nil {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-timer.C:
+		case <-n.ctx.Done():
+			timer.Stop()
+
+			return err
+		}
+
+		backoff *= 2
+	}
+}
+
+// post sends a single delivery attempt.
+func (n *notifier) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(n.ctx, n.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range n.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// logf logs a delivery issue.
+func (n *notifier) logf(format string, args ...any) {
+	logger := n.config.ErrorLog
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	logger.Printf("ho: notifications: "+format, args...)
+}
+
+//////
+// Helpers.
+//////
+
+// improvementPercent returns the improvement of current over previous, in
+// percent of previous. Nil if there's no previous best, i.e. previous is
+// unset or a failure penalty, or zero.
+func improvementPercent(previous, current float64) *float64 {
+	if previous >= math.MaxFloat64/2 || previous == 0 {
+		return nil
+	}
+
+	improvement := (previous - current) / math.Abs(previous) * 100
+
+	return &improvement
+}
+
+// newRunSummary summarizes a terminated run.
+func newRunSummary[T constraints.Integer | constraints.Float](result *Result[T]) *RunSummary {
+	summary := &RunSummary{
+		TerminationReason: result.TerminationReason,
+		TerminationDetail: result.TerminationDetail,
+		Trials:            len(result.Trials),
+	}
+
+	if result.Err != nil {
+		summary.Error = result.Err.Error()
+	}
+
+	for _, record := range result.Records() {
+		if record.Value == nil {
+			continue
+		}
+
+		summary.Completed++
+
+		if summary.Best == nil || *record.Value < *summary.Best.Value {
+			summary.Best = &record
+		}
+	}
+
+	return summary
+}
+
+//////
+// Factory.
+//////
+
+// newNotifier creates a notifier, and starts its delivery goroutine.
+//
+// Parameters:
+// - ctx: Run context, deliveries are abandoned once it's canceled
+// - config: Notifications configuration.
+func newNotifier(ctx context.Context, config Notifications) *notifier {
+	if config.Timeout == 0 {
+		config.Timeout = DefaultNotificationTimeout
+	}
+
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = DefaultNotificationBackoff
+	}
+
+	if config.DrainTimeout == 0 {
+		config.DrainTimeout = DefaultNotificationDrainTimeout
+	}
+
+	n := &notifier{
+		config: config,
+		client: &http.Client{},
+		queue:  make(chan Notification, notificationQueueSize),
+	}
+
+	n.ctx, n.cancel = context.WithCancel(ctx)
+
+	n.wg.Add(1)
+
+	go n.loop()
+
+	return n
+}
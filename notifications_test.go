@@ -0,0 +1,214 @@
+package ho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// webhook records the notifications it receives. The first failFirst
+// requests, or all of them if failFirst is negative, are answered with a 500.
+type webhook struct {
+	mu            sync.Mutex
+	notifications []Notification
+	requests      atomic.Int32
+	failFirst     int32
+}
+
+func (w *webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	n := w.requests.Add(1)
+
+	if w.failFirst < 0 || n <= w.failFirst {
+		rw.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	var notification Notification
+
+	if err := json.NewDecoder(r.Body).Decode(&notification); err != nil || r.Header.Get("Authorization") != "Bearer token" {
+		rw.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	w.mu.Lock()
+	w.notifications = append(w.notifications, notification)
+	w.mu.Unlock()
+}
+
+// notifyingConfig returns a configuration notifying the given server.
+func notifyingConfig(url string) OptimizationConfig {
+	config := fastConfig()
+
+	config.Notifications = &Notifications{
+		URL:          url,
+		Headers:      map[string]string{"Authorization": "Bearer token"},
+		Timeout:      time.Second,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	}
+
+	return config
+}
+
+func TestNotifications(t *testing.T) {
+	hook := &webhook{}
+
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	result := OptimizeObjective(notifyingConfig(server.URL), func(params ...float64) (float64, error) {
+		return params[0] * params[0], nil
+	}, ParameterRange[float64]{Name: "x", Min: -10, Max: 10})
+
+	assert.NoError(t, result.Err)
+	assert.Equal(t, 0, result.NotificationFailures)
+
+	if !assert.GreaterOrEqual(t, len(hook.notifications), 2) {
+		return
+	}
+
+	// New bests, in order, then the completion.
+	bests := hook.notifications[:len(hook.notifications)-1]
+
+	for i, notification := range bests {
+		assert.Equal(t, EventNewBest, notification.Event)
+		assert.Contains(t, notification.Trial.Params, "x")
+
+		if i == 0 {
+			assert.Nil(t, notification.ImprovementPercent)
+
+			continue
+		}
+
+		previous, current := *bests[i-1].Trial.Value, *notification.Trial.Value
+
+		assert.Less(t, current, previous)
+		assert.InDelta(t, (previous-current)/previous*100, *notification.ImprovementPercent, 1e-9)
+	}
+
+	completion := hook.notifications[len(hook.notifications)-1]
+
+	assert.Equal(t, EventRunCompleted, completion.Event)
+	assert.Equal(t, TerminationCompleted, completion.Summary.TerminationReason)
+	assert.Equal(t, len(result.Trials), completion.Summary.Trials)
+	assert.Equal(t, len(result.Trials), completion.Summary.Completed)
+	assert.Equal(t, result.BestTime, *completion.Summary.Best.Value)
+	assert.Equal(t, bests[len(bests)-1].Trial, completion.Summary.Best)
+}
+
+func TestNotificationsRetries(t *testing.T) {
+	t.Run("flaky", func(t *testing.T) {
+		hook := &webhook{failFirst: 2}
+
+		server := httptest.NewServer(hook)
+		defer server.Close()
+
+		result := OptimizeObjective(notifyingConfig(server.URL), func(params ...float64) (float64, error) {
+			return params[0], nil
+		}, ParameterRange[float64]{Min: 0, Max: 1})
+
+		// The first notification is delivered on its last attempt.
+		assert.Equal(t, 0, result.NotificationFailures)
+		assert.Equal(t, EventNewBest, hook.notifications[0].Event)
+		assert.Contains(t, hook.notifications[0].Trial.Params, "param0")
+		assert.Equal(t, int32(len(hook.notifications)+2), hook.requests.Load())
+	})
+
+	t.Run("down", func(t *testing.T) {
+		hook := &webhook{failFirst: -1}
+
+		server := httptest.NewServer(hook)
+		defer server.Close()
+
+		var logs bytes.Buffer
+
+		config := notifyingConfig(server.URL)
+		config.Notifications.ErrorLog = log.New(&logs, "", 0)
+
+		result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+			return params[0], nil
+		}, ParameterRange[float64]{Min: 0, Max: 1})
+
+		// Failures don't affect the run.
+		assert.NoError(t, result.Err)
+		assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+		assert.Positive(t, result.NotificationFailures)
+		assert.Equal(t, int32(result.NotificationFailures*3), hook.requests.Load())
+		assert.Contains(t, logs.String(), "delivering runCompleted notification: unexpected status 500")
+	})
+}
+
+func TestNotificationsDrain(t *testing.T) {
+	// hanging never answers before the test ends.
+	release := make(chan struct{})
+
+	hanging := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}))
+
+	defer hanging.Close()
+	defer close(release)
+
+	objective := func(params ...float64) (float64, error) {
+		return params[0], nil
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		config := notifyingConfig(hanging.URL)
+		config.Notifications.Timeout = time.Minute
+		config.Notifications.DrainTimeout = 50 * time.Millisecond
+		config.Notifications.ErrorLog = log.New(&bytes.Buffer{}, "", 0)
+
+		start := time.Now()
+
+		result := OptimizeObjective(config, objective, ParameterRange[float64]{Min: 0, Max: 1})
+
+		assert.NoError(t, result.Err)
+		assert.Less(t, time.Since(start), 10*time.Second)
+		assert.Positive(t, result.NotificationFailures)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		config := notifyingConfig(hanging.URL)
+		config.Notifications.Timeout = time.Minute
+		config.Notifications.DrainTimeout = time.Minute
+		config.Notifications.ErrorLog = log.New(&bytes.Buffer{}, "", 0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		start := time.Now()
+
+		// The completion notification is abandoned with the run.
+		result := OptimizeObjectiveWithContext(ctx, config, func(_ context.Context, params ...float64) (float64, error) {
+			cancel()
+
+			return params[0], nil
+		}, ParameterRange[float64]{Min: 0, Max: 1})
+
+		assert.Equal(t, TerminationContextCanceled, result.TerminationReason)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+}
+
+func TestNotificationsInvalidConfig(t *testing.T) {
+	config := notifyingConfig("")
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return params[0], nil
+	}, ParameterRange[float64]{Min: 0, Max: 1})
+
+	assert.Equal(t, TerminationInvalidConfig, result.TerminationReason)
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+}
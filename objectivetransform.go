@@ -0,0 +1,67 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Methods.
+//////
+
+// transformObjective applies OptimizationConfig.ObjectiveTransform to the
+// value of a completed trial, keeping the measured one in MeasuredValue. A
+// failing transform fails the trial.
+//
+// Parameters:
+// - trial: The trial, updated in place.
+func (o *optimizer[T]) transformObjective(trial *Trial[T]) {
+	if o.config.ObjectiveTransform == nil || trial.Status != TrialCompleted {
+		return
+	}
+
+	measured := trial.ExecutionTime
+
+	trial.MeasuredValue = &measured
+
+	value, err := o.objectiveValue(measured, trial.Params)
+	if err != nil {
+		trial.Status = TrialFailed
+		trial.ExecutionTime = math.MaxFloat64 / 2
+		trial.Err = err
+
+		return
+	}
+
+	trial.ExecutionTime = value
+}
+
+// objectiveValue returns the value minimized for a measured one, see
+// OptimizationConfig.ObjectiveTransform.
+//
+// Parameters:
+// - measured: The measured value
+// - params: The parameters it was measured with
+//
+// Returns:
+// - float64: The transformed value, measured if there's no transform
+// - error: Wrapping ErrObjectiveTransform if the transform failed, or
+// returned a non-finite value.
+func (o *optimizer[T]) objectiveValue(measured float64, params []T) (float64, error) {
+	if o.config.ObjectiveTransform == nil {
+		return measured, nil
+	}
+
+	params, _ = o.benchmarkParams(params)
+
+	value, err := o.config.ObjectiveTransform(measured, paramsToFloat64s(params))
+
+	switch {
+	case err != nil:
+		return 0, fmt.Errorf("%w: %w", ErrObjectiveTransform, err)
+	case math.IsNaN(value) || math.IsInf(value, 0):
+		return 0, fmt.Errorf("%w: %w %v", ErrObjectiveTransform, ErrNonFiniteValue, value)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,150 @@
+package ho
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// overhead is the fixed overhead the objectives below measure, and the
+// transforms subtract.
+const overhead = 1000
+
+func TestObjectiveTransform(t *testing.T) {
+	config := fastConfig()
+
+	var calls int
+
+	config.ObjectiveTransform = func(measured float64, params []float64) (float64, error) {
+		calls++
+
+		// The transform gets the parameters of the measurement.
+		assert.Equal(t, measured, math.Pow(params[0]-5, 2)+overhead)
+
+		return measured - overhead, nil
+	}
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return math.Pow(params[0]-5, 2) + overhead, nil
+	}, ParameterRange[float64]{Name: "x", Min: 0, Max: 10})
+
+	assert.NoError(t, result.Err)
+	assert.Equal(t, config.InitialSamples+config.Iterations, calls)
+
+	phases := map[string]int{}
+
+	for _, trial := range result.Trials {
+		phases[trial.Phase]++
+
+		// Both values are kept, the transformed one is minimized.
+		if assert.NotNil(t, trial.MeasuredValue) {
+			assert.Equal(t, *trial.MeasuredValue-overhead, trial.ExecutionTime)
+		}
+
+		assert.Less(t, trial.ExecutionTime, float64(overhead))
+	}
+
+	assert.Equal(t, map[string]int{PhaseInitialSampling: config.InitialSamples, PhaseOptimization: config.Iterations}, phases)
+	assert.Less(t, result.BestTime, float64(overhead))
+
+	// Exports keep both.
+	record := result.Records()[0]
+
+	if assert.NotNil(t, record.Value) && assert.NotNil(t, record.MeasuredValue) {
+		assert.Equal(t, *record.MeasuredValue-overhead, *record.Value)
+	}
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, ExportOptunaJSON(&buf, "transform", result, ParameterRange[float64]{Name: "x", Min: 0, Max: 10}))
+
+	var study optunaStudy
+
+	if assert.NoError(t, json.Unmarshal(buf.Bytes(), &study)) {
+		assert.Equal(t, *record.MeasuredValue, study.Trials[0].UserAttrs[optunaMeasuredAttr])
+	}
+}
+
+func TestObjectiveTransformFailure(t *testing.T) {
+	config := fastConfig()
+
+	// Seeded, so some trials complete and others fail.
+	config.Seed = 1
+
+	errCeiling := errors.New("above the ceiling")
+
+	config.ObjectiveTransform = func(measured float64, _ []float64) (float64, error) {
+		switch {
+		case measured > 20:
+			return 0, errCeiling
+		case measured > 10:
+			return math.Inf(1), nil
+		}
+
+		return measured, nil
+	}
+
+	result := OptimizeObjective(config, func(params ...int) (float64, error) {
+		return float64(params[0]), nil
+	}, ParameterRange[int]{Min: 0, Max: 30})
+
+	var failed int
+
+	for _, trial := range result.Trials {
+		if assert.NotNil(t, trial.MeasuredValue) {
+			assert.Equal(t, float64(trial.Params[0]), *trial.MeasuredValue)
+		}
+
+		switch {
+		case trial.Params[0] > 20:
+			assert.ErrorIs(t, trial.Err, ErrObjectiveTransform)
+			assert.ErrorIs(t, trial.Err, errCeiling)
+		case trial.Params[0] > 10:
+			assert.ErrorIs(t, trial.Err, ErrObjectiveTransform)
+			assert.ErrorIs(t, trial.Err, ErrNonFiniteValue)
+		default:
+			assert.Equal(t, TrialCompleted, trial.Status)
+
+			continue
+		}
+
+		failed++
+
+		// Failures are penalized, and never the best.
+		assert.Equal(t, TrialFailed, trial.Status)
+		assert.Equal(t, math.MaxFloat64/2, trial.ExecutionTime)
+	}
+
+	assert.Positive(t, failed)
+	assert.LessOrEqual(t, result.BestTime, 10.0)
+}
+
+func TestObjectiveTransformAskTell(t *testing.T) {
+	config := fastConfig()
+	config.ObjectiveTransform = func(measured float64, _ []float64) (float64, error) {
+		return measured - overhead, nil
+	}
+
+	opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	for !opt.Done() {
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+
+		trial, err := opt.Tell(suggestion.TrialID, suggestion.Params[0]+overhead, nil)
+		assert.NoError(t, err)
+
+		if assert.NotNil(t, trial.MeasuredValue) {
+			assert.Equal(t, suggestion.Params[0]+overhead, *trial.MeasuredValue)
+		}
+
+		assert.InDelta(t, suggestion.Params[0], trial.ExecutionTime, 1e-9)
+	}
+
+	assert.Less(t, opt.Result().BestTime, float64(overhead))
+}
@@ -0,0 +1,2156 @@
+package ho
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// maxDrawsFactor caps how many random draws are made to find candidates
+	// that pass the CandidateFilter, as a multiple of the number of candidates
+	// needed.
+	maxDrawsFactor = 10
+
+	// defaultPerturbationScale is the CandidateMix.Scale used if unset.
+	defaultPerturbationScale = 0.1
+
+	// candidatesPerDimension is the number of candidates per parameter range
+	// if NumCandidates is unset.
+	candidatesPerDimension = 100
+
+	// maxAutoCandidates caps the number of candidates if NumCandidates is
+	// unset, so iterations stay fast in many dimensions.
+	maxAutoCandidates = 2000
+
+	// minPerturbationScale is the fraction of the initial perturbation scale
+	// below which it doesn't shrink any further.
+	minPerturbationScale = 0.1
+
+	// defaultTopKCount is the CandidateMix.TopKCount used if unset.
+	defaultTopKCount = 5
+
+	// selectionChunk is the number of candidates predicted between checks
+	// of MaxCandidateSelectionTime.
+	selectionChunk = 16
+)
+
+// termination describes why a run ended, see Result.TerminationReason.
+type termination struct {
+	// reason is the termination reason.
+	reason TerminationReason
+
+	// detail details the reason, see Result.TerminationDetail.
+	detail string
+
+	// err is the error that terminated the run early, if any.
+	err error
+}
+
+// measurement is the outcome of a single benchmark invocation.
+type measurement struct {
+	// value is the objective value, or the execution time in nanoseconds.
+	value float64
+
+	// err is the error returned by the benchmark, if any.
+	err error
+
+	// startedAt is when the invocation started.
+	startedAt time.Time
+
+	// duration is the measured wall time, up to cancellation.
+	duration time.Duration
+
+	// canceled is the error of the trial context if it was done when the
+	// benchmark returned, or the error the rate limiter refused the
+	// invocation with, nil otherwise.
+	canceled error
+
+	// wait is the time spent waiting for the rate limiter.
+	wait time.Duration
+
+	// seedValues holds the values of each seed, see
+	// OptimizationConfig.SeedsPerTrial.
+	seedValues []float64
+}
+
+// optimizer holds the state of a single optimization run.
+//
+// Fields:
+// - ctx: Run context, trial contexts are derived from it
+// - config: OptimizationConfig controlling the optimization process
+// - benchmarkFunc: The function whose parameters are being optimized
+// - objectiveFunc: Optional function whose returned value is minimized instead
+// of the execution time of benchmarkFunc
+// - hypers: ParameterRange values defining the search space
+// - rng: Random number generator used to draw parameters, from source
+// (protected by rngMu)
+// - gp: Gaussian Process model fed with every non-skipped trial
+// - bestParams, bestTime, trials, stopErr: Run results (protected by mu)
+// - lastTrialID: Used to assign trial IDs (protected by mu)
+// - warnings: Non-fatal issues found during the run (protected by mu)
+// - cache: Reusable trials, by cache key (protected by mu)
+// - observations: Observations fed to the model (protected by mu)
+// - failures: Points of the failed trials kept out of the model (protected
+// by mu)
+// - checkpoints, checkpointed: Checkpoint state (protected by mu)
+// - stopped, outOfTime, ended: Why the run ended (protected by mu)
+//
+// Thread safety:
+// - Random parameter generation is protected by rngMu
+// - Results are protected by mu
+// - The Gaussian Process model is thread-safe on its own.
+type optimizer[T constraints.Integer | constraints.Float] struct {
+	// config controls the optimization process.
+	config OptimizationConfig
+
+	// ctx is the run context. Trial contexts are derived from it.
+	ctx context.Context
+
+	// benchmarkFunc is the function whose parameters are being optimized.
+	benchmarkFunc trialFunc[T]
+
+	// objectiveFunc, if set, is used instead of benchmarkFunc, and the value
+	// it returns is minimized instead of the measured execution time.
+	objectiveFunc func(ctx context.Context, info TrialInfo, params ...T) (float64, error)
+
+	// hypers defines the search space.
+	hypers []ParameterRange[T]
+
+	// rng is used to generate parameter values.
+	rng *rand.Rand
+
+	// source is the source of rng, counting draws for checkpoints.
+	source *countingSource
+
+	// rngMu protects access to rng and source.
+	rngMu sync.Mutex
+
+	// model predicts performance at untested points.
+	model SurrogateModel
+
+	// mu protects access to bestParams, bestTime, trials, stopErr,
+	// lastTrialID, warnings, cache, observations, failures, checkpoints,
+	// checkpointed, stopped, outOfTime and ended.
+	mu sync.Mutex
+
+	// bestParams tracks the parameter combination that produced the best result.
+	bestParams []T
+
+	// bestTime tracks the best execution time seen so far (lower is better).
+	bestTime float64
+
+	// trials records every evaluation, in completion order.
+	trials []Trial[T]
+
+	// stopErr holds the error the benchmark requested a stop with, if any.
+	stopErr error
+
+	// safety tracks the safety constraint, nil unless Safety is set.
+	// Violations are protected by mu.
+	safety *safetyState
+
+	// fidelity tracks a multi-fidelity run, nil unless it is one, see
+	// OptimizeMultiFidelity.
+	fidelity *fidelityState
+
+	// hyperband tracks a Hyperband run, nil unless it is one, see
+	// OptimizeHyperband. Its brackets are protected by mu.
+	hyperband *hyperbandState
+
+	// lastTrialID is the ID of the last trial started.
+	lastTrialID int
+
+	// bestTrialID is the ID of the last trial that improved the best
+	// result, see ProgressUpdate.NewBest.
+	bestTrialID int
+
+	// warnings holds non-fatal issues found during the run.
+	warnings []string
+
+	// cache holds the completed and failed trials, by cache key, if
+	// CacheEvaluations is set.
+	cache map[string]Trial[T]
+
+	// observations holds the observations fed to the model, warm start
+	// included, in the order they were fed.
+	observations []Observation
+
+	// failures holds the points of the failed trials kept out of the model,
+	// see FailureSubstitute.
+	failures [][]float64
+
+	// checkpoints writes checkpoints, nil unless configured.
+	checkpoints *checkpointer
+
+	// debug collects the debug snapshots, nil unless DebugCapture is set,
+	// and for the ask/tell Optimizer.
+	debug *debugTrace
+
+	// profiles holds the paths of the profiles written, see ProfileBest.
+	// Protected by mu.
+	profiles struct{ cpu, heap string }
+
+	// checkpointed is the number of trials at the last checkpoint.
+	checkpointed int
+
+	// resumed is the checkpoint the run resumes from, if any.
+	resumed *checkpointFile
+
+	// checkpointLeases adds the outstanding suggestions of the ask/tell
+	// Optimizer driving the run to a checkpoint, nil outside ask/tell. It's
+	// called with the mu of the Optimizer held.
+	checkpointLeases func(state *checkpointFile)
+
+	// studyID identifies the study of the run in OptimizationConfig.Storage,
+	// empty unless stored.
+	studyID string
+
+	// storeBestMu serializes the updates of the stored best trial.
+	storeBestMu sync.Mutex
+
+	// resumedSlots holds the trial slots that ended before the run resumed,
+	// keyed by phase and iteration.
+	resumedSlots map[TrialInfo]bool
+
+	// feasiblePoint is a point outside of the exclusion zones satisfying the
+	// constraints, found by validate, see randomParams.
+	feasiblePoint []T
+
+	// invalidErr holds the validation error that prevented the run, if any.
+	invalidErr error
+
+	// notifier delivers webhook notifications, nil unless configured.
+	notifier *notifier
+
+	// trackers receives the run events, nil unless configured.
+	trackers *trackers
+
+	// control pauses and stops the run, see RunHandle.
+	control *runControl
+
+	// stopped is true if the run ended because it was stopped.
+	stopped bool
+
+	// outOfTime is true if the run ended because TimeBudget elapsed.
+	outOfTime bool
+
+	// progress is the highest overall progress sent, so it never decreases,
+	// see ProgressUpdate.OverallProgress.
+	progress float64
+
+	// ended is why the run ended, set once it did. Nil while the run goes
+	// on, and for the ask/tell Optimizer, whose termination is evaluated
+	// live.
+	ended *termination
+
+	// invoked is true once the benchmark was invoked, as cooldowns only
+	// happen between invocations.
+	invoked atomic.Bool
+
+	// drift tracks the reference measurements, nil unless DriftSentinel is
+	// set. Protected by mu.
+	drift *driftState
+
+	// sleep waits for a duration or until a context is done, see sleep.
+	// Tests replace it to fake the clock.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+//////
+// Methods.
+//////
+
+// randomParams generates a set of random parameters within the search space,
+// outside of any exclusion zone, and satisfying the constraints. This is used
+// both for initial sampling and generating candidates during optimization.
+//
+// Returns:
+// - []T: Slice of random values, one for each parameter range
+//
+// Important notes:
+// - Draws are rejected until one is feasible. After rejectionDraws of them,
+// e.g. when exclusion zones leave a small region free, a random cell of those
+// the zone bounds split the space into is searched for instead, see
+// outsideZones, and failing that, the point validate found is returned.
+func (o *optimizer[T]) randomParams() []T {
+	for i := 0; i < rejectionDraws; i++ {
+		params := o.drawParams()
+
+		if o.feasible(params) {
+			return params
+		}
+	}
+
+	o.rngMu.Lock()
+	params, _ := outsideZones(o.hypers, o.config.ExclusionZones, o.rng, o.feasible)
+	o.rngMu.Unlock()
+
+	if params != nil {
+		return params
+	}
+
+	return slices.Clone(o.feasiblePoint)
+}
+
+// drawParams generates a set of random parameters within the search space in
+// a thread-safe manner, regardless of exclusion zones and constraints.
+//
+// Returns:
+// - []T: Slice of random values, one for each parameter range.
+func (o *optimizer[T]) drawParams() []T {
+	o.rngMu.Lock()
+	defer o.rngMu.Unlock()
+
+	params := make([]T, len(o.hypers))
+
+	for i, hyper := range o.hypers {
+		params[i] = drawValue(o.rng, hyper)
+	}
+
+	return params
+}
+
+// accepted returns true if the parameters pass the CandidateFilter.
+func (o *optimizer[T]) accepted(params []T) bool {
+	return o.config.CandidateFilter == nil || o.config.CandidateFilter(paramsToFloat64s(params))
+}
+
+// candidates generates n candidates that pass the CandidateFilter, according
+// to the CandidateMix. Rejected candidates are re-sampled up to
+// n*maxDrawsFactor draws. If the filter rejects everything, n unfiltered
+// random candidates are returned and a warning is recorded.
+//
+// Parameters:
+// - n: Number of candidates to generate
+// - iteration: Current optimization iteration, 0 during initial sampling
+//
+// Returns:
+// - [][]T: Generated candidates, may be less than n if the filter rejects
+// most of them.
+func (o *optimizer[T]) candidates(n, iteration int) [][]T {
+	candidates := make([][]T, 0, n)
+
+	for draws := 0; len(candidates) < n && draws < n*maxDrawsFactor; draws++ {
+		if params := o.generateCandidate(len(candidates), n, iteration); o.accepted(params) {
+			candidates = append(candidates, params)
+		}
+	}
+
+	if len(candidates) == 0 && n > 0 {
+		o.warnf("candidate filter rejected all %d draws, scoring unfiltered candidates", n*maxDrawsFactor)
+
+		for len(candidates) < n {
+			candidates = append(candidates, o.randomParams())
+		}
+	}
+
+	return candidates
+}
+
+// generateCandidate generates the candidate of the given slot according to
+// the CandidateMix: the first slots are perturbations of the incumbent, the
+// next ones perturbations of top historical points, and the remainder uniform
+// random points. Perturbation candidates come first so they win ties.
+//
+// Parameters:
+// - slot: Index of the candidate among the n candidates
+// - n: Number of candidates to generate
+// - iteration: Current optimization iteration, 0 during initial sampling
+//
+// Returns:
+// - []T: The candidate parameters.
+func (o *optimizer[T]) generateCandidate(slot, n, iteration int) []T {
+	mix := o.config.CandidateMix
+
+	// There's nothing to perturb during initial sampling.
+	if iteration == 0 {
+		return o.randomParams()
+	}
+
+	numIncumbent := int(math.Round(float64(n) * mix.Incumbent))
+
+	numTopK := int(math.Round(float64(n) * mix.TopK))
+
+	var center []T
+
+	switch {
+	case slot < numIncumbent:
+		center = o.incumbent()
+	case slot < numIncumbent+numTopK:
+		center = o.randomTopPoint()
+	}
+
+	if center == nil {
+		return o.randomParams()
+	}
+
+	// The perturbation scale shrinks over iterations, down to
+	// minPerturbationScale of its initial value.
+	scale := mix.Scale
+	if scale <= 0 {
+		scale = defaultPerturbationScale
+	}
+
+	progress := float64(iteration-1) / float64(max(o.config.Iterations, 1))
+
+	scale *= math.Max(1-progress, minPerturbationScale)
+
+	// Perturbations may land inside exclusion zones, or violate constraints,
+	// retry a few times before falling back to a uniform random point.
+	for attempt := 0; attempt < maxDrawsFactor; attempt++ {
+		params := o.perturbParams(center, scale)
+
+		if o.feasible(params) {
+			return params
+		}
+	}
+
+	return o.randomParams()
+}
+
+// perturbParams generates a Gaussian perturbation of center in a thread-safe
+// manner. Each dimension's standard deviation is scale times its range width.
+// Integer dimensions are rounded, and all dimensions clipped to their range.
+//
+// Parameters:
+// - center: Parameters to perturb
+// - scale: Standard deviation, as a fraction of the range widths
+//
+// Returns:
+// - []T: The perturbed parameters.
+func (o *optimizer[T]) perturbParams(center []T, scale float64) []T {
+	o.rngMu.Lock()
+	defer o.rngMu.Unlock()
+
+	params := make([]T, len(o.hypers))
+
+	for i, hyper := range o.hypers {
+		// Offsets from Min keep integer ranges exact wherever they lie, see
+		// offsetOf and rangeValue.
+		v := offsetOf(hyper, center[i]) + o.rng.NormFloat64()*scale*offsetOf(hyper, hyper.Max)
+
+		params[i] = snapToStep(hyper, atOffset(hyper, v))
+	}
+
+	return params
+}
+
+// incumbent returns a copy of the best parameters found so far, or nil if no
+// trial completed yet.
+func (o *optimizer[T]) incumbent() []T {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.bestTime == math.MaxFloat64 {
+		return nil
+	}
+
+	params := make([]T, len(o.bestParams))
+	copy(params, o.bestParams)
+
+	return params
+}
+
+// studyMeta describes the run, for trackers.
+func (o *optimizer[T]) studyMeta() StudyMeta {
+	meta := StudyMeta{
+		StartedAt:      time.Now(),
+		Parameters:     o.parameterSpecs(),
+		Iterations:     o.config.Iterations,
+		InitialSamples: o.config.InitialSamples,
+		NumCandidates:  o.config.NumCandidates,
+		Seed:           o.config.Seed,
+	}
+
+	if acquisition, ok := acquisitionOf(o.config); ok {
+		meta.Acquisition = acquisition.Name
+	}
+
+	var studyTags map[string]string
+
+	if o.config.Study != nil {
+		meta.Study = o.config.Study.Name
+		studyTags = o.config.Study.Tags
+	}
+
+	meta.Tags = mergeTags(studyTags, o.config.Tags)
+
+	return meta
+}
+
+// parameterSpecs describes the search space.
+func (o *optimizer[T]) parameterSpecs() []ParameterSpec {
+	specs := make([]ParameterSpec, len(o.hypers))
+
+	names := o.paramNames()
+
+	for i, hyper := range o.hypers {
+		specs[i] = ParameterSpec{
+			Name:   paramName(names, i),
+			Type:   paramType(hyper),
+			Min:    float64(hyper.Min),
+			Max:    float64(hyper.Max),
+			Step:   float64(hyper.Step),
+			Values: hyper.Values,
+		}
+	}
+
+	return specs
+}
+
+// warmStart feeds the prior observations to the model, see
+// OptimizationConfig.WarmStart, at the points the model sees, see
+// modelCoordinates. Those the model rejects are skipped, with a warning.
+func (o *optimizer[T]) warmStart() {
+	for i, observation := range o.config.WarmStart {
+		observation.Params = modelCoordinates(o.hypers, observation.Params)
+
+		if err := updateWeighted(o.model, observation.Params, observation.Value, observation.Weight); err != nil {
+			o.warnf("WarmStart[%d] not fed to the model: %v", i, err)
+
+			continue
+		}
+
+		o.observations = append(o.observations, observation)
+	}
+}
+
+// paramNames returns the names of the parameter ranges.
+func (o *optimizer[T]) paramNames() []string {
+	names := make([]string, len(o.hypers))
+
+	for i, hyper := range o.hypers {
+		names[i] = hyper.Name
+	}
+
+	return names
+}
+
+// progressNames returns the parameter names for progress updates, unnamed
+// ones as "param<i>", see ProgressUpdate.ParamNames.
+func (o *optimizer[T]) progressNames() []string {
+	names := o.paramNames()
+
+	for i := range names {
+		names[i] = paramName(names, i)
+	}
+
+	return names
+}
+
+// modelPoint converts parameters to the point the models see, see
+// modelPoint.
+func (o *optimizer[T]) modelPoint(params []T) []float64 {
+	return modelPoint(o.hypers, params)
+}
+
+// bestValues returns the best parameters as float64, nil if there's none.
+// The caller must hold mu.
+func (o *optimizer[T]) bestValues() []float64 {
+	if o.bestTime == math.MaxFloat64 {
+		return nil
+	}
+
+	return paramsToFloat64s(o.bestParams)
+}
+
+// incumbentTime returns the best value seen so far, math.MaxFloat64 if no
+// trial completed yet.
+func (o *optimizer[T]) incumbentTime() float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.bestTime
+}
+
+// randomTopPoint returns the parameters of a random trial among the
+// CandidateMix.TopKCount best completed ones, or nil if no trial completed yet.
+func (o *optimizer[T]) randomTopPoint() []T {
+	topKCount := o.config.CandidateMix.TopKCount
+	if topKCount <= 0 {
+		topKCount = defaultTopKCount
+	}
+
+	o.mu.Lock()
+
+	completed := make([]Trial[T], 0, len(o.trials))
+
+	for _, trial := range o.trials {
+		if trial.Status == TrialCompleted {
+			completed = append(completed, trial)
+		}
+	}
+
+	o.mu.Unlock()
+
+	if len(completed) == 0 {
+		return nil
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].ExecutionTime < completed[j].ExecutionTime
+	})
+
+	o.rngMu.Lock()
+	top := completed[o.rng.Intn(min(topKCount, len(completed)))]
+	o.rngMu.Unlock()
+
+	return top.Params
+}
+
+// initialParams generates the parameters of an initial sample, applying the
+// CandidateFilter if FilterInitialSamples is set, with the same re-sampling
+// cap and fallback as candidates.
+func (o *optimizer[T]) initialParams() []T {
+	if o.config.Safety != nil {
+		return o.safeRegionParams()
+	}
+
+	if !o.config.FilterInitialSamples {
+		return o.randomParams()
+	}
+
+	return o.candidates(1, 0)[0]
+}
+
+// warnf records a non-fatal issue found during the run.
+func (o *optimizer[T]) warnf(format string, args ...any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.warnings = append(o.warnings, fmt.Sprintf(format, args...))
+}
+
+// nextCandidate generates NumCandidates candidates and returns the most
+// promising one according to the acquisition function.
+//
+// Parameters:
+// - model: Model scoring the candidates, usually o.model
+// - iteration: Current optimization iteration
+//
+// Returns:
+// - []T: The selected candidate parameters
+// - int: Number of candidates scored, see MaxCandidateSelectionTime.
+func (o *optimizer[T]) nextCandidate(model SurrogateModel, iteration int) ([]T, int) {
+	return o.proposeCandidate(model, iteration, o.incumbentTime(), o.incumbent())
+}
+
+// proposeCandidate works like nextCandidate, against the given best value
+// and parameters instead of the run's, e.g. for a model of other values than
+// the best one's.
+//
+// Parameters:
+// - model: Model scoring the candidates
+// - iteration: Current optimization iteration
+// - bestTime: The best value the model observed, math.MaxFloat64 if none
+// - incumbent: The parameters of the best value, nil if none
+//
+// Returns:
+// - []T: The selected candidate parameters
+// - int: Number of candidates scored, less than generated if
+// MaxCandidateSelectionTime ran out.
+func (o *optimizer[T]) proposeCandidate(model SurrogateModel, iteration int, bestTime float64, incumbent []T) ([]T, int) {
+	var deadline time.Time
+
+	if o.config.MaxCandidateSelectionTime > 0 {
+		deadline = time.Now().Add(o.config.MaxCandidateSelectionTime)
+	}
+
+	model = o.withFailures(model)
+
+	bestAcquisition := math.MaxFloat64
+
+	// Update acquisition function with current best time, model's prediction
+	// at the best point, evaluated points, iteration and dimensions. Any
+	// BestSoFar set in the configuration is overwritten, math.MaxFloat64 until
+	// a trial succeeds.
+	//
+	// Acquisition functions compare predictions, of transformed values, to
+	// the best one. Penalties of failed trials aren't values to improve on.
+	if bestTime >= math.MaxFloat64/2 {
+		bestTime, incumbent = math.MaxFloat64, nil
+	} else {
+		bestTime = transformedValue(model, bestTime)
+	}
+
+	o.config.AcqParams.BestSoFar = bestTime
+
+	o.config.AcqParams.IncumbentMean = bestTime
+
+	if incumbent != nil {
+		o.config.AcqParams.IncumbentMean, _ = model.Predict(o.modelPoint(incumbent))
+	}
+
+	o.config.AcqParams.EvaluatedPoints = model.Points()
+
+	o.config.AcqParams.Iteration = iteration
+
+	o.config.AcqParams.Dimensions = len(o.hypers)
+
+	// Already evaluated candidates, on the lattice of integer and stepped
+	// dimensions, or cached ones, are only selected if all candidates are.
+	next, duplicate, duplicateAcquisition := -1, -1, math.MaxFloat64
+
+	lattices := latticesOf(o.hypers)
+
+	evaluated := make(map[string]bool, len(o.config.AcqParams.EvaluatedPoints))
+
+	for _, point := range o.config.AcqParams.EvaluatedPoints {
+		evaluated[pointKey(onLattice(lattices, point))] = true
+	}
+
+	// Generate random candidates, and get model's predictions for all of
+	// them at once, scored on the lattice as the model sees them
+	candidates := o.candidates(o.config.NumCandidates, iteration)
+
+	// First, so it's scored whatever MaxCandidateSelectionTime.
+	if combined := o.combineTerms(model, candidates); combined != nil {
+		candidates = append([][]T{combined}, candidates...)
+	}
+
+	points := make([][]float64, len(candidates))
+
+	for i, candidateParams := range candidates {
+		points[i] = onLattice(lattices, o.modelPoint(candidateParams))
+	}
+
+	means, variances := predictUntil(model, points, deadline)
+
+	// Candidates left unscored when time ran out aren't considered.
+	candidates, points = candidates[:len(means)], points[:len(means)]
+
+	// Candidates not predicted safe are never selected.
+	var safe []bool
+
+	if o.config.Safety != nil {
+		safe = o.safeCandidates(model, candidates, points)
+	}
+
+	// Scores are only kept for the debug trace.
+	var scores []float64
+
+	if o.debug != nil {
+		scores = make([]float64, len(candidates))
+	}
+
+	// Choose the most promising one according to the acquisition function
+	for i, candidateParams := range candidates {
+		// Evaluate how promising this point is
+		acquisition := o.acquisition(points[i], means[i], variances[i])
+
+		if scores != nil {
+			scores[i] = acquisition
+		}
+
+		if safe != nil && !safe[i] {
+			continue
+		}
+
+		if _, ok := o.cached(candidateParams); ok || evaluated[pointKey(points[i])] {
+			if duplicate < 0 || acquisition < duplicateAcquisition {
+				duplicateAcquisition = acquisition
+
+				duplicate = i
+			}
+
+			continue
+		}
+
+		// Update if this is the most promising candidate so far. The first
+		// candidate is always kept, so one is selected even if every
+		// acquisition value is +Inf or NaN.
+		if next < 0 || acquisition < bestAcquisition {
+			bestAcquisition = acquisition
+
+			next = i
+		}
+	}
+
+	if next < 0 {
+		next = duplicate
+	}
+
+	if next < 0 && safe != nil {
+		return o.safeFallback(), len(candidates)
+	}
+
+	if next < 0 {
+		return nil, len(candidates)
+	}
+
+	if o.debug != nil {
+		o.captureDebug(model, iteration, candidates, means, variances, scores, next)
+	}
+
+	return candidates[next], len(candidates)
+}
+
+// combineTerms exploits the decomposition of an additive model, see
+// AdditiveModel: each group of dimensions takes the values of the candidate
+// whose term has the lowest confidence bound.
+//
+// Parameters:
+// - model: Model scoring the candidates
+// - candidates: The candidates
+//
+// Returns:
+// - []T: The combined candidate, nil if the model isn't additive, or the
+// candidate is filtered out, e.g. by CandidateFilter.
+func (o *optimizer[T]) combineTerms(model SurrogateModel, candidates [][]T) []T {
+	additive, ok := additiveOf(model)
+	if !ok || len(candidates) < 2 {
+		return nil
+	}
+
+	groups := additive.Groups()
+
+	points := make([][]float64, len(candidates))
+
+	for i, candidateParams := range candidates {
+		points[i] = o.modelPoint(candidateParams)
+	}
+
+	means, variances := additive.PredictTerms(points)
+
+	combined := make([]T, len(o.hypers))
+
+	for g, group := range groups {
+		best, bestBound := 0, math.Inf(1)
+
+		for c := range candidates {
+			if bound := LowerConfidenceBound(means[g][c], variances[g][c], o.config.AcqParams); bound < bestBound {
+				best, bestBound = c, bound
+			}
+		}
+
+		for _, d := range group {
+			combined[d] = candidates[best][d]
+		}
+	}
+
+	if !o.accepted(combined) || !o.feasible(combined) {
+		return nil
+	}
+
+	return combined
+}
+
+// acquisition scores a candidate, using AcquisitionFuncEx if set, and
+// AcquisitionFunc otherwise. Scores are always "lower is better": values of
+// acquisition functions with MaximizeAcquisition direction are negated.
+//
+// Until a trial succeeds, there's no best value to improve on: candidates
+// are scored by LowerConfidenceBound, see AcquisitionParams.BestSoFar.
+func (o *optimizer[T]) acquisition(candidate []float64, mean, variance float64) float64 {
+	if o.config.AcqParams.BestSoFar == math.MaxFloat64 {
+		params := o.config.AcqParams
+
+		if params.Beta <= 0 {
+			params.Beta = defaultBeta
+		}
+
+		return LowerConfidenceBound(mean, variance, params)
+	}
+
+	var value float64
+
+	if o.config.AcquisitionFuncEx != nil {
+		value = o.config.AcquisitionFuncEx(candidate, mean, variance, o.config.AcqParams)
+	} else {
+		value = o.config.AcquisitionFunc(mean, variance, o.config.AcqParams)
+	}
+
+	if o.config.AcquisitionDirection == MaximizeAcquisition {
+		return -value
+	}
+
+	return value
+}
+
+// newTrialInfo assigns the next trial ID and returns the trial metadata.
+//
+// Parameters:
+// - phase: Phase the trial belongs to
+// - iteration: Iteration of the phase the trial belongs to
+// - retry: Whether the trial replaces a previous one
+//
+// Returns:
+// - TrialInfo: The trial metadata.
+func (o *optimizer[T]) newTrialInfo(phase string, iteration int, retry bool) TrialInfo {
+	// Parameters are drawn right after.
+	position := o.rngPosition()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.lastTrialID++
+
+	return TrialInfo{
+		TrialID:     o.lastTrialID,
+		Phase:       phase,
+		Iteration:   iteration,
+		Retry:       retry,
+		Seed:        trialSeed(o.source.seed, o.lastTrialID),
+		RNGPosition: position,
+	}
+}
+
+// evaluate runs the benchmark function with the given parameters, records the
+// trial, and feeds the result to the model.
+//
+// Parameters:
+// - info: Metadata of the trial
+// - params: Parameters to evaluate
+//
+// Returns:
+// - Trial[T]: The recorded trial
+//
+// Important notes:
+// - Skipped trials (ErrSkipTrial) are recorded but neither update the model
+// nor the best result
+// - Failed trials are penalized, and kept out of the model unless
+// OptimizationConfig.FailedTrials is FailurePenalize
+// - Stop requests (ErrStopOptimization) are recorded as failed trials and
+// end the run
+// - Non-finite objective values are penalized or skipped, see
+// OptimizationConfig.NonFiniteValues
+// - Completed values are transformed, see
+// OptimizationConfig.ObjectiveTransform
+// - Trials wait for the system to be ready first, see
+// OptimizationConfig.LoadGate
+// - Surprising values are measured again, see
+// OptimizationConfig.SurpriseRemeasure
+// - Measurements run between the Setup and Teardown hooks, see
+// OptimizationConfig.HookScope
+// - Timed out trials are penalized like failed ones, trials interrupted by run
+// cancellation never reach the model
+// - If CacheEvaluations is set, parameters matching a previous completed or
+// failed trial reuse its outcome without invoking the benchmark.
+func (o *optimizer[T]) evaluate(info TrialInfo, params []T) Trial[T] {
+	if cached, ok := o.cached(params); ok {
+		return o.reuse(info, params, cached)
+	}
+
+	underLoad, err := o.awaitReady(info.TrialID)
+
+	// The run was canceled while waiting for the system to be ready.
+	if err != nil {
+		return o.record(Trial[T]{
+			TrialInfo:     info,
+			Params:        params,
+			ExecutionTime: math.MaxFloat64 / 2,
+			StartedAt:     time.Now(),
+			Status:        TrialCanceled,
+			UnderLoad:     underLoad,
+			Err:           err,
+		})
+	}
+
+	return o.record(o.measureTrial(info, params, underLoad))
+}
+
+// measureTrial measures a trial between the Setup and Teardown hooks, if
+// they run once per trial, and sets its status.
+//
+// Parameters:
+// - info: Metadata of the trial
+// - params: Parameters to evaluate
+// - underLoad: Whether the trial runs under load, see LoadGate
+//
+// Returns:
+// - Trial[T]: The trial, not recorded yet.
+func (o *optimizer[T]) measureTrial(info TrialInfo, params []T, underLoad bool) Trial[T] {
+	teardown, err := o.setUp(HooksPerTrial, info, params)
+	defer teardown()
+
+	m := measurement{err: err, startedAt: time.Now()}
+
+	if err == nil {
+		m = o.measureSeeds(info, params)
+	}
+
+	trial := Trial[T]{
+		TrialInfo:     info,
+		Params:        params,
+		ExecutionTime: m.value,
+		Duration:      m.duration,
+		StartedAt:     m.startedAt,
+		RateLimitWait: m.wait,
+		Status:        TrialCompleted,
+		UnderLoad:     underLoad,
+		SeedValues:    m.seedValues,
+		Err:           m.err,
+	}
+
+	if benchmarkParams, ok := o.benchmarkParams(params); ok {
+		trial.BenchmarkParams = benchmarkParams
+	}
+
+	switch {
+	case errors.Is(m.err, ErrSkipTrial):
+		trial.Status = TrialSkipped
+	case m.canceled != nil:
+		trial.Status = TrialCanceled
+
+		trial.ExecutionTime = math.MaxFloat64/2 + m.value
+
+		if m.err == nil {
+			trial.Err = m.canceled
+		}
+	case m.err == nil:
+		// A non-finite objective value, e.g. a NaN, would corrupt the model.
+		if o.objectiveFunc != nil && handleNonFinite(&trial, o.config.NonFiniteValues) {
+			break
+		}
+
+		o.remeasure(&trial)
+		o.correctDrift(&trial)
+		o.transformObjective(&trial)
+		o.measureSafety(&trial)
+	default:
+		// Apply penalty if the benchmark failed.
+		trial.Status = TrialFailed
+
+		trial.ExecutionTime = math.MaxFloat64/2 + m.value
+	}
+
+	// The model trusts values measured more often, or under normal
+	// conditions, more.
+	if trial.Status == TrialCompleted {
+		trial.Weight = measurementWeight(len(trial.Measurements), trial.Surprising, trial.UnderLoad)
+	}
+
+	return trial
+}
+
+// measure invokes the benchmark once, in its own trial context, once the
+// rate limiter allows it.
+//
+// Parameters:
+// - info: Metadata of the trial
+// - params: Parameters to evaluate
+//
+// Returns:
+// - measurement: The outcome of the invocation.
+func (o *optimizer[T]) measure(info TrialInfo, params []T) measurement {
+	var m measurement
+
+	// The rate limiter waits on the run context, so waiting doesn't eat into
+	// TrialTimeout.
+	if o.config.RateLimiter != nil {
+		start := time.Now()
+
+		err := o.config.RateLimiter.Wait(o.ctx)
+
+		m.wait = time.Since(start)
+
+		if err != nil {
+			m.startedAt = time.Now()
+			m.canceled = err
+
+			return m
+		}
+	}
+
+	teardown, err := o.setUp(HooksPerMeasurement, info, params)
+	defer teardown()
+
+	if err != nil {
+		m.startedAt = time.Now()
+		m.err = err
+
+		return m
+	}
+
+	params, _ = o.benchmarkParams(params)
+
+	ctx, cancel := o.trialContext()
+	defer cancel()
+
+	// Record when the trial context is canceled, so time measurement stops at
+	// cancellation even if the benchmark doesn't honor it.
+	canceledAt := make(chan time.Time, 1)
+
+	stopAfter := context.AfterFunc(ctx, func() {
+		canceledAt <- time.Now()
+	})
+
+	o.invoked.Store(true)
+
+	m.startedAt = time.Now()
+
+	if o.objectiveFunc != nil {
+		m.value, m.err = o.objectiveFunc(ctx, info, params...)
+	} else {
+		m.err = o.benchmarkFunc(ctx, info, params...)
+	}
+
+	endTime := time.Now()
+
+	m.canceled = ctx.Err()
+
+	if !stopAfter() {
+		if at := <-canceledAt; at.Before(endTime) {
+			endTime = at
+		}
+	}
+
+	m.duration = endTime.Sub(m.startedAt)
+
+	if o.objectiveFunc == nil {
+		m.value = float64(m.duration.Nanoseconds())
+	}
+
+	return m
+}
+
+// record records a trial and, unless it was skipped, a stop was requested or
+// the run context is done, feeds it to the model and the best result.
+//
+// Parameters:
+// - trial: The trial, with its status set
+//
+// Returns:
+// - Trial[T]: The recorded trial, with its regret set.
+func (o *optimizer[T]) record(trial Trial[T]) Trial[T] {
+	params := trial.Params
+
+	err := trial.Err
+
+	if o.config.KnownOptimum != nil && trial.Status != TrialSkipped {
+		trial.Regret = trial.ExecutionTime - o.config.KnownOptimum.Value
+	}
+
+	o.checkSafety(&trial)
+
+	o.mu.Lock()
+
+	o.trials = append(o.trials, trial)
+
+	// Trials that don't reach the model are checkpointed right away, the
+	// others once they did.
+	defer func() {
+		o.mu.Lock()
+		o.checkpoint(false)
+		o.mu.Unlock()
+	}()
+
+	if o.cache != nil && (trial.Status == TrialCompleted || trial.Status == TrialFailed) {
+		o.cache[cacheKey(o.hypers, params)] = trial
+	}
+
+	if errors.Is(err, ErrStopOptimization) {
+		o.stopErr = err
+	}
+
+	o.mu.Unlock()
+
+	o.trackTrial(trial)
+
+	if trial.Status == TrialSkipped || errors.Is(err, ErrStopOptimization) || o.ctx.Err() != nil {
+		return trial
+	}
+
+	if o.fidelity != nil {
+		o.recordFidelity(trial)
+
+		return trial
+	}
+
+	if o.hyperband != nil {
+		o.recordHyperband(trial)
+
+		return trial
+	}
+
+	// Failed trials, timed out ones included, only steer candidates away,
+	// see FailureSubstitute.
+	if (trial.Status == TrialFailed || trial.Status == TrialCanceled) && o.substitutesFailures() {
+		o.mu.Lock()
+		o.failures = append(o.failures, o.modelPoint(params))
+		o.mu.Unlock()
+
+		o.updateBest(params, trial.ExecutionTime)
+
+		return trial
+	}
+
+	// Update model with the new observation. An observation the model
+	// rejects can't be the best either.
+	point := o.modelPoint(params)
+
+	if err := updateWeighted(o.model, point, trial.ExecutionTime, trial.Weight); err != nil {
+		o.warnf("trial %d not fed to the model: %v", trial.TrialID, err)
+
+		return trial
+	}
+
+	o.mu.Lock()
+	o.observations = append(o.observations, Observation{Params: point, Value: trial.ExecutionTime, Weight: trial.Weight})
+	o.mu.Unlock()
+
+	// Update best parameters if this is better, on average if measurements
+	// are repeated.
+	var (
+		previous float64
+		improved bool
+	)
+
+	if o.config.IncumbentReevaluation != nil {
+		previous, improved = o.updateBestMean(trial)
+	} else {
+		previous, improved = o.updateBest(params, trial.ExecutionTime)
+	}
+
+	if improved && trial.Status == TrialCompleted {
+		o.mu.Lock()
+		o.bestTrialID = trial.TrialID
+		o.mu.Unlock()
+
+		o.newBest(trial, previous)
+	}
+
+	return trial
+}
+
+// trackTrial reports an ended trial to the storage and the trackers, if any.
+func (o *optimizer[T]) trackTrial(trial Trial[T]) {
+	if o.studyID == "" && o.trackers == nil {
+		return
+	}
+
+	record := o.trialRecord(trial)
+
+	o.storeTrial(record)
+
+	if o.trackers != nil {
+		o.trackers.call("OnTrialEnd", func(t Tracker) { t.OnTrialEnd(record) })
+	}
+}
+
+// trialRecord converts a trial to its JSON representation, see
+// NewTrialRecord, with its typed parameters formatted.
+func (o *optimizer[T]) trialRecord(trial Trial[T]) TrialRecord {
+	names := o.paramNames()
+
+	record := NewTrialRecord(trial, names)
+
+	record.Formatted = formatParams(o.hypers, names, trial.Params)
+
+	return record
+}
+
+// newBest reports a new best trial to the storage, the trackers and the
+// webhook, if any.
+//
+// Parameters:
+// - trial: The new best trial
+// - previous: The previous best time.
+func (o *optimizer[T]) newBest(trial Trial[T], previous float64) {
+	if o.studyID == "" && o.trackers == nil && o.notifier == nil {
+		return
+	}
+
+	record := o.trialRecord(trial)
+
+	o.storeBest(record)
+
+	if o.trackers != nil {
+		o.trackers.call("OnNewBest", func(t Tracker) { t.OnNewBest(record) })
+	}
+
+	if o.notifier != nil {
+		o.notifier.notify(Notification{
+			Event:              EventNewBest,
+			Time:               time.Now(),
+			Trial:              &record,
+			ImprovementPercent: improvementPercent(previous, trial.ExecutionTime),
+		})
+	}
+}
+
+// cached returns the trial previously recorded for the parameters, if
+// CacheEvaluations is set and there's one.
+func (o *optimizer[T]) cached(params []T) (Trial[T], bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.cache == nil {
+		return Trial[T]{}, false
+	}
+
+	trial, ok := o.cache[cacheKey(o.hypers, params)]
+
+	return trial, ok
+}
+
+// reuse records a trial reusing the outcome of a cached one, without invoking
+// the benchmark. The model and the best result already account for it.
+//
+// Parameters:
+// - info: Metadata of the trial
+// - params: Parameters to evaluate, as drawn
+// - cached: The trial whose outcome is reused
+//
+// Returns:
+// - Trial[T]: The recorded trial.
+func (o *optimizer[T]) reuse(info TrialInfo, params []T, cached Trial[T]) Trial[T] {
+	trial := Trial[T]{
+		TrialInfo:     info,
+		Params:        params,
+		ExecutionTime: cached.ExecutionTime,
+		MeasuredValue: cached.MeasuredValue,
+		SeedValues:    cached.SeedValues,
+		Regret:        cached.Regret,
+		StartedAt:     time.Now(),
+		Status:        cached.Status,
+		Err:           cached.Err,
+		Cached:        true,
+	}
+
+	if benchmarkParams, ok := o.benchmarkParams(params); ok {
+		trial.BenchmarkParams = benchmarkParams
+	}
+
+	o.mu.Lock()
+	o.trials = append(o.trials, trial)
+	o.checkpoint(false)
+	o.mu.Unlock()
+
+	o.trackTrial(trial)
+
+	return trial
+}
+
+// runTrial evaluates one trial slot, drawing replacement evaluations for
+// skipped trials as allowed by MaxSkipRetries, and emits a progress update.
+// No replacement is drawn once the run is done.
+//
+// Parameters:
+// - phase: Phase the trial belongs to
+// - iteration: Iteration of the phase the trial belongs to
+// - total: Total number of iterations of the phase
+// - next: Returns the parameters to evaluate for each attempt, and the
+// number of candidates scored to propose them
+//
+// Returns:
+// - Trial[T]: The last recorded trial of the slot.
+func (o *optimizer[T]) runTrial(phase string, iteration, total int, next func() ([]T, int)) Trial[T] {
+	var trial Trial[T]
+
+	for attempt := 0; attempt <= o.config.MaxSkipRetries; attempt++ {
+		info := o.newTrialInfo(phase, iteration, attempt > 0)
+
+		params, scored := next()
+
+		trial = o.evaluate(info, params)
+
+		if trial.Status != TrialSkipped || !o.proceed() {
+			o.sendProgress(trial, total, scored)
+
+			break
+		}
+	}
+
+	return trial
+}
+
+// trialContext derives the context of a trial from the run context, applying
+// TrialTimeout if set.
+func (o *optimizer[T]) trialContext() (context.Context, context.CancelFunc) {
+	if o.config.TrialTimeout > 0 {
+		return context.WithTimeout(o.ctx, o.config.TrialTimeout)
+	}
+
+	return context.WithCancel(o.ctx)
+}
+
+// done returns true if the run must not start any further trial, because the
+// benchmark requested a stop, the run context was canceled, the run was
+// stopped, or TimeBudget elapsed.
+func (o *optimizer[T]) done() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.stopErr != nil || o.safetyErr() != nil || o.ctx.Err() != nil || o.interrupted()
+}
+
+// updateBest safely updates the best parameters and time if a new best is
+// found.
+//
+// Parameters:
+// - params: Parameter combination to potentially update as best
+// - executionTime: Execution time achieved with these parameters
+//
+// Returns:
+// - float64: The previous best time
+// - bool: Whether the best was updated.
+func (o *optimizer[T]) updateBest(params []T, executionTime float64) (float64, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	previous := o.bestTime
+
+	if executionTime < o.bestTime {
+		o.bestTime = executionTime
+
+		copy(o.bestParams, params)
+
+		return previous, true
+	}
+
+	return previous, false
+}
+
+// sendProgress sends a progress update for the given trial, if a progress
+// channel is configured. Updates are dropped if the channel is full.
+//
+// Parameters:
+// - trial: The trial
+// - total: Number of iterations of its phase
+// - scored: Number of candidates scored to propose it, 0 if it wasn't.
+func (o *optimizer[T]) sendProgress(trial Trial[T], total, scored int) {
+	if o.config.ProgressChan == nil {
+		return
+	}
+
+	o.mu.Lock()
+
+	// Convert current and best params to []int for backward compatibility
+	currentInts := roundInts(trial.Params)
+
+	bestInts := roundInts(o.bestParams)
+
+	update := ProgressUpdate{
+		TrialID:             trial.TrialID,
+		Time:                time.Now(),
+		Phase:               trial.Phase,
+		CurrentIteration:    trial.Iteration,
+		TotalIterations:     total,
+		CurrentParams:       currentInts,
+		CurrentBestParams:   bestInts,
+		ParamNames:          o.progressNames(),
+		CurrentValues:       paramsToFloat64s(trial.Params),
+		BestValues:          o.bestValues(),
+		CurrentFormatted:    formatParams(o.hypers, o.paramNames(), trial.Params),
+		BestFormatted:       formatParams(o.hypers, o.paramNames(), o.bestParams),
+		CurrentBestTime:     o.bestTime,
+		LastExecutionTime:   trial.ExecutionTime,
+		LastDuration:        trial.Duration,
+		NewBest:             trial.TrialID == o.bestTrialID,
+		InstantaneousRegret: trial.Regret,
+		DriftRatio:          o.driftRatio(),
+		FailedTrials:        len(o.failures),
+		CandidatesScored:    scored,
+	}
+
+	update.OverallProgress, update.EvaluationsCompleted, update.EvaluationsPlanned = o.overallProgress()
+
+	// Sending under the lock keeps concurrent updates in progress order.
+	defer o.mu.Unlock()
+
+	select {
+	case o.config.ProgressChan <- update:
+	default:
+		// Skip update if channel is full.
+	}
+}
+
+// sendFinalProgress sends the final progress update, with phase PhaseDone
+// and the termination reason, if a progress channel is configured. The
+// update is dropped if the channel is full.
+func (o *optimizer[T]) sendFinalProgress(ended termination) {
+	if o.config.ProgressChan == nil {
+		return
+	}
+
+	o.mu.Lock()
+
+	bestInts := roundInts(o.bestParams)
+
+	update := ProgressUpdate{
+		Time:              time.Now(),
+		Phase:             PhaseDone,
+		CurrentBestParams: bestInts,
+		ParamNames:        o.progressNames(),
+		BestValues:        o.bestValues(),
+		BestFormatted:     formatParams(o.hypers, o.paramNames(), o.bestParams),
+		CurrentBestTime:   o.bestTime,
+		TerminationReason: ended.reason,
+		TerminationDetail: ended.detail,
+	}
+
+	// The run is over, whatever was planned.
+	_, update.EvaluationsCompleted, update.EvaluationsPlanned = o.overallProgress()
+
+	update.OverallProgress = 1
+
+	if ci, err := bestConfidence(o.trials, o.bestParams, defaultConfidenceLevel); err == nil {
+		update.BestConfidence = &ci
+	}
+
+	o.mu.Unlock()
+
+	select {
+	case o.config.ProgressChan <- update:
+	default:
+		// Skip update if channel is full.
+	}
+}
+
+// overallProgress estimates how far the run is, across phases, see
+// ProgressUpdate.OverallProgress. The caller must hold mu.
+//
+// Returns:
+// - float64: The progress, in [0, 1], never less than the last one
+// - int: Number of evaluations completed
+// - int: Number of evaluations planned, completed ones included.
+func (o *optimizer[T]) overallProgress() (float64, int, int) {
+	trials := o.config.InitialSamples + o.config.Iterations
+
+	if o.hyperband != nil {
+		trials = o.hyperband.planned
+	}
+
+	var completed, planned int
+
+	for _, trial := range o.trials {
+		// Replacements and extra measurements weren't planned upfront.
+		extra := max(len(trial.Measurements)-1, 0)
+
+		if trial.Retry {
+			trials++
+		}
+
+		completed += 1 + extra
+		planned += extra
+	}
+
+	planned += max(trials, len(o.trials))
+
+	// The remaining budget of a multi-fidelity run is planned as low-fidelity
+	// evaluations.
+	if o.fidelity != nil {
+		cost, _, _ := o.fidelityCost()
+
+		planned = completed + int(math.Max(o.fidelity.config.Budget-cost, 0))
+	}
+
+	if o.drift != nil {
+		every := o.config.DriftSentinel.Every
+		if every == 0 {
+			every = defaultSentinelEvery
+		}
+
+		// The baseline, then one every Every trials.
+		completed += o.drift.attempts
+		planned += max(1+(max(trials, 1)-1)/every, o.drift.attempts)
+	}
+
+	progress := 0.0
+
+	if planned > 0 {
+		progress = float64(completed) / float64(planned)
+	}
+
+	// The run ends when the first of its budgets is exhausted.
+	if o.config.TimeBudget > 0 {
+		progress = math.Max(progress, float64(o.control.elapsed(o.config.CountPausedTime))/float64(o.config.TimeBudget))
+	}
+
+	o.progress = math.Max(o.progress, math.Min(progress, 1))
+
+	return o.progress, completed, planned
+}
+
+// runInitialSampling evaluates InitialSamples random points, running up to
+// MaxConcurrentEvaluations benchmarks concurrently. It returns once all
+// started trials completed.
+func (o *optimizer[T]) runInitialSampling() {
+	concurrency := max(o.config.MaxConcurrentEvaluations, 1)
+
+	// sem limits the number of concurrent evaluations.
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < o.config.InitialSamples; i++ {
+		// Slots that ended before the run resumed aren't evaluated again.
+		if o.resumedSlots[TrialInfo{Phase: PhaseInitialSampling, Iteration: i + 1}] {
+			continue
+		}
+
+		sem <- struct{}{}
+
+		if !o.proceed() {
+			<-sem
+
+			break
+		}
+
+		wg.Add(1)
+
+		go func(iteration int) {
+			defer wg.Done()
+
+			defer func() { <-sem }()
+
+			o.runTrial(PhaseInitialSampling, iteration, o.config.InitialSamples, func() ([]T, int) { return o.initialParams(), 0 })
+		}(i + 1)
+	}
+
+	// The optimization phase must not begin until all initial samples are in.
+	wg.Wait()
+}
+
+// validate checks the configuration and search space before the run starts.
+//
+// Returns:
+// - error: Wraps ErrInvalidConfig if the run can't start, nil otherwise.
+func (o *optimizer[T]) validate() error {
+	if o.model == nil {
+		return fmt.Errorf("%w: Surrogate returned a nil model", ErrInvalidConfig)
+	}
+
+	if err := checkSurrogate(o.model, len(o.hypers)); err != nil {
+		return err
+	}
+
+	if err := o.config.Validate(); err != nil {
+		return err
+	}
+
+	switch o.config.NonFiniteValues {
+	case "", NonFinitePenalize, NonFiniteSkip:
+	default:
+		return fmt.Errorf("%w: NonFiniteValues: unknown policy %q", ErrInvalidConfig, o.config.NonFiniteValues)
+	}
+
+	if err := o.config.OutputTransform.validate(); err != nil {
+		return err
+	}
+
+	if err := o.config.FailedTrials.validate(); err != nil {
+		return err
+	}
+
+	if err := o.config.HookScope.validate(); err != nil {
+		return err
+	}
+
+	if err := o.checkTransforms(); err != nil {
+		return err
+	}
+
+	if o.config.SeedsPerTrial < 0 {
+		return fmt.Errorf("%w: SeedsPerTrial %d is negative", ErrInvalidConfig, o.config.SeedsPerTrial)
+	}
+
+	if err := o.config.SeedAggregation.validate(); err != nil {
+		return err
+	}
+
+	switch {
+	case o.config.CooldownBetweenTrials < 0:
+		return fmt.Errorf("%w: CooldownBetweenTrials %v is negative", ErrInvalidConfig, o.config.CooldownBetweenTrials)
+	case o.config.CooldownWithinTrials < 0:
+		return fmt.Errorf("%w: CooldownWithinTrials %v is negative", ErrInvalidConfig, o.config.CooldownWithinTrials)
+	}
+
+	if o.config.NumCandidates < 0 {
+		return fmt.Errorf("%w: NumCandidates %d is negative", ErrInvalidConfig, o.config.NumCandidates)
+	}
+
+	if o.config.MaxCandidateSelectionTime < 0 {
+		return fmt.Errorf("%w: MaxCandidateSelectionTime %v is negative", ErrInvalidConfig, o.config.MaxCandidateSelectionTime)
+	}
+
+	if o.config.TimeBudget < 0 {
+		return fmt.Errorf("%w: TimeBudget %v is negative", ErrInvalidConfig, o.config.TimeBudget)
+	}
+
+	for i, hyper := range o.hypers {
+		if err := validateBounds(hyper); err != nil {
+			return fmt.Errorf("%w: range %d: %w", ErrInvalidConfig, i, err)
+		}
+
+		switch hyper.Type {
+		case "", IntParameter, FloatParameter, DurationParameter:
+			if hyper.Values != nil {
+				return fmt.Errorf("%w: range %d: only enum ranges take values", ErrInvalidConfig, i)
+			}
+		case BoolParameter:
+			if hyper.Min != 0 || hyper.Max != 1 || hyper.Step != 1 {
+				return fmt.Errorf("%w: range %d: bool ranges are 0 to 1 by steps of 1, see BoolParam", ErrInvalidConfig, i)
+			}
+		case EnumParameter:
+			if err := validateEnum(hyper.Values); err != nil {
+				return fmt.Errorf("%w: range %d: %w", ErrInvalidConfig, i, err)
+			}
+
+			if hyper.Min != 0 || float64(hyper.Max) != float64(len(hyper.Values)-1) || hyper.Step != 1 {
+				return fmt.Errorf("%w: range %d: enum ranges are indexes by steps of 1, see EnumParam", ErrInvalidConfig, i)
+			}
+		default:
+			return fmt.Errorf("%w: range %d: unknown type %q", ErrInvalidConfig, i, hyper.Type)
+		}
+	}
+
+	for i, observation := range o.config.WarmStart {
+		switch {
+		case len(observation.Params) != len(o.hypers):
+			return fmt.Errorf("%w: WarmStart[%d] has %d parameters, expected %d", ErrInvalidConfig, i, len(observation.Params), len(o.hypers))
+		case math.IsNaN(observation.Value) || math.IsInf(observation.Value, 0):
+			return fmt.Errorf("%w: WarmStart[%d] has non-finite value %v", ErrInvalidConfig, i, observation.Value)
+		case observation.Weight < 0 || math.IsNaN(observation.Weight) || math.IsInf(observation.Weight, 0):
+			return fmt.Errorf("%w: WarmStart[%d] has invalid weight %v", ErrInvalidConfig, i, observation.Weight)
+		}
+	}
+
+	if o.config.Notifications != nil {
+		if err := o.config.Notifications.validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.config.DriftSentinel != nil {
+		if err := o.config.DriftSentinel.validate(len(o.hypers)); err != nil {
+			return err
+		}
+	}
+
+	if o.config.LoadGate != nil {
+		if err := o.config.LoadGate.validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.config.IncumbentReevaluation != nil {
+		if err := o.config.IncumbentReevaluation.validate(o.config); err != nil {
+			return err
+		}
+	}
+
+	if o.config.SurpriseRemeasure != nil {
+		if err := o.config.SurpriseRemeasure.validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.config.Checkpoint != nil {
+		if err := o.config.Checkpoint.validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.config.DebugCapture != nil {
+		if err := o.config.DebugCapture.validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.config.ProfileBest != nil {
+		if err := o.config.ProfileBest.validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.config.Study != nil {
+		if err := o.checkStudy(); err != nil {
+			return err
+		}
+	}
+
+	if o.resumed != nil {
+		if err := o.checkResumable(); err != nil {
+			return err
+		}
+	}
+
+	if o.config.Safety != nil {
+		mins, maxs := make([]float64, len(o.hypers)), make([]float64, len(o.hypers))
+
+		for d, hyper := range o.hypers {
+			mins[d], maxs[d] = float64(hyper.Min), float64(hyper.Max)
+		}
+
+		if err := o.config.Safety.validate(mins, maxs); err != nil {
+			return err
+		}
+	}
+
+	for _, zone := range o.config.ExclusionZones {
+		if err := zone.validate(len(o.hypers)); err != nil {
+			return err
+		}
+	}
+
+	for i, c := range o.config.Constraints {
+		if err := c.validate(len(o.hypers)); err != nil {
+			return fmt.Errorf("%w (Constraints[%d])", err, i)
+		}
+	}
+
+	if o.config.DriftSentinel != nil && len(o.config.Constraints) > 0 && !o.feasible(o.referenceParams()) {
+		return fmt.Errorf("%w: DriftSentinel: the reference violates Constraints", ErrInvalidConfig)
+	}
+
+	if len(o.config.ExclusionZones) > 0 || len(o.config.Constraints) > 0 {
+		return o.findFeasiblePoint()
+	}
+
+	return nil
+}
+
+// findFeasiblePoint sets feasiblePoint. Coverage by exclusion zones alone is
+// decided exactly, see outsideZones, constraints by random draws.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if no feasible point was found.
+func (o *optimizer[T]) findFeasiblePoint() error {
+	point, decided := outsideZones(o.hypers, o.config.ExclusionZones, nil, o.feasible)
+
+	if point == nil && decided && len(o.config.Constraints) == 0 {
+		return fmt.Errorf("%w: exclusion zones cover the whole search space", ErrInvalidConfig)
+	}
+
+	for i := 0; point == nil && i < coverageCheckDraws; i++ {
+		if params := o.drawParams(); o.feasible(params) {
+			point = params
+		}
+	}
+
+	if point == nil {
+		return fmt.Errorf("%w: exclusion zones and constraints rule out the whole search space", ErrInvalidConfig)
+	}
+
+	o.feasiblePoint = point
+
+	return nil
+}
+
+// run executes the optimization process.
+//
+// Returns:
+// - *Result[T]: The outcome of the run.
+func (o *optimizer[T]) run() *Result[T] {
+	if err := o.validate(); err != nil {
+		o.invalidErr = err
+
+		return o.result()
+	}
+
+	o.control.start()
+
+	// The warm start observations are among those of the checkpoint.
+	if o.resumed != nil {
+		o.restore()
+	} else {
+		o.warmStart()
+	}
+
+	o.createStudy()
+
+	if o.config.Checkpoint != nil {
+		o.checkpoints = newCheckpointer(*o.config.Checkpoint, o.warnf)
+	}
+
+	o.debug = newDebugTrace(o.config, o.warnf)
+
+	if o.config.Notifications != nil {
+		o.notifier = newNotifier(o.ctx, *o.config.Notifications)
+	}
+
+	o.startTracking()
+
+	switch {
+	case o.fidelity != nil:
+		o.runFidelities()
+	case o.hyperband != nil:
+		o.runHyperband()
+	default:
+		o.runPhases()
+	}
+
+	// The reason is settled here, e.g. so canceling the run context
+	// afterwards doesn't change it.
+	o.mu.Lock()
+
+	ended := o.termination()
+
+	o.ended = &ended
+
+	o.mu.Unlock()
+
+	o.profileBest()
+
+	o.sendFinalProgress(ended)
+
+	if o.checkpoints != nil {
+		o.mu.Lock()
+		o.checkpoint(true)
+		o.mu.Unlock()
+
+		// Write failures are recorded as warnings.
+		o.checkpoints.close()
+	}
+
+	result := o.endTracking()
+
+	if o.notifier != nil {
+		o.notifier.notify(Notification{
+			Event:   EventRunCompleted,
+			Time:    time.Now(),
+			Summary: newRunSummary(result),
+		})
+
+		result.NotificationFailures = o.notifier.close()
+	}
+
+	return result
+}
+
+// runPhases runs the initial sampling, then the optimization loop, slots
+// that ended before the run resumed aside.
+func (o *optimizer[T]) runPhases() {
+	// Phase 1: Initial random sampling.
+	//
+	// Build initial model by sampling random points in the parameter space.
+	// This helps establish a baseline understanding of the function behavior.
+	o.runInitialSampling()
+
+	// Phase 2: Bayesian optimization loop.
+	//
+	// Iteratively select and evaluate new points based on model predictions.
+	resumed := 0
+
+	for slot := range o.resumedSlots {
+		if slot.Phase == PhaseOptimization || slot.Phase == PhaseIncumbentCheck {
+			resumed++
+		}
+	}
+
+	for i := resumed; i < o.config.Iterations && o.proceed(); i++ {
+		iteration := i + 1
+
+		if o.reevaluationDue(iteration) {
+			o.runTrial(PhaseIncumbentCheck, iteration, o.config.Iterations, func() ([]T, int) { return o.incumbent(), 0 })
+
+			continue
+		}
+
+		o.runTrial(PhaseOptimization, iteration, o.config.Iterations, func() ([]T, int) {
+			return o.nextCandidate(o.model, iteration)
+		})
+	}
+}
+
+// termination returns why the run ended, as settled at the end of the run,
+// or as things stand if it's still going on. The caller must hold mu.
+func (o *optimizer[T]) termination() termination {
+	if o.ended != nil {
+		return *o.ended
+	}
+
+	switch {
+	case o.invalidErr != nil:
+		return termination{reason: TerminationInvalidConfig, detail: o.invalidErr.Error(), err: o.invalidErr}
+	case o.stopErr != nil:
+		return termination{reason: TerminationBenchmarkRequestedStop, detail: o.stopErr.Error(), err: o.stopErr}
+	case o.safetyErr() != nil:
+		return termination{reason: TerminationSafetyViolation, detail: o.safety.err.Error(), err: o.safety.err}
+	case o.ctx.Err() != nil:
+		cause := context.Cause(o.ctx)
+
+		return termination{reason: TerminationContextCanceled, detail: cause.Error(), err: cause}
+	case o.stopped:
+		return termination{reason: TerminationStopped, detail: fmt.Sprintf("stopped after %d trials", len(o.trials))}
+	case o.outOfTime:
+		return termination{
+			reason: TerminationTimeBudget,
+			detail: fmt.Sprintf("time budget of %v elapsed after %d trials", o.config.TimeBudget, len(o.trials)),
+		}
+	}
+
+	if o.hyperband != nil {
+		return termination{
+			reason: TerminationCompleted,
+			detail: fmt.Sprintf("%d brackets evaluated over %d Hyperband iterations", len(o.hyperband.brackets), o.hyperband.config.Iterations),
+		}
+	}
+
+	if o.fidelity != nil {
+		cost, low, high := o.fidelityCost()
+
+		return termination{
+			reason: TerminationCompleted,
+			detail: fmt.Sprintf("%v of %v cost units spent on %d low-fidelity and %d high-fidelity evaluations", cost, o.fidelity.config.Budget, low, high),
+		}
+	}
+
+	return termination{
+		reason: TerminationCompleted,
+		detail: fmt.Sprintf("%d initial samples and %d iterations evaluated", o.config.InitialSamples, o.config.Iterations),
+	}
+}
+
+// result builds a snapshot of the run results.
+func (o *optimizer[T]) result() *Result[T] {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	bestParams := make([]T, len(o.bestParams))
+	copy(bestParams, o.bestParams)
+
+	trials := make([]Trial[T], len(o.trials))
+	copy(trials, o.trials)
+
+	warnings := make([]string, len(o.warnings))
+	copy(warnings, o.warnings)
+
+	if warning := jitterWarning(o.model); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	var drift []DriftMeasurement
+
+	if o.drift != nil {
+		drift = append(drift, o.drift.history...)
+	}
+
+	ended := o.termination()
+
+	paramNames := o.paramNames()
+
+	var regret *Regret
+
+	var violations []int
+
+	if o.safety != nil {
+		violations = append(violations, o.safety.violations...)
+	}
+
+	var brackets []BracketStats
+
+	if o.hyperband != nil {
+		brackets = append(brackets, o.hyperband.brackets...)
+	}
+
+	if o.config.KnownOptimum != nil {
+		regret = computeRegret(o.config.KnownOptimum, trials, bestParams)
+	}
+
+	var bestBenchmarkParams []T
+
+	if len(bestParams) > 0 {
+		if params, ok := o.benchmarkParams(bestParams); ok {
+			bestBenchmarkParams = params
+		}
+	}
+
+	return &Result[T]{
+		BestParams:          bestParams,
+		BestBenchmarkParams: bestBenchmarkParams,
+		BestTime:            o.bestTime,
+		Trials:              trials,
+		TerminationReason:   ended.reason,
+		TerminationDetail:   ended.detail,
+		Err:                 ended.err,
+		Warnings:            warnings,
+		Drift:               drift,
+		Regret:              regret,
+		SafetyViolations:    violations,
+		Brackets:            brackets,
+		ParamNames:          paramNames,
+		Seed:                o.source.seed,
+		DebugTrace:          o.debug.trace(),
+		CPUProfile:          o.profiles.cpu,
+		HeapProfile:         o.profiles.heap,
+		NumCandidates:       o.config.NumCandidates,
+		StudyID:             o.studyID,
+		hypers:              o.hypers,
+		model:               o.model.Clone(),
+	}
+}
+
+//////
+// Factory.
+//////
+
+// newOptimizer creates a new optimizer for a single run.
+//
+// Parameters:
+// - ctx: Run context, canceling it stops the run
+// - config: OptimizationConfig controlling the optimization process
+// - benchmarkFunc: The function whose parameters you want to optimize
+// - hypers: One or more ParameterRange defining the search space
+//
+// Returns:
+// - *optimizer[T]: Optimizer ready to run.
+func newOptimizer[T constraints.Integer | constraints.Float](
+	ctx context.Context,
+	config OptimizationConfig,
+	benchmarkFunc trialFunc[T],
+	hypers ...ParameterRange[T],
+) *optimizer[T] {
+	// Using current time as seed ensures different random sequences across
+	// runs, unless a seed is configured.
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	source := newCountingSource(seed)
+
+	if config.Study != nil && config.Storage == nil {
+		config.Storage = config.Study.Storage
+	}
+
+	// Candidates must cover the space, whatever its dimensions.
+	if config.NumCandidates == 0 {
+		config.NumCandidates = autoCandidates(len(hypers))
+	}
+
+	newLattice := surrogateFactory(config, hypers)
+
+	model := newTransformedModel(newLattice(), config.OutputTransform, newLattice)
+
+	var cache map[string]Trial[T]
+
+	if config.CacheEvaluations {
+		cache = make(map[string]Trial[T])
+	}
+
+	return &optimizer[T]{
+		ctx:           ctx,
+		config:        config,
+		benchmarkFunc: benchmarkFunc,
+		hypers:        hypers,
+
+		rng:    rand.New(source),
+		source: source,
+
+		model:      model,
+		bestParams: make([]T, len(hypers)),
+		bestTime:   math.MaxFloat64,
+		cache:      cache,
+		control:    newRunControl(),
+		drift:      newDriftState(config),
+		safety:     newSafetyState(config, newLattice),
+		sleep:      sleep,
+	}
+}
@@ -0,0 +1,816 @@
+package ho
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho/benchfuncs"
+)
+
+// rangesOf returns the search space of the synthetic function f.
+func rangesOf(f benchfuncs.Function) []ParameterRange[float64] {
+	ranges := make([]ParameterRange[float64], len(f.Bounds))
+
+	for i, b := range f.Bounds {
+		ranges[i] = ParameterRange[float64]{Min: b.Min, Max: b.Max}
+	}
+
+	return ranges
+}
+
+// newSyntheticOptimizer returns an optimizer minimizing the objective over the
+// search space of the synthetic function f, seeded for deterministic runs.
+func newSyntheticOptimizer(
+	config OptimizationConfig,
+	f benchfuncs.Function,
+	objective benchfuncs.Objective,
+	seed int64,
+) *optimizer[float64] {
+	o := newOptimizer[float64](context.Background(), config, nil, rangesOf(f)...)
+
+	o.rng = rand.New(rand.NewSource(seed))
+
+	o.objectiveFunc = func(_ context.Context, _ TrialInfo, params ...float64) (float64, error) {
+		return objective(params...)
+	}
+
+	return o
+}
+
+// fastConfig returns a small configuration suitable for fast tests.
+func fastConfig() OptimizationConfig {
+	config := DefaultConfig()
+
+	config.InitialSamples = 3
+	config.Iterations = 5
+	config.NumCandidates = 10
+
+	return config
+}
+
+func TestSkipTrial(t *testing.T) {
+	var calls int
+
+	// Every other trial is skipped. Skipped trials are much faster than
+	// recorded ones, so they would win if they leaked into the best result.
+	benchmarkFunc := func(params ...int) error {
+		calls++
+
+		if calls%2 == 0 {
+			return fmt.Errorf("load generator hiccup: %w", ErrSkipTrial)
+		}
+
+		time.Sleep(time.Millisecond)
+
+		return nil
+	}
+
+	o := newOptimizer(context.Background(), fastConfig(), fromBenchmarkFunc(benchmarkFunc), ParameterRange[int]{Min: 1, Max: 10})
+
+	result := o.run()
+
+	var skipped, recorded int
+
+	// Repeated parameters are merged into a single point of the model.
+	points := make(map[int]bool)
+
+	for _, trial := range result.Trials {
+		if trial.Status == TrialSkipped {
+			skipped++
+
+			continue
+		}
+
+		recorded++
+
+		points[trial.Params[0]] = true
+	}
+
+	// No replacements: the budget is shortened by the skipped trials.
+	assert.Len(t, result.Trials, 8)
+	assert.Equal(t, 4, skipped)
+	assert.GreaterOrEqual(t, result.BestTime, float64(time.Millisecond))
+
+	// Skipped trials never reach the model.
+	assert.Equal(t, recorded, o.model.Len())
+	assert.Len(t, o.model.Points(), len(points))
+}
+
+func TestSkipTrialReplacement(t *testing.T) {
+	var calls int
+
+	// Every other trial is skipped.
+	benchmarkFunc := func(params ...int) error {
+		calls++
+
+		if calls%2 == 0 {
+			return ErrSkipTrial
+		}
+
+		return nil
+	}
+
+	config := fastConfig()
+	config.MaxSkipRetries = 1
+
+	o := newOptimizer(context.Background(), config, fromBenchmarkFunc(benchmarkFunc), ParameterRange[int]{Min: 1, Max: 10})
+
+	result := o.run()
+
+	// Every skipped trial got a replacement, so the budget is preserved.
+	assert.Equal(t, config.InitialSamples+config.Iterations, o.model.Len())
+	assert.Len(t, result.Trials, calls)
+}
+
+func TestSkipTrialDoesNotUpdateBest(t *testing.T) {
+	// All trials are skipped.
+	benchmarkFunc := func(params ...int) error {
+		return ErrSkipTrial
+	}
+
+	o := newOptimizer(context.Background(), fastConfig(), fromBenchmarkFunc(benchmarkFunc), ParameterRange[int]{Min: 1, Max: 10})
+
+	result := o.run()
+
+	assert.Zero(t, o.model.Len())
+	assert.Equal(t, []int{0}, result.BestParams)
+	assert.Equal(t, math.MaxFloat64, result.BestTime)
+}
+
+func TestStopOptimization(t *testing.T) {
+	errBudget := errors.New("error budget exhausted")
+
+	tests := []struct {
+		name   string
+		stopAt int
+		phase  string
+	}{
+		{name: "initial sampling", stopAt: 2, phase: PhaseInitialSampling},
+		{name: "optimization", stopAt: 5, phase: PhaseOptimization},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+
+			benchmarkFunc := func(params ...int) error {
+				calls++
+
+				if calls == tt.stopAt {
+					return fmt.Errorf("%w: %w", ErrStopOptimization, errBudget)
+				}
+
+				return nil
+			}
+
+			result := Optimize(fastConfig(), benchmarkFunc, ParameterRange[int]{Min: 1, Max: 10})
+
+			// No further benchmark invocations after the stop.
+			assert.Equal(t, tt.stopAt, calls)
+			assert.Len(t, result.Trials, tt.stopAt)
+
+			// The stop trial is recorded.
+			last := result.Trials[len(result.Trials)-1]
+			assert.Equal(t, tt.phase, last.Phase)
+			assert.Equal(t, TrialFailed, last.Status)
+
+			// The best found so far is returned, with the error preserved.
+			assert.Equal(t, TerminationBenchmarkRequestedStop, result.TerminationReason)
+			assert.ErrorIs(t, result.Err, ErrStopOptimization)
+			assert.ErrorIs(t, result.Err, errBudget)
+			assert.Less(t, result.BestTime, math.MaxFloat64)
+		})
+	}
+}
+
+func TestTerminationReason(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	objective := func(ctx context.Context, params ...float64) (float64, error) {
+		return math.Pow(params[0]-3, 2), nil
+	}
+
+	tests := []struct {
+		name   string
+		run    func(config OptimizationConfig) *Result[float64]
+		reason TerminationReason
+		detail string
+	}{
+		{
+			name: "completed",
+			run: func(config OptimizationConfig) *Result[float64] {
+				return OptimizeObjectiveWithContext(context.Background(), config, objective, ranges...)
+			},
+			reason: TerminationCompleted,
+			detail: "3 initial samples and 5 iterations evaluated",
+		},
+		{
+			name: "time budget",
+			run: func(config OptimizationConfig) *Result[float64] {
+				config.TimeBudget = 150 * time.Millisecond
+
+				return OptimizeObjectiveWithContext(context.Background(), config, func(ctx context.Context, params ...float64) (float64, error) {
+					time.Sleep(100 * time.Millisecond)
+
+					return objective(ctx, params...)
+				}, ranges...)
+			},
+			reason: TerminationTimeBudget,
+			detail: "time budget of 150ms elapsed after 2 trials",
+		},
+		{
+			name: "context canceled",
+			run: func(config OptimizationConfig) *Result[float64] {
+				ctx, cancel := context.WithCancelCause(context.Background())
+
+				var calls int
+
+				return OptimizeObjectiveWithContext(ctx, config, func(ctx context.Context, params ...float64) (float64, error) {
+					if calls++; calls == 4 {
+						cancel(errors.New("shutting down"))
+					}
+
+					return objective(ctx, params...)
+				}, ranges...)
+			},
+			reason: TerminationContextCanceled,
+			detail: "shutting down",
+		},
+		{
+			name: "benchmark requested stop",
+			run: func(config OptimizationConfig) *Result[float64] {
+				return OptimizeObjectiveWithContext(context.Background(), config, func(ctx context.Context, params ...float64) (float64, error) {
+					return 0, fmt.Errorf("error budget exhausted: %w", ErrStopOptimization)
+				}, ranges...)
+			},
+			reason: TerminationBenchmarkRequestedStop,
+			detail: "error budget exhausted: optimization stopped by benchmark",
+		},
+		{
+			name: "stopped",
+			run: func(config OptimizationConfig) *Result[float64] {
+				var calls atomic.Int32
+
+				reached, release := make(chan struct{}), make(chan struct{})
+
+				run := StartObjective(context.Background(), config, blockingObjective(&calls, reached, release), ranges...)
+
+				<-reached
+
+				go func() {
+					// Lets Stop go first.
+					time.Sleep(10 * time.Millisecond)
+
+					close(release)
+				}()
+
+				return run.Stop()
+			},
+			reason: TerminationStopped,
+			detail: "stopped after 3 trials",
+		},
+		{
+			name: "invalid config",
+			run: func(config OptimizationConfig) *Result[float64] {
+				config.TimeBudget = -time.Second
+
+				return OptimizeObjectiveWithContext(context.Background(), config, objective, ranges...)
+			},
+			reason: TerminationInvalidConfig,
+			detail: "invalid configuration: TimeBudget -1s is negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progressChan := make(chan ProgressUpdate, 100)
+
+			config := fastConfig()
+			config.ProgressChan = progressChan
+
+			result := tt.run(config)
+
+			close(progressChan)
+
+			assert.Equal(t, tt.reason, result.TerminationReason)
+			assert.Equal(t, tt.detail, result.TerminationDetail)
+
+			summary := newRunSummary(result)
+
+			assert.Equal(t, tt.reason, summary.TerminationReason)
+			assert.Equal(t, tt.detail, summary.TerminationDetail)
+
+			// The final progress update reports the reason, unless the run
+			// didn't start.
+			var final *ProgressUpdate
+
+			for update := range progressChan {
+				if update.Phase == PhaseDone {
+					final = &update
+				}
+			}
+
+			if tt.reason == TerminationInvalidConfig {
+				assert.Nil(t, final)
+
+				return
+			}
+
+			if assert.NotNil(t, final) {
+				assert.Equal(t, tt.reason, final.TerminationReason)
+				assert.Equal(t, tt.detail, final.TerminationDetail)
+			}
+		})
+	}
+
+	t.Run("settled once", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		o := newOptimizer[float64](ctx, fastConfig(), nil, ranges...)
+
+		o.objectiveFunc = func(ctx context.Context, _ TrialInfo, params ...float64) (float64, error) {
+			return objective(ctx, params...)
+		}
+
+		o.run()
+
+		cancel()
+
+		assert.Equal(t, TerminationCompleted, o.result().TerminationReason)
+		assert.NoError(t, o.result().Err)
+	})
+}
+
+func TestTrialInfo(t *testing.T) {
+	config := fastConfig()
+	config.MaxSkipRetries = 1
+
+	progressChan := make(chan ProgressUpdate, 2*(config.InitialSamples+config.Iterations))
+	config.ProgressChan = progressChan
+
+	var infos []TrialInfo
+
+	// Every third trial is skipped, so retries show up.
+	benchmarkFunc := func(info TrialInfo, params ...int) error {
+		infos = append(infos, info)
+
+		if info.TrialID%3 == 0 {
+			return ErrSkipTrial
+		}
+
+		return nil
+	}
+
+	result := OptimizeWithInfo(config, benchmarkFunc, ParameterRange[int]{Min: 1, Max: 10})
+
+	close(progressChan)
+
+	// IDs are unique and ordered, and consistent with the history.
+	assert.Len(t, result.Trials, len(infos))
+
+	for i, info := range infos {
+		assert.Equal(t, i+1, info.TrialID)
+		assert.Equal(t, info, result.Trials[i].TrialInfo)
+		assert.Equal(t, info.TrialID%3 == 1 && info.TrialID > 1, info.Retry)
+	}
+
+	// Progress updates use the same IDs, and the final one reports why the
+	// run ended.
+	var (
+		updates int
+		final   ProgressUpdate
+	)
+
+	for update := range progressChan {
+		if update.Phase == PhaseDone {
+			final = update
+
+			continue
+		}
+
+		updates++
+
+		trial := result.Trials[update.TrialID-1]
+
+		assert.Equal(t, trial.Phase, update.Phase)
+		assert.Equal(t, trial.Iteration, update.CurrentIteration)
+		assert.Equal(t, TrialCompleted, trial.Status)
+	}
+
+	assert.Equal(t, config.InitialSamples+config.Iterations, updates)
+	assert.Equal(t, TerminationCompleted, final.TerminationReason)
+	assert.Equal(t, result.BestTime, final.CurrentBestTime)
+}
+
+// sleepCtx sleeps for d, honoring ctx.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+func TestTrialTimeout(t *testing.T) {
+	config := fastConfig()
+	config.TrialTimeout = 20 * time.Millisecond
+
+	// Trials with params > 5 take longer than the timeout.
+	benchmarkFunc := func(ctx context.Context, params ...int) error {
+		if params[0] > 5 {
+			return sleepCtx(ctx, time.Second)
+		}
+
+		return sleepCtx(ctx, time.Millisecond)
+	}
+
+	result := OptimizeWithContext(context.Background(), config, benchmarkFunc, ParameterRange[int]{Min: 1, Max: 10})
+
+	assert.Equal(t, TerminationCompleted, result.TerminationReason)
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+	for _, trial := range result.Trials {
+		// Every trial stops within the deadline.
+		assert.Less(t, trial.Duration, config.TrialTimeout+10*time.Millisecond)
+
+		if trial.Params[0] > 5 {
+			assert.Equal(t, TrialCanceled, trial.Status)
+			assert.ErrorIs(t, trial.Err, context.DeadlineExceeded)
+		} else {
+			assert.Equal(t, TrialCompleted, trial.Status)
+		}
+	}
+}
+
+func TestRunContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var calls int
+
+	// The first trial blocks until the run is canceled.
+	benchmarkFunc := func(ctx context.Context, params ...int) error {
+		calls++
+
+		return sleepCtx(ctx, time.Minute)
+	}
+
+	startTime := time.Now()
+
+	o := newOptimizer(ctx, fastConfig(), fromBenchmarkFuncCtx(benchmarkFunc), ParameterRange[int]{Min: 1, Max: 10})
+
+	result := o.run()
+
+	assert.Less(t, time.Since(startTime), time.Second)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, TerminationContextCanceled, result.TerminationReason)
+	assert.ErrorIs(t, result.Err, context.DeadlineExceeded)
+	assert.Equal(t, TrialCanceled, result.Trials[0].Status)
+
+	// Trials interrupted by run cancellation never reach the model.
+	assert.Zero(t, o.model.Len())
+}
+
+func TestParallelInitialSampling(t *testing.T) {
+	config := fastConfig()
+	config.InitialSamples = 8
+	config.Iterations = 2
+	config.MaxConcurrentEvaluations = 4
+
+	var running, maxRunning int32
+
+	benchmarkFunc := func(params ...int) error {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			prev := atomic.LoadInt32(&maxRunning)
+
+			if current <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, current) {
+				break
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		return nil
+	}
+
+	startTime := time.Now()
+
+	result := Optimize(config, benchmarkFunc, ParameterRange[int]{Min: 1, Max: 10})
+
+	// Serially, the initial phase alone would take 400ms; with 4 concurrent
+	// evaluations it takes ~100ms, plus ~100ms for the optimization phase.
+	assert.Less(t, time.Since(startTime), 350*time.Millisecond)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(config.MaxConcurrentEvaluations))
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+	// The optimization phase begins only once all initial samples are in.
+	for i, trial := range result.Trials {
+		if i < config.InitialSamples {
+			assert.Equal(t, PhaseInitialSampling, trial.Phase)
+		} else {
+			assert.Equal(t, PhaseOptimization, trial.Phase)
+		}
+	}
+}
+
+func TestAcquisitionFuncEx(t *testing.T) {
+	const minSpacing = 5.0
+
+	config := fastConfig()
+	config.NumCandidates = 50
+
+	// Distance-penalized acquisition: candidates closer than minSpacing to any
+	// evaluated point are heavily penalized.
+	config.AcquisitionFuncEx = func(candidate []float64, mean, variance float64, params AcquisitionParams) float64 {
+		for _, point := range params.EvaluatedPoints {
+			if math.Abs(point[0]-candidate[0]) < minSpacing {
+				return math.MaxFloat64 / 2
+			}
+		}
+
+		return LowerConfidenceBound(mean, variance, params)
+	}
+
+	benchmarkFunc := func(params ...int) error {
+		return nil
+	}
+
+	result := Optimize(config, benchmarkFunc, ParameterRange[int]{Min: 1, Max: 100})
+
+	// Every point selected during optimization keeps a minimum spacing from
+	// every previously evaluated point.
+	for i, trial := range result.Trials {
+		if trial.Phase != PhaseOptimization {
+			continue
+		}
+
+		for _, previous := range result.Trials[:i] {
+			assert.GreaterOrEqual(t, math.Abs(float64(trial.Params[0]-previous.Params[0])), minSpacing)
+		}
+	}
+}
+
+func TestCandidateFilter(t *testing.T) {
+	config := fastConfig()
+	config.FilterInitialSamples = true
+
+	// Temporarily avoid large values.
+	config.CandidateFilter = func(candidate []float64) bool {
+		return candidate[0] <= 50
+	}
+
+	benchmarkFunc := func(params ...int) error {
+		return nil
+	}
+
+	result := Optimize(config, benchmarkFunc, ParameterRange[int]{Min: 1, Max: 100})
+
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+	assert.Empty(t, result.Warnings)
+
+	for _, trial := range result.Trials {
+		assert.LessOrEqual(t, trial.Params[0], 50)
+	}
+}
+
+func TestCandidateFilterRejectsEverything(t *testing.T) {
+	config := fastConfig()
+	config.FilterInitialSamples = true
+
+	config.CandidateFilter = func(candidate []float64) bool {
+		return false
+	}
+
+	benchmarkFunc := func(params ...int) error {
+		return nil
+	}
+
+	result := Optimize(config, benchmarkFunc, ParameterRange[int]{Min: 1, Max: 100})
+
+	// The run falls back to unfiltered candidates instead of looping forever,
+	// and warns about it.
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+	assert.Len(t, result.Warnings, config.InitialSamples+config.Iterations)
+
+	for _, trial := range result.Trials {
+		assert.Len(t, trial.Params, 1)
+	}
+}
+
+func TestNonFiniteObjective(t *testing.T) {
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		t.Run(fmt.Sprint(value), func(t *testing.T) {
+			for _, policy := range []NonFinitePolicy{"", NonFiniteSkip} {
+				var calls atomic.Int32
+
+				config := fastConfig()
+				config.NonFiniteValues = policy
+
+				result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+					// The second trial yields the non-finite value.
+					if calls.Add(1) == 2 {
+						return value, nil
+					}
+
+					return math.Pow(params[0]-3, 2), nil
+				}, ParameterRange[float64]{Min: 0, Max: 10})
+
+				trial := result.Trials[1]
+
+				assert.ErrorIs(t, trial.Err, ErrNonFiniteValue)
+				assert.Equal(t, math.Float64bits(value), math.Float64bits(trial.RawValue))
+				assert.Equal(t, math.MaxFloat64/2, trial.ExecutionTime)
+
+				if policy == NonFiniteSkip {
+					assert.Equal(t, TrialSkipped, trial.Status)
+					assert.ErrorIs(t, trial.Err, ErrSkipTrial)
+				} else {
+					assert.Equal(t, TrialFailed, trial.Status)
+				}
+
+				assert.Equal(t, len(result.Trials)-1, result.model.Len())
+
+				// The non-finite value reaches neither the model nor the best result.
+				assert.Empty(t, result.Warnings)
+				assert.False(t, math.IsNaN(result.BestTime) || math.IsInf(result.BestTime, 0))
+				assert.Less(t, result.BestTime, math.MaxFloat64/2)
+				assert.NotEqual(t, trial.Params, result.BestParams)
+
+				for _, y := range result.model.(*gaussianProcess).Y {
+					assert.False(t, math.IsNaN(y) || math.IsInf(y, 0))
+				}
+
+				if policy == NonFiniteSkip {
+					mean, stddev, err := result.Predict([]float64{3})
+
+					assert.NoError(t, err)
+					assert.False(t, math.IsNaN(mean) || math.IsInf(mean, 0))
+					assert.False(t, math.IsNaN(stddev) || math.IsInf(stddev, 0))
+				}
+			}
+		})
+	}
+
+	config := fastConfig()
+	config.NonFiniteValues = "Ignore"
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return params[0], nil
+	}, ParameterRange[float64]{Min: 0, Max: 10})
+
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+}
+
+func TestNumCandidates(t *testing.T) {
+	tests := []struct {
+		dimensions int
+		explicit   int
+		want       int
+	}{
+		{dimensions: 1, want: 100},
+		{dimensions: 8, want: 800},
+		{dimensions: 20, want: 2000},
+		{dimensions: 50, want: 2000},
+		{dimensions: 1, explicit: 7, want: 7},
+		{dimensions: 50, explicit: 30, want: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d dimensions, %d candidates", tt.dimensions, tt.explicit), func(t *testing.T) {
+			config := fastConfig()
+			config.Iterations = 1
+			config.NumCandidates = tt.explicit
+
+			hypers := make([]ParameterRange[float64], tt.dimensions)
+
+			for i := range hypers {
+				hypers[i] = ParameterRange[float64]{Min: 0, Max: 1}
+			}
+
+			result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+				return params[0], nil
+			}, hypers...)
+
+			assert.NoError(t, result.Err)
+			assert.Equal(t, tt.want, result.NumCandidates)
+		})
+	}
+
+	config := fastConfig()
+	config.NumCandidates = -1
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return params[0], nil
+	}, ParameterRange[float64]{Min: 0, Max: 10})
+
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+}
+
+func TestOverallProgress(t *testing.T) {
+	var calls int
+
+	// The 6th call, the first trial of the optimization phase once the
+	// baseline and a reference measurement were taken, glitches.
+	objective := func(params ...float64) (float64, error) {
+		calls++
+
+		value := math.Pow(params[0]-3, 2) + 1
+
+		if calls == 6 {
+			return value - 1000, nil
+		}
+
+		return value, nil
+	}
+
+	progressChan := make(chan ProgressUpdate, 100)
+
+	config := fastConfig()
+	config.Seed = 1
+	config.ProgressChan = progressChan
+	config.SurpriseRemeasure = &SurpriseRemeasure{Remeasurements: 2}
+	config.DriftSentinel = &DriftSentinel{Every: 2}
+
+	result := OptimizeObjective(config, objective, ParameterRange[float64]{Min: 0, Max: 10})
+
+	close(progressChan)
+
+	assert.NoError(t, result.Err)
+	assert.True(t, result.Trials[config.InitialSamples].Surprising)
+
+	var updates []ProgressUpdate
+
+	for update := range progressChan {
+		updates = append(updates, update)
+	}
+
+	if !assert.Len(t, updates, config.InitialSamples+config.Iterations+1) {
+		return
+	}
+
+	// Progress doesn't restart with the optimization phase.
+	for i, update := range updates {
+		assert.Greater(t, update.OverallProgress, 0.0)
+		assert.LessOrEqual(t, update.OverallProgress, 1.0)
+		assert.LessOrEqual(t, update.EvaluationsCompleted, update.EvaluationsPlanned)
+
+		if i > 0 {
+			assert.GreaterOrEqual(t, update.OverallProgress, updates[i-1].OverallProgress)
+			assert.GreaterOrEqual(t, update.EvaluationsCompleted, updates[i-1].EvaluationsCompleted)
+		}
+	}
+
+	// Trials, re-measurements and reference measurements all count.
+	final := updates[len(updates)-1]
+
+	assert.Equal(t, PhaseDone, final.Phase)
+	assert.Equal(t, 1.0, final.OverallProgress)
+	assert.Equal(t, config.InitialSamples+config.Iterations+2+len(result.Drift), final.EvaluationsCompleted)
+	assert.Equal(t, final.EvaluationsCompleted, final.EvaluationsPlanned)
+	assert.Equal(t, 1.0, updates[len(updates)-2].OverallProgress)
+
+	t.Run("time budget", func(t *testing.T) {
+		progressChan := make(chan ProgressUpdate, 100)
+
+		config := fastConfig()
+		config.ProgressChan = progressChan
+		config.Iterations = 1000
+		config.TimeBudget = 200 * time.Millisecond
+
+		result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+			time.Sleep(10 * time.Millisecond)
+
+			return params[0], nil
+		}, ParameterRange[float64]{Min: 0, Max: 10})
+
+		close(progressChan)
+
+		assert.Equal(t, TerminationTimeBudget, result.TerminationReason)
+
+		// The budget binds long before the iterations do.
+		var last ProgressUpdate
+
+		for update := range progressChan {
+			if update.Phase != PhaseDone {
+				assert.GreaterOrEqual(t, update.OverallProgress, last.OverallProgress)
+
+				last = update
+			}
+		}
+
+		assert.Greater(t, last.OverallProgress, 0.5)
+		assert.Less(t, last.EvaluationsCompleted, last.EvaluationsPlanned/10)
+	})
+}
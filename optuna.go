@@ -0,0 +1,512 @@
+package ho
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Optuna trial states.
+const (
+	optunaComplete = "COMPLETE"
+	optunaFail     = "FAIL"
+	optunaPruned   = "PRUNED"
+)
+
+// Optuna distribution names, current and legacy.
+const (
+	optunaFloat           = "FloatDistribution"
+	optunaInt             = "IntDistribution"
+	optunaCategorical     = "CategoricalDistribution"
+	optunaUniform         = "UniformDistribution"
+	optunaLogUniform      = "LogUniformDistribution"
+	optunaDiscreteUniform = "DiscreteUniformDistribution"
+	optunaIntUniform      = "IntUniformDistribution"
+	optunaIntLogUniform   = "IntLogUniformDistribution"
+)
+
+// optunaTimeLayout is the layout of Optuna datetimes.
+const optunaTimeLayout = "2006-01-02T15:04:05.999999"
+
+// optunaStatusAttr is the user attribute recording the ho status of trials
+// whose status has no Optuna equivalent.
+const optunaStatusAttr = "ho_status"
+
+// optunaMeasuredAttr is the user attribute recording the measured value of
+// trials whose value was transformed, see OptimizationConfig.ObjectiveTransform.
+const optunaMeasuredAttr = "ho_measured_value"
+
+// optunaBenchmarkParamsAttr is the user attribute recording the values the
+// benchmark received, see Trial.BenchmarkParams.
+const optunaBenchmarkParamsAttr = "ho_benchmark_params"
+
+// optunaStudy is the layout of Optuna study documents.
+type optunaStudy struct {
+	StudyName  string        `json:"study_name"`
+	Directions []string      `json:"directions"`
+	Trials     []optunaTrial `json:"trials"`
+}
+
+// optunaTrial is the layout of Optuna trials.
+type optunaTrial struct {
+	Number             int                           `json:"number"`
+	State              string                        `json:"state"`
+	Value              *float64                      `json:"value"`
+	Values             []float64                     `json:"values"`
+	DatetimeStart      string                        `json:"datetime_start"`
+	DatetimeComplete   string                        `json:"datetime_complete"`
+	Params             map[string]json.RawMessage    `json:"params"`
+	Distributions      map[string]optunaDistribution `json:"distributions"`
+	UserAttrs          map[string]any                `json:"user_attrs"`
+	SystemAttrs        map[string]any                `json:"system_attrs"`
+	IntermediateValues map[string]float64            `json:"intermediate_values"`
+}
+
+// optunaDistribution is the layout of Optuna distributions, as written by
+// optuna.distributions.distribution_to_json.
+type optunaDistribution struct {
+	Name       string                     `json:"name"`
+	Attributes map[string]json.RawMessage `json:"attributes"`
+}
+
+//////
+// Methods.
+//////
+
+// attr decodes a distribution attribute, and returns false if it's missing or
+// null.
+func (d optunaDistribution) attr(name string, v any) (bool, error) {
+	raw, ok := d.Attributes[name]
+	if !ok || string(raw) == "null" {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("attribute %s: %w", name, err)
+	}
+
+	return true, nil
+}
+
+// spec converts the distribution to a parameter definition.
+func (d optunaDistribution) spec(name string) (ParameterSpec, error) {
+	spec := ParameterSpec{Name: name, Type: FloatParameter}
+
+	var log bool
+
+	if _, err := d.attr("low", &spec.Min); err != nil {
+		return spec, err
+	}
+
+	if _, err := d.attr("high", &spec.Max); err != nil {
+		return spec, err
+	}
+
+	switch d.Name {
+	case optunaFloat, optunaInt:
+		if _, err := d.attr("log", &log); err != nil {
+			return spec, err
+		}
+
+		if _, err := d.attr("step", &spec.Step); err != nil {
+			return spec, err
+		}
+
+		if d.Name == optunaInt {
+			spec.Type = IntParameter
+		}
+	case optunaUniform:
+	case optunaLogUniform:
+		log = true
+	case optunaDiscreteUniform:
+		if _, err := d.attr("q", &spec.Step); err != nil {
+			return spec, err
+		}
+	case optunaIntUniform, optunaIntLogUniform:
+		spec.Type = IntParameter
+
+		log = d.Name == optunaIntLogUniform
+
+		if _, err := d.attr("step", &spec.Step); err != nil {
+			return spec, err
+		}
+	case optunaCategorical:
+		var choices []any
+
+		if _, err := d.attr("choices", &choices); err != nil {
+			return spec, err
+		}
+
+		spec.Type = EnumParameter
+		spec.Values = make([]string, len(choices))
+
+		for i, choice := range choices {
+			value, ok := choice.(string)
+			if !ok {
+				return spec, fmt.Errorf("choice %v isn't supported, ho only has string choices", choice)
+			}
+
+			spec.Values[i] = value
+		}
+
+		spec.Max = float64(len(spec.Values) - 1)
+	default:
+		return spec, fmt.Errorf("unknown distribution %q", d.Name)
+	}
+
+	if log {
+		spec.Scale = "log"
+	}
+
+	// Integer parameters always have a step of 1, see Ranges.
+	if spec.Type == IntParameter && spec.Step == 1 {
+		spec.Step = 0
+	}
+
+	return spec, nil
+}
+
+// value returns the value of a completed trial.
+func (t optunaTrial) value() (float64, error) {
+	switch {
+	case t.Value != nil:
+		return *t.Value, nil
+	case len(t.Values) == 1:
+		return t.Values[0], nil
+	default:
+		return math.NaN(), fmt.Errorf("completed trial without a single value")
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// optunaDistributionOf returns the Optuna distribution of a parameter range.
+// Enums are categorical distributions.
+func optunaDistributionOf[T constraints.Integer | constraints.Float](hyper ParameterRange[T]) optunaDistribution {
+	if hyper.Type == EnumParameter {
+		// Marshaling strings never fails.
+		choices, _ := json.Marshal(hyper.Values)
+
+		return optunaDistribution{Name: optunaCategorical, Attributes: map[string]json.RawMessage{"choices": choices}}
+	}
+
+	_, log := hyper.Prior.(logUniform)
+
+	name := optunaFloat
+
+	var step any
+
+	// Integer parameters of float ranges, see Ranges, are integer
+	// distributions too.
+	integral := hyper.Step >= 1 && isWhole(float64(hyper.Step)) && isWhole(float64(hyper.Min)) && isWhole(float64(hyper.Max))
+
+	if half := 0.5; T(half) == 0 || integral {
+		name = optunaInt
+
+		step = max(int64(hyper.Step), 1)
+	} else if hyper.Step > 0 {
+		step = float64(hyper.Step)
+	}
+
+	attributes := map[string]json.RawMessage{}
+
+	for key, value := range map[string]any{
+		"low":  float64(hyper.Min),
+		"high": float64(hyper.Max),
+		"log":  log,
+		"step": step,
+	} {
+		// Marshaling numbers, booleans and nil never fails.
+		attributes[key], _ = json.Marshal(value)
+	}
+
+	return optunaDistribution{Name: name, Attributes: attributes}
+}
+
+// isWhole reports whether v is a whole number.
+func isWhole(v float64) bool {
+	return v == math.Trunc(v)
+}
+
+// optunaState maps a trial status to an Optuna state. Statuses with no
+// equivalent are recorded in the user attributes.
+func optunaState(status TrialStatus) (string, bool) {
+	switch status {
+	case TrialCompleted:
+		return optunaComplete, true
+	case TrialFailed:
+		return optunaFail, true
+	case TrialSkipped:
+		return optunaPruned, false
+	default:
+		return optunaFail, false
+	}
+}
+
+//////
+// Exported functionalities.
+//////
+
+// ExportOptunaJSON writes the trials of a result as an Optuna study document,
+// for analysis in Optuna tools.
+//
+// Parameters:
+// - w: Where the document is written
+// - studyName: Name of the study
+// - result: The result to export
+// - hypers: The parameter ranges the result was optimized with, which define
+// the parameter distributions
+//
+// Returns:
+// - error: If the ranges don't match the result, or the document can't be
+// written.
+//
+// Usage example:
+//
+//	result := Optimize(config, benchmark, ranges...)
+//
+//	if err := ExportOptunaJSON(f, "buffer-tuning", result, ranges...); err != nil {
+//	    return err
+//	}
+//
+// Important notes:
+// - The document holds the study name, directions and trials. Trials follow
+// Optuna's layout: number, state, value, datetime_start/complete, params and
+// distributions (distribution_to_json format), user and system attributes
+// - Trial numbers are 0-based trial IDs. Values are omitted for trials that
+// didn't complete
+// - Skipped trials are exported as PRUNED, canceled ones as FAIL, with their
+// ho status in the "ho_status" user attribute
+// - Log scale ranges (see LogUniform) are exported as log distributions,
+// other priors as uniform ones
+// - Enum ranges are exported as categorical distributions, with the chosen
+// values as params.
+func ExportOptunaJSON[T constraints.Integer | constraints.Float](
+	w io.Writer,
+	studyName string,
+	result *Result[T],
+	hypers ...ParameterRange[T],
+) error {
+	if len(hypers) != len(result.ParamNames) {
+		return fmt.Errorf("%w: got %d ranges for %d parameters", ErrInvalidConfig, len(hypers), len(result.ParamNames))
+	}
+
+	distributions := make(map[string]optunaDistribution, len(hypers))
+
+	choices := map[string][]string{}
+
+	for i, hyper := range hypers {
+		distributions[paramName(result.ParamNames, i)] = optunaDistributionOf(hyper)
+
+		if hyper.Type == EnumParameter {
+			choices[paramName(result.ParamNames, i)] = hyper.Values
+		}
+	}
+
+	study := optunaStudy{
+		StudyName:  studyName,
+		Directions: []string{"MINIMIZE"},
+		Trials:     make([]optunaTrial, len(result.Trials)),
+	}
+
+	for i, trial := range result.Trials {
+		record := NewTrialRecord(trial, result.ParamNames)
+
+		state, exact := optunaState(trial.Status)
+
+		ot := optunaTrial{
+			Number:             trial.TrialID - 1,
+			State:              state,
+			Value:              record.Value,
+			DatetimeStart:      trial.StartedAt.UTC().Format(optunaTimeLayout),
+			DatetimeComplete:   trial.StartedAt.Add(trial.Duration).UTC().Format(optunaTimeLayout),
+			Params:             make(map[string]json.RawMessage, len(record.Params)),
+			Distributions:      distributions,
+			UserAttrs:          map[string]any{},
+			SystemAttrs:        map[string]any{},
+			IntermediateValues: map[string]float64{},
+		}
+
+		if record.Value != nil {
+			ot.Values = []float64{*record.Value}
+		}
+
+		for name, value := range record.Params {
+			// Marshaling finite numbers and strings never fails.
+			if values, ok := choices[name]; ok {
+				ot.Params[name], _ = json.Marshal(enumValue(values, value))
+
+				continue
+			}
+
+			ot.Params[name], _ = json.Marshal(value)
+		}
+
+		if !exact {
+			ot.UserAttrs[optunaStatusAttr] = trial.Status
+		}
+
+		if record.MeasuredValue != nil {
+			ot.UserAttrs[optunaMeasuredAttr] = *record.MeasuredValue
+		}
+
+		if record.BenchmarkParams != nil {
+			ot.UserAttrs[optunaBenchmarkParamsAttr] = record.BenchmarkParams
+		}
+
+		if record.Error != "" {
+			ot.SystemAttrs["fail_reason"] = record.Error
+		}
+
+		study.Trials[i] = ot
+	}
+
+	encoder := json.NewEncoder(w)
+
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(study)
+}
+
+// ImportOptunaJSON reads an Optuna study document, e.g. written by
+// ExportOptunaJSON or by a script dumping an Optuna study in the same layout,
+// and converts its completed trials to prior observations, for warm starting
+// (see OptimizationConfig.WarmStart).
+//
+// Parameters:
+// - r: The document
+//
+// Returns:
+// - SearchSpace: The search space, from the parameter distributions, sorted
+// by parameter name. Use Ranges to get the ParameterRange values to optimize
+// - []Observation: The completed trials, with parameters in search space
+// order
+// - error: Wrapping ErrInvalidConfig if the study can't be converted.
+//
+// Usage example:
+//
+//	space, observations, err := ImportOptunaJSON(f)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	config := DefaultConfig()
+//	config.WarmStart = observations
+//
+//	result := Optimize(config, benchmark, Ranges[int](space)...)
+//
+// Important notes:
+// - Maximized studies are negated, as ho minimizes
+// - Categorical distributions of strings are enum parameters, see
+// EnumParam
+// - Multi-objective studies, categorical distributions of other values and
+// conditional search spaces (trials with different parameters) aren't
+// supported.
+func ImportOptunaJSON(r io.Reader) (SearchSpace, []Observation, error) {
+	var (
+		study optunaStudy
+		space SearchSpace
+	)
+
+	if err := json.NewDecoder(r).Decode(&study); err != nil {
+		return space, nil, fmt.Errorf("%w: decoding Optuna study: %v", ErrInvalidConfig, err)
+	}
+
+	sign := 1.0
+
+	switch {
+	case len(study.Directions) > 1:
+		return space, nil, fmt.Errorf("%w: multi-objective studies aren't supported", ErrInvalidConfig)
+	case len(study.Directions) == 1 && study.Directions[0] == "MAXIMIZE":
+		sign = -1
+	}
+
+	specs := map[string]ParameterSpec{}
+
+	for _, trial := range study.Trials {
+		for name, distribution := range trial.Distributions {
+			spec, err := distribution.spec(name)
+			if err != nil {
+				return space, nil, fmt.Errorf("%w: trial %d: parameter %q: %v", ErrInvalidConfig, trial.Number, name, err)
+			}
+
+			if known, ok := specs[name]; ok && !known.equal(spec) {
+				return space, nil, fmt.Errorf("%w: trial %d: parameter %q: distribution differs from previous trials", ErrInvalidConfig, trial.Number, name)
+			}
+
+			specs[name] = spec
+		}
+	}
+
+	if len(specs) == 0 {
+		return space, nil, fmt.Errorf("%w: the study has no parameters", ErrInvalidConfig)
+	}
+
+	for _, spec := range specs {
+		space.Parameters = append(space.Parameters, spec)
+	}
+
+	sort.Slice(space.Parameters, func(i, j int) bool { return space.Parameters[i].Name < space.Parameters[j].Name })
+
+	if err := space.Validate(); err != nil {
+		return space, nil, err
+	}
+
+	var observations []Observation
+
+	for _, trial := range study.Trials {
+		if trial.State != optunaComplete {
+			continue
+		}
+
+		value, err := trial.value()
+		if err != nil {
+			return space, nil, fmt.Errorf("%w: trial %d: %v", ErrInvalidConfig, trial.Number, err)
+		}
+
+		observation := Observation{Params: make([]float64, len(space.Parameters)), Value: sign * value}
+
+		for i, p := range space.Parameters {
+			raw, ok := trial.Params[p.Name]
+			if !ok {
+				return space, nil, fmt.Errorf("%w: trial %d lacks parameter %q, conditional search spaces aren't supported", ErrInvalidConfig, trial.Number, p.Name)
+			}
+
+			if p.Type == EnumParameter {
+				var choice string
+
+				if err := json.Unmarshal(raw, &choice); err != nil {
+					return space, nil, fmt.Errorf("%w: trial %d: parameter %q: %v", ErrInvalidConfig, trial.Number, p.Name, err)
+				}
+
+				index := slices.Index(p.Values, choice)
+				if index < 0 {
+					return space, nil, fmt.Errorf("%w: trial %d: parameter %q: unknown choice %q", ErrInvalidConfig, trial.Number, p.Name, choice)
+				}
+
+				observation.Params[i] = float64(index)
+
+				continue
+			}
+
+			if err := json.Unmarshal(raw, &observation.Params[i]); err != nil {
+				return space, nil, fmt.Errorf("%w: trial %d: parameter %q: %v", ErrInvalidConfig, trial.Number, p.Name, err)
+			}
+		}
+
+		observations = append(observations, observation)
+	}
+
+	return space, observations, nil
+}
@@ -0,0 +1,308 @@
+package ho
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// optunaSpace mixes the supported distributions.
+var optunaSpace = SearchSpace{Parameters: []ParameterSpec{
+	{Name: "batch", Type: IntParameter, Min: 16, Max: 256, Step: 16},
+	{Name: "dropout", Type: FloatParameter, Min: 0, Max: 0.5, Step: 0.1},
+	{Name: "rate", Type: FloatParameter, Min: 0.0001, Max: 0.1, Scale: "log"},
+	{Name: "workers", Type: IntParameter, Min: 1, Max: 32},
+}}
+
+// completedObservations returns the completed trials of a result.
+func completedObservations[T int | float64](result *Result[T]) []Observation {
+	var observations []Observation
+
+	for _, trial := range result.Trials {
+		if trial.Status == TrialCompleted {
+			observations = append(observations, Observation{Params: paramsToFloat64s(trial.Params), Value: trial.ExecutionTime})
+		}
+	}
+
+	return observations
+}
+
+// openFixture opens an Optuna fixture.
+func openFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", "optuna", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+func TestOptunaRoundTrip(t *testing.T) {
+	ranges := Ranges[float64](optunaSpace)
+	calls := 0
+
+	result := OptimizeObjective(fastConfig(), func(params ...float64) (float64, error) {
+		calls++
+
+		switch calls {
+		case 2:
+			return 0, ErrSkipTrial
+		case 4:
+			return 0, assert.AnError
+		}
+
+		return params[0]/16 + params[1] + math.Log(params[2]) + params[3], nil
+	}, ranges...)
+
+	assert.NoError(t, result.Err)
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, ExportOptunaJSON(&buf, "round-trip", result, ranges...))
+
+	// The document follows Optuna's layout.
+	var document map[string]any
+
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Equal(t, "round-trip", document["study_name"])
+	assert.Equal(t, []any{"MINIMIZE"}, document["directions"])
+
+	trials := document["trials"].([]any)
+
+	if assert.Len(t, trials, len(result.Trials)) {
+		first := trials[0].(map[string]any)
+
+		assert.Equal(t, 0.0, first["number"])
+		assert.Equal(t, "COMPLETE", first["state"])
+		assert.Contains(t, first["params"], "workers")
+		assert.Equal(t, map[string]any{
+			"name":       "FloatDistribution",
+			"attributes": map[string]any{"low": 0.0001, "high": 0.1, "log": true, "step": nil},
+		}, first["distributions"].(map[string]any)["rate"])
+
+		skipped := trials[1].(map[string]any)
+
+		assert.Equal(t, "PRUNED", skipped["state"])
+		assert.Nil(t, skipped["value"])
+		assert.Equal(t, map[string]any{"ho_status": "Skipped"}, skipped["user_attrs"])
+
+		failed := trials[3].(map[string]any)
+
+		assert.Equal(t, "FAIL", failed["state"])
+		assert.Equal(t, assert.AnError.Error(), failed["system_attrs"].(map[string]any)["fail_reason"])
+		assert.Regexp(t, `^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d`, failed["datetime_start"])
+	}
+
+	space, observations, err := ImportOptunaJSON(&buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, optunaSpace, space)
+	assert.Equal(t, completedObservations(result), observations)
+	assert.Len(t, observations, len(result.Trials)-2)
+}
+
+func TestImportOptunaJSON(t *testing.T) {
+	tests := []struct {
+		fixture      string
+		space        SearchSpace
+		observations []Observation
+	}{
+		{
+			// Mixed states and current distributions.
+			fixture: "minimize.json",
+			space: SearchSpace{Parameters: []ParameterSpec{
+				{Name: "batch_size", Type: IntParameter, Min: 16, Max: 256, Step: 16},
+				{Name: "dropout", Type: FloatParameter, Min: 0, Max: 0.5, Step: 0.1},
+				{Name: "learning_rate", Type: FloatParameter, Min: 0.0001, Max: 0.1, Scale: "log"},
+				{Name: "workers", Type: IntParameter, Min: 1, Max: 32},
+			}},
+			observations: []Observation{
+				{Params: []float64{64, 0.2, 0.01, 8}, Value: 12.5},
+				{Params: []float64{128, 0.1, 0.003, 16}, Value: 7.25},
+			},
+		},
+		{
+			// Maximized, with legacy distributions and values.
+			fixture: "maximize_legacy.json",
+			space: SearchSpace{Parameters: []ParameterSpec{
+				{Name: "connections", Type: IntParameter, Min: 1, Max: 1024, Scale: "log"},
+				{Name: "threads", Type: IntParameter, Min: 1, Max: 16},
+				{Name: "timeout", Type: FloatParameter, Min: 0.5, Max: 10, Step: 0.5},
+			}},
+			observations: []Observation{
+				{Params: []float64{20, 4, 2.5}, Value: -1520},
+				{Params: []float64{64, 8, 5}, Value: -1875.5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			space, observations, err := ImportOptunaJSON(openFixture(t, tt.fixture))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.space, space)
+			assert.Equal(t, tt.observations, observations)
+
+			// Warm starting from the fixture, then exporting, preserves the
+			// search space.
+			config := fastConfig()
+			config.WarmStart = observations
+
+			ranges := Ranges[float64](space)
+
+			result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+				return params[0], nil
+			}, ranges...)
+
+			assert.NoError(t, result.Err)
+
+			var buf bytes.Buffer
+
+			assert.NoError(t, ExportOptunaJSON(&buf, tt.fixture, result, ranges...))
+
+			exported, reimported, err := ImportOptunaJSON(&buf)
+
+			assert.NoError(t, err)
+			assert.Equal(t, space, exported)
+			assert.Equal(t, completedObservations(result), reimported)
+		})
+	}
+}
+
+func TestImportOptunaJSONErrors(t *testing.T) {
+	// distribution returns a trial with the given distribution.
+	distribution := func(name, attributes string) string {
+		return `{"number": 0, "state": "COMPLETE", "value": 1, "params": {"x": 1},
+			"distributions": {"x": {"name": "` + name + `", "attributes": ` + attributes + `}}}`
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{
+			name:     "malformed",
+			document: `{"trials": [`,
+			err:      "decoding Optuna study",
+		},
+		{
+			name:     "multi-objective",
+			document: `{"directions": ["MINIMIZE", "MAXIMIZE"], "trials": []}`,
+			err:      "multi-objective studies aren't supported",
+		},
+		{
+			name:     "empty",
+			document: `{"directions": ["MINIMIZE"], "trials": []}`,
+			err:      "the study has no parameters",
+		},
+		{
+			name:     "unknown distribution",
+			document: `{"trials": [` + distribution("BetaDistribution", `{}`) + `]}`,
+			err:      `trial 0: parameter "x": unknown distribution "BetaDistribution"`,
+		},
+		{
+			name:     "numeric choices",
+			document: `{"trials": [` + distribution("CategoricalDistribution", `{"choices": [1, 2]}`) + `]}`,
+			err:      `trial 0: parameter "x": choice 1 isn't supported, ho only has string choices`,
+		},
+		{
+			name:     "invalid range",
+			document: `{"trials": [` + distribution("FloatDistribution", `{"low": 2, "high": 1}`) + `]}`,
+			err:      "parameters[0].min: 2 is greater than max 1",
+		},
+		{
+			name: "conflicting distributions",
+			document: `{"trials": [` + distribution("FloatDistribution", `{"low": 0, "high": 1}`) + `,` +
+				strings.Replace(distribution("FloatDistribution", `{"low": 0, "high": 2}`), `"number": 0`, `"number": 1`, 1) + `]}`,
+			err: `trial 1: parameter "x": distribution differs from previous trials`,
+		},
+		{
+			name: "conditional",
+			document: `{"trials": [` + distribution("FloatDistribution", `{"low": 0, "high": 1}`) + `,
+				{"number": 1, "state": "COMPLETE", "value": 1, "params": {}, "distributions": {}}]}`,
+			err: `trial 1 lacks parameter "x", conditional search spaces aren't supported`,
+		},
+		{
+			name: "missing value",
+			document: `{"trials": [` + strings.Replace(distribution("FloatDistribution", `{"low": 0, "high": 1}`), `"value": 1`, `"value": null`, 1) +
+				`]}`,
+			err: "trial 0: completed trial without a single value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ImportOptunaJSON(strings.NewReader(tt.document))
+
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	t.Run("categorical", func(t *testing.T) {
+		space, observations, err := ImportOptunaJSON(openFixture(t, "categorical.json"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, ParameterSpec{Name: "codec", Type: EnumParameter, Max: 2, Values: []string{"gzip", "zstd", "lz4"}}, space.Parameters[0])
+		assert.Equal(t, []Observation{{Params: []float64{1, 3}, Value: 0.42}}, observations)
+	})
+}
+
+func TestExportOptunaJSONRangesMismatch(t *testing.T) {
+	result := OptimizeObjective(fastConfig(), func(params ...float64) (float64, error) {
+		return params[0], nil
+	}, ParameterRange[float64]{Min: 0, Max: 1})
+
+	err := ExportOptunaJSON(&bytes.Buffer{}, "study", result)
+
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestWarmStart(t *testing.T) {
+	hyper := ParameterRange[float64]{Min: -10, Max: 10}
+
+	// Prior observations are model data, not trials.
+	config := fastConfig()
+	config.WarmStart = []Observation{{Params: []float64{3}, Value: 9}, {Params: []float64{-1}, Value: 1}}
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return params[0] * params[0], nil
+	}, hyper)
+
+	assert.NoError(t, result.Err)
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+	opt, err := NewOptimizer(config, hyper)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, opt.Observations())
+	}
+
+	// Invalid observations are rejected.
+	config.WarmStart = []Observation{{Params: []float64{1, 2}, Value: 1}}
+
+	result = OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return params[0], nil
+	}, hyper)
+
+	assert.Equal(t, TerminationInvalidConfig, result.TerminationReason)
+	assert.EqualError(t, result.Err, "invalid configuration: WarmStart[0] has 2 parameters, expected 1")
+
+	config.WarmStart = []Observation{{Params: []float64{1}, Value: math.NaN()}}
+
+	_, err = NewOptimizer(config, hyper)
+
+	assert.EqualError(t, err, "invalid configuration: WarmStart[0] has non-finite value NaN")
+}
@@ -0,0 +1,432 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// OutputTransform is the transformation of objective values before they're
+// fed to the model, see OptimizationConfig.OutputTransform.
+type OutputTransform string
+
+const (
+	// OutputRaw feeds the values as they are.
+	OutputRaw OutputTransform = "Raw"
+
+	// OutputLog feeds sign(y) * log(1 + |y|), compressing values spanning
+	// many orders of magnitude while keeping their order, negative ones
+	// included.
+	OutputLog OutputTransform = "Log"
+
+	// OutputStandardize feeds the values minus their mean, divided by their
+	// standard deviation.
+	OutputStandardize OutputTransform = "Standardize"
+
+	// OutputRankGauss feeds the normal scores of the values: the quantile of
+	// the standard normal distribution at the rank of each value among the
+	// observed ones. Scores are bounded whatever the values, so extreme
+	// ones, e.g. failure penalties, no longer dominate the model.
+	OutputRankGauss OutputTransform = "RankGauss"
+)
+
+// outputScale maps objective values to the values fed to the model, and
+// back, as fitted on the observed values. It's never modified once fitted.
+type outputScale struct {
+	// transform is the transformation.
+	transform OutputTransform
+
+	// center and spread are the mean and the standard deviation of the
+	// values, for OutputStandardize.
+	center, spread float64
+
+	// values holds the distinct observed values, in increasing order, and
+	// scores their normal scores, for OutputRankGauss. Values in between are
+	// interpolated linearly, and extrapolated along the outermost segments.
+	values, scores []float64
+}
+
+// transformedModel is a SurrogateModel fed with transformed objective
+// values, see OptimizationConfig.OutputTransform.
+//
+// Important notes:
+// - Update takes objective values, and Predict returns predictions of the
+// transformed values, the space acquisition functions operate in. See
+// transformedValue and rawPrediction to convert
+// - Transformations fitted on the observations, e.g. OutputRankGauss,
+// refit the model on every observation, in O(n) model updates.
+//
+// Thread safety:
+// - All methods are safe for concurrent use.
+type transformedModel struct {
+	// mu protects the fields below.
+	mu sync.RWMutex
+
+	// newModel creates the model fed with transformed values.
+	newModel func() SurrogateModel
+
+	// x holds the observed points, never modified once added.
+	x [][]float64
+
+	// y holds the observed objective values.
+	y []float64
+
+	// weights holds the weights of the observations, see WeightedUpdater.
+	weights []float64
+
+	// scale is the transformation fitted on y.
+	scale *outputScale
+
+	// model is fed with the values of y transformed by scale.
+	model SurrogateModel
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the transformation.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if the transformation is unknown, nil
+// otherwise.
+func (t OutputTransform) validate() error {
+	switch t {
+	case "", OutputRaw, OutputLog, OutputStandardize, OutputRankGauss:
+		return nil
+	default:
+		return fmt.Errorf("%w: OutputTransform: unknown transform %q", ErrInvalidConfig, t)
+	}
+}
+
+// forward transforms an objective value.
+func (s *outputScale) forward(y float64) float64 {
+	switch s.transform {
+	case OutputLog:
+		return math.Copysign(math.Log1p(math.Abs(y)), y)
+	case OutputStandardize:
+		return (y - s.center) / s.spread
+	case OutputRankGauss:
+		return interpolate(s.values, s.scores, y)
+	default:
+		return y
+	}
+}
+
+// inverse transforms a predicted value back to an objective value.
+func (s *outputScale) inverse(z float64) float64 {
+	switch s.transform {
+	case OutputLog:
+		return math.Copysign(math.Expm1(math.Abs(z)), z)
+	case OutputStandardize:
+		return s.center + z*s.spread
+	case OutputRankGauss:
+		return interpolate(s.scores, s.values, z)
+	default:
+		return z
+	}
+}
+
+// slope returns the derivative of inverse at z, which scales predicted
+// standard deviations back to objective values.
+func (s *outputScale) slope(z float64) float64 {
+	switch s.transform {
+	case OutputLog:
+		return math.Exp(math.Abs(z))
+	case OutputStandardize:
+		return s.spread
+	case OutputRankGauss:
+		if len(s.scores) < 2 {
+			return 1
+		}
+
+		i := segment(s.scores, z)
+
+		return (s.values[i+1] - s.values[i]) / (s.scores[i+1] - s.scores[i])
+	default:
+		return 1
+	}
+}
+
+// Update implements SurrogateModel. y is the objective value, transformed
+// before it reaches the model, which is refitted if the transformation
+// depends on the observations.
+func (m *transformedModel) Update(x []float64, y float64) error {
+	return m.UpdateWeighted(x, y, 1)
+}
+
+// UpdateWeighted implements WeightedUpdater, weighting the observation, on
+// refits too, if the wrapped model does.
+func (m *transformedModel) UpdateWeighted(x []float64, y, weight float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := checkObservation(x, y, m.x); err != nil {
+		return err
+	}
+
+	if err := checkWeight(weight); err != nil {
+		return err
+	}
+
+	ys := append(append([]float64(nil), m.y...), y)
+
+	scale := fitOutputScale(m.scale.transform, ys)
+
+	// A fixed transformation doesn't need a refit.
+	if scale.transform == OutputLog {
+		if err := updateWeighted(m.model, x, scale.forward(y), weight); err != nil {
+			return err
+		}
+	} else {
+		model := m.newModel()
+
+		for i, point := range m.x {
+			if err := updateWeighted(model, point, scale.forward(ys[i]), m.weights[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := updateWeighted(model, x, scale.forward(y), weight); err != nil {
+			return err
+		}
+
+		m.model = model
+	}
+
+	m.x = append(m.x, append([]float64(nil), x...))
+	m.y = ys
+	m.weights = append(m.weights, weight)
+	m.scale = scale
+
+	return nil
+}
+
+// Predict implements SurrogateModel. The prediction is that of the
+// transformed value, see rawPrediction.
+func (m *transformedModel) Predict(x []float64) (mean, variance float64) {
+	return m.inner().Predict(x)
+}
+
+// PredictBatch implements BatchPredictor.
+func (m *transformedModel) PredictBatch(points [][]float64) (means, variances []float64) {
+	return predictBatch(m.inner(), points)
+}
+
+// Points implements SurrogateModel.
+func (m *transformedModel) Points() [][]float64 {
+	return m.inner().Points()
+}
+
+// Len implements SurrogateModel.
+func (m *transformedModel) Len() int {
+	return m.inner().Len()
+}
+
+// Clone implements SurrogateModel.
+func (m *transformedModel) Clone() SurrogateModel {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return &transformedModel{
+		newModel: m.newModel,
+		x:        append([][]float64(nil), m.x...),
+		y:        append([]float64(nil), m.y...),
+		weights:  append([]float64(nil), m.weights...),
+		scale:    m.scale,
+		model:    m.model.Clone(),
+	}
+}
+
+// inner returns the model fed with transformed values.
+func (m *transformedModel) inner() SurrogateModel {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.model
+}
+
+// outputScale returns the transformation fitted on the observations.
+func (m *transformedModel) outputScale() *outputScale {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.scale
+}
+
+//////
+// Helpers.
+//////
+
+// fitOutputScale fits a transformation on objective values.
+//
+// Parameters:
+// - transform: The transformation
+// - ys: Observed values, all finite
+//
+// Returns:
+// - *outputScale: The fitted transformation.
+func fitOutputScale(transform OutputTransform, ys []float64) *outputScale {
+	scale := &outputScale{transform: transform}
+
+	switch transform {
+	case OutputStandardize:
+		scale.center, scale.spread = 0, 1
+
+		if len(ys) == 0 {
+			break
+		}
+
+		// Dividing first keeps failure penalties from overflowing.
+		for _, y := range ys {
+			scale.center += y / float64(len(ys))
+		}
+
+		var largest float64
+
+		for _, y := range ys {
+			largest = math.Max(largest, math.Abs(y-scale.center))
+		}
+
+		if largest == 0 || math.IsInf(largest, 0) {
+			break
+		}
+
+		var sum float64
+
+		for _, y := range ys {
+			d := (y - scale.center) / largest
+
+			sum += d * d
+		}
+
+		scale.spread = largest * math.Sqrt(sum/float64(len(ys)))
+	case OutputRankGauss:
+		sorted := append([]float64(nil), ys...)
+
+		sort.Float64s(sorted)
+
+		n := float64(len(sorted))
+
+		for i := 0; i < len(sorted); {
+			j := i
+
+			for j+1 < len(sorted) && sorted[j+1] == sorted[i] {
+				j++
+			}
+
+			// Ties share their average rank, i+1 to j+1.
+			rank := float64(i+j)/2 + 1
+
+			scale.values = append(scale.values, sorted[i])
+			scale.scores = append(scale.scores, normalQuantile((rank-0.5)/n))
+
+			i = j + 1
+		}
+	}
+
+	return scale
+}
+
+// interpolate maps v through the piecewise linear function joining the
+// knots (xs[i], ys[i]), xs being increasing, extended along its outermost
+// segments. With a single knot, it's a shift.
+func interpolate(xs, ys []float64, v float64) float64 {
+	switch len(xs) {
+	case 0:
+		return v
+	case 1:
+		return ys[0] + v - xs[0]
+	}
+
+	i := segment(xs, v)
+
+	return ys[i] + (v-xs[i])*(ys[i+1]-ys[i])/(xs[i+1]-xs[i])
+}
+
+// segment returns the index of the segment of the increasing knots xs, at
+// least 2, v falls in, the outermost ones extending to infinity.
+func segment(xs []float64, v float64) int {
+	i := sort.SearchFloat64s(xs, v) - 1
+
+	return max(0, min(i, len(xs)-2))
+}
+
+// transformedValue transforms an objective value as the model transforms
+// observations, e.g. the best value so far, to compare it with predictions.
+//
+// Parameters:
+// - model: The model
+// - y: Objective value
+//
+// Returns:
+// - float64: The value the model would be fed, y if it isn't transformed.
+func transformedValue(model SurrogateModel, y float64) float64 {
+	if m, ok := model.(*transformedModel); ok {
+		return m.outputScale().forward(y)
+	}
+
+	return y
+}
+
+// rawPrediction predicts the model at x, transformed back to objective
+// values for reporting. Variances are scaled by the squared slope of the
+// inverse transformation at the mean.
+//
+// Parameters:
+// - model: The model
+// - x: Point to predict at
+//
+// Returns:
+// - mean: Predicted objective value
+// - variance: Variance of the prediction, in squared objective units.
+func rawPrediction(model SurrogateModel, x []float64) (mean, variance float64) {
+	m, ok := model.(*transformedModel)
+	if !ok {
+		return model.Predict(x)
+	}
+
+	// The model and its transformation are taken together, as Update
+	// replaces both.
+	m.mu.RLock()
+	inner, scale := m.model, m.scale
+	m.mu.RUnlock()
+
+	mean, variance = inner.Predict(x)
+
+	slope := scale.slope(mean)
+
+	return scale.inverse(mean), variance * slope * slope
+}
+
+//////
+// Factory.
+//////
+
+// newTransformedModel returns a model fed with transformed objective values,
+// or model itself if values are fed as they are.
+//
+// Parameters:
+// - model: The model, without observations, nil if the Surrogate factory
+// returned nil
+// - transform: The transformation, valid
+// - newModel: Creates models like model, to refit
+//
+// Returns:
+// - SurrogateModel: The model to feed with objective values.
+func newTransformedModel(model SurrogateModel, transform OutputTransform, newModel func() SurrogateModel) SurrogateModel {
+	if model == nil || transform == "" || transform == OutputRaw {
+		return model
+	}
+
+	return &transformedModel{
+		newModel: newModel,
+		scale:    fitOutputScale(transform, nil),
+		model:    model,
+	}
+}
@@ -0,0 +1,163 @@
+package ho
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// penalizedParabola updates the model with a parabola over [0, 10], its
+// minimum at 3, except for a failure penalty at 9.
+func penalizedParabola(model SurrogateModel) {
+	for x := 0.0; x <= 10; x++ {
+		y := math.Pow(x-3, 2) + 1
+
+		if x == 9 {
+			y = 1e12
+		}
+
+		model.Update([]float64{x}, y)
+	}
+}
+
+// argminMean returns the point of [0, 10], by steps of 0.1, with the lowest
+// predicted mean.
+func argminMean(model SurrogateModel) float64 {
+	best, bestMean := 0.0, math.Inf(1)
+
+	for x := 0.0; x <= 10; x += 0.1 {
+		if mean, _ := model.Predict([]float64{x}); mean < bestMean {
+			best, bestMean = x, mean
+		}
+	}
+
+	return best
+}
+
+func TestOutputScale(t *testing.T) {
+	ys := []float64{-5, 3, 3, 7, 0.5, 1e12, 42}
+
+	sorted := append([]float64(nil), ys...)
+	sort.Float64s(sorted)
+
+	for _, transform := range []OutputTransform{OutputLog, OutputStandardize, OutputRankGauss} {
+		scale := fitOutputScale(transform, ys)
+
+		// Order is preserved, within and beyond the observed values.
+		probes := append([]float64{-100}, sorted...)
+		probes = append(probes, 2e12)
+
+		for i := 1; i < len(probes); i++ {
+			if probes[i] == probes[i-1] {
+				continue
+			}
+
+			assert.Less(t, scale.forward(probes[i-1]), scale.forward(probes[i]), "%s at %v", transform, probes[i])
+		}
+
+		for _, y := range ys {
+			assert.InEpsilon(t, y, scale.inverse(scale.forward(y)), 1e-9, "%s at %v", transform, y)
+		}
+	}
+
+	// Scores are bounded whatever the values, ties share theirs.
+	scale := fitOutputScale(OutputRankGauss, ys)
+
+	assert.Equal(t, []float64{-5, 0.5, 3, 7, 42, 1e12}, scale.values)
+	assert.InDelta(t, normalQuantile(3.0/7), scale.forward(3), 1e-12)
+	assert.InDelta(t, -scale.forward(-5), scale.forward(1e12), 1e-12)
+	assert.Less(t, scale.forward(1e12), 2.0)
+
+	// A single value is only shifted.
+	scale = fitOutputScale(OutputRankGauss, []float64{4})
+
+	assert.Equal(t, 0.0, scale.forward(4))
+	assert.Equal(t, 5.0, scale.inverse(1))
+}
+
+func TestOutputTransformPenalty(t *testing.T) {
+	raw := newGaussianProcess()
+
+	penalizedParabola(raw)
+
+	// The penalty dominates the raw model, and even the log transformed
+	// one: the parabola is flattened out.
+	assert.Greater(t, math.Abs(argminMean(raw)-3), 1.0)
+
+	newModel := func() SurrogateModel { return newGaussianProcess() }
+
+	logged := newTransformedModel(newModel(), OutputLog, newModel)
+
+	penalizedParabola(logged)
+
+	assert.Greater(t, math.Abs(argminMean(logged)-3), 1.0)
+
+	model := newTransformedModel(newModel(), OutputRankGauss, newModel)
+
+	penalizedParabola(model)
+
+	assert.Equal(t, 11, model.Len())
+	assert.InDelta(t, 3, argminMean(model), 0.5)
+
+	// Predictions at observed points are transformed back to the observed
+	// values.
+	mean, variance := rawPrediction(model, []float64{5})
+
+	assert.InDelta(t, 5, mean, 0.5)
+	assert.Greater(t, variance, 0.0)
+
+	// The best value is compared to predictions in the same space.
+	best, _ := model.Predict([]float64{3})
+
+	assert.InDelta(t, transformedValue(model, 1), best, 0.1)
+}
+
+func TestOutputTransform(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	t.Run("rank gauss", func(t *testing.T) {
+		var bests []float64
+
+		config := fastConfig()
+		config.Seed = 1
+		config.OutputTransform = OutputRankGauss
+		config.AcquisitionFunc = func(mean, variance float64, params AcquisitionParams) float64 {
+			bests = append(bests, params.BestSoFar)
+
+			return LowerConfidenceBound(mean, variance, params)
+		}
+
+		result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+			return 1e6*math.Pow(params[0]-3, 4) + 1, nil
+		}, ranges...)
+
+		assert.NoError(t, result.Err)
+		assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+		// BestSoFar is a normal score, not an objective value.
+		for _, best := range bests {
+			assert.Less(t, math.Abs(best), 3.0)
+		}
+
+		// Predictions are objective values.
+		trial := result.Trials[0]
+
+		mean, _, err := result.Predict(trial.Params)
+
+		assert.NoError(t, err)
+		assert.InEpsilon(t, trial.ExecutionTime, mean, 0.05)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		config := fastConfig()
+		config.OutputTransform = "Sqrt"
+
+		result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+			return params[0], nil
+		}, ranges...)
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+}
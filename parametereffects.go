@@ -0,0 +1,152 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// DefaultEffectResolution is the maximum number of values each parameter is
+// swept over by ParameterEffects.
+const DefaultEffectResolution = 20
+
+// EffectCurve is the model prediction along a parameter sweep, see
+// ParameterEffect.
+type EffectCurve struct {
+	// Mean holds the predicted mean at each sweep value.
+	Mean []float64
+
+	// StdDev holds the predicted standard deviation at each sweep value. Mean
+	// plus or minus StdDev is the uncertainty band.
+	StdDev []float64
+}
+
+// ParameterEffect is the effect of a single parameter on the objective, as
+// the model believes it, see Result.ParameterEffects.
+type ParameterEffect struct {
+	// Dim is the index of the parameter.
+	Dim int
+
+	// Name is the name of the parameter. Unnamed parameters are named
+	// "param<i>", as in TrialRecord.
+	Name string
+
+	// Values holds the sweep values, in increasing order.
+	Values []float64
+
+	// PartialDependence is the prediction averaged over the observed points,
+	// the parameter being replaced by each sweep value. It's the effect of
+	// the parameter across the explored space.
+	PartialDependence EffectCurve
+
+	// Slice is the prediction with the other parameters held at the best
+	// parameters. It's the effect of the parameter around the incumbent.
+	Slice EffectCurve
+}
+
+//////
+// Methods.
+//////
+
+// ParameterEffects computes, for each parameter, the model's predicted
+// objective as the parameter sweeps its range: averaged over the observed
+// points (partial dependence), and with the other parameters held at the
+// best parameters (slice). Comparing the amplitude of the curves tells which
+// parameters matter.
+//
+// Returns:
+// - []ParameterEffect: The effects, one per parameter, in range order
+// - error: Wrapping ErrInvalidConfig if the run has no best parameters.
+//
+// Usage example:
+//
+//	effects, err := result.ParameterEffects()
+//	if err != nil {
+//	    return err
+//	}
+//
+//	for _, effect := range effects {
+//	    fmt.Println(effect.Name, slices.Max(effect.PartialDependence.Mean)-slices.Min(effect.PartialDependence.Mean))
+//	}
+//
+// Important notes:
+// - Parameters are swept as in PredictGrid, over up to
+// DefaultEffectResolution values, on the lattice of integer and stepped
+// parameters
+// - The partial dependence standard deviation is the average of the
+// standard deviations over the observed points
+// - Warm start observations (see OptimizationConfig.WarmStart) are observed
+// points too.
+func (r *Result[T]) ParameterEffects() ([]ParameterEffect, error) {
+	effects := make([]ParameterEffect, len(r.hypers))
+
+	for dim := range r.hypers {
+		slice, err := r.PredictGrid([]int{dim}, DefaultEffectResolution)
+		if err != nil {
+			return nil, err
+		}
+
+		effects[dim] = ParameterEffect{
+			Dim:    dim,
+			Name:   slice.Names[0],
+			Values: slice.X,
+			Slice: EffectCurve{
+				Mean:   column(slice.Mean),
+				StdDev: column(slice.StdDev),
+			},
+			PartialDependence: r.partialDependence(dim, slice.X),
+		}
+	}
+
+	if len(effects) == 0 {
+		return nil, fmt.Errorf("%w: the run has no best parameters to predict around", ErrInvalidConfig)
+	}
+
+	return effects, nil
+}
+
+// partialDependence averages the predictions over the observed points, dim
+// being replaced by each value, at the coordinate the model sees, see
+// modelCoordinates.
+func (r *Result[T]) partialDependence(dim int, values []float64) EffectCurve {
+	points := r.model.Points()
+
+	curve := EffectCurve{
+		Mean:   make([]float64, len(values)),
+		StdDev: make([]float64, len(values)),
+	}
+
+	for i, v := range values {
+		for _, point := range points {
+			point[dim] = v - float64(r.hypers[dim].Min)
+
+			mean, variance := rawPrediction(r.model, point)
+
+			curve.Mean[i] += mean
+			curve.StdDev[i] += math.Sqrt(math.Max(variance, 0))
+		}
+
+		curve.Mean[i] /= float64(len(points))
+		curve.StdDev[i] /= float64(len(points))
+	}
+
+	return curve
+}
+
+//////
+// Helpers.
+//////
+
+// column returns the first column of a matrix.
+func column(matrix [][]float64) []float64 {
+	values := make([]float64, len(matrix))
+
+	for i, row := range matrix {
+		values[i] = row[0]
+	}
+
+	return values
+}
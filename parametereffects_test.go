@@ -0,0 +1,54 @@
+package ho
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterEffects(t *testing.T) {
+	// The model is fitted to the additive f(x, y) = (x-4)^2 + y, observed on a
+	// 3x3 lattice, so predictions at observed points are the observed values.
+	effects, err := knownSurface().ParameterEffects()
+
+	if !assert.NoError(t, err) || !assert.Len(t, effects, 2) {
+		return
+	}
+
+	x, y := effects[0], effects[1]
+
+	assert.Equal(t, 0, x.Dim)
+	assert.Equal(t, "x", x.Name)
+	assert.Equal(t, []float64{0, 1, 2, 3, 4, 5, 6, 7, 8}, x.Values)
+
+	assert.Equal(t, "param1", y.Name)
+	assert.Equal(t, []float64{0, 2, 4, 6, 8}, y.Values)
+
+	for i, v := range x.Values {
+		if int(v)%4 != 0 {
+			continue
+		}
+
+		// Averaged over observed y values (mean 4), or at the best y (0).
+		assert.InDelta(t, (v-4)*(v-4)+4, x.PartialDependence.Mean[i], 0.05)
+		assert.InDelta(t, (v-4)*(v-4), x.Slice.Mean[i], 0.05)
+	}
+
+	for i, v := range y.Values {
+		if int(v)%4 != 0 {
+			continue
+		}
+
+		// Averaged over observed x effects (mean 32/3), or at the best x (4).
+		assert.InDelta(t, 32.0/3+v, y.PartialDependence.Mean[i], 0.05)
+		assert.InDelta(t, v, y.Slice.Mean[i], 0.05)
+	}
+
+	// Uncertainty is higher between observed values.
+	assert.Less(t, x.Slice.StdDev[4], x.Slice.StdDev[2])
+	assert.Less(t, y.PartialDependence.StdDev[2], y.PartialDependence.StdDev[1])
+
+	_, err = (&Result[int]{}).ParameterEffects()
+
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
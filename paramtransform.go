@@ -0,0 +1,133 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// transformCheckPoints is the number of evenly spaced values of a range,
+	// bounds included, Inverse must map back, see ParameterRange.Transform.
+	transformCheckPoints = 11
+
+	// transformTolerance is the relative error Inverse may map float values
+	// back with.
+	transformTolerance = 1e-9
+)
+
+//////
+// Methods.
+//////
+
+// checkTransforms checks that the Transform of each range is paired with its
+// Inverse, and that Inverse maps values evenly spaced over the range back,
+// see ParameterRange.Transform.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a pair is incomplete, or doesn't
+// round-trip, nil otherwise.
+func (o *optimizer[T]) checkTransforms() error {
+	names := o.paramNames()
+
+	for i, hyper := range o.hypers {
+		switch {
+		case hyper.Transform == nil && hyper.Inverse == nil:
+			continue
+		case hyper.Transform == nil || hyper.Inverse == nil:
+			return fmt.Errorf("%w: parameter %q: Transform and Inverse must be set together", ErrInvalidConfig, paramName(names, i))
+		}
+
+		for k := 0; k < transformCheckPoints; k++ {
+			x := rangeValue(hyper, float64(hyper.Min)+float64(k)/(transformCheckPoints-1)*(float64(hyper.Max)-float64(hyper.Min)))
+
+			v := hyper.Transform(float64(x))
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("%w: parameter %q: Transform(%v) = %v isn't finite", ErrInvalidConfig, paramName(names, i), x, v)
+			}
+
+			back := hyper.Inverse(float64(fromFloat64[T](v)))
+
+			if !roundTrips(x, back) {
+				return fmt.Errorf("%w: parameter %q: Inverse(Transform(%v)) = %v, expected %v", ErrInvalidConfig, paramName(names, i), x, back, x)
+			}
+		}
+	}
+
+	return nil
+}
+
+// benchmarkParams returns the values the benchmark receives for parameters of
+// the search space, see ParameterRange.Transform.
+//
+// Returns:
+// - []T: The values, a copy if a range is transformed
+// - bool: Whether a range is transformed.
+func (o *optimizer[T]) benchmarkParams(params []T) ([]T, bool) {
+	return benchmarkParams(o.hypers, params)
+}
+
+// benchmarkBest returns the best parameters as the benchmark received them.
+func (r *Result[T]) benchmarkBest() []T {
+	if r.BestBenchmarkParams != nil {
+		return r.BestBenchmarkParams
+	}
+
+	return r.BestParams
+}
+
+//////
+// Helpers.
+//////
+
+// roundTrips returns whether back, the value Inverse mapped a transformed
+// value back to, is x: the same integer for integer types, x within
+// transformTolerance otherwise.
+func roundTrips[T constraints.Integer | constraints.Float](x T, back float64) bool {
+	if math.IsNaN(back) || math.IsInf(back, 0) {
+		return false
+	}
+
+	if half := 0.5; T(half) == 0 {
+		return fromFloat64[T](back) == x
+	}
+
+	return math.Abs(back-float64(x)) <= transformTolerance*max(1, math.Abs(float64(x)))
+}
+
+// benchmarkParams applies the Transform of each range to the parameters.
+//
+// Parameters:
+// - hypers: The ranges
+// - params: Parameters of the search space
+//
+// Returns:
+// - []T: The values the benchmark receives, rounded for integer types, a
+// copy if a range is transformed, params otherwise
+// - bool: Whether a range is transformed.
+func benchmarkParams[T constraints.Integer | constraints.Float](hypers []ParameterRange[T], params []T) ([]T, bool) {
+	var transformed []T
+
+	for i, hyper := range hypers {
+		if hyper.Transform == nil || i >= len(params) {
+			continue
+		}
+
+		if transformed == nil {
+			transformed = append([]T(nil), params...)
+		}
+
+		transformed[i] = fromFloat64[T](hyper.Transform(float64(params[i])))
+	}
+
+	if transformed == nil {
+		return params, false
+	}
+
+	return transformed, true
+}
@@ -0,0 +1,120 @@
+package ho
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// log2Range searches log2 of a buffer size, in bytes.
+var log2Range = ParameterRange[int]{Name: "BufferSize", Min: 8, Max: 20, Transform: math.Exp2, Inverse: math.Log2}
+
+func TestParameterTransform(t *testing.T) {
+	config := fastConfig()
+
+	var received []int
+
+	result := OptimizeObjective(config, func(params ...int) (float64, error) {
+		received = append(received, params[0])
+
+		// Best at 16KB, and flat in log space.
+		return math.Abs(math.Log2(float64(params[0])) - 14), nil
+	}, log2Range)
+
+	assert.NoError(t, result.Err)
+
+	for i, trial := range result.Trials {
+		// The model searches exponents, the benchmark receives bytes.
+		assert.GreaterOrEqual(t, trial.Params[0], 8)
+		assert.LessOrEqual(t, trial.Params[0], 20)
+		assert.Equal(t, []int{1 << trial.Params[0]}, trial.BenchmarkParams)
+		assert.Equal(t, received[i], trial.BenchmarkParams[0])
+	}
+
+	assert.Equal(t, []int{1 << result.BestParams[0]}, result.BestBenchmarkParams)
+
+	var cfg struct{ BufferSize int }
+
+	if assert.NoError(t, result.Scan(&cfg)) {
+		assert.Equal(t, result.BestBenchmarkParams[0], cfg.BufferSize)
+	}
+
+	// Exports record both.
+	trial := result.Trials[0]
+	record := result.Records()[0]
+
+	assert.Equal(t, map[string]float64{"BufferSize": float64(trial.Params[0])}, record.Params)
+	assert.Equal(t, map[string]float64{"BufferSize": float64(trial.BenchmarkParams[0])}, record.BenchmarkParams)
+
+	assert.Equal(t, trial.BenchmarkParams, restoreTrial([]ParameterRange[int]{log2Range}, newCheckpointTrial(trial)).BenchmarkParams)
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, ExportOptunaJSON(&buf, "transform", result, log2Range))
+
+	var study optunaStudy
+
+	if assert.NoError(t, json.Unmarshal(buf.Bytes(), &study)) {
+		assert.Equal(t, map[string]any{"BufferSize": float64(trial.BenchmarkParams[0])}, study.Trials[0].UserAttrs[optunaBenchmarkParamsAttr])
+	}
+
+	// Replays receive what the benchmark received.
+	_, err := result.ReplayTrial(trial.TrialID, func(_ TrialInfo, params ...int) error {
+		assert.Equal(t, trial.BenchmarkParams, params)
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestParameterTransformAskTell(t *testing.T) {
+	opt, err := NewOptimizer(fastConfig(), log2Range)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	suggestion, err := opt.Ask()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []int{1 << suggestion.Params[0]}, suggestion.BenchmarkParams)
+
+	trial, err := opt.Tell(suggestion.TrialID, 1, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, suggestion.BenchmarkParams, trial.BenchmarkParams)
+}
+
+func TestParameterTransformInvalid(t *testing.T) {
+	objective := func(params ...float64) (float64, error) { return params[0], nil }
+
+	for _, tt := range []struct {
+		name  string
+		hyper ParameterRange[float64]
+		want  string
+	}{
+		{"no inverse", ParameterRange[float64]{Name: "x", Min: 1, Max: 10, Transform: math.Exp2}, `parameter "x": Transform and Inverse must be set together`},
+		{"no transform", ParameterRange[float64]{Name: "x", Min: 1, Max: 10, Inverse: math.Log2}, `parameter "x": Transform and Inverse must be set together`},
+		{"not an inverse", ParameterRange[float64]{Name: "x", Min: 1, Max: 10, Transform: math.Exp2, Inverse: math.Log10}, `parameter "x": Inverse(Transform(1)) = 0.3010299956639812, expected 1`},
+		{"not finite", ParameterRange[float64]{Name: "x", Min: 0, Max: 10, Transform: math.Log, Inverse: math.Exp}, `parameter "x": Transform(0) = -Inf isn't finite`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := OptimizeObjective(fastConfig(), objective, tt.hyper)
+
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+			assert.ErrorContains(t, result.Err, tt.want)
+		})
+	}
+
+	// Integer values need only round back to the same integer.
+	result := OptimizeObjective(fastConfig(), func(params ...int) (float64, error) {
+		return float64(params[0]), nil
+	}, ParameterRange[int]{Min: 1, Max: 10, Transform: func(v float64) float64 { return v / 3 }, Inverse: func(v float64) float64 { return v * 3 }})
+
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+}
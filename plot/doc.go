@@ -0,0 +1,20 @@
+// Package plot draws the history of a run as image files, e.g. for a wiki
+// page, with gonum.org/v1/plot:
+//
+//	files, err := plot.WriteFiles(result, "plots", plot.Options{Format: plot.SVG})
+//	if err != nil {
+//	    return err
+//	}
+//
+// Three kinds of charts are drawn: the best value so far by evaluation, the
+// measured value against each parameter, colored by evaluation, from blue
+// (early) to red (late), and the calibration of the model, its predictions
+// against the measured values.
+//
+// The series behind the charts are available as is, see Convergence,
+// Parameters and Calibration, and WriteCSVFiles writes them as CSV files
+// instead, to plot with other tools.
+//
+// The package is its own module, github.com/thalesfsp/ho/plot, so the
+// optimizer doesn't depend on gonum/plot.
+package plot
@@ -0,0 +1,241 @@
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+
+	"github.com/thalesfsp/ho"
+	"golang.org/x/exp/constraints"
+	gonumplot "gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette/moreland"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultWidth and defaultHeight are the Options.Width and
+	// Options.Height used if unset.
+	defaultWidth, defaultHeight = 6 * vg.Inch, 4 * vg.Inch
+)
+
+// Format is the format of the image files.
+type Format string
+
+const (
+	// PNG writes PNG files.
+	PNG Format = "png"
+
+	// SVG writes SVG files.
+	SVG Format = "svg"
+)
+
+// Options configures WriteFiles.
+type Options struct {
+	// Format is the format of the files.
+	// If empty, PNG is used.
+	Format Format
+
+	// Width and Height are the size of the charts.
+	// If 0, 6 by 4 inches are used.
+	Width, Height vg.Length
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the options, and sets the defaults.
+func (o *Options) validate() error {
+	switch o.Format {
+	case "":
+		o.Format = PNG
+	case PNG, SVG:
+	default:
+		return fmt.Errorf("%w: unknown format %q, expected %q or %q", ho.ErrInvalidConfig, o.Format, PNG, SVG)
+	}
+
+	if o.Width < 0 || o.Height < 0 {
+		return fmt.Errorf("%w: negative size %vx%v", ho.ErrInvalidConfig, o.Width, o.Height)
+	}
+
+	if o.Width == 0 {
+		o.Width = defaultWidth
+	}
+
+	if o.Height == 0 {
+		o.Height = defaultHeight
+	}
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// points returns the points of a series.
+func points(s Series) plotter.XYs {
+	xys := make(plotter.XYs, len(s.X))
+
+	for i := range xys {
+		xys[i] = plotter.XY{X: s.X[i], Y: s.Y[i]}
+	}
+
+	return xys
+}
+
+// newPlot returns an empty chart, titled and labelled as the series.
+func newPlot(s Series) *gonumplot.Plot {
+	p := gonumplot.New()
+
+	p.Title.Text = s.Title
+	p.X.Label.Text = s.XLabel
+	p.Y.Label.Text = s.YLabel
+
+	p.Add(plotter.NewGrid())
+
+	return p
+}
+
+// convergencePlot draws the best value so far as a step line.
+func convergencePlot(s Series) (*gonumplot.Plot, error) {
+	p := newPlot(s)
+
+	line, err := plotter.NewLine(points(s))
+	if err != nil {
+		return nil, err
+	}
+
+	line.StepStyle = plotter.PostStep
+	line.Color = color.RGBA{R: 0xcc, G: 0x33, B: 0x33, A: 0xff}
+	line.Width = vg.Points(1.5)
+
+	p.Add(line)
+
+	return p, nil
+}
+
+// parameterPlot draws the values as a scatter, colored by evaluation.
+func parameterPlot(s Series) (*gonumplot.Plot, error) {
+	p := newPlot(s)
+
+	scatter, err := plotter.NewScatter(points(s))
+	if err != nil {
+		return nil, err
+	}
+
+	// Evaluations count from 1, and the range can't be empty.
+	last := 2.0
+
+	for _, z := range s.Z {
+		last = max(last, z)
+	}
+
+	colors := moreland.SmoothBlueRed()
+	colors.SetMin(1)
+	colors.SetMax(last)
+
+	scatter.GlyphStyleFunc = func(i int) draw.GlyphStyle {
+		style := scatter.GlyphStyle
+
+		if c, err := colors.At(s.Z[i]); err == nil {
+			style.Color = c
+		}
+
+		return style
+	}
+
+	p.Add(scatter)
+
+	return p, nil
+}
+
+// calibrationPlot draws the measured values against the predicted ones, and
+// the diagonal of a perfect calibration.
+func calibrationPlot(s Series) (*gonumplot.Plot, error) {
+	p := newPlot(s)
+
+	scatter, err := plotter.NewScatter(points(s))
+	if err != nil {
+		return nil, err
+	}
+
+	scatter.Color = color.RGBA{R: 0x33, G: 0x66, B: 0xcc, A: 0xff}
+
+	diagonal := plotter.NewFunction(func(x float64) float64 { return x })
+	diagonal.Dashes = []vg.Length{vg.Points(4), vg.Points(3)}
+
+	p.Add(scatter, diagonal)
+	p.Legend.Add("prediction", scatter)
+	p.Legend.Add("perfect calibration", diagonal)
+	p.Legend.Top = true
+
+	return p, nil
+}
+
+//////
+// Exported functionalities.
+//////
+
+// WriteFiles draws the history of the result in a directory, a file per
+// chart: convergence, a parameter_<name> chart per parameter, and
+// calibration if the result has a model. See Convergence, Parameters and
+// Calibration for the data.
+//
+// Parameters:
+// - r: The result
+// - dir: The directory, created if needed
+// - options: Configures the files
+//
+// Returns:
+// - []string: The paths of the files written
+// - error: Wrapping ho.ErrInvalidConfig if the options are invalid, or the
+// error of the file system.
+//
+// Usage example:
+//
+//	files, err := plot.WriteFiles(result, "plots", plot.Options{})
+func WriteFiles[T constraints.Integer | constraints.Float](r *ho.Result[T], dir string, options Options) ([]string, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var files []string
+
+	for i, s := range allSeries(r) {
+		chart := parameterPlot
+
+		switch {
+		case i == 0:
+			chart = convergencePlot
+		case s.Name == "calibration":
+			chart = calibrationPlot
+		}
+
+		p, err := chart(s)
+		if err != nil {
+			return files, fmt.Errorf("%s: %w", s.Name, err)
+		}
+
+		path := filepath.Join(dir, s.Name+"."+string(options.Format))
+
+		if err := p.Save(options.Width, options.Height, path); err != nil {
+			return files, err
+		}
+
+		files = append(files, path)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,180 @@
+package plot
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho"
+)
+
+// run returns the result of a short run over "Workers" and "Batch size", its
+// second trial failing.
+func run() *ho.Result[int] {
+	config := ho.DefaultConfig()
+	config.InitialSamples = 3
+	config.Iterations = 7
+	config.NumCandidates = 10
+	config.Seed = 3
+
+	calls := 0
+
+	return ho.OptimizeObjective(config, func(params ...int) (float64, error) {
+		calls++
+
+		if calls == 2 {
+			return 0, errors.New("boom")
+		}
+
+		return float64((params[0]-6)*(params[0]-6) + params[1]), nil
+	},
+		ho.ParameterRange[int]{Name: "Workers", Min: 1, Max: 12},
+		ho.ParameterRange[int]{Name: "Batch size", Min: 0, Max: 8},
+	)
+}
+
+// readCSV reads a CSV file as numbers, after its header.
+func readCSV(t *testing.T, path string) (header []string, rows [][]float64) {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		return nil, nil
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if !assert.NoError(t, err) || !assert.NotEmpty(t, records) {
+		return nil, nil
+	}
+
+	for _, record := range records[1:] {
+		row := make([]float64, len(record))
+
+		for i, field := range record {
+			row[i], err = strconv.ParseFloat(field, 64)
+			assert.NoError(t, err)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return records[0], rows
+}
+
+func TestWriteFiles(t *testing.T) {
+	result := run()
+
+	for _, format := range []Format{PNG, SVG} {
+		t.Run(string(format), func(t *testing.T) {
+			dir := t.TempDir()
+
+			files, err := WriteFiles(result, dir, Options{Format: format})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, []string{
+				filepath.Join(dir, "convergence."+string(format)),
+				filepath.Join(dir, "parameter_Workers."+string(format)),
+				filepath.Join(dir, "parameter_Batch_size."+string(format)),
+				filepath.Join(dir, "calibration."+string(format)),
+			}, files)
+
+			for _, file := range files {
+				info, err := os.Stat(file)
+				if assert.NoError(t, err) {
+					assert.Greater(t, info.Size(), int64(1000), file)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := WriteFiles(result, t.TempDir(), Options{Format: "gif"})
+		assert.ErrorIs(t, err, ho.ErrInvalidConfig)
+
+		_, err = WriteFiles(result, t.TempDir(), Options{Width: -1})
+		assert.ErrorIs(t, err, ho.ErrInvalidConfig)
+	})
+
+	t.Run("no model", func(t *testing.T) {
+		// A result without a model, e.g. decoded, skips calibration.
+		files, err := WriteFiles(&ho.Result[int]{
+			ParamNames: []string{"x"},
+			Trials:     []ho.Trial[int]{{Params: []int{1}, ExecutionTime: 2, Status: ho.TrialCompleted}},
+		}, t.TempDir(), Options{})
+
+		assert.NoError(t, err)
+		assert.Len(t, files, 2)
+	})
+}
+
+func TestWriteCSVFiles(t *testing.T) {
+	result := run()
+	dir := t.TempDir()
+
+	files, err := WriteCSVFiles(result, dir)
+	if !assert.NoError(t, err) || !assert.Len(t, files, 4) {
+		return
+	}
+
+	var completed []int
+
+	for i, trial := range result.Trials {
+		if trial.Status == ho.TrialCompleted {
+			completed = append(completed, i)
+		}
+	}
+
+	assert.Len(t, completed, len(result.Trials)-1)
+
+	header, rows := readCSV(t, filepath.Join(dir, "convergence.csv"))
+
+	assert.Equal(t, []string{"evaluation", "best"}, header)
+
+	if assert.Len(t, rows, len(completed)) {
+		best := rows[0][1]
+
+		for j, i := range completed {
+			best = min(best, result.Trials[i].ExecutionTime)
+
+			assert.Equal(t, []float64{float64(i + 1), best}, rows[j])
+		}
+
+		assert.Equal(t, result.BestTime, rows[len(rows)-1][1])
+	}
+
+	for d, name := range []string{"parameter_Workers.csv", "parameter_Batch_size.csv"} {
+		header, rows := readCSV(t, filepath.Join(dir, name))
+
+		assert.Equal(t, []string{result.ParamNames[d], "value", "evaluation"}, header)
+
+		if assert.Len(t, rows, len(completed)) {
+			for j, i := range completed {
+				trial := result.Trials[i]
+
+				assert.Equal(t, []float64{float64(trial.Params[d]), trial.ExecutionTime, float64(i + 1)}, rows[j])
+			}
+		}
+	}
+
+	header, rows = readCSV(t, filepath.Join(dir, "calibration.csv"))
+
+	assert.Equal(t, []string{"predicted", "measured", "stddev"}, header)
+
+	if assert.Len(t, rows, len(completed)) {
+		for j, i := range completed {
+			trial := result.Trials[i]
+
+			mean, stddev, err := result.Predict(trial.Params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, []float64{mean, trial.ExecutionTime, stddev}, rows[j])
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package plot
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/thalesfsp/ho"
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Series is the data behind a chart: points, and optionally a third value
+// per point, e.g. the evaluation a point comes from.
+type Series struct {
+	// Name identifies the series, and names its files, e.g. "convergence".
+	Name string
+
+	// Title is the title of the chart.
+	Title string
+
+	// XLabel, YLabel and ZLabel label the values, and head the CSV columns.
+	// ZLabel is empty if there's no Z.
+	XLabel, YLabel, ZLabel string
+
+	// X and Y hold the coordinates of the points.
+	X, Y []float64
+
+	// Z holds the third value of each point, nil if there's none.
+	Z []float64
+}
+
+//////
+// Methods.
+//////
+
+// WriteCSV writes the series as CSV, a header then a row per point: x, y and
+// z if any.
+//
+// Parameters:
+// - w: The writer
+//
+// Returns:
+// - error: The error of the writer, if any.
+func (s Series) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{s.XLabel, s.YLabel}
+
+	if s.Z != nil {
+		header = append(header, s.ZLabel)
+	}
+
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i := range s.X {
+		row := []string{formatFloat(s.X[i]), formatFloat(s.Y[i])}
+
+		if s.Z != nil {
+			row = append(row, formatFloat(s.Z[i]))
+		}
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+//////
+// Helpers.
+//////
+
+// formatFloat formats a value with the fewest digits that read back to it.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// paramName returns the name of parameter i, or "param<i>" if it's unnamed.
+func paramName(names []string, i int) string {
+	if i < len(names) && names[i] != "" {
+		return names[i]
+	}
+
+	return fmt.Sprintf("param%d", i)
+}
+
+// fileName turns a name into a file name stem: anything but letters, digits,
+// '-' and '_' becomes '_'.
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// writeFile creates a file and writes it with write.
+func writeFile(path string, write func(io.Writer) error) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return write(f)
+}
+
+// allSeries returns the series of the result, calibration included if the
+// result has a model.
+func allSeries[T constraints.Integer | constraints.Float](r *ho.Result[T]) []Series {
+	series := append([]Series{Convergence(r)}, Parameters(r)...)
+
+	if calibration, err := Calibration(r); err == nil {
+		series = append(series, calibration)
+	}
+
+	return series
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Convergence returns the best value so far after each completed
+// evaluation, X being the evaluation, from 1, in the order of
+// Result.Trials.
+//
+// Parameters:
+// - r: The result
+//
+// Returns:
+// - Series: The series, named "convergence".
+func Convergence[T constraints.Integer | constraints.Float](r *ho.Result[T]) Series {
+	s := Series{Name: "convergence", Title: "Best value so far", XLabel: "evaluation", YLabel: "best"}
+
+	for i, trial := range r.Trials {
+		if trial.Status != ho.TrialCompleted {
+			continue
+		}
+
+		best := trial.ExecutionTime
+
+		if n := len(s.Y); n > 0 && s.Y[n-1] < best {
+			best = s.Y[n-1]
+		}
+
+		s.X = append(s.X, float64(i+1))
+		s.Y = append(s.Y, best)
+	}
+
+	return s
+}
+
+// Parameters returns, for each parameter, the measured value of each
+// completed evaluation against the value of the parameter, Z being the
+// evaluation, from 1.
+//
+// Parameters:
+// - r: The result
+//
+// Returns:
+// - []Series: A series per parameter, in the order of the ranges, named
+// "parameter_<name>".
+func Parameters[T constraints.Integer | constraints.Float](r *ho.Result[T]) []Series {
+	var series []Series
+
+	for i, trial := range r.Trials {
+		if trial.Status != ho.TrialCompleted {
+			continue
+		}
+
+		for d, v := range trial.Params {
+			for len(series) <= d {
+				name := paramName(r.ParamNames, len(series))
+
+				series = append(series, Series{
+					Name:   "parameter_" + fileName(name),
+					Title:  "Value by " + name,
+					XLabel: name,
+					YLabel: "value",
+					ZLabel: "evaluation",
+				})
+			}
+
+			series[d].X = append(series[d].X, float64(v))
+			series[d].Y = append(series[d].Y, trial.ExecutionTime)
+			series[d].Z = append(series[d].Z, float64(i+1))
+		}
+	}
+
+	return series
+}
+
+// Calibration returns the value the model predicts for each completed
+// evaluation against the measured one, Z being the standard deviation of
+// the prediction.
+//
+// Parameters:
+// - r: The result
+//
+// Returns:
+// - Series: The series, named "calibration"
+// - error: Wrapping ho.ErrInvalidConfig if the result has completed trials
+// but no model, see Result.Predict.
+//
+// Important notes:
+// - The model is the one at the end of the run, fitted on the evaluations it
+// predicts: points far from the diagonal are evaluations it couldn't fit,
+// e.g. noisy ones.
+func Calibration[T constraints.Integer | constraints.Float](r *ho.Result[T]) (Series, error) {
+	s := Series{
+		Name:   "calibration",
+		Title:  "Predicted against measured value",
+		XLabel: "predicted",
+		YLabel: "measured",
+		ZLabel: "stddev",
+		Z:      []float64{},
+	}
+
+	for _, trial := range r.Trials {
+		if trial.Status != ho.TrialCompleted {
+			continue
+		}
+
+		mean, stddev, err := r.Predict(trial.Params)
+		if err != nil {
+			return Series{}, err
+		}
+
+		s.X = append(s.X, mean)
+		s.Y = append(s.Y, trial.ExecutionTime)
+		s.Z = append(s.Z, stddev)
+	}
+
+	return s, nil
+}
+
+// WriteCSVFiles writes the series of the result as CSV files in a
+// directory, for plotting with other tools: convergence.csv, a
+// parameter_<name>.csv per parameter, and calibration.csv if the result has
+// a model.
+//
+// Parameters:
+// - r: The result
+// - dir: The directory, created if needed
+//
+// Returns:
+// - []string: The paths of the files written
+// - error: The error of the file system, if any.
+//
+// Usage example:
+//
+//	files, err := plot.WriteCSVFiles(result, "plots")
+func WriteCSVFiles[T constraints.Integer | constraints.Float](r *ho.Result[T], dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var files []string
+
+	for _, s := range allSeries(r) {
+		path := filepath.Join(dir, s.Name+".csv")
+
+		if err := writeFile(path, s.WriteCSV); err != nil {
+			return files, err
+		}
+
+		files = append(files, path)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,112 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Methods.
+//////
+
+// Predict asks the model what it thinks of parameters, without evaluating
+// them: the predicted objective value, and its uncertainty.
+//
+// Parameters:
+// - params: Parameter values, in the same order as the ranges
+//
+// Returns:
+// - mean: Predicted objective value, e.g. execution time in nanoseconds
+// - stddev: Standard deviation of the prediction
+// - error: Wrapping ErrInvalidConfig if params doesn't have one value per
+// range, or ErrInvalidObservation if the model can't predict them, e.g.
+// its points have other dimensions.
+//
+// Usage example:
+//
+//	mean, stddev, err := opt.Predict([]int{64, 8})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	fmt.Printf("expected %v ± %v\n", time.Duration(mean), time.Duration(stddev))
+//
+// Important notes:
+// - Parameters are fed to the model as they are during the run, so
+// predictions are those candidates are scored with, without the lies of
+// pending suggestions
+// - Parameters outside the ranges are predicted too, with the uncertainty of
+// extrapolation
+// - Safe to call concurrently with Ask and Tell, without waiting for them.
+func (opt *Optimizer[T]) Predict(params []T) (mean, stddev float64, err error) {
+	o := opt.current.Load()
+
+	return predictParams(o.model, o.hypers, params)
+}
+
+// Predict asks the model at the end of the run what it thinks of parameters,
+// see Optimizer.Predict.
+//
+// Parameters:
+// - params: Parameter values, in the same order as BestParams
+//
+// Returns:
+// - mean: Predicted objective value, e.g. execution time in nanoseconds
+// - stddev: Standard deviation of the prediction
+// - error: Wrapping ErrInvalidConfig if params doesn't have one value per
+// range, or the result has no model, e.g. the run didn't start, or
+// ErrInvalidObservation if the model can't predict them.
+//
+// Usage example:
+//
+//	result := Optimize(config, benchmark, ranges...)
+//
+//	mean, stddev, err := result.Predict([]int{64, 8})
+//
+// Important notes:
+// - The model is a snapshot taken when the result was, as for PredictGrid.
+func (r *Result[T]) Predict(params []T) (mean, stddev float64, err error) {
+	if r.model == nil {
+		return 0, 0, fmt.Errorf("%w: the result has no model to predict with", ErrInvalidConfig)
+	}
+
+	return predictParams(r.model, r.hypers, params)
+}
+
+//////
+// Helpers.
+//////
+
+// predictParams predicts the model at parameters, converted as the run
+// converts them.
+//
+// Parameters:
+// - model: The model
+// - hypers: The parameter ranges
+// - params: Parameter values
+//
+// Returns:
+// - mean: Predicted mean
+// - stddev: Predicted standard deviation
+// - error: Wrapping ErrInvalidConfig if params doesn't have one value per
+// range, or ErrInvalidObservation if the model predicts NaN, e.g. for a
+// point without the dimensions of its observations.
+func predictParams[T constraints.Integer | constraints.Float](
+	model SurrogateModel,
+	hypers []ParameterRange[T],
+	params []T,
+) (mean, stddev float64, err error) {
+	if len(params) != len(hypers) {
+		return 0, 0, fmt.Errorf("%w: %d parameters, expected %d", ErrInvalidConfig, len(params), len(hypers))
+	}
+
+	mean, variance := rawPrediction(model, modelPoint(hypers, params))
+
+	if math.IsNaN(mean) || math.IsNaN(variance) {
+		return 0, 0, fmt.Errorf("%w: the model can't predict %v", ErrInvalidObservation, params)
+	}
+
+	return mean, math.Sqrt(math.Max(variance, 0)), nil
+}
@@ -0,0 +1,92 @@
+package ho
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredict(t *testing.T) {
+	f := func(x float64) float64 {
+		return (x - 3) * (x - 3)
+	}
+
+	// The model is trained on a grid of [0, 10], warm starting it.
+	config := fastConfig()
+
+	for x := 0.0; x <= 10; x += 0.5 {
+		config.WarmStart = append(config.WarmStart, Observation{Params: []float64{x}, Value: f(x)})
+	}
+
+	opt, err := NewOptimizer(config, ParameterRange[float64]{Min: 0, Max: 10})
+	assert.NoError(t, err)
+
+	// Held-out points are predicted accurately and confidently.
+	for _, x := range []float64{1.25, 3.25, 7.75} {
+		mean, stddev, err := opt.Predict([]float64{x})
+
+		assert.NoError(t, err)
+		assert.InDelta(t, f(x), mean, 0.05)
+		assert.Less(t, stddev, 0.05)
+	}
+
+	// Far from the data, the model is uncertain.
+	_, stddev, err := opt.Predict([]float64{100})
+
+	assert.NoError(t, err)
+	assert.Greater(t, stddev, 1.0)
+
+	_, _, err = opt.Predict([]float64{1, 2})
+
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.EqualError(t, err, "invalid configuration: 2 parameters, expected 1")
+
+	// Predictions are safe during the run.
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				_, _, err := opt.Predict([]float64{float64(j) / 5})
+
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	for !opt.Done() {
+		suggestion, err := opt.Ask()
+		assert.NoError(t, err)
+
+		_, err = opt.Tell(suggestion.TrialID, f(suggestion.Params[0]), nil)
+		assert.NoError(t, err)
+	}
+
+	wg.Wait()
+
+	// The result predicts as the handle does.
+	result := opt.Result()
+
+	mean, stddev, err := result.Predict([]float64{4.2})
+	assert.NoError(t, err)
+
+	expectedMean, expectedStdDev, _ := opt.Predict([]float64{4.2})
+
+	assert.Equal(t, expectedMean, mean)
+	assert.Equal(t, expectedStdDev, stddev)
+	assert.False(t, math.IsNaN(stddev))
+
+	_, _, err = result.Predict(nil)
+
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, _, err = (&Result[float64]{}).Predict(nil)
+
+	assert.EqualError(t, err, "invalid configuration: the result has no model to predict with")
+}
@@ -0,0 +1,174 @@
+package ho
+
+import (
+	"math"
+	"math/rand"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Prior is a sampling distribution for a search-space dimension. It's used for
+// initial samples and candidate generation instead of uniform sampling when
+// you already believe where the optimum probably is, e.g. "probably around 64,
+// definitely between 16 and 512".
+//
+// Built-in priors:
+// - TruncatedNormal: Normal distribution truncated to the range bounds
+// - LogNormal: Log-normal distribution truncated to the range bounds
+// - InverseCDF: User-supplied inverse cumulative distribution function
+//
+// Usage example:
+//
+//	ranges := []ParameterRange[int]{
+//	    {Min: 16, Max: 512, Prior: TruncatedNormal(64, 32)},  // Buffer size
+//	    {Min: 1, Max: 32},                                    // Worker count (uniform)
+//	}
+//
+// Important notes:
+// - The hard Min/Max bounds of the range always apply
+// - Only sampling is affected, the Gaussian Process is not
+// - Implementations must be thread-safe and only use the given rng.
+type Prior interface {
+	// Sample draws a value in [min, max] using rng.
+	Sample(rng *rand.Rand, min, max float64) float64
+}
+
+// truncatedNormal implements the TruncatedNormal prior.
+type truncatedNormal struct {
+	mu    float64
+	sigma float64
+}
+
+// logNormal implements the LogNormal prior.
+type logNormal struct {
+	mu    float64
+	sigma float64
+}
+
+// inverseCDF implements the InverseCDF prior.
+type inverseCDF func(u float64) float64
+
+// logUniform implements the LogUniform prior.
+type logUniform struct{}
+
+//////
+// Methods.
+//////
+
+// Sample draws a value from the normal distribution truncated to [min, max],
+// using inverse transform sampling.
+func (p truncatedNormal) Sample(rng *rand.Rand, min, max float64) float64 {
+	lower := normalCDF((min - p.mu) / p.sigma)
+	upper := normalCDF((max - p.mu) / p.sigma)
+
+	u := lower + rng.Float64()*(upper-lower)
+
+	return clamp(p.mu+p.sigma*normalQuantile(u), min, max)
+}
+
+// Sample draws a value from the log-normal distribution truncated to
+// [min, max], using inverse transform sampling in log space.
+func (p logNormal) Sample(rng *rand.Rand, min, max float64) float64 {
+	// A non-positive min means no lower truncation: log-normal values are
+	// always positive.
+	lower := 0.0
+
+	if min > 0 {
+		lower = normalCDF((math.Log(min) - p.mu) / p.sigma)
+	}
+
+	upper := normalCDF((math.Log(max) - p.mu) / p.sigma)
+
+	u := lower + rng.Float64()*(upper-lower)
+
+	return clamp(math.Exp(p.mu+p.sigma*normalQuantile(u)), min, max)
+}
+
+// Sample draws a value by applying the inverse CDF to a uniform value, clipped
+// to [min, max].
+func (p inverseCDF) Sample(rng *rand.Rand, min, max float64) float64 {
+	return clamp(p(rng.Float64()), min, max)
+}
+
+// Sample draws a value uniformly in log space over [min, max], both of which
+// must be positive.
+func (p logUniform) Sample(rng *rand.Rand, min, max float64) float64 {
+	return clamp(math.Exp(math.Log(min)+rng.Float64()*(math.Log(max)-math.Log(min))), min, max)
+}
+
+//////
+// Helpers.
+//////
+
+// sampleFromPrior draws a value for the given range from its prior, rounded
+// and snapped to the range, see rangeValue.
+func sampleFromPrior[T constraints.Integer | constraints.Float](rng *rand.Rand, hyper ParameterRange[T]) T {
+	return rangeValue(hyper, hyper.Prior.Sample(rng, float64(hyper.Min), float64(hyper.Max)))
+}
+
+//////
+// Factory.
+//////
+
+// TruncatedNormal returns a Prior sampling from a normal distribution with
+// mean mu and standard deviation sigma, truncated to the range bounds.
+//
+// Parameters:
+// - mu: Mean, where you believe the optimum is
+// - sigma: Standard deviation, how confident you are (must be positive)
+//
+// Returns:
+// - Prior: The prior.
+func TruncatedNormal(mu, sigma float64) Prior {
+	return truncatedNormal{mu: mu, sigma: sigma}
+}
+
+// LogNormal returns a Prior sampling from a log-normal distribution, truncated
+// to the range bounds. Well suited for scale parameters such as buffer sizes or
+// learning rates.
+//
+// Parameters:
+// - mu: Mean of the natural logarithm of the values, e.g. math.Log(64)
+// - sigma: Standard deviation of the natural logarithm of the values (must be
+// positive)
+//
+// Returns:
+// - Prior: The prior.
+func LogNormal(mu, sigma float64) Prior {
+	return logNormal{mu: mu, sigma: sigma}
+}
+
+// InverseCDF returns a Prior sampling with a user-supplied inverse cumulative
+// distribution function (quantile function). Values are clipped to the range
+// bounds.
+//
+// Parameters:
+// - f: Maps a uniform value in [0, 1) to a value of the distribution
+//
+// Returns:
+// - Prior: The prior
+//
+// Usage example:
+//
+//	// Density increasing linearly towards Max, over [0, 100].
+//	prior := InverseCDF(func(u float64) float64 {
+//	    return 100 * math.Sqrt(u)
+//	})
+func InverseCDF(f func(u float64) float64) Prior {
+	return inverseCDF(f)
+}
+
+// LogUniform returns a Prior sampling uniformly in log space, i.e. every
+// order of magnitude of the range is equally likely. Well suited for scale
+// parameters when you have no idea where the optimum is. The range bounds
+// must be positive.
+//
+// Returns:
+// - Prior: The prior.
+func LogUniform() Prior {
+	return logUniform{}
+}
@@ -0,0 +1,139 @@
+package ho
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// samplePrior draws n values from the prior over [min, max].
+func samplePrior(prior Prior, min, max float64, n int) []float64 {
+	rng := rand.New(rand.NewSource(1))
+
+	samples := make([]float64, n)
+
+	for i := range samples {
+		samples[i] = prior.Sample(rng, min, max)
+	}
+
+	return samples
+}
+
+// meanStd returns the mean and standard deviation of the values.
+func meanStd(values []float64) (mean, std float64) {
+	for _, v := range values {
+		mean += v
+	}
+
+	mean /= float64(len(values))
+
+	for _, v := range values {
+		std += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(std / float64(len(values)))
+}
+
+func TestTruncatedNormal(t *testing.T) {
+	samples := samplePrior(TruncatedNormal(64, 20), 16, 512, 20000)
+
+	for _, v := range samples {
+		assert.GreaterOrEqual(t, v, 16.0)
+		assert.LessOrEqual(t, v, 512.0)
+	}
+
+	// Truncation at 16 (2.4 sigma) slightly shifts the mean up.
+	mean, std := meanStd(samples)
+	assert.InDelta(t, 64.45, mean, 1)
+	assert.InDelta(t, 20, std, 1)
+
+	// Heavy truncation keeps samples within bounds.
+	for _, v := range samplePrior(TruncatedNormal(0, 1), 5, 6, 1000) {
+		assert.GreaterOrEqual(t, v, 5.0)
+		assert.LessOrEqual(t, v, 6.0)
+	}
+}
+
+func TestLogNormal(t *testing.T) {
+	samples := samplePrior(LogNormal(math.Log(64), 0.5), 16, 512, 20000)
+
+	for _, v := range samples {
+		assert.GreaterOrEqual(t, v, 16.0)
+		assert.LessOrEqual(t, v, 512.0)
+	}
+
+	logs := make([]float64, len(samples))
+
+	for i, v := range samples {
+		logs[i] = math.Log(v)
+	}
+
+	// Bounds are ~2.8 sigma away in log space, so truncation is negligible.
+	mean, std := meanStd(logs)
+	assert.InDelta(t, math.Log(64), mean, 0.02)
+	assert.InDelta(t, 0.5, std, 0.02)
+	assert.InDelta(t, 64, median(samples), 2)
+}
+
+func TestInverseCDF(t *testing.T) {
+	// P(x <= 25) = P(u <= 0.5) = 0.5.
+	samples := samplePrior(InverseCDF(func(u float64) float64 {
+		return 100 * u * u
+	}), 0, 100, 20000)
+
+	assert.InDelta(t, 25, median(samples), 1)
+
+	// Values escaping the bounds are clipped.
+	for _, v := range samplePrior(InverseCDF(func(u float64) float64 {
+		return 200*u - 50
+	}), 0, 100, 1000) {
+		assert.GreaterOrEqual(t, v, 0.0)
+		assert.LessOrEqual(t, v, 100.0)
+	}
+}
+
+func TestLogUniform(t *testing.T) {
+	samples := samplePrior(LogUniform(), 1, 10000, 20000)
+
+	below10 := 0
+
+	for _, v := range samples {
+		assert.GreaterOrEqual(t, v, 1.0)
+		assert.LessOrEqual(t, v, 10000.0)
+
+		if v < 10 {
+			below10++
+		}
+	}
+
+	// Every order of magnitude is equally likely.
+	assert.InDelta(t, 100, median(samples), 10)
+	assert.InDelta(t, 0.25, float64(below10)/float64(len(samples)), 0.02)
+}
+
+func TestPriorInOptimization(t *testing.T) {
+	config := fastConfig()
+	config.InitialSamples = 50
+	config.Iterations = 0
+
+	benchmarkFunc := func(params ...int) error {
+		return nil
+	}
+
+	result := Optimize(config, benchmarkFunc, ParameterRange[int]{Min: 16, Max: 512, Prior: TruncatedNormal(64, 4)})
+
+	values := make([]float64, 0, len(result.Trials))
+
+	for _, trial := range result.Trials {
+		assert.GreaterOrEqual(t, trial.Params[0], 16)
+		assert.LessOrEqual(t, trial.Params[0], 512)
+
+		values = append(values, float64(trial.Params[0]))
+	}
+
+	// Uniform sampling would have a mean of ~264.
+	mean, _ := meanStd(values)
+	assert.InDelta(t, 64, mean, 3)
+}
@@ -0,0 +1,198 @@
+package ho
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// PhaseProfiling is the TrialInfo.Phase of the profiling run of the best
+// parameters, see ProfileBest.
+const PhaseProfiling = "Profiling"
+
+// ProfileBest configures the profiling of the best parameters: once the run
+// ended, they're measured once more with runtime/pprof CPU profiling, to find
+// out why they're fast, e.g. with "go tool pprof".
+//
+// Important notes:
+// - The profiling run is invoked with TrialInfo.Phase PhaseProfiling,
+// TrialID 0, and the seed of the best trial. It's neither recorded in
+// Result.Trials nor fed to the model, and doesn't count against the budgets
+// - It's skipped if no trial completed, or the run context is done
+// - Failures, e.g. of the benchmark or to write a profile, are recorded in
+// Result.Warnings, and don't fail the run
+// - The CPU profile covers the benchmark only, not the Setup and Teardown
+// hooks. Only one CPU profile can be active in a process at once.
+type ProfileBest struct {
+	// CPUPath is the file the CPU profile is written to.
+	// If empty, the CPU isn't profiled.
+	CPUPath string
+
+	// HeapPath is the file the heap profile is written to, once the
+	// profiling run ended and after a garbage collection. Heap profiles
+	// cover the allocations of the process since it started, see
+	// runtime.MemProfile.
+	// If empty, the heap isn't profiled.
+	HeapPath string
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the settings.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a setting is invalid, nil otherwise.
+func (p *ProfileBest) validate() error {
+	if p.CPUPath == "" && p.HeapPath == "" {
+		return fmt.Errorf("%w: ProfileBest: neither CPUPath nor HeapPath is set", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// profileBest measures the best parameters once more with profiling, see
+// ProfileBest.
+func (o *optimizer[T]) profileBest() {
+	settings := o.config.ProfileBest
+
+	if settings == nil {
+		return
+	}
+
+	o.mu.Lock()
+
+	best, params := o.bestTime, slices.Clone(o.bestParams)
+
+	info := TrialInfo{Phase: PhaseProfiling}
+
+	for _, trial := range o.trials {
+		if trial.TrialID == o.bestTrialID {
+			info.Seed = trial.Seed
+		}
+	}
+
+	o.mu.Unlock()
+
+	switch {
+	case best == math.MaxFloat64:
+		o.warnf("best parameters not profiled: no trial completed")
+
+		return
+	case o.ctx.Err() != nil:
+		o.warnf("best parameters not profiled: %v", context.Cause(o.ctx))
+
+		return
+	}
+
+	if o.cooldown(false) != nil {
+		return
+	}
+
+	m := func() measurement {
+		teardown, err := o.setUp(HooksPerTrial, info, params)
+		defer teardown()
+
+		if err != nil {
+			return measurement{err: err}
+		}
+
+		if settings.CPUPath != "" {
+			stop, err := startCPUProfile(settings.CPUPath)
+			if err != nil {
+				o.warnf("CPU profile of the best parameters not written: %v", err)
+			} else {
+				defer func() {
+					if err := stop(); err != nil {
+						o.warnf("CPU profile of the best parameters not written: %v", err)
+
+						return
+					}
+
+					o.mu.Lock()
+					o.profiles.cpu = settings.CPUPath
+					o.mu.Unlock()
+				}()
+			}
+		}
+
+		return o.measure(info, params)
+	}()
+
+	switch {
+	case m.err != nil:
+		o.warnf("profiling run of the best parameters failed: %v", m.err)
+	case m.canceled != nil:
+		o.warnf("profiling run of the best parameters failed: %v", m.canceled)
+	}
+
+	if settings.HeapPath == "" {
+		return
+	}
+
+	if err := writeHeapProfile(settings.HeapPath); err != nil {
+		o.warnf("heap profile of the best parameters not written: %v", err)
+
+		return
+	}
+
+	o.mu.Lock()
+	o.profiles.heap = settings.HeapPath
+	o.mu.Unlock()
+}
+
+//////
+// Helpers.
+//////
+
+// startCPUProfile starts profiling the CPU to the file at path.
+//
+// Returns:
+// - func() error: Stops profiling, and closes the file
+// - error: If the file can't be created, or a profile is already active.
+func startCPUProfile(path string) (func() error, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+
+		return nil, err
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+
+		return f.Close()
+	}, nil
+}
+
+// writeHeapProfile writes the heap profile to the file at path, after a
+// garbage collection so it's up to date.
+func writeHeapProfile(path string) error {
+	runtime.GC()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,148 @@
+package ho
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// parseProfile checks the file at path holds a gzipped pprof profile, a
+// perftools.profiles.Profile protocol buffer.
+//
+// Returns:
+// - map[protowire.Number]int: The number of occurrences of each field.
+func parseProfile(t *testing.T, path string) map[protowire.Number]int {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	data, err := io.ReadAll(r)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	fields := make(map[protowire.Number]int)
+
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if !assert.GreaterOrEqual(t, n, 0, "invalid tag") {
+			return nil
+		}
+
+		data = data[n:]
+
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if !assert.GreaterOrEqual(t, n, 0, "invalid field %d", num) {
+			return nil
+		}
+
+		data = data[n:]
+
+		fields[num]++
+	}
+
+	return fields
+}
+
+func TestProfileBest(t *testing.T) {
+	ranges := []ParameterRange[int]{{Min: 0, Max: 100}}
+
+	// spin burns CPU, more so far from 42.
+	spin := func(params ...int) error {
+		var x float64
+
+		for i := 0; i < 10000*(1+max(params[0]-42, 42-params[0])); i++ {
+			x += float64(i)
+		}
+
+		_ = x
+
+		return nil
+	}
+
+	t.Run("profiles", func(t *testing.T) {
+		dir := t.TempDir()
+
+		var profiled []TrialInfo
+
+		config := fastConfig()
+		config.ProfileBest = &ProfileBest{
+			CPUPath:  filepath.Join(dir, "cpu.pprof"),
+			HeapPath: filepath.Join(dir, "heap.pprof"),
+		}
+
+		result := OptimizeWithInfo(config, func(info TrialInfo, params ...int) error {
+			if info.Phase == PhaseProfiling {
+				profiled = append(profiled, info)
+			}
+
+			return spin(params...)
+		}, ranges...)
+
+		assert.NoError(t, result.Err)
+		assert.Empty(t, result.Warnings)
+
+		// The profiling run isn't a trial.
+		assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+		for _, trial := range result.Trials {
+			if trial.ExecutionTime == result.BestTime {
+				assert.Equal(t, []TrialInfo{{Phase: PhaseProfiling, Seed: trial.Seed}}, profiled)
+			}
+		}
+
+		assert.Equal(t, config.ProfileBest.CPUPath, result.CPUProfile)
+		assert.Equal(t, config.ProfileBest.HeapPath, result.HeapProfile)
+
+		// Sample types (field 1) are always set.
+		for _, path := range []string{result.CPUProfile, result.HeapProfile} {
+			assert.Positive(t, parseProfile(t, path)[1], path)
+		}
+	})
+
+	t.Run("failures are warnings", func(t *testing.T) {
+		config := fastConfig()
+		config.ProfileBest = &ProfileBest{CPUPath: filepath.Join(t.TempDir(), "missing", "cpu.pprof")}
+
+		result := Optimize(config, spin, ranges...)
+
+		assert.NoError(t, result.Err)
+		assert.Empty(t, result.CPUProfile)
+		assert.Len(t, result.Warnings, 1)
+		assert.Contains(t, result.Warnings[0], "CPU profile of the best parameters not written")
+	})
+
+	t.Run("no completed trial", func(t *testing.T) {
+		config := fastConfig()
+		config.ProfileBest = &ProfileBest{HeapPath: filepath.Join(t.TempDir(), "heap.pprof")}
+
+		result := Optimize(config, func(...int) error { return ErrSkipTrial }, ranges...)
+
+		assert.Empty(t, result.HeapProfile)
+		assert.Contains(t, result.Warnings, "best parameters not profiled: no trial completed")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		config := fastConfig()
+		config.ProfileBest = &ProfileBest{}
+
+		result := Optimize(config, spin, ranges...)
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	})
+}
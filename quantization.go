@@ -0,0 +1,79 @@
+package ho
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Quantization defines the tolerance below which two values of a dimension
+// are considered the same configuration. It's only used to build cache keys
+// and to deduplicate candidates against history (see
+// OptimizationConfig.CacheEvaluations), the benchmark always receives the
+// actual values.
+//
+// Usage example:
+//
+//	// Learning rates closer than 1% are the same configuration.
+//	learningRate := ParameterRange[float64]{
+//	    Min:          0.0001,
+//	    Max:          0.1,
+//	    Quantization: Quantization{Relative: 0.01},
+//	}
+//
+// Important notes:
+// - Values are bucketed: values in the same bucket (of width Absolute, or
+// of relative width Relative) share a key, so two values within tolerance but
+// on both sides of a bucket boundary don't
+// - Absolute takes precedence over Relative if both are set
+// - The zero value means exact matching.
+type Quantization struct {
+	// Absolute is the width of the buckets, in the parameter unit.
+	Absolute float64 `json:"absolute,omitempty"`
+
+	// Relative is the relative width of the buckets, e.g. 0.01 for 1%. Buckets
+	// are uniform in log space, which suits log-scaled parameters.
+	Relative float64 `json:"relative,omitempty"`
+}
+
+//////
+// Methods.
+//////
+
+// bucket returns the bucket of the value.
+func (q Quantization) bucket(v float64) float64 {
+	switch {
+	case q.Absolute > 0:
+		return math.Round(v / q.Absolute)
+	case q.Relative > 0 && v != 0:
+		return math.Copysign(math.Round(math.Log(math.Abs(v))/math.Log1p(q.Relative)), v)
+	default:
+		return v
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// cacheKey builds the cache key of the parameters, quantized according to
+// each dimension's Quantization.
+func cacheKey[T constraints.Integer | constraints.Float](hypers []ParameterRange[T], params []T) string {
+	var sb strings.Builder
+
+	for i, v := range params {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(strconv.FormatFloat(hypers[i].Quantization.bucket(float64(v)), 'g', -1, 64))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,83 @@
+package ho
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheKey(t *testing.T) {
+	hypers := []ParameterRange[float64]{
+		{Min: 0, Max: 10, Quantization: Quantization{Absolute: 0.5}},
+		{Min: 0.001, Max: 1, Quantization: Quantization{Relative: 0.01}},
+		{Min: 0, Max: 1},
+	}
+
+	key := cacheKey(hypers, []float64{1.01, 0.1, 0.5})
+
+	// Within tolerance.
+	assert.Equal(t, key, cacheKey(hypers, []float64{1.1, 0.1001, 0.5}))
+
+	// Outside tolerance, per dimension.
+	assert.NotEqual(t, key, cacheKey(hypers, []float64{2, 0.1, 0.5}))
+	assert.NotEqual(t, key, cacheKey(hypers, []float64{1.01, 0.11, 0.5}))
+	assert.NotEqual(t, key, cacheKey(hypers, []float64{1.01, 0.1, 0.5 + 1e-12}))
+}
+
+func TestCacheEvaluations(t *testing.T) {
+	var received []float64
+
+	config := fastConfig()
+	config.CacheEvaluations = true
+
+	o := newOptimizer(context.Background(), config, fromBenchmarkFunc(func(params ...float64) error {
+		received = append(received, params[0])
+
+		return nil
+	}), ParameterRange[float64]{Min: 0, Max: 10, Quantization: Quantization{Absolute: 0.01}})
+
+	first := o.evaluate(o.newTrialInfo(PhaseInitialSampling, 1, false), []float64{1.0001})
+	assert.False(t, first.Cached)
+
+	// Within tolerance: collapses to the first evaluation.
+	second := o.evaluate(o.newTrialInfo(PhaseInitialSampling, 2, false), []float64{1.0002})
+	assert.True(t, second.Cached)
+	assert.Equal(t, first.ExecutionTime, second.ExecutionTime)
+	assert.Equal(t, []float64{1.0002}, second.Params)
+
+	// Outside tolerance: evaluated.
+	third := o.evaluate(o.newTrialInfo(PhaseInitialSampling, 3, false), []float64{3})
+	assert.False(t, third.Cached)
+
+	// The benchmark received the actual values.
+	assert.Equal(t, []float64{1.0001, 3}, received)
+	assert.Len(t, o.result().Trials, 3)
+}
+
+func TestCacheEvaluationsDeduplicatesCandidates(t *testing.T) {
+	config := fastConfig()
+	config.CacheEvaluations = true
+	config.NumCandidates = 50
+
+	// The acquisition function strongly prefers 0, which is already
+	// evaluated.
+	config.AcquisitionFuncEx = func(candidate []float64, mean, variance float64, params AcquisitionParams) float64 {
+		return candidate[0]
+	}
+
+	o := newOptimizer(context.Background(), config, fromBenchmarkFunc(func(params ...int) error {
+		return nil
+	}), ParameterRange[int]{Min: 0, Max: 1})
+
+	o.evaluate(o.newTrialInfo(PhaseInitialSampling, 1, false), []int{0})
+
+	next, _ := o.nextCandidate(o.model, 1)
+	assert.Equal(t, []int{1}, next)
+
+	// Once everything is evaluated, duplicates are selected.
+	o.evaluate(o.newTrialInfo(PhaseInitialSampling, 2, false), []int{1})
+
+	next, _ = o.nextCandidate(o.model, 2)
+	assert.Equal(t, []int{0}, next)
+}
@@ -0,0 +1,200 @@
+package ho
+
+import (
+	"math/rand"
+	"sync"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// refitGrowth is the fraction of observations added since the last fit that
+// triggers a refit, unless RandomForestOptions.RefitEvery is set.
+const refitGrowth = 0.1
+
+// RandomForestOptions configures a RandomForest.
+type RandomForestOptions struct {
+	// Trees is the number of trees.
+	// If not positive, 32 trees are used.
+	Trees int
+
+	// RefitEvery is the number of observations added after which the forest
+	// is refitted.
+	// If not positive, the forest is refitted once observations grew by 10%
+	// since the last fit, i.e. after every observation for the first 10.
+	RefitEvery int
+
+	// Seed seeds bootstrapping and feature subsets: forests with the same
+	// seed and observations are identical.
+	Seed int64
+}
+
+// RandomForest is a SurrogateModel based on a random forest regressor, as in
+// SMAC: bootstrap-sampled regression trees, each split considering a random
+// subset of the features. Predictions are the mean of the tree predictions,
+// with the variance across trees as the uncertainty.
+//
+// Important notes:
+// - Fitting scales linearly with observations, and trees split integer
+// parameters on thresholds, so forests fit large, mostly discrete spaces
+// better than Gaussian processes
+// - Observations added between refits, e.g. constant liar lies of the
+// ask/tell Optimizer, only affect predictions after the next refit. Set
+// RefitEvery to 1 to refit after every observation
+// - Away from observations, predictions are those of the nearest leaves, so
+// the variance doesn't grow with the distance to observations as with a
+// Gaussian process.
+//
+// Thread safety:
+// - All methods are safe for concurrent use.
+type RandomForest struct {
+	// mu protects the fields below.
+	mu sync.RWMutex
+
+	// options configures the forest.
+	options RandomForestOptions
+
+	// x holds the observed points, never modified once added.
+	x [][]float64
+
+	// y holds the observed values.
+	y []float64
+
+	// forest is the last fitted forest, never modified once fitted.
+	forest *regressionForest
+
+	// fitted is the number of observations the forest was fitted on.
+	fitted int
+}
+
+//////
+// Methods.
+//////
+
+// Update implements SurrogateModel. It refits the forest if due, see
+// RandomForestOptions.RefitEvery.
+func (f *RandomForest) Update(x []float64, y float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := checkObservation(x, y, f.x); err != nil {
+		return err
+	}
+
+	f.x = append(f.x, append([]float64(nil), x...))
+	f.y = append(f.y, y)
+
+	every := f.options.RefitEvery
+	if every <= 0 {
+		every = max(1, int(float64(f.fitted)*refitGrowth))
+	}
+
+	if len(f.x)-f.fitted >= every {
+		// Seeding with the number of observations keeps fits reproducible,
+		// clones included.
+		rng := rand.New(rand.NewSource(f.options.Seed + int64(len(f.x))))
+
+		f.forest = fitForest(f.x, f.y, rng, f.options.Trees)
+		f.fitted = len(f.x)
+	}
+
+	return nil
+}
+
+// Predict implements SurrogateModel. It returns (0, 1) until the first
+// observation, as the Gaussian process does.
+func (f *RandomForest) Predict(x []float64) (mean, variance float64) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.forest == nil {
+		return 0, 1
+	}
+
+	return f.forest.predictWithVariance(x)
+}
+
+// PredictBatch implements BatchPredictor.
+func (f *RandomForest) PredictBatch(points [][]float64) (means, variances []float64) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	means = make([]float64, len(points))
+	variances = make([]float64, len(points))
+
+	for i, x := range points {
+		if f.forest == nil {
+			variances[i] = 1
+
+			continue
+		}
+
+		means[i], variances[i] = f.forest.predictWithVariance(x)
+	}
+
+	return means, variances
+}
+
+// Points implements SurrogateModel.
+func (f *RandomForest) Points() [][]float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	points := make([][]float64, len(f.x))
+
+	for i, x := range f.x {
+		points[i] = append([]float64(nil), x...)
+	}
+
+	return points
+}
+
+// Len implements SurrogateModel.
+func (f *RandomForest) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return len(f.x)
+}
+
+// Clone implements SurrogateModel. Observations and the fitted forest are
+// shared, as they're never modified.
+func (f *RandomForest) Clone() SurrogateModel {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return &RandomForest{
+		options: f.options,
+		x:       append([][]float64(nil), f.x...),
+		y:       append([]float64(nil), f.y...),
+		forest:  f.forest,
+		fitted:  f.fitted,
+	}
+}
+
+//////
+// Factory.
+//////
+
+// NewRandomForest creates a RandomForest.
+//
+// Parameters:
+// - options: Configures the forest, zero values select the defaults
+//
+// Returns:
+// - *RandomForest: The model, without observations.
+//
+// Usage example:
+//
+//	config := DefaultConfig()
+//	config.Surrogate = func() SurrogateModel {
+//	    return NewRandomForest(RandomForestOptions{Seed: 42})
+//	}
+func NewRandomForest(options RandomForestOptions) *RandomForest {
+	if options.Trees <= 0 {
+		options.Trees = forestTrees
+	}
+
+	return &RandomForest{options: options}
+}
@@ -0,0 +1,158 @@
+package ho
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mixedDiscrete is a synthetic problem over integer parameters: a quadratic
+// bowl, an absolute value, and a "categorical" parameter where only one
+// level is good. Its minimum is 0, at (13, 4, 2).
+func mixedDiscrete(params ...int) float64 {
+	value := float64((params[0]-13)*(params[0]-13)) + 3*math.Abs(float64(params[1]-4))
+
+	if params[2] != 2 {
+		value += 25
+	}
+
+	return value
+}
+
+// meanBestDiscrete runs the optimization of mixedDiscrete runs times, and
+// returns the mean best value found.
+func meanBestDiscrete(config OptimizationConfig, runs int) float64 {
+	var sum float64
+
+	for seed := 0; seed < runs; seed++ {
+		o := newOptimizer(context.Background(), config, nil,
+			ParameterRange[int]{Min: 0, Max: 30},
+			ParameterRange[int]{Min: 0, Max: 30},
+			ParameterRange[int]{Min: 0, Max: 5},
+		)
+
+		o.rng = rand.New(rand.NewSource(int64(seed)))
+
+		o.objectiveFunc = func(_ context.Context, _ TrialInfo, params ...int) (float64, error) {
+			return mixedDiscrete(params...), nil
+		}
+
+		sum += o.run().BestTime
+	}
+
+	return sum / float64(runs)
+}
+
+func TestRandomForestConvergence(t *testing.T) {
+	config := fastConfig()
+	config.InitialSamples = 8
+	config.Iterations = 30
+	config.NumCandidates = 50
+	config.AcquisitionFunc = ExpectedImprovement
+
+	gp := meanBestDiscrete(config, 8)
+
+	config.Surrogate = func() SurrogateModel { return NewRandomForest(RandomForestOptions{}) }
+
+	forest := meanBestDiscrete(config, 8)
+
+	config.Iterations = 0
+
+	random := meanBestDiscrete(config, 8)
+
+	// The forest improves on the initial samples, at least as well as the
+	// Gaussian process, give or take.
+	assert.Less(t, forest, random)
+	assert.LessOrEqual(t, forest, 1.5*gp+5)
+}
+
+func TestRandomForest(t *testing.T) {
+	model := NewRandomForest(RandomForestOptions{RefitEvery: 1, Seed: 7})
+
+	mean, variance := model.Predict([]float64{1})
+
+	assert.Equal(t, 0.0, mean)
+	assert.Equal(t, 1.0, variance)
+
+	// A step function.
+	for x := 0; x < 40; x++ {
+		y := 0.0
+		if x >= 20 {
+			y = 100
+		}
+
+		model.Update([]float64{float64(x)}, y)
+	}
+
+	assert.Equal(t, 40, model.Len())
+	assert.Len(t, model.Points(), 40)
+
+	low, _ := model.Predict([]float64{5})
+	high, _ := model.Predict([]float64{35})
+
+	assert.InDelta(t, 0, low, 5)
+	assert.InDelta(t, 100, high, 5)
+
+	// Trees disagree most around the step.
+	_, far := model.Predict([]float64{5})
+	_, near := model.Predict([]float64{19.5})
+
+	assert.Greater(t, near, far)
+
+	// Clones are independent, and fits reproducible.
+	clone := model.Clone()
+
+	clone.Update([]float64{5}, 1000)
+
+	changed, _ := clone.Predict([]float64{5})
+	unchanged, _ := model.Predict([]float64{5})
+
+	assert.Greater(t, changed, low)
+	assert.Equal(t, low, unchanged)
+
+	again := NewRandomForest(RandomForestOptions{RefitEvery: 1, Seed: 7})
+
+	for _, point := range model.Points() {
+		y := 0.0
+		if point[0] >= 20 {
+			y = 100
+		}
+
+		again.Update(point, y)
+	}
+
+	same, _ := again.Predict([]float64{5})
+
+	assert.Equal(t, low, same)
+}
+
+func TestRandomForestRefits(t *testing.T) {
+	model := NewRandomForest(RandomForestOptions{})
+
+	for x := 0; x < 20; x++ {
+		model.Update([]float64{float64(x)}, float64(x))
+	}
+
+	// Below 20 observations, 10% growth rounds down to every observation.
+	assert.Equal(t, 20, model.fitted)
+
+	model.Update([]float64{20}, 20)
+
+	assert.Equal(t, 20, model.fitted)
+
+	model.Update([]float64{21}, 21)
+
+	assert.Equal(t, 22, model.fitted)
+}
+
+func TestSurrogateNil(t *testing.T) {
+	config := fastConfig()
+	config.Surrogate = func() SurrogateModel { return nil }
+
+	_, err := NewOptimizer(config, ParameterRange[int]{Min: 0, Max: 1})
+
+	assert.EqualError(t, err, "invalid configuration: Surrogate returned a nil model")
+}
@@ -0,0 +1,26 @@
+package ho
+
+import "context"
+
+//////
+// Const, vars, types.
+//////
+
+// RateLimiter limits the rate of benchmark invocations, e.g. so runs tuning
+// the same staging cluster respect a global "at most N evaluations per
+// minute" policy, see OptimizationConfig.RateLimiter. A *rate.Limiter from
+// golang.org/x/time/rate satisfies it.
+//
+// Thread safety:
+// - Wait must be safe for concurrent use if
+// OptimizationConfig.MaxConcurrentEvaluations is greater than 1.
+type RateLimiter interface {
+	// Wait blocks until an invocation is allowed, or ctx is done.
+	//
+	// Parameters:
+	// - ctx: The run context
+	//
+	// Returns:
+	// - error: If the invocation isn't allowed, e.g. ctx is done.
+	Wait(ctx context.Context) error
+}
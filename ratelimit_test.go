@@ -0,0 +1,155 @@
+package ho
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// spacingLimiter is a RateLimiter allowing an invocation every interval. It
+// records the times it grants invocations at.
+type spacingLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	granted  []time.Time
+}
+
+// Wait implements RateLimiter.
+func (l *spacingLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+
+	at := time.Now()
+	if at.Before(l.next) {
+		at = l.next
+	}
+
+	l.next = at.Add(l.interval)
+
+	l.mu.Unlock()
+
+	if err := sleep(ctx, time.Until(at)); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.granted = append(l.granted, at)
+	l.mu.Unlock()
+
+	return nil
+}
+
+// invocationTimes returns a benchmark recording when it's invoked in starts.
+func invocationTimes(mu *sync.Mutex, starts *[]time.Time) BenchmarkFunc[int] {
+	return func(params ...int) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		*starts = append(*starts, time.Now())
+
+		return nil
+	}
+}
+
+// assertGranted asserts each invocation started once the limiter granted it,
+// and the grants are interval apart. Grant times are compared, not the gaps
+// between invocations, which include hooks and scheduling.
+func assertGranted(t *testing.T, limiter *spacingLimiter, starts []time.Time, interval time.Duration) {
+	t.Helper()
+
+	granted := slices.Clone(limiter.granted)
+
+	if !assert.Len(t, granted, len(starts)) {
+		return
+	}
+
+	slices.SortFunc(granted, func(a, b time.Time) int { return a.Compare(b) })
+	slices.SortFunc(starts, func(a, b time.Time) int { return a.Compare(b) })
+
+	for i := range starts {
+		assert.False(t, starts[i].Before(granted[i]), "invocation %d started before its grant", i)
+
+		if i > 0 {
+			assert.GreaterOrEqual(t, granted[i].Sub(granted[i-1]), interval)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	ranges := []ParameterRange[int]{{Min: 1, Max: 100}}
+
+	t.Run("serial", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			starts []time.Time
+		)
+
+		limiter := &spacingLimiter{interval: 10 * time.Millisecond}
+
+		config := fastConfig()
+		config.RateLimiter = limiter
+
+		result := Optimize(config, invocationTimes(&mu, &starts), ranges...)
+
+		assert.Len(t, starts, config.InitialSamples+config.Iterations)
+		assertGranted(t, limiter, starts, 10*time.Millisecond)
+
+		// The wait is recorded apart from the duration.
+		for _, trial := range result.Trials[1:] {
+			assert.Greater(t, trial.RateLimitWait, time.Duration(0))
+			assert.Less(t, trial.Duration, 5*time.Millisecond)
+		}
+
+		assert.Equal(t, result.Trials[1].RateLimitWait.Nanoseconds(), NewTrialRecord(result.Trials[1], nil).RateLimitWaitNS)
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			starts []time.Time
+		)
+
+		limiter := &spacingLimiter{interval: 10 * time.Millisecond}
+
+		config := fastConfig()
+		config.InitialSamples = 6
+		config.MaxConcurrentEvaluations = 3
+		config.RateLimiter = limiter
+
+		Optimize(config, invocationTimes(&mu, &starts), ranges...)
+
+		assert.Len(t, starts, config.InitialSamples+config.Iterations)
+		assertGranted(t, limiter, starts, 10*time.Millisecond)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		config := fastConfig()
+		config.RateLimiter = &spacingLimiter{interval: time.Hour, next: time.Now().Add(time.Hour)}
+
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		var invoked bool
+
+		result := OptimizeWithContext(ctx, config, func(ctx context.Context, params ...int) error {
+			invoked = true
+
+			return nil
+		}, ranges...)
+
+		assert.False(t, invoked)
+		assert.Equal(t, TerminationContextCanceled, result.TerminationReason)
+
+		if assert.Len(t, result.Trials, 1) {
+			assert.Equal(t, TrialCanceled, result.Trials[0].Status)
+			assert.ErrorIs(t, result.Trials[0].Err, context.Canceled)
+			assert.GreaterOrEqual(t, result.Trials[0].RateLimitWait, 15*time.Millisecond)
+		}
+	})
+}
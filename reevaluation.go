@@ -0,0 +1,162 @@
+package ho
+
+import "fmt"
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// PhaseIncumbentCheck is the TrialInfo.Phase of re-evaluations of the
+	// best parameters, see IncumbentReevaluation.
+	PhaseIncumbentCheck = "IncumbentCheck"
+
+	// defaultReevaluationEvery is the IncumbentReevaluation.Every used if
+	// unset.
+	defaultReevaluationEvery = 5
+)
+
+// IncumbentReevaluation configures the periodic re-evaluation of the best
+// parameters, for noisy objectives: a lucky measurement would otherwise stay
+// the best forever, and block genuine improvements. Re-evaluated, its value
+// regresses to its mean, and better points can displace it.
+//
+// Important notes:
+// - Every Every-th iteration evaluates the best parameters instead of a new
+// candidate, with TrialInfo.Phase PhaseIncumbentCheck, so re-evaluations
+// count against Iterations and TimeBudget, and are listed in Result.Trials
+// - The value of a point is the mean of its completed measurements, e.g.
+// Result.BestTime is the mean of those of Result.BestParams, and the best
+// parameters are those with the lowest mean. Failed re-evaluations don't
+// change it
+// - Measurements of a point are merged into their mean by the model, as the
+// default Gaussian process does, see GaussianProcessOptions
+// - Not supported with CacheEvaluations, which would reuse the first
+// measurement. Only optimization runs re-evaluate, not the ask/tell
+// Optimizer.
+type IncumbentReevaluation struct {
+	// Every is the number of iterations between re-evaluations, the
+	// re-evaluation included.
+	// If 0, 5 is used.
+	Every int
+}
+
+// pointMean is the mean of the completed measurements of a point, see
+// IncumbentReevaluation.
+type pointMean[T any] struct {
+	// params holds the parameters of the point.
+	params []T
+
+	// sum and count are the sum and number of the measurements.
+	sum   float64
+	count int
+
+	// trialID is the ID of the last trial measuring the point.
+	trialID int
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the settings.
+//
+// Parameters:
+// - config: The configuration of the run
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a setting is invalid, nil otherwise.
+func (r *IncumbentReevaluation) validate(config OptimizationConfig) error {
+	switch {
+	case r.Every < 0:
+		return fmt.Errorf("%w: IncumbentReevaluation.Every %d is negative", ErrInvalidConfig, r.Every)
+	case config.CacheEvaluations:
+		return fmt.Errorf("%w: IncumbentReevaluation: not supported with CacheEvaluations", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// mean returns the mean of the measurements.
+func (m *pointMean[T]) mean() float64 {
+	return m.sum / float64(m.count)
+}
+
+// reevaluationDue returns true if the iteration must re-evaluate the best
+// parameters, see IncumbentReevaluation.
+func (o *optimizer[T]) reevaluationDue(iteration int) bool {
+	settings := o.config.IncumbentReevaluation
+
+	if settings == nil {
+		return false
+	}
+
+	every := settings.Every
+	if every == 0 {
+		every = defaultReevaluationEvery
+	}
+
+	return iteration%every == 0 && o.incumbent() != nil
+}
+
+// updateBestMean sets the best parameters to those with the lowest mean of
+// their completed measurements, the trial included, see
+// IncumbentReevaluation.
+//
+// Parameters:
+// - trial: The trial, recorded
+//
+// Returns:
+// - float64: The previous best value
+// - bool: Whether the parameters of the trial are now the best, with a
+// lower value than the previous best.
+func (o *optimizer[T]) updateBestMean(trial Trial[T]) (float64, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	previous := o.bestTime
+
+	means := map[string]*pointMean[T]{}
+
+	var order []string
+
+	for _, t := range o.trials {
+		if t.Status != TrialCompleted || t.Cached {
+			continue
+		}
+
+		key := pointKey(o.modelPoint(t.Params))
+
+		m, ok := means[key]
+		if !ok {
+			m = &pointMean[T]{params: t.Params}
+			means[key] = m
+
+			order = append(order, key)
+		}
+
+		m.sum += t.ExecutionTime
+		m.count++
+		m.trialID = t.TrialID
+	}
+
+	var best *pointMean[T]
+
+	// Ties go to the first measured point.
+	for _, key := range order {
+		if m := means[key]; best == nil || m.mean() < best.mean() {
+			best = m
+		}
+	}
+
+	if best == nil {
+		return previous, false
+	}
+
+	o.bestTime = best.mean()
+	o.bestTrialID = best.trialID
+
+	copy(o.bestParams, best.params)
+
+	return previous, best.trialID == trial.TrialID && o.bestTime < previous
+}
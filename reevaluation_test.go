@@ -0,0 +1,129 @@
+package ho
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flukeObjective is minimal at 7, with a little noise, but its first
+// measurement below 3, where it's at least 1.6, is a fluke at -1.
+func flukeObjective(seed int64) func(params ...float64) (float64, error) {
+	var mu sync.Mutex
+
+	rng := rand.New(rand.NewSource(seed))
+
+	fluked := false
+
+	return func(params ...float64) (float64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if params[0] < 3 && !fluked {
+			fluked = true
+
+			return -1, nil
+		}
+
+		return (params[0]-7)*(params[0]-7)/10 + 0.05*rng.NormFloat64(), nil
+	}
+}
+
+func TestIncumbentReevaluation(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	newConfig := func() OptimizationConfig {
+		config := fastConfig()
+		config.Seed = 1
+		config.InitialSamples = 5
+		config.Iterations = 20
+
+		return config
+	}
+
+	// Without re-evaluation, the fluke stays the best.
+	config := newConfig()
+
+	result := OptimizeObjective(config, flukeObjective(1), ranges...)
+
+	assert.NoError(t, result.Err)
+	assert.Equal(t, -1.0, result.BestTime)
+	assert.Less(t, result.BestParams[0], 3.0)
+
+	config = newConfig()
+	config.IncumbentReevaluation = &IncumbentReevaluation{Every: 4}
+
+	result = OptimizeObjective(config, flukeObjective(1), ranges...)
+
+	if !assert.NoError(t, result.Err) {
+		return
+	}
+
+	assert.Len(t, result.Trials, config.InitialSamples+config.Iterations)
+
+	// Every 4th iteration is a re-evaluation of the best parameters at the
+	// time, counted as an iteration.
+	var checks int
+
+	for _, trial := range result.Trials {
+		if trial.Phase != PhaseIncumbentCheck {
+			continue
+		}
+
+		checks++
+
+		assert.Zero(t, trial.Iteration%4)
+		assert.Equal(t, PhaseIncumbentCheck, NewTrialRecord(trial, nil).Phase)
+	}
+
+	assert.Equal(t, config.Iterations/4, checks)
+
+	// The fluke regressed, and the true optimum is recommended, valued at
+	// the mean of its measurements.
+	assert.InDelta(t, 7, result.BestParams[0], 0.5)
+	assert.Greater(t, result.BestTime, -0.1)
+
+	var sum float64
+
+	var count int
+
+	for _, trial := range result.Trials {
+		if trial.Params[0] == result.BestParams[0] {
+			sum += trial.ExecutionTime
+			count++
+		}
+	}
+
+	assert.InDelta(t, sum/float64(count), result.BestTime, 1e-9)
+
+	// The model merged the measurements of the fluke.
+	for _, trial := range result.Trials {
+		if trial.ExecutionTime == -1 {
+			mean, _, err := result.Predict(paramsToFloat64s(trial.Params))
+
+			if assert.NoError(t, err) {
+				assert.Greater(t, mean, 0.0)
+			}
+		}
+	}
+}
+
+func TestIncumbentReevaluationInvalid(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	config := fastConfig()
+	config.IncumbentReevaluation = &IncumbentReevaluation{Every: -1}
+
+	result := OptimizeObjective(config, flukeObjective(1), ranges...)
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+
+	config.IncumbentReevaluation = &IncumbentReevaluation{}
+	config.CacheEvaluations = true
+
+	result = OptimizeObjective(config, flukeObjective(1), ranges...)
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	assert.False(t, math.IsNaN(result.BestTime))
+}
@@ -0,0 +1,67 @@
+package ho
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Helpers.
+//////
+
+// computeRegret computes the regret curves of the trials against the known
+// optimum. Skipped trials aren't evaluations, they're ignored.
+//
+// Parameters:
+// - optimum: The known optimum
+// - trials: Trials, in completion order
+// - bestParams: Best parameters found
+//
+// Returns:
+// - *Regret: The regret curves.
+func computeRegret[T constraints.Integer | constraints.Float](
+	optimum *KnownOptimum,
+	trials []Trial[T],
+	bestParams []T,
+) *Regret {
+	regret := &Regret{
+		Simple:     make([]float64, 0, len(trials)),
+		Cumulative: make([]float64, 0, len(trials)),
+	}
+
+	best := math.MaxFloat64
+
+	var cumulative float64
+
+	for _, trial := range trials {
+		if trial.Status == TrialSkipped {
+			continue
+		}
+
+		best = math.Min(best, trial.ExecutionTime)
+
+		cumulative += trial.Regret
+
+		regret.Simple = append(regret.Simple, best-optimum.Value)
+		regret.Cumulative = append(regret.Cumulative, cumulative)
+	}
+
+	if len(regret.Simple) > 0 {
+		regret.Final = regret.Simple[len(regret.Simple)-1]
+	}
+
+	if optimum.Location != nil {
+		var sum float64
+
+		for i, v := range paramsToFloat64s(bestParams) {
+			diff := v - optimum.Location[i]
+
+			sum += diff * diff
+		}
+
+		regret.Distance = math.Sqrt(sum)
+	}
+
+	return regret
+}
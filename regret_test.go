@@ -0,0 +1,57 @@
+package ho
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho/benchfuncs"
+)
+
+func TestRegret(t *testing.T) {
+	for _, f := range []benchfuncs.Function{benchfuncs.Branin(), benchfuncs.Rastrigin(2), benchfuncs.Ackley(2)} {
+		t.Run(f.Name, func(t *testing.T) {
+			config := fastConfig()
+			config.KnownOptimum = &KnownOptimum{Value: f.Optimum, Location: f.Minimizers[0]}
+
+			progressChan := make(chan ProgressUpdate, config.InitialSamples+config.Iterations)
+			config.ProgressChan = progressChan
+
+			result := newSyntheticOptimizer(config, f, f.Objective, 1).run()
+
+			close(progressChan)
+
+			regret := result.Regret
+
+			assert.Len(t, regret.Simple, config.InitialSamples+config.Iterations)
+			assert.Len(t, regret.Cumulative, config.InitialSamples+config.Iterations)
+
+			for i := range regret.Simple {
+				// Regret is non-negative.
+				assert.GreaterOrEqual(t, regret.Simple[i], 0.0)
+				assert.GreaterOrEqual(t, result.Trials[i].Regret, 0.0)
+
+				// Simple regret never increases, cumulative never decreases.
+				if i > 0 {
+					assert.LessOrEqual(t, regret.Simple[i], regret.Simple[i-1])
+					assert.GreaterOrEqual(t, regret.Cumulative[i], regret.Cumulative[i-1])
+				}
+			}
+
+			assert.InDelta(t, result.BestTime-f.Optimum, regret.Final, 1e-12)
+			assert.Greater(t, regret.Distance, 0.0)
+
+			// Progress updates carry instantaneous regret.
+			for update := range progressChan {
+				assert.InDelta(t, update.LastExecutionTime-f.Optimum, update.InstantaneousRegret, 1e-12)
+			}
+		})
+	}
+}
+
+func TestNoRegretWithoutKnownOptimum(t *testing.T) {
+	f := benchfuncs.Branin()
+
+	result := newSyntheticOptimizer(fastConfig(), f, f.Objective, 1).run()
+
+	assert.Nil(t, result.Regret)
+}
@@ -0,0 +1,111 @@
+package ho
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+//////
+// Methods.
+//////
+
+// ReplayTrial re-runs a trial of the run, e.g. to investigate a surprising
+// result: the benchmark is invoked with the exact parameters and TrialInfo
+// of the trial, seed included, and measured as during the run.
+//
+// Parameters:
+// - trialID: The trial to replay, see TrialInfo.TrialID
+// - benchmarkFunc: The benchmark of the run
+//
+// Returns:
+// - Trial[T]: The replay, with the TrialInfo and parameters of the trial. It
+// isn't recorded in the result
+// - error: Wrapping ErrUnknownTrial if the result has no such trial.
+//
+// Usage example:
+//
+//	replay, err := result.ReplayTrial(42, func(info TrialInfo, params ...int) error {
+//	    return runWorkload(rand.New(rand.NewSource(info.Seed)), params[0])
+//	})
+//
+// Important notes:
+// - The benchmark sees the same TrialInfo.Seed as during the run, so a
+// benchmark drawing its randomness from it replays the same workload
+// - Failed replays are penalized as failed trials are.
+func (r *Result[T]) ReplayTrial(trialID int, benchmarkFunc BenchmarkFuncWithInfo[T]) (Trial[T], error) {
+	var recorded *Trial[T]
+
+	for i := range r.Trials {
+		if r.Trials[i].TrialID == trialID {
+			recorded = &r.Trials[i]
+
+			break
+		}
+	}
+
+	if recorded == nil {
+		return Trial[T]{}, fmt.Errorf("%w: %d", ErrUnknownTrial, trialID)
+	}
+
+	params := append([]T(nil), recorded.Params...)
+
+	// The benchmark receives what it received during the run.
+	benchmarkParams := params
+	if recorded.BenchmarkParams != nil {
+		benchmarkParams = append([]T(nil), recorded.BenchmarkParams...)
+	}
+
+	startTime := time.Now()
+
+	err := benchmarkFunc(recorded.TrialInfo, benchmarkParams...)
+
+	duration := time.Since(startTime)
+
+	trial := Trial[T]{
+		TrialInfo:       recorded.TrialInfo,
+		BenchmarkParams: recorded.BenchmarkParams,
+		Params:          params,
+		ExecutionTime:   float64(duration.Nanoseconds()),
+		Duration:        duration,
+		StartedAt:       startTime,
+		Status:          TrialCompleted,
+		Err:             err,
+	}
+
+	switch {
+	case errors.Is(err, ErrSkipTrial):
+		trial.Status = TrialSkipped
+	case err != nil:
+		trial.Status = TrialFailed
+
+		trial.ExecutionTime = math.MaxFloat64/2 + trial.ExecutionTime
+	}
+
+	return trial, nil
+}
+
+// rngPosition returns the number of values drawn from the random number
+// generator of the run.
+func (o *optimizer[T]) rngPosition() uint64 {
+	o.rngMu.Lock()
+	defer o.rngMu.Unlock()
+
+	return o.source.draws
+}
+
+//////
+// Helpers.
+//////
+
+// trialSeed derives the seed of a trial from the seed of the run, with the
+// SplitMix64 finalizer, so seeds of consecutive trials are uncorrelated.
+func trialSeed(seed int64, trialID int) int64 {
+	z := uint64(seed) + uint64(trialID)*0x9e3779b97f4a7c15
+
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return int64(z ^ (z >> 31))
+}
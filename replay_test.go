@@ -0,0 +1,101 @@
+package ho
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayTrial(t *testing.T) {
+	config := fastConfig()
+	config.Seed = 5
+
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}, {Min: -5, Max: 5}}
+
+	infos := make(map[int]TrialInfo)
+
+	// The 4th trial fails, e.g. on a dropped connection.
+	benchmark := func(info TrialInfo, params ...float64) error {
+		infos[info.TrialID] = info
+
+		if info.TrialID == 4 {
+			return errors.New("connection reset")
+		}
+
+		return nil
+	}
+
+	result := OptimizeWithInfo(config, benchmark, ranges...)
+
+	assert.Equal(t, int64(5), result.Seed)
+
+	seeds := make(map[int64]bool)
+
+	for _, trial := range result.Trials {
+		assert.Equal(t, infos[trial.TrialID], trial.TrialInfo)
+		assert.Equal(t, trialSeed(5, trial.TrialID), trial.Seed)
+
+		seeds[trial.Seed] = true
+	}
+
+	assert.Len(t, seeds, len(result.Trials))
+
+	t.Run("replay", func(t *testing.T) {
+		var replayed []float64
+
+		replay, err := result.ReplayTrial(4, func(info TrialInfo, params ...float64) error {
+			assert.Equal(t, result.Trials[3].TrialInfo, info)
+
+			replayed = params
+
+			return errors.New("connection reset")
+		})
+
+		if !assert.NoError(t, err) || !assert.Len(t, replayed, 2) {
+			return
+		}
+
+		for i, v := range result.Trials[3].Params {
+			assert.Equal(t, math.Float64bits(v), math.Float64bits(replayed[i]))
+		}
+
+		assert.Equal(t, result.Trials[3].TrialInfo, replay.TrialInfo)
+		assert.Equal(t, TrialFailed, replay.Status)
+		assert.EqualError(t, replay.Err, "connection reset")
+
+		_, err = result.ReplayTrial(99, func(info TrialInfo, params ...float64) error { return nil })
+
+		assert.ErrorIs(t, err, ErrUnknownTrial)
+	})
+
+	t.Run("stream position", func(t *testing.T) {
+		// Initial samples are drawn at random: seeding the generator and
+		// skipping to the position of a trial draws its parameters again.
+		for _, trial := range result.Trials[:config.InitialSamples] {
+			o := newOptimizer[float64](context.Background(), config, nil, ranges...)
+
+			o.source.Seed(result.Seed)
+
+			for i := uint64(0); i < trial.RNGPosition; i++ {
+				o.source.Uint64()
+			}
+
+			assert.Equal(t, trial.Params, o.initialParams())
+		}
+	})
+
+	t.Run("records", func(t *testing.T) {
+		record := result.Records()[3]
+
+		assert.Equal(t, result.Trials[3].Seed, record.Seed)
+		assert.Equal(t, result.Trials[3].RNGPosition, record.RNGPosition)
+
+		again := OptimizeWithInfo(config, benchmark, ranges...)
+
+		assert.Equal(t, result.Records()[3].Seed, again.Records()[3].Seed)
+		assert.Equal(t, result.Records()[3].RNGPosition, again.Records()[3].RNGPosition)
+	})
+}
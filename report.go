@@ -0,0 +1,436 @@
+package ho
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultReportTitle is the ReportOptions.Title used if unset.
+	defaultReportTitle = "Optimization report"
+
+	// chartWidth and chartHeight are the size of the charts, in pixels.
+	chartWidth, chartHeight = 420, 220
+
+	// chartMargin is the room around the plot area, for axis labels.
+	chartMargin = 24
+)
+
+// templates holds the templates of the HTML report.
+//
+//go:embed templates/report.html.tmpl
+var templates embed.FS
+
+// reportTemplate is the template of the HTML report.
+var reportTemplate = template.Must(template.ParseFS(templates, "templates/report.html.tmpl"))
+
+// ReportOptions configures Result.WriteHTMLReport.
+type ReportOptions struct {
+	// Title is the title of the report.
+	// If empty, "Optimization report" is used.
+	Title string
+
+	// Durations renders values as durations, for values in nanoseconds,
+	// e.g. from Optimize. Otherwise values are rendered as numbers.
+	Durations bool
+}
+
+// reportData is what the report template renders.
+type reportData struct {
+	Title             string
+	Termination       TerminationReason
+	TerminationDetail string
+	Err               string
+	Best              string
+	Confidence        string
+	BestParams        []reportParam
+	Trials            int
+	Completed         int
+	Failed            int
+	Skipped           int
+	Seed              int64
+	StudyID           string
+	Warnings          []string
+	Convergence       *reportChart
+	Importance        []reportBar
+	ImportanceNote    string
+	Effects           []*reportChart
+	EffectsNote       string
+	Columns           []string
+	Rows              []reportRow
+}
+
+// reportParam is a named parameter value.
+type reportParam struct {
+	Name, Value string
+}
+
+// reportBar is the importance of a parameter.
+type reportBar struct {
+	Name    string
+	Percent float64
+}
+
+// reportRow is a row of the trial table.
+type reportRow struct {
+	Best  bool
+	Cells []reportCell
+}
+
+// reportCell is a cell of the trial table: its text, and the key it sorts
+// by, numeric when possible.
+type reportCell struct {
+	Text, Sort string
+}
+
+// reportLine is a polyline of a chart.
+type reportLine struct {
+	Class, Points string
+}
+
+// reportDot is a point of a chart, with its tooltip.
+type reportDot struct {
+	X, Y  string
+	Label string
+}
+
+// reportChart is an SVG chart, in pixel coordinates.
+type reportChart struct {
+	Title, XLabel          string
+	Width, Height          int
+	Band                   string
+	Lines                  []reportLine
+	Dots                   []reportDot
+	XMin, XMax, YMin, YMax string
+	LeftX, RightX, AxisY   int
+	BottomY                int
+	xmin, xmax, ymin, ymax float64
+	formatX, formatY       func(float64) string
+	plotWidth, plotHeight  float64
+	plotLeft, plotTop      float64
+	hasPoints              bool
+}
+
+//////
+// Methods.
+//////
+
+// WriteHTMLReport writes a self-contained HTML report of the run, to attach
+// to a ticket: the summary, the convergence curve, the parameter importance,
+// the effect of each parameter, and the trials, in a table sortable by
+// clicking its headers. Charts are inline SVG, so it opens offline.
+//
+// Parameters:
+// - w: Writer of the report, e.g. a file
+// - options: Configures the report
+//
+// Returns:
+// - error: The error of the writer, if any.
+//
+// Usage example:
+//
+//	f, err := os.Create("report.html")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//
+//	err = result.WriteHTMLReport(f, ReportOptions{Title: "Pool tuning", Durations: true})
+//
+// Important notes:
+// - Importance needs a few completed trials, see ParameterImportance, and
+// effects the model of the run, see ParameterEffects: sections that can't
+// be computed say why instead
+// - Failed and skipped trials are listed, but not charted.
+func (r *Result[T]) WriteHTMLReport(w io.Writer, options ReportOptions) error {
+	if options.Title == "" {
+		options.Title = defaultReportTitle
+	}
+
+	data := reportData{
+		Title:             options.Title,
+		Termination:       r.TerminationReason,
+		TerminationDetail: r.TerminationDetail,
+		Best:              "n/a",
+		Trials:            len(r.Trials),
+		Seed:              r.Seed,
+		StudyID:           r.StudyID,
+		Warnings:          r.Warnings,
+	}
+
+	value := func(v float64) string { return reportValue(v, options.Durations) }
+
+	if r.Err != nil {
+		data.Err = r.Err.Error()
+	}
+
+	if r.BestTime != math.MaxFloat64 {
+		data.Best = value(r.BestTime)
+
+		for i, v := range r.BestParams {
+			spec := formatSpec(r.hyper(i))
+
+			data.BestParams = append(data.BestParams, reportParam{Name: paramName(r.ParamNames, i), Value: spec.Format(float64(v))})
+		}
+
+		if ci, err := r.BestConfidence(defaultConfidenceLevel); err == nil {
+			data.Confidence = fmt.Sprintf("%.0f%% CI %s..%s, n=%d", ci.Level*100, value(ci.Lower), value(ci.Upper), ci.Samples)
+		}
+	}
+
+	for _, trial := range r.Trials {
+		switch trial.Status {
+		case TrialCompleted:
+			data.Completed++
+		case TrialFailed:
+			data.Failed++
+		case TrialSkipped:
+			data.Skipped++
+		}
+	}
+
+	data.Convergence = r.convergenceChart(value)
+
+	if importance, err := r.ParameterImportance(); err != nil {
+		data.ImportanceNote = err.Error()
+	} else {
+		for _, score := range importance.Scores {
+			data.Importance = append(data.Importance, reportBar{Name: score.Name, Percent: math.Round(score.Score * 100)})
+		}
+	}
+
+	if effects, err := r.ParameterEffects(); err != nil {
+		data.EffectsNote = err.Error()
+	} else {
+		for _, effect := range effects {
+			data.Effects = append(data.Effects, r.effectChart(effect, value))
+		}
+	}
+
+	data.Columns, data.Rows = r.trialTable(value)
+
+	return reportTemplate.Execute(w, data)
+}
+
+// convergenceChart charts the completed trials, and the best value so far,
+// by trial. Nil if no trial completed.
+func (r *Result[T]) convergenceChart(value func(float64) string) *reportChart {
+	chart := newReportChart("Best value by trial", "Trial", func(v float64) string { return strconv.Itoa(int(v)) }, value)
+
+	for i, trial := range r.Trials {
+		if trial.Status == TrialCompleted {
+			chart.extend(float64(i+1), trial.ExecutionTime)
+		}
+	}
+
+	if !chart.hasPoints {
+		return nil
+	}
+
+	var (
+		best   = math.MaxFloat64
+		points []string
+	)
+
+	for i, trial := range r.Trials {
+		if trial.Status != TrialCompleted {
+			continue
+		}
+
+		x := float64(i + 1)
+
+		// A step down at each improvement.
+		if trial.ExecutionTime < best {
+			if best != math.MaxFloat64 {
+				points = append(points, chart.point(x, best))
+			}
+
+			best = trial.ExecutionTime
+		}
+
+		points = append(points, chart.point(x, best))
+
+		cx, cy := chart.coordinates(x, trial.ExecutionTime)
+
+		chart.Dots = append(chart.Dots, reportDot{
+			X:     cx,
+			Y:     cy,
+			Label: fmt.Sprintf("trial %d: %s", trial.TrialID, value(trial.ExecutionTime)),
+		})
+	}
+
+	chart.Lines = []reportLine{{Class: "best", Points: strings.Join(points, " ")}}
+
+	return chart.finish()
+}
+
+// effectChart charts the effect of a parameter: the slice, with its
+// standard deviation band, and the partial dependence.
+func (r *Result[T]) effectChart(effect ParameterEffect, value func(float64) string) *reportChart {
+	spec := formatSpec(r.hyper(effect.Dim))
+
+	chart := newReportChart(effect.Name, effect.Name, spec.Format, value)
+
+	for i, x := range effect.Values {
+		chart.extend(x, effect.Slice.Mean[i]-effect.Slice.StdDev[i])
+		chart.extend(x, effect.Slice.Mean[i]+effect.Slice.StdDev[i])
+		chart.extend(x, effect.PartialDependence.Mean[i])
+	}
+
+	var mean, dependence, upper, lower []string
+
+	for i, x := range effect.Values {
+		mean = append(mean, chart.point(x, effect.Slice.Mean[i]))
+		dependence = append(dependence, chart.point(x, effect.PartialDependence.Mean[i]))
+		upper = append(upper, chart.point(x, effect.Slice.Mean[i]+effect.Slice.StdDev[i]))
+		lower = append([]string{chart.point(x, effect.Slice.Mean[i]-effect.Slice.StdDev[i])}, lower...)
+	}
+
+	chart.Band = strings.Join(append(upper, lower...), " ")
+	chart.Lines = []reportLine{
+		{Class: "mean", Points: strings.Join(mean, " ")},
+		{Class: "dependence", Points: strings.Join(dependence, " ")},
+	}
+
+	return chart.finish()
+}
+
+// trialTable returns the columns and rows of the trial table.
+func (r *Result[T]) trialTable(value func(float64) string) ([]string, []reportRow) {
+	columns := []string{"Trial", "Phase", "Status", "Value", "Duration"}
+
+	for i := range r.hypers {
+		columns = append(columns, paramName(r.ParamNames, i))
+	}
+
+	rows := make([]reportRow, len(r.Trials))
+
+	for i, trial := range r.Trials {
+		result := reportCell{Text: string(trial.Status), Sort: "Infinity"}
+
+		if trial.Status == TrialCompleted {
+			result = reportCell{Text: value(trial.ExecutionTime), Sort: strconv.FormatFloat(trial.ExecutionTime, 'g', -1, 64)}
+		}
+
+		cells := []reportCell{
+			{Text: strconv.Itoa(trial.TrialID), Sort: strconv.Itoa(trial.TrialID)},
+			{Text: trial.Phase, Sort: trial.Phase},
+			{Text: string(trial.Status), Sort: string(trial.Status)},
+			result,
+			{Text: reportValue(float64(trial.Duration), true), Sort: strconv.FormatInt(trial.Duration.Nanoseconds(), 10)},
+		}
+
+		for d, v := range trial.Params {
+			cells = append(cells, reportCell{Text: formatSpec(r.hyper(d)).Format(float64(v)), Sort: strconv.FormatFloat(float64(v), 'g', -1, 64)})
+		}
+
+		rows[i] = reportRow{
+			Best:  trial.Status == TrialCompleted && trial.ExecutionTime == r.BestTime,
+			Cells: cells,
+		}
+	}
+
+	return columns, rows
+}
+
+// extend grows the ranges of the chart to the point.
+func (c *reportChart) extend(x, y float64) {
+	if math.IsNaN(y) || math.IsInf(y, 0) {
+		return
+	}
+
+	if !c.hasPoints {
+		c.xmin, c.xmax, c.ymin, c.ymax = x, x, y, y
+		c.hasPoints = true
+
+		return
+	}
+
+	c.xmin, c.xmax = math.Min(c.xmin, x), math.Max(c.xmax, x)
+	c.ymin, c.ymax = math.Min(c.ymin, y), math.Max(c.ymax, y)
+}
+
+// coordinates returns the pixel coordinates of a point, within the ranges.
+func (c *reportChart) coordinates(x, y float64) (string, string) {
+	px, py := c.plotLeft+c.plotWidth/2, c.plotTop+c.plotHeight/2
+
+	if c.xmax > c.xmin {
+		px = c.plotLeft + (x-c.xmin)/(c.xmax-c.xmin)*c.plotWidth
+	}
+
+	if c.ymax > c.ymin {
+		py = c.plotTop + (c.ymax-y)/(c.ymax-c.ymin)*c.plotHeight
+	}
+
+	return strconv.FormatFloat(px, 'f', 1, 64), strconv.FormatFloat(py, 'f', 1, 64)
+}
+
+// point returns the pixel coordinates of a point, as a polyline vertex.
+func (c *reportChart) point(x, y float64) string {
+	px, py := c.coordinates(x, y)
+
+	return px + "," + py
+}
+
+// finish sets the axis labels, once the ranges are known.
+func (c *reportChart) finish() *reportChart {
+	c.XMin, c.XMax = c.formatX(c.xmin), c.formatX(c.xmax)
+	c.YMin, c.YMax = c.formatY(c.ymin), c.formatY(c.ymax)
+
+	return c
+}
+
+//////
+// Helpers.
+//////
+
+// reportValue renders a value, as a duration if durations is true.
+func reportValue(v float64, durations bool) string {
+	if durations {
+		d := time.Duration(math.Round(v))
+
+		// Sub-millisecond values keep their digits.
+		if d >= time.Millisecond {
+			d = d.Round(time.Microsecond)
+		}
+
+		return d.String()
+	}
+
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+//////
+// Factory.
+//////
+
+// newReportChart creates an empty chart, its axis labels rendered by formatX
+// and formatY.
+func newReportChart(title, xLabel string, formatX, formatY func(float64) string) *reportChart {
+	return &reportChart{
+		Title:      title,
+		XLabel:     xLabel,
+		Width:      chartWidth,
+		Height:     chartHeight,
+		LeftX:      chartMargin,
+		RightX:     chartWidth - 4,
+		AxisY:      chartHeight - 4,
+		BottomY:    chartHeight - chartMargin,
+		formatX:    formatX,
+		formatY:    formatY,
+		plotLeft:   chartMargin,
+		plotTop:    chartMargin / 2,
+		plotWidth:  chartWidth - 2*chartMargin,
+		plotHeight: chartHeight - 2*chartMargin,
+	}
+}
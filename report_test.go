@@ -0,0 +1,97 @@
+package ho
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHTMLReport(t *testing.T) {
+	config := fastConfig()
+	config.Seed = 7
+	config.Iterations = 10
+
+	calls := 0
+
+	result := OptimizeObjective(config, func(params ...int) (float64, error) {
+		calls++
+
+		// A failure, listed but not charted.
+		if calls == 2 {
+			return 0, errors.New("boom")
+		}
+
+		return float64((params[0]-6)*(params[0]-6) + params[1]), nil
+	},
+		ParameterRange[int]{Name: "Workers", Min: 1, Max: 12},
+		ParameterRange[int]{Name: "Batch", Min: 0, Max: 8},
+	)
+
+	var buf bytes.Buffer
+
+	if !assert.NoError(t, result.WriteHTMLReport(&buf, ReportOptions{Title: "Pool <tuning>"})) {
+		return
+	}
+
+	out := buf.String()
+
+	// Self-contained, and escaped.
+	assert.NotRegexp(t, regexp.MustCompile(`(src|href)="?https?:`), out)
+	assert.Contains(t, out, "<title>Pool &lt;tuning&gt;</title>")
+
+	for _, id := range []string{"summary", "convergence", "importance", "effects", "trials"} {
+		assert.Contains(t, out, `<section id="`+id+`">`)
+	}
+
+	assert.Contains(t, out, "<dd>"+string(TerminationCompleted)+": ")
+	assert.Contains(t, out, "<dt>Best value</dt><dd>"+reportValue(result.BestTime, false)+"</dd>")
+	assert.Contains(t, out, fmt.Sprintf("<dt>Workers</dt><dd>%d</dd>", result.BestParams[0]))
+	assert.Contains(t, out, "13 (12 completed, 1 failed, 0 skipped)")
+
+	// The convergence curve, and both effect charts.
+	assert.Equal(t, 3, strings.Count(out, "<svg"))
+	assert.Equal(t, 12, strings.Count(out, `<circle class="trial"`))
+	assert.Contains(t, out, `<polyline class="best"`)
+	assert.Equal(t, 2, strings.Count(out, `<polyline class="dependence"`))
+	assert.Contains(t, out, "<figcaption>Batch</figcaption>")
+
+	// Importance is computed, effects too.
+	assert.Contains(t, out, `<table class="importance">`)
+	assert.Regexp(t, `<tr><td>Workers</td>.*<td>\d+%</td></tr>`, out)
+
+	// A row per trial, the best highlighted, sortable.
+	assert.Equal(t, len(result.Trials), strings.Count(out, "<tr><td data-sort")+strings.Count(out, `<tr class="best-trial">`))
+	assert.Contains(t, out, "<th>Trial</th><th>Phase</th><th>Status</th><th>Value</th><th>Duration</th><th>Workers</th><th>Batch</th>")
+	assert.Contains(t, out, `<td data-sort="Infinity">Failed</td>`)
+	assert.Contains(t, out, `th.addEventListener("click"`)
+}
+
+func TestWriteHTMLReportEmpty(t *testing.T) {
+	result := &Result[int]{
+		BestTime:          math.MaxFloat64,
+		TerminationReason: TerminationContextCanceled,
+		hypers:            []ParameterRange[int]{{Name: "x", Min: 0, Max: 8}},
+	}
+
+	var buf bytes.Buffer
+
+	if !assert.NoError(t, result.WriteHTMLReport(&buf, ReportOptions{Durations: true})) {
+		return
+	}
+
+	out := buf.String()
+
+	// Sections that can't be computed say why.
+	assert.Contains(t, out, "<title>Optimization report</title>")
+	assert.Contains(t, out, "No completed trial.")
+	assert.Contains(t, out, "0 (0 completed, 0 failed, 0 skipped)")
+	assert.NotContains(t, out, "<svg")
+	assert.Equal(t, 3, strings.Count(out, `<p class="note">`))
+	assert.Contains(t, out, "at least 4 completed trials")
+}
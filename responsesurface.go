@@ -0,0 +1,236 @@
+package ho
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// ResponseSurface holds the model predictions over a grid of one or two
+// parameters, the others being fixed at the best parameters, e.g. to plot
+// what the model believes. See Result.PredictGrid.
+type ResponseSurface struct {
+	// Dims holds the indices of the gridded parameters.
+	Dims []int
+
+	// Names holds the names of the gridded parameters. Unnamed parameters are
+	// named "param<i>", as in TrialRecord.
+	Names []string
+
+	// X holds the grid coordinates along Dims[0], in increasing order.
+	X []float64
+
+	// Y holds the grid coordinates along Dims[1], in increasing order. Nil
+	// for a single dimension.
+	Y []float64
+
+	// Fixed holds the point the grid goes through, i.e. the best parameters.
+	// Parameters not in Dims are fixed at their value.
+	Fixed []float64
+
+	// Mean holds the predicted mean, Mean[i][j] being at (X[i], Y[j]). Rows
+	// have a single column for a single dimension.
+	Mean [][]float64
+
+	// StdDev holds the predicted standard deviation, laid out as Mean.
+	StdDev [][]float64
+}
+
+//////
+// Methods.
+//////
+
+// PredictGrid predicts the mean and standard deviation of the model at the
+// end of the run over a grid of one or two parameters, the others being
+// fixed at the best parameters.
+//
+// Parameters:
+// - dims: Indices of the parameters to grid, one or two
+// - resolution: Maximum number of grid points per parameter, at least 2
+//
+// Returns:
+// - *ResponseSurface: The predictions, see ResponseSurface.WriteCSV
+// - error: Wrapping ErrInvalidConfig if the dimensions or resolution are
+// invalid, or if the run has no best parameters.
+//
+// Usage example:
+//
+//	result := Optimize(config, benchmark, ranges...)
+//
+//	surface, err := result.PredictGrid([]int{0, 1}, 50)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	return surface.WriteCSV(f)
+//
+// Important notes:
+// - Predictions are made in the parameter space the model is fitted in:
+// grids are evenly spaced, geometrically for LogUniform ranges
+// - Integer parameters and ranges with a Step are gridded on their lattice,
+// so coarse lattices may have fewer than resolution points
+// - The model is a snapshot taken when the result was, so predictions from
+// an ask/tell Optimizer result don't change with later Tell calls.
+func (r *Result[T]) PredictGrid(dims []int, resolution int) (*ResponseSurface, error) {
+	switch {
+	case r.model == nil || len(r.BestParams) != len(r.hypers):
+		return nil, fmt.Errorf("%w: the run has no best parameters to predict around", ErrInvalidConfig)
+	case len(dims) != 1 && len(dims) != 2:
+		return nil, fmt.Errorf("%w: expected 1 or 2 dimensions, got %d", ErrInvalidConfig, len(dims))
+	case len(dims) == 2 && dims[0] == dims[1]:
+		return nil, fmt.Errorf("%w: dimension %d is repeated", ErrInvalidConfig, dims[0])
+	case resolution < 2:
+		return nil, fmt.Errorf("%w: resolution %d is less than 2", ErrInvalidConfig, resolution)
+	}
+
+	for _, dim := range dims {
+		if dim < 0 || dim >= len(r.hypers) {
+			return nil, fmt.Errorf("%w: dimension %d is out of range [0, %d)", ErrInvalidConfig, dim, len(r.hypers))
+		}
+	}
+
+	surface := &ResponseSurface{
+		Dims:  append([]int(nil), dims...),
+		Names: make([]string, len(dims)),
+		Fixed: paramsToFloat64s(r.BestParams),
+	}
+
+	for i, dim := range dims {
+		surface.Names[i] = paramName(r.ParamNames, dim)
+	}
+
+	surface.X = gridAxis(r.hypers[dims[0]], resolution)
+
+	// A single dimension is a grid with a single column.
+	ys := []float64{surface.Fixed[dims[0]]}
+
+	if len(dims) == 2 {
+		surface.Y = gridAxis(r.hypers[dims[1]], resolution)
+
+		ys = surface.Y
+	}
+
+	surface.Mean = make([][]float64, len(surface.X))
+	surface.StdDev = make([][]float64, len(surface.X))
+
+	// The model sees offsets from the Min of the ranges, see modelPoint.
+	point := modelPoint(r.hypers, r.BestParams)
+
+	for i, x := range surface.X {
+		surface.Mean[i] = make([]float64, len(ys))
+		surface.StdDev[i] = make([]float64, len(ys))
+
+		point[dims[0]] = x - float64(r.hypers[dims[0]].Min)
+
+		for j, y := range ys {
+			if len(dims) == 2 {
+				point[dims[1]] = y - float64(r.hypers[dims[1]].Min)
+			}
+
+			mean, variance := rawPrediction(r.model, point)
+
+			surface.Mean[i][j] = mean
+
+			// Rounding may yield slightly negative variances.
+			surface.StdDev[i][j] = math.Sqrt(math.Max(variance, 0))
+		}
+	}
+
+	return surface, nil
+}
+
+// WriteCSV writes the predictions as CSV, one row per grid point, with a
+// header naming the gridded parameters, then "mean" and "stddev".
+//
+// Parameters:
+// - w: Where the CSV is written
+//
+// Returns:
+// - error: If writing fails.
+//
+// Important notes:
+// - For two dimensions, rows are grouped by X, groups being separated by a
+// blank line, as gnuplot's splot expects. pandas' read_csv skips blank
+// lines by default.
+func (s *ResponseSurface) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	format := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+
+	if err := writer.Write(append(append([]string(nil), s.Names...), "mean", "stddev")); err != nil {
+		return err
+	}
+
+	for i, x := range s.X {
+		if s.Y == nil {
+			if err := writer.Write([]string{format(x), format(s.Mean[i][0]), format(s.StdDev[i][0])}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if i > 0 {
+			writer.Flush()
+
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+
+		for j, y := range s.Y {
+			if err := writer.Write([]string{format(x), format(y), format(s.Mean[i][j]), format(s.StdDev[i][j])}); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+//////
+// Helpers.
+//////
+
+// gridAxis returns up to resolution evenly spaced values of a parameter
+// range, snapped to its lattice, deduplicated and in increasing order.
+func gridAxis[T constraints.Integer | constraints.Float](hyper ParameterRange[T], resolution int) []float64 {
+	axis := make([]float64, 0, resolution)
+
+	for k := 0; k < resolution; k++ {
+		axis = appendAxisValue(axis, hyper, float64(k)/float64(resolution-1))
+	}
+
+	return axis
+}
+
+// appendAxisValue appends the value of a parameter range at fraction t of the
+// range, geometrically for LogUniform ranges, snapped to its lattice, unless
+// it's not greater than the last value of the axis.
+func appendAxisValue[T constraints.Integer | constraints.Float](axis []float64, hyper ParameterRange[T], t float64) []float64 {
+	min, max := float64(hyper.Min), float64(hyper.Max)
+
+	v := min + t*(max-min)
+
+	if _, log := hyper.Prior.(logUniform); log && min > 0 {
+		v = min * math.Pow(max/min, t)
+	}
+
+	snapped := float64(rangeValue(hyper, v))
+
+	if len(axis) > 0 && snapped <= axis[len(axis)-1] {
+		return axis
+	}
+
+	return append(axis, snapped)
+}
@@ -0,0 +1,161 @@
+package ho
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// knownSurface returns a result whose model is fitted to f(x, y) = (x-4)^2 + y
+// on a 3x3 lattice, observations being far apart relative to the kernel
+// width.
+func knownSurface() *Result[int] {
+	model := newGaussianProcess()
+
+	for x := 0; x <= 8; x += 4 {
+		for y := 0; y <= 8; y += 4 {
+			model.Update([]float64{float64(x), float64(y)}, float64((x-4)*(x-4)+y))
+		}
+	}
+
+	return &Result[int]{
+		BestParams: []int{4, 0},
+		ParamNames: []string{"x", ""},
+		hypers:     []ParameterRange[int]{{Name: "x", Min: 0, Max: 8}, {Min: 0, Max: 8, Step: 2}},
+		model:      model,
+	}
+}
+
+func TestPredictGrid(t *testing.T) {
+	result := knownSurface()
+
+	surface, err := result.PredictGrid([]int{0, 1}, 5)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"x", "param1"}, surface.Names)
+	assert.Equal(t, []float64{0, 2, 4, 6, 8}, surface.X)
+	assert.Equal(t, []float64{0, 2, 4, 6, 8}, surface.Y)
+	assert.Equal(t, []float64{4, 0}, surface.Fixed)
+
+	// At observed points, the mean is the observed value.
+	for i := 0; i < 5; i += 2 {
+		for j := 0; j < 5; j += 2 {
+			x, y := surface.X[i], surface.Y[j]
+
+			assert.InDelta(t, (x-4)*(x-4)+y, surface.Mean[i][j], 0.05)
+		}
+	}
+
+	// Uncertainty is lower at observed points.
+	assert.Less(t, surface.StdDev[0][0], surface.StdDev[1][1])
+	assert.Less(t, surface.StdDev[2][2], surface.StdDev[2][1])
+
+	// A single dimension goes through the best parameters.
+	surface, err = result.PredictGrid([]int{1}, 3)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{0, 4, 8}, surface.X)
+		assert.Nil(t, surface.Y)
+
+		for i, y := range surface.X {
+			mean, _ := result.model.Predict([]float64{4, y})
+
+			assert.Equal(t, []float64{mean}, surface.Mean[i])
+		}
+	}
+}
+
+func TestPredictGridLattice(t *testing.T) {
+	// Coarse lattices have fewer points than the resolution.
+	assert.Equal(t, []float64{0, 1, 2, 3}, gridAxis(ParameterRange[int]{Min: 0, Max: 3}, 10))
+	assert.Equal(t, []float64{0, 0.5, 1}, gridAxis(ParameterRange[float64]{Min: 0, Max: 1, Step: 0.5}, 10))
+	assert.Equal(t, []float64{-1, -0.5, 0, 0.5, 1}, gridAxis(ParameterRange[float64]{Min: -1, Max: 1}, 5))
+
+	// Log ranges are gridded geometrically.
+	axis := gridAxis(ParameterRange[float64]{Min: 1, Max: 1000, Prior: LogUniform()}, 4)
+
+	if assert.Len(t, axis, 4) {
+		for i, expected := range []float64{1, 10, 100, 1000} {
+			assert.InDelta(t, expected, axis[i], 1e-9)
+		}
+	}
+}
+
+func TestPredictGridRun(t *testing.T) {
+	result := OptimizeObjective(fastConfig(), func(params ...float64) (float64, error) {
+		return math.Abs(params[0]-1) + params[1], nil
+	}, ParameterRange[float64]{Name: "a", Min: -5, Max: 5}, ParameterRange[float64]{Name: "b", Min: 0, Max: 10, Step: 1})
+
+	surface, err := result.PredictGrid([]int{1, 0}, 11)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, surface.X)
+	assert.Len(t, surface.Y, 11)
+	assert.Equal(t, result.BestParams, surface.Fixed)
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, surface.WriteCSV(&buf))
+
+	// One block of rows per X value, separated by blank lines.
+	blocks := strings.Split(strings.TrimSpace(buf.String()), "\n\n")
+
+	if assert.Len(t, blocks, 11) {
+		lines := strings.Split(blocks[0], "\n")
+
+		assert.Equal(t, "b,a,mean,stddev", lines[0])
+		assert.Len(t, lines, 12)
+		assert.True(t, strings.HasPrefix(lines[1], "0,-5,"))
+	}
+}
+
+func TestPredictGridWriteCSV(t *testing.T) {
+	surface := &ResponseSurface{
+		Names:  []string{"x"},
+		X:      []float64{1, 2.5},
+		Mean:   [][]float64{{10}, {20.25}},
+		StdDev: [][]float64{{0.5}, {1}},
+	}
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, surface.WriteCSV(&buf))
+	assert.Equal(t, "x,mean,stddev\n1,10,0.5\n2.5,20.25,1\n", buf.String())
+}
+
+func TestPredictGridErrors(t *testing.T) {
+	result := knownSurface()
+
+	tests := []struct {
+		dims       []int
+		resolution int
+		err        string
+	}{
+		{dims: nil, resolution: 5, err: "expected 1 or 2 dimensions, got 0"},
+		{dims: []int{0, 1, 0}, resolution: 5, err: "expected 1 or 2 dimensions, got 3"},
+		{dims: []int{1, 1}, resolution: 5, err: "dimension 1 is repeated"},
+		{dims: []int{2}, resolution: 5, err: "dimension 2 is out of range [0, 2)"},
+		{dims: []int{0}, resolution: 1, err: "resolution 1 is less than 2"},
+	}
+
+	for _, tt := range tests {
+		_, err := result.PredictGrid(tt.dims, tt.resolution)
+
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, tt.err)
+	}
+
+	// Results without best parameters can't be predicted around.
+	_, err := (&Result[int]{}).PredictGrid([]int{0}, 5)
+
+	assert.ErrorContains(t, err, "the run has no best parameters to predict around")
+}
@@ -0,0 +1,175 @@
+package ho
+
+import (
+	"math"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// RunStats is a snapshot of the state of a run, polled while it goes on, see
+// RunHandle.Stats and Optimizer.Stats, e.g. for monitoring.
+type RunStats struct {
+	// Observations is the number of observations in the model, warm start
+	// included.
+	Observations int `json:"observations"`
+
+	// BestValue is the best value found so far, math.MaxFloat64 if no trial
+	// completed.
+	BestValue float64 `json:"bestValue"`
+
+	// BestParams holds the parameters of BestValue, as float64, nil if
+	// there's none.
+	BestParams []float64 `json:"bestParams,omitempty"`
+
+	// Kernel holds the effective kernel hyperparameters of the model, nil
+	// if it isn't kernel-based, e.g. a random forest.
+	Kernel *KernelStats `json:"kernel,omitempty"`
+
+	// Trials is the number of ended trials, skipped and failed ones
+	// included.
+	Trials int `json:"trials"`
+
+	// Completed is the number of completed trials.
+	Completed int `json:"completed"`
+
+	// Failed is the number of failed and canceled trials, e.g. timed out.
+	Failed int `json:"failed"`
+
+	// Elapsed is the wall time since the run started, pauses included.
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// KernelStats holds the effective hyperparameters of a kernel-based model:
+// Gaussian, additive and Student-t processes. Mean and Variance are in the
+// units the model sees, i.e. after OptimizationConfig.OutputTransform.
+type KernelStats struct {
+	// Width is the width of the RBF kernel.
+	Width float64 `json:"width"`
+
+	// Mean is the prior mean, the mean of the observed values.
+	Mean float64 `json:"mean"`
+
+	// Variance is the prior variance, the variance of the observed values.
+	Variance float64 `json:"variance"`
+
+	// Jitter is the variance added to the diagonal of the kernel matrix.
+	Jitter float64 `json:"jitter"`
+
+	// DegreesOfFreedom is the degrees of freedom of a Student-t process, 0
+	// for other models.
+	DegreesOfFreedom float64 `json:"degreesOfFreedom,omitempty"`
+}
+
+//////
+// Methods.
+//////
+
+// kernelStats returns the effective hyperparameters of the kernel.
+func (gp *gaussianProcess) kernelStats() *KernelStats {
+	gp.mu.RLock()
+	defer gp.mu.RUnlock()
+
+	return &KernelStats{
+		Width:    gp.sigma,
+		Mean:     gp.prior,
+		Variance: gp.scale,
+		Jitter:   gp.jitter,
+	}
+}
+
+// kernelStats returns the effective hyperparameters of the kernel, the prior
+// being the one of the shrunk observations.
+func (t *StudentTProcess) kernelStats() *KernelStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stats := t.gp.kernelStats()
+
+	stats.Mean, stats.Variance, stats.DegreesOfFreedom = t.prior, t.scale, t.nu
+
+	return stats
+}
+
+// stats returns a snapshot of the state of the run. The caller must hold mu.
+func (o *optimizer[T]) stats() RunStats {
+	stats := RunStats{
+		BestValue: o.bestTime,
+		Kernel:    kernelStatsOf(o.model),
+		Trials:    len(o.trials),
+		Elapsed:   o.control.elapsed(true),
+	}
+
+	if o.model != nil {
+		stats.Observations = o.model.Len()
+	}
+
+	if o.bestTime != math.MaxFloat64 {
+		stats.BestParams = paramsToFloat64s(o.bestParams)
+	}
+
+	for _, trial := range o.trials {
+		switch trial.Status {
+		case TrialCompleted:
+			stats.Completed++
+		case TrialFailed, TrialCanceled:
+			stats.Failed++
+		}
+	}
+
+	return stats
+}
+
+// Stats returns a snapshot of the state of the run, safe to poll while it
+// goes on, e.g. from a monitoring goroutine.
+//
+// Returns:
+// - RunStats: The snapshot, the final state once the run ended.
+func (h *RunHandle[T]) Stats() RunStats {
+	h.o.mu.Lock()
+	defer h.o.mu.Unlock()
+
+	return h.o.stats()
+}
+
+// Stats returns a snapshot of the state of the optimization, safe to poll
+// along Ask and Tell. Elapsed is the wall time since the Optimizer was
+// created, or last reset, see Optimizer.Reset.
+//
+// Returns:
+// - RunStats: The snapshot.
+func (opt *Optimizer[T]) Stats() RunStats {
+	o := opt.current.Load()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.stats()
+}
+
+//////
+// Helpers.
+//////
+
+// kernelStatsOf returns the effective kernel hyperparameters of the model,
+// transformed or not, nil if it isn't kernel-based.
+func kernelStatsOf(model SurrogateModel) *KernelStats {
+	switch m := model.(type) {
+	case *gaussianProcess:
+		return m.kernelStats()
+	case *StudentTProcess:
+		return m.kernelStats()
+	case *AdditiveGaussianProcess:
+		return m.gp.kernelStats()
+	case *HeteroscedasticGaussianProcess:
+		return m.gp.kernelStats()
+	case *transformedModel:
+		return kernelStatsOf(m.inner())
+	case *latticeModel:
+		return kernelStatsOf(m.model)
+	default:
+		return nil
+	}
+}
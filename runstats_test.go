@@ -0,0 +1,126 @@
+package ho
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStats(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	t.Run("polled during a run", func(t *testing.T) {
+		config := fastConfig()
+		config.Iterations = 10
+
+		var calls int
+
+		run := StartObjective(context.Background(), config, func(ctx context.Context, params ...float64) (float64, error) {
+			calls++
+
+			time.Sleep(2 * time.Millisecond)
+
+			if calls == 2 {
+				return 0, errors.New("crashed")
+			}
+
+			return math.Pow(params[0]-3, 2), nil
+		}, ranges...)
+
+		var (
+			wg    sync.WaitGroup
+			polls []RunStats
+		)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-run.Done():
+					return
+				default:
+					polls = append(polls, run.Stats())
+				}
+			}
+		}()
+
+		result := run.Wait()
+
+		wg.Wait()
+
+		assert.NotEmpty(t, polls)
+
+		for i, stats := range polls {
+			assert.LessOrEqual(t, stats.Completed+stats.Failed, stats.Trials)
+			assert.LessOrEqual(t, stats.Observations, stats.Completed)
+			assert.Positive(t, stats.Elapsed)
+
+			if i > 0 {
+				assert.GreaterOrEqual(t, stats.Trials, polls[i-1].Trials)
+				assert.LessOrEqual(t, stats.BestValue, polls[i-1].BestValue)
+			}
+		}
+
+		stats := run.Stats()
+
+		assert.Equal(t, len(result.Trials), stats.Trials)
+		assert.Equal(t, len(result.Trials)-1, stats.Completed)
+		assert.Equal(t, 1, stats.Failed)
+		assert.Equal(t, stats.Completed, stats.Observations)
+		assert.Equal(t, result.BestTime, stats.BestValue)
+		assert.Equal(t, result.BestParams, stats.BestParams)
+
+		if assert.NotNil(t, stats.Kernel) {
+			assert.Positive(t, stats.Kernel.Width)
+			assert.Positive(t, stats.Kernel.Variance)
+			assert.Positive(t, stats.Kernel.Jitter)
+			assert.Zero(t, stats.Kernel.DegreesOfFreedom)
+		}
+	})
+
+	t.Run("ask/tell", func(t *testing.T) {
+		config := fastConfig()
+		config.Surrogate = func() SurrogateModel { return NewStudentTProcess(StudentTProcessOptions{}) }
+
+		opt, err := NewOptimizer(config, ranges...)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		stats := opt.Stats()
+
+		assert.Zero(t, stats.Trials)
+		assert.Nil(t, stats.BestParams)
+		assert.Equal(t, math.MaxFloat64, stats.BestValue)
+
+		suggestion, err := opt.Ask()
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, err = opt.Tell(suggestion.TrialID, 4, nil)
+
+		assert.NoError(t, err)
+
+		stats = opt.Stats()
+
+		assert.Equal(t, 1, stats.Trials)
+		assert.Equal(t, 1, stats.Observations)
+		assert.Equal(t, 4.0, stats.BestValue)
+		assert.Equal(t, suggestion.Params, stats.BestParams)
+		assert.Positive(t, stats.Elapsed)
+		assert.Less(t, stats.Elapsed, time.Minute)
+
+		if assert.NotNil(t, stats.Kernel) {
+			assert.Positive(t, stats.Kernel.DegreesOfFreedom)
+		}
+	})
+}
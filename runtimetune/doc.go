@@ -0,0 +1,23 @@
+// Package runtimetune tunes the Go runtime knobs, GOGC, GOMAXPROCS and
+// optionally GOMEMLIMIT, for a workload of the process, e.g. a representative
+// slice of a service's traffic:
+//
+//	result, err := runtimetune.Tune(ctx, func(ctx context.Context) error {
+//	    return replayTraffic(ctx, sample)
+//	}, runtimetune.Options{})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	fmt.Printf("%+v is %.0f%% faster than %+v\n", result.Best, result.Improvement*100, result.Baseline)
+//
+// The search space is opinionated: GOGC from 25 to 800, sampled in log space,
+// and GOMAXPROCS from 1 to runtime.NumCPU. Each trial applies its settings
+// with debug.SetGCPercent, runtime.GOMAXPROCS and debug.SetMemoryLimit, runs
+// the workload several times, each after a garbage collection so garbage of
+// the previous run isn't billed to the next, and restores the original
+// settings. Its value is the median duration.
+//
+// The settings are process-wide: trials run one at a time, and nothing else
+// in the process should be timing-sensitive while tuning.
+package runtimetune
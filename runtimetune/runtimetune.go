@@ -0,0 +1,308 @@
+package runtimetune
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/thalesfsp/ho"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultRepeats is the Options.Repeats used if unset.
+	defaultRepeats = 3
+
+	// minGOGC and maxGOGC bound the GOGC values tried.
+	minGOGC, maxGOGC = 25, 800
+)
+
+// Options configures the tuning, see Tune.
+type Options struct {
+	// Config controls the optimization, e.g. its number of iterations. Its
+	// MaxConcurrentEvaluations is forced to 1, as the settings are
+	// process-wide.
+	// If nil, ho.DefaultConfig is used.
+	Config *ho.OptimizationConfig
+
+	// Repeats is the number of times the workload runs per trial, the trial
+	// value being the median duration.
+	// If 0, it runs 3 times.
+	Repeats int
+
+	// SkipGC disables the garbage collection before each workload run, e.g.
+	// if the workload collects on its own.
+	SkipGC bool
+
+	// MinMemoryLimit and MaxMemoryLimit, in bytes, bound the GOMEMLIMIT
+	// values tried, in log space.
+	// If MaxMemoryLimit is 0, GOMEMLIMIT isn't tuned.
+	MinMemoryLimit, MaxMemoryLimit int64
+}
+
+// Settings are values of the runtime knobs.
+type Settings struct {
+	// GOGC is the garbage collection target percentage, see
+	// debug.SetGCPercent. Negative if the garbage collector is off.
+	GOGC int
+
+	// GOMAXPROCS is the number of threads executing Go code at once, see
+	// runtime.GOMAXPROCS.
+	GOMAXPROCS int
+
+	// MemoryLimit is the soft memory limit in bytes, see
+	// debug.SetMemoryLimit. math.MaxInt64 if there's none.
+	MemoryLimit int64
+}
+
+// Result is the outcome of Tune.
+type Result struct {
+	// Best holds the best settings found. Knobs that weren't tuned keep
+	// their Baseline value.
+	Best Settings
+
+	// Baseline holds the settings before tuning, restored since.
+	Baseline Settings
+
+	// BestTime is the median duration of the workload with Best.
+	BestTime time.Duration
+
+	// BaselineTime is the median duration of the workload with Baseline.
+	BaselineTime time.Duration
+
+	// Improvement is the fraction of BaselineTime saved with Best, e.g. 0.2
+	// if it's 20% faster. Negative if the baseline is faster, which is then
+	// the settings to keep.
+	Improvement float64
+
+	// Run is the outcome of the optimization, parameters being GOGC,
+	// GOMAXPROCS and, if tuned, GOMEMLIMIT.
+	Run *ho.Result[int]
+}
+
+//////
+// Methods.
+//////
+
+// apply applies the settings.
+//
+// Returns:
+// - Settings: The previous settings.
+func (s Settings) apply() Settings {
+	return Settings{
+		GOGC:        debug.SetGCPercent(s.GOGC),
+		GOMAXPROCS:  runtime.GOMAXPROCS(s.GOMAXPROCS),
+		MemoryLimit: debug.SetMemoryLimit(s.MemoryLimit),
+	}
+}
+
+// validate checks the options.
+//
+// Returns:
+// - error: Wrapping ho.ErrInvalidConfig if an option is invalid, nil
+// otherwise.
+func (o Options) validate() error {
+	switch {
+	case o.Repeats < 0:
+		return fmt.Errorf("%w: Repeats %d is negative", ho.ErrInvalidConfig, o.Repeats)
+	case o.MaxMemoryLimit < 0:
+		return fmt.Errorf("%w: MaxMemoryLimit %d is negative", ho.ErrInvalidConfig, o.MaxMemoryLimit)
+	case o.MaxMemoryLimit > 0 && (o.MinMemoryLimit <= 0 || o.MinMemoryLimit > o.MaxMemoryLimit):
+		return fmt.Errorf("%w: MinMemoryLimit %d must be positive and at most MaxMemoryLimit %d",
+			ho.ErrInvalidConfig, o.MinMemoryLimit, o.MaxMemoryLimit)
+	}
+
+	return nil
+}
+
+// measure runs the workload Repeats times, after a garbage collection unless
+// SkipGC is set.
+//
+// Returns:
+// - time.Duration: The median duration, garbage collections excluded
+// - error: The first workload error, nil otherwise.
+func (o Options) measure(ctx context.Context, workload func(ctx context.Context) error) (time.Duration, error) {
+	repeats := o.Repeats
+	if repeats == 0 {
+		repeats = defaultRepeats
+	}
+
+	durations := make([]time.Duration, repeats)
+
+	for i := range durations {
+		if !o.SkipGC {
+			runtime.GC()
+		}
+
+		start := time.Now()
+
+		if err := workload(ctx); err != nil {
+			return 0, err
+		}
+
+		durations[i] = time.Since(start)
+	}
+
+	slices.Sort(durations)
+
+	if repeats%2 == 1 {
+		return durations[repeats/2], nil
+	}
+
+	return (durations[repeats/2-1] + durations[repeats/2]) / 2, nil
+}
+
+// ranges returns the search space.
+func (o Options) ranges() []ho.ParameterRange[int] {
+	ranges := []ho.ParameterRange[int]{
+		{Name: "GOGC", Min: minGOGC, Max: maxGOGC, Prior: ho.LogUniform()},
+		{Name: "GOMAXPROCS", Min: 1, Max: runtime.NumCPU()},
+	}
+
+	if o.MaxMemoryLimit > 0 {
+		ranges = append(ranges, ho.ParameterRange[int]{
+			Name:  "GOMEMLIMIT",
+			Min:   int(o.MinMemoryLimit),
+			Max:   int(o.MaxMemoryLimit),
+			Prior: ho.LogUniform(),
+		})
+	}
+
+	return ranges
+}
+
+//////
+// Helpers.
+//////
+
+// settingsOf returns the settings of parameters, untuned knobs keeping their
+// baseline value.
+func settingsOf(params []int, baseline Settings) Settings {
+	settings := Settings{GOGC: params[0], GOMAXPROCS: params[1], MemoryLimit: baseline.MemoryLimit}
+
+	if len(params) > 2 {
+		settings.MemoryLimit = int64(params[2])
+	}
+
+	return settings
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Current returns the current settings.
+//
+// Returns:
+// - Settings: The settings.
+func Current() Settings {
+	// GOGC can only be read by setting it.
+	gogc := debug.SetGCPercent(100)
+
+	debug.SetGCPercent(gogc)
+
+	return Settings{
+		GOGC:        gogc,
+		GOMAXPROCS:  runtime.GOMAXPROCS(0),
+		MemoryLimit: debug.SetMemoryLimit(-1),
+	}
+}
+
+// Tune finds the runtime settings the workload runs fastest with, see the
+// package documentation.
+//
+// Parameters:
+// - ctx: Run context, see ho.OptimizeObjectiveWithContext
+// - workload: The workload to speed up. It runs Options.Repeats times per
+// trial, and to measure the baseline
+// - options: Configures the tuning
+//
+// Returns:
+// - *Result: The best settings, and how they compare to the current ones
+// - error: Wrapping ho.ErrInvalidConfig if an option is invalid, the error
+// of the baseline measurement, or of the run if no trial completed.
+//
+// Usage example:
+//
+//	config := ho.DefaultConfig()
+//	config.Iterations = 40
+//
+//	result, err := runtimetune.Tune(ctx, workload, runtimetune.Options{
+//	    Config:         &config,
+//	    Repeats:        5,
+//	    MinMemoryLimit: 256 << 20,
+//	    MaxMemoryLimit: 4 << 30,
+//	})
+//
+// Important notes:
+// - The settings are restored once Tune returns, even if it fails, and
+// between trials
+// - The baseline is fed to the model as a warm start observation, if it's
+// within the search space.
+func Tune(ctx context.Context, workload func(ctx context.Context) error, options Options) (*Result, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	config := ho.DefaultConfig()
+
+	if options.Config != nil {
+		config = *options.Config
+	}
+
+	config.MaxConcurrentEvaluations = 1
+
+	baseline := Current()
+
+	defer baseline.apply()
+
+	baselineTime, err := options.measure(ctx, workload)
+	if err != nil {
+		return nil, fmt.Errorf("measuring the baseline: %w", err)
+	}
+
+	ranges := options.ranges()
+
+	if options.MaxMemoryLimit == 0 &&
+		baseline.GOGC >= minGOGC && baseline.GOGC <= maxGOGC && baseline.GOMAXPROCS <= runtime.NumCPU() {
+		config.WarmStart = append(slices.Clip(config.WarmStart), ho.Observation{
+			Params: []float64{float64(baseline.GOGC), float64(baseline.GOMAXPROCS)},
+			Value:  float64(baselineTime.Nanoseconds()),
+		})
+	}
+
+	run := ho.OptimizeObjectiveWithContext(ctx, config, func(ctx context.Context, params ...int) (float64, error) {
+		previous := settingsOf(params, baseline).apply()
+
+		defer previous.apply()
+
+		d, err := options.measure(ctx, workload)
+
+		return float64(d.Nanoseconds()), err
+	}, ranges...)
+
+	if run.BestTime == math.MaxFloat64 {
+		if run.Err != nil {
+			return nil, run.Err
+		}
+
+		return nil, errors.New("no trial completed")
+	}
+
+	return &Result{
+		Best:         settingsOf(run.BestParams, baseline),
+		Baseline:     baseline,
+		BestTime:     time.Duration(run.BestTime),
+		BaselineTime: baselineTime,
+		Improvement:  1 - run.BestTime/float64(baselineTime.Nanoseconds()),
+		Run:          run,
+	}, nil
+}
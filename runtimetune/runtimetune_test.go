@@ -0,0 +1,104 @@
+package runtimetune
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho"
+)
+
+// sink keeps allocations alive, so they aren't optimized away.
+var sink [][]byte
+
+// allocate is an allocation-heavy workload.
+func allocate(context.Context) error {
+	sink = nil
+
+	for i := 0; i < 2000; i++ {
+		sink = append(sink, make([]byte, 4096))
+
+		if len(sink) > 100 {
+			sink = sink[1:]
+		}
+	}
+
+	return nil
+}
+
+func TestTune(t *testing.T) {
+	// Non-default settings, which must be restored.
+	original := Settings{GOGC: 150, GOMAXPROCS: max(runtime.NumCPU()/2, 1), MemoryLimit: 8 << 30}
+
+	previous := original.apply()
+	defer previous.apply()
+
+	config := ho.DefaultConfig()
+	config.Seed = 1
+	config.InitialSamples = 3
+	config.Iterations = 3
+	config.NumCandidates = 20
+
+	t.Run("tune", func(t *testing.T) {
+		var calls int
+
+		workload := func(ctx context.Context) error {
+			calls++
+
+			return allocate(ctx)
+		}
+
+		result, err := Tune(context.Background(), workload, Options{
+			Config:         &config,
+			Repeats:        2,
+			MinMemoryLimit: 64 << 20,
+			MaxMemoryLimit: 1 << 30,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, original, Current())
+		assert.Equal(t, original, result.Baseline)
+
+		// The baseline, then each trial, repeated.
+		assert.Equal(t, 2*(1+len(result.Run.Trials)), calls)
+
+		assert.GreaterOrEqual(t, result.Best.GOGC, minGOGC)
+		assert.LessOrEqual(t, result.Best.GOGC, maxGOGC)
+		assert.GreaterOrEqual(t, result.Best.GOMAXPROCS, 1)
+		assert.LessOrEqual(t, result.Best.GOMAXPROCS, runtime.NumCPU())
+		assert.GreaterOrEqual(t, result.Best.MemoryLimit, int64(64<<20))
+		assert.LessOrEqual(t, result.Best.MemoryLimit, int64(1<<30))
+
+		assert.Positive(t, result.BaselineTime)
+		assert.Positive(t, result.BestTime)
+		assert.InDelta(t, 1-float64(result.BestTime)/float64(result.BaselineTime), result.Improvement, 1e-9)
+	})
+
+	t.Run("without GOMEMLIMIT", func(t *testing.T) {
+		result, err := Tune(context.Background(), allocate, Options{Config: &config, Repeats: 1, SkipGC: true})
+		assert.NoError(t, err)
+
+		assert.Equal(t, original, Current())
+		assert.Equal(t, original.MemoryLimit, result.Best.MemoryLimit)
+		assert.Equal(t, []string{"GOGC", "GOMAXPROCS"}, result.Run.ParamNames)
+	})
+
+	t.Run("failing workload", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		_, err := Tune(context.Background(), func(context.Context) error { return boom }, Options{Config: &config})
+		assert.ErrorIs(t, err, boom)
+
+		assert.Equal(t, original, Current())
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := Tune(context.Background(), allocate, Options{MaxMemoryLimit: 1 << 30})
+		assert.ErrorIs(t, err, ho.ErrInvalidConfig)
+
+		_, err = Tune(context.Background(), allocate, Options{Repeats: -1})
+		assert.ErrorIs(t, err, ho.ErrInvalidConfig)
+	})
+}
@@ -0,0 +1,434 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// DefaultSafetyStdDevs is the Safety.StdDevs used if unset.
+	DefaultSafetyStdDevs = 2
+
+	// DefaultSafetyMaxStep is the Safety.MaxStep used if unset.
+	DefaultSafetyMaxStep = 0.05
+)
+
+// Safety constrains the exploration to points predicted to stay below a hard
+// limit, e.g. a latency SLO of a shared staging system, see
+// OptimizationConfig.Safety.
+//
+// Important notes:
+// - Initial samples are drawn uniformly within SafeRegion, priors, filters
+// and exclusion zones aside
+// - Candidates outside of SafeRegion are only eligible if they're within
+// MaxStep of a point observed safe, and the predicted mean plus StdDevs
+// standard deviations is at most Limit. Far from observed points,
+// predictions revert to the mean of the observed values, so the explored
+// region grows step by step from the safe one instead. If no candidate is
+// eligible, a point of SafeRegion is evaluated, and a warning is recorded in
+// Result.Warnings
+// - Without a Metric, the constrained value is the objective, and failed
+// trials count as violations, e.g. a crash of the target. Timeouts are
+// failures, see OptimizationConfig.TrialTimeout
+// - Violations that slip through, e.g. because the model was wrong, are
+// flagged in Trial.SafetyViolation, listed in Result.SafetyViolations and
+// recorded in Result.Warnings.
+type Safety struct {
+	// Limit is the hard limit the constrained value must stay at or below,
+	// in objective units (nanoseconds for execution times) or Metric units.
+	Limit float64
+
+	// StdDevs is the number of standard deviations added to the predicted
+	// mean: the higher, the more confident predictions must be, e.g. 3 is
+	// about 99.9% for a Gaussian.
+	// If 0, DefaultSafetyStdDevs is used.
+	StdDevs float64
+
+	// Metric measures the auxiliary metric the limit applies to, e.g. the
+	// error rate of the target, once the trial completed and before the
+	// Teardown hook. It has its own model, fitted on the metric values.
+	// Errors are recorded in Result.Warnings, the trial is kept.
+	// If nil, the limit applies to the objective. Not supported by the
+	// ask/tell Optimizer, whose Tell only reports the objective.
+	Metric func(info TrialInfo, params ...float64) (float64, error)
+
+	// SafeRegion is a region known to be safe, one value per parameter range.
+	// Initial samples are drawn within it, and its points are always
+	// eligible. It's required.
+	SafeRegion *Box
+
+	// MaxStep is the maximum distance of eligible candidates to the nearest
+	// point observed safe, in each dimension, as a fraction of the range
+	// width.
+	// If 0, DefaultSafetyMaxStep is used.
+	MaxStep float64
+
+	// AbortOnViolation ends the run on the first violation, with termination
+	// reason TerminationSafetyViolation, and Result.Err wrapping
+	// ErrSafetyViolation.
+	AbortOnViolation bool
+}
+
+// safetyState holds the state of the safety constraint of a run.
+type safetyState struct {
+	// model is the model of the Metric, nil if the limit applies to the
+	// objective.
+	model SurrogateModel
+
+	// safe holds the points whose Metric was observed within the limit,
+	// protected by mu. Points of the objective are observations.
+	safe [][]float64
+
+	// violations holds the IDs of the trials that violated the limit.
+	violations []int
+
+	// err is the violation the run was aborted with, if any.
+	err error
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the settings against the search space.
+//
+// Parameters:
+// - mins, maxs: Bounds of the parameter ranges
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a setting is invalid, nil otherwise.
+func (s *Safety) validate(mins, maxs []float64) error {
+	switch {
+	case math.IsNaN(s.Limit) || math.IsInf(s.Limit, 0):
+		return fmt.Errorf("%w: Safety: Limit %v isn't finite", ErrInvalidConfig, s.Limit)
+	case !(s.StdDevs >= 0) || math.IsInf(s.StdDevs, 0):
+		return fmt.Errorf("%w: Safety: StdDevs %v must be finite and non-negative", ErrInvalidConfig, s.StdDevs)
+	case !(s.MaxStep >= 0 && s.MaxStep <= 1):
+		return fmt.Errorf("%w: Safety: MaxStep %v must be within [0, 1]", ErrInvalidConfig, s.MaxStep)
+	case s.SafeRegion == nil:
+		return fmt.Errorf("%w: Safety: SafeRegion is required", ErrInvalidConfig)
+	}
+
+	region := s.SafeRegion
+
+	if len(region.Min) != len(mins) || len(region.Max) != len(mins) {
+		return fmt.Errorf(
+			"%w: Safety: SafeRegion must have %d dimensions, got min=%d max=%d",
+			ErrInvalidConfig, len(mins), len(region.Min), len(region.Max),
+		)
+	}
+
+	for d := range mins {
+		switch {
+		case region.Min[d] > region.Max[d]:
+			return fmt.Errorf("%w: Safety: SafeRegion min (%v) greater than max (%v) in dimension %d", ErrInvalidConfig, region.Min[d], region.Max[d], d)
+		case region.Max[d] < mins[d] || region.Min[d] > maxs[d]:
+			return fmt.Errorf("%w: Safety: SafeRegion is outside of the range of dimension %d", ErrInvalidConfig, d)
+		}
+	}
+
+	return nil
+}
+
+// stdDevs returns the number of standard deviations added to predictions.
+func (s *Safety) stdDevs() float64 {
+	if s.StdDevs == 0 {
+		return DefaultSafetyStdDevs
+	}
+
+	return s.StdDevs
+}
+
+// maxStep returns the maximum step from points observed safe.
+func (s *Safety) maxStep() float64 {
+	if s.MaxStep == 0 {
+		return DefaultSafetyMaxStep
+	}
+
+	return s.MaxStep
+}
+
+// safetyErr returns the violation the run was aborted with, if any. The
+// caller must hold mu.
+func (o *optimizer[T]) safetyErr() error {
+	if o.safety == nil {
+		return nil
+	}
+
+	return o.safety.err
+}
+
+// safeRegionParams draws parameters uniformly within the SafeRegion, on the
+// lattice of integer and stepped parameters.
+//
+// Important notes:
+// - Lattice points nearest to a draw may lie just outside of the region, so
+// draws are repeated a few times, after which the last one is returned
+// - Unlike the region, exclusion zones and constraints are never bypassed:
+// if no draw satisfies them, a random point of the space is returned.
+func (o *optimizer[T]) safeRegionParams() []T {
+	region := o.config.Safety.SafeRegion
+
+	params := make([]T, len(o.hypers))
+
+	o.rngMu.Lock()
+
+	for attempt := 0; attempt < maxDrawsFactor; attempt++ {
+		for d, hyper := range o.hypers {
+			low := math.Max(region.Min[d], float64(hyper.Min))
+			high := math.Min(region.Max[d], float64(hyper.Max))
+
+			params[d] = rangeValue(hyper, low+o.rng.Float64()*(high-low))
+		}
+
+		if region.Contains(paramsToFloat64s(params)) && o.feasible(params) {
+			break
+		}
+	}
+
+	o.rngMu.Unlock()
+
+	if !o.feasible(params) {
+		return o.randomParams()
+	}
+
+	return params
+}
+
+// safetyModel returns the model predicting the constrained value: the model
+// of the Metric, or the given objective model.
+func (o *optimizer[T]) safetyModel(objective SurrogateModel) SurrogateModel {
+	if o.safety.model != nil {
+		return o.safety.model
+	}
+
+	return objective
+}
+
+// safeCandidates tells which points are eligible under the safety
+// constraint: within the SafeRegion, or within MaxStep of a point observed
+// safe and predicted to satisfy the limit with enough confidence.
+//
+// Parameters:
+// - model: The objective model, with failures, see withFailures
+// - candidates: The candidates, checked against the SafeRegion
+// - points: Their points, as the models see them, see modelPoint
+//
+// Returns:
+// - []bool: Whether each point is eligible.
+func (o *optimizer[T]) safeCandidates(model SurrogateModel, candidates [][]T, points [][]float64) []bool {
+	safety := o.config.Safety
+
+	model = o.safetyModel(model)
+
+	o.mu.Lock()
+
+	observed := o.safety.safe
+
+	if o.safety.model == nil {
+		observed = nil
+
+		for _, observation := range o.observations {
+			if observation.Value <= safety.Limit {
+				observed = append(observed, observation.Params)
+			}
+		}
+	}
+
+	o.mu.Unlock()
+
+	steps := make([]float64, len(o.hypers))
+
+	for d, hyper := range o.hypers {
+		steps[d] = safety.maxStep() * (float64(hyper.Max) - float64(hyper.Min))
+	}
+
+	near := func(point []float64) bool {
+		for _, p := range observed {
+			within := true
+
+			for d := range point {
+				if math.Abs(point[d]-p[d]) > steps[d] {
+					within = false
+
+					break
+				}
+			}
+
+			if within {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	k := safety.stdDevs()
+
+	safe := make([]bool, len(points))
+
+	for i, point := range points {
+		if safety.SafeRegion.Contains(paramsToFloat64s(candidates[i])) {
+			safe[i] = true
+
+			continue
+		}
+
+		if model.Len() == 0 || !near(point) {
+			continue
+		}
+
+		mean, variance := rawPrediction(model, point)
+
+		safe[i] = mean+k*math.Sqrt(math.Max(variance, 0)) <= safety.Limit
+	}
+
+	return safe
+}
+
+// safeFallback returns the parameters evaluated when no candidate is
+// eligible: a point of the SafeRegion.
+func (o *optimizer[T]) safeFallback() []T {
+	o.warnf("no candidate predicted safe, evaluating a point of the safe region")
+
+	return o.safeRegionParams()
+}
+
+// measureSafety measures the safety Metric of a completed trial, if set.
+//
+// Parameters:
+// - trial: The trial, SafetyMetric is set on success
+func (o *optimizer[T]) measureSafety(trial *Trial[T]) {
+	if o.config.Safety == nil || o.config.Safety.Metric == nil || trial.Status != TrialCompleted {
+		return
+	}
+
+	params, _ := o.benchmarkParams(trial.Params)
+
+	value, err := o.config.Safety.Metric(trial.TrialInfo, paramsToFloat64s(params)...)
+
+	switch {
+	case err != nil:
+		o.warnf("trial %d: safety metric not measured: %v", trial.TrialID, err)
+	case math.IsNaN(value) || math.IsInf(value, 0):
+		o.warnf("trial %d: safety metric not measured: %v isn't finite", trial.TrialID, value)
+	default:
+		trial.SafetyMetric = &value
+	}
+}
+
+// checkSafety flags the trial if it violated the safety limit, and feeds its
+// metric to the model of the Metric. Cached, skipped and canceled trials
+// never violate the limit, as they didn't reach the system under test in
+// full.
+//
+// Parameters:
+// - trial: The trial, SafetyViolation is set if it violated the limit
+func (o *optimizer[T]) checkSafety(trial *Trial[T]) {
+	safety := o.config.Safety
+
+	if safety == nil || trial.Cached {
+		return
+	}
+
+	value, violated := trial.ExecutionTime, false
+
+	switch {
+	case safety.Metric != nil:
+		if trial.SafetyMetric == nil {
+			return
+		}
+
+		value = *trial.SafetyMetric
+
+		if err := o.safety.model.Update(o.modelPoint(trial.Params), value); err != nil {
+			o.warnf("trial %d: safety metric not fed to the model: %v", trial.TrialID, err)
+		}
+
+		violated = value > safety.Limit
+
+		if !violated {
+			o.mu.Lock()
+			o.safety.safe = append(o.safety.safe, o.modelPoint(trial.Params))
+			o.mu.Unlock()
+		}
+	case trial.Status == TrialFailed:
+		violated = true
+	case trial.Status == TrialCompleted:
+		violated = value > safety.Limit
+	}
+
+	if !violated {
+		return
+	}
+
+	trial.SafetyViolation = true
+
+	detail := fmt.Sprintf("%v exceeds the limit %v", value, safety.Limit)
+	if trial.Status == TrialFailed {
+		detail = fmt.Sprintf("failed: %v", trial.Err)
+	}
+
+	o.warnf("trial %d violated the safety limit: %s", trial.TrialID, detail)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.safety.violations = append(o.safety.violations, trial.TrialID)
+
+	if safety.AbortOnViolation && o.safety.err == nil {
+		o.safety.err = fmt.Errorf("%w: trial %d %s", ErrSafetyViolation, trial.TrialID, detail)
+	}
+}
+
+// restoreSafety restores the safety state of a trial of a checkpoint, before
+// the run resumes: its violation, and its metric, fed to the model of the
+// Metric.
+func (o *optimizer[T]) restoreSafety(trial Trial[T]) {
+	if o.safety == nil || trial.Cached {
+		return
+	}
+
+	if trial.SafetyViolation {
+		o.safety.violations = append(o.safety.violations, trial.TrialID)
+	}
+
+	if o.safety.model != nil && trial.SafetyMetric != nil {
+		// The value was fed to the model when the trial ended.
+		_ = o.safety.model.Update(o.modelPoint(trial.Params), *trial.SafetyMetric)
+
+		if !trial.SafetyViolation {
+			o.safety.safe = append(o.safety.safe, o.modelPoint(trial.Params))
+		}
+	}
+}
+
+//////
+// Factory.
+//////
+
+// newSafetyState creates the safety state of a run.
+//
+// Parameters:
+// - config: The run configuration
+// - newModel: Creates the model of the Metric
+//
+// Returns:
+// - *safetyState: The state, nil if Safety isn't set.
+func newSafetyState(config OptimizationConfig, newModel func() SurrogateModel) *safetyState {
+	if config.Safety == nil {
+		return nil
+	}
+
+	state := &safetyState{}
+
+	if config.Safety.Metric != nil {
+		state.model = newModel()
+	}
+
+	return state
+}
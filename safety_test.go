@@ -0,0 +1,154 @@
+package ho
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dangerZone is minimal at (6, 5), next to a danger zone beyond x = 7 where
+// it climbs steeply past 40, e.g. a latency SLO.
+func dangerZone(params ...float64) (float64, error) {
+	x, y := params[0], params[1]
+
+	value := ((x-6)*(x-6) + (y-5)*(y-5)) / 4
+
+	if x > 7 {
+		value += 100 * (x - 7) * (x - 7)
+	}
+
+	return value, nil
+}
+
+// dangerRanges is the search space of dangerZone.
+var dangerRanges = []ParameterRange[float64]{{Name: "x", Min: 0, Max: 10}, {Name: "y", Min: 0, Max: 10}}
+
+func TestSafety(t *testing.T) {
+	safeConfig := func(seed int64) OptimizationConfig {
+		config := DefaultConfig()
+		config.Seed = seed
+		config.InitialSamples = 5
+		config.Iterations = 30
+		config.Safety = &Safety{
+			Limit:      40,
+			SafeRegion: &Box{Min: []float64{0, 0}, Max: []float64{3, 10}},
+		}
+
+		return config
+	}
+
+	t.Run("objective", func(t *testing.T) {
+		for seed := int64(1); seed <= 4; seed++ {
+			config := safeConfig(seed)
+
+			result := OptimizeObjective(config, dangerZone, dangerRanges...)
+
+			assert.NoError(t, result.Err)
+			assert.Empty(t, result.SafetyViolations)
+
+			for _, trial := range result.Trials {
+				assert.LessOrEqual(t, trial.ExecutionTime, 40.0, trial.Params)
+				assert.False(t, trial.SafetyViolation)
+
+				if trial.Phase == PhaseInitialSampling {
+					assert.True(t, config.Safety.SafeRegion.Contains(paramsToFloat64s(trial.Params)), trial.Params)
+				}
+			}
+
+			// The optimum next to the danger zone is still found.
+			assert.Less(t, result.BestTime, 0.5)
+
+			// Unconstrained, the same run wanders into the danger zone.
+			config.Safety = nil
+
+			var violations int
+
+			for _, trial := range OptimizeObjective(config, dangerZone, dangerRanges...).Trials {
+				if trial.ExecutionTime > 40 {
+					violations++
+				}
+			}
+
+			assert.Greater(t, violations, 3)
+		}
+	})
+
+	t.Run("metric", func(t *testing.T) {
+		config := safeConfig(1)
+
+		// The objective is minimal at (10, 10), but the error rate must stay
+		// at most 1.5, i.e. x + y at most 15.
+		config.Safety.Limit = 1.5
+		config.Safety.Metric = func(_ TrialInfo, params ...float64) (float64, error) {
+			return (params[0] + params[1]) / 10, nil
+		}
+
+		result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+			return 20 - params[0] - params[1], nil
+		}, dangerRanges...)
+
+		assert.NoError(t, result.Err)
+		assert.Empty(t, result.SafetyViolations)
+
+		for _, trial := range result.Trials {
+			if assert.NotNil(t, trial.SafetyMetric) {
+				assert.InDelta(t, (trial.Params[0]+trial.Params[1])/10, *trial.SafetyMetric, 1e-9)
+				assert.LessOrEqual(t, *trial.SafetyMetric, 1.5)
+			}
+		}
+
+		// The best parameters approach the limit.
+		assert.Less(t, result.BestTime, 7.0)
+	})
+
+	t.Run("abort on violation", func(t *testing.T) {
+		config := safeConfig(1)
+
+		// The declared safe region isn't.
+		config.Safety.SafeRegion = &Box{Min: []float64{8, 0}, Max: []float64{10, 10}}
+		config.Safety.AbortOnViolation = true
+
+		result := OptimizeObjective(config, dangerZone, dangerRanges...)
+
+		assert.Equal(t, TerminationSafetyViolation, result.TerminationReason)
+		assert.ErrorIs(t, result.Err, ErrSafetyViolation)
+		assert.Len(t, result.Trials, 1)
+		assert.Equal(t, []int{1}, result.SafetyViolations)
+		assert.True(t, result.Trials[0].SafetyViolation)
+		assert.Contains(t, result.Warnings[0], "trial 1 violated the safety limit")
+
+		// Violations are reported.
+		assert.True(t, NewTrialRecord(result.Trials[0], result.ParamNames).SafetyViolation)
+	})
+
+	t.Run("failures are violations", func(t *testing.T) {
+		config := safeConfig(1)
+		config.Iterations = 0
+
+		result := Optimize(config, func(...float64) error { return assert.AnError }, dangerRanges...)
+
+		assert.Len(t, result.SafetyViolations, config.InitialSamples)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, safety := range []*Safety{
+			{Limit: 1},
+			{Limit: 1, StdDevs: -1, SafeRegion: &Box{Min: []float64{0, 0}, Max: []float64{1, 1}}},
+			{Limit: 1, MaxStep: 2, SafeRegion: &Box{Min: []float64{0, 0}, Max: []float64{1, 1}}},
+			{Limit: 1, SafeRegion: &Box{Min: []float64{0}, Max: []float64{1}}},
+			{Limit: 1, SafeRegion: &Box{Min: []float64{20, 0}, Max: []float64{30, 1}}},
+		} {
+			config := safeConfig(1)
+			config.Safety = safety
+
+			result := OptimizeObjective(config, dangerZone, dangerRanges...)
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+		}
+
+		config := safeConfig(1)
+		config.Safety.Metric = func(TrialInfo, ...float64) (float64, error) { return 0, nil }
+
+		_, err := NewOptimizer(config, dangerRanges...)
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+}
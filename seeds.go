@@ -0,0 +1,164 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// SeedAggregation determines how the values measured with the seeds of a
+// trial are combined into its value, see OptimizationConfig.SeedsPerTrial.
+type SeedAggregation string
+
+const (
+	// SeedMean minimizes the mean value across seeds.
+	SeedMean SeedAggregation = "Mean"
+
+	// SeedMedian minimizes the median value across seeds, e.g. when a seed
+	// occasionally yields an outlier.
+	SeedMedian SeedAggregation = "Median"
+
+	// SeedWorst minimizes the worst (highest) value across seeds, for
+	// parameters that must hold up whatever the seed.
+	SeedWorst SeedAggregation = "Worst"
+)
+
+//////
+// Methods.
+//////
+
+// validate checks the aggregation.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if the aggregation is unknown, nil
+// otherwise.
+func (a SeedAggregation) validate() error {
+	switch a {
+	case "", SeedMean, SeedMedian, SeedWorst:
+		return nil
+	default:
+		return fmt.Errorf("%w: SeedAggregation: unknown strategy %q", ErrInvalidConfig, a)
+	}
+}
+
+// aggregate combines the values measured with each seed.
+func (a SeedAggregation) aggregate(values []float64) float64 {
+	switch a {
+	case SeedMedian:
+		return median(values)
+	case SeedWorst:
+		worst := values[0]
+
+		for _, v := range values[1:] {
+			worst = max(worst, v)
+		}
+
+		return worst
+	default:
+		return mean(values)
+	}
+}
+
+// measureSeeds measures the parameters once per seed of the trial, see
+// OptimizationConfig.SeedsPerTrial, or once with the seed of the trial if
+// seeds aren't averaged.
+//
+// Parameters:
+// - info: Metadata of the trial
+// - params: Parameters to evaluate
+//
+// Returns:
+// - measurement: The aggregated outcome: its value combines the values of
+// the seeds, its duration and wait are the sums of theirs. The first seed
+// failing, skipped, canceled or yielding a non-finite value ends it with
+// its outcome.
+func (o *optimizer[T]) measureSeeds(info TrialInfo, params []T) measurement {
+	if o.config.SeedsPerTrial <= 1 {
+		return o.measure(info, params)
+	}
+
+	var (
+		aggregated measurement
+		duration   time.Duration
+		wait       time.Duration
+	)
+
+	for i, seed := range EvaluationSeeds(info.Seed, o.config.SeedsPerTrial) {
+		if i > 0 {
+			if err := o.cooldown(true); err != nil {
+				aggregated.canceled = err
+
+				break
+			}
+		}
+
+		seedInfo := info
+		seedInfo.Seed = seed
+		seedInfo.SeedIndex = i + 1
+
+		m := o.measure(seedInfo, params)
+
+		duration += m.duration
+		wait += m.wait
+
+		if i == 0 {
+			aggregated.startedAt = m.startedAt
+		}
+
+		aggregated.seedValues = append(aggregated.seedValues, m.value)
+
+		if m.err != nil || m.canceled != nil || math.IsNaN(m.value) || math.IsInf(m.value, 0) {
+			aggregated.value, aggregated.err, aggregated.canceled = m.value, m.err, m.canceled
+
+			break
+		}
+	}
+
+	aggregated.duration, aggregated.wait = duration, wait
+
+	if aggregated.err == nil && aggregated.canceled == nil && len(aggregated.seedValues) == o.config.SeedsPerTrial {
+		aggregated.value = o.config.SeedAggregation.aggregate(aggregated.seedValues)
+	}
+
+	return aggregated
+}
+
+//////
+// Exported functionalities.
+//////
+
+// EvaluationSeeds returns the seeds a trial is evaluated with when
+// OptimizationConfig.SeedsPerTrial is set: each invocation of the benchmark
+// sees one of them as TrialInfo.Seed. They're derived from the seed of the
+// trial, itself derived from the seed of the run, so a run seeded alike
+// evaluates the same seeds.
+//
+// Parameters:
+// - seed: The seed of the trial, see Trial.Seed
+// - n: The number of seeds, see OptimizationConfig.SeedsPerTrial
+//
+// Returns:
+// - []int64: The seeds, in evaluation order.
+//
+// Usage example:
+//
+//	// Replay the trial with its second seed.
+//	seeds := EvaluationSeeds(trial.Seed, config.SeedsPerTrial)
+//
+//	info := trial.TrialInfo
+//	info.Seed, info.SeedIndex = seeds[1], 2
+//
+//	err := benchmark(info, trial.Params...)
+func EvaluationSeeds(seed int64, n int) []int64 {
+	seeds := make([]int64, n)
+
+	for i := range seeds {
+		seeds[i] = trialSeed(seed, i+1)
+	}
+
+	return seeds
+}
@@ -0,0 +1,183 @@
+package ho
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// seedSensitive is minimal at x=8 whatever the seed, but has a narrow well
+// around x=2, deeper than the minimum for half of the seeds and a spike for
+// the others.
+func seedSensitive(info TrialInfo, params ...float64) (float64, error) {
+	x := params[0]
+	value := (x - 8) * (x - 8) / 10
+
+	if d := math.Abs(x - 2); d < 1 {
+		if rand.New(rand.NewSource(info.Seed)).Float64() < 0.5 {
+			value -= 5 * (1 - d)
+		} else {
+			value += 20 * (1 - d)
+		}
+	}
+
+	return value, nil
+}
+
+func TestSeedsPerTrial(t *testing.T) {
+	config := fastConfig()
+	config.InitialSamples = 10
+	config.Iterations = 20
+	config.Seed = 2
+	config.SeedsPerTrial = 8
+
+	run := func() (*Result[float64], map[int][]int64) {
+		var mu sync.Mutex
+
+		seen := map[int][]int64{}
+
+		result := OptimizeObjectiveWithInfo(config, func(info TrialInfo, params ...float64) (float64, error) {
+			mu.Lock()
+			seen[info.TrialID] = append(seen[info.TrialID], info.Seed)
+
+			// Seeds are passed in order.
+			assert.Len(t, seen[info.TrialID], info.SeedIndex)
+			mu.Unlock()
+
+			return seedSensitive(info, params...)
+		}, ParameterRange[float64]{Name: "x", Min: 0, Max: 10})
+
+		assert.NoError(t, result.Err)
+
+		return result, seen
+	}
+
+	result, seen := run()
+
+	var fooled int
+
+	for _, trial := range result.Trials {
+		// Each trial is evaluated with its own seeds, derived from its seed.
+		assert.Equal(t, EvaluationSeeds(trial.Seed, config.SeedsPerTrial), seen[trial.TrialID])
+		assert.Zero(t, trial.SeedIndex)
+
+		if assert.Len(t, trial.SeedValues, config.SeedsPerTrial) {
+			assert.InDelta(t, mean(trial.SeedValues), trial.ExecutionTime, 1e-9)
+		}
+
+		// A lucky seed would have made the well look best.
+		for _, v := range trial.SeedValues {
+			if v < result.BestTime && math.Abs(trial.Params[0]-2) < 1 {
+				fooled++
+
+				break
+			}
+		}
+	}
+
+	assert.Positive(t, fooled)
+
+	// The optimum holds up whatever the seed.
+	assert.InDelta(t, 8, result.BestParams[0], 1.5)
+
+	for _, trial := range result.Trials {
+		if trial.ExecutionTime == result.BestTime {
+			for _, v := range trial.SeedValues {
+				assert.Less(t, v, 1.0)
+			}
+		}
+	}
+
+	// Seeds are reproducible from the seed of the run.
+	_, again := run()
+
+	assert.Equal(t, seen, again)
+
+	// Exports keep the values of the seeds.
+	assert.Equal(t, result.Trials[0].SeedValues, result.Records()[0].SeedValues)
+}
+
+func TestSeedAggregation(t *testing.T) {
+	values := []float64{3, 1, 8, 2}
+
+	for _, tt := range []struct {
+		aggregation SeedAggregation
+		want        float64
+	}{
+		{"", 3.5},
+		{SeedMean, 3.5},
+		{SeedMedian, 2.5},
+		{SeedWorst, 8},
+	} {
+		assert.Equal(t, tt.want, tt.aggregation.aggregate(values), tt.aggregation)
+	}
+
+	// The values aren't reordered.
+	assert.Equal(t, []float64{3, 1, 8, 2}, values)
+}
+
+func TestSeedsPerTrialFailures(t *testing.T) {
+	config := fastConfig()
+	config.SeedsPerTrial = 3
+	config.NonFiniteValues = NonFiniteSkip
+
+	errSeed := errors.New("diverged")
+
+	result := OptimizeObjectiveWithInfo(config, func(info TrialInfo, params ...int) (float64, error) {
+		switch {
+		case params[0] < 3 && info.SeedIndex == 2:
+			return 0, errSeed
+		case params[0] > 7 && info.SeedIndex == 3:
+			return math.NaN(), nil
+		}
+
+		return float64(params[0]), nil
+	}, ParameterRange[int]{Min: 0, Max: 10})
+
+	assert.NoError(t, result.Err)
+
+	for _, trial := range result.Trials {
+		switch {
+		case trial.Params[0] < 3:
+			// The failing seed ends the trial.
+			assert.Equal(t, TrialFailed, trial.Status)
+			assert.ErrorIs(t, trial.Err, errSeed)
+			assert.Len(t, trial.SeedValues, 2)
+		case trial.Params[0] > 7:
+			assert.Equal(t, TrialSkipped, trial.Status)
+			assert.True(t, math.IsNaN(trial.RawValue))
+			assert.Len(t, trial.SeedValues, 3)
+		default:
+			assert.Equal(t, TrialCompleted, trial.Status)
+			assert.Equal(t, float64(trial.Params[0]), trial.ExecutionTime)
+		}
+	}
+}
+
+func TestSeedsPerTrialInvalid(t *testing.T) {
+	objective := func(params ...int) (float64, error) { return float64(params[0]), nil }
+
+	for _, mutate := range []func(*OptimizationConfig){
+		func(c *OptimizationConfig) { c.SeedsPerTrial = -1 },
+		func(c *OptimizationConfig) { c.SeedAggregation = "Best" },
+	} {
+		config := fastConfig()
+		mutate(&config)
+
+		result := OptimizeObjective(config, objective, ParameterRange[int]{Min: 0, Max: 10})
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	}
+
+	// The caller evaluates ask/tell trials.
+	config := fastConfig()
+	config.SeedsPerTrial = 3
+
+	_, err := NewOptimizer(config, ParameterRange[int]{Min: 0, Max: 10})
+
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
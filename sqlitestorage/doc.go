@@ -0,0 +1,28 @@
+// Package sqlitestorage provides a ho.Storage recording studies in a SQLite
+// database, so every trial is durable as soon as it ends, and dashboards can
+// query runs in flight with plain SQL:
+//
+//	storage, err := sqlitestorage.Open("studies.db")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	defer storage.Close()
+//
+//	config := ho.DefaultConfig()
+//	config.Storage = storage
+//
+//	result := ho.Optimize(config, benchmark, ranges...)
+//
+// The database has two tables:
+//
+//	studies (id, created_at, meta, best)
+//	trials  (seq, study_id, trial_id, phase, iteration, status, value, record)
+//
+// meta, best and record hold the JSON representation of ho.StudyMeta and
+// ho.TrialRecord. value is the value of completed trials, NULL otherwise.
+//
+// The database runs in WAL mode with full synchronization: a trial is on disk
+// once AppendTrial returns, and readers never block the run. It uses the pure
+// Go modernc.org/sqlite driver, so cgo isn't required.
+package sqlitestorage
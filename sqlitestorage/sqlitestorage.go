@@ -0,0 +1,278 @@
+package sqlitestorage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/thalesfsp/ho"
+
+	// Registers the "sqlite" driver.
+	_ "modernc.org/sqlite"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// schema creates the tables, unless they exist.
+const schema = `
+CREATE TABLE IF NOT EXISTS studies (
+	id         TEXT PRIMARY KEY,
+	created_at TEXT NOT NULL,
+	meta       TEXT NOT NULL,
+	best       TEXT
+);
+
+CREATE TABLE IF NOT EXISTS trials (
+	seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+	study_id  TEXT NOT NULL REFERENCES studies (id),
+	trial_id  INTEGER NOT NULL,
+	phase     TEXT NOT NULL,
+	iteration INTEGER NOT NULL,
+	status    TEXT NOT NULL,
+	value     REAL,
+	record    TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS trials_study ON trials (study_id, seq);
+`
+
+// Storage is a ho.Storage recording studies in a SQLite database, see the
+// package documentation. It's safe for concurrent use.
+type Storage struct {
+	// db is the database. Writes are serialized on a single connection.
+	db *sql.DB
+}
+
+//////
+// Methods.
+//////
+
+// CreateStudy implements ho.Storage. IDs are random.
+func (s *Storage) CreateStudy(ctx context.Context, meta ho.StudyMeta) (string, error) {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+
+	id := newID()
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO studies (id, created_at, meta) VALUES (?, ?, ?)`,
+		id, time.Now().UTC().Format(time.RFC3339Nano), string(data),
+	); err != nil {
+		return "", fmt.Errorf("creating study: %w", err)
+	}
+
+	return id, nil
+}
+
+// AppendTrial implements ho.Storage. The trial is on disk once it returns.
+func (s *Storage) AppendTrial(ctx context.Context, studyID string, trial ho.TrialRecord) error {
+	data, err := json.Marshal(trial)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO trials (study_id, trial_id, phase, iteration, status, value, record) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		studyID, trial.ID, trial.Phase, trial.Iteration, string(trial.Status), trial.Value, string(data),
+	); err != nil {
+		return fmt.Errorf("appending trial %d to study %q: %w", trial.ID, studyID, err)
+	}
+
+	return nil
+}
+
+// UpdateBest implements ho.Storage.
+func (s *Storage) UpdateBest(ctx context.Context, studyID string, trial ho.TrialRecord) error {
+	data, err := json.Marshal(trial)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.ExecContext(ctx, `UPDATE studies SET best = ? WHERE id = ?`, string(data), studyID)
+	if err != nil {
+		return fmt.Errorf("updating the best trial of study %q: %w", studyID, err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("unknown study %q", studyID)
+	}
+
+	return nil
+}
+
+// LoadStudy implements ho.Storage.
+func (s *Storage) LoadStudy(ctx context.Context, studyID string) (*ho.StoredStudy, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT id, meta, best FROM studies WHERE id = ?`, studyID)
+
+	study, err := scanStudy(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("unknown study %q", studyID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("loading study %q: %w", studyID, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT record FROM trials WHERE study_id = ? ORDER BY seq`, studyID)
+	if err != nil {
+		return nil, fmt.Errorf("loading the trials of study %q: %w", studyID, err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			data  string
+			trial ho.TrialRecord
+		)
+
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(data), &trial); err != nil {
+			return nil, fmt.Errorf("decoding a trial of study %q: %w", studyID, err)
+		}
+
+		study.Trials = append(study.Trials, trial)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading the trials of study %q: %w", studyID, err)
+	}
+
+	return study, nil
+}
+
+// ListStudies implements ho.Storage.
+func (s *Storage) ListStudies(ctx context.Context) ([]ho.StoredStudy, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, meta, best FROM studies ORDER BY rowid`)
+	if err != nil {
+		return nil, fmt.Errorf("listing studies: %w", err)
+	}
+
+	defer rows.Close()
+
+	var studies []ho.StoredStudy
+
+	for rows.Next() {
+		study, err := scanStudy(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		studies = append(studies, *study)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing studies: %w", err)
+	}
+
+	return studies, nil
+}
+
+// Close closes the database.
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+//////
+// Helpers.
+//////
+
+// scanStudy decodes a row of the studies table, without trials.
+func scanStudy(row interface{ Scan(dest ...any) error }) (*ho.StoredStudy, error) {
+	var (
+		study ho.StoredStudy
+		meta  string
+		best  sql.NullString
+	)
+
+	if err := row.Scan(&study.ID, &meta, &best); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(meta), &study.Meta); err != nil {
+		return nil, fmt.Errorf("decoding the metadata of study %q: %w", study.ID, err)
+	}
+
+	if best.Valid {
+		study.Best = &ho.TrialRecord{}
+
+		if err := json.Unmarshal([]byte(best.String), study.Best); err != nil {
+			return nil, fmt.Errorf("decoding the best trial of study %q: %w", study.ID, err)
+		}
+	}
+
+	return &study, nil
+}
+
+// newID returns a random study ID.
+func newID() string {
+	b := make([]byte, 16)
+
+	// crypto/rand.Read never returns an error.
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+//////
+// Factory.
+//////
+
+// Open opens the SQLite database at path, creating it and its tables if
+// needed.
+//
+// Parameters:
+// - path: The database file
+//
+// Returns:
+// - *Storage: The storage, to close once done
+// - error: If the database can't be opened or initialized.
+//
+// Usage example:
+//
+//	storage, err := sqlitestorage.Open("studies.db")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	defer storage.Close()
+func Open(path string) (*Storage, error) {
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() + "?" + url.Values{
+		"_pragma": {
+			"busy_timeout(5000)",
+			"foreign_keys(1)",
+			"journal_mode(WAL)",
+			"synchronous(FULL)",
+		},
+	}.Encode()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// A single connection serializes writes, rather than failing them with
+	// SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("initializing %s: %w", path, err)
+	}
+
+	return &Storage{db: db}, nil
+}
@@ -0,0 +1,217 @@
+package sqlitestorage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho"
+)
+
+// durabilityChecker is a tracker checking, with a separate connection, that
+// every trial is in the database by the time the optimizer reports it ended.
+type durabilityChecker struct {
+	ho.MemoryTracker
+
+	t       *testing.T
+	path    string
+	studyID string
+}
+
+func (d *durabilityChecker) OnTrialEnd(trial ho.TrialRecord) {
+	reader, err := Open(d.path)
+	if !assert.NoError(d.t, err) {
+		return
+	}
+
+	defer reader.Close()
+
+	studies, err := reader.ListStudies(context.Background())
+	if !assert.NoError(d.t, err) || !assert.Len(d.t, studies, 1) {
+		return
+	}
+
+	study, err := reader.LoadStudy(context.Background(), studies[0].ID)
+	if !assert.NoError(d.t, err) {
+		return
+	}
+
+	var found bool
+
+	for _, stored := range study.Trials {
+		found = found || stored.ID == trial.ID
+	}
+
+	assert.True(d.t, found, "trial %d isn't in the database", trial.ID)
+}
+
+func TestStorage(t *testing.T) {
+	ranges := []ho.ParameterRange[float64]{{Name: "x", Min: 0, Max: 10}, {Name: "y", Min: -5, Max: 5}}
+
+	objective := func(params ...float64) (float64, error) {
+		return math.Pow(params[0]-3, 2) + math.Pow(params[1]-1, 2), nil
+	}
+
+	t.Run("run", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "studies.db")
+
+		storage, err := Open(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		defer storage.Close()
+
+		config := ho.DefaultConfig()
+		config.InitialSamples = 12
+		config.Iterations = 4
+		config.NumCandidates = 10
+		config.MaxConcurrentEvaluations = 6
+		config.Storage = storage
+		config.Trackers = []ho.Tracker{&durabilityChecker{t: t, path: path}}
+
+		result := ho.OptimizeObjective(config, objective, ranges...)
+
+		assert.Empty(t, result.Warnings)
+
+		// Everything is there once the database is reopened.
+		assert.NoError(t, storage.Close())
+
+		storage, err = Open(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		study, err := storage.LoadStudy(context.Background(), result.StudyID)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// Trials evaluated concurrently may be stored in another order.
+		assert.ElementsMatch(t, result.Records(), study.Trials)
+		assert.Equal(t, 12, study.Meta.InitialSamples)
+
+		if assert.NotNil(t, study.Best) && assert.NotNil(t, study.Best.Value) {
+			assert.Equal(t, result.BestTime, *study.Best.Value)
+		}
+
+		// Resuming appends to the study.
+		config.Iterations = 6
+		config.Storage = storage
+		config.Trackers = nil
+
+		resumed := ho.ResumeObjectiveFromStorage(result.StudyID, config, objective, ranges...)
+
+		assert.NoError(t, resumed.Err)
+		assert.Len(t, resumed.Trials, len(result.Trials)+2)
+
+		study, err = storage.LoadStudy(context.Background(), result.StudyID)
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, resumed.Records(), study.Trials)
+	})
+
+	t.Run("concurrent appends", func(t *testing.T) {
+		storage, err := Open(filepath.Join(t.TempDir(), "studies.db"))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		defer storage.Close()
+
+		ctx := context.Background()
+
+		ids := make([]string, 4)
+
+		for i := range ids {
+			ids[i], err = storage.CreateStudy(ctx, ho.StudyMeta{Iterations: i})
+
+			assert.NoError(t, err)
+		}
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			go func(worker int) {
+				defer wg.Done()
+
+				for j := 0; j < 25; j++ {
+					value := float64(j)
+
+					trial := ho.TrialRecord{
+						ID:     worker*100 + j,
+						Status: ho.TrialCompleted,
+						Value:  &value,
+						Params: map[string]float64{"worker": float64(worker)},
+					}
+
+					assert.NoError(t, storage.AppendTrial(ctx, ids[worker%len(ids)], trial))
+					assert.NoError(t, storage.UpdateBest(ctx, ids[worker%len(ids)], trial))
+				}
+			}(i)
+		}
+
+		wg.Wait()
+
+		studies, err := storage.ListStudies(ctx)
+
+		if assert.NoError(t, err) && assert.Len(t, studies, len(ids)) {
+			for i, study := range studies {
+				assert.Equal(t, ids[i], study.ID)
+				assert.Equal(t, i, study.Meta.Iterations)
+				assert.NotNil(t, study.Best)
+				assert.Nil(t, study.Trials)
+			}
+		}
+
+		for _, id := range ids {
+			study, err := storage.LoadStudy(ctx, id)
+
+			if assert.NoError(t, err) && assert.Len(t, study.Trials, 50) {
+				// Each worker's trials are in order.
+				last := map[float64]int{}
+
+				for _, trial := range study.Trials {
+					worker := trial.Params["worker"]
+
+					if previous, ok := last[worker]; ok {
+						assert.Greater(t, trial.ID, previous)
+					}
+
+					last[worker] = trial.ID
+				}
+			}
+		}
+	})
+
+	t.Run("unknown study", func(t *testing.T) {
+		storage, err := Open(filepath.Join(t.TempDir(), "studies.db"))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		defer storage.Close()
+
+		ctx := context.Background()
+
+		_, err = storage.LoadStudy(ctx, "missing")
+
+		assert.EqualError(t, err, `unknown study "missing"`)
+		assert.Error(t, storage.AppendTrial(ctx, "missing", ho.TrialRecord{}))
+		assert.EqualError(t, storage.UpdateBest(ctx, "missing", ho.TrialRecord{}), `unknown study "missing"`)
+	})
+
+	t.Run("path with spaces", func(t *testing.T) {
+		storage, err := Open(filepath.Join(t.TempDir(), fmt.Sprintf("my %s.db", "studies")))
+
+		if assert.NoError(t, err) {
+			assert.NoError(t, storage.Close())
+		}
+	})
+}
@@ -0,0 +1,311 @@
+package ho
+
+import (
+	"math"
+	"sort"
+)
+
+//////
+// Statistics helpers.
+//////
+
+// mean returns the arithmetic mean of the values, 0 if empty.
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+// sampleVariance returns the unbiased sample variance of the values, 0 if
+// there are less than two values.
+func sampleVariance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	m := mean(values)
+
+	var sum float64
+
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+
+	return sum / float64(len(values)-1)
+}
+
+// populationVariance returns the population variance of the values, 0 if
+// empty.
+func populationVariance(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	m := mean(values)
+
+	var sum float64
+
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+
+	return sum / float64(len(values))
+}
+
+// median returns the median of the values, 0 if empty.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
+
+// quantile returns the p-quantile of the values, interpolating linearly
+// between order statistics, 0 if empty.
+func quantile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+
+	sort.Float64s(sorted)
+
+	pos := p * float64(len(sorted)-1)
+
+	lower := int(math.Floor(pos))
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+
+	return sorted[lower] + (pos-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// welchTTest performs Welch's unequal variances t-test.
+//
+// Returns:
+// - t: The t statistic (positive if a's mean is greater than b's)
+// - df: Welch-Satterthwaite degrees of freedom
+// - p: Two-sided p-value.
+func welchTTest(a, b []float64) (t, df, p float64) {
+	varA := sampleVariance(a) / float64(len(a))
+
+	varB := sampleVariance(b) / float64(len(b))
+
+	se := math.Sqrt(varA + varB)
+
+	diff := mean(a) - mean(b)
+
+	if se == 0 {
+		if diff == 0 {
+			return 0, 1, 1
+		}
+
+		return math.Copysign(math.Inf(1), diff), 1, 0
+	}
+
+	t = diff / se
+
+	df = (varA + varB) * (varA + varB) /
+		(varA*varA/float64(len(a)-1) + varB*varB/float64(len(b)-1))
+
+	return t, df, studentTTwoSided(t, df)
+}
+
+// mannWhitneyU performs the Mann-Whitney U test, using the normal
+// approximation with tie correction.
+//
+// Returns:
+// - u: The U statistic of a
+// - p: Two-sided p-value.
+func mannWhitneyU(a, b []float64) (u, p float64) {
+	type sample struct {
+		value float64
+		fromA bool
+	}
+
+	samples := make([]sample, 0, len(a)+len(b))
+
+	for _, v := range a {
+		samples = append(samples, sample{value: v, fromA: true})
+	}
+
+	for _, v := range b {
+		samples = append(samples, sample{value: v})
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].value < samples[j].value })
+
+	// Assign average ranks to ties, and accumulate the tie correction term.
+	var rankSumA, ties float64
+
+	for i := 0; i < len(samples); {
+		j := i
+
+		for j < len(samples) && samples[j].value == samples[i].value {
+			j++
+		}
+
+		rank := float64(i+j+1) / 2
+
+		for k := i; k < j; k++ {
+			if samples[k].fromA {
+				rankSumA += rank
+			}
+		}
+
+		n := float64(j - i)
+
+		ties += n*n*n - n
+
+		i = j
+	}
+
+	n1 := float64(len(a))
+	n2 := float64(len(b))
+	n := n1 + n2
+
+	u = rankSumA - n1*(n1+1)/2
+
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+
+	if sigma == 0 {
+		return u, 1
+	}
+
+	z := (u - n1*n2/2) / sigma
+
+	return u, 2 * (1 - normalCDF(math.Abs(z)))
+}
+
+// studentTTwoSided returns the two-sided p-value of the t statistic under a
+// Student's t distribution with df degrees of freedom.
+func studentTTwoSided(t, df float64) float64 {
+	return regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// studentTQuantile returns the critical value q such that a Student's t
+// random variable with df degrees of freedom exceeds q in absolute value with
+// probability alpha.
+func studentTQuantile(alpha, df float64) float64 {
+	low, high := 0.0, 1e6
+
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+
+		if studentTTwoSided(mid, df) > alpha {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return (low + high) / 2
+}
+
+// regularizedIncompleteBeta computes I_x(a, b) using its continued fraction
+// representation (Numerical Recipes, betacf).
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	if x >= 1 {
+		return 1
+	}
+
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// Use the symmetry relation where the continued fraction converges fast.
+	if x > (a+1)/(a+b+2) {
+		return 1 - front*betaContinuedFraction(1-x, b, a)/b
+	}
+
+	return front * betaContinuedFraction(x, a, b) / a
+}
+
+// betaContinuedFraction evaluates the continued fraction of the incomplete
+// beta function using the modified Lentz's method.
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+
+		// Even step.
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+
+		d = 1 / d
+		h *= d * c
+
+		// Odd step.
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+
+	return h
+}
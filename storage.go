@@ -0,0 +1,452 @@
+package ho
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Storage durably records studies as they run, trial by trial, e.g. so
+// dashboards can query runs in flight, see OptimizationConfig.Storage. The
+// sqlitestorage subpackage provides a SQLite implementation, and
+// MemoryStorage an in-memory one.
+//
+// Important notes:
+// - Methods are called synchronously: a trial is only reported as ended
+// once AppendTrial returned, so each trial appended without error must be
+// durable
+// - Implementations must be safe for concurrent use: trials evaluated
+// concurrently are appended concurrently
+// - Failures are recorded in Result.Warnings, and don't stop the run
+// - Each study of an ask/tell Optimizer is stored as a study, see
+// ResumeOptimizerFromStorage.
+type Storage interface {
+	// CreateStudy creates a study, and returns its ID.
+	CreateStudy(ctx context.Context, meta StudyMeta) (string, error)
+
+	// AppendTrial records an ended trial of a study, skipped, failed, cached
+	// and canceled ones included.
+	AppendTrial(ctx context.Context, studyID string, trial TrialRecord) error
+
+	// UpdateBest records the new best trial of a study, after it was
+	// appended.
+	UpdateBest(ctx context.Context, studyID string, trial TrialRecord) error
+
+	// LoadStudy loads a study, with its trials in the order they were
+	// appended.
+	LoadStudy(ctx context.Context, studyID string) (*StoredStudy, error)
+
+	// ListStudies lists the studies, without their trials, in the order they
+	// were created.
+	ListStudies(ctx context.Context) ([]StoredStudy, error)
+}
+
+// StoredStudy is a study, as recorded by a Storage.
+type StoredStudy struct {
+	// ID identifies the study.
+	ID string `json:"id"`
+
+	// Meta describes the run.
+	Meta StudyMeta `json:"meta"`
+
+	// Best is the best trial, nil if none completed.
+	Best *TrialRecord `json:"best,omitempty"`
+
+	// Trials holds the ended trials, in order. Nil when listed.
+	Trials []TrialRecord `json:"trials,omitempty"`
+}
+
+// MemoryStorage is a Storage keeping studies in memory, e.g. for tests. It's
+// safe for concurrent use.
+type MemoryStorage struct {
+	// mu protects studies.
+	mu sync.Mutex
+
+	// studies holds the studies, in creation order.
+	studies []*StoredStudy
+}
+
+//////
+// Methods.
+//////
+
+// CreateStudy implements Storage. IDs are sequence numbers.
+func (m *MemoryStorage) CreateStudy(_ context.Context, meta StudyMeta) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	study := &StoredStudy{
+		ID:   strconv.Itoa(len(m.studies) + 1),
+		Meta: meta,
+	}
+
+	m.studies = append(m.studies, study)
+
+	return study.ID, nil
+}
+
+// AppendTrial implements Storage.
+func (m *MemoryStorage) AppendTrial(_ context.Context, studyID string, trial TrialRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	study, err := m.study(studyID)
+	if err != nil {
+		return err
+	}
+
+	study.Trials = append(study.Trials, trial)
+
+	return nil
+}
+
+// UpdateBest implements Storage.
+func (m *MemoryStorage) UpdateBest(_ context.Context, studyID string, trial TrialRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	study, err := m.study(studyID)
+	if err != nil {
+		return err
+	}
+
+	study.Best = &trial
+
+	return nil
+}
+
+// LoadStudy implements Storage.
+func (m *MemoryStorage) LoadStudy(_ context.Context, studyID string) (*StoredStudy, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	study, err := m.study(studyID)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := *study
+
+	loaded.Trials = append([]TrialRecord(nil), study.Trials...)
+
+	return &loaded, nil
+}
+
+// ListStudies implements Storage.
+func (m *MemoryStorage) ListStudies(_ context.Context) ([]StoredStudy, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	studies := make([]StoredStudy, len(m.studies))
+
+	for i, study := range m.studies {
+		studies[i] = *study
+		studies[i].Trials = nil
+	}
+
+	return studies, nil
+}
+
+// study returns the study with the given ID. The caller must hold mu.
+func (m *MemoryStorage) study(studyID string) (*StoredStudy, error) {
+	for _, study := range m.studies {
+		if study.ID == studyID {
+			return study, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown study %q", studyID)
+}
+
+// createStudy creates the study of the run in the Storage, unless it resumes
+// one. Failures are recorded as warnings, and the run isn't stored.
+func (o *optimizer[T]) createStudy() {
+	if o.config.Storage == nil || o.studyID != "" {
+		return
+	}
+
+	studyID, err := o.config.Storage.CreateStudy(context.WithoutCancel(o.ctx), o.studyMeta())
+	if err != nil {
+		o.warnf("study not stored: %v", err)
+
+		return
+	}
+
+	o.studyID = studyID
+}
+
+// storeTrial appends an ended trial to the study of the run, if stored.
+func (o *optimizer[T]) storeTrial(record TrialRecord) {
+	if o.studyID == "" {
+		return
+	}
+
+	// Trials canceled with the run are stored too.
+	if err := o.config.Storage.AppendTrial(context.WithoutCancel(o.ctx), o.studyID, record); err != nil {
+		o.warnf("trial %d not stored: %v", record.ID, err)
+	}
+}
+
+// storeBest records a new best trial in the study of the run, if stored.
+// Concurrent trials may improve on each other before they're stored, so a
+// best already superseded isn't.
+func (o *optimizer[T]) storeBest(record TrialRecord) {
+	if o.studyID == "" {
+		return
+	}
+
+	o.storeBestMu.Lock()
+	defer o.storeBestMu.Unlock()
+
+	o.mu.Lock()
+	superseded := record.Value == nil || *record.Value != o.bestTime
+	o.mu.Unlock()
+
+	if superseded {
+		return
+	}
+
+	if err := o.config.Storage.UpdateBest(context.WithoutCancel(o.ctx), o.studyID, record); err != nil {
+		o.warnf("best trial %d not stored: %v", record.ID, err)
+	}
+}
+
+// resumeStudy runs the optimization from the state of a stored study,
+// appending to it.
+//
+// Parameters:
+// - studyID: The study, in OptimizationConfig.Storage
+//
+// Returns:
+// - *Result[T]: The outcome of the run, trials of the study included.
+func (o *optimizer[T]) resumeStudy(studyID string) *Result[T] {
+	if o.config.Storage == nil {
+		o.invalidErr = fmt.Errorf("%w: resuming a study requires Storage", ErrInvalidConfig)
+
+		return o.result()
+	}
+
+	study, err := o.config.Storage.LoadStudy(o.ctx, studyID)
+	if err != nil {
+		o.invalidErr = fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+
+		return o.result()
+	}
+
+	state, err := o.studyCheckpoint(study)
+	if err != nil {
+		o.invalidErr = err
+
+		return o.result()
+	}
+
+	o.resumed = state
+	o.studyID = studyID
+
+	return o.run()
+}
+
+// studyCheckpoint converts a stored study to a checkpoint to resume from.
+// Stored trials don't carry the state of the random number generator, which
+// is left as it is, nor the penalties of failed trials, which are restored.
+//
+// Parameters:
+// - study: The stored study
+//
+// Returns:
+// - *checkpointFile: The checkpoint
+// - error: Wrapping ErrInvalidConfig if a trial lacks parameters.
+func (o *optimizer[T]) studyCheckpoint(study *StoredStudy) (*checkpointFile, error) {
+	state := &checkpointFile{
+		Version:     checkpointVersion,
+		Fingerprint: fingerprint(study.Meta),
+		Parameters:  study.Meta.Parameters,
+		Seed:        o.source.seed,
+		Draws:       o.source.draws,
+		BestValue:   math.MaxFloat64,
+		Trials:      make([]checkpointTrial, len(study.Trials)),
+	}
+
+	// Observations are at the points the model sees, see modelPoint.
+	for _, observation := range o.config.WarmStart {
+		state.Observations = append(state.Observations, checkpointObservation{
+			Params: modelCoordinates(o.hypers, observation.Params),
+			Value:  checkpointFloat(observation.Value),
+			Weight: observation.Weight,
+		})
+	}
+
+	for i, record := range study.Trials {
+		trial := checkpointTrial{
+			ID:              record.ID,
+			Phase:           record.Phase,
+			Iteration:       record.Iteration,
+			Retry:           record.Retry,
+			Seed:            record.Seed,
+			RNGPosition:     record.RNGPosition,
+			Status:          record.Status,
+			Cached:          record.Cached,
+			UnderLoad:       record.UnderLoad,
+			Surprising:      record.Surprising,
+			Measurements:    record.Measurements,
+			SeedValues:      record.SeedValues,
+			Weight:          record.Weight,
+			Duration:        time.Duration(record.DurationNS),
+			RateLimitWait:   time.Duration(record.RateLimitWaitNS),
+			DriftCorrection: record.DriftCorrection,
+			MeasuredValue:   record.MeasuredValue,
+			Error:           record.Error,
+			Stop:            record.Stop,
+		}
+
+		params := make([]float64, len(study.Meta.Parameters))
+
+		var benchmarkParams []float64
+
+		for j, spec := range study.Meta.Parameters {
+			v, ok := record.Params[spec.Name]
+			if !ok {
+				return nil, fmt.Errorf("%w: stored trial %d lacks parameter %q", ErrInvalidConfig, record.ID, spec.Name)
+			}
+
+			params[j] = v
+
+			if b, ok := record.BenchmarkParams[spec.Name]; ok {
+				if benchmarkParams == nil {
+					benchmarkParams = make([]float64, len(study.Meta.Parameters))
+				}
+
+				benchmarkParams[j] = b
+			}
+		}
+
+		trial.Params = checkpointValues(params)
+
+		if benchmarkParams != nil {
+			trial.BenchmarkParams = checkpointValues(benchmarkParams)
+		}
+
+		switch {
+		case record.Value != nil:
+			trial.Value = checkpointFloat(*record.Value)
+		case record.Status == TrialFailed || record.Status == TrialCanceled:
+			trial.Value = math.MaxFloat64 / 2
+		}
+
+		state.Trials[i] = trial
+		state.LastTrialID = max(state.LastTrialID, record.ID)
+
+		// Skipped, reused and canceled trials didn't reach the model, save
+		// for timeouts, which can't be told apart.
+		if record.Cached || record.Status == TrialSkipped || record.Status == TrialCanceled {
+			continue
+		}
+
+		state.Observations = append(state.Observations, checkpointObservation{Params: modelCoordinates(o.hypers, params), Value: trial.Value, Weight: trial.Weight})
+	}
+
+	if study.Best != nil {
+		best := make([]float64, len(study.Meta.Parameters))
+
+		for j, spec := range study.Meta.Parameters {
+			best[j] = study.Best.Params[spec.Name]
+		}
+
+		state.BestParams = checkpointValues(best)
+
+		if study.Best.Value != nil {
+			state.BestValue = checkpointFloat(*study.Best.Value)
+		}
+	}
+
+	return state, nil
+}
+
+//////
+// Helpers.
+//////
+
+// observationsOf converts completed stored trials to observations, with
+// parameters in the order of specs.
+func observationsOf(trials []TrialRecord, specs []ParameterSpec) ([]Observation, error) {
+	var observations []Observation
+
+	for _, record := range trials {
+		if record.Status != TrialCompleted || record.Value == nil || record.Cached {
+			continue
+		}
+
+		observation := Observation{Params: make([]float64, len(specs)), Value: *record.Value, Weight: record.Weight}
+
+		for i, spec := range specs {
+			v, ok := record.Params[spec.Name]
+			if !ok {
+				return nil, fmt.Errorf("%w: stored trial %d lacks parameter %q", ErrInvalidConfig, record.ID, spec.Name)
+			}
+
+			observation.Params[i] = v
+		}
+
+		observations = append(observations, observation)
+	}
+
+	return observations, nil
+}
+
+//////
+// Factory.
+//////
+
+// NewMemoryStorage creates a MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{}
+}
+
+// WarmStartFromStorage loads a stored study and converts its completed
+// trials to prior observations, for warm starting (see
+// OptimizationConfig.WarmStart), as ImportOptunaJSON does for Optuna studies.
+//
+// Parameters:
+// - ctx: Context of the Storage calls
+// - storage: The storage
+// - studyID: The study
+//
+// Returns:
+// - SearchSpace: The search space of the study. Use Ranges to get the
+// ParameterRange values to optimize
+// - []Observation: The completed trials, with parameters in search space
+// order. Reused (cached) trials are left out, as they repeat earlier ones
+// - error: Wrapping ErrInvalidConfig if the study can't be loaded or
+// converted.
+//
+// Usage example:
+//
+//	space, observations, err := WarmStartFromStorage(ctx, storage, studyID)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	config := DefaultConfig()
+//	config.WarmStart = observations
+//
+//	result := Optimize(config, benchmark, Ranges[int](space)...)
+func WarmStartFromStorage(ctx context.Context, storage Storage, studyID string) (SearchSpace, []Observation, error) {
+	study, err := storage.LoadStudy(ctx, studyID)
+	if err != nil {
+		return SearchSpace{}, nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	observations, err := observationsOf(study.Trials, study.Meta.Parameters)
+	if err != nil {
+		return SearchSpace{}, nil, err
+	}
+
+	return SearchSpace{Parameters: append([]ParameterSpec(nil), study.Meta.Parameters...)}, observations, nil
+}
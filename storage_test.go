@@ -0,0 +1,173 @@
+package ho
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStorage is a Storage whose every call fails.
+type failingStorage struct {
+	MemoryStorage
+}
+
+func (f *failingStorage) AppendTrial(context.Context, string, TrialRecord) error {
+	return errors.New("disk full")
+}
+
+func TestStorage(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Name: "x", Min: 0, Max: 10}, {Name: "y", Min: -5, Max: 5}}
+
+	objective := func(params ...float64) (float64, error) {
+		if params[0] > 9 {
+			return 0, errors.New("out of memory")
+		}
+
+		return math.Pow(params[0]-3, 2) + math.Pow(params[1]-1, 2), nil
+	}
+
+	t.Run("concurrent appends", func(t *testing.T) {
+		storage := NewMemoryStorage()
+
+		config := fastConfig()
+		config.InitialSamples = 16
+		config.MaxConcurrentEvaluations = 8
+		config.Storage = storage
+
+		result := OptimizeObjective(config, objective, ranges...)
+
+		assert.NotEmpty(t, result.StudyID)
+
+		study, err := storage.LoadStudy(context.Background(), result.StudyID)
+		if !assert.NoError(t, err) || !assert.Len(t, study.Trials, len(result.Trials)) {
+			return
+		}
+
+		// Trials evaluated concurrently may be stored in another order.
+		assert.ElementsMatch(t, result.Records(), study.Trials)
+		assert.Equal(t, 16, study.Meta.InitialSamples)
+		assert.Equal(t, "x", study.Meta.Parameters[0].Name)
+
+		if assert.NotNil(t, study.Best) && assert.NotNil(t, study.Best.Value) {
+			assert.Equal(t, result.BestTime, *study.Best.Value)
+			assert.Equal(t, map[string]float64{"x": result.BestParams[0], "y": result.BestParams[1]}, study.Best.Params)
+		}
+
+		studies, err := storage.ListStudies(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, studies, 1)
+		assert.Nil(t, studies[0].Trials)
+	})
+
+	t.Run("warm start", func(t *testing.T) {
+		storage := NewMemoryStorage()
+
+		config := fastConfig()
+		config.Storage = storage
+
+		result := OptimizeObjective(config, objective, ranges...)
+
+		space, observations, err := WarmStartFromStorage(context.Background(), storage, result.StudyID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"x", "y"}, []string{space.Parameters[0].Name, space.Parameters[1].Name})
+
+		var completed int
+
+		for _, trial := range result.Trials {
+			if trial.Status == TrialCompleted {
+				completed++
+			}
+		}
+
+		assert.Len(t, observations, completed)
+
+		_, _, err = WarmStartFromStorage(context.Background(), storage, "missing")
+
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		storage := NewMemoryStorage()
+
+		config := fastConfig()
+		config.Storage = storage
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var calls atomic.Int32
+
+		// The run dies during its 6th evaluation.
+		crashed := OptimizeObjectiveWithContext(ctx, config, func(ctx context.Context, params ...float64) (float64, error) {
+			if calls.Add(1) == 6 {
+				cancel()
+
+				return 0, ctx.Err()
+			}
+
+			return objective(params...)
+		}, ranges...)
+
+		assert.Len(t, crashed.Trials, 6)
+
+		var evaluations int
+
+		resumed := ResumeObjectiveFromStorage(crashed.StudyID, config, func(params ...float64) (float64, error) {
+			evaluations++
+
+			return objective(params...)
+		}, ranges...)
+
+		assert.NoError(t, resumed.Err)
+		assert.Equal(t, crashed.StudyID, resumed.StudyID)
+
+		// The canceled slot is evaluated again.
+		assert.Equal(t, config.InitialSamples+config.Iterations-5, evaluations)
+		assert.Len(t, resumed.Trials, 6+evaluations)
+		assert.Equal(t, crashed.Records()[:5], resumed.Records()[:5])
+		assert.LessOrEqual(t, resumed.BestTime, crashed.BestTime)
+
+		study, err := storage.LoadStudy(context.Background(), crashed.StudyID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, resumed.Records(), study.Trials)
+	})
+
+	t.Run("resume errors", func(t *testing.T) {
+		config := fastConfig()
+
+		result := ResumeObjectiveFromStorage("1", config, objective, ranges...)
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+
+		config.Storage = NewMemoryStorage()
+
+		result = ResumeObjectiveFromStorage("1", config, objective, ranges...)
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+		assert.ErrorContains(t, result.Err, `unknown study "1"`)
+
+		stored := OptimizeObjective(config, objective, ranges...)
+
+		result = ResumeObjectiveFromStorage(stored.StudyID, config, objective, ranges[0])
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+		assert.Empty(t, result.Trials)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		config := fastConfig()
+		config.Storage = &failingStorage{}
+
+		result := OptimizeObjective(config, objective, ranges...)
+
+		assert.Equal(t, TerminationCompleted, result.TerminationReason)
+		assert.Contains(t, result.Warnings, "trial 1 not stored: disk full")
+	})
+}
@@ -0,0 +1,356 @@
+package ho
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// structTag is the struct tag key declaring the search space of a field.
+const structTag = "ho"
+
+// durationType is the reflect type of time.Duration.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// fieldKind is the kind of a tunable struct field.
+type fieldKind int
+
+const (
+	fieldInt fieldKind = iota
+	fieldUint
+	fieldFloat
+	fieldBool
+	fieldDuration
+	fieldEnum
+)
+
+// structField is a tunable struct field, i.e. a dimension of the search space.
+type structField struct {
+	// index is the index of the field in the struct.
+	index int
+
+	// kind determines how parameter values are converted to the field type.
+	kind fieldKind
+
+	// values holds the choices of enum fields.
+	values []string
+}
+
+// structSpace is a search space built from the tags of a struct type.
+type structSpace struct {
+	// fields holds the tunable fields, one per range.
+	fields []structField
+
+	// ranges holds the search space, one range per field.
+	ranges []ParameterRange[float64]
+}
+
+//////
+// Methods.
+//////
+
+// fill returns a copy of template with the tunable fields set from params.
+// Integer fields are rounded to the nearest integer, boolean fields are true
+// if the value is at least 0.5, string fields are set to the chosen value.
+func (s *structSpace) fill(template reflect.Value, params []float64) reflect.Value {
+	v := reflect.New(template.Type()).Elem()
+
+	v.Set(template)
+
+	for i, field := range s.fields {
+		if field.kind == fieldEnum {
+			v.Field(field.index).SetString(enumValue(field.values, params[i]))
+
+			continue
+		}
+
+		// Field types were checked when parsing, and values are within the
+		// ranges, so setting can't fail.
+		_ = setField(v.Field(field.index), params[i])
+	}
+
+	return v
+}
+
+//////
+// Helpers.
+//////
+
+// parseStructSpace builds the search space from the `ho` tags of the fields
+// of the struct type t.
+//
+// Returns:
+// - *structSpace: The search space
+// - error: Wrapping ErrInvalidConfig if t isn't a struct, a tag is invalid,
+// or no field is tagged.
+func parseStructSpace(t reflect.Type) (*structSpace, error) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %v is not a struct", ErrInvalidConfig, t)
+	}
+
+	space := &structSpace{}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		tag, ok := sf.Tag.Lookup(structTag)
+
+		// Unexported and untagged fields are passed through.
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		field, hyper, err := parseStructField(sf, tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s: %w", ErrInvalidConfig, sf.Name, err)
+		}
+
+		field.index = i
+
+		space.fields = append(space.fields, field)
+		space.ranges = append(space.ranges, hyper)
+	}
+
+	if len(space.fields) == 0 {
+		return nil, fmt.Errorf("%w: %v has no field tagged %q", ErrInvalidConfig, t, structTag)
+	}
+
+	return space, nil
+}
+
+// parseStructField parses the tag of a struct field, in the form
+// "min=<value>,max=<value>[,step=<value>][,scale=linear|log]". Boolean
+// fields take no options, string fields take "values=<value>|<value>...".
+func parseStructField(sf reflect.StructField, tag string) (structField, ParameterRange[float64], error) {
+	var field structField
+
+	switch {
+	case sf.Type == durationType:
+		field.kind = fieldDuration
+	case sf.Type.Kind() == reflect.Bool:
+		field.kind = fieldBool
+	case sf.Type.Kind() == reflect.String:
+		field.kind = fieldEnum
+	case sf.Type.Kind() >= reflect.Int && sf.Type.Kind() <= reflect.Int64:
+		field.kind = fieldInt
+	case sf.Type.Kind() >= reflect.Uint && sf.Type.Kind() <= reflect.Uintptr:
+		field.kind = fieldUint
+	case sf.Type.Kind() == reflect.Float32 || sf.Type.Kind() == reflect.Float64:
+		field.kind = fieldFloat
+	default:
+		return field, ParameterRange[float64]{}, fmt.Errorf("unsupported type %v", sf.Type)
+	}
+
+	options := map[string]string{}
+
+	for _, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return field, ParameterRange[float64]{}, fmt.Errorf("invalid option %q, expected key=value", option)
+		}
+
+		switch key {
+		case "min", "max", "step", "scale", "values":
+		default:
+			return field, ParameterRange[float64]{}, fmt.Errorf("unknown option %q", key)
+		}
+
+		if _, dup := options[key]; dup {
+			return field, ParameterRange[float64]{}, fmt.Errorf("duplicate option %q", key)
+		}
+
+		options[key] = strings.TrimSpace(value)
+	}
+
+	if field.kind == fieldBool {
+		if len(options) > 0 {
+			return field, ParameterRange[float64]{}, fmt.Errorf("bool fields take no options")
+		}
+
+		return field, BoolParam{Name: sf.Name}.Range(), nil
+	}
+
+	if field.kind == fieldEnum {
+		values, ok := options["values"]
+		if !ok || len(options) > 1 {
+			return field, ParameterRange[float64]{}, fmt.Errorf("string fields take a single option, values")
+		}
+
+		field.values = strings.Split(values, "|")
+
+		if err := validateEnum(field.values); err != nil {
+			return field, ParameterRange[float64]{}, err
+		}
+
+		return field, EnumParam{Name: sf.Name, Values: field.values}.Range(), nil
+	}
+
+	if _, ok := options["values"]; ok {
+		return field, ParameterRange[float64]{}, fmt.Errorf("only string fields take values")
+	}
+
+	hyper := ParameterRange[float64]{Name: sf.Name}
+
+	for key, bound := range map[string]*float64{"min": &hyper.Min, "max": &hyper.Max} {
+		value, ok := options[key]
+		if !ok {
+			return field, hyper, fmt.Errorf("missing option %q", key)
+		}
+
+		v, err := parseStructValue(field.kind, value)
+		if err != nil {
+			return field, hyper, fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+
+		*bound = v
+	}
+
+	if hyper.Min > hyper.Max {
+		return field, hyper, fmt.Errorf("min %v is greater than max %v", options["min"], options["max"])
+	}
+
+	if field.kind == fieldUint && hyper.Min < 0 {
+		return field, hyper, fmt.Errorf("min %v is negative for an unsigned field", options["min"])
+	}
+
+	if value, ok := options["step"]; ok {
+		step, err := parseStructValue(field.kind, value)
+
+		switch {
+		case err != nil:
+			return field, hyper, fmt.Errorf("invalid step %q: %w", value, err)
+		case step < 0:
+			return field, hyper, fmt.Errorf("step %v is negative", value)
+		}
+
+		hyper.Step = step
+	}
+
+	// Durations are whole numbers of nanoseconds, rendered as durations.
+	if field.kind == fieldDuration {
+		hyper.Type = DurationParameter
+		hyper.Step = math.Max(hyper.Step, 1)
+	}
+
+	switch options["scale"] {
+	case "", "linear":
+	case "log":
+		if hyper.Min <= 0 {
+			return field, hyper, fmt.Errorf("log scale requires a positive min, got %v", options["min"])
+		}
+
+		hyper.Prior = LogUniform()
+	default:
+		return field, hyper, fmt.Errorf("unknown scale %q, expected linear or log", options["scale"])
+	}
+
+	return field, hyper, nil
+}
+
+// parseStructValue parses a min or max tag value: a duration such as "10ms"
+// for time.Duration fields, a number otherwise.
+func parseStructValue(kind fieldKind, value string) (float64, error) {
+	if kind == fieldDuration {
+		d, err := time.ParseDuration(value)
+
+		return float64(d), err
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+//////
+// Exported functionalities.
+//////
+
+// OptimizeStruct optimizes the fields of a configuration struct. The search
+// space is built from the `ho` tags of its fields, and each trial benchmarks a
+// copy of the template with the tagged fields filled in.
+//
+// Type Parameter:
+//   - S: The configuration struct type
+//
+// Parameters:
+// - config: OptimizationConfig controlling the optimization process
+// - benchmarkFunc: The function whose configuration you want to optimize
+// - template: Configuration holding the values of the untagged fields
+//
+// Returns:
+// - S: Copy of template filled with the best values found, or template if no
+// trial completed
+// - *Result[float64]: The outcome of the run, with one parameter per tagged
+// field, in declaration order, named after the field
+//
+// Usage example:
+//
+//	type ServerConfig struct {
+//	    Workers      int           `ho:"min=1,max=32"`
+//	    LearningRate float64       `ho:"min=0.001,max=0.1,scale=log"`
+//	    Timeout      time.Duration `ho:"min=10ms,max=1s,step=10ms"`
+//	    Compression  bool          `ho:""`
+//	    Codec        string        `ho:"values=zstd|lz4|none"`
+//	    Addr         string        // Passed through from the template.
+//	}
+//
+//	best, result := OptimizeStruct(DefaultConfig(), func(cfg ServerConfig) error {
+//	    return runWorkload(cfg)
+//	}, ServerConfig{Addr: ":8080"})
+//
+// Tags:
+// - min, max: Inclusive bounds, required for all but bool and string
+// fields. Durations are written like "10ms"
+// - step: Optional granularity, values are min plus a multiple of step, see
+// ParameterRange.Step
+// - scale: "linear" (default) or "log", which samples every order of
+// magnitude equally and requires a positive min
+// - Bool fields take no options, use `ho:""`
+// - values: The choices of string fields, separated by "|", see EnumParam
+// - `ho:"-"`, untagged and unexported fields are passed through from template
+//
+// Important notes:
+// - Supported field types are signed and unsigned integers, floats, bool,
+// string and time.Duration
+// - Integer fields are rounded, bool fields are true for values >= 0.5
+// - Invalid tags fail the run with ErrInvalidConfig, before any benchmark
+// invocation.
+func OptimizeStruct[S any](
+	config OptimizationConfig,
+	benchmarkFunc func(cfg S) error,
+	template S,
+) (S, *Result[float64]) {
+	space, err := parseStructSpace(reflect.TypeOf(template))
+	if err != nil {
+		o := newOptimizer[float64](context.Background(), config, nil)
+
+		o.invalidErr = err
+
+		return template, o.result()
+	}
+
+	templateValue := reflect.ValueOf(template)
+
+	result := Optimize(config, func(params ...float64) error {
+		return benchmarkFunc(space.fill(templateValue, params).Interface().(S))
+	}, space.ranges...)
+
+	result.structType = reflect.TypeOf(template)
+
+	if result.BestTime == math.MaxFloat64 {
+		return template, result
+	}
+
+	return space.fill(templateValue, result.BestParams).Interface().(S), result
+}
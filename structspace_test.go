@@ -0,0 +1,155 @@
+package ho
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tunables struct {
+	Workers      int           `ho:"min=1,max=32"`
+	Batch        uint16        `ho:"min=8, max=64"`
+	LearningRate float64       `ho:"min=0.001,max=0.1,scale=log"`
+	Timeout      time.Duration `ho:"min=10ms,max=1s"`
+	Compression  bool          `ho:""`
+	Addr         string
+	Retries      int `ho:"-"`
+	secret       int `ho:"min=1,max=2"`
+}
+
+func TestParseStructSpace(t *testing.T) {
+	space, err := parseStructSpace(reflect.TypeOf(tunables{}))
+	assert.NoError(t, err)
+
+	assert.Len(t, space.ranges, 5)
+	assert.Equal(t, ParameterRange[float64]{Min: 1, Max: 32, Name: "Workers"}, space.ranges[0])
+	assert.Equal(t, ParameterRange[float64]{Min: 8, Max: 64, Name: "Batch"}, space.ranges[1])
+	assert.Equal(t, LogUniform(), space.ranges[2].Prior)
+	assert.Equal(t, ParameterRange[float64]{Min: float64(10 * time.Millisecond), Max: float64(time.Second), Name: "Timeout", Step: 1, Type: DurationParameter}, space.ranges[3])
+	assert.Equal(t, BoolParam{Name: "Compression"}.Range(), space.ranges[4])
+
+	template := tunables{Addr: ":8080", Retries: 3, secret: 7}
+
+	filled := space.fill(reflect.ValueOf(template), []float64{4.6, 8, 0.01, 2.5e7, 0.7}).Interface().(tunables)
+
+	assert.Equal(t, tunables{
+		Workers:      5,
+		Batch:        8,
+		LearningRate: 0.01,
+		Timeout:      25 * time.Millisecond,
+		Compression:  true,
+		Addr:         ":8080",
+		Retries:      3,
+		secret:       7,
+	}, filled)
+}
+
+func TestParseStructSpaceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  any
+	}{
+		{name: "not a struct", typ: 1},
+		{name: "no tagged field", typ: struct{ A int }{}},
+		{name: "missing max", typ: struct {
+			A int `ho:"min=1"`
+		}{}},
+		{name: "invalid number", typ: struct {
+			A int `ho:"min=one,max=2"`
+		}{}},
+		{name: "invalid duration", typ: struct {
+			A time.Duration `ho:"min=1,max=2s"`
+		}{}},
+		{name: "min greater than max", typ: struct {
+			A float64 `ho:"min=2,max=1"`
+		}{}},
+		{name: "unknown option", typ: struct {
+			A int `ho:"min=1,max=2,stride=1"`
+		}{}},
+		{name: "duplicate option", typ: struct {
+			A int `ho:"min=1,max=2,min=0"`
+		}{}},
+		{name: "not key value", typ: struct {
+			A int `ho:"min=1,max"`
+		}{}},
+		{name: "unknown scale", typ: struct {
+			A float64 `ho:"min=1,max=2,scale=exp"`
+		}{}},
+		{name: "log scale non-positive min", typ: struct {
+			A float64 `ho:"min=0,max=2,scale=log"`
+		}{}},
+		{name: "negative unsigned", typ: struct {
+			A uint `ho:"min=-1,max=2"`
+		}{}},
+		{name: "bool with options", typ: struct {
+			A bool `ho:"min=0,max=1"`
+		}{}},
+		{name: "unsupported type", typ: struct {
+			A []int `ho:"min=0,max=1"`
+		}{}},
+		{name: "string with bounds", typ: struct {
+			A string `ho:"min=0,max=1"`
+		}{}},
+		{name: "duplicate values", typ: struct {
+			A string `ho:"values=zstd|lz4|zstd"`
+		}{}},
+		{name: "values of a number", typ: struct {
+			A int `ho:"min=0,max=1,values=a|b"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseStructSpace(reflect.TypeOf(tt.typ))
+
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+}
+
+func TestOptimizeStruct(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		calls := 0
+
+		best, result := OptimizeStruct(fastConfig(), func(cfg tunables) error {
+			calls++
+
+			assert.Equal(t, ":8080", cfg.Addr)
+			assert.Equal(t, 7, cfg.secret)
+			assert.GreaterOrEqual(t, cfg.Workers, 1)
+			assert.LessOrEqual(t, cfg.Workers, 32)
+			assert.GreaterOrEqual(t, cfg.LearningRate, 0.001)
+			assert.LessOrEqual(t, cfg.LearningRate, 0.1)
+			assert.GreaterOrEqual(t, cfg.Timeout, 10*time.Millisecond)
+			assert.LessOrEqual(t, cfg.Timeout, time.Second)
+
+			return nil
+		}, tunables{Addr: ":8080", secret: 7})
+
+		assert.NoError(t, result.Err)
+		assert.Equal(t, calls, len(result.Trials))
+		assert.Len(t, result.BestParams, 5)
+		assert.Equal(t, ":8080", best.Addr)
+		assert.Equal(t, int(result.BestParams[0]+0.5), best.Workers)
+	})
+
+	t.Run("invalid tags", func(t *testing.T) {
+		template := struct {
+			A int `ho:"min=1"`
+		}{A: 3}
+
+		best, result := OptimizeStruct(fastConfig(), func(cfg struct {
+			A int `ho:"min=1"`
+		}) error {
+			t.Fatal("benchmark must not be invoked")
+
+			return nil
+		}, template)
+
+		assert.Equal(t, template, best)
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+		assert.Equal(t, TerminationInvalidConfig, result.TerminationReason)
+	})
+}
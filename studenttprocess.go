@@ -0,0 +1,320 @@
+package ho
+
+import (
+	"math"
+	"sync"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultNu is the default degrees of freedom of a StudentTProcess.
+	defaultNu = 4
+
+	// studentTIterations is the number of expectation-maximization iterations
+	// reweighting the observations of a StudentTProcess.
+	studentTIterations = 10
+
+	// madToStdDev scales the median absolute deviation of normal residuals to
+	// their standard deviation.
+	madToStdDev = 1.4826
+)
+
+// StudentTProcessOptions configures a StudentTProcess.
+type StudentTProcessOptions struct {
+	// Nu is the degrees of freedom ν of the Student-t distribution of the
+	// observations around the model. Lower values are more tolerant of
+	// outliers, and the model approaches the Gaussian process as Nu grows.
+	// If not greater than 2, i.e. if the variance would be infinite, 4 is used.
+	Nu float64
+}
+
+// StudentTProcess is a SurrogateModel for heavy-tailed objectives, e.g.
+// execution times with occasional extreme measurements. It shares the kernel
+// and posterior of the Gaussian process, but assumes observations are
+// Student-t distributed around the model: each observation is weighted by how
+// well its neighbors explain it, and the posterior is fitted to observations
+// shrunk toward their neighbors by one minus their weight. A single extreme
+// measurement barely moves predictions, where the Gaussian process
+// interpolates it.
+//
+// Important notes:
+// - Weights are fitted by expectation-maximization on every Update, from
+// the leave-one-out residuals against neighboring observations, scaled by
+// their median absolute deviation. Observations out of reach of the kernel
+// of all others can't be judged, and keep full weight
+// - Predictions are t-distributed. Predict returns their variance, i.e.
+// the squared scale inflated by ν'/(ν'-2), ν' = ν + n, so acquisition
+// functions consuming mean and variance as for a Gaussian see the wider
+// tails. The scale also grows with the size of the residuals relative to ν
+// and n, so the model is less confident when the data is heavy-tailed
+// - Update is O(n²), as for the Gaussian process, times the number of
+// expectation-maximization iterations. Kernel values between observations
+// are cached, so each Update only evaluates the kernel n times.
+//
+// Thread safety:
+// - All methods are safe for concurrent use.
+type StudentTProcess struct {
+	// mu protects the fields below.
+	mu sync.RWMutex
+
+	// nu is the degrees of freedom.
+	nu float64
+
+	// gp holds the observations, the kernel, and the Cholesky factor of the
+	// kernel matrix.
+	gp *gaussianProcess
+
+	// weights holds the weight of each observation, in (0, 1]: about 1 for
+	// observations in line with their neighbors, and close to 0 for
+	// outliers.
+	weights []float64
+
+	// prior is the prior mean, the mean of the shrunk observations.
+	prior float64
+
+	// scale is the prior variance, the variance of the shrunk observations.
+	scale float64
+
+	// whitened holds L^-1 times the centered shrunk observations, L being
+	// the Cholesky factor of the kernel matrix.
+	whitened []float64
+
+	// beta is the sum of the squared standardized residuals.
+	beta float64
+
+	// kernel caches the kernel values between observations: row i holds
+	// those between observation i and the observations before it. Rows are
+	// never modified once added.
+	kernel [][]float64
+}
+
+//////
+// Methods.
+//////
+
+// Update implements SurrogateModel. It refits the weights of all
+// observations.
+func (t *StudentTProcess) Update(x []float64, y float64) error {
+	return t.UpdateWeighted(x, y, 1)
+}
+
+// UpdateWeighted implements WeightedUpdater: the weight scales the noise of
+// the observation in the underlying Gaussian process, on top of the weight
+// refitted from its residual.
+func (t *StudentTProcess) UpdateWeighted(x []float64, y, weight float64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.gp.UpdateWeighted(x, y, weight); err != nil {
+		return err
+	}
+
+	row := make([]float64, len(t.kernel))
+
+	for j := range row {
+		row[j] = t.gp.kernel(x, t.gp.X[j])
+	}
+
+	t.kernel = append(t.kernel, row)
+
+	t.fit()
+
+	return nil
+}
+
+// Predict implements SurrogateModel. It returns (0, 1) until the first
+// observation, as the Gaussian process does.
+func (t *StudentTProcess) Predict(x []float64) (mean, variance float64) {
+	means, variances := t.PredictBatch([][]float64{x})
+
+	return means[0], variances[0]
+}
+
+// PredictBatch implements BatchPredictor.
+func (t *StudentTProcess) PredictBatch(points [][]float64) (means, variances []float64) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	// Mismatched points are predicted as NaN.
+	means, variances, _ = t.gp.posterior(points, t.prior, t.scale, t.whitened)
+
+	n := len(t.gp.X)
+
+	if n == 0 {
+		return means, variances
+	}
+
+	dof := t.nu + float64(n)
+
+	for i, variance := range variances {
+		variance = variance * (t.nu + t.beta - 2) / (dof - 2)
+
+		variances[i] = variance * dof / (dof - 2)
+	}
+
+	return means, variances
+}
+
+// Points implements SurrogateModel.
+func (t *StudentTProcess) Points() [][]float64 {
+	return t.gp.Points()
+}
+
+// Len implements SurrogateModel.
+func (t *StudentTProcess) Len() int {
+	return t.gp.Len()
+}
+
+// Clone implements SurrogateModel.
+func (t *StudentTProcess) Clone() SurrogateModel {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return &StudentTProcess{
+		nu:       t.nu,
+		gp:       t.gp.snapshot(),
+		weights:  append([]float64(nil), t.weights...),
+		prior:    t.prior,
+		scale:    t.scale,
+		whitened: append([]float64(nil), t.whitened...),
+		beta:     t.beta,
+		kernel:   append([][]float64(nil), t.kernel...),
+	}
+}
+
+// fit refits the weights, the shrunk observations, and beta. The caller must
+// hold the write lock.
+func (t *StudentTProcess) fit() {
+	n := len(t.gp.X)
+
+	t.weights = make([]float64, n)
+	t.beta = float64(n)
+
+	for i := range t.weights {
+		t.weights[i] = 1
+	}
+
+	residuals := make([]float64, n)
+
+	// Telling outliers apart takes a few observations.
+	if n >= 3 {
+		t.reweight(residuals)
+	}
+
+	shrunk := make([]float64, n)
+
+	for i, y := range t.gp.Y {
+		shrunk[i] = y - (1-t.weights[i])*residuals[i]
+	}
+
+	t.prior, t.scale = priorMean(shrunk), priorScale(shrunk)
+
+	for i := range shrunk {
+		shrunk[i] -= t.prior
+	}
+
+	t.whitened = t.gp.chol.solve([][]float64{shrunk})[0]
+}
+
+// reweight fits the weights and beta by expectation-maximization, leaving the
+// last residuals in residuals. The caller must hold the write lock.
+func (t *StudentTProcess) reweight(residuals []float64) {
+	n := len(t.gp.X)
+
+	deviations := make([]float64, n)
+
+	for iteration := 0; iteration < studentTIterations; iteration++ {
+		for i := range residuals {
+			// Leave-one-out kernel regression on the neighbors.
+			var sum, sumWeights float64
+
+			for j, k := range t.kernel[i] {
+				sum += k * t.weights[j] * t.gp.Y[j]
+				sumWeights += k * t.weights[j]
+			}
+
+			for j := i + 1; j < n; j++ {
+				k := t.kernel[j][i]
+
+				sum += k * t.weights[j] * t.gp.Y[j]
+				sumWeights += k * t.weights[j]
+			}
+
+			residuals[i] = 0
+
+			if sumWeights > 1e-12 {
+				residuals[i] = t.gp.Y[i] - sum/sumWeights
+			}
+
+			deviations[i] = math.Abs(residuals[i])
+		}
+
+		scale := madToStdDev * median(deviations)
+
+		// Most observations are explained perfectly, fall back to the root
+		// mean square.
+		if scale == 0 {
+			var sumSquares float64
+
+			for _, r := range residuals {
+				sumSquares += r * r
+			}
+
+			scale = math.Sqrt(sumSquares / float64(n))
+		}
+
+		// Neighbors explain every observation perfectly.
+		if scale == 0 {
+			return
+		}
+
+		t.beta = 0
+
+		for i, r := range residuals {
+			z := r / scale
+
+			// The expected precision of a Student-t observation, (ν+1)/(ν+z²),
+			// normalized to 1 for z = 0.
+			t.weights[i] = t.nu / (t.nu + z*z)
+			t.beta += z * z
+		}
+	}
+}
+
+//////
+// Factory.
+//////
+
+// NewStudentTProcess creates a StudentTProcess.
+//
+// Parameters:
+// - options: Configures the model, zero values select the defaults
+//
+// Returns:
+// - *StudentTProcess: The model, without observations.
+//
+// Usage example:
+//
+//	config := DefaultConfig()
+//	config.Surrogate = func() SurrogateModel {
+//	    return NewStudentTProcess(StudentTProcessOptions{Nu: 3})
+//	}
+func NewStudentTProcess(options StudentTProcessOptions) *StudentTProcess {
+	nu := options.Nu
+	if nu <= 2 {
+		nu = defaultNu
+	}
+
+	// Observations are weighted one by one, so repeated ones aren't merged.
+	gp := newGaussianProcess()
+
+	gp.options.AppendDuplicates = true
+
+	return &StudentTProcess{
+		nu: nu,
+		gp: gp,
+	}
+}
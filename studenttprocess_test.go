@@ -0,0 +1,141 @@
+package ho
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// noisyLine updates the model with a noisy line over [0, 10).
+func noisyLine(model SurrogateModel) {
+	for x := 0.0; x < 10; x++ {
+		model.Update([]float64{x}, 10+x+0.3*math.Sin(7*x))
+	}
+}
+
+func TestStudentTProcessOutlier(t *testing.T) {
+	gp := newGaussianProcess()
+	tp := NewStudentTProcess(StudentTProcessOptions{})
+
+	noisyLine(gp)
+	noisyLine(tp)
+
+	queries := []float64{2.5, 4.5, 6.5}
+
+	gpBefore := make([]float64, len(queries))
+	tpBefore := make([]float64, len(queries))
+
+	for i, q := range queries {
+		gpBefore[i], _ = gp.Predict([]float64{q})
+		tpBefore[i], _ = tp.Predict([]float64{q})
+	}
+
+	_, gpVariance := gp.Predict([]float64{-1})
+	_, tpVariance := tp.Predict([]float64{-1})
+
+	inflation := tpVariance / gpVariance
+
+	// A single extreme measurement.
+	gp.Update([]float64{4.5}, 1000)
+	tp.Update([]float64{4.5}, 1000)
+
+	for i, q := range queries {
+		gpAfter, _ := gp.Predict([]float64{q})
+		tpAfter, _ := tp.Predict([]float64{q})
+
+		assert.Less(t, math.Abs(tpAfter-tpBefore[i]), math.Abs(gpAfter-gpBefore[i])/10, "at %v", q)
+	}
+
+	// The model is less confident in heavy-tailed data.
+	_, gpVariance = gp.Predict([]float64{-1})
+	_, tpVariance = tp.Predict([]float64{-1})
+
+	assert.Greater(t, tpVariance/gpVariance, inflation)
+}
+
+func TestStudentTProcess(t *testing.T) {
+	tp := NewStudentTProcess(StudentTProcessOptions{Nu: 1})
+
+	assert.Equal(t, float64(defaultNu), tp.nu)
+
+	mean, variance := tp.Predict([]float64{1})
+
+	assert.Equal(t, 0.0, mean)
+	assert.Equal(t, 1.0, variance)
+
+	gp := newGaussianProcess()
+
+	noisyLine(gp)
+	noisyLine(tp)
+
+	assert.Equal(t, 10, tp.Len())
+	assert.Equal(t, gp.Points(), tp.Points())
+
+	// The variance is inflated by the t-distribution.
+	_, gpVariance := gp.Predict([]float64{-1})
+	tpMean, tpVariance := tp.Predict([]float64{-1})
+
+	assert.Greater(t, tpVariance, gpVariance)
+
+	// As Nu grows, the model approaches the Gaussian process.
+	gaussian := NewStudentTProcess(StudentTProcessOptions{Nu: 1e9})
+
+	noisyLine(gaussian)
+
+	for _, q := range []float64{-1, 4.2, 12} {
+		gpMean, gpVariance := gp.Predict([]float64{q})
+		mean, variance := gaussian.Predict([]float64{q})
+
+		assert.InDelta(t, gpMean, mean, 1e-6)
+		assert.InEpsilon(t, gpVariance, variance, 1e-6)
+	}
+
+	// Clones are independent.
+	clone := tp.Clone()
+
+	clone.Update([]float64{4.2}, 1000)
+
+	unchanged, _ := tp.Predict([]float64{-1})
+
+	assert.Equal(t, tpMean, unchanged)
+	assert.Equal(t, 11, clone.Len())
+}
+
+func TestStudentTProcessConvergence(t *testing.T) {
+	config := fastConfig()
+	config.InitialSamples = 8
+	config.Iterations = 30
+	config.NumCandidates = 50
+	config.AcquisitionFunc = ExpectedImprovement
+	config.Surrogate = func() SurrogateModel { return NewStudentTProcess(StudentTProcessOptions{}) }
+
+	tp := meanBestDiscrete(config, 8)
+
+	config.Iterations = 0
+
+	random := meanBestDiscrete(config, 8)
+
+	assert.Less(t, tp, random)
+}
+
+func TestStudentTProcessKernelCache(t *testing.T) {
+	tp := NewStudentTProcess(StudentTProcessOptions{})
+
+	noisyLine(tp)
+
+	clone := tp.Clone().(*StudentTProcess)
+
+	clone.Update([]float64{4.5}, 1000)
+
+	assert.Len(t, tp.kernel, 10)
+	assert.Len(t, clone.kernel, 11)
+
+	for i, row := range clone.kernel {
+		assert.Len(t, row, i)
+
+		for j, k := range row {
+			assert.Equal(t, rbfKernel(clone.gp.X[i], clone.gp.X[j], 1), k)
+		}
+	}
+}
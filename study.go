@@ -0,0 +1,356 @@
+package ho
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Study groups related runs, e.g. the tuning of a service across
+// environments and releases, so they can be compared and new runs warm
+// started from the trials of earlier ones, see OptimizationConfig.Study.
+//
+// Important notes:
+// - Each run is stored as a study of its own in Storage (see StoredStudy),
+// labeled with the name of the Study in StudyMeta.Study. Runs of a Study are
+// those labeled with its name, so names must be unique within a Storage
+// - Runs must optimize Space: parameter names and bounds must match.
+//
+// Usage example:
+//
+//	study := &Study{
+//	    Name:    "cache-tuning",
+//	    Space:   space,
+//	    Tags:    map[string]string{"service": "api"},
+//	    Storage: storage,
+//	}
+//
+//	config := DefaultConfig()
+//	config.Study = study
+//	config.Tags = map[string]string{"env": "staging"}
+//	config.WarmStart, _ = study.WarmStart(ctx)
+//
+//	result := Optimize(config, benchmark, Ranges[int](study.Space)...)
+type Study struct {
+	// Name identifies the study within Storage.
+	Name string
+
+	// Space is the search space of the runs.
+	Space SearchSpace
+
+	// Tags describe the study, e.g. the service tuned, and label each run
+	// along with OptimizationConfig.Tags.
+	Tags map[string]string
+
+	// Storage records the runs, unless OptimizationConfig.Storage is set.
+	Storage Storage
+}
+
+// Run is a run of a Study, as recorded by its Storage.
+type Run StoredStudy
+
+// StudyReport compares the runs of a study, see Study.Compare.
+type StudyReport struct {
+	// Study is the name of the study.
+	Study string `json:"study"`
+
+	// Runs holds the runs, in the order they were created.
+	Runs []RunReport `json:"runs"`
+
+	// Best is the index in Runs of the run with the best trial, -1 if no
+	// trial completed.
+	Best int `json:"best"`
+
+	// Min, Median and Max aggregate the curves of the runs: element i is
+	// taken over the runs with more than i completed trials.
+	Min    []float64 `json:"min"`
+	Median []float64 `json:"median"`
+	Max    []float64 `json:"max"`
+}
+
+// RunReport summarizes a run of a study, see StudyReport.
+type RunReport struct {
+	// ID identifies the run in Storage.
+	ID string `json:"id"`
+
+	// StartedAt is when the run started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// Tags label the run, see StudyMeta.Tags.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Trials is the number of ended trials.
+	Trials int `json:"trials"`
+
+	// Best is the best trial, nil if none completed.
+	Best *TrialRecord `json:"best,omitempty"`
+
+	// Curve holds the best value after each completed trial, reused
+	// (cached) ones left out.
+	Curve []float64 `json:"curve"`
+}
+
+// studyDocument is the layout of Study.ExportJSON documents.
+type studyDocument struct {
+	Name  string            `json:"name"`
+	Space SearchSpace       `json:"space"`
+	Tags  map[string]string `json:"tags,omitempty"`
+	Runs  []Run             `json:"runs"`
+}
+
+//////
+// Methods.
+//////
+
+// Runs loads the runs of the study, with their trials, in the order they were
+// created.
+//
+// Parameters:
+// - ctx: Context of the Storage calls
+//
+// Returns:
+// - []Run: The runs
+// - error: Wrapping ErrInvalidConfig if the study has no Storage, or the
+// error of the Storage.
+func (s *Study) Runs(ctx context.Context) ([]Run, error) {
+	if s.Storage == nil {
+		return nil, fmt.Errorf("%w: study %q has no Storage", ErrInvalidConfig, s.Name)
+	}
+
+	stored, err := s.Storage.ListStudies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []Run
+
+	for _, study := range stored {
+		if study.Meta.Study != s.Name {
+			continue
+		}
+
+		loaded, err := s.Storage.LoadStudy(ctx, study.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, Run(*loaded))
+	}
+
+	return runs, nil
+}
+
+// WarmStart converts the completed trials of all runs of the study to prior
+// observations, to warm start a new run (see OptimizationConfig.WarmStart).
+//
+// Parameters:
+// - ctx: Context of the Storage calls
+//
+// Returns:
+// - []Observation: The completed trials, with parameters in Space order.
+// Reused (cached) trials are left out, as they repeat earlier ones
+// - error: Wrapping ErrInvalidConfig if the study has no Storage or a trial
+// lacks a parameter of Space, or the error of the Storage.
+func (s *Study) WarmStart(ctx context.Context) ([]Observation, error) {
+	runs, err := s.Runs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var observations []Observation
+
+	for _, run := range runs {
+		runObservations, err := observationsOf(run.Trials, s.Space.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("run %s: %w", run.ID, err)
+		}
+
+		observations = append(observations, runObservations...)
+	}
+
+	return observations, nil
+}
+
+// Compare compares the runs of the study: their best trials, and how fast
+// they got there.
+//
+// Parameters:
+// - ctx: Context of the Storage calls
+//
+// Returns:
+// - *StudyReport: The comparison
+// - error: Wrapping ErrInvalidConfig if the study has no Storage, or the
+// error of the Storage.
+func (s *Study) Compare(ctx context.Context) (*StudyReport, error) {
+	runs, err := s.Runs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StudyReport{
+		Study: s.Name,
+		Runs:  make([]RunReport, len(runs)),
+		Best:  -1,
+	}
+
+	bestValue := math.Inf(1)
+
+	for i, run := range runs {
+		report.Runs[i] = run.report()
+
+		if best := report.Runs[i].Best; best != nil && best.Value != nil && *best.Value < bestValue {
+			report.Best, bestValue = i, *best.Value
+		}
+	}
+
+	for i := 0; ; i++ {
+		var values []float64
+
+		for _, run := range report.Runs {
+			if i < len(run.Curve) {
+				values = append(values, run.Curve[i])
+			}
+		}
+
+		if len(values) == 0 {
+			break
+		}
+
+		lowest, highest := values[0], values[0]
+
+		for _, v := range values[1:] {
+			lowest, highest = math.Min(lowest, v), math.Max(highest, v)
+		}
+
+		report.Min = append(report.Min, lowest)
+		report.Median = append(report.Median, median(values))
+		report.Max = append(report.Max, highest)
+	}
+
+	return report, nil
+}
+
+// ExportJSON writes the study as a JSON document: its name, search space and
+// tags, and its runs with their trials.
+//
+// Parameters:
+// - ctx: Context of the Storage calls
+// - w: Where the document is written
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if the study has no Storage, or the
+// error of the Storage or of w.
+func (s *Study) ExportJSON(ctx context.Context, w io.Writer) error {
+	runs, err := s.Runs(ctx)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(studyDocument{
+		Name:  s.Name,
+		Space: s.Space,
+		Tags:  s.Tags,
+		Runs:  runs,
+	})
+}
+
+// ExportJSON writes the run as a JSON document, in the layout of StoredStudy.
+//
+// Parameters:
+// - w: Where the document is written
+//
+// Returns:
+// - error: The error of w, if any.
+func (r *Run) ExportJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(r)
+}
+
+// report summarizes the run.
+func (r *Run) report() RunReport {
+	report := RunReport{
+		ID:        r.ID,
+		StartedAt: r.Meta.StartedAt,
+		Tags:      r.Meta.Tags,
+		Trials:    len(r.Trials),
+		Best:      r.Best,
+		Curve:     []float64{},
+	}
+
+	best := math.Inf(1)
+
+	for _, trial := range r.Trials {
+		if trial.Status != TrialCompleted || trial.Value == nil || trial.Cached {
+			continue
+		}
+
+		best = math.Min(best, *trial.Value)
+
+		report.Curve = append(report.Curve, best)
+	}
+
+	return report
+}
+
+// checkStudy checks the parameter ranges match the search space of the study
+// of the run, if any, and that the run can be stored.
+func (o *optimizer[T]) checkStudy() error {
+	study := o.config.Study
+
+	if o.config.Storage == nil {
+		return fmt.Errorf("%w: study %q has no Storage", ErrInvalidConfig, study.Name)
+	}
+
+	specs := o.parameterSpecs()
+
+	if len(specs) != len(study.Space.Parameters) {
+		return fmt.Errorf("%w: got %d parameters, study %q has %d", ErrInvalidConfig, len(specs), study.Name, len(study.Space.Parameters))
+	}
+
+	for i, spec := range specs {
+		want := study.Space.Parameters[i]
+
+		if spec.Name != want.Name || spec.Min != want.Min || spec.Max != want.Max {
+			return fmt.Errorf(
+				"%w: parameter %d is %s [%v, %v], study %q has %s [%v, %v]",
+				ErrInvalidConfig, i, spec.Name, spec.Min, spec.Max, study.Name, want.Name, want.Min, want.Max,
+			)
+		}
+	}
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// mergeTags merges the tags of a study with those of a run, the latter
+// winning. Nil if both are empty.
+func mergeTags(study, run map[string]string) map[string]string {
+	if len(study) == 0 && len(run) == 0 {
+		return nil
+	}
+
+	tags := make(map[string]string, len(study)+len(run))
+
+	maps.Copy(tags, study)
+	maps.Copy(tags, run)
+
+	return tags
+}
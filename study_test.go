@@ -0,0 +1,212 @@
+package ho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStudy(t *testing.T) {
+	ctx := context.Background()
+
+	space := SearchSpace{Parameters: []ParameterSpec{
+		{Name: "x", Type: FloatParameter, Min: 0, Max: 10},
+		{Name: "y", Type: FloatParameter, Min: -5, Max: 5},
+	}}
+
+	objective := func(params ...float64) (float64, error) {
+		return math.Pow(params[0]-3, 2) + math.Pow(params[1]-1, 2), nil
+	}
+
+	storage := NewMemoryStorage()
+
+	study := &Study{
+		Name:    "tuning",
+		Space:   space,
+		Tags:    map[string]string{"service": "api", "env": "dev"},
+		Storage: storage,
+	}
+
+	// A run outside of the study, in the same storage.
+	unrelated := fastConfig()
+	unrelated.Storage = storage
+
+	OptimizeObjective(unrelated, objective, Ranges[float64](space)...)
+
+	var results []*Result[float64]
+
+	for i, env := range []string{"staging", "production"} {
+		config := fastConfig()
+		config.Seed = int64(i + 1)
+		config.Study = study
+		config.Tags = map[string]string{"env": env}
+
+		results = append(results, OptimizeObjective(config, objective, Ranges[float64](space)...))
+	}
+
+	t.Run("warm start", func(t *testing.T) {
+		observations, err := study.WarmStart(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		var expected []Observation
+
+		for _, result := range results {
+			for _, trial := range result.Trials {
+				expected = append(expected, Observation{Params: trial.Params, Value: trial.ExecutionTime, Weight: trial.Weight})
+			}
+		}
+
+		assert.Equal(t, expected, observations)
+
+		// The prior runs stand in for the initial samples.
+		config := fastConfig()
+		config.Seed = 3
+		config.InitialSamples = 0
+		config.Study = study
+		config.Tags = map[string]string{"env": "canary"}
+		config.WarmStart = observations
+
+		result := OptimizeObjective(config, objective, Ranges[float64](space)...)
+
+		assert.NoError(t, result.Err)
+
+		// The model of the new run knows the best trial of the prior ones.
+		prior := results[0]
+		if results[1].BestTime < prior.BestTime {
+			prior = results[1]
+		}
+
+		mean, stddev, err := result.Predict(prior.BestParams)
+
+		assert.NoError(t, err)
+		assert.InDelta(t, prior.BestTime, mean, 1e-2)
+		assert.Less(t, stddev, 1e-1)
+
+		results = append(results, result)
+
+		run, err := storage.LoadStudy(ctx, result.StudyID)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "tuning", run.Meta.Study)
+			assert.Equal(t, map[string]string{"service": "api", "env": "canary"}, run.Meta.Tags)
+		}
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		report, err := study.Compare(ctx)
+		if !assert.NoError(t, err) || !assert.Len(t, report.Runs, len(results)) {
+			return
+		}
+
+		assert.Equal(t, "tuning", report.Study)
+
+		best := 0
+
+		for i, result := range results {
+			if result.BestTime < results[best].BestTime {
+				best = i
+			}
+		}
+
+		assert.Equal(t, best, report.Best)
+
+		for i, run := range report.Runs {
+			assert.Equal(t, results[i].StudyID, run.ID)
+			assert.Equal(t, len(results[i].Trials), run.Trials)
+			assert.Len(t, run.Curve, len(results[i].Trials))
+
+			if assert.NotNil(t, run.Best) && assert.NotNil(t, run.Best.Value) {
+				assert.Equal(t, results[i].BestTime, *run.Best.Value)
+				assert.Equal(t, results[i].BestTime, run.Curve[len(run.Curve)-1])
+			}
+
+			for j := 1; j < len(run.Curve); j++ {
+				assert.LessOrEqual(t, run.Curve[j], run.Curve[j-1])
+			}
+		}
+
+		// The warm started run is shorter.
+		if !assert.Len(t, report.Median, len(results[0].Trials)) {
+			return
+		}
+
+		for i := range report.Median {
+			var values []float64
+
+			for _, run := range report.Runs {
+				if i < len(run.Curve) {
+					values = append(values, run.Curve[i])
+				}
+			}
+
+			assert.Equal(t, slices.Min(values), report.Min[i])
+			assert.Equal(t, median(values), report.Median[i])
+			assert.Equal(t, slices.Max(values), report.Max[i])
+		}
+	})
+
+	t.Run("export", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		if !assert.NoError(t, study.ExportJSON(ctx, &buf)) {
+			return
+		}
+
+		var document studyDocument
+
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+		assert.Equal(t, "tuning", document.Name)
+		assert.Equal(t, space, document.Space)
+
+		if !assert.Len(t, document.Runs, len(results)) {
+			return
+		}
+
+		runs, err := study.Runs(ctx)
+
+		assert.NoError(t, err)
+
+		buf.Reset()
+
+		if !assert.NoError(t, runs[0].ExportJSON(&buf)) {
+			return
+		}
+
+		var run Run
+
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &run))
+		assert.Equal(t, results[0].StudyID, run.ID)
+		assert.Equal(t, results[0].Records(), run.Trials)
+		assert.Equal(t, document.Runs[0].Trials, run.Trials)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		config := fastConfig()
+		config.Study = study
+
+		result := OptimizeObjective(config, objective, ParameterRange[float64]{Name: "x", Min: 0, Max: 10})
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+
+		result = OptimizeObjective(config, objective, ParameterRange[float64]{Name: "x", Min: 0, Max: 10}, ParameterRange[float64]{Name: "y", Min: -5, Max: 6})
+
+		assert.ErrorContains(t, result.Err, "parameter 1 is y [-5, 6]")
+
+		config.Study = &Study{Name: "unstored", Space: space}
+
+		result = OptimizeObjective(config, objective, Ranges[float64](space)...)
+
+		assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+
+		_, err := config.Study.Compare(ctx)
+
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+}
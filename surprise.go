@@ -0,0 +1,149 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultSurpriseThreshold is the SurpriseRemeasure.Threshold used if
+	// unset.
+	defaultSurpriseThreshold = 4.0
+
+	// maxRemeasurements caps SurpriseRemeasure.Remeasurements.
+	maxRemeasurements = 10
+)
+
+// SurpriseRemeasure configures the re-measurement of surprising trials: a
+// value much better or worse than the model predicted is more likely a
+// measurement glitch than reality, so the trial is measured again, and the
+// median of all its measurements is what enters the model.
+//
+// Important notes:
+// - Only trials of the optimization phase are checked, as the model needs
+// the initial samples to predict anything
+// - Re-measurements invoke the benchmark again with the same TrialInfo and
+// parameters, and stop early if the run must end, e.g. TimeBudget elapsed,
+// or if one of them fails
+// - Surprising trials are flagged, see Trial.Surprising and
+// Trial.Measurements
+// - Only optimization runs re-measure, not the ask/tell Optimizer.
+type SurpriseRemeasure struct {
+	// Threshold is the distance between the measured and predicted values,
+	// in predicted standard deviations, beyond which a trial is surprising.
+	// If 0, 4 is used.
+	Threshold float64
+
+	// Remeasurements is the number of times a surprising trial is measured
+	// again, at most 10. As the median of two measurements is their mean,
+	// at least 2 are needed to discard a one-off glitch.
+	// If 0, 1 is used.
+	Remeasurements int
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the settings.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if a setting is invalid, nil otherwise.
+func (s *SurpriseRemeasure) validate() error {
+	switch {
+	case s.Threshold < 0 || math.IsNaN(s.Threshold):
+		return fmt.Errorf("%w: SurpriseRemeasure.Threshold %v must be positive", ErrInvalidConfig, s.Threshold)
+	case s.Remeasurements < 0 || s.Remeasurements > maxRemeasurements:
+		return fmt.Errorf("%w: SurpriseRemeasure.Remeasurements %d must be between 0 and %d", ErrInvalidConfig, s.Remeasurements, maxRemeasurements)
+	}
+
+	return nil
+}
+
+// surprising returns true if the value of a completed trial is too far from
+// the prediction of the model, see SurpriseRemeasure.
+func (o *optimizer[T]) surprising(trial Trial[T]) bool {
+	settings := o.config.SurpriseRemeasure
+
+	if settings == nil || trial.Phase != PhaseOptimization {
+		return false
+	}
+
+	threshold := settings.Threshold
+	if threshold == 0 {
+		threshold = defaultSurpriseThreshold
+	}
+
+	// The model learned transformed values, see
+	// OptimizationConfig.ObjectiveTransform.
+	value, err := o.objectiveValue(trial.ExecutionTime, trial.Params)
+	if err != nil {
+		return false
+	}
+
+	mean, variance := o.model.Predict(o.modelPoint(trial.Params))
+
+	// The prediction is compared in the space of the model, see
+	// OptimizationConfig.OutputTransform.
+	distance := math.Abs(transformedValue(o.model, value) - mean)
+
+	// Predictions overflowed by failure penalties tell nothing.
+	if math.IsNaN(distance) || math.IsInf(distance, 0) {
+		return false
+	}
+
+	return distance > threshold*math.Sqrt(math.Max(variance, 0))
+}
+
+// remeasure measures a completed trial again if it's surprising, and sets
+// its value to the median of its measurements, see SurpriseRemeasure.
+//
+// Parameters:
+// - trial: The completed trial, updated in place.
+func (o *optimizer[T]) remeasure(trial *Trial[T]) {
+	if !o.surprising(*trial) {
+		return
+	}
+
+	remeasurements := o.config.SurpriseRemeasure.Remeasurements
+	if remeasurements == 0 {
+		remeasurements = 1
+	}
+
+	trial.Surprising = true
+	trial.Measurements = []float64{trial.ExecutionTime}
+
+	for i := 0; i < remeasurements && !o.done(); i++ {
+		if o.cooldown(true) != nil {
+			break
+		}
+
+		m := o.measureSeeds(trial.TrialInfo, trial.Params)
+
+		err := m.err
+
+		switch {
+		case err == nil && m.canceled != nil:
+			err = m.canceled
+		case err == nil && (math.IsNaN(m.value) || math.IsInf(m.value, 0)):
+			err = fmt.Errorf("%w %v", ErrNonFiniteValue, m.value)
+		}
+
+		if err != nil {
+			o.warnf("trial %d: re-measurement %d failed: %v", trial.TrialID, i+1, err)
+
+			break
+		}
+
+		trial.Measurements = append(trial.Measurements, m.value)
+		trial.SeedValues = m.seedValues
+		trial.Duration += m.duration
+		trial.RateLimitWait += m.wait
+	}
+
+	trial.ExecutionTime = median(trial.Measurements)
+}
@@ -0,0 +1,120 @@
+package ho
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// glitchingObjective returns an objective whose 4th call, the first of the
+// optimization phase with fastConfig, returns a value 1000 below the actual
+// one, and whose 5th call, the first re-measurement if any, fails with err.
+func glitchingObjective(err error, sleep time.Duration) ObjectiveFunc[float64] {
+	var calls int
+
+	return func(params ...float64) (float64, error) {
+		calls++
+
+		value := math.Pow(params[0]-3, 2)
+
+		switch calls {
+		case 4:
+			time.Sleep(sleep)
+
+			return value - 1000, nil
+		case 5:
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		return value, nil
+	}
+}
+
+func TestSurpriseRemeasure(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	t.Run("glitch", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 1
+
+		// Without re-measurement, the glitch is the best result.
+		result := OptimizeObjective(config, glitchingObjective(nil, 0), ranges...)
+
+		assert.Less(t, result.BestTime, -900.0)
+
+		config.SurpriseRemeasure = &SurpriseRemeasure{Remeasurements: 2}
+
+		result = OptimizeObjective(config, glitchingObjective(nil, 0), ranges...)
+
+		trial := result.Trials[config.InitialSamples]
+		value := math.Pow(trial.Params[0]-3, 2)
+
+		assert.True(t, trial.Surprising)
+		assert.Equal(t, []float64{value - 1000, value, value}, trial.Measurements)
+		assert.Equal(t, value, trial.ExecutionTime)
+		assert.Equal(t, 3*surpriseWeight, trial.Weight)
+		assert.GreaterOrEqual(t, result.BestTime, 0.0)
+
+		// The model isn't anchored to the glitch.
+		mean, _, err := result.Predict(trial.Params)
+
+		assert.NoError(t, err)
+		assert.InDelta(t, value, mean, 1)
+
+		for _, trial := range result.Trials[:config.InitialSamples] {
+			assert.False(t, trial.Surprising)
+		}
+
+		record := NewTrialRecord(trial, nil)
+
+		assert.True(t, record.Surprising)
+		assert.Equal(t, trial.Measurements, record.Measurements)
+	})
+
+	t.Run("failed re-measurement", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 1
+		config.SurpriseRemeasure = &SurpriseRemeasure{Remeasurements: 2}
+
+		result := OptimizeObjective(config, glitchingObjective(errors.New("connection reset"), 0), ranges...)
+
+		trial := result.Trials[config.InitialSamples]
+
+		// The trial keeps its single measurement.
+		assert.True(t, trial.Surprising)
+		assert.Len(t, trial.Measurements, 1)
+		assert.Equal(t, trial.Measurements[0], trial.ExecutionTime)
+		assert.Contains(t, result.Warnings, "trial 4: re-measurement 1 failed: connection reset")
+	})
+
+	t.Run("time budget", func(t *testing.T) {
+		config := fastConfig()
+		config.Seed = 1
+		config.TimeBudget = 100 * time.Millisecond
+		config.SurpriseRemeasure = &SurpriseRemeasure{}
+
+		result := OptimizeObjective(config, glitchingObjective(nil, 150*time.Millisecond), ranges...)
+
+		// The budget elapsed during the trial, it isn't measured again.
+		assert.Equal(t, TerminationTimeBudget, result.TerminationReason)
+		assert.Len(t, result.Trials, config.InitialSamples+1)
+		assert.True(t, result.Trials[config.InitialSamples].Surprising)
+		assert.Len(t, result.Trials[config.InitialSamples].Measurements, 1)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, settings := range []SurpriseRemeasure{{Threshold: -1}, {Remeasurements: -1}, {Remeasurements: 11}} {
+			config := fastConfig()
+			config.SurpriseRemeasure = &settings
+
+			result := OptimizeObjective(config, glitchingObjective(nil, 0), ranges...)
+
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+		}
+	})
+}
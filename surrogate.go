@@ -0,0 +1,307 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// SurrogateModel models the objective from the observations, to predict it
+// at untested points. See OptimizationConfig.Surrogate.
+//
+// Important notes:
+// - Implementations must be safe for concurrent use: Predict is called while
+// trials are recorded, e.g. by the ask/tell Optimizer
+// - Predictions feed the acquisition function, as mean and variance.
+// - Points are offsets from each range's Min, so integer ranges are exact.
+type SurrogateModel interface {
+	// Update adds an observation: the objective value y at x. It returns an
+	// error wrapping ErrInvalidObservation, leaving the model untouched, if
+	// the observation would corrupt it, e.g. it isn't finite, or x doesn't
+	// have the dimensions of the observed points.
+	Update(x []float64, y float64) error
+
+	// Predict returns the predicted mean and variance of the objective at x.
+	Predict(x []float64) (mean, variance float64)
+
+	// Points returns a copy of the observed points, in observation order.
+	Points() [][]float64
+
+	// Len returns the number of observations.
+	Len() int
+
+	// Clone returns an independent copy of the model, e.g. to add temporary
+	// observations to, or to predict from while the model keeps being
+	// updated.
+	Clone() SurrogateModel
+}
+
+// BatchPredictor is optionally implemented by a SurrogateModel predicting
+// many points at once faster than point by point, e.g. by sharing work
+// across points. Candidates are scored with PredictBatch when available.
+type BatchPredictor interface {
+	// PredictBatch returns the predicted means and variances at the points,
+	// aligned with them, exactly as Predict would.
+	PredictBatch(points [][]float64) (means, variances []float64)
+}
+
+// AdditiveModel is optionally implemented by a SurrogateModel modeling the
+// objective as a sum of independent terms, each over a group of dimensions,
+// e.g. AdditiveGaussianProcess. The optimizer then exploits the
+// decomposition: each group is optimized separately, taking the values of
+// the candidate whose term has the lowest confidence bound (see
+// AcquisitionParams.Beta), and the combined candidate is scored along with
+// the others.
+type AdditiveModel interface {
+	// Groups returns the groups of dimensions, by index, partitioning the
+	// dimensions, or nil if they aren't known yet, e.g. before the first
+	// observation.
+	Groups() [][]int
+
+	// PredictTerms returns the predicted means and variances of each term at
+	// the points: one slice per group, aligned with Groups, each aligned with
+	// points. Terms only depend on the dimensions of their group.
+	PredictTerms(points [][]float64) (means, variances [][]float64)
+}
+
+// WeightedUpdater is optionally implemented by a SurrogateModel trusting
+// observations unequally, e.g. a value measured under load less than one
+// measured 10 times. Trials are fed to the model with UpdateWeighted when
+// available, with Update otherwise, see Trial.Weight.
+type WeightedUpdater interface {
+	// UpdateWeighted adds an observation as Update does, with a positive
+	// weight: the inverse of the scaling of its noise, 1 being that of
+	// Update. It returns an error wrapping ErrInvalidObservation, leaving the
+	// model untouched, if the weight isn't positive and finite.
+	UpdateWeighted(x []float64, y, weight float64) error
+}
+
+//////
+// Helpers.
+//////
+
+// surrogateFactory returns the factory of the models of a run: Surrogate, or
+// a Gaussian process if unset. Models see integer and stepped dimensions on
+// their lattice, whatever the points they're given, see latticeModel.
+func surrogateFactory[T constraints.Integer | constraints.Float](config OptimizationConfig, hypers []ParameterRange[T]) func() SurrogateModel {
+	newModel := func() SurrogateModel { return newGaussianProcess() }
+
+	if config.Surrogate != nil {
+		newModel = config.Surrogate
+	}
+
+	lattices := latticesOf(hypers)
+
+	return func() SurrogateModel { return newLatticeModel(newModel(), lattices) }
+}
+
+// checkSurrogate checks the model against the search space, e.g. the groups
+// of an AdditiveGaussianProcess against its dimensions.
+//
+// Returns:
+// - error: Wrapping ErrInvalidConfig if the model can't model the space,
+// nil otherwise.
+func checkSurrogate(model SurrogateModel, dimensions int) error {
+	switch m := model.(type) {
+	case *transformedModel:
+		return checkSurrogate(m.inner(), dimensions)
+	case *latticeModel:
+		return checkSurrogate(m.model, dimensions)
+	case *fidelityModel:
+		return checkSurrogate(m.low, dimensions)
+	case *AdditiveGaussianProcess:
+		if err := m.options.validate(dimensions); err != nil {
+			return fmt.Errorf("%w: Surrogate: %w", ErrInvalidConfig, err)
+		}
+	}
+
+	return nil
+}
+
+// checkObservation checks that an observation is finite, as models can't
+// recover from NaN or infinite values, and that its point has the dimensions
+// of the observed ones.
+//
+// Parameters:
+// - x: Observed point
+// - y: Observed value
+// - observed: Points observed so far
+//
+// Returns:
+// - error: Wrapping ErrInvalidObservation if x or y isn't finite, or x has
+// different dimensions, nil otherwise.
+func checkObservation(x []float64, y float64, observed [][]float64) error {
+	if len(observed) > 0 && len(x) != len(observed[0]) {
+		return fmt.Errorf("%w: point has %d dimensions, expected %d", ErrInvalidObservation, len(x), len(observed[0]))
+	}
+
+	if math.IsNaN(y) || math.IsInf(y, 0) {
+		return fmt.Errorf("%w: non-finite value %v", ErrInvalidObservation, y)
+	}
+
+	for i, v := range x {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: non-finite coordinate %d: %v", ErrInvalidObservation, i, v)
+		}
+	}
+
+	return nil
+}
+
+// checkWeight checks that the weight of an observation is positive and
+// finite, see WeightedUpdater.
+//
+// Returns:
+// - error: Wrapping ErrInvalidObservation if it isn't, nil otherwise.
+func checkWeight(weight float64) error {
+	if !(weight > 0) || math.IsInf(weight, 0) {
+		return fmt.Errorf("%w: weight %v must be positive and finite", ErrInvalidObservation, weight)
+	}
+
+	return nil
+}
+
+// updateWeighted feeds an observation to the model, with its weight if the
+// model is a WeightedUpdater, see Trial.Weight. A weight of 0, i.e. unset,
+// is 1, which models feed with Update.
+//
+// Parameters:
+// - model: The model
+// - x: Observed point
+// - y: Observed value
+// - weight: Weight of the observation
+//
+// Returns:
+// - error: The error of the model, if any.
+func updateWeighted(model SurrogateModel, x []float64, y, weight float64) error {
+	if weight == 0 || weight == 1 {
+		return model.Update(x, y)
+	}
+
+	if weighted, ok := model.(WeightedUpdater); ok {
+		return weighted.UpdateWeighted(x, y, weight)
+	}
+
+	return model.Update(x, y)
+}
+
+// predictBatch predicts the model at the points, at once if the model is a
+// BatchPredictor, point by point otherwise.
+//
+// Parameters:
+// - model: The model
+// - points: Points to predict at
+//
+// Returns:
+// - means: Predicted means, aligned with points
+// - variances: Predicted variances, aligned with points.
+func predictBatch(model SurrogateModel, points [][]float64) (means, variances []float64) {
+	if batch, ok := model.(BatchPredictor); ok {
+		return batch.PredictBatch(points)
+	}
+
+	means = make([]float64, len(points))
+	variances = make([]float64, len(points))
+
+	for i, x := range points {
+		means[i], variances[i] = model.Predict(x)
+	}
+
+	return means, variances
+}
+
+// predictUntil predicts the model at the points, as predictBatch does, a few
+// points at a time once a deadline is set, stopping at the first chunk ending
+// past it.
+//
+// Parameters:
+// - model: The model
+// - points: Points to predict at
+// - deadline: Time to stop predicting at, none if zero
+//
+// Returns:
+// - means: Predicted means, aligned with the first points, at least one
+// chunk of them
+// - variances: Predicted variances, aligned with means.
+func predictUntil(model SurrogateModel, points [][]float64, deadline time.Time) (means, variances []float64) {
+	if deadline.IsZero() {
+		return predictBatch(model, points)
+	}
+
+	means = make([]float64, 0, len(points))
+	variances = make([]float64, 0, len(points))
+
+	for start := 0; start < len(points); start += selectionChunk {
+		m, v := predictBatch(model, points[start:min(start+selectionChunk, len(points))])
+
+		means, variances = append(means, m...), append(variances, v...)
+
+		if time.Now().After(deadline) {
+			break
+		}
+	}
+
+	return means, variances
+}
+
+// jitterWarning returns a warning if the model is a Gaussian, additive,
+// heteroscedastic or Student-t process, transformed or not, that needed more
+// than the minimal jitter to factorize its kernel matrix, which smooths its
+// predictions, empty otherwise.
+func jitterWarning(model SurrogateModel) string {
+	var gp *gaussianProcess
+
+	switch m := model.(type) {
+	case *gaussianProcess:
+		gp = m
+	case *StudentTProcess:
+		gp = m.gp
+	case *HeteroscedasticGaussianProcess:
+		gp = m.gp
+	case *transformedModel:
+		return jitterWarning(m.inner())
+	case *latticeModel:
+		return jitterWarning(m.model)
+	case *AdditiveGaussianProcess:
+		gp = m.gp
+	default:
+		return ""
+	}
+
+	jitter := gp.usedJitter()
+	if jitter <= jitters[0] {
+		return ""
+	}
+
+	return fmt.Sprintf("kernel matrix ill-conditioned, e.g. by near-duplicate points: jitter %g added to its diagonal", jitter)
+}
+
+// withLies returns a copy of the model with additional pseudo-observations,
+// all with the same value. It's used by the constant liar strategy: points
+// being evaluated are temporarily assumed to yield the lie, so the next
+// suggestion goes elsewhere.
+//
+// Parameters:
+// - model: The model, left untouched
+// - points: Points being evaluated
+// - lie: Value assumed at each point
+//
+// Returns:
+// - SurrogateModel: The copy.
+func withLies(model SurrogateModel, points [][]float64, lie float64) SurrogateModel {
+	clone := model.Clone()
+
+	// The lie, an observed value, and the points, suggested ones, are
+	// finite.
+	for _, x := range points {
+		_ = clone.Update(x, lie)
+	}
+
+	return clone
+}
@@ -0,0 +1,99 @@
+package ho
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Methods.
+//////
+
+// TopK returns the k best distinct configurations, e.g. to deploy the best
+// feasible one when the best needs more resources than available.
+//
+// Parameters:
+// - k: Maximum number of configurations to return
+//
+// Returns:
+// - []Trial[T]: The best completed trial of each distinct parameter tuple,
+// best first, at most k of them.
+//
+// Usage example:
+//
+//	for _, trial := range result.TopK(5) {
+//	    if fitsInMemory(trial.Params) {
+//	        return deploy(trial.Params)
+//	    }
+//	}
+//
+// Important notes:
+// - Only completed trials count: failed, canceled and skipped ones carry
+// penalties or no value at all
+// - Parameter tuples are compared exactly, quantization aside
+// - Ties are broken by trial ID, the earliest trial first
+// - Returns nil if k isn't positive.
+func (r *Result[T]) TopK(k int) []Trial[T] {
+	if k <= 0 {
+		return nil
+	}
+
+	best := make(map[string]int)
+
+	var top []Trial[T]
+
+	for _, trial := range r.Trials {
+		if trial.Status != TrialCompleted {
+			continue
+		}
+
+		key := paramsKey(trial.Params)
+
+		i, ok := best[key]
+
+		switch {
+		case !ok:
+			best[key] = len(top)
+
+			top = append(top, trial)
+		case betterTrial(trial, top[i]):
+			top[i] = trial
+		}
+	}
+
+	sort.Slice(top, func(i, j int) bool { return betterTrial(top[i], top[j]) })
+
+	return top[:min(k, len(top))]
+}
+
+//////
+// Helpers.
+//////
+
+// betterTrial returns true if a ranks before b: a lower value, or the same
+// value and a lower trial ID.
+func betterTrial[T constraints.Integer | constraints.Float](a, b Trial[T]) bool {
+	if a.ExecutionTime != b.ExecutionTime {
+		return a.ExecutionTime < b.ExecutionTime
+	}
+
+	return a.TrialID < b.TrialID
+}
+
+// paramsKey returns a key identifying the exact parameter values.
+func paramsKey[T constraints.Integer | constraints.Float](params []T) string {
+	var sb strings.Builder
+
+	for i, v := range params {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 64))
+	}
+
+	return sb.String()
+}
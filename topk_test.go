@@ -0,0 +1,47 @@
+package ho
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopK(t *testing.T) {
+	trial := func(id int, status TrialStatus, value float64, params ...int) Trial[int] {
+		return Trial[int]{TrialInfo: TrialInfo{TrialID: id}, Params: params, ExecutionTime: value, Status: status}
+	}
+
+	result := &Result[int]{
+		Trials: []Trial[int]{
+			trial(1, TrialCompleted, 30, 1, 1),
+			trial(2, TrialCompleted, 10, 2, 2),
+			trial(3, TrialFailed, math.MaxFloat64/2, 3, 3),
+			// A repeat of (1, 1), better than the first measurement.
+			trial(4, TrialCompleted, 20, 1, 1),
+			// A repeat of (2, 2), worse than the first measurement.
+			trial(5, TrialCompleted, 40, 2, 2),
+			// Ties with (1, 1), but comes later.
+			trial(6, TrialCompleted, 20, 4, 4),
+			trial(7, TrialSkipped, 0, 5, 5),
+			trial(8, TrialCanceled, math.MaxFloat64/2, 6, 6),
+			trial(9, TrialCompleted, 50, 7, 7),
+		},
+	}
+
+	ids := func(trials []Trial[int]) []int {
+		var ids []int
+
+		for _, trial := range trials {
+			ids = append(ids, trial.TrialID)
+		}
+
+		return ids
+	}
+
+	assert.Equal(t, []int{2, 4, 6, 9}, ids(result.TopK(10)))
+	assert.Equal(t, []int{2, 4}, ids(result.TopK(2)))
+	assert.Equal(t, []int{1, 1}, result.TopK(2)[1].Params)
+	assert.Nil(t, result.TopK(0))
+	assert.Empty(t, (&Result[int]{}).TopK(3))
+}
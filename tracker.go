@@ -0,0 +1,318 @@
+package ho
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// Tracker receives the events of optimization runs, e.g. to push them to an
+// experiment tracking service, see OptimizationConfig.Trackers.
+//
+// Important notes:
+// - Methods are called synchronously, one at a time, in both phases, so they
+// should return quickly. They don't need to be thread-safe, unless shared
+// across concurrent runs
+// - Panics are recovered, and recorded in Result.Warnings
+// - Each study of an ask/tell Optimizer is tracked as a run, from its
+// creation or Reset to the next Reset or Close.
+type Tracker interface {
+	// OnStudyStart is called once, before the first trial.
+	OnStudyStart(meta StudyMeta)
+
+	// OnTrialEnd is called for every recorded trial, skipped, failed, cached
+	// and canceled ones included.
+	OnTrialEnd(trial TrialRecord)
+
+	// OnNewBest is called, after OnTrialEnd, whenever a completed trial
+	// improves on the best result.
+	OnNewBest(trial TrialRecord)
+
+	// OnStudyEnd is called once, when the run terminates.
+	OnStudyEnd(summary RunSummary)
+}
+
+// StudyMeta describes a run, as passed to Tracker.OnStudyStart.
+type StudyMeta struct {
+	// StartedAt is when the run started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// Parameters describes the search space. Unnamed parameters are named
+	// "param<i>", as in TrialRecord.
+	Parameters []ParameterSpec `json:"parameters"`
+
+	// Iterations is the number of optimization iterations.
+	Iterations int `json:"iterations"`
+
+	// InitialSamples is the number of initial samples.
+	InitialSamples int `json:"initialSamples"`
+
+	// NumCandidates is the number of candidates scored per iteration.
+	NumCandidates int `json:"numCandidates"`
+
+	// Acquisition is the name of the acquisition function, empty if it isn't
+	// registered, see RegisterAcquisition.
+	Acquisition string `json:"acquisition,omitempty"`
+
+	// Seed is the configured seed, zero if unset.
+	Seed int64 `json:"seed,omitempty"`
+
+	// Study is the name of the study the run belongs to, empty if none, see
+	// OptimizationConfig.Study.
+	Study string `json:"study,omitempty"`
+
+	// Tags label the run, see OptimizationConfig.Tags.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// trackers dispatches events to trackers, serialized and isolated from
+// panics.
+type trackers struct {
+	// mu serializes calls.
+	mu sync.Mutex
+
+	// list holds the trackers.
+	list []Tracker
+
+	// warnf records panics.
+	warnf func(format string, args ...any)
+}
+
+// TrackerEvent is a line written by JSONLinesTracker.
+type TrackerEvent struct {
+	// Event is the name of the Tracker method called, e.g. "OnTrialEnd".
+	Event string `json:"event"`
+
+	// Time is when the event happened.
+	Time time.Time `json:"time"`
+
+	// Study is set for OnStudyStart.
+	Study *StudyMeta `json:"study,omitempty"`
+
+	// Trial is set for OnTrialEnd and OnNewBest.
+	Trial *TrialRecord `json:"trial,omitempty"`
+
+	// Summary is set for OnStudyEnd.
+	Summary *RunSummary `json:"summary,omitempty"`
+}
+
+// JSONLinesTracker is a Tracker writing events as JSON lines, see
+// TrackerEvent. It's safe for concurrent use.
+type JSONLinesTracker struct {
+	// mu protects encoder and err.
+	mu sync.Mutex
+
+	// encoder writes the lines.
+	encoder *json.Encoder
+
+	// err holds the first write error.
+	err error
+}
+
+// MemoryTracker is a Tracker keeping the events of the last study in memory,
+// e.g. for tests. It's safe for concurrent use.
+type MemoryTracker struct {
+	// mu protects the fields below.
+	mu sync.Mutex
+
+	// meta holds the study metadata.
+	meta *StudyMeta
+
+	// trials holds the ended trials, in order.
+	trials []TrialRecord
+
+	// bests holds the successive bests, in order.
+	bests []TrialRecord
+
+	// summary holds the run summary, once ended.
+	summary *RunSummary
+}
+
+//////
+// Methods.
+//////
+
+// call calls fn on each tracker, recovering panics.
+func (t *trackers) call(event string, fn func(Tracker)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, tracker := range t.list {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.warnf("tracker %T panicked in %s: %v", tracker, event, r)
+				}
+			}()
+
+			fn(tracker)
+		}()
+	}
+}
+
+// OnStudyStart implements Tracker.
+func (j *JSONLinesTracker) OnStudyStart(meta StudyMeta) {
+	j.write(TrackerEvent{Event: "OnStudyStart", Time: time.Now(), Study: &meta})
+}
+
+// OnTrialEnd implements Tracker.
+func (j *JSONLinesTracker) OnTrialEnd(trial TrialRecord) {
+	j.write(TrackerEvent{Event: "OnTrialEnd", Time: time.Now(), Trial: &trial})
+}
+
+// OnNewBest implements Tracker.
+func (j *JSONLinesTracker) OnNewBest(trial TrialRecord) {
+	j.write(TrackerEvent{Event: "OnNewBest", Time: time.Now(), Trial: &trial})
+}
+
+// OnStudyEnd implements Tracker.
+func (j *JSONLinesTracker) OnStudyEnd(summary RunSummary) {
+	j.write(TrackerEvent{Event: "OnStudyEnd", Time: time.Now(), Summary: &summary})
+}
+
+// Err returns the first write error, if any. Events aren't written after an
+// error.
+func (j *JSONLinesTracker) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.err
+}
+
+// write writes an event, unless a previous write failed.
+func (j *JSONLinesTracker) write(event TrackerEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.err != nil {
+		return
+	}
+
+	if err := j.encoder.Encode(event); err != nil {
+		j.err = fmt.Errorf("writing %s event: %w", event.Event, err)
+	}
+}
+
+// OnStudyStart implements Tracker. Each study starts over, e.g. after
+// Optimizer.Reset.
+func (m *MemoryTracker) OnStudyStart(meta StudyMeta) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.meta = &meta
+	m.trials, m.bests, m.summary = nil, nil, nil
+}
+
+// OnTrialEnd implements Tracker.
+func (m *MemoryTracker) OnTrialEnd(trial TrialRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.trials = append(m.trials, trial)
+}
+
+// OnNewBest implements Tracker.
+func (m *MemoryTracker) OnNewBest(trial TrialRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.bests = append(m.bests, trial)
+}
+
+// OnStudyEnd implements Tracker.
+func (m *MemoryTracker) OnStudyEnd(summary RunSummary) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.summary = &summary
+}
+
+// Meta returns the study metadata, nil until the study starts.
+func (m *MemoryTracker) Meta() *StudyMeta {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.meta
+}
+
+// Trials returns the ended trials, in order.
+func (m *MemoryTracker) Trials() []TrialRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]TrialRecord(nil), m.trials...)
+}
+
+// Bests returns the successive bests, in order.
+func (m *MemoryTracker) Bests() []TrialRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]TrialRecord(nil), m.bests...)
+}
+
+// Summary returns the run summary, nil until the study ends.
+func (m *MemoryTracker) Summary() *RunSummary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.summary
+}
+
+// startTracking reports the start of the run to the trackers, if any.
+func (o *optimizer[T]) startTracking() {
+	if len(o.config.Trackers) == 0 {
+		return
+	}
+
+	o.trackers = &trackers{list: o.config.Trackers, warnf: o.warnf}
+
+	meta := o.studyMeta()
+
+	o.trackers.call("OnStudyStart", func(t Tracker) { t.OnStudyStart(meta) })
+}
+
+// endTracking reports the end of the run to the trackers, if any.
+//
+// Returns:
+// - *Result[T]: The outcome of the run, panics of the trackers included.
+func (o *optimizer[T]) endTracking() *Result[T] {
+	result := o.result()
+
+	if o.trackers == nil {
+		return result
+	}
+
+	summary := newRunSummary(result)
+
+	o.trackers.call("OnStudyEnd", func(t Tracker) { t.OnStudyEnd(*summary) })
+
+	// Panics are recorded as warnings.
+	return o.result()
+}
+
+//////
+// Factory.
+//////
+
+// NewJSONLinesTracker creates a JSONLinesTracker.
+//
+// Parameters:
+// - w: Where events are written, e.g. a file
+//
+// Returns:
+// - *JSONLinesTracker: The tracker.
+func NewJSONLinesTracker(w io.Writer) *JSONLinesTracker {
+	return &JSONLinesTracker{encoder: json.NewEncoder(w)}
+}
+
+// NewMemoryTracker creates a MemoryTracker.
+func NewMemoryTracker() *MemoryTracker {
+	return &MemoryTracker{}
+}
@@ -0,0 +1,176 @@
+package ho
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// panickingTracker panics in every method.
+type panickingTracker struct{}
+
+func (panickingTracker) OnStudyStart(StudyMeta) { panic("start") }
+func (panickingTracker) OnTrialEnd(TrialRecord) { panic("trial") }
+func (panickingTracker) OnNewBest(TrialRecord)  { panic("best") }
+func (panickingTracker) OnStudyEnd(RunSummary)  { panic("end") }
+
+// countingTracker counts calls, without synchronization.
+type countingTracker struct {
+	calls int
+}
+
+func (c *countingTracker) OnStudyStart(StudyMeta) { c.calls++ }
+func (c *countingTracker) OnTrialEnd(TrialRecord) { c.calls++ }
+func (c *countingTracker) OnNewBest(TrialRecord)  { c.calls++ }
+func (c *countingTracker) OnStudyEnd(RunSummary)  { c.calls++ }
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+// trackedObjective skips every third trial.
+func trackedObjective() ObjectiveFunc[float64] {
+	calls := 0
+
+	return func(params ...float64) (float64, error) {
+		calls++
+
+		if calls%3 == 0 {
+			return 0, ErrSkipTrial
+		}
+
+		return params[0] * params[0], nil
+	}
+}
+
+func TestMemoryTracker(t *testing.T) {
+	tracker := NewMemoryTracker()
+
+	config := fastConfig()
+	config.Trackers = []Tracker{tracker}
+
+	result := OptimizeObjective(config, trackedObjective(), ParameterRange[float64]{Name: "x", Min: -10, Max: 10})
+
+	assert.NoError(t, result.Err)
+	assert.Empty(t, result.Warnings)
+
+	meta := tracker.Meta()
+
+	if assert.NotNil(t, meta) {
+		assert.Equal(t, []ParameterSpec{{Name: "x", Type: FloatParameter, Min: -10, Max: 10}}, meta.Parameters)
+		assert.Equal(t, config.Iterations, meta.Iterations)
+		assert.Equal(t, "LowerConfidenceBound", meta.Acquisition)
+	}
+
+	// Every trial is tracked, skipped ones included.
+	assert.Equal(t, result.Records(), tracker.Trials())
+
+	bests := tracker.Bests()
+
+	if assert.NotEmpty(t, bests) {
+		for i := 1; i < len(bests); i++ {
+			assert.Less(t, *bests[i].Value, *bests[i-1].Value)
+		}
+
+		assert.Equal(t, result.BestTime, *bests[len(bests)-1].Value)
+	}
+
+	summary := tracker.Summary()
+
+	if assert.NotNil(t, summary) {
+		assert.Equal(t, TerminationCompleted, summary.TerminationReason)
+		assert.Equal(t, len(result.Trials), summary.Trials)
+		assert.Equal(t, &bests[len(bests)-1], summary.Best)
+	}
+}
+
+func TestTrackerPanics(t *testing.T) {
+	tracker := NewMemoryTracker()
+
+	config := fastConfig()
+	config.Trackers = []Tracker{panickingTracker{}, tracker}
+
+	result := OptimizeObjective(config, trackedObjective(), ParameterRange[float64]{Min: -10, Max: 10})
+
+	// Panics neither stop the run nor the other trackers.
+	assert.NoError(t, result.Err)
+	assert.Len(t, tracker.Trials(), len(result.Trials))
+	assert.NotNil(t, tracker.Summary())
+
+	assert.Contains(t, result.Warnings, "tracker ho.panickingTracker panicked in OnStudyStart: start")
+	assert.Contains(t, result.Warnings, "tracker ho.panickingTracker panicked in OnTrialEnd: trial")
+	assert.Contains(t, result.Warnings, "tracker ho.panickingTracker panicked in OnNewBest: best")
+	assert.Contains(t, result.Warnings, "tracker ho.panickingTracker panicked in OnStudyEnd: end")
+}
+
+func TestTrackerConcurrentInitialSampling(t *testing.T) {
+	tracker := &countingTracker{}
+
+	config := fastConfig()
+	config.MaxConcurrentEvaluations = 3
+	config.Trackers = []Tracker{tracker}
+
+	result := OptimizeObjective(config, func(params ...float64) (float64, error) {
+		return params[0], nil
+	}, ParameterRange[float64]{Min: 0, Max: 1})
+
+	// Calls are serialized, run with -race.
+	assert.Greater(t, tracker.calls, len(result.Trials)+2)
+}
+
+func TestJSONLinesTracker(t *testing.T) {
+	var buf bytes.Buffer
+
+	tracker := NewJSONLinesTracker(&buf)
+
+	config := fastConfig()
+	config.Trackers = []Tracker{tracker}
+
+	result := OptimizeObjective(config, trackedObjective(), ParameterRange[float64]{Min: -10, Max: 10})
+
+	assert.NoError(t, tracker.Err())
+
+	var events []TrackerEvent
+
+	scanner := bufio.NewScanner(&buf)
+
+	for scanner.Scan() {
+		var event TrackerEvent
+
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+
+		events = append(events, event)
+	}
+
+	if !assert.Greater(t, len(events), len(result.Trials)+2) {
+		return
+	}
+
+	assert.Equal(t, "OnStudyStart", events[0].Event)
+	assert.Equal(t, "param0", events[0].Study.Parameters[0].Name)
+	assert.Equal(t, "OnStudyEnd", events[len(events)-1].Event)
+	assert.Equal(t, len(result.Trials), events[len(events)-1].Summary.Trials)
+
+	var trials []TrialRecord
+
+	for _, event := range events {
+		if event.Event == "OnTrialEnd" {
+			trials = append(trials, *event.Trial)
+		}
+	}
+
+	assert.Equal(t, result.Records(), trials)
+
+	// Write errors are reported.
+	tracker = NewJSONLinesTracker(failingWriter{})
+
+	tracker.OnStudyEnd(RunSummary{})
+	tracker.OnStudyEnd(RunSummary{})
+
+	assert.EqualError(t, tracker.Err(), "writing OnStudyEnd event: disk full")
+}
@@ -0,0 +1,281 @@
+package ho
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// TrialRecord is the JSON representation of a trial, shared by exports and
+// notifications. Parameters are keyed by name, see ParameterRange.Name.
+type TrialRecord struct {
+	// ID is the trial ID, see TrialInfo.TrialID.
+	ID int `json:"id"`
+
+	// Phase is the phase the trial ran in.
+	Phase string `json:"phase"`
+
+	// Iteration is the iteration of the phase the trial belongs to.
+	Iteration int `json:"iteration"`
+
+	// Retry is true for replacement evaluations of skipped trials.
+	Retry bool `json:"retry,omitempty"`
+
+	// Seed is the seed of the trial, see TrialInfo.Seed.
+	Seed int64 `json:"seed"`
+
+	// RNGPosition is the position of the random number generator of the run
+	// when the parameters were generated, see TrialInfo.RNGPosition.
+	RNGPosition uint64 `json:"rngPosition"`
+
+	// Budget is the resource budget of the trial, see TrialInfo.Budget.
+	Budget float64 `json:"budget,omitempty"`
+
+	// Status is the outcome of the trial.
+	Status TrialStatus `json:"status"`
+
+	// Cached is true if the outcome of a previous trial was reused.
+	Cached bool `json:"cached,omitempty"`
+
+	// Value is the value minimized, only set for completed trials, i.e.
+	// penalties are left out.
+	Value *float64 `json:"value,omitempty"`
+
+	// MeasuredValue is the value measured, if it was transformed, see
+	// Trial.MeasuredValue.
+	MeasuredValue *float64 `json:"measuredValue,omitempty"`
+
+	// UnderLoad is true if the trial started while the system wasn't ready,
+	// see Trial.UnderLoad.
+	UnderLoad bool `json:"underLoad,omitempty"`
+
+	// Surprising is true if the trial was measured again, see
+	// Trial.Surprising.
+	Surprising bool `json:"surprising,omitempty"`
+
+	// Measurements holds the values measured for a surprising trial, see
+	// Trial.Measurements.
+	Measurements []float64 `json:"measurements,omitempty"`
+
+	// SeedValues holds the values measured with each seed, see
+	// Trial.SeedValues.
+	SeedValues []float64 `json:"seedValues,omitempty"`
+
+	// Weight is the confidence in the value the model was fed with, see
+	// Trial.Weight.
+	Weight float64 `json:"weight,omitempty"`
+
+	// DurationNS is the measured wall time of the trial, in nanoseconds.
+	DurationNS int64 `json:"durationNs"`
+
+	// DriftCorrection is the drift ratio the value was divided by, see
+	// Trial.DriftCorrection.
+	DriftCorrection float64 `json:"driftCorrection,omitempty"`
+
+	// RateLimitWaitNS is the time spent waiting for the rate limiter, in
+	// nanoseconds, see Trial.RateLimitWait.
+	RateLimitWaitNS int64 `json:"rateLimitWaitNs,omitempty"`
+
+	// SafetyMetric is the value of the safety metric, see
+	// Trial.SafetyMetric.
+	SafetyMetric *float64 `json:"safetyMetric,omitempty"`
+
+	// SafetyViolation is true if the trial violated the safety limit, see
+	// Trial.SafetyViolation.
+	SafetyViolation bool `json:"safetyViolation,omitempty"`
+
+	// Params holds the parameter values, by name.
+	Params map[string]float64 `json:"params"`
+
+	// BenchmarkParams holds the values the benchmark received, by name, if
+	// a range is transformed, see Trial.BenchmarkParams.
+	BenchmarkParams map[string]float64 `json:"benchmarkParams,omitempty"`
+
+	// Formatted holds the values of typed parameters, durations, bools and
+	// enums, by name, as rendered by ParameterSpec.Format, e.g. "250ms" or
+	// "zstd". Unset by NewTrialRecord, which doesn't know the types.
+	Formatted map[string]string `json:"formatted,omitempty"`
+
+	// Error is the error the trial failed with, if any.
+	Error string `json:"error,omitempty"`
+
+	// Stop is true if the error requested the run to stop, see
+	// ErrStopOptimization.
+	Stop bool `json:"stop,omitempty"`
+}
+
+//////
+// Methods.
+//////
+
+// Records converts the trials to their JSON representation, see TrialRecord.
+func (r *Result[T]) Records() []TrialRecord {
+	records := make([]TrialRecord, len(r.Trials))
+
+	for i, trial := range r.Trials {
+		records[i] = NewTrialRecord(trial, r.ParamNames)
+		records[i].Formatted = formatParams(r.hypers, r.ParamNames, trial.Params)
+	}
+
+	return records
+}
+
+// FormatParams renders parameters as name=value pairs, in the order of the
+// ranges, values rendered as ParameterSpec.Format does, e.g. durations like
+// "250ms" and enums as the chosen value.
+//
+// Parameters:
+// - params: Parameter values, e.g. BestParams
+//
+// Returns:
+// - string: The pairs, separated by spaces, e.g. "Timeout=250ms Workers=8".
+func (r *Result[T]) FormatParams(params []T) string {
+	pairs := make([]string, len(params))
+
+	for i, v := range params {
+		spec := ParameterSpec{Type: FloatParameter}
+
+		if i < len(r.hypers) {
+			spec = formatSpec(r.hypers[i])
+		}
+
+		pairs[i] = paramName(r.ParamNames, i) + "=" + spec.Format(float64(v))
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+//////
+// Helpers.
+//////
+
+// paramName returns the name of the i-th parameter, "param<i>" if unnamed.
+func paramName(names []string, i int) string {
+	if i < len(names) && names[i] != "" {
+		return names[i]
+	}
+
+	return "param" + strconv.Itoa(i)
+}
+
+// paramType returns the type of a range: its Type if set, IntParameter for
+// integer types, FloatParameter otherwise.
+func paramType[T constraints.Integer | constraints.Float](hyper ParameterRange[T]) ParameterType {
+	if hyper.Type != "" {
+		return hyper.Type
+	}
+
+	// Integer types truncate halves.
+	if half := 0.5; T(half) == 0 {
+		return IntParameter
+	}
+
+	return FloatParameter
+}
+
+// formatSpec returns what ParameterSpec.Format needs to render values of a
+// range: its type and choices.
+func formatSpec[T constraints.Integer | constraints.Float](hyper ParameterRange[T]) ParameterSpec {
+	return ParameterSpec{Type: paramType(hyper), Values: hyper.Values}
+}
+
+// formatParams renders the values of typed parameters, i.e. durations, bools
+// and enums, by name, see TrialRecord.Formatted.
+//
+// Parameters:
+// - hypers: The parameter ranges
+// - names: Parameter names
+// - params: Parameter values
+//
+// Returns:
+// - map[string]string: The rendered values, nil if no parameter is typed.
+func formatParams[T constraints.Integer | constraints.Float](hypers []ParameterRange[T], names []string, params []T) map[string]string {
+	var formatted map[string]string
+
+	for i, hyper := range hypers {
+		switch {
+		case i >= len(params):
+			continue
+		case hyper.Type != DurationParameter && hyper.Type != BoolParameter && hyper.Type != EnumParameter:
+			continue
+		}
+
+		if formatted == nil {
+			formatted = make(map[string]string)
+		}
+
+		formatted[paramName(names, i)] = formatSpec(hyper).Format(float64(params[i]))
+	}
+
+	return formatted
+}
+
+//////
+// Factory.
+//////
+
+// NewTrialRecord converts a trial to its JSON representation.
+//
+// Parameters:
+// - trial: The trial
+// - names: Parameter names, e.g. Result.ParamNames. Unnamed parameters are
+// keyed "param<i>", i being their 0-based index
+//
+// Returns:
+// - TrialRecord: The record.
+func NewTrialRecord[T constraints.Integer | constraints.Float](trial Trial[T], names []string) TrialRecord {
+	record := TrialRecord{
+		ID:              trial.TrialID,
+		Phase:           trial.Phase,
+		Iteration:       trial.Iteration,
+		Retry:           trial.Retry,
+		Seed:            trial.Seed,
+		RNGPosition:     trial.RNGPosition,
+		Budget:          trial.Budget,
+		Status:          trial.Status,
+		Cached:          trial.Cached,
+		UnderLoad:       trial.UnderLoad,
+		Surprising:      trial.Surprising,
+		Measurements:    trial.Measurements,
+		SeedValues:      trial.SeedValues,
+		Weight:          trial.Weight,
+		DurationNS:      trial.Duration.Nanoseconds(),
+		RateLimitWaitNS: trial.RateLimitWait.Nanoseconds(),
+		DriftCorrection: trial.DriftCorrection,
+		MeasuredValue:   trial.MeasuredValue,
+		SafetyMetric:    trial.SafetyMetric,
+		SafetyViolation: trial.SafetyViolation,
+		Params:          make(map[string]float64, len(trial.Params)),
+	}
+
+	for i, p := range trial.Params {
+		record.Params[paramName(names, i)] = float64(p)
+	}
+
+	if trial.BenchmarkParams != nil {
+		record.BenchmarkParams = make(map[string]float64, len(trial.BenchmarkParams))
+
+		for i, p := range trial.BenchmarkParams {
+			record.BenchmarkParams[paramName(names, i)] = float64(p)
+		}
+	}
+
+	if trial.Status == TrialCompleted {
+		value := trial.ExecutionTime
+
+		record.Value = &value
+	}
+
+	if trial.Err != nil {
+		record.Error = trial.Err.Error()
+		record.Stop = errors.Is(trial.Err, ErrStopOptimization)
+	}
+
+	return record
+}
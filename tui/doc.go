@@ -0,0 +1,24 @@
+// Package tui renders the progress of a run in a terminal, from its progress
+// updates, see ho.OptimizationConfig.ProgressChan:
+//
+//	progress := make(chan ho.ProgressUpdate, 100)
+//	config.ProgressChan = progress
+//
+//	renderer := tui.New(os.Stderr, tui.Options{Durations: true})
+//	done := make(chan struct{})
+//
+//	go func() {
+//	    renderer.Run(progress)
+//	    close(done)
+//	}()
+//
+//	result := ho.Optimize(config, benchmark, ranges...)
+//
+//	close(progress)
+//	<-done
+//
+// On a terminal, a single line refreshes in place: a spinner, the phase, a
+// progress bar, the best and last values and the estimated time left, fitted
+// to the width of the terminal. Other writers, e.g. CI logs, get one plain
+// line per update instead.
+package tui
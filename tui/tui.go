@@ -0,0 +1,395 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/thalesfsp/ho"
+)
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// defaultWidth is the terminal width used if unknown.
+	defaultWidth = 80
+
+	// defaultRefresh is the Options.Refresh used if unset.
+	defaultRefresh = 100 * time.Millisecond
+
+	// minBarWidth is the narrowest progress bar drawn, narrower terminals
+	// get none.
+	minBarWidth = 10
+
+	// maxBarWidth is the widest progress bar drawn.
+	maxBarWidth = 30
+
+	// clearLine moves the cursor to the start of the line and clears it.
+	clearLine = "\r\x1b[K"
+)
+
+// spinner holds the frames of the spinner.
+var spinner = []string{"|", "/", "-", "\\"}
+
+// Mode determines how updates are rendered.
+type Mode int
+
+const (
+	// ModeAuto refreshes a line in place if the writer is a terminal, and
+	// falls back to ModePlain otherwise.
+	ModeAuto Mode = iota
+
+	// ModeInteractive refreshes a line in place.
+	ModeInteractive
+
+	// ModePlain writes a line per update, e.g. for CI logs.
+	ModePlain
+)
+
+// Options configures a Renderer.
+type Options struct {
+	// Mode determines how updates are rendered.
+	// If ModeAuto, it depends on whether the writer is a terminal.
+	Mode Mode
+
+	// Durations renders values as durations, for values in nanoseconds,
+	// e.g. from ho.Optimize. Otherwise values are rendered as numbers.
+	Durations bool
+
+	// Width is the width of the terminal, interactive lines being cut to
+	// fit.
+	// If 0, the COLUMNS environment variable is used, or 80 if unset.
+	Width int
+
+	// Refresh is how often Run redraws the interactive line between
+	// updates, to animate the spinner.
+	// If 0, 100ms is used.
+	Refresh time.Duration
+}
+
+// Renderer renders progress updates to a writer. It's safe for concurrent
+// use.
+type Renderer struct {
+	// w is the writer rendered to.
+	w io.Writer
+
+	// options configures the rendering, Mode and Width resolved.
+	options Options
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// last is the last update rendered.
+	last ho.ProgressUpdate
+
+	// started is when the first trial started, zero before the first
+	// update.
+	started time.Time
+
+	// frame is the frame of the spinner.
+	frame int
+
+	// drawn is true if an interactive line is on screen.
+	drawn bool
+}
+
+//////
+// Methods.
+//////
+
+// Update renders an update, e.g. from a callback.
+//
+// Parameters:
+// - u: The update.
+func (r *Renderer) Update(u ho.ProgressUpdate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started.IsZero() {
+		r.started = u.Time.Add(-u.LastDuration)
+	}
+
+	r.last = u
+	r.frame++
+
+	if r.options.Mode == ModePlain {
+		fmt.Fprintln(r.w, r.plainLine(u))
+
+		return
+	}
+
+	r.draw()
+
+	// The final line stays.
+	if u.Phase == ho.PhaseDone {
+		fmt.Fprintln(r.w)
+
+		r.drawn = false
+	}
+}
+
+// Run renders the updates until the channel is closed, redrawing the
+// interactive line every Refresh meanwhile, then calls Close.
+//
+// Parameters:
+// - updates: The updates, e.g. the channel set as
+// ho.OptimizationConfig.ProgressChan.
+func (r *Renderer) Run(updates <-chan ho.ProgressUpdate) {
+	defer r.Close()
+
+	ticker := time.NewTicker(r.options.Refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case u, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			r.Update(u)
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+// Close ends the interactive line, if any, so the next output starts on its
+// own line.
+func (r *Renderer) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.drawn {
+		fmt.Fprintln(r.w)
+
+		r.drawn = false
+	}
+}
+
+// tick advances the spinner of the interactive line.
+func (r *Renderer) tick() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.drawn {
+		return
+	}
+
+	r.frame++
+
+	r.draw()
+}
+
+// draw redraws the interactive line. Callers must hold mu.
+func (r *Renderer) draw() {
+	fmt.Fprint(r.w, clearLine+r.interactiveLine(r.last))
+
+	r.drawn = true
+}
+
+// interactiveLine renders an update as a line fitted to the width: segments
+// are dropped from the least important one, the progress bar first, until
+// it fits.
+func (r *Renderer) interactiveLine(u ho.ProgressUpdate) string {
+	// The last column is left empty, so terminals don't wrap.
+	width := r.options.Width - 1
+
+	if u.Phase == ho.PhaseDone {
+		return fit(r.doneLine(u), width)
+	}
+
+	head := fmt.Sprintf("%s %s %d/%d %3.0f%%", spinner[r.frame%len(spinner)], u.Phase, u.CurrentIteration, u.TotalIterations, 100*u.OverallProgress)
+
+	// By decreasing importance.
+	segments := []string{"best " + r.value(u.CurrentBestTime), "last " + r.value(u.LastExecutionTime), ""}
+
+	if eta, ok := r.eta(u); ok {
+		segments[2] = "ETA " + eta
+	}
+
+	line := head
+
+	for n := len(segments); n >= 0; n-- {
+		line = head
+
+		for _, s := range segments[:n] {
+			if s != "" {
+				line += "  " + s
+			}
+		}
+
+		if n == 0 || utf8.RuneCountInString(line) <= width {
+			break
+		}
+	}
+
+	// The bar takes the room left, if any.
+	if room := width - utf8.RuneCountInString(line) - 2; room >= minBarWidth {
+		before, after, _ := strings.Cut(line, "%")
+
+		line = before + "% " + bar(u.OverallProgress, min(room, maxBarWidth)) + after
+	}
+
+	return fit(line, width)
+}
+
+// plainLine renders an update as a line of a log, e.g. "[Optimization 3/50]
+// 26% trial 13 value=1.2ms best=1.2ms eta=1m20s NEW BEST".
+func (r *Renderer) plainLine(u ho.ProgressUpdate) string {
+	if u.Phase == ho.PhaseDone {
+		return r.doneLine(u)
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[%s %d/%d] %.0f%% trial %d value=%s best=%s", u.Phase, u.CurrentIteration, u.TotalIterations, 100*u.OverallProgress, u.TrialID, r.value(u.LastExecutionTime), r.value(u.CurrentBestTime))
+
+	if eta, ok := r.eta(u); ok {
+		b.WriteString(" eta=" + eta)
+	}
+
+	if u.NewBest {
+		b.WriteString(" NEW BEST")
+	}
+
+	return b.String()
+}
+
+// doneLine renders the final update, e.g. "Done (Completed): best 1.2ms
+// after 40 evaluations in 3m2s".
+func (r *Renderer) doneLine(u ho.ProgressUpdate) string {
+	line := fmt.Sprintf("%s (%s): best %s after %d evaluations", u.Phase, u.TerminationReason, r.value(u.CurrentBestTime), u.EvaluationsCompleted)
+
+	if elapsed := u.Time.Sub(r.started); !u.Time.IsZero() && elapsed > 0 {
+		line += " in " + humanDuration(elapsed)
+	}
+
+	return line
+}
+
+// value renders a value: "n/a" if there's none, "failed" for the penalty of
+// failed trials, in human units otherwise.
+func (r *Renderer) value(v float64) string {
+	switch {
+	case v == math.MaxFloat64:
+		return "n/a"
+	case v >= math.MaxFloat64/2:
+		return "failed"
+	case r.options.Durations:
+		return humanDuration(time.Duration(math.Round(v)))
+	default:
+		return strconv.FormatFloat(v, 'g', 4, 64)
+	}
+}
+
+// eta returns the estimated time left, extrapolated from the time elapsed
+// since the first trial started and the overall progress.
+func (r *Renderer) eta(u ho.ProgressUpdate) (string, bool) {
+	elapsed := u.Time.Sub(r.started)
+
+	if u.Time.IsZero() || elapsed <= 0 || u.OverallProgress <= 0 || u.OverallProgress >= 1 {
+		return "", false
+	}
+
+	return humanDuration(time.Duration(float64(elapsed) * (1 - u.OverallProgress) / u.OverallProgress)), true
+}
+
+//////
+// Helpers.
+//////
+
+// bar draws a progress bar of the width, brackets included.
+func bar(progress float64, width int) string {
+	inner := width - 2
+
+	filled := int(math.Round(math.Min(math.Max(progress, 0), 1) * float64(inner)))
+
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", inner-filled) + "]"
+}
+
+// fit cuts the line to the width, in runes.
+func fit(line string, width int) string {
+	if width < 1 || utf8.RuneCountInString(line) <= width {
+		return line
+	}
+
+	return string([]rune(line)[:width])
+}
+
+// humanDuration renders a duration with 3 significant digits, e.g. "1.23ms",
+// or to the second from a minute on, e.g. "2m5s".
+func humanDuration(d time.Duration) string {
+	if d >= time.Minute {
+		return d.Round(time.Second).String()
+	}
+
+	for _, unit := range []time.Duration{time.Second, time.Millisecond, time.Microsecond} {
+		switch {
+		case d >= 100*unit:
+			return d.Round(unit).String()
+		case d >= 10*unit:
+			return d.Round(unit / 10).String()
+		case d >= unit:
+			return d.Round(unit / 100).String()
+		}
+	}
+
+	return d.String()
+}
+
+// isTerminal returns true if the writer is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+//////
+// Factory.
+//////
+
+// New creates a renderer writing to w.
+//
+// Parameters:
+// - w: The writer, e.g. os.Stderr
+// - options: Configures the rendering
+//
+// Returns:
+// - *Renderer: The renderer, see Run and Update.
+func New(w io.Writer, options Options) *Renderer {
+	if options.Mode == ModeAuto {
+		options.Mode = ModePlain
+
+		if isTerminal(w) {
+			options.Mode = ModeInteractive
+		}
+	}
+
+	if options.Width <= 0 {
+		options.Width = defaultWidth
+
+		if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+			options.Width = columns
+		}
+	}
+
+	if options.Refresh <= 0 {
+		options.Refresh = defaultRefresh
+	}
+
+	return &Renderer{w: w, options: options}
+}
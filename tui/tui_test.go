@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/ho"
+)
+
+// update rewrites the golden files, e.g. go test ./tui -update.
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// assertGolden compares got to the golden file testdata/name.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		assert.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, string(want), got)
+	}
+}
+
+// stream returns the updates of a synthetic run of 2 initial samples and 4
+// iterations, a trial every 2 seconds, values in nanoseconds.
+func stream() []ho.ProgressUpdate {
+	at := time.Date(2026, 3, 14, 15, 9, 0, 0, time.UTC)
+
+	values := []float64{3.2e6, math.MaxFloat64 / 2, 1.234e6, 2.5e6, 987e3, 1.1e6}
+
+	best := math.MaxFloat64
+
+	updates := make([]ho.ProgressUpdate, 0, len(values)+1)
+
+	for i, v := range values {
+		u := ho.ProgressUpdate{
+			TrialID:              i + 1,
+			Time:                 at.Add(time.Duration(i+1) * 2 * time.Second),
+			Phase:                ho.PhaseInitialSampling,
+			CurrentIteration:     i + 1,
+			TotalIterations:      2,
+			LastExecutionTime:    v,
+			LastDuration:         2 * time.Second,
+			NewBest:              v < best,
+			OverallProgress:      float64(i+1) / float64(len(values)),
+			EvaluationsCompleted: i + 1,
+			EvaluationsPlanned:   len(values),
+		}
+
+		if i >= 2 {
+			u.Phase, u.CurrentIteration, u.TotalIterations = ho.PhaseOptimization, i-1, 4
+		}
+
+		best = math.Min(best, v)
+
+		u.CurrentBestTime = best
+
+		updates = append(updates, u)
+	}
+
+	return append(updates, ho.ProgressUpdate{
+		Time:                 at.Add(12 * time.Second),
+		Phase:                ho.PhaseDone,
+		CurrentBestTime:      best,
+		LastExecutionTime:    math.MaxFloat64,
+		OverallProgress:      1,
+		EvaluationsCompleted: len(values),
+		EvaluationsPlanned:   len(values),
+		TerminationReason:    ho.TerminationCompleted,
+	})
+}
+
+// render renders the stream through Run.
+func render(options Options) string {
+	var buf bytes.Buffer
+
+	updates := make(chan ho.ProgressUpdate, 10)
+
+	for _, u := range stream() {
+		updates <- u
+	}
+
+	close(updates)
+
+	// Refreshes would make the output depend on timing.
+	options.Refresh = time.Hour
+
+	New(&buf, options).Run(updates)
+
+	return buf.String()
+}
+
+func TestRenderer(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		// Buffers aren't terminals.
+		assertGolden(t, "plain.golden", render(Options{Durations: true}))
+		assertGolden(t, "plain_numbers.golden", render(Options{Mode: ModePlain}))
+	})
+
+	t.Run("interactive", func(t *testing.T) {
+		out := render(Options{Mode: ModeInteractive, Durations: true, Width: 100})
+
+		assertGolden(t, "interactive.golden", out)
+
+		assert.Equal(t, len(stream()), strings.Count(out, clearLine))
+		assert.True(t, strings.HasSuffix(out, "\n"))
+	})
+
+	t.Run("narrow", func(t *testing.T) {
+		for _, width := range []int{60, 40, 20, 8} {
+			out := render(Options{Mode: ModeInteractive, Durations: true, Width: width})
+
+			lines := strings.Split(strings.ReplaceAll(out, "\n", ""), clearLine)[1:]
+
+			if !assert.Len(t, lines, len(stream())) {
+				continue
+			}
+
+			for _, line := range lines {
+				assert.LessOrEqual(t, utf8.RuneCountInString(line), width-1, line)
+
+				// The bar goes first, the phase never.
+				if width <= 40 {
+					assert.NotContains(t, line, "[")
+				}
+			}
+
+			assert.Contains(t, lines[3], "Optim")
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		renderer := New(&buf, Options{Mode: ModeInteractive, Width: 80})
+
+		renderer.Update(stream()[0])
+		renderer.Close()
+		renderer.Close()
+
+		assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	})
+}
+
+func TestHumanDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                                      "0s",
+		999:                                    "999ns",
+		1234:                                   "1.23µs",
+		12345678:                               "12.3ms",
+		123456789:                              "123ms",
+		1500 * time.Millisecond:                "1.5s",
+		125*time.Second + 400*time.Millisecond: "2m5s",
+		3*time.Hour + 30*time.Second:           "3h0m30s",
+	} {
+		assert.Equal(t, want, humanDuration(d), d)
+	}
+}
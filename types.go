@@ -1,13 +1,66 @@
 package ho
 
 import (
+	"context"
 	"math/rand"
+	"reflect"
+	"time"
 
 	"golang.org/x/exp/constraints"
 )
 
+//////
+// Const, vars, types.
+//////
+
+// Phases of the optimization process, as reported in ProgressUpdate.Phase and
+// Trial.Phase.
+const (
+	// PhaseInitialSampling is the random sampling phase used to build the
+	// initial Gaussian Process model.
+	PhaseInitialSampling = "InitialSampling"
+
+	// PhaseOptimization is the Bayesian optimization phase.
+	PhaseOptimization = "Optimization"
+
+	// PhaseDone is the phase of the final progress update, sent once the run
+	// ended.
+	PhaseDone = "Done"
+)
+
+// TrialStatus describes the outcome of a single benchmark evaluation.
+type TrialStatus string
+
+const (
+	// TrialCompleted means the benchmark ran successfully and its execution
+	// time was fed to the model.
+	TrialCompleted TrialStatus = "Completed"
+
+	// TrialFailed means the benchmark returned an error. The execution time is
+	// penalized so the model learns to avoid the configuration.
+	TrialFailed TrialStatus = "Failed"
+
+	// TrialSkipped means the benchmark returned ErrSkipTrial. The trial is kept
+	// for bookkeeping only, it never reaches the model nor the best result.
+	TrialSkipped TrialStatus = "Skipped"
+
+	// TrialCanceled means the trial context was canceled before the benchmark
+	// returned, either because TrialTimeout elapsed or because the run context
+	// was canceled. Timed out trials are penalized like failed ones, trials
+	// interrupted by run cancellation never reach the model.
+	TrialCanceled TrialStatus = "Canceled"
+)
+
 // ProgressUpdate represents the current state of the optimization process.
 type ProgressUpdate struct {
+	// TrialID is the ID of the trial that produced this update. It matches
+	// TrialInfo.TrialID as seen by the benchmark and Trial.TrialID in the
+	// result, so everything joins up
+	TrialID int
+
+	// Time is when the update was sent
+	Time time.Time
+
 	// Phase indicates whether we're in initial sampling or optimization phase
 	Phase string
 
@@ -17,28 +70,107 @@ type ProgressUpdate struct {
 	// TotalIterations is the total number of iterations to run
 	TotalIterations int
 
-	// CurrentParams holds the parameter values being tested
+	// CurrentParams holds the parameter values being tested, rounded to ints
 	CurrentParams []int
 
-	// CurrentBestParams holds the best parameters found so far
+	// CurrentBestParams holds the best parameters found so far, rounded to ints
 	CurrentBestParams []int
 
+	// ParamNames holds the parameter names, "param<i>" for unnamed ones, i
+	// being their 0-based index
+	ParamNames []string
+
+	// CurrentValues holds the parameter values being tested, unrounded
+	CurrentValues []float64
+
+	// BestValues holds the best parameters found so far, unrounded, nil if
+	// there's none
+	BestValues []float64
+
+	// CurrentFormatted holds the typed parameters being tested, e.g. enums,
+	// by name, as rendered by ParameterSpec.Format
+	CurrentFormatted map[string]string
+
+	// BestFormatted holds the typed best parameters found so far, as
+	// CurrentFormatted does
+	BestFormatted map[string]string
+
 	// CurrentBestTime holds the best execution time found so far
 	CurrentBestTime float64
 
 	// LastExecutionTime holds the execution time of the last test
 	LastExecutionTime float64
+
+	// LastDuration is the measured wall time of the last test, see
+	// Trial.Duration
+	LastDuration time.Duration
+
+	// NewBest is true if the last test improved the best result
+	NewBest bool
+
+	// InstantaneousRegret holds the regret of the last test against
+	// OptimizationConfig.KnownOptimum, if set
+	InstantaneousRegret float64
+
+	// DriftRatio is the ratio of the last reference measurement to the
+	// baseline, see OptimizationConfig.DriftSentinel. Zero if unset
+	DriftRatio float64
+
+	// FailedTrials is the number of failed trials kept out of the model so
+	// far, see OptimizationConfig.FailedTrials
+	FailedTrials int
+
+	// CandidatesScored is the number of candidates scored to propose the
+	// trial, fewer than NumCandidates if MaxCandidateSelectionTime ran out.
+	// Zero for trials not proposed from scored candidates, e.g. initial
+	// samples, promotions or Hyperband's
+	CandidatesScored int
+
+	// OverallProgress is how far the run is, from 0 to 1, across phases,
+	// unlike CurrentIteration and TotalIterations: the share of
+	// EvaluationsPlanned completed, or of TimeBudget elapsed if further
+	// along. It never decreases, and is 1 in the final update
+	OverallProgress float64
+
+	// EvaluationsCompleted is the number of evaluations completed so far:
+	// trials, replacements included, extra measurements of surprising
+	// trials, see OptimizationConfig.SurpriseRemeasure, and reference
+	// measurements, see OptimizationConfig.DriftSentinel
+	EvaluationsCompleted int
+
+	// EvaluationsPlanned is the number of evaluations the run is expected
+	// to make, completed ones included. It grows as trials are replaced or
+	// measured again
+	EvaluationsPlanned int
+
+	// TerminationReason describes why the run ended. Only set in the final
+	// update, whose phase is PhaseDone
+	TerminationReason TerminationReason
+
+	// TerminationDetail details TerminationReason, see
+	// Result.TerminationDetail. Only set in the final update
+	TerminationDetail string
+
+	// BestConfidence is the 95% confidence interval of the value of the best
+	// parameters, see Result.BestConfidence. Only set in the final update,
+	// if they were measured more than once
+	BestConfidence *ConfidenceInterval
 }
 
 // ParameterRange defines the valid range for a hyperparameter in the optimization process.
 // Each hyperparameter must have a minimum and maximum value to define its search space.
 //
 // Type Parameter:
-//   - T: The numeric type for this parameter range (int64 or float64)
+//   - T: The numeric type for this parameter range (any integer or floating-point type)
 //
 // Fields:
 // - Min: The minimum (inclusive) value for this hyperparameter
 // - Max: The maximum (inclusive) value for this hyperparameter
+// - Name: Optional name, used to bind results to struct fields, see
+// Result.Scan
+// - Step: Optional granularity, values are Min plus a multiple of Step
+// - Type: Optional type of the values, e.g. DurationParameter
+// - Values: Choices of an EnumParameter range
 //
 // Usage:
 //
@@ -56,11 +188,28 @@ type ProgressUpdate struct {
 //
 // Validation:
 // - Min must be less than or equal to Max
+// - Float bounds must be finite, and so must Max - Min
+// - Step must be nonnegative, and finite
 // - The range is inclusive of both Min and Max values
+// - Runs with an invalid range fail with ErrInvalidConfig
 //
 // Warning:
 //   - Using a very large range may result in slower convergence
 //     as the search space becomes too large to explore effectively
+//   - The Gaussian process kernel has a fixed width of 1 in the units of
+//     the range, as models see offsets from Min, not normalized ones: over
+//     ranges far wider than the scale the objective varies on, e.g.
+//     [0, math.MaxUint64], observations barely inform each other, and the
+//     search is close to random. Search such ranges in a rescaled
+//     coordinate instead, e.g. log2 of the value with Transform
+//   - Integer ranges may span the full domain of their type, e.g.
+//     [0, math.MaxUint64]. Values are sampled, perturbed and snapped in
+//     integer arithmetic, so benchmarks and BestParams get exact, in-range
+//     values. The surrogate model sees offsets from Min, so integer values
+//     stay exact while the range spans at most 2^53 steps, and checkpoints
+//     store them as exact integers. Records, exports and Trials hold float64
+//     values, so they can't tell apart values closer than about 2^-53 of
+//     their magnitude, e.g. 2048 near math.MaxUint64.
 type ParameterRange[T constraints.Integer | constraints.Float] struct {
 	// Min defines the minimum allowed value (inclusive) for this hyperparameter.
 	// Example: Min: 1 means the hyperparameter cannot be less than 1
@@ -69,13 +218,61 @@ type ParameterRange[T constraints.Integer | constraints.Float] struct {
 	// Max defines the maximum allowed value (inclusive) for this hyperparameter.
 	// Example: Max: 100 means the hyperparameter cannot exceed 100
 	Max T
+
+	// Prior optionally defines the sampling distribution used for initial
+	// samples and candidate generation, see Prior.
+	// If nil, values are sampled uniformly.
+	Prior Prior
+
+	// Quantization defines the tolerance below which two values are the same
+	// configuration, see Quantization.
+	// If zero, values must match exactly.
+	Quantization Quantization
+
+	// Name optionally names the hyperparameter, e.g. "Workers". Named
+	// parameters can be bound to the struct field of the same name, see
+	// Result.Scan.
+	Name string
+
+	// Step optionally restricts values to Min plus a multiple of Step, e.g.
+	// buffer sizes in 4KB increments. Values are snapped to the nearest step
+	// within the range.
+	// If zero, values are continuous (or any integer for integer types).
+	Step T
+
+	// Type optionally tells what the values stand for, e.g.
+	// DurationParameter for durations in nanoseconds, see DurationRange.
+	// Results render values accordingly, see ParameterSpec.Format.
+	// If empty, the type follows T: IntParameter for integer types,
+	// FloatParameter otherwise.
+	Type ParameterType
+
+	// Values names the choices of an EnumParameter range, values being
+	// indexes into it, see EnumParam.
+	Values []string
+
+	// Transform optionally maps values of the range, the coordinate
+	// searched, to the value the benchmark receives, e.g. math.Exp2 to
+	// search log2(bufferSize) and pass bytes. The model, Trial.Params and
+	// Result.BestParams stay in the searched coordinate, while
+	// Trial.BenchmarkParams and Result.BestBenchmarkParams hold what the
+	// benchmark received, rounded for integer types. Hooks,
+	// ObjectiveTransform and Safety.Metric receive it too.
+	// If nil, the benchmark receives values of the range as is.
+	Transform func(v float64) float64
+
+	// Inverse maps a value the benchmark receives back to the coordinate
+	// searched, e.g. math.Log2. It's required with Transform, and checked
+	// to map values evenly spaced over the range back before the run
+	// starts.
+	Inverse func(v float64) float64
 }
 
 // BenchmarkFunc defines the signature for functions that will be optimized.
 // This function type represents the task whose parameters you want to optimize.
 //
 // Type Parameter:
-//   - T: The numeric type for parameters (int64 or float64)
+//   - T: The numeric type for parameters (any integer or floating-point type)
 //
 // Parameters:
 //   - params: Variable number of numeric parameters representing the hyperparameters
@@ -111,6 +308,166 @@ type ParameterRange[T constraints.Integer | constraints.Float] struct {
 //	})
 type BenchmarkFunc[T constraints.Integer | constraints.Float] func(params ...T) error
 
+// ObjectiveFunc is an alternative to BenchmarkFunc for functions that compute
+// the value to minimize themselves, instead of having their execution time
+// measured, e.g. a latency percentile reported by a load generator, or a
+// synthetic test function (see the benchfuncs subpackage).
+//
+// Parameters:
+// - params: Same as BenchmarkFunc
+//
+// Returns:
+// - float64: The value to minimize (lower is better)
+// - error: Same as BenchmarkFunc
+//
+// Usage example:
+//
+//	objective := ObjectiveFunc[int](func(params ...int) (float64, error) {
+//	    report, err := runLoadTest(params[0], params[1])
+//	    if err != nil {
+//	        return 0, err
+//	    }
+//
+//	    return report.P99Latency.Seconds(), nil
+//	})
+//
+//	result := OptimizeObjective(DefaultConfig(), objective, ranges...)
+type ObjectiveFunc[T constraints.Integer | constraints.Float] func(params ...T) (float64, error)
+
+// ObjectiveFuncCtx is an alternative to ObjectiveFunc that receives a context,
+// see BenchmarkFuncCtx.
+//
+// Parameters:
+// - ctx: Trial context, canceled on timeout or run cancellation
+// - params: Same as BenchmarkFunc
+//
+// Returns:
+// - float64: The value to minimize (lower is better)
+// - error: Same as BenchmarkFunc
+type ObjectiveFuncCtx[T constraints.Integer | constraints.Float] func(ctx context.Context, params ...T) (float64, error)
+
+// ObjectiveFuncWithInfo is an alternative to ObjectiveFunc that also
+// receives metadata about the trial being evaluated, see
+// BenchmarkFuncWithInfo.
+//
+// Parameters:
+// - info: Metadata about the trial being evaluated
+// - params: Same as BenchmarkFunc
+//
+// Returns:
+// - float64: The value to minimize (lower is better)
+// - error: Same as BenchmarkFunc
+//
+// Usage example:
+//
+//	objective := ObjectiveFuncWithInfo[float64](func(info TrialInfo, params ...float64) (float64, error) {
+//	    return train(rand.New(rand.NewSource(info.Seed)), params[0])
+//	})
+//
+//	result := OptimizeObjectiveWithInfo(DefaultConfig(), objective, ranges...)
+type ObjectiveFuncWithInfo[T constraints.Integer | constraints.Float] func(info TrialInfo, params ...T) (float64, error)
+
+// BenchmarkFuncWithInfo is an alternative to BenchmarkFunc that also receives
+// metadata about the trial being evaluated. It's useful to correlate the
+// benchmark's own logs and artifacts with the optimizer's trials.
+//
+// Parameters:
+// - info: Metadata about the trial being evaluated
+// - params: Same as BenchmarkFunc
+//
+// Returns:
+// - error: Same as BenchmarkFunc
+//
+// Usage example:
+//
+//	benchmark := BenchmarkFuncWithInfo[int](func(info TrialInfo, params ...int) error {
+//	    log.Printf("trial=%d phase=%s iteration=%d", info.TrialID, info.Phase, info.Iteration)
+//
+//	    return runWorkload(params[0])
+//	})
+//
+//	result := OptimizeWithInfo(DefaultConfig(), benchmark, ranges...)
+type BenchmarkFuncWithInfo[T constraints.Integer | constraints.Float] func(info TrialInfo, params ...T) error
+
+// BenchmarkFuncCtx is an alternative to BenchmarkFunc that receives a context.
+// The context is derived from the run context, with OptimizationConfig.TrialTimeout
+// applied as deadline, so per-trial timeouts and run cancellation can actually
+// cancel work.
+//
+// Parameters:
+// - ctx: Trial context, canceled on timeout or run cancellation
+// - params: Same as BenchmarkFunc
+//
+// Returns:
+// - error: Same as BenchmarkFunc
+//
+// Usage example:
+//
+//	benchmark := BenchmarkFuncCtx[int](func(ctx context.Context, params ...int) error {
+//	    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	    if err != nil {
+//	        return err
+//	    }
+//
+//	    // ...
+//	})
+//
+//	result := OptimizeWithContext(ctx, DefaultConfig(), benchmark, ranges...)
+//
+// Important notes:
+// - The benchmark should honor ctx; time measurement stops at cancellation
+// either way
+// - Trials canceled by the context are recorded with status TrialCanceled.
+type BenchmarkFuncCtx[T constraints.Integer | constraints.Float] func(ctx context.Context, params ...T) error
+
+// trialFunc is the internal benchmark signature every exported benchmark
+// function type is adapted to.
+type trialFunc[T constraints.Integer | constraints.Float] func(ctx context.Context, info TrialInfo, params ...T) error
+
+// TrialInfo carries metadata about a trial.
+type TrialInfo struct {
+	// TrialID uniquely identifies the trial within a run. IDs start at 1 and
+	// increase monotonically in evaluation order.
+	TrialID int
+
+	// Phase is the phase in which the trial runs, see PhaseInitialSampling and
+	// PhaseOptimization.
+	Phase string
+
+	// Iteration is the (1-based) iteration of the phase the trial belongs to.
+	// Replacement evaluations share the iteration of the trial they replace.
+	Iteration int
+
+	// Retry is true if the trial is a replacement for a previous trial of the
+	// same iteration, e.g. one that was skipped.
+	Retry bool
+
+	// Seed is derived from the seed of the run (see Result.Seed) and TrialID,
+	// for the benchmark's own randomness, e.g. to generate its workload. A
+	// replay of the trial sees the same, see Result.ReplayTrial.
+	Seed int64
+
+	// SeedIndex is the (1-based) index of Seed among the seeds of the trial,
+	// see OptimizationConfig.SeedsPerTrial. It's 0 if seeds aren't averaged,
+	// Seed then being the seed of the trial.
+	SeedIndex int
+
+	// RNGPosition is the number of values drawn from the random number
+	// generator of the run, seeded with Result.Seed, before the parameters of
+	// the trial were generated. Trials evaluated concurrently draw
+	// concurrently, so it's only exact for serial evaluations.
+	RNGPosition uint64
+
+	// Budget is the resource budget the trial is evaluated with, e.g. a
+	// number of epochs, in a Hyperband run, see OptimizeHyperband. It's 0
+	// otherwise.
+	Budget float64
+
+	// StudyVersion is the version of the ask/tell study the trial belongs
+	// to, see Optimizer.StudyVersion. It's 0 outside ask/tell.
+	StudyVersion uint64
+}
+
 // AcquisitionFunc defines the signature for acquisition functions used in the
 // Bayesian optimization process. These functions help decide which points in the
 // parameter space should be evaluated next.
@@ -123,17 +480,21 @@ type BenchmarkFunc[T constraints.Integer | constraints.Float] func(params ...T)
 // Returns:
 // - float64: Acquisition value (lower values indicate more promising points)
 //
-// Built-in acquisition functions:
-// - UCB: Upper Confidence Bound
-// - ProbabilityOfImprovement: Probability of finding better value
-// - ExpectedImprovement: Expected magnitude of improvement
+// Built-in acquisition functions (see Acquisitions for their direction):
+// - LowerConfidenceBound: Confidence bound for minimization (UCB is a
+// deprecated alias)
+// - UpperConfidenceBound: Literature UCB, for maximization
+// - ProbabilityOfImprovement, ProbabilityOfImprovementMin: Probability of
+// finding better value
+// - ExpectedImprovement, ExpectedImprovementMin: Expected magnitude of
+// improvement
 // - ThompsonSampling: Random sampling from posterior
 //
 // Usage example:
 //
 //	// Example 1: Using a built-in acquisition function
 //	config := OptimizationConfig{
-//	    AcquisitionFunc: UCB,
+//	    AcquisitionFunc: LowerConfidenceBound,
 //	    AcqParams: AcquisitionParams{
 //	        Beta: 2.0,
 //	    },
@@ -150,17 +511,101 @@ type BenchmarkFunc[T constraints.Integer | constraints.Float] func(params ...T)
 // - Should handle edge cases (zero variance, extreme means)
 // - Must be thread-safe
 // - Should be deterministic
-// - Should return lower values for more promising points
+// - Should return lower values for more promising points, unless
+// OptimizationConfig.AcquisitionDirection is MaximizeAcquisition
 // - Must properly use parameters from AcquisitionParams.
 type AcquisitionFunc func(mean, variance float64, params AcquisitionParams) float64
 
+// AcquisitionDirection tells whether lower or higher acquisition values
+// indicate more promising points.
+type AcquisitionDirection string
+
+const (
+	// MinimizeAcquisition means lower acquisition values are better. It's the
+	// package's convention, followed by all built-ins not suffixed with Min
+	// (except UpperConfidenceBound).
+	MinimizeAcquisition AcquisitionDirection = "Minimize"
+
+	// MaximizeAcquisition means higher acquisition values are better, as in
+	// most of the literature, e.g. ExpectedImprovementMin.
+	MaximizeAcquisition AcquisitionDirection = "Maximize"
+)
+
+// NonFinitePolicy determines how non-finite objective values, e.g. a NaN
+// from a division by zero in the metric, are handled, see
+// OptimizationConfig.NonFiniteValues.
+type NonFinitePolicy string
+
+const (
+	// NonFinitePenalize records the trial as failed, penalized as failed
+	// trials are, so the model learns to avoid its region.
+	NonFinitePenalize NonFinitePolicy = "Penalize"
+
+	// NonFiniteSkip records the trial as skipped, as if the objective
+	// returned ErrSkipTrial, e.g. when non-finite values are flukes unrelated
+	// to the parameters.
+	NonFiniteSkip NonFinitePolicy = "Skip"
+)
+
+// Acquisition is an acquisition function along with its direction, so the
+// optimizer always selects the right candidate. See Acquisitions and
+// LookupAcquisition for the built-ins.
+type Acquisition struct {
+	// Name of the acquisition function.
+	Name string
+
+	// Func is the acquisition function.
+	Func AcquisitionFunc
+
+	// Direction tells whether lower or higher values are better.
+	Direction AcquisitionDirection
+
+	// Validate optionally checks the parameters the function needs, see
+	// OptimizationConfig.Validate. It may fill in defaults for unset
+	// parameters, and returns a descriptive error for invalid ones.
+	// If nil, any parameters are accepted.
+	Validate func(params *AcquisitionParams) error
+}
+
+// AcquisitionFuncEx is an extended AcquisitionFunc that also receives the
+// coordinates of the candidate being scored, allowing location-aware
+// strategies, e.g. penalizing candidates near already-evaluated points, or
+// encoding domain preferences like "prefer smaller buffer sizes when
+// predictions tie".
+//
+// Parameters:
+// - candidate: Coordinates of the candidate, offsets from each range's Min
+// - mean, variance, params: Same as AcquisitionFunc
+//
+// Returns:
+// - float64: Acquisition value (lower values indicate more promising points)
+//
+// Usage example:
+//
+//	// Penalize candidates closer than 5 to any evaluated point. Both are
+//	// offsets from Min, so distances are the same as in the parameter space.
+//	config.AcquisitionFuncEx = func(candidate []float64, mean, variance float64, params AcquisitionParams) float64 {
+//	    for _, point := range params.EvaluatedPoints {
+//	        if math.Abs(point[0]-candidate[0]) < 5 {
+//	            return math.MaxFloat64 / 2
+//	        }
+//	    }
+//
+//	    return LowerConfidenceBound(mean, variance, params)
+//	}
+//
+// Important notes:
+// - Same implementation notes as AcquisitionFunc apply
+// - Must not modify candidate nor params.EvaluatedPoints.
+type AcquisitionFuncEx func(candidate []float64, mean, variance float64, params AcquisitionParams) float64
+
 // AcquisitionParams holds parameters used by different acquisition functions to make decisions
 // about which points to sample next in the optimization process. Each acquisition function
 // may use different parameters to balance between exploring new areas (exploration) and
 // focusing on areas known to be good (exploitation).
 type AcquisitionParams struct {
-	// Beta controls the exploration-exploitation trade-off in the Upper Confidence Bound (UCB)
-	// acquisition function.
+	// Beta controls the exploration-exploitation trade-off in the confidence bound
+	// acquisition functions (LowerConfidenceBound, UpperConfidenceBound).
 	// - Higher values (e.g., 3.0 or 5.0) encourage more exploration of uncertain areas
 	// - Lower values (e.g., 0.1 or 0.5) focus more on exploiting known good areas
 	// Typical values range from 0.1 to 5.0, with 2.0 being a good default.
@@ -174,18 +619,15 @@ type AcquisitionParams struct {
 	// Typical values range from 0.01 to 0.1.
 	Xi float64
 
-	// BestSoFar keeps track of the best (lowest) execution time we've seen so far.
-	// This is used by PI and EI to determine how much improvement a new point might offer.
+	// BestSoFar is the best (lowest) value observed so far, which PI and EI
+	// compare predictions to.
 	//
-	// Initial value:
-	// - MUST be set to math.MaxFloat64 in your configuration
-	// - Will be automatically updated by the optimizer during the optimization process
-	// - The units are in nanoseconds (same as time.Duration)
+	// The optimizer manages it: it's set before candidates are scored, from
+	// the first successful trial on. Until then, candidates are scored by
+	// LowerConfidenceBound, as there's no value to improve on yet.
 	//
-	// Example:
-	//     params := AcquisitionParams{
-	//         BestSoFar: math.MaxFloat64,  // Required initial value
-	//     }
+	// Deprecated: It's read by acquisition functions only, any value set in
+	// the configuration is ignored.
 	BestSoFar float64
 
 	// RandomState is the random number generator used by Thompson Sampling.
@@ -204,6 +646,35 @@ type AcquisitionParams struct {
 	// - Do NOT use a nil RandomState
 	// - Do NOT share RandomState between different optimization runs
 	RandomState *rand.Rand
+
+	// EvaluatedPoints holds the coordinates of the points evaluated so far, as
+	// fed to the Gaussian Process model, i.e. each value is an offset from its
+	// range's Min. It's automatically updated by the
+	// optimizer before each iteration.
+	//
+	// Warning:
+	// - Read-only, do NOT modify it
+	EvaluatedPoints [][]float64
+
+	// Iteration is the current (1-based) iteration of the optimization phase.
+	// It's automatically updated by the optimizer, and used by GPUCB.
+	Iteration int
+
+	// Dimensions is the number of dimensions of the search space. It's
+	// automatically set by the optimizer, and used by GPUCB.
+	Dimensions int
+
+	// Delta (δ) is the confidence parameter of GPUCB: its regret bounds hold
+	// with probability 1 - δ. Smaller values make beta grow, encouraging more
+	// exploration.
+	// Typical values range from 0.01 to 0.2. If 0, defaults to 0.1.
+	Delta float64
+
+	// IncumbentMean is the model's predicted mean at the best observed point.
+	// Unlike BestSoFar, it isn't optimistically biased by a lucky noisy
+	// measurement. It's automatically updated by the optimizer before each
+	// iteration, and used by NoisyExpectedImprovement.
+	IncumbentMean float64
 }
 
 // OptimizationConfig holds all configuration parameters for the Bayesian optimization process.
@@ -235,7 +706,6 @@ type AcquisitionParams struct {
 //	    // Configure acquisition function parameters
 //	    AcqParams: AcquisitionParams{
 //	        Xi: 0.01,
-//	        BestSoFar: math.MaxFloat64,
 //	        RandomState: rand.New(rand.NewSource(time.Now().UnixNano())),
 //	    },
 //	}
@@ -243,7 +713,8 @@ type AcquisitionParams struct {
 // Default values recommendations:
 // - Iterations: 50 (increase for more thorough optimization)
 // - InitialSamples: 10 (increase for more stable initial model)
-// - NumCandidates: 50-100 (increase for more thorough search per iteration)
+// - NumCandidates: 0, derived from the number of dimensions (set it for more
+// thorough search per iteration, or faster iterations)
 //
 // Performance impact notes:
 // - Higher Iterations = Better results but longer total runtime
@@ -268,13 +739,33 @@ type OptimizationConfig struct {
 	// NumCandidates determines how many random candidates to consider in each
 	// iteration before selecting the best one to evaluate.
 	// Higher values = more thorough search but slower iterations.
-	// Recommended range: 50-500
+	// If 0, 100 per parameter range, at most 2000, so candidates cover the
+	// space whatever its dimensions, see Result.NumCandidates. The share of
+	// candidates perturbing good points, see CandidateMix, scales with it.
 	NumCandidates int
 
+	// MaxCandidateSelectionTime caps the time spent scoring candidates per
+	// iteration, e.g. for a big model and many candidates. Candidates are
+	// predicted a few at a time, and once the time is up the best of those
+	// scored so far is selected, at least a few always being scored. See
+	// ProgressUpdate.CandidatesScored.
+	// If 0, every candidate is scored.
+	MaxCandidateSelectionTime time.Duration
+
 	// AcquisitionFunc determines the strategy for selecting the next point to
 	// evaluate. See AcquisitionFunc type for built-in options.
 	AcquisitionFunc AcquisitionFunc
 
+	// AcquisitionFuncEx is an optional location-aware acquisition function.
+	// If set, it's used instead of AcquisitionFunc.
+	AcquisitionFuncEx AcquisitionFuncEx
+
+	// AcquisitionDirection tells whether lower or higher acquisition values are
+	// better, for both AcquisitionFunc and AcquisitionFuncEx. Use
+	// SetAcquisition to set it along with the function.
+	// If empty, lower values are better (MinimizeAcquisition).
+	AcquisitionDirection AcquisitionDirection
+
 	// AcqParams holds the parameters for the acquisition function.
 	// Must be properly initialized based on the chosen AcquisitionFunc.
 	AcqParams AcquisitionParams
@@ -282,4 +773,631 @@ type OptimizationConfig struct {
 	// ProgressChan is used to send progress updates during optimization
 	// If nil, no updates will be sent
 	ProgressChan chan<- ProgressUpdate
+
+	// MaxSkipRetries determines how many replacement evaluations may be drawn
+	// when the benchmark returns ErrSkipTrial for the same trial slot, so the
+	// budget isn't silently shortened by skipped trials.
+	// If 0, skipped trials are not replaced.
+	MaxSkipRetries int
+
+	// NonFiniteValues determines how NaN and infinite objective values are
+	// handled. The original value is kept in Trial.RawValue, and never
+	// reaches the model nor the best result.
+	// If empty, they're penalized (NonFinitePenalize).
+	NonFiniteValues NonFinitePolicy
+
+	// ObjectiveTransform maps what was measured to what is minimized, e.g.
+	// to subtract a known overhead, convert a throughput to a latency, or
+	// clip at a ceiling. It receives the value of each completed trial,
+	// after re-measurements and drift correction, and its parameters, as
+	// floats. The measured value is kept in Trial.MeasuredValue. An error, or
+	// a non-finite value, fails the trial.
+	// If nil, measured values are minimized as is.
+	ObjectiveTransform func(measured float64, params []float64) (float64, error)
+
+	// SeedsPerTrial is the number of seeds each trial is evaluated with, for
+	// objectives whose value depends on their randomness, e.g. a training
+	// run: the benchmark is invoked once per seed, each seeing one as
+	// TrialInfo.Seed (see EvaluationSeeds), and the values are combined as
+	// SeedAggregation determines, so the optimum holds up whatever the
+	// seed. The values of the seeds are kept in Trial.SeedValues. Use
+	// OptimizeWithInfo or OptimizeObjectiveWithInfo for the benchmark to
+	// see the seeds. Not supported by the ask/tell Optimizer.
+	// If 0 or 1, trials are evaluated once, with the seed of the trial.
+	SeedsPerTrial int
+
+	// SeedAggregation determines how the values of the seeds of a trial
+	// are combined, see SeedsPerTrial.
+	// If empty, their mean is minimized (SeedMean).
+	SeedAggregation SeedAggregation
+
+	// FailedTrials determines how failed trials, timed out ones included,
+	// are modeled. Either way, they're recorded in Result.Trials with their
+	// penalty.
+	// If empty, they're kept out of the model, and candidates are scored as
+	// if they yielded the worst observed value plus a margin
+	// (FailureSubstitute).
+	FailedTrials FailurePolicy
+
+	// TrialTimeout is the deadline applied to the context of each trial. Only
+	// benchmarks that receive a context (see BenchmarkFuncCtx) can honor it.
+	// If 0, trials have no deadline.
+	TrialTimeout time.Duration
+
+	// CooldownBetweenTrials is the wait between benchmark invocations, so
+	// trials heating up the system under test, e.g. its caches or connection
+	// pools, don't contaminate the next ones. It isn't part of trial
+	// durations, but counts against TimeBudget. Canceling the run context
+	// cuts it short.
+	// If 0, trials run back-to-back.
+	CooldownBetweenTrials time.Duration
+
+	// CooldownWithinTrials is the wait between the measurements of a trial,
+	// see SurpriseRemeasure.
+	// If 0, CooldownBetweenTrials is used.
+	CooldownWithinTrials time.Duration
+
+	// Setup is an optional hook run before the benchmark, e.g. to warm up
+	// or reset the system under test. It isn't part of trial durations, nor
+	// subject to TrialTimeout. If it fails, the benchmark isn't invoked and
+	// the trial fails with its error, or is skipped if it's ErrSkipTrial.
+	// Params are the parameters of the trial, as float64. Reference
+	// measurements of the DriftSentinel run it too, in PhaseSentinel.
+	Setup func(info TrialInfo, params ...float64) error
+
+	// Teardown is an optional hook run after the benchmark, e.g. to release
+	// what Setup acquired. It runs whenever Setup was due, even if Setup or
+	// the benchmark failed, timed out or panicked, and isn't part of trial
+	// durations.
+	Teardown func(info TrialInfo)
+
+	// HookScope determines whether Setup and Teardown run once per trial,
+	// around all of its measurements, or around each of them, see
+	// SurpriseRemeasure. They don't run for the ask/tell Optimizer, nor for
+	// Result.ReplayTrial.
+	// If empty, they run once per trial (HooksPerTrial).
+	HookScope HookScope
+
+	// RateLimiter is waited for before each benchmark invocation, in both
+	// phases, e.g. to share a target with other runs, see RateLimiter.
+	// Concurrent initial samples wait for it too, which serializes bursts.
+	// The wait isn't part of trial durations, see Trial.RateLimitWait.
+	// If nil, invocations aren't limited.
+	RateLimiter RateLimiter
+
+	// DriftSentinel measures a reference configuration every few trials, to
+	// detect environmental drift, e.g. the system slowing down halfway
+	// through the run, see DriftSentinel.
+	// If nil, drift isn't monitored.
+	DriftSentinel *DriftSentinel
+
+	// LoadGate makes each trial wait for the system to be ready, e.g. for
+	// the load of a shared runner to go down, see LoadGate.
+	// If nil, trials start right away.
+	LoadGate *LoadGate
+
+	// TimeBudget caps the wall time of the run: no trial starts once it
+	// elapsed, and the run ends with TerminationTimeBudget. Running trials
+	// aren't interrupted. Time spent paused (see RunHandle.Pause) doesn't
+	// count, unless CountPausedTime is set.
+	// If 0, the run isn't limited in time.
+	TimeBudget time.Duration
+
+	// CountPausedTime determines whether time spent paused counts against
+	// TimeBudget, e.g. when the budget is a hard deadline.
+	CountPausedTime bool
+
+	// MaxConcurrentEvaluations determines how many benchmarks may run
+	// concurrently during the initial sampling phase, whose points are
+	// independent of one another. The optimization phase only begins once all
+	// initial samples are in.
+	// If 0 or 1, initial samples are evaluated serially.
+	// Warning: the benchmark function must be thread-safe if greater than 1.
+	MaxConcurrentEvaluations int
+
+	// CandidateFilter is an optional cheap veto applied to every generated
+	// candidate before it's scored, e.g. to temporarily avoid large worker
+	// counts while the cluster is busy. It receives the candidate coordinates,
+	// one value per parameter range, and returns false to reject it.
+	// Rejected candidates are re-sampled, up to a cap, so NumCandidates
+	// candidates are still scored when possible. If the filter rejects
+	// everything, unfiltered candidates are scored and a warning is recorded
+	// in Result.Warnings.
+	// If nil, no candidate is rejected.
+	CandidateFilter func(candidate []float64) bool
+
+	// FilterInitialSamples determines whether CandidateFilter also applies to
+	// initial samples.
+	FilterInitialSamples bool
+
+	// ExclusionZones declares rectangular sub-regions of the search space that
+	// must never be evaluated, e.g. buffer sizes that OOM the host. Unlike
+	// CandidateFilter, zones are declarative, always apply (initial samples
+	// included), and are never bypassed by a fallback.
+	// The run fails with ErrInvalidConfig if zones cover the whole space,
+	// which is decided exactly, however small the region they leave free.
+	ExclusionZones []Box
+
+	// Constraints declares relations between parameters every evaluated
+	// point satisfies, e.g. LessOrEqual(minConns, maxConns). Like
+	// ExclusionZones, they always apply and are never bypassed: points are
+	// drawn, or perturbed, again until they satisfy them (rejection), so
+	// initial samples are uniform over the feasible region. The run fails
+	// with ErrInvalidConfig if no point of the space satisfies them, or if
+	// they reject nearly all of it, see Constraint.
+	Constraints []Constraint
+
+	// CandidateMix determines how candidates are generated during the
+	// optimization phase. By default, all candidates are uniform random points.
+	CandidateMix CandidateMix
+
+	// KnownOptimum is the known optimum of the function being optimized, e.g.
+	// when testing optimizer settings on synthetic functions (see the
+	// benchfuncs subpackage). If set, the result includes regret curves, and
+	// progress updates carry instantaneous regret.
+	// If nil, no regret is computed.
+	KnownOptimum *KnownOptimum
+
+	// CacheEvaluations determines whether configurations already evaluated
+	// are reused instead of benchmarked again, and deprioritized during
+	// candidate selection. Configurations are matched according to each
+	// parameter range's Quantization. Evaluated points, rounded to the
+	// lattice of integer and stepped ranges, are deprioritized either way.
+	// Skipped and canceled trials are never reused.
+	CacheEvaluations bool
+
+	// Seed seeds the random number generator drawing parameters, making runs
+	// reproducible given a deterministic benchmark and no concurrency.
+	// If zero, the current time is used.
+	Seed int64
+
+	// LeaseTimeout is how long the result of a suggestion handed out by
+	// Optimizer.Ask is awaited. A suggestion not told in time is abandoned:
+	// it stops diversifying the next suggestions, and no longer counts as
+	// pending, e.g. an initial sample is drawn again.
+	// Only applies to ask/tell, see Optimizer.
+	// If 0, suggestions never expire.
+	LeaseTimeout time.Duration
+
+	// AcceptLateTells determines whether Optimizer.Tell accepts the result of
+	// an abandoned suggestion, recording it as usual. If false, it's rejected
+	// with ErrLeaseExpired.
+	AcceptLateTells bool
+
+	// Notifications configures webhook notifications on new bests and run
+	// completion, see Notifications.
+	// If nil, no notification is sent.
+	Notifications *Notifications
+
+	// Trackers receive the run events, e.g. to record runs in an experiment
+	// tracking service, see Tracker.
+	Trackers []Tracker
+
+	// Checkpoint configures periodic checkpoints of the run state, so an
+	// interrupted run doesn't lose its evaluations, see Checkpoint.
+	// If nil, no checkpoint is written.
+	Checkpoint *Checkpoint
+
+	// Storage durably records the run as a study, trial by trial, e.g. so
+	// dashboards can query it while it runs, see Storage and
+	// Result.StudyID.
+	// If nil, the run isn't stored.
+	Storage Storage
+
+	// SurpriseRemeasure configures the re-measurement of trials whose value
+	// is too far from the prediction of the model, as likely measurement
+	// glitches, see SurpriseRemeasure.
+	// If nil, trials are measured once.
+	SurpriseRemeasure *SurpriseRemeasure
+
+	// IncumbentReevaluation re-evaluates the best parameters every few
+	// iterations, so a lucky measurement regresses to its mean instead of
+	// staying the best, see IncumbentReevaluation.
+	// If nil, the best parameters are those of the best single measurement.
+	IncumbentReevaluation *IncumbentReevaluation
+
+	// DebugCapture captures what the model believed at each iteration: the
+	// selected candidate, the runners-up and the kernel hyperparameters, see
+	// DebugCapture.
+	// If nil, nothing is captured.
+	DebugCapture *DebugCapture
+
+	// ProfileBest re-runs the best parameters once the run ended, with CPU
+	// and optionally heap profiling, to find out why they're fast, see
+	// ProfileBest.
+	// If nil, nothing is profiled.
+	ProfileBest *ProfileBest
+
+	// Safety restricts the exploration to points predicted to stay below a
+	// hard limit, on the objective or an auxiliary metric, e.g. a latency SLO
+	// of a shared staging system, see Safety.
+	// If nil, any point of the search space may be evaluated.
+	Safety *Safety
+
+	// Study groups the run with related runs, e.g. to compare them, see
+	// Study. The run is stored in Study.Storage unless Storage is set, and
+	// the parameter ranges must match Study.Space.
+	// If nil, the run belongs to no study.
+	Study *Study
+
+	// Tags label the run, e.g. {"env": "staging"}, in StudyMeta.Tags, along
+	// with those of the study, which they override.
+	// If nil, the run is labeled with the tags of the study only.
+	Tags map[string]string
+
+	// WarmStart holds prior observations, e.g. from a previous study (see
+	// ImportOptunaJSON), fed to the model before the first trial. They steer
+	// candidate selection, but aren't trials: they're neither in
+	// Result.Trials nor eligible as the best result.
+	// If nil, the model starts empty.
+	WarmStart []Observation
+
+	// Surrogate creates the model of each run, e.g. a RandomForest for large,
+	// mostly discrete spaces, see SurrogateModel.
+	// If nil, a Gaussian process is used.
+	Surrogate func() SurrogateModel
+
+	// OutputTransform transforms objective values before they're fed to the
+	// model, e.g. OutputRankGauss for values spanning many orders of
+	// magnitude or including failure penalties. Acquisition functions operate
+	// on transformed values, AcquisitionParams.BestSoFar included, while
+	// predictions are transformed back, e.g. by Result.Predict.
+	// If empty, values are fed as they are (OutputRaw).
+	OutputTransform OutputTransform
+}
+
+// Observation is a prior observation, see OptimizationConfig.WarmStart.
+type Observation struct {
+	// Params holds the parameter values, one per parameter range.
+	Params []float64
+
+	// Value is the observed value, lower is better.
+	Value float64
+
+	// Weight is the confidence in Value, see Trial.Weight. If 0, 1 is used.
+	Weight float64
+}
+
+// KnownOptimum is the known optimum of the function being optimized.
+type KnownOptimum struct {
+	// Value is the optimum (minimum) value.
+	Value float64
+
+	// Location optionally holds the coordinates of the optimum, one value per
+	// parameter range. If set, the distance from the best parameters found to
+	// it is computed.
+	Location []float64
+}
+
+// Regret holds the regret of a run against a known optimum, per evaluation.
+// Skipped trials aren't evaluations, so they're not included.
+type Regret struct {
+	// Simple holds the simple regret after each evaluation: best value found
+	// so far minus the optimum.
+	Simple []float64
+
+	// Cumulative holds the cumulative regret after each evaluation: sum of
+	// the instantaneous regrets (value minus the optimum) so far.
+	Cumulative []float64
+
+	// Final is the simple regret at the end of the run.
+	Final float64
+
+	// Distance is the Euclidean distance from the best parameters found to
+	// the optimum location. Only meaningful if KnownOptimum.Location is set.
+	Distance float64
+}
+
+// CandidateMix determines the share of candidates generated as Gaussian
+// perturbations of good points, instead of uniform random points, which
+// ignore where the good region is.
+//
+// Usage example:
+//
+//	// 50% uniform, 30% around the incumbent, 20% around the top 5 points.
+//	config.CandidateMix = CandidateMix{
+//	    Incumbent: 0.3,
+//	    TopK:      0.2,
+//	    TopKCount: 5,
+//	}
+//
+// Important notes:
+// - The remainder (1 - Incumbent - TopK) is uniform random points
+// - Perturbed integer dimensions are rounded, all dimensions are clipped
+// - Perturbations never land inside exclusion zones.
+type CandidateMix struct {
+	// Incumbent is the share of candidates perturbed around the best
+	// parameters found so far.
+	Incumbent float64
+
+	// TopK is the share of candidates perturbed around a random point among
+	// the TopKCount best historical ones.
+	TopK float64
+
+	// TopKCount is the number of best historical points considered for TopK
+	// candidates.
+	// If 0, defaults to 5.
+	TopKCount int
+
+	// Scale is the standard deviation of perturbations, as a fraction of each
+	// dimension's range width. It shrinks linearly over iterations, down to
+	// 10% of its value.
+	// If 0, defaults to 0.1.
+	Scale float64
+}
+
+// TerminationReason describes why an optimization run ended, see
+// Result.TerminationDetail for the specifics.
+//
+// Important notes:
+// - There's one reason per way a run can end. The optimizer has no plateau
+// detection, target value or failure limit, so there's no
+// EarlyStoppingPlateau, TargetReached or FailureLimit reason: benchmarks
+// implementing such a policy return ErrStopOptimization, and the run ends
+// with TerminationBenchmarkRequestedStop, the error as detail.
+type TerminationReason string
+
+const (
+	// TerminationCompleted means the whole budget was evaluated.
+	TerminationCompleted TerminationReason = "Completed"
+
+	// TerminationBenchmarkRequestedStop means the benchmark returned
+	// ErrStopOptimization.
+	TerminationBenchmarkRequestedStop TerminationReason = "BenchmarkRequestedStop"
+
+	// TerminationContextCanceled means the run context was canceled.
+	TerminationContextCanceled TerminationReason = "ContextCanceled"
+
+	// TerminationStopped means the run was stopped with RunHandle.Stop, or
+	// an ask/tell Optimizer closed before its budget was told.
+	TerminationStopped TerminationReason = "Stopped"
+
+	// TerminationTimeBudget means OptimizationConfig.TimeBudget elapsed.
+	TerminationTimeBudget TerminationReason = "TimeBudget"
+
+	// TerminationInvalidConfig means the run didn't start because of an
+	// invalid configuration, see ErrInvalidConfig.
+	TerminationInvalidConfig TerminationReason = "InvalidConfig"
+
+	// TerminationSafetyViolation means a trial violated the safety limit,
+	// see Safety.AbortOnViolation.
+	TerminationSafetyViolation TerminationReason = "SafetyViolation"
+)
+
+// Trial is the record of a single benchmark evaluation.
+type Trial[T constraints.Integer | constraints.Float] struct {
+	// TrialInfo holds the trial metadata, as passed to the benchmark.
+	TrialInfo
+
+	// Params holds the parameter values that were tested.
+	Params []T
+
+	// BenchmarkParams holds the values the benchmark received, if a range
+	// is transformed, see ParameterRange.Transform. Nil otherwise.
+	BenchmarkParams []T
+
+	// ExecutionTime is the measured execution time in nanoseconds, or the
+	// objective value for OptimizeObjective, including the penalty for failed
+	// trials.
+	ExecutionTime float64
+
+	// RawValue is the non-finite value returned by the objective, e.g. NaN,
+	// before the trial was penalized or skipped, see
+	// OptimizationConfig.NonFiniteValues. Zero otherwise.
+	RawValue float64
+
+	// MeasuredValue is the value measured, before
+	// OptimizationConfig.ObjectiveTransform produced ExecutionTime. Nil if
+	// it wasn't transformed.
+	MeasuredValue *float64
+
+	// Duration is the measured wall time of the trial, without any penalty.
+	// For canceled trials, measurement stops at cancellation.
+	Duration time.Duration
+
+	// DriftCorrection is the drift ratio ExecutionTime was divided by, see
+	// DriftCorrect. Zero if it wasn't corrected.
+	DriftCorrection float64
+
+	// RateLimitWait is the time spent waiting for the rate limiter before
+	// invoking the benchmark, not part of Duration, see
+	// OptimizationConfig.RateLimiter.
+	RateLimitWait time.Duration
+
+	// StartedAt is when the trial started, or when the suggestion was handed
+	// out for ask/tell.
+	StartedAt time.Time
+
+	// Regret is the instantaneous regret of the trial (ExecutionTime minus the
+	// optimum) against OptimizationConfig.KnownOptimum, if set.
+	Regret float64
+
+	// Status is the outcome of the trial.
+	Status TrialStatus
+
+	// Cached is true if the trial reused the outcome of a previous trial
+	// instead of invoking the benchmark, see
+	// OptimizationConfig.CacheEvaluations.
+	Cached bool
+
+	// UnderLoad is true if the system still wasn't ready when the trial
+	// started, see OptimizationConfig.LoadGate.
+	UnderLoad bool
+
+	// Surprising is true if the measured value was too far from the
+	// prediction of the model, and the trial was measured again, see
+	// OptimizationConfig.SurpriseRemeasure.
+	Surprising bool
+
+	// Measurements holds the values measured for a surprising trial, the
+	// first one included. ExecutionTime is their median, and Duration
+	// includes every measurement.
+	Measurements []float64
+
+	// SeedValues holds the values measured with each seed, see
+	// OptimizationConfig.SeedsPerTrial, of the last measurement for a
+	// surprising trial. ExecutionTime combines them.
+	SeedValues []float64
+
+	// Weight is the confidence in ExecutionTime the trial is fed to the
+	// model with, relative to a single measurement under normal conditions:
+	// the number of measurements, halved if the trial is surprising, and
+	// quartered if it ran under load. 0 if unset, e.g. for failed or reused
+	// trials, which are fed with weight 1. See WeightedUpdater.
+	Weight float64
+
+	// SafetyMetric is the value of the safety metric, see Safety.Metric. Nil
+	// if it wasn't measured.
+	SafetyMetric *float64
+
+	// SafetyViolation is true if the trial violated the safety limit, see
+	// OptimizationConfig.Safety.
+	SafetyViolation bool
+
+	// Err is the error returned by the benchmark function, if any.
+	Err error
+}
+
+// Result holds the outcome of an optimization run.
+type Result[T constraints.Integer | constraints.Float] struct {
+	// BestParams holds the best parameters found (in same order as hypers).
+	BestParams []T
+
+	// BestBenchmarkParams holds the values the benchmark received for
+	// BestParams, if a range is transformed, see ParameterRange.Transform.
+	// Nil otherwise.
+	BestBenchmarkParams []T
+
+	// BestTime holds the best execution time found, in nanoseconds, or the
+	// best objective value for OptimizeObjective.
+	BestTime float64
+
+	// Trials holds every evaluation performed, in completion order, including
+	// skipped ones.
+	Trials []Trial[T]
+
+	// TerminationReason describes why the run ended.
+	TerminationReason TerminationReason
+
+	// TerminationDetail details TerminationReason in a human-readable form,
+	// e.g. the error the benchmark requested a stop with, or the time budget
+	// that elapsed.
+	TerminationDetail string
+
+	// Err holds the error that terminated the run early, if any. For example,
+	// the error returned by the benchmark when it requested a stop, with any
+	// wrapped error preserved.
+	Err error
+
+	// Warnings holds non-fatal issues found during the run, e.g. a
+	// CandidateFilter that rejected every candidate.
+	Warnings []string
+
+	// Drift holds the measurements of the reference configuration, baseline
+	// first, if OptimizationConfig.DriftSentinel is set.
+	Drift []DriftMeasurement
+
+	// Regret holds the regret curves against OptimizationConfig.KnownOptimum.
+	// Nil if KnownOptimum is unset.
+	Regret *Regret
+
+	// SafetyViolations holds the IDs of the trials that violated the safety
+	// limit, in order, see OptimizationConfig.Safety.
+	SafetyViolations []int
+
+	// Brackets holds the statistics of the brackets of a Hyperband run, in
+	// order, see OptimizeHyperband.
+	Brackets []BracketStats
+
+	// ParamNames holds the names of the parameter ranges, in the same order
+	// as BestParams. Unnamed ranges have an empty name.
+	ParamNames []string
+
+	// NotificationFailures is the number of webhook notifications that
+	// couldn't be delivered, see OptimizationConfig.Notifications.
+	NotificationFailures int
+
+	// Seed is the seed of the random number generator of the run:
+	// OptimizationConfig.Seed, or the one drawn from the clock if unset.
+	Seed int64
+
+	// StudyID identifies the study of the run in OptimizationConfig.Storage,
+	// e.g. to resume it with ResumeFromStorage. Empty if the run isn't
+	// stored.
+	StudyID string
+
+	// NumCandidates is the number of candidates scored per iteration:
+	// OptimizationConfig.NumCandidates, or the one derived from the number
+	// of parameter ranges if unset.
+	NumCandidates int
+
+	// Embedding maps the latent points of a run in a random embedding to
+	// parameters, see OptimizeEmbedded. Nil for other runs.
+	Embedding *Embedding
+
+	// DebugTrace holds a snapshot of the model per candidate selection, if
+	// OptimizationConfig.DebugCapture is set without a Path.
+	DebugTrace []DebugSnapshot
+
+	// CPUProfile and HeapProfile are the paths of the profiles of the best
+	// parameters, empty unless written, see OptimizationConfig.ProfileBest.
+	CPUProfile, HeapProfile string
+
+	// hypers holds the parameter ranges, see PredictGrid.
+	hypers []ParameterRange[T]
+
+	// structType is the struct type of an OptimizeStruct run, see GoCode.
+	structType reflect.Type
+
+	// model is a snapshot of the model at the end of the run, see
+	// PredictGrid.
+	model SurrogateModel
+}
+
+// Recommendation is the outcome of a validation, see ValidateAgainst.
+type Recommendation string
+
+const (
+	// RecommendCandidate means the candidate parameters are significantly
+	// faster than the baseline ones.
+	RecommendCandidate Recommendation = "UseCandidate"
+
+	// RecommendBaseline means the candidate parameters are significantly
+	// slower than the baseline ones.
+	RecommendBaseline Recommendation = "KeepBaseline"
+
+	// RecommendInconclusive means no significant difference was detected.
+	// More runs may be needed, or the difference is just noise.
+	RecommendInconclusive Recommendation = "Inconclusive"
+)
+
+// Verdict is the result of benchmarking candidate parameters against baseline
+// ones, see ValidateAgainst. Times are in nanoseconds.
+type Verdict struct {
+	// CandidateMean is the mean execution time of the candidate parameters.
+	CandidateMean float64
+
+	// BaselineMean is the mean execution time of the baseline parameters.
+	BaselineMean float64
+
+	// CandidateCI is the 95% confidence interval of CandidateMean.
+	CandidateCI [2]float64
+
+	// BaselineCI is the 95% confidence interval of BaselineMean.
+	BaselineCI [2]float64
+
+	// Improvement is the estimated relative improvement of the candidate over
+	// the baseline, e.g. 0.2 means the candidate is 20% faster. Negative
+	// values mean the candidate is slower.
+	Improvement float64
+
+	// PValue is the two-sided p-value of Welch's t-test.
+	PValue float64
+
+	// MannWhitneyPValue is the two-sided p-value of the Mann-Whitney U test.
+	// Unlike PValue, it doesn't assume normality, so it's robust to outliers.
+	MannWhitneyPValue float64
+
+	// Recommendation summarizes the verdict, at the 5% significance level.
+	Recommendation Recommendation
 }
@@ -1,8 +1,14 @@
 package ho
 
 import (
+	"context"
+	"fmt"
 	"math"
+	"math/rand"
+	"reflect"
 	"time"
+
+	"golang.org/x/exp/constraints"
 )
 
 //////
@@ -27,6 +33,213 @@ func normalPDF(x float64) float64 {
 	return math.Exp(-x*x/2.0) / math.Sqrt(2.0*math.Pi)
 }
 
+// Helper function used by priors to compute the quantile function (inverse
+// CDF) of the standard normal distribution.
+//
+// Returns:
+// - Value x such that a standard normal random variable is less than x with
+// probability p.
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// roundInts converts params to ints, rounded to the nearest one, e.g. for
+// ProgressUpdate.CurrentParams.
+func roundInts[T constraints.Integer | constraints.Float](params []T) []int {
+	ints := make([]int, len(params))
+
+	for i, v := range params {
+		ints[i] = int(math.Round(float64(v)))
+	}
+
+	return ints
+}
+
+// autoCandidates returns the number of candidates for a search space of the
+// given dimensions, if NumCandidates is unset: 100 per dimension, at most 2000.
+func autoCandidates(dimensions int) int {
+	return min(candidatesPerDimension*max(dimensions, 1), maxAutoCandidates)
+}
+
+// clamp restricts v to [min, max].
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
+
+// snapToStep snaps v to the nearest Min plus a multiple of the range Step,
+// within the range. It's a no-op if Step isn't positive.
+func snapToStep[T constraints.Integer | constraints.Float](hyper ParameterRange[T], v T) T {
+	if hyper.Step <= 0 {
+		return v
+	}
+
+	// Integer ranges snap in integer arithmetic, so values stay exact
+	// multiples of Step whatever the width of the range, see offsetOf.
+	if half := 0.5; T(half) == 0 {
+		step := uint64(hyper.Step)
+
+		span := uint64(hyper.Max) - uint64(hyper.Min)
+
+		var delta uint64
+
+		if v > hyper.Min {
+			delta = min(uint64(v)-uint64(hyper.Min), span)
+		}
+
+		// Round half up, unless it's beyond the range.
+		multiple, remainder := delta/step, delta%step
+
+		if remainder >= step-remainder && step <= span && multiple*step <= span-step {
+			multiple++
+		}
+
+		return T(uint64(hyper.Min) + multiple*step)
+	}
+
+	min := float64(hyper.Min)
+
+	step := float64(hyper.Step)
+
+	snapped := min + math.Round((float64(v)-min)/step)*step
+
+	if snapped > float64(hyper.Max) {
+		snapped -= step
+	}
+
+	return fromFloat64[T](math.Max(snapped, min))
+}
+
+// offsetOf returns the distance from the range Min to v. For integer ranges,
+// it's computed in integer arithmetic: it's exact below 2^53 wherever the
+// range lies, e.g. near math.MaxUint64, where float64(v) isn't.
+func offsetOf[T constraints.Integer | constraints.Float](hyper ParameterRange[T], v T) float64 {
+	if half := 0.5; T(half) != 0 {
+		return float64(v) - float64(hyper.Min)
+	}
+
+	// Differences of integers of up to 64 bits, signed or not, are exact
+	// modulo 2^64.
+	if v < hyper.Min {
+		return -float64(uint64(hyper.Min) - uint64(v))
+	}
+
+	return float64(uint64(v) - uint64(hyper.Min))
+}
+
+// atOffset returns the value of the range at offset from its Min, clamped to
+// the range. For integer ranges, the offset is rounded, halfway cases up, and
+// added to Min in integer arithmetic, see offsetOf.
+func atOffset[T constraints.Integer | constraints.Float](hyper ParameterRange[T], offset float64) T {
+	if half := 0.5; T(half) != 0 {
+		return T(clamp(float64(hyper.Min)+offset, float64(hyper.Min), float64(hyper.Max)))
+	}
+
+	span := uint64(hyper.Max) - uint64(hyper.Min)
+
+	offset = math.Round(offset)
+
+	var delta uint64
+
+	switch {
+	case !(offset > 0):
+		// Also catches NaN.
+		delta = 0
+	case offset >= float64(span):
+		delta = span
+	default:
+		delta = uint64(offset)
+	}
+
+	return T(uint64(hyper.Min) + delta)
+}
+
+// validateBounds checks the bounds and the step of a range.
+//
+// Returns:
+// - error: Describing the first issue if Min is greater than Max, a bound
+// or the span isn't finite, or the step is negative or not finite, nil
+// otherwise.
+func validateBounds[T constraints.Integer | constraints.Float](hyper ParameterRange[T]) error {
+	minimum, maximum, step := float64(hyper.Min), float64(hyper.Max), float64(hyper.Step)
+
+	switch {
+	case math.IsNaN(minimum) || math.IsInf(minimum, 0) || math.IsNaN(maximum) || math.IsInf(maximum, 0):
+		return fmt.Errorf("bounds [%v, %v] aren't finite", hyper.Min, hyper.Max)
+	case hyper.Min > hyper.Max:
+		return fmt.Errorf("min %v is greater than max %v", hyper.Min, hyper.Max)
+	case math.IsInf(maximum-minimum, 0):
+		// Offsets from Min, as the models see them, would overflow.
+		return fmt.Errorf("span of [%v, %v] isn't finite", hyper.Min, hyper.Max)
+	case !(step >= 0) || math.IsInf(step, 0):
+		return fmt.Errorf("step %v must be nonnegative and finite", hyper.Step)
+	}
+
+	return nil
+}
+
+// rangeValue converts a continuous value, e.g. a sample or a perturbation, to
+// a value of the range. Every such conversion goes through it, or through
+// atOffset and snapToStep for offsets, so values are never truncated.
+//
+// Parameters:
+// - hyper: The range
+// - v: The value
+//
+// Returns:
+// - T: v rounded to the nearest integer for integer ranges, halfway cases
+// up, clamped to [Min, Max] and snapped to the Step lattice.
+func rangeValue[T constraints.Integer | constraints.Float](hyper ParameterRange[T], v float64) T {
+	if half := 0.5; T(half) != 0 {
+		return snapToStep(hyper, T(clamp(v, float64(hyper.Min), float64(hyper.Max))))
+	}
+
+	return snapToStep(hyper, atOffset(hyper, v-float64(hyper.Min)))
+}
+
+// randomInteger draws an integer uniformly in [min, max], for integer types T
+// of up to 64 bits, whatever the width of the range, e.g. the full uint64
+// domain.
+func randomInteger[T constraints.Integer | constraints.Float](rng *rand.Rand, min, max T) T {
+	// Exact modulo 2^64, see offsetOf.
+	span := uint64(max) - uint64(min)
+
+	var delta uint64
+
+	switch {
+	case span < math.MaxInt64:
+		delta = uint64(rng.Int63n(int64(span) + 1))
+	case span == math.MaxUint64:
+		delta = rng.Uint64()
+	default:
+		// Rejection sampling, at least half of the draws are within the span.
+		for delta = rng.Uint64(); delta > span; delta = rng.Uint64() {
+		}
+	}
+
+	return T(uint64(min) + delta)
+}
+
+// drawValue draws a value of the range: from its Prior if set, uniformly
+// otherwise, rounded and snapped as rangeValue does.
+func drawValue[T constraints.Integer | constraints.Float](rng *rand.Rand, hyper ParameterRange[T]) T {
+	if hyper.Prior != nil {
+		return sampleFromPrior(rng, hyper)
+	}
+
+	if half := 0.5; T(half) == 0 {
+		// For integer types, generate random integer in range, whatever its
+		// width, e.g. the full uint64 domain
+		return snapToStep(hyper, randomInteger(rng, hyper.Min, hyper.Max))
+	}
+
+	// For float types, generate random float in range
+	min := float64(hyper.Min)
+
+	max := float64(hyper.Max)
+
+	return rangeValue(hyper, min+rng.Float64()*(max-min))
+}
+
 // measureExecutionTime runs a benchmark function with the given parameters and
 // measures its execution time in nanoseconds.
 //
@@ -116,3 +329,174 @@ func intsToFloats(ints []int) []float64 {
 
 	return floats
 }
+
+// paramsToFloat64s converts parameters to float64, e.g. for exclusion zones
+// and constraints. Surrogate models see points, see modelPoint.
+//
+// Parameters:
+// - params: Slice of parameters to convert
+//
+// Returns:
+// - []float64: New slice containing float64 versions of input values.
+func paramsToFloat64s[T constraints.Integer | constraints.Float](params []T) []float64 {
+	floats := make([]float64, len(params))
+
+	for i, v := range params {
+		floats[i] = float64(v)
+	}
+
+	return floats
+}
+
+// modelPoint converts parameters to the point the surrogate models see: the
+// offset of each value from the Min of its range, see offsetOf. Offsets are
+// a translation of the values, which the models' distances don't change,
+// but offsets of integer ranges are exact up to 2^53 wherever the range
+// lies: the values of [math.MaxUint64-1000, math.MaxUint64] are distinct
+// points, while as float64 they're all the same.
+//
+// Parameters:
+// - hypers: The ranges
+// - params: Parameters, in range order
+//
+// Returns:
+// - []float64: The point. Values beyond the ranges are copied as they are,
+// for the model to reject.
+func modelPoint[T constraints.Integer | constraints.Float](hypers []ParameterRange[T], params []T) []float64 {
+	point := make([]float64, len(params))
+
+	for i, v := range params {
+		if i < len(hypers) {
+			point[i] = offsetOf(hypers[i], v)
+
+			continue
+		}
+
+		point[i] = float64(v)
+	}
+
+	return point
+}
+
+// modelCoordinates converts values of the ranges given as float64, e.g. warm
+// start observations or grid values, to the point the surrogate models see,
+// see modelPoint. Values beyond the ranges are copied as they are.
+func modelCoordinates[T constraints.Integer | constraints.Float](hypers []ParameterRange[T], values []float64) []float64 {
+	point := make([]float64, len(values))
+
+	for i, v := range values {
+		if i < len(hypers) {
+			v -= float64(hypers[i].Min)
+		}
+
+		point[i] = v
+	}
+
+	return point
+}
+
+// modelValue converts a coordinate of a point the surrogate models see back
+// to a value of the range, see modelPoint: rounded, clamped and snapped as
+// rangeValue does.
+func modelValue[T constraints.Integer | constraints.Float](hyper ParameterRange[T], coordinate float64) T {
+	return snapToStep(hyper, atOffset(hyper, coordinate))
+}
+
+// fromBenchmarkFunc adapts a BenchmarkFunc to the internal trial signature.
+func fromBenchmarkFunc[T constraints.Integer | constraints.Float](f BenchmarkFunc[T]) trialFunc[T] {
+	return func(_ context.Context, _ TrialInfo, params ...T) error {
+		return f(params...)
+	}
+}
+
+// fromBenchmarkFuncWithInfo adapts a BenchmarkFuncWithInfo to the internal
+// trial signature.
+func fromBenchmarkFuncWithInfo[T constraints.Integer | constraints.Float](f BenchmarkFuncWithInfo[T]) trialFunc[T] {
+	return func(_ context.Context, info TrialInfo, params ...T) error {
+		return f(info, params...)
+	}
+}
+
+// fromBenchmarkFuncCtx adapts a BenchmarkFuncCtx to the internal trial
+// signature.
+func fromBenchmarkFuncCtx[T constraints.Integer | constraints.Float](f BenchmarkFuncCtx[T]) trialFunc[T] {
+	return func(ctx context.Context, _ TrialInfo, params ...T) error {
+		return f(ctx, params...)
+	}
+}
+
+// fromFloat64 converts a float64 to T, rounding to the nearest integer for
+// integer types. Values beyond the bounds of integer types saturate, as
+// converting them is implementation-specific.
+func fromFloat64[T constraints.Integer | constraints.Float](v float64) T {
+	var zero T
+
+	switch any(zero).(type) {
+	case float32, float64:
+		return T(v)
+	}
+
+	v = math.Round(v)
+
+	// Bounds of integer types are powers of two, exact in float64.
+	kind := reflect.TypeOf(zero).Kind()
+
+	bits := reflect.TypeOf(zero).Bits()
+
+	if kind >= reflect.Int && kind <= reflect.Int64 {
+		limit := math.Ldexp(1, bits-1)
+		max := T(uint64(1)<<(bits-1) - 1)
+
+		switch {
+		case v >= limit:
+			return max
+		case v < -limit:
+			return -max - 1
+		}
+
+		return T(v)
+	}
+
+	switch {
+	case v >= math.Ldexp(1, bits):
+		return T(uint64(math.MaxUint64) >> (64 - bits))
+	case v < 0:
+		return 0
+	}
+
+	return T(v)
+}
+
+// handleNonFinite handles a completed trial whose value isn't finite,
+// according to the policy, see OptimizationConfig.NonFiniteValues. The
+// original value is kept in RawValue, and replaced with the failure penalty
+// so it never reaches the model, the best result or the storage.
+//
+// Parameters:
+// - trial: The trial, with the value in ExecutionTime
+// - policy: How to handle the value
+//
+// Returns:
+// - bool: True if the value wasn't finite, and the trial was updated.
+func handleNonFinite[T constraints.Integer | constraints.Float](trial *Trial[T], policy NonFinitePolicy) bool {
+	value := trial.ExecutionTime
+
+	if !math.IsNaN(value) && !math.IsInf(value, 0) {
+		return false
+	}
+
+	trial.RawValue = value
+	trial.ExecutionTime = math.MaxFloat64 / 2
+
+	if policy == NonFiniteSkip {
+		trial.Status = TrialSkipped
+		trial.Err = fmt.Errorf("%w %v: %w", ErrNonFiniteValue, value, ErrSkipTrial)
+
+		return true
+	}
+
+	trial.Status = TrialFailed
+	trial.Err = fmt.Errorf("%w %v", ErrNonFiniteValue, value)
+
+	return true
+}
@@ -0,0 +1,227 @@
+package ho
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomInteger(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	var high int
+
+	for i := 0; i < 1000; i++ {
+		v := randomInteger[uint64](rng, 0, math.MaxUint64)
+
+		if v > math.MaxInt64 {
+			high++
+		}
+
+		top := randomInteger[uint64](rng, math.MaxInt64+1, math.MaxUint64)
+
+		assert.GreaterOrEqual(t, top, uint64(math.MaxInt64+1))
+
+		signed := randomInteger[int64](rng, math.MinInt64, math.MaxInt64)
+
+		assert.GreaterOrEqual(t, signed, int64(math.MinInt64))
+
+		small := randomInteger[int8](rng, -128, 127)
+
+		assert.GreaterOrEqual(t, small, int8(-128))
+
+		single := randomInteger[uint64](rng, math.MaxUint64, math.MaxUint64)
+
+		assert.Equal(t, uint64(math.MaxUint64), single)
+	}
+
+	// Both halves of the domain are drawn.
+	assert.InDelta(t, 500, high, 100)
+}
+
+func TestIntegerOffsets(t *testing.T) {
+	// float64 can't tell these values apart, offsets can.
+	hyper := ParameterRange[uint64]{Min: math.MaxUint64 - 100, Max: math.MaxUint64, Step: 10}
+
+	assert.Equal(t, float64(100), offsetOf(hyper, math.MaxUint64))
+	assert.Equal(t, float64(-5), offsetOf(hyper, math.MaxUint64-105))
+	assert.Equal(t, uint64(math.MaxUint64-93), atOffset(hyper, 7.2))
+	assert.Equal(t, uint64(math.MaxUint64), atOffset(hyper, 1e30))
+	assert.Equal(t, uint64(math.MaxUint64-100), atOffset(hyper, -3))
+	assert.Equal(t, uint64(math.MaxUint64-60), snapToStep(hyper, math.MaxUint64-57))
+	assert.Equal(t, uint64(math.MaxUint64), snapToStep(hyper, math.MaxUint64-4))
+	assert.Equal(t, uint64(math.MaxUint64-1), snapToStep(ParameterRange[uint64]{Min: math.MaxUint64 - 7, Max: math.MaxUint64, Step: 3}, math.MaxUint64))
+
+	// Out of range conversions saturate.
+	assert.Equal(t, uint64(math.MaxUint64), fromFloat64[uint64](math.Ldexp(1, 64)))
+	assert.Equal(t, uint64(0), fromFloat64[uint64](-1))
+	assert.Equal(t, int8(127), fromFloat64[int8](300))
+	assert.Equal(t, int64(math.MinInt64), fromFloat64[int64](-1e30))
+	assert.Equal(t, uint32(math.MaxUint32), fromFloat64[uint32](1e10))
+	assert.Equal(t, int32(-7), fromFloat64[int32](-7.2))
+}
+
+func TestRangeValue(t *testing.T) {
+	signed := ParameterRange[int]{Min: -10, Max: 10}
+	stepped := ParameterRange[int]{Min: -10, Max: 10, Step: 4}
+	unsigned := ParameterRange[uint8]{Min: 0, Max: 255}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "rounds down", got: rangeValue(signed, 3.4), want: 3},
+		{name: "rounds up", got: rangeValue(signed, 3.6), want: 4},
+		{name: "negative rounds down", got: rangeValue(signed, -3.6), want: -4},
+		{name: "negative rounds up", got: rangeValue(signed, -3.4), want: -3},
+		{name: "negative isn't truncated", got: rangeValue(signed, -0.9), want: -1},
+		{name: "halfway up", got: rangeValue(signed, 2.5), want: 3},
+		{name: "negative halfway up", got: rangeValue(signed, -2.5), want: -2},
+		{name: "clamped below", got: rangeValue(signed, -10.4), want: -10},
+		{name: "clamped above", got: rangeValue(signed, 1e9), want: 10},
+		{name: "step lattice", got: rangeValue(stepped, -4.9), want: -6},
+		{name: "step halfway up", got: rangeValue(stepped, -8), want: -6},
+		{name: "step clamped above", got: rangeValue(stepped, 10), want: 10},
+		{name: "unsigned clamped below", got: rangeValue(unsigned, -0.7), want: uint8(0)},
+		{name: "unsigned halfway up", got: rangeValue(unsigned, 254.5), want: uint8(255)},
+		{name: "unsigned clamped above", got: rangeValue(unsigned, 255.6), want: uint8(255)},
+		{name: "floats aren't rounded", got: rangeValue(ParameterRange[float64]{Min: -1, Max: 1}, -0.25), want: -0.25},
+		{name: "floats clamped", got: rangeValue(ParameterRange[float64]{Min: -1, Max: 1}, 3), want: 1.0},
+		{name: "float step lattice", got: rangeValue(ParameterRange[float64]{Min: 0, Max: 1, Step: 0.25}, 0.3), want: 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+
+	assert.Equal(t, []int{-3, 3, 0}, roundInts([]float64{-2.6, 2.5, 0.4}))
+}
+
+func TestOptimizeUint64(t *testing.T) {
+	tests := []struct {
+		name  string
+		hyper ParameterRange[uint64]
+	}{
+		{name: "full width", hyper: ParameterRange[uint64]{Min: 0, Max: math.MaxUint64}},
+		{name: "above MaxInt64", hyper: ParameterRange[uint64]{Min: math.MaxInt64 + 1, Max: math.MaxUint64}},
+		{name: "top of the domain", hyper: ParameterRange[uint64]{Min: math.MaxUint64 - 1000, Max: math.MaxUint64}},
+		{name: "span beyond 2^53", hyper: ParameterRange[uint64]{Min: 1 << 60, Max: 1<<60 + 1<<55, Step: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := map[uint64]bool{}
+
+			result := Optimize(fastConfig(), func(params ...uint64) error {
+				assert.GreaterOrEqual(t, params[0], tt.hyper.Min)
+				assert.LessOrEqual(t, params[0], tt.hyper.Max)
+
+				if tt.hyper.Step > 0 {
+					assert.Zero(t, (params[0]-tt.hyper.Min)%tt.hyper.Step)
+				}
+
+				seen[params[0]] = true
+
+				return nil
+			}, tt.hyper)
+
+			assert.NoError(t, result.Err)
+			assert.Greater(t, len(seen), 1)
+
+			// The best is exactly one of the evaluated values.
+			assert.True(t, seen[result.BestParams[0]], "%d wasn't evaluated", result.BestParams[0])
+		})
+	}
+}
+
+func TestInvalidRanges(t *testing.T) {
+	benchmark := func(params ...float64) error {
+		t.Errorf("benchmark called with %v", params)
+
+		return nil
+	}
+
+	tests := []struct {
+		name  string
+		hyper ParameterRange[float64]
+		want  string
+	}{
+		{name: "min above max", hyper: ParameterRange[float64]{Min: 2.5, Max: 1}, want: "min 2.5 is greater than max 1"},
+		{name: "NaN min", hyper: ParameterRange[float64]{Min: math.NaN(), Max: 1}, want: "aren't finite"},
+		{name: "infinite max", hyper: ParameterRange[float64]{Min: 0, Max: math.Inf(1)}, want: "aren't finite"},
+		{name: "infinite span", hyper: ParameterRange[float64]{Min: -math.MaxFloat64, Max: math.MaxFloat64}, want: "span"},
+		{name: "negative step", hyper: ParameterRange[float64]{Min: 0, Max: 1, Step: -0.1}, want: "step -0.1"},
+		{name: "NaN step", hyper: ParameterRange[float64]{Min: 0, Max: 1, Step: math.NaN()}, want: "step NaN"},
+		{name: "infinite step", hyper: ParameterRange[float64]{Min: 0, Max: 1, Step: math.Inf(1)}, want: "step +Inf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Optimize(fastConfig(), benchmark, ParameterRange[float64]{Min: 0, Max: 1}, tt.hyper)
+
+			assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+			assert.ErrorContains(t, result.Err, "range 1: ")
+			assert.ErrorContains(t, result.Err, tt.want)
+			assert.Empty(t, result.Trials)
+		})
+	}
+
+	// Integer ranges are checked too, ask/tell handles included.
+	result := Optimize(fastConfig(), func(...int) error { return nil }, ParameterRange[int]{Min: 5, Max: 3})
+
+	assert.ErrorIs(t, result.Err, ErrInvalidConfig)
+	assert.ErrorContains(t, result.Err, "min 5 is greater than max 3")
+
+	_, err := NewOptimizer(fastConfig(), ParameterRange[int]{Min: 0, Max: 10, Step: -2})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestModelPointUint64(t *testing.T) {
+	hypers := []ParameterRange[uint64]{{Min: math.MaxUint64 - 1000, Max: math.MaxUint64}}
+
+	// As float64, the values are the same; as offsets, they're distinct.
+	assert.Equal(t, float64(math.MaxUint64-1), float64(uint64(math.MaxUint64)))
+	assert.Equal(t, []float64{999}, modelPoint(hypers, []uint64{math.MaxUint64 - 1}))
+	assert.Equal(t, []float64{1000}, modelPoint(hypers, []uint64{math.MaxUint64}))
+	assert.Equal(t, uint64(math.MaxUint64-1), modelValue(hypers[0], 999))
+
+	// The model sees every evaluated value as a point of its own.
+	config := fastConfig()
+	config.Iterations = 10
+
+	evaluated := map[uint64]bool{}
+
+	result := OptimizeObjective(config, func(params ...uint64) (float64, error) {
+		evaluated[params[0]] = true
+
+		return math.Abs(float64(params[0]-(math.MaxUint64-1000)) - 300), nil
+	}, hypers...)
+
+	assert.NoError(t, result.Err)
+
+	points := map[string]bool{}
+
+	for _, point := range result.model.Points() {
+		points[pointKey(point)] = true
+	}
+
+	assert.Len(t, points, len(evaluated))
+
+	// Checkpoints restore the values exactly.
+	data, err := json.Marshal(newCheckpointTrial(Trial[uint64]{Params: []uint64{math.MaxUint64 - 1}}))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var record checkpointTrial
+
+	if assert.NoError(t, json.Unmarshal(data, &record)) {
+		assert.Equal(t, []uint64{math.MaxUint64 - 1}, restoreTrial(hypers, record).Params)
+	}
+}
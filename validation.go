@@ -0,0 +1,181 @@
+package ho
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, types.
+//////
+
+// significanceLevel is the significance level of the validation tests, and
+// the complement of the confidence intervals level.
+const significanceLevel = 0.05
+
+//////
+// Exported functionalities.
+//////
+
+// ValidateAgainst benchmarks candidate parameters (typically the best found by
+// an optimization run) against baseline ones (typically the production
+// configuration), and tells whether the candidate is really better or the
+// optimization just got lucky on noisy measurements.
+//
+// Parameters:
+// - benchmarkFunc: The function to benchmark
+// - candidateParams: The candidate parameters
+// - baselineParams: The baseline parameters
+// - runs: Number of times each configuration is benchmarked, at least 2
+//
+// Returns:
+// - *Verdict: Means, confidence intervals, tests and recommendation
+// - error: Wrapping ErrInvalidConfig if runs < 2, or the benchmark error.
+//
+// Usage example:
+//
+//	result := Optimize(config, benchmark, ranges...)
+//
+//	verdict, err := ValidateAgainst(benchmark, result.BestParams, current, 30)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	if verdict.Recommendation == RecommendCandidate {
+//	    deploy(result.BestParams)
+//	}
+//
+// Important notes:
+// - Runs are interleaved (ABBA order), so slow drifts of the system under test
+// (thermal throttling, cache warm-up, noisy neighbors) affect both equally
+// - Significance is assessed with Welch's t-test, Mann-Whitney's U test is
+// reported as a robustness check.
+func ValidateAgainst[T constraints.Integer | constraints.Float](
+	benchmarkFunc BenchmarkFunc[T],
+	candidateParams, baselineParams []T,
+	runs int,
+) (*Verdict, error) {
+	return validateAgainst(func(params []T) (float64, error) {
+		start := time.Now()
+
+		if err := benchmarkFunc(params...); err != nil {
+			return 0, err
+		}
+
+		return float64(time.Since(start).Nanoseconds()), nil
+	}, candidateParams, baselineParams, runs)
+}
+
+//////
+// Helpers.
+//////
+
+// validateAgainst runs the interleaved measurements, see ValidateAgainst.
+func validateAgainst[T constraints.Integer | constraints.Float](
+	measure func(params []T) (float64, error),
+	candidateParams, baselineParams []T,
+	runs int,
+) (*Verdict, error) {
+	if runs < 2 {
+		return nil, fmt.Errorf("%w: runs must be at least 2, got %d", ErrInvalidConfig, runs)
+	}
+
+	candidate, baseline, err := interleave(measure, candidateParams, baselineParams, runs)
+	if err != nil {
+		return nil, fmt.Errorf("validation %w", err)
+	}
+
+	return compareSamples(candidate, baseline), nil
+}
+
+// interleave measures two configurations runs times each, in ABBA order, so
+// slow drifts of the system under test affect both equally.
+//
+// Returns:
+// - a, b: The measurements of each configuration, in order
+// - error: The first measurement error, nil otherwise.
+func interleave[T constraints.Integer | constraints.Float](
+	measure func(params []T) (float64, error),
+	paramsA, paramsB []T,
+	runs int,
+) (a, b []float64, err error) {
+	a = make([]float64, 0, runs)
+	b = make([]float64, 0, runs)
+
+	for i := 0; i < runs; i++ {
+		// ABBA order: A goes first on even runs, second on odd ones.
+		order := []bool{true, false}
+		if i%2 == 1 {
+			order = []bool{false, true}
+		}
+
+		for _, isA := range order {
+			params := paramsB
+			if isA {
+				params = paramsA
+			}
+
+			value, err := measure(params)
+			if err != nil {
+				return nil, nil, fmt.Errorf("run %d: %w", i, err)
+			}
+
+			if isA {
+				a = append(a, value)
+			} else {
+				b = append(b, value)
+			}
+		}
+	}
+
+	return a, b, nil
+}
+
+// compareSamples compares candidate and baseline measurements, lower is
+// better.
+func compareSamples(candidate, baseline []float64) *Verdict {
+	verdict := &Verdict{
+		CandidateMean:  mean(candidate),
+		BaselineMean:   mean(baseline),
+		CandidateCI:    confidenceInterval(candidate),
+		BaselineCI:     confidenceInterval(baseline),
+		Recommendation: RecommendInconclusive,
+	}
+
+	if verdict.BaselineMean != 0 {
+		verdict.Improvement = (verdict.BaselineMean - verdict.CandidateMean) / verdict.BaselineMean
+	}
+
+	_, _, verdict.PValue = welchTTest(candidate, baseline)
+
+	_, verdict.MannWhitneyPValue = mannWhitneyU(candidate, baseline)
+
+	if verdict.PValue < significanceLevel {
+		if verdict.CandidateMean < verdict.BaselineMean {
+			verdict.Recommendation = RecommendCandidate
+		} else {
+			verdict.Recommendation = RecommendBaseline
+		}
+	}
+
+	return verdict
+}
+
+// confidenceInterval returns the confidence interval of the mean of the
+// values, based on the Student's t distribution.
+func confidenceInterval(values []float64) [2]float64 {
+	m := mean(values)
+
+	if len(values) < 2 {
+		return [2]float64{m, m}
+	}
+
+	n := float64(len(values))
+
+	margin := studentTQuantile(significanceLevel, n-1) * math.Sqrt(sampleVariance(values)/n)
+
+	return [2]float64{m - margin, m + margin}
+}
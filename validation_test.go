@@ -0,0 +1,103 @@
+package ho
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStudentT(t *testing.T) {
+	// Reference values from t tables.
+	assert.InDelta(t, 0.05, studentTTwoSided(2.228, 10), 1e-3)
+	assert.InDelta(t, 0.01, studentTTwoSided(2.861, 19), 1e-3)
+	assert.InDelta(t, 2.228, studentTQuantile(0.05, 10), 1e-3)
+	assert.InDelta(t, 1.960, studentTQuantile(0.05, 1e6), 1e-3)
+}
+
+func TestCompareSamples(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	normal := func(n int, mean, stddev float64) []float64 {
+		values := make([]float64, n)
+
+		for i := range values {
+			values[i] = mean + stddev*rng.NormFloat64()
+		}
+
+		return values
+	}
+
+	tests := []struct {
+		name          string
+		candidateMean float64
+		want          Recommendation
+	}{
+		{name: "candidate faster", candidateMean: 80, want: RecommendCandidate},
+		{name: "candidate slower", candidateMean: 120, want: RecommendBaseline},
+		{name: "no difference", candidateMean: 100, want: RecommendInconclusive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := compareSamples(normal(30, tt.candidateMean, 5), normal(30, 100, 5))
+
+			assert.Equal(t, tt.want, verdict.Recommendation)
+			assert.InDelta(t, (100-tt.candidateMean)/100, verdict.Improvement, 0.03)
+
+			assert.Less(t, verdict.CandidateCI[0], verdict.CandidateMean)
+			assert.Greater(t, verdict.CandidateCI[1], verdict.CandidateMean)
+
+			if tt.want == RecommendInconclusive {
+				assert.Greater(t, verdict.PValue, 0.05)
+			} else {
+				assert.Less(t, verdict.PValue, 1e-6)
+				assert.Less(t, verdict.MannWhitneyPValue, 1e-6)
+			}
+		})
+	}
+}
+
+func TestValidateAgainst(t *testing.T) {
+	t.Run("interleaved", func(t *testing.T) {
+		var order []int
+
+		verdict, err := validateAgainst(func(params []int) (float64, error) {
+			order = append(order, params[0])
+
+			return float64(params[0]) + float64(len(order)%3), nil
+		}, []int{1}, []int{2}, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 2, 1, 1, 2, 2, 1}, order)
+		assert.Less(t, verdict.CandidateMean, verdict.BaselineMean)
+	})
+
+	t.Run("invalid runs", func(t *testing.T) {
+		_, err := ValidateAgainst(func(params ...int) error { return nil }, []int{1}, []int{2}, 1)
+
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+
+	t.Run("benchmark error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+
+		_, err := ValidateAgainst(func(params ...int) error { return errBoom }, []int{1}, []int{2}, 3)
+
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("measures execution time", func(t *testing.T) {
+		verdict, err := ValidateAgainst(func(params ...int) error {
+			time.Sleep(time.Duration(params[0]) * time.Millisecond)
+
+			return nil
+		}, []int{1}, []int{10}, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, RecommendCandidate, verdict.Recommendation)
+		assert.Greater(t, verdict.Improvement, 0.5)
+	})
+}
@@ -0,0 +1,44 @@
+package ho
+
+//////
+// Const, vars, types.
+//////
+
+const (
+	// surpriseWeight scales the weight of a surprising trial, as the median
+	// of its measurements is still less trusted than as many unsurprising
+	// ones, see SurpriseRemeasure.
+	surpriseWeight = 0.5
+
+	// underLoadWeight scales the weight of a trial measured while the system
+	// wasn't ready, see LoadGate.
+	underLoadWeight = 0.25
+)
+
+//////
+// Helpers.
+//////
+
+// measurementWeight returns the weight of a completed trial, see
+// Trial.Weight.
+//
+// Parameters:
+// - measurements: Number of measurements the value summarizes, at least 1
+// - surprising: Whether the trial was surprising
+// - underLoad: Whether the trial ran under load
+//
+// Returns:
+// - float64: The weight, positive.
+func measurementWeight(measurements int, surprising, underLoad bool) float64 {
+	weight := float64(max(measurements, 1))
+
+	if surprising {
+		weight *= surpriseWeight
+	}
+
+	if underLoad {
+		weight *= underLoadWeight
+	}
+
+	return weight
+}
@@ -0,0 +1,92 @@
+package ho
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasurementWeight(t *testing.T) {
+	for _, tc := range []struct {
+		measurements          int
+		surprising, underLoad bool
+		want                  float64
+	}{
+		{0, false, false, 1},
+		{1, false, false, 1},
+		{10, false, false, 10},
+		{3, true, false, 1.5},
+		{1, false, true, 0.25},
+		{3, true, true, 0.375},
+	} {
+		assert.Equal(t, tc.want, measurementWeight(tc.measurements, tc.surprising, tc.underLoad), tc)
+	}
+}
+
+func TestTrialWeights(t *testing.T) {
+	ranges := []ParameterRange[float64]{{Min: 0, Max: 10}}
+
+	objective := func(params ...float64) (float64, error) {
+		return (params[0] - 3) * (params[0] - 3), nil
+	}
+
+	path := filepath.Join(t.TempDir(), "run.json")
+
+	config := fastConfig()
+	config.Seed = 1
+	config.Checkpoint = &Checkpoint{Path: path}
+	config.LoadGate = &LoadGate{
+		Ready:   func(context.Context) error { return errors.New("busy") },
+		MaxWait: time.Millisecond,
+		Backoff: time.Millisecond,
+	}
+
+	result := OptimizeObjective(config, objective, ranges...)
+
+	if !assert.NoError(t, result.Err) {
+		return
+	}
+
+	// Trials measured under load are trusted less, in reports too.
+	for _, trial := range result.Trials {
+		assert.Equal(t, underLoadWeight, trial.Weight)
+		assert.Equal(t, underLoadWeight, NewTrialRecord(trial, nil).Weight)
+	}
+
+	// Weights persist through checkpoints, observations included.
+	state := readCheckpointFile(t, path)
+
+	if !assert.NotNil(t, state) || !assert.Len(t, state.Trials, len(result.Trials)) {
+		return
+	}
+
+	for i, record := range state.Trials {
+		assert.Equal(t, underLoadWeight, record.Weight)
+		assert.Equal(t, underLoadWeight, restoreTrial[float64](nil, record).Weight)
+		assert.Equal(t, underLoadWeight, state.Observations[i].Weight)
+	}
+
+	// Resuming feeds the model the same weighted observations.
+	config.LoadGate = nil
+	config.Iterations = 0
+
+	resumed := ResumeObjectiveFromCheckpoint(path, config, objective, ranges...)
+
+	if !assert.NoError(t, resumed.Err) {
+		return
+	}
+
+	for _, x := range []float64{1, 3, 7} {
+		want, _, err := result.Predict([]float64{x})
+		assert.NoError(t, err)
+
+		got, _, err := resumed.Predict([]float64{x})
+		assert.NoError(t, err)
+
+		assert.InDelta(t, want, got, 1e-9)
+	}
+}